@@ -0,0 +1,100 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package appinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+)
+
+// Backfill fills in the app slug and HTML URL when the store holds
+// registered credentials without them, which is common for credentials
+// provisioned by hand rather than through the installer. The values are
+// fetched from GET /app and persisted if the store implements
+// configstore.MetadataStore. The client must authenticate as the app. It
+// returns the store's status with the backfilled values applied, and makes
+// no API call when nothing is missing.
+func Backfill(ctx context.Context, store configstore.Store, client *github.Client) (*configstore.InstallerStatus, error) {
+	status, err := store.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("appinfo: failed to read installer status: %w", err)
+	}
+	if !status.Registered || (status.AppSlug != "" && status.HTMLURL != "") {
+		return status, nil
+	}
+	if client == nil {
+		return status, errors.New("appinfo: backfill has no GitHub client")
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return status, fmt.Errorf("appinfo: failed to fetch app: %w", err)
+	}
+
+	// Only fill the missing values so hand-set ones are never overwritten.
+	var slug, htmlURL string
+	if status.AppSlug == "" {
+		slug = app.GetSlug()
+	}
+	if status.HTMLURL == "" {
+		htmlURL = app.GetHTMLURL()
+	}
+	if slug == "" && htmlURL == "" {
+		return status, nil
+	}
+
+	ms, ok := store.(configstore.MetadataStore)
+	if !ok {
+		clog.FromContext(ctx).Warnf("[appinfo] store %T cannot persist app metadata; using fetched values for this status only", store)
+	} else if err := ms.SaveMetadata(ctx, slug, htmlURL); err != nil {
+		return status, fmt.Errorf("appinfo: failed to save app metadata: %w", err)
+	}
+
+	if slug != "" {
+		status.AppSlug = slug
+	}
+	if htmlURL != "" {
+		status.HTMLURL = htmlURL
+	}
+	clog.FromContext(ctx).Infof("[appinfo] backfilled app metadata: slug=%s html_url=%s", status.AppSlug, status.HTMLURL)
+	return status, nil
+}
+
+// BackfillRuntime runs Backfill against the Runtime's store, authenticating
+// as the Runtime's app. It is meant to be called from a LoadFunc after the
+// credentials are loaded; the Runtime is available from the LoadFunc's
+// context through ghappsetup.FromContext.
+func BackfillRuntime(ctx context.Context, r *ghappsetup.Runtime) error {
+	if r == nil {
+		return errors.New("appinfo: runtime is required")
+	}
+	client, err := ghauth.NewClient(ctx, r, 0)
+	if err != nil {
+		return err
+	}
+	_, err = Backfill(ctx, r.Store(), client)
+	return err
+}
+
+// BackfillOnLoad runs BackfillRuntime with the Runtime from ctx and logs
+// any failure instead of returning it. It matches ghappsetup.Config's
+// AfterLoad, so metadata is backfilled after every successful load:
+//
+//	ghappsetup.Config{
+//	    LoadFunc:  loadConfig,
+//	    AfterLoad: appinfo.BackfillOnLoad,
+//	}
+func BackfillOnLoad(ctx context.Context) {
+	if err := BackfillRuntime(ctx, ghappsetup.FromContext(ctx)); err != nil {
+		clog.FromContext(ctx).Warnf("[appinfo] app metadata backfill failed: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package appinfo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func newBackfillStore(t *testing.T, slug string) *configstore.LocalFileStore {
+	t.Helper()
+	store := configstore.NewLocalFileStore(filepath.Join(t.TempDir(), "creds"))
+	err := store.Save(context.Background(), &configstore.AppCredentials{
+		AppID:         1,
+		AppSlug:       slug,
+		ClientID:      "Iv1.abc",
+		ClientSecret:  "secret",
+		WebhookSecret: "hook",
+		PrivateKey:    "key",
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return store
+}
+
+func newCountingAppClient(t *testing.T, calls *atomic.Int32) *github.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":       1,
+			"slug":     "live-app",
+			"html_url": "https://github.com/apps/live-app",
+		})
+	}))
+	t.Cleanup(srv.Close)
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	client := newCountingAppClient(t, &calls)
+	store := newBackfillStore(t, "hand-set")
+
+	status, err := Backfill(ctx, store, client)
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+	if status.AppSlug != "hand-set" || status.HTMLURL != "https://github.com/apps/live-app" {
+		t.Errorf("Backfill() = %+v, want hand-set slug kept and HTML URL filled", status)
+	}
+
+	stored, _ := store.Status(ctx)
+	if stored.AppSlug != "hand-set" || stored.HTMLURL != "https://github.com/apps/live-app" {
+		t.Errorf("stored status = %+v, want backfilled values persisted", stored)
+	}
+
+	if _, err := Backfill(ctx, store, client); err != nil {
+		t.Fatalf("second Backfill() error = %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("API calls = %d, want 1 (no fetch once metadata is complete)", got)
+	}
+}
+
+func TestBackfill_Unregistered(t *testing.T) {
+	var calls atomic.Int32
+	client := newCountingAppClient(t, &calls)
+	store := configstore.NewLocalFileStore(t.TempDir())
+
+	status, err := Backfill(context.Background(), store, client)
+	if err != nil || status.Registered {
+		t.Fatalf("Backfill() = %+v, %v", status, err)
+	}
+	if calls.Load() != 0 {
+		t.Error("Backfill() should not call the API without registered credentials")
+	}
+}
+
+// statusOnlyStore is a Store that cannot persist metadata.
+type statusOnlyStore struct {
+	configstore.Store
+}
+
+func TestBackfill_StoreWithoutMetadataSupport(t *testing.T) {
+	var calls atomic.Int32
+	client := newCountingAppClient(t, &calls)
+	store := statusOnlyStore{newBackfillStore(t, "")}
+
+	status, err := Backfill(context.Background(), store, client)
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+	if status.AppSlug != "live-app" {
+		t.Errorf("Backfill() slug = %q, want fetched value", status.AppSlug)
+	}
+}
+
+func TestBackfillOnLoad_NoRuntime(t *testing.T) {
+	// Failures are logged, never returned or raised.
+	BackfillOnLoad(context.Background())
+}
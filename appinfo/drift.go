@@ -0,0 +1,230 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package appinfo inspects the live GitHub App registration through the
+// API, for example to detect settings that were changed by hand on GitHub
+// and no longer match the configured manifest.
+package appinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// DefaultCheckInterval is how often Checker.Run compares the live app
+// against the manifest.
+const DefaultCheckInterval = time.Hour
+
+// PermissionChange is a permission whose live level differs from the
+// manifest. An empty level means the permission is not granted or not
+// configured.
+type PermissionChange struct {
+	Name string `json:"name"`
+	Want string `json:"want"`
+	Got  string `json:"got"`
+}
+
+// Drift lists the differences between the live app and the manifest.
+type Drift struct {
+	CheckedAt     time.Time          `json:"checked_at"`
+	Permissions   []PermissionChange `json:"permissions,omitempty"`
+	MissingEvents []string           `json:"missing_events,omitempty"`
+	ExtraEvents   []string           `json:"extra_events,omitempty"`
+}
+
+// HasDrift reports whether any difference was found.
+func (d *Drift) HasDrift() bool {
+	return d != nil && (len(d.Permissions) > 0 || len(d.MissingEvents) > 0 || len(d.ExtraEvents) > 0)
+}
+
+// Compare returns the differences between the live app's permissions and
+// events and those the manifest requests. The "metadata" permission,
+// which GitHub grants every app, is only compared if the manifest sets it.
+func Compare(m installer.Manifest, app *github.App) *Drift {
+	d := &Drift{}
+
+	live := permissionMap(app.GetPermissions())
+	if _, ok := m.DefaultPerms["metadata"]; !ok {
+		delete(live, "metadata")
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.DefaultPerms)) {
+		if want := m.DefaultPerms[name]; live[name] != want {
+			d.Permissions = append(d.Permissions, PermissionChange{Name: name, Want: want, Got: live[name]})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(live)) {
+		if _, ok := m.DefaultPerms[name]; !ok {
+			d.Permissions = append(d.Permissions, PermissionChange{Name: name, Got: live[name]})
+		}
+	}
+
+	for _, event := range m.DefaultEvents {
+		if !slices.Contains(app.Events, event) {
+			d.MissingEvents = append(d.MissingEvents, event)
+		}
+	}
+	for _, event := range app.Events {
+		if !slices.Contains(m.DefaultEvents, event) {
+			d.ExtraEvents = append(d.ExtraEvents, event)
+		}
+	}
+	return d
+}
+
+// permissionMap flattens go-github's permission struct into name/level
+// pairs.
+func permissionMap(p *github.InstallationPermissions) map[string]string {
+	perms := make(map[string]string)
+	if p == nil {
+		return perms
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return perms
+	}
+	_ = json.Unmarshal(data, &perms)
+	return perms
+}
+
+// Checker compares the live app against a manifest on demand or
+// periodically, keeping the latest result for status endpoints.
+type Checker struct {
+	client   *github.Client
+	manifest installer.Manifest
+	interval time.Duration
+	onDrift  func(ctx context.Context, d *Drift)
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	last    *Drift
+	lastErr error
+}
+
+// CheckerOption is a functional option for configuring Checker.
+type CheckerOption func(*Checker)
+
+// WithCheckInterval sets how often Run checks. The default is
+// DefaultCheckInterval.
+func WithCheckInterval(d time.Duration) CheckerOption {
+	return func(c *Checker) {
+		c.interval = d
+	}
+}
+
+// WithOnDrift sets a hook called after every check that finds drift, e.g.
+// to alert or re-apply the manifest.
+func WithOnDrift(fn func(ctx context.Context, d *Drift)) CheckerOption {
+	return func(c *Checker) {
+		c.onDrift = fn
+	}
+}
+
+// NewChecker creates a Checker. The client must authenticate as the app
+// (e.g. ghauth.NewClient with installation ID zero).
+func NewChecker(client *github.Client, manifest installer.Manifest, opts ...CheckerOption) *Checker {
+	c := &Checker{
+		client:   client,
+		manifest: manifest,
+		interval: DefaultCheckInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Check fetches the app and compares it against the manifest.
+func (c *Checker) Check(ctx context.Context) (*Drift, error) {
+	if c.client == nil {
+		return nil, errors.New("appinfo: checker has no GitHub client")
+	}
+	app, _, err := c.client.Apps.Get(ctx, "")
+	if err != nil {
+		err = fmt.Errorf("appinfo: failed to fetch app: %w", err)
+		c.setResult(nil, err)
+		return nil, err
+	}
+
+	d := Compare(c.manifest, app)
+	d.CheckedAt = c.now()
+	c.setResult(d, nil)
+
+	if d.HasDrift() {
+		clog.FromContext(ctx).Warnf("[appinfo] app settings drifted from manifest: %d permission changes, %d missing events, %d extra events",
+			len(d.Permissions), len(d.MissingEvents), len(d.ExtraEvents))
+		if c.onDrift != nil {
+			c.onDrift(ctx, d)
+		}
+	}
+	return d, nil
+}
+
+// Run checks immediately and then every interval until ctx is canceled.
+// Failed checks are logged and retried at the next interval.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Check(ctx); err != nil && ctx.Err() == nil {
+			clog.FromContext(ctx).Warnf("[appinfo] drift check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Last returns the result of the most recent check, or nil before the
+// first check.
+func (c *Checker) Last() (*Drift, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.last, c.lastErr
+}
+
+func (c *Checker) setResult(d *Drift, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d != nil {
+		c.last = d
+	}
+	c.lastErr = err
+}
+
+// StatusHandler returns an http.Handler reporting the latest check as
+// JSON: {"drift": bool, "result": Drift, "error": string}. A failed check
+// keeps the previous result and reports the error alongside it.
+func (c *Checker) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d, err := c.Last()
+		resp := struct {
+			Drift  bool   `json:"drift"`
+			Result *Drift `json:"result,omitempty"`
+			Error  string `json:"error,omitempty"`
+		}{Drift: d.HasDrift(), Result: d}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			clog.FromContext(r.Context()).Errorf("[appinfo] failed to write drift status: %v", err)
+		}
+	})
+}
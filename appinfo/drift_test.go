@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package appinfo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+var testManifest = installer.Manifest{
+	DefaultPerms:  map[string]string{"contents": "read", "pull_requests": "write", "issues": "write"},
+	DefaultEvents: []string{"pull_request", "push"},
+}
+
+// newAppServer returns a client whose GET /app returns app.
+func newAppServer(t *testing.T, app map[string]any) *github.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(app)
+	}))
+	t.Cleanup(srv.Close)
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+func TestCompare(t *testing.T) {
+	app := &github.App{
+		Permissions: &github.InstallationPermissions{
+			Contents:     github.Ptr("read"),
+			PullRequests: github.Ptr("read"),
+			Metadata:     github.Ptr("read"),
+			Checks:       github.Ptr("write"),
+		},
+		Events: []string{"push", "check_run"},
+	}
+
+	d := Compare(testManifest, app)
+	want := []PermissionChange{
+		{Name: "issues", Want: "write", Got: ""},
+		{Name: "pull_requests", Want: "write", Got: "read"},
+		{Name: "checks", Want: "", Got: "write"},
+	}
+	if !slices.Equal(d.Permissions, want) {
+		t.Errorf("Permissions = %+v, want %+v", d.Permissions, want)
+	}
+	if !slices.Equal(d.MissingEvents, []string{"pull_request"}) {
+		t.Errorf("MissingEvents = %v, want [pull_request]", d.MissingEvents)
+	}
+	if !slices.Equal(d.ExtraEvents, []string{"check_run"}) {
+		t.Errorf("ExtraEvents = %v, want [check_run]", d.ExtraEvents)
+	}
+	if !d.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+}
+
+func TestCompare_NoDrift(t *testing.T) {
+	app := &github.App{
+		Permissions: &github.InstallationPermissions{
+			Contents:     github.Ptr("read"),
+			PullRequests: github.Ptr("write"),
+			Issues:       github.Ptr("write"),
+			Metadata:     github.Ptr("read"),
+		},
+		Events: []string{"push", "pull_request"},
+	}
+	if d := Compare(testManifest, app); d.HasDrift() {
+		t.Errorf("Compare() = %+v, want no drift", d)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	client := newAppServer(t, map[string]any{
+		"id":          1,
+		"permissions": map[string]string{"contents": "read", "pull_requests": "write"},
+		"events":      []string{"push", "pull_request"},
+	})
+
+	var hooked *Drift
+	c := NewChecker(client, testManifest, WithOnDrift(func(ctx context.Context, d *Drift) { hooked = d }))
+	d, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if hooked != d {
+		t.Error("OnDrift hook should receive the drift")
+	}
+	if last, _ := c.Last(); last != d {
+		t.Error("Last() should return the latest check")
+	}
+
+	rec := httptest.NewRecorder()
+	c.StatusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/drift", nil))
+	var body struct {
+		Drift  bool  `json:"drift"`
+		Result Drift `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if !body.Drift || len(body.Result.Permissions) != 1 || body.Result.Permissions[0].Name != "issues" {
+		t.Errorf("status = %s", rec.Body.String())
+	}
+}
+
+func TestChecker_ReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	c := NewChecker(client, testManifest)
+	if _, err := c.Check(context.Background()); err == nil {
+		t.Fatal("Check() should fail when the API rejects the request")
+	}
+	if _, err := c.Last(); err == nil {
+		t.Error("Last() should report the failed check")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Command ghappextension is an AWS Lambda external extension that caches
+// GitHub App credentials for the function. It reads the store configured
+// by the function's STORAGE_MODE variables once per execution environment
+// and serves the credentials on localhost, where the handler reads them
+// with lambdaext.ResolveEnvironment instead of calling the store on every
+// cold start.
+//
+// Build it for the function's architecture and ship it in a layer under
+// extensions/ (see "make lambda-extension"):
+//
+//	GOOS=linux GOARCH=arm64 CGO_ENABLED=0 go build -o extensions/ghappextension ./cmd/ghappextension
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/lambdaext"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	logger := clog.New(slog.NewTextHandler(os.Stderr, nil))
+	ctx = clog.WithLogger(ctx, logger)
+
+	if err := run(ctx); err != nil {
+		logger.Errorf("[ghappextension] %v", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	store, err := configstore.NewFromEnv()
+	if err != nil {
+		return err
+	}
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return errors.New("store cannot load credentials")
+	}
+	return lambdaext.New(loader).Run(ctx)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// controller syncs every GitHubAppCredential on each pass.
+type controller struct {
+	kube      *kubeClient
+	namespace string // empty for every namespace
+
+	newStore func(spec storeSpec) (configstore.Store, error)
+	signal   func(ctx context.Context, namespace, pod, container string) error
+	now      func() time.Time
+}
+
+// syncAll syncs each credential and records the outcome in its status.
+// Failures of single credentials are logged and reported in their status;
+// only a failure to list them is returned.
+func (c *controller) syncAll(ctx context.Context) error {
+	list, err := c.kube.listCredentials(ctx, c.namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list %s resources: %w", crdKind, err)
+	}
+	for i := range list {
+		cr := &list[i]
+		log := clog.FromContext(ctx).With("namespace", cr.Metadata.Namespace, "name", cr.Metadata.Name)
+		prev := cr.Status
+		if err := c.sync(ctx, cr); err != nil {
+			log.Errorf("[ghappoperator] sync failed: %v", err)
+			cr.Status.Error = err.Error()
+		} else {
+			cr.Status.Error = ""
+		}
+		cr.Status.ObservedGeneration = cr.Metadata.Generation
+		cr.Status.LastSyncTime = timestamp(c.now())
+		if cr.Status.CredentialsHash != prev.CredentialsHash {
+			log.Infof("[ghappoperator] synced app %d to secret %s", cr.Status.AppID, cr.secretName())
+		}
+		if err := c.kube.updateCredentialStatus(ctx, cr); err != nil {
+			log.Errorf("[ghappoperator] failed to update status: %v", err)
+		}
+	}
+	return nil
+}
+
+// sync copies the credentials in cr's store to its Secret and reloads the
+// deployments consuming them.
+func (c *controller) sync(ctx context.Context, cr *gitHubAppCredential) error {
+	store, err := c.newStore(cr.Spec.Store)
+	if err != nil {
+		return err
+	}
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return errors.New("store cannot load credentials")
+	}
+	creds, err := loader.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+	data := secretData(creds)
+	hash := credentialsHash(data)
+	cr.Status.AppID = creds.AppID
+
+	updated, err := c.syncSecret(ctx, cr, data, hash)
+	if err != nil {
+		return err
+	}
+	if updated {
+		cr.Status.SecretUpdateTime = timestamp(c.now())
+	}
+	cr.Status.CredentialsHash = hash
+	return c.reloadDeployments(ctx, cr, hash)
+}
+
+// secretData returns the Secret keys for creds, named like the
+// environment variables configstore reads, so consumers can use envFrom.
+func secretData(creds *configstore.AppCredentials) map[string]string {
+	data := map[string]string{
+		configstore.EnvGitHubAppID:         strconv.FormatInt(creds.AppID, 10),
+		configstore.EnvGitHubAppSlug:       creds.AppSlug,
+		configstore.EnvGitHubAppHTMLURL:    creds.HTMLURL,
+		configstore.EnvGitHubAppPrivateKey: creds.PrivateKey,
+		configstore.EnvGitHubWebhookSecret: creds.WebhookSecret,
+		configstore.EnvGitHubClientID:      creds.ClientID,
+		configstore.EnvGitHubClientSecret:  creds.ClientSecret,
+	}
+	maps.Copy(data, creds.CustomFields)
+	maps.DeleteFunc(data, func(_, value string) bool { return value == "" })
+	return data
+}
+
+// credentialsHash identifies the contents of data.
+func credentialsHash(data map[string]string) string {
+	h := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		fmt.Fprintf(h, "%d:%s=%d:%s\n", len(key), key, len(data[key]), data[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// syncSecret creates or updates cr's Secret and reports whether it
+// changed. It refuses to overwrite a Secret cr does not own.
+func (c *controller) syncSecret(ctx context.Context, cr *gitHubAppCredential, data map[string]string, hash string) (bool, error) {
+	namespace, name := cr.Metadata.Namespace, cr.secretName()
+	existing, err := c.kube.getSecret(ctx, namespace, name)
+	if errors.Is(err, errNotFound) {
+		s := &secret{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata: objectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{"app.kubernetes.io/managed-by": "ghappoperator"},
+				Annotations:     map[string]string{annotationHash: hash},
+				OwnerReferences: []ownerReference{cr.ownerReference()},
+			},
+			Type:       "Opaque",
+			StringData: data,
+		}
+		if err := c.kube.createSecret(ctx, s); err != nil {
+			return false, fmt.Errorf("failed to create secret %s: %w", name, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get secret %s: %w", name, err)
+	}
+	if !slices.ContainsFunc(existing.Metadata.OwnerReferences, func(ref ownerReference) bool { return ref.UID == cr.Metadata.UID }) {
+		return false, fmt.Errorf("secret %s exists and is not owned by this %s", name, crdKind)
+	}
+	if existing.Metadata.Annotations[annotationHash] == hash {
+		return false, nil
+	}
+
+	if existing.Metadata.Annotations == nil {
+		existing.Metadata.Annotations = make(map[string]string)
+	}
+	existing.Metadata.Annotations[annotationHash] = hash
+	existing.APIVersion, existing.Kind = "v1", "Secret"
+	// Replacing data drops keys the store no longer has
+	existing.Data, existing.StringData = nil, data
+	if err := c.kube.updateSecret(ctx, existing); err != nil {
+		return false, fmt.Errorf("failed to update secret %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// reloadDeployments makes cr's deployments pick up the credentials with
+// the given hash. Both strategies are idempotent: they record the hash
+// and skip what already has it, so each change reloads once.
+func (c *controller) reloadDeployments(ctx context.Context, cr *gitHubAppCredential, hash string) error {
+	var errs []error
+	for _, name := range cr.Spec.Deployments {
+		var err error
+		switch cr.reload() {
+		case reloadRollout:
+			err = c.rollout(ctx, cr.Metadata.Namespace, name, hash)
+		case reloadSignal:
+			err = c.signalPods(ctx, cr.Metadata.Namespace, name, cr.Spec.Container, hash)
+		case reloadNone:
+		default:
+			return fmt.Errorf("unknown reload %q (expected %s, %s, or %s)", cr.Spec.Reload, reloadRollout, reloadSignal, reloadNone)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deployment %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rollout sets the hash annotation on the deployment's pod template, which
+// rolls out new pods when it changes, like kubectl rollout restart.
+func (c *controller) rollout(ctx context.Context, namespace, name, hash string) error {
+	patch := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{annotationHash: hash},
+				},
+			},
+		},
+	}
+	return c.kube.patchDeployment(ctx, namespace, name, patch)
+}
+
+// signalPods sends SIGHUP to the running pods of the deployment that have
+// not been signaled for hash, then annotates them with it.
+func (c *controller) signalPods(ctx context.Context, namespace, name, container, hash string) error {
+	d, err := c.kube.getDeployment(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	if len(d.Spec.Selector.MatchExpressions) > 0 || len(d.Spec.Selector.MatchLabels) == 0 {
+		return errors.New("signal reload needs a selector with matchLabels only")
+	}
+	pods, err := c.kube.listPods(ctx, namespace, d.Spec.Selector.MatchLabels)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range pods {
+		if p.Status.Phase != "Running" || p.Metadata.Annotations[annotationHash] == hash {
+			continue
+		}
+		target := container
+		if target == "" && len(p.Spec.Containers) > 0 {
+			target = p.Spec.Containers[0].Name
+		}
+		if err := c.signal(ctx, namespace, p.Metadata.Name, target); err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", p.Metadata.Name, err))
+			continue
+		}
+		patch := map[string]any{"metadata": map[string]any{"annotations": map[string]string{annotationHash: hash}}}
+		if err := c.kube.patchPod(ctx, namespace, p.Metadata.Name, patch); err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", p.Metadata.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
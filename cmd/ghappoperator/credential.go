@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The GitHubAppCredential custom resource, defined in deploy/crd.yaml.
+const (
+	crdGroup      = "ghappsetup.cruxstack.io"
+	crdVersion    = "v1alpha1"
+	crdAPIVersion = crdGroup + "/" + crdVersion
+	crdKind       = "GitHubAppCredential"
+	crdResource   = "githubappcredentials"
+)
+
+// Reload strategies for the deployments consuming a credential.
+const (
+	// reloadRollout restarts the deployments by changing an annotation
+	// on their pod templates, for workloads reading the Secret.
+	reloadRollout = "rollout"
+	// reloadSignal sends SIGHUP to the deployments' pods, for workloads
+	// that reload from the store themselves, e.g. with
+	// Runtime.ListenForReloads.
+	reloadSignal = "signal"
+	// reloadNone only updates the Secret.
+	reloadNone = "none"
+)
+
+// Annotations the operator sets.
+const (
+	// annotationHash holds the hash of the credentials on the Secret, on
+	// the pod templates it rolled out, and on the pods it signaled.
+	annotationHash = crdGroup + "/credentials-hash"
+)
+
+// gitHubAppCredential asks the operator to sync the credentials in a store
+// to a Secret and to reload the deployments that consume them.
+type gitHubAppCredential struct {
+	APIVersion string                    `json:"apiVersion"`
+	Kind       string                    `json:"kind"`
+	Metadata   objectMeta                `json:"metadata"`
+	Spec       gitHubAppCredentialSpec   `json:"spec"`
+	Status     gitHubAppCredentialStatus `json:"status"`
+}
+
+type gitHubAppCredentialSpec struct {
+	Store storeSpec `json:"store"`
+	// SecretName defaults to the resource's name.
+	SecretName string `json:"secretName,omitempty"`
+	// Deployments in the same namespace are reloaded when the
+	// credentials change.
+	Deployments []string `json:"deployments,omitempty"`
+	// Reload is rollout (the default), signal, or none.
+	Reload string `json:"reload,omitempty"`
+	// Container receives the signal; it defaults to each pod's first
+	// container.
+	Container string `json:"container,omitempty"`
+}
+
+// storeSpec selects the store like STORAGE_MODE, STORAGE_DIR, and
+// AWS_SSM_PARAMETER_PREFIX do for configstore.NewFromEnv. The local modes
+// read paths on the operator's own filesystem.
+type storeSpec struct {
+	Mode   string `json:"mode"`
+	Prefix string `json:"prefix,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+type gitHubAppCredentialStatus struct {
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+	AppID              int64  `json:"appID,omitempty"`
+	CredentialsHash    string `json:"credentialsHash,omitempty"`
+	LastSyncTime       string `json:"lastSyncTime,omitempty"`
+	SecretUpdateTime   string `json:"secretUpdateTime,omitempty"`
+	// Error describes the last failed sync; it is empty after a
+	// successful one.
+	Error string `json:"error,omitempty"`
+}
+
+func (cr *gitHubAppCredential) secretName() string {
+	if cr.Spec.SecretName != "" {
+		return cr.Spec.SecretName
+	}
+	return cr.Metadata.Name
+}
+
+func (cr *gitHubAppCredential) reload() string {
+	if cr.Spec.Reload != "" {
+		return cr.Spec.Reload
+	}
+	return reloadRollout
+}
+
+// ownerReference makes the Secret garbage collected with cr.
+func (cr *gitHubAppCredential) ownerReference() ownerReference {
+	return ownerReference{APIVersion: crdAPIVersion, Kind: crdKind, Name: cr.Metadata.Name, UID: cr.Metadata.UID, Controller: true}
+}
+
+// listCredentials lists the GitHubAppCredentials in namespace, or in every
+// namespace if it is empty.
+func (c *kubeClient) listCredentials(ctx context.Context, namespace string) ([]gitHubAppCredential, error) {
+	var list struct {
+		Items []gitHubAppCredential `json:"items"`
+	}
+	err := c.do(ctx, http.MethodGet, namespacePath("/apis/"+crdAPIVersion, namespace, crdResource), "", nil, &list)
+	return list.Items, err
+}
+
+// updateCredentialStatus writes the status subresource of cr.
+func (c *kubeClient) updateCredentialStatus(ctx context.Context, cr *gitHubAppCredential) error {
+	path := namespacePath("/apis/"+crdAPIVersion, cr.Metadata.Namespace, crdResource+"/"+url.PathEscape(cr.Metadata.Name)+"/status")
+	var status map[string]any
+	data, err := json.Marshal(cr.Status)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	// A merge patch keeps fields it omits, so clear the error explicitly
+	if cr.Status.Error == "" {
+		status["error"] = nil
+	}
+	return c.do(ctx, http.MethodPatch, path, mergePatchContentType, map[string]any{"status": status}, nil)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
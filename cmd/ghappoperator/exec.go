@@ -0,0 +1,177 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Kubernetes streams exec sessions over a WebSocket whose messages start
+// with a channel byte. The error channel carries the command's final
+// status.
+const (
+	execProtocol      = "v4.channel.k8s.io"
+	execChannelStderr = 2
+	execChannelError  = 3
+
+	websocketGUID       = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	maxExecMessageBytes = 1 << 20
+)
+
+// signalCommand sends SIGHUP to the container's main process. The image
+// must provide kill, e.g. from a shell or busybox.
+var signalCommand = []string{"kill", "-HUP", "1"}
+
+// signal runs signalCommand in a pod's container with the exec API.
+func (c *kubeClient) signal(ctx context.Context, namespace, podName, container string) error {
+	ctx, cancel := context.WithTimeout(ctx, kubeRequestTimeout)
+	defer cancel()
+
+	query := url.Values{"command": signalCommand, "stdout": {"true"}, "stderr": {"true"}}
+	if container != "" {
+		query.Set("container", container)
+	}
+	path := namespacePath("/api/v1", namespace, "pods/"+url.PathEscape(podName)+"/exec") + "?" + query.Encode()
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Protocol", execProtocol)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("exec in pod %s: %w", podName, errNotFound)
+		}
+		return &statusError{code: resp.StatusCode, message: strings.TrimSpace(string(data))}
+	}
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		return errors.New("exec: invalid WebSocket handshake")
+	}
+	// Closing the body, not the request context, ends the session once
+	// the handshake is done
+	stop := context.AfterFunc(ctx, func() { resp.Body.Close() })
+	defer stop()
+	return readExecStatus(bufio.NewReader(resp.Body))
+}
+
+// readExecStatus reads exec messages until the status arrives on the
+// error channel, and returns an error if the command failed.
+func readExecStatus(r *bufio.Reader) error {
+	var stderr strings.Builder
+	for {
+		msg, err := readWebSocketMessage(r)
+		if errors.Is(err, io.EOF) {
+			return errors.New("exec: session ended without a status")
+		}
+		if err != nil {
+			return fmt.Errorf("exec: %w", err)
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		switch msg[0] {
+		case execChannelStderr:
+			stderr.Write(msg[1:])
+		case execChannelError:
+			var status struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(msg[1:], &status); err != nil {
+				return fmt.Errorf("exec: invalid status: %w", err)
+			}
+			if status.Status == "Success" {
+				return nil
+			}
+			if out := strings.TrimSpace(stderr.String()); out != "" {
+				return fmt.Errorf("%s failed: %s: %s", strings.Join(signalCommand, " "), status.Message, out)
+			}
+			return fmt.Errorf("%s failed: %s", strings.Join(signalCommand, " "), status.Message)
+		}
+	}
+}
+
+// readWebSocketMessage reads the next data message, joining fragmented
+// frames. Control frames other than close are skipped; close returns
+// io.EOF.
+func readWebSocketMessage(r *bufio.Reader) ([]byte, error) {
+	var msg []byte
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, err
+		}
+		fin, opcode := header[0]&0x80 != 0, header[0]&0x0f
+		masked, size := header[1]&0x80 != 0, uint64(header[1]&0x7f)
+		switch size {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return nil, err
+			}
+			size = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return nil, err
+			}
+			size = binary.BigEndian.Uint64(ext[:])
+		}
+		if size > maxExecMessageBytes || uint64(len(msg))+size > maxExecMessageBytes {
+			return nil, errors.New("message too large")
+		}
+		var mask [4]byte
+		if masked {
+			if _, err := io.ReadFull(r, mask[:]); err != nil {
+				return nil, err
+			}
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		switch {
+		case opcode == 0x8: // close
+			return nil, io.EOF
+		case opcode >= 0x8: // ping and pong
+			continue
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// frame encodes an unmasked WebSocket frame.
+func frame(fin bool, opcode byte, payload []byte) []byte {
+	b0 := opcode
+	if fin {
+		b0 |= 0x80
+	}
+	out := []byte{b0}
+	switch n := len(payload); {
+	case n < 126:
+		out = append(out, byte(n))
+	case n < 1<<16:
+		out = append(out, 126, byte(n>>8), byte(n))
+	default:
+		out = append(out, 127, 0, 0, 0, 0, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, payload...)
+}
+
+func channel(ch byte, data string) []byte {
+	return frame(true, 0x2, append([]byte{ch}, data...))
+}
+
+// execServer accepts exec WebSocket requests and replies with frames.
+func execServer(t *testing.T, frames ...[]byte) (*kubeClient, *http.Request) {
+	var got http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = *r.Clone(context.Background())
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Protocol") != execProtocol {
+			http.Error(w, `{"message":"upgrade required"}`, http.StatusBadRequest)
+			return
+		}
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Protocol: %s\r\nSec-WebSocket-Accept: %s\r\n\r\n", execProtocol, base64.StdEncoding.EncodeToString(sum[:]))
+		for _, f := range frames {
+			rw.Write(f)
+		}
+		rw.Flush()
+	}))
+	t.Cleanup(srv.Close)
+	return newKubeClient(srv.URL), &got
+}
+
+func TestSignal(t *testing.T) {
+	kube, req := execServer(t,
+		frame(true, 0x9, nil), // ping
+		channel(1, ""),
+		channel(execChannelError, `{"metadata":{},"status":"Success"}`),
+	)
+	if err := kube.signal(context.Background(), "apps", "web-1", "app"); err != nil {
+		t.Fatalf("signal() error = %v", err)
+	}
+	if req.URL.Path != "/api/v1/namespaces/apps/pods/web-1/exec" {
+		t.Errorf("path = %s", req.URL.Path)
+	}
+	q := req.URL.Query()
+	if !slices.Equal(q["command"], signalCommand) || q.Get("container") != "app" {
+		t.Errorf("query = %v", q)
+	}
+}
+
+func TestSignal_Failure(t *testing.T) {
+	kube, _ := execServer(t,
+		channel(execChannelStderr, "sh: kill: not found\n"),
+		channel(execChannelError, `{"status":"Failure","message":"command terminated with non-zero exit code"}`),
+	)
+	err := kube.signal(context.Background(), "apps", "web-1", "app")
+	if err == nil || !strings.Contains(err.Error(), "kill: not found") {
+		t.Errorf("signal() error = %v, want the command's stderr", err)
+	}
+
+	kube, _ = execServer(t, frame(true, 0x8, nil))
+	if err := kube.signal(context.Background(), "apps", "web-1", "app"); err == nil {
+		t.Error("signal() without a status succeeded")
+	}
+}
+
+func TestReadWebSocketMessage(t *testing.T) {
+	long := strings.Repeat("x", 70000)
+	var stream bytes.Buffer
+	stream.Write(frame(false, 0x1, []byte("frag")))
+	stream.Write(frame(true, 0x0, []byte("mented")))
+	stream.Write(frame(true, 0x2, []byte(long)))
+	// A masked frame, which servers should not send but may
+	mask := []byte{1, 2, 3, 4}
+	payload := []byte("masked")
+	masked := []byte{0x82, 0x80 | byte(len(payload))}
+	masked = append(masked, mask...)
+	for i, b := range payload {
+		masked = append(masked, b^mask[i%4])
+	}
+	stream.Write(masked)
+
+	r := bufio.NewReader(&stream)
+	for _, want := range []string{"fragmented", long, "masked"} {
+		got, err := readWebSocketMessage(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("message = %.20q (%d bytes), want %.20q", got, len(got), want)
+		}
+	}
+}
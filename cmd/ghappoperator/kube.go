@@ -0,0 +1,260 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// serviceAccountDir holds the credentials Kubernetes mounts into pods.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+const kubeRequestTimeout = 30 * time.Second
+
+// kubeClient calls the Kubernetes API with plain JSON requests. It covers
+// the handful of calls the operator makes, which keeps client-go out of
+// the module.
+type kubeClient struct {
+	baseURL   string
+	tokenFile string // re-read per request, since bound tokens rotate
+	client    *http.Client
+}
+
+// errNotFound is returned for 404 responses.
+var errNotFound = errors.New("not found")
+
+// statusError is a failed API response.
+type statusError struct {
+	code    int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("kubernetes API returned %d: %s", e.code, e.message)
+}
+
+// inClusterClient returns a client using the pod's service account.
+func inClusterClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a cluster; pass -kube-api, e.g. the address of kubectl proxy")
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("cluster CA contains no certificates")
+	}
+	// HTTP/2 is not enabled, since exec upgrades the connection
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}
+	return &kubeClient{
+		baseURL:   "https://" + net.JoinHostPort(host, port),
+		tokenFile: filepath.Join(serviceAccountDir, "token"),
+		client:    &http.Client{Transport: transport},
+	}, nil
+}
+
+// newKubeClient returns a client for an API server that needs no
+// credentials, such as kubectl proxy.
+func newKubeClient(baseURL string) *kubeClient {
+	return &kubeClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{}}
+}
+
+// newRequest builds an authenticated request for path, which may include a
+// query.
+func (c *kubeClient) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return req, nil
+}
+
+// do sends in as the request body with contentType, if in is not nil, and
+// decodes the response into out, if out is not nil.
+func (c *kubeClient) do(ctx context.Context, method, path, contentType string, in, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, kubeRequestTimeout)
+	defer cancel()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+	case resp.StatusCode >= 300:
+		var status struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &status) != nil || status.Message == "" {
+			status.Message = strings.TrimSpace(string(data))
+		}
+		return &statusError{code: resp.StatusCode, message: status.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+const (
+	jsonContentType       = "application/json"
+	mergePatchContentType = "application/merge-patch+json"
+)
+
+// objectMeta is the subset of ObjectMeta the operator reads and writes.
+type objectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	UID             string            `json:"uid,omitempty"`
+	Generation      int64             `json:"generation,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	OwnerReferences []ownerReference  `json:"ownerReferences,omitempty"`
+}
+
+type ownerReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	Controller bool   `json:"controller"`
+}
+
+type secret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   objectMeta        `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	StringData map[string]string `json:"stringData,omitempty"`
+	Data       map[string][]byte `json:"data,omitempty"`
+}
+
+type deployment struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Selector struct {
+			MatchLabels      map[string]string `json:"matchLabels"`
+			MatchExpressions []json.RawMessage `json:"matchExpressions"`
+		} `json:"selector"`
+	} `json:"spec"`
+}
+
+type pod struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Containers []struct {
+			Name string `json:"name"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+func namespacePath(group, namespace, resource string) string {
+	if namespace == "" {
+		return group + "/" + resource
+	}
+	return group + "/namespaces/" + url.PathEscape(namespace) + "/" + resource
+}
+
+func (c *kubeClient) getSecret(ctx context.Context, namespace, name string) (*secret, error) {
+	var s secret
+	err := c.do(ctx, http.MethodGet, namespacePath("/api/v1", namespace, "secrets/"+url.PathEscape(name)), "", nil, &s)
+	return &s, err
+}
+
+func (c *kubeClient) createSecret(ctx context.Context, s *secret) error {
+	return c.do(ctx, http.MethodPost, namespacePath("/api/v1", s.Metadata.Namespace, "secrets"), jsonContentType, s, nil)
+}
+
+// updateSecret replaces a secret, failing with a conflict if it changed
+// since it was read.
+func (c *kubeClient) updateSecret(ctx context.Context, s *secret) error {
+	path := namespacePath("/api/v1", s.Metadata.Namespace, "secrets/"+url.PathEscape(s.Metadata.Name))
+	return c.do(ctx, http.MethodPut, path, jsonContentType, s, nil)
+}
+
+func (c *kubeClient) getDeployment(ctx context.Context, namespace, name string) (*deployment, error) {
+	var d deployment
+	err := c.do(ctx, http.MethodGet, namespacePath("/apis/apps/v1", namespace, "deployments/"+url.PathEscape(name)), "", nil, &d)
+	return &d, err
+}
+
+// patchDeployment applies a JSON merge patch to a deployment.
+func (c *kubeClient) patchDeployment(ctx context.Context, namespace, name string, patch any) error {
+	path := namespacePath("/apis/apps/v1", namespace, "deployments/"+url.PathEscape(name))
+	return c.do(ctx, http.MethodPatch, path, mergePatchContentType, patch, nil)
+}
+
+// listPods lists the pods matching labels.
+func (c *kubeClient) listPods(ctx context.Context, namespace string, labels map[string]string) ([]pod, error) {
+	var selector []string
+	for key, value := range labels {
+		selector = append(selector, key+"="+value)
+	}
+	query := url.Values{"labelSelector": {strings.Join(selector, ",")}}
+	var list struct {
+		Items []pod `json:"items"`
+	}
+	err := c.do(ctx, http.MethodGet, namespacePath("/api/v1", namespace, "pods")+"?"+query.Encode(), "", nil, &list)
+	return list.Items, err
+}
+
+// patchPod applies a JSON merge patch to a pod.
+func (c *kubeClient) patchPod(ctx context.Context, namespace, name string, patch any) error {
+	path := namespacePath("/api/v1", namespace, "pods/"+url.PathEscape(name))
+	return c.do(ctx, http.MethodPatch, path, mergePatchContentType, patch, nil)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Command ghappoperator syncs GitHub App credentials from a configstore
+// backend to Kubernetes Secrets. Each GitHubAppCredential resource (see
+// deploy/crd.yaml) names a store, the Secret to write, and the
+// deployments to reload when the credentials change:
+//
+//	apiVersion: ghappsetup.cruxstack.io/v1alpha1
+//	kind: GitHubAppCredential
+//	metadata:
+//	  name: my-app
+//	spec:
+//	  store:
+//	    mode: aws-ssm
+//	    prefix: /my-app/prod/
+//	  deployments: [my-app]
+//
+// The operator re-reads every store on each pass, every -interval, since
+// stores have no change notifications; the interval bounds how long a
+// rotation takes to reach the Secret. It runs in the cluster with the
+// service account from deploy/rbac.yaml, or elsewhere against kubectl
+// proxy with -kube-api.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+const defaultInterval = time.Minute
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	fs := flag.NewFlagSet("ghappoperator", flag.ExitOnError)
+	kubeAPI := fs.String("kube-api", "", "Kubernetes API URL that needs no credentials, e.g. from kubectl proxy (default: in-cluster)")
+	namespace := fs.String("namespace", os.Getenv("WATCH_NAMESPACE"), "only sync resources in this namespace (default $WATCH_NAMESPACE, else all)")
+	interval := fs.Duration("interval", defaultInterval, "time between sync passes")
+	once := fs.Bool("once", false, "run one sync pass and exit")
+	fs.Parse(os.Args[1:])
+
+	logger := clog.New(slog.NewTextHandler(os.Stderr, nil))
+	ctx = clog.WithLogger(ctx, logger)
+
+	if err := run(ctx, *kubeAPI, *namespace, *interval, *once); err != nil {
+		logger.Errorf("[ghappoperator] %v", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, kubeAPI, namespace string, interval time.Duration, once bool) error {
+	if interval <= 0 {
+		return errors.New("-interval must be positive")
+	}
+	kube := newKubeClient(kubeAPI)
+	if kubeAPI == "" {
+		var err error
+		if kube, err = inClusterClient(); err != nil {
+			return err
+		}
+	}
+	c := &controller{
+		kube:      kube,
+		namespace: namespace,
+		newStore:  newStoreFactory(ctx),
+		signal:    kube.signal,
+		now:       time.Now,
+	}
+
+	log := clog.FromContext(ctx)
+	log.Infof("[ghappoperator] syncing every %v", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.syncAll(ctx); err != nil {
+			if once {
+				return err
+			}
+			log.Errorf("[ghappoperator] %v", err)
+		}
+		if once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newStoreFactory returns a function building the store a resource names.
+// The aws-ssm stores share one client using the operator's AWS
+// credentials.
+func newStoreFactory(ctx context.Context) func(storeSpec) (configstore.Store, error) {
+	var client *ssm.Client
+	return func(spec storeSpec) (configstore.Store, error) {
+		switch spec.Mode {
+		case configstore.StorageModeAWSSSM:
+			if spec.Prefix == "" {
+				return nil, errors.New("store.prefix is required for aws-ssm")
+			}
+			if client == nil {
+				cfg, err := config.LoadDefaultConfig(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load AWS config: %w", err)
+				}
+				client = ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+					if endpoint := os.Getenv(configstore.EnvAWSEndpointURLSSM); endpoint != "" {
+						o.BaseEndpoint = &endpoint
+					}
+				})
+			}
+			return configstore.NewAWSSSMStore(spec.Prefix, configstore.WithSSMClient(client))
+		case configstore.StorageModeEnvFile, configstore.StorageModeFiles:
+			if spec.Path == "" {
+				return nil, fmt.Errorf("store.path is required for %s", spec.Mode)
+			}
+			if spec.Mode == configstore.StorageModeFiles {
+				return configstore.NewLocalFileStore(spec.Path), nil
+			}
+			return configstore.NewLocalEnvFileStore(spec.Path), nil
+		default:
+			return nil, fmt.Errorf("unknown store.mode %q (expected %s, %s, or %s)", spec.Mode,
+				configstore.StorageModeAWSSSM, configstore.StorageModeEnvFile, configstore.StorageModeFiles)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// runBootstrap implements "ghappsetup bootstrap".
+func runBootstrap(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) == 0 {
+		return usagef("expected a backend: aws-ssm")
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(env.stderr, "Usage: ghappsetup bootstrap aws-ssm -prefix <path> [-kms <key>] [-tags k=v,...] [-policies <json>] [-param <name>]...")
+		return flag.ErrHelp
+	case configstore.StorageModeAWSSSM:
+		return runBootstrapSSM(ctx, env, args[1:])
+	default:
+		return usagef("unknown backend %q (expected %s)", args[0], configstore.StorageModeAWSSSM)
+	}
+}
+
+// runBootstrapSSM implements "ghappsetup bootstrap aws-ssm". Defaults
+// come from the same variables the store reads, so the parameters match
+// what the app will write.
+func runBootstrapSSM(ctx context.Context, env *cliEnv, args []string) error {
+	tags, err := tagsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet(env, "bootstrap aws-ssm", "-prefix <path> [-kms <key>] [-tags k=v,...] [-policies <json>] [-param <name>]... [-json]")
+	prefix := fs.String("prefix", os.Getenv(configstore.EnvAWSSSMParameterPfx), "parameter prefix (default $AWS_SSM_PARAMETER_PREFIX)")
+	kmsKey := fs.String("kms", os.Getenv(configstore.EnvAWSSSMKMSKeyID), "KMS key ID, ARN, or alias (default $AWS_SSM_KMS_KEY_ID, else the AWS managed key)")
+	fs.Var(tags, "tags", "tags as key=value pairs separated by commas, added to $AWS_SSM_TAGS")
+	policies := fs.String("policies", "", "SSM parameter policies as a JSON array; selects the Advanced tier")
+	var params stringsFlag
+	fs.Var(&params, "param", "additional parameter to create for a custom field (repeatable)")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *prefix == "" {
+		return usagef("-prefix is required")
+	}
+	if *policies != "" && !json.Valid([]byte(*policies)) {
+		return usagef("-policies is not valid JSON")
+	}
+
+	client, err := env.newSSMClient(ctx)
+	if err != nil {
+		return err
+	}
+	opts := []configstore.SSMStoreOption{configstore.WithSSMClient(client)}
+	if *kmsKey != "" {
+		opts = append(opts, configstore.WithKMSKey(*kmsKey))
+	}
+	if len(tags) > 0 {
+		opts = append(opts, configstore.WithTags(tags))
+	}
+	store, err := configstore.NewAWSSSMStore(*prefix, opts...)
+	if err != nil {
+		return err
+	}
+
+	var bootstrapOpts []configstore.BootstrapOption
+	if *policies != "" {
+		bootstrapOpts = append(bootstrapOpts, configstore.WithParameterPolicies(*policies))
+	}
+	if len(params) > 0 {
+		bootstrapOpts = append(bootstrapOpts, configstore.WithExtraParameters(params...))
+	}
+	result, err := store.Bootstrap(ctx, bootstrapOpts...)
+	if *jsonOut {
+		if err != nil {
+			return err
+		}
+		return writeJSON(env.stdout, bootstrapResult{
+			Prefix:  store.ParameterPrefix,
+			Created: nonNil(result.Created),
+			Existed: nonNil(result.Existed),
+		})
+	}
+	if result != nil {
+		for _, name := range result.Created {
+			fmt.Fprintf(env.stdout, "created  %s\n", name)
+		}
+		for _, name := range result.Existed {
+			fmt.Fprintf(env.stdout, "exists   %s\n", name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "%d created, %d already existed; placeholders read as missing until the app saves credentials\n",
+		len(result.Created), len(result.Existed))
+	return nil
+}
+
+// bootstrapResult is the -json output of bootstrap aws-ssm, listing
+// parameter names.
+type bootstrapResult struct {
+	Prefix  string   `json:"prefix"`
+	Created []string `json:"created"`
+	Existed []string `json:"existed"`
+}
+
+// nonNil returns s, or an empty slice for nil, so JSON output has [] and
+// not null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// tagsFlag is a set of tags given as comma-separated key=value pairs.
+type tagsFlag map[string]string
+
+func (f tagsFlag) String() string {
+	var pairs []string
+	for _, k := range slices.Sorted(maps.Keys(f)) {
+		pairs = append(pairs, k+"="+f[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f tagsFlag) Set(v string) error {
+	for _, pair := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid tag %q: want key=value", pair)
+		}
+		f[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return nil
+}
+
+// tagsFromEnv reads the default tags from AWS_SSM_TAGS, a JSON object as
+// configstore.NewFromEnv expects.
+func tagsFromEnv() (tagsFlag, error) {
+	tags := tagsFlag{}
+	if v := os.Getenv(configstore.EnvAWSSSMTags); v != "" {
+		if err := json.Unmarshal([]byte(v), &tags); err != nil {
+			return nil, fmt.Errorf("failed to parse %s as JSON: %w", configstore.EnvAWSSSMTags, err)
+		}
+	}
+	return tags, nil
+}
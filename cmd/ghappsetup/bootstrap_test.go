@@ -0,0 +1,104 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// memSSMClient is an in-memory SSM client that records PutParameter calls.
+type memSSMClient struct {
+	values map[string]string
+	puts   []*ssm.PutParameterInput
+}
+
+func (c *memSSMClient) PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	c.puts = append(c.puts, params)
+	if _, ok := c.values[*params.Name]; ok && !aws.ToBool(params.Overwrite) {
+		return nil, &types.ParameterAlreadyExists{}
+	}
+	c.values[*params.Name] = *params.Value
+	return &ssm.PutParameterOutput{}, nil
+}
+
+func (c *memSSMClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	value, ok := c.values[*params.Name]
+	if !ok {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Name: params.Name, Value: aws.String(value)}}, nil
+}
+
+func (c *memSSMClient) DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error) {
+	for _, name := range params.Names {
+		delete(c.values, name)
+	}
+	return &ssm.DeleteParametersOutput{DeletedParameters: params.Names}, nil
+}
+
+func TestBootstrapSSM(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(configstore.EnvAWSSSMTags, `{"owner":"platform"}`)
+	client := &memSSMClient{values: map[string]string{}}
+	newEnv := func() *testEnv {
+		env := newTestEnv(nil)
+		env.newSSMClient = func(ctx context.Context) (configstore.SSMClient, error) { return client, nil }
+		return env
+	}
+
+	env := newEnv()
+	args := []string{"bootstrap", "aws-ssm", "-prefix", "/app/github", "-kms", "alias/x", "-tags", "env=prod", "-param", "SLACK_TOKEN"}
+	if code := run(ctx, env.cliEnv, args); code != 0 {
+		t.Fatalf("bootstrap exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if !strings.Contains(env.stdout.String(), "created  /app/github/GITHUB_APP_PRIVATE_KEY") ||
+		!strings.Contains(env.stdout.String(), "created  /app/github/SLACK_TOKEN") {
+		t.Errorf("output does not list the created parameters:\n%s", env.stdout)
+	}
+	for _, put := range client.puts {
+		if aws.ToString(put.KeyId) != "alias/x" || len(put.Tags) != 2 {
+			t.Errorf("parameter %s key = %s, tags = %v", *put.Name, aws.ToString(put.KeyId), put.Tags)
+		}
+	}
+
+	// The placeholders do not register the app, and a second run keeps them
+	store, err := configstore.NewAWSSSMStore("/app/github", configstore.WithSSMClient(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := store.Status(ctx); err != nil || status.Registered {
+		t.Errorf("Status() = %+v, %v; want not registered", status, err)
+	}
+	env = newEnv()
+	if code := run(ctx, env.cliEnv, args); code != 0 {
+		t.Fatalf("second bootstrap exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if strings.Contains(env.stdout.String(), "created  ") || !strings.Contains(env.stdout.String(), "0 created, 10 already existed") {
+		t.Errorf("second bootstrap output:\n%s", env.stdout)
+	}
+}
+
+func TestBootstrap_Usage(t *testing.T) {
+	t.Setenv(configstore.EnvAWSSSMParameterPfx, "")
+	for _, args := range [][]string{
+		{"bootstrap"},
+		{"bootstrap", "vault"},
+		{"bootstrap", "aws-ssm"},
+		{"bootstrap", "aws-ssm", "-prefix", "/app", "-tags", "novalue"},
+		{"bootstrap", "aws-ssm", "-prefix", "/app", "-policies", "[{"},
+	} {
+		env := newTestEnv(nil)
+		if code := run(context.Background(), env.cliEnv, args); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/credbundle"
+)
+
+// runExport implements "ghappsetup export".
+func runExport(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "export", "-out <file> (-recipient <key> | -recipients-file <file> | -passphrase-env <var> | -kms-key <key>) [-json]")
+	out := fs.String("out", "", `bundle file to write, or "-" for stdout (required)`)
+	var recipients stringsFlag
+	fs.Var(&recipients, "recipient", "age public key to encrypt to (repeatable)")
+	recipientsFile := fs.String("recipients-file", "", "file of age public keys to encrypt to")
+	passphraseEnv := fs.String("passphrase-env", "", "environment variable holding an age passphrase")
+	kmsKey := fs.String("kms-key", "", "AWS KMS key ID, ARN, or alias to encrypt with")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return usagef("-out is required")
+	}
+	if *out == "-" && *jsonOut {
+		return usagef("-json needs -out to name a file")
+	}
+
+	modes := 0
+	for _, set := range []bool{len(recipients) > 0 || *recipientsFile != "", *passphraseEnv != "", *kmsKey != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return usagef("specify exactly one of -recipient/-recipients-file, -passphrase-env, or -kms-key")
+	}
+
+	var sealer credbundle.Sealer
+	result := exportResult{Path: *out, Encryption: "age"}
+	switch {
+	case *kmsKey != "":
+		client, err := env.newKMSClient(ctx)
+		if err != nil {
+			return err
+		}
+		sealer = credbundle.NewKMSSealer(client, *kmsKey)
+		result.Encryption = "kms"
+	case *passphraseEnv != "":
+		passphrase, err := secretFromEnv(*passphraseEnv)
+		if err != nil {
+			return err
+		}
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return err
+		}
+		sealer = credbundle.NewAgeSealer(recipient)
+		result.Encryption = "age-passphrase"
+	default:
+		parsed, err := parseRecipients(recipients, *recipientsFile)
+		if err != nil {
+			return err
+		}
+		sealer = credbundle.NewAgeSealer(parsed...)
+	}
+
+	store, err := env.newStore()
+	if err != nil {
+		return err
+	}
+	data, err := credbundle.Export(ctx, store, sealer)
+	if err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		_, err = env.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if *jsonOut {
+		return writeJSON(env.stdout, result)
+	}
+	fmt.Fprintf(env.stderr, "exported credentials to %s\n", *out)
+	return nil
+}
+
+// exportResult is the -json output of export. Encryption is "age",
+// "age-passphrase", or "kms".
+type exportResult struct {
+	Path       string `json:"path"`
+	Encryption string `json:"encryption"`
+}
+
+// runImport implements "ghappsetup import".
+func runImport(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "import", "-in <file> [-identity <file> | -passphrase-env <var>] [-force] [-dry-run] [-json]")
+	in := fs.String("in", "", `bundle file to read, or "-" for stdin (required)`)
+	var identities stringsFlag
+	fs.Var(&identities, "identity", "age identity file to decrypt with (repeatable)")
+	passphraseEnv := fs.String("passphrase-env", "", "environment variable holding the age passphrase")
+	force := fs.Bool("force", false, "overwrite credentials already in the store")
+	dryRun := fs.Bool("dry-run", false, "decrypt and describe the bundle without saving it")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return usagef("-in is required")
+	}
+
+	var data []byte
+	var err error
+	if *in == "-" {
+		data, err = io.ReadAll(env.stdin)
+	} else {
+		data, err = os.ReadFile(*in)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	// KMS bundles identify their key; age bundles need an identity
+	var opener credbundle.Opener
+	switch {
+	case credbundle.IsKMSEnvelope(data):
+		client, err := env.newKMSClient(ctx)
+		if err != nil {
+			return err
+		}
+		opener = credbundle.NewKMSSealer(client, "")
+	case *passphraseEnv != "":
+		passphrase, err := secretFromEnv(*passphraseEnv)
+		if err != nil {
+			return err
+		}
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return err
+		}
+		opener = credbundle.NewAgeOpener(identity)
+	case len(identities) > 0:
+		parsed, err := parseIdentities(identities)
+		if err != nil {
+			return err
+		}
+		opener = credbundle.NewAgeOpener(parsed...)
+	default:
+		return usagef("age bundles need -identity or -passphrase-env")
+	}
+
+	if *dryRun {
+		bundle, err := credbundle.Open(ctx, data, opener)
+		if err != nil {
+			return err
+		}
+		if *jsonOut {
+			return writeJSON(env.stdout, newImportResult(bundle, nil))
+		}
+		fmt.Fprintf(env.stdout, "bundle created %s: %s\n", bundle.CreatedAt.Format("2006-01-02T15:04:05Z"), describeBundle(bundle))
+		return nil
+	}
+
+	store, err := env.newStore()
+	if err != nil {
+		return err
+	}
+	if !*force {
+		status, err := store.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read store status: %w", err)
+		}
+		if status.Registered {
+			return fmt.Errorf("store already holds credentials for app %d; use -force to overwrite", status.AppID)
+		}
+	}
+
+	bundle, err := credbundle.Import(ctx, data, opener, store)
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		return writeJSON(env.stdout, newImportResult(bundle, store))
+	}
+	fmt.Fprintf(env.stdout, "imported %s\n", describeBundle(bundle))
+	return nil
+}
+
+// importResult is the -json output of import. Store is omitted for
+// -dry-run, which saves nothing.
+type importResult struct {
+	AppID             int64      `json:"app_id"`
+	AppSlug           string     `json:"app_slug"`
+	CreatedAt         time.Time  `json:"created_at"`
+	CustomFields      []string   `json:"custom_fields"`
+	Installations     int        `json:"installations"`
+	InstallerDisabled bool       `json:"installer_disabled"`
+	Store             *storeInfo `json:"store,omitempty"`
+}
+
+// newImportResult describes b without its secrets; only the names of
+// custom fields are included.
+func newImportResult(b *credbundle.Bundle, store configstore.Store) importResult {
+	r := importResult{
+		AppID:             b.Credentials.AppID,
+		AppSlug:           b.Credentials.AppSlug,
+		CreatedAt:         b.CreatedAt,
+		CustomFields:      slices.Sorted(maps.Keys(b.CustomFields)),
+		Installations:     len(b.Installations),
+		InstallerDisabled: b.InstallerDisabled,
+	}
+	if r.CustomFields == nil {
+		r.CustomFields = []string{}
+	}
+	if store != nil {
+		info := storeInfoOf(store)
+		r.Store = &info
+	}
+	return r
+}
+
+// describeBundle summarizes a bundle without revealing secrets.
+func describeBundle(b *credbundle.Bundle) string {
+	desc := fmt.Sprintf("app %d", b.Credentials.AppID)
+	if b.Credentials.AppSlug != "" {
+		desc += fmt.Sprintf(" (%s)", b.Credentials.AppSlug)
+	}
+	desc += fmt.Sprintf(", %d custom fields, %d installations", len(b.CustomFields), len(b.Installations))
+	if b.InstallerDisabled {
+		desc += ", installer disabled"
+	}
+	return desc
+}
+
+// secretFromEnv reads a secret given by environment variable name, so it
+// stays out of shell history and process listings.
+func secretFromEnv(name string) (string, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is empty", name)
+	}
+	return value, nil
+}
+
+// parseRecipients parses age public keys given as flags and in a file.
+func parseRecipients(keys []string, file string) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, key := range keys {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid -recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		parsed, err := age.ParseRecipients(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipients file %s: %w", file, err)
+		}
+		recipients = append(recipients, parsed...)
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("no age recipients given")
+	}
+	return recipients, nil
+}
+
+// parseIdentities reads age identity files.
+func parseIdentities(files []string) ([]age.Identity, error) {
+	var identities []age.Identity
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := age.ParseIdentities(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity file %s: %w", file, err)
+		}
+		identities = append(identities, parsed...)
+	}
+	return identities, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// commandSpec describes a command for completion. "completion -json"
+// prints the specs, for tools that generate their own.
+type commandSpec struct {
+	Name        string     `json:"name"`
+	Summary     string     `json:"summary"`
+	Subcommands []string   `json:"subcommands,omitempty"`
+	Flags       []flagSpec `json:"flags"`
+}
+
+type flagSpec struct {
+	Name       string `json:"name"`
+	Usage      string `json:"usage"`
+	TakesValue bool   `json:"takes_value"`
+}
+
+// runCompletion implements "ghappsetup completion".
+func runCompletion(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "completion", "bash|zsh|fish | -json")
+	jsonOut := fs.Bool("json", false, "print the commands and flags as JSON instead of a script")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	specs := commandSpecs(ctx)
+	if *jsonOut {
+		if fs.NArg() > 0 {
+			return usagef("-json takes no shell")
+		}
+		return writeJSON(env.stdout, specs)
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a shell: bash, zsh, or fish")
+	}
+	tmpl, ok := completionTemplates[fs.Arg(0)]
+	if !ok {
+		return usagef("unknown shell %q (expected bash, zsh, or fish)", fs.Arg(0))
+	}
+	return tmpl.Execute(env.stdout, specs)
+}
+
+// commandSpecs collects each command's flags by running it with -h while
+// env.onHelp records the flag set, so completions cannot drift from the
+// flags the commands define.
+func commandSpecs(ctx context.Context) []commandSpec {
+	specs := make([]commandSpec, 0, len(commands))
+	for _, cmd := range commands {
+		spec := commandSpec{Name: cmd.name, Summary: cmd.summary, Subcommands: cmd.subcommands, Flags: []flagSpec{}}
+		env := &cliEnv{stdin: strings.NewReader(""), stdout: io.Discard, stderr: io.Discard}
+		env.onHelp = func(fs *flag.FlagSet) {
+			fs.VisitAll(func(f *flag.Flag) {
+				if slices.ContainsFunc(spec.Flags, func(s flagSpec) bool { return s.Name == f.Name }) {
+					return
+				}
+				_, usage := flag.UnquoteUsage(f)
+				b, isBool := f.Value.(interface{ IsBoolFlag() bool })
+				spec.Flags = append(spec.Flags, flagSpec{Name: f.Name, Usage: usage, TakesValue: !isBool || !b.IsBoolFlag()})
+			})
+		}
+		cmd.run(ctx, env, []string{"-h"})
+		if cmd.name != "completion" {
+			for _, sub := range cmd.subcommands {
+				cmd.run(ctx, env, []string{sub, "-h"})
+			}
+		}
+		slices.SortFunc(spec.Flags, func(a, b flagSpec) int { return strings.Compare(a.Name, b.Name) })
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+var completionFuncs = template.FuncMap{
+	"join": strings.Join,
+	"names": func(specs []commandSpec) string {
+		var names []string
+		for _, s := range specs {
+			names = append(names, s.Name)
+		}
+		return strings.Join(names, " ")
+	},
+	"flags": func(flags []flagSpec) string {
+		var names []string
+		for _, f := range flags {
+			names = append(names, "-"+f.Name)
+		}
+		return strings.Join(names, " ")
+	},
+	// sq quotes a string for bash and zsh; fq for fish.
+	"sq": func(s string) string { return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'" },
+	"fq": func(s string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+	},
+}
+
+var completionTemplates = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(completionFuncs).Parse(`# bash completion for ghappsetup
+# Load with: source <(ghappsetup completion bash)
+
+_ghappsetup() {
+	local cur=${COMP_WORDS[COMP_CWORD]}
+	COMPREPLY=()
+	if [[ $COMP_CWORD -eq 1 ]]; then
+		COMPREPLY=($(compgen -W "{{names .}}" -- "$cur"))
+		return
+	fi
+	case ${COMP_WORDS[1]} in
+{{- range .}}
+	{{.Name}})
+{{- if .Subcommands}}
+		if [[ $COMP_CWORD -eq 2 ]]; then
+			COMPREPLY=($(compgen -W "{{join .Subcommands " "}}" -- "$cur"))
+			return
+		fi
+{{- end}}
+		if [[ $cur == -* ]]; then
+			COMPREPLY=($(compgen -W "{{flags .Flags}}" -- "$cur"))
+		fi
+		;;
+{{- end}}
+	esac
+}
+
+complete -o default -F _ghappsetup ghappsetup
+`)),
+
+	"zsh": template.Must(template.New("zsh").Funcs(completionFuncs).Parse(`#compdef ghappsetup
+# zsh completion for ghappsetup
+# Load with: source <(ghappsetup completion zsh)
+
+_ghappsetup() {
+	local -a items
+	if (( CURRENT == 2 )); then
+		items=(
+{{- range .}}
+			{{sq (print .Name ":" .Summary)}}
+{{- end}}
+		)
+		_describe command items
+		return
+	fi
+	case $words[2] in
+{{- range .}}
+	{{.Name}})
+{{- if .Subcommands}}
+		if (( CURRENT == 3 )); then
+			compadd -- {{join .Subcommands " "}}
+			return
+		fi
+{{- end}}
+		if [[ $PREFIX == -* ]]; then
+			items=(
+{{- range .Flags}}
+				{{sq (print "-" .Name ":" .Usage)}}
+{{- end}}
+			)
+			_describe flag items
+		else
+			_files
+		fi
+		;;
+{{- end}}
+	esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+	_ghappsetup "$@"
+else
+	compdef _ghappsetup ghappsetup
+fi
+`)),
+
+	"fish": template.Must(template.New("fish").Funcs(completionFuncs).Parse(`# fish completion for ghappsetup
+# Load with: ghappsetup completion fish | source
+
+complete -c ghappsetup -f
+{{- range .}}
+complete -c ghappsetup -n __fish_use_subcommand -a {{.Name}} -d {{fq .Summary}}
+{{- end}}
+{{- range $cmd := .}}
+{{- range .Subcommands}}
+complete -c ghappsetup -n '__fish_seen_subcommand_from {{$cmd.Name}}; and not __fish_seen_subcommand_from {{join $cmd.Subcommands " "}}' -a {{.}}
+{{- end}}
+{{- range .Flags}}
+complete -c ghappsetup -n '__fish_seen_subcommand_from {{$cmd.Name}}' -o {{.Name}} -d {{fq .Usage}}{{if .TakesValue}} -r -F{{end}}
+{{- end}}
+{{- end}}
+`)),
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompletion_Specs(t *testing.T) {
+	env := newTestEnv(nil)
+	if code := run(context.Background(), env.cliEnv, []string{"completion", "-json"}); code != 0 {
+		t.Fatalf("completion -json exit = %d\nstderr: %s", code, env.stderr)
+	}
+	var specs []commandSpec
+	if err := json.Unmarshal(env.stdout.Bytes(), &specs); err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != len(commands) {
+		t.Fatalf("got %d specs, want one per command (%d)", len(specs), len(commands))
+	}
+	for _, spec := range specs {
+		if f, ok := findFlag(spec, "json"); !ok || f.TakesValue {
+			t.Errorf("%s has no -json flag: %+v", spec.Name, spec.Flags)
+		}
+		if spec.Name == "manifest" {
+			if f, ok := findFlag(spec, "template"); !ok || !f.TakesValue {
+				t.Errorf("manifest flags do not include the render flags: %+v", spec.Flags)
+			}
+		}
+	}
+}
+
+func findFlag(spec commandSpec, name string) (flagSpec, bool) {
+	i := slices.IndexFunc(spec.Flags, func(f flagSpec) bool { return f.Name == name })
+	if i < 0 {
+		return flagSpec{}, false
+	}
+	return spec.Flags[i], true
+}
+
+func TestCompletion_Scripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			env := newTestEnv(nil)
+			if code := run(context.Background(), env.cliEnv, []string{"completion", shell}); code != 0 {
+				t.Fatalf("completion %s exit = %d\nstderr: %s", shell, code, env.stderr)
+			}
+			script := env.stdout.String()
+			for _, want := range []string{"rotate-key", "render", "json"} {
+				if !strings.Contains(script, want) {
+					t.Errorf("script does not mention %s", want)
+				}
+			}
+
+			// Check the syntax if the shell is installed
+			path, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s not installed", shell)
+			}
+			file := filepath.Join(t.TempDir(), "ghappsetup."+shell)
+			if err := os.WriteFile(file, []byte(script), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(path, "-n", file).CombinedOutput(); err != nil {
+				t.Errorf("%s -n: %v\n%s", shell, err, out)
+			}
+		})
+	}
+
+	env := newTestEnv(nil)
+	if code := run(context.Background(), env.cliEnv, []string{"completion", "tcsh"}); code != 2 {
+		t.Errorf("completion tcsh exit = %d, want 2", code)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// runDelete implements "ghappsetup delete".
+//
+// GitHub has no API to delete an app registration, so -also-github-app
+// removes every installation of the app, which revokes its access, and
+// prints the settings page where the app itself is deleted.
+func runDelete(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "delete", "[-store <mode> [-prefix <prefix> | -dir <path>]] [-also-github-app] [-yes] [-json]")
+	mode := fs.String("store", "", "storage mode: envfile, files, or aws-ssm (default from the environment)")
+	prefix := fs.String("prefix", "", "SSM parameter prefix for -store aws-ssm")
+	dir := fs.String("dir", "", "file or directory for -store envfile or files (default ./.env)")
+	alsoApp := fs.Bool("also-github-app", false, "also uninstall the app everywhere and print where to delete it on GitHub")
+	githubURL := fs.String("github-url", os.Getenv(installer.EnvGitHubURL), "GitHub web URL (default $GITHUB_URL, then the app's HTML URL)")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *mode == "" && (*prefix != "" || *dir != "") {
+		return usagef("-prefix and -dir require -store")
+	}
+
+	store, err := deleteStore(ctx, env, storageConfig{Mode: *mode, Prefix: *prefix, Dir: *dir})
+	if err != nil {
+		return err
+	}
+	deleter, ok := store.(configstore.CredentialDeleter)
+	if !ok {
+		return errors.New("store cannot delete credentials")
+	}
+	status, err := store.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+
+	var creds *configstore.AppCredentials
+	if *alsoApp {
+		loader, ok := store.(configstore.CredentialLoader)
+		if !ok {
+			return errors.New("store cannot load credentials")
+		}
+		if creds, err = loader.Load(ctx); err != nil {
+			return fmt.Errorf("failed to load credentials, which -also-github-app needs: %w", err)
+		}
+	}
+
+	if !*yes {
+		question := fmt.Sprintf("Delete the credentials in %s?", describeStore(store))
+		if status.Registered {
+			question = fmt.Sprintf("Delete the credentials of %s from %s?", appName(status), describeStore(store))
+		}
+		if *alsoApp {
+			question += " The app is also uninstalled from every account."
+		}
+		ok, err := confirm(env, question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted")
+		}
+	}
+
+	result := deleteResult{AppID: status.AppID, AppSlug: status.AppSlug, Store: storeInfoOf(store), Uninstalled: []int64{}}
+	if *alsoApp {
+		// Uninstall before deleting the credentials, since it needs them
+		result.AppID = creds.AppID
+		if result.SettingsURL, result.Uninstalled, err = uninstallApp(ctx, *githubURL, creds); err != nil {
+			return err
+		}
+	}
+	if err := deleter.Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete credentials: %w", err)
+	}
+
+	if *jsonOut {
+		return writeJSON(env.stdout, result)
+	}
+	fmt.Fprintf(env.stdout, "deleted the credentials from %s\n", describeStore(store))
+	if *alsoApp {
+		fmt.Fprintf(env.stdout, "uninstalled app %d from %d account(s)\n", result.AppID, len(result.Uninstalled))
+		fmt.Fprintf(env.stdout, "GitHub has no API to delete the app itself; delete it at %s\n", result.SettingsURL)
+	}
+	return nil
+}
+
+// deleteResult is the -json output of delete. Uninstalled lists the
+// installation IDs removed by -also-github-app, and SettingsURL is the page
+// where the app registration is deleted.
+type deleteResult struct {
+	AppID       int64     `json:"app_id"`
+	AppSlug     string    `json:"app_slug,omitempty"`
+	Store       storeInfo `json:"store"`
+	Uninstalled []int64   `json:"uninstalled"`
+	SettingsURL string    `json:"settings_url,omitempty"`
+}
+
+// deleteStore returns the store named by the -store flags, or the
+// configured store if none was given.
+func deleteStore(ctx context.Context, env *cliEnv, storage storageConfig) (configstore.Store, error) {
+	if storage.Mode == "" {
+		return env.newStore()
+	}
+	if storage.Mode == configstore.StorageModeAWSSSM && storage.Prefix == "" {
+		return nil, usagef("-prefix is required for -store aws-ssm")
+	}
+	cfg := &setupConfig{Storage: storage}
+	return cfg.store(ctx, env)
+}
+
+// appName names the app in status for messages.
+func appName(status *configstore.InstallerStatus) string {
+	name := fmt.Sprintf("app %d", status.AppID)
+	if status.AppSlug != "" {
+		name += fmt.Sprintf(" (%s)", status.AppSlug)
+	}
+	return name
+}
+
+// uninstallApp deletes every installation of the app and returns the URL
+// of the settings page where the app can be deleted, along with the IDs
+// of the removed installations.
+func uninstallApp(ctx context.Context, githubURL string, creds *configstore.AppCredentials) (string, []int64, error) {
+	key, err := ghauth.ParsePrivateKey([]byte(creds.PrivateKey))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse the stored private key: %w", err)
+	}
+	opts := append(githubOptions(githubURL, creds), ghauth.WithCredentials(ghauth.StaticCredentials(creds.AppID, key)))
+	src := ghauth.NewTokenSource(opts...)
+	client, err := ghauth.NewClientFromSource(ctx, src, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get app %d: %w", creds.AppID, err)
+	}
+
+	var ids []int64
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Apps.ListInstallations(ctx, opt)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to list installations: %w", err)
+		}
+		for _, inst := range page {
+			ids = append(ids, inst.GetID())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	uninstalled := []int64{}
+	for _, id := range ids {
+		if _, err := client.Apps.DeleteInstallation(ctx, id); err != nil {
+			return "", nil, fmt.Errorf("failed to uninstall installation %d (%d of %d uninstalled): %w",
+				id, len(uninstalled), len(ids), err)
+		}
+		uninstalled = append(uninstalled, id)
+	}
+
+	webURL := strings.TrimSuffix(githubURL, "/")
+	if webURL == "" {
+		u, err := url.Parse(app.GetHTMLURL())
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid app HTML URL %q: %w", app.GetHTMLURL(), err)
+		}
+		webURL = u.Scheme + "://" + u.Host
+	}
+	settings := fmt.Sprintf("%s/settings/apps/%s/advanced", webURL, app.GetSlug())
+	if app.GetOwner().GetType() == "Organization" {
+		settings = fmt.Sprintf("%s/organizations/%s/settings/apps/%s/advanced", webURL, app.GetOwner().GetLogin(), app.GetSlug())
+	}
+	return settings, uninstalled, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/githubtest"
+)
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	store := registeredStore(t)
+
+	// Declining leaves the credentials in place
+	env := newTestEnv(store)
+	env.stdin = strings.NewReader("n\n")
+	if code := run(ctx, env.cliEnv, []string{"delete"}); code != 1 {
+		t.Fatalf("delete exit = %d, want 1", code)
+	}
+	if !strings.Contains(env.stderr.String(), "credentials of app 123 (my-app)") {
+		t.Errorf("confirmation does not name the app: %s", env.stderr)
+	}
+	if status, _ := store.Status(ctx); !status.Registered {
+		t.Fatal("credentials deleted without confirmation")
+	}
+
+	env = newTestEnv(store)
+	env.stdin = strings.NewReader("y\n")
+	if code := run(ctx, env.cliEnv, []string{"delete"}); code != 0 {
+		t.Fatalf("delete exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if status, _ := store.Status(ctx); status.Registered || status.AppID != 0 {
+		t.Errorf("status after delete = %+v", status)
+	}
+}
+
+func TestDelete_SSMStore(t *testing.T) {
+	client := &memSSMClient{values: map[string]string{
+		"/preview/pr-42/GITHUB_APP_ID":            "123",
+		"/preview/pr-42/GITHUB_APP_SLUG":          "my-app",
+		"/preview/pr-42/GITHUB_CLIENT_ID":         "Iv1.abc",
+		"/preview/pr-42/GITHUB_APP_INSTALLATIONS": "[]",
+		"/preview/pr-43/GITHUB_APP_ID":            "456",
+	}}
+	env := newTestEnv(nil)
+	env.newSSMClient = func(context.Context) (configstore.SSMClient, error) { return client, nil }
+	args := []string{"delete", "-store", "aws-ssm", "-prefix", "/preview/pr-42", "-yes", "-json"}
+	if code := run(context.Background(), env.cliEnv, args); code != 0 {
+		t.Fatalf("delete exit = %d\nstderr: %s", code, env.stderr)
+	}
+	want := map[string]string{"/preview/pr-43/GITHUB_APP_ID": "456"}
+	if !reflect.DeepEqual(client.values, want) {
+		t.Errorf("parameters left = %v, want %v", client.values, want)
+	}
+
+	var result deleteResult
+	if err := json.Unmarshal(env.stdout.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Store != (storeInfo{Mode: "aws-ssm", Location: "/preview/pr-42/"}) || len(result.Uninstalled) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	env = newTestEnv(nil)
+	if code := run(context.Background(), env.cliEnv, []string{"delete", "-store", "aws-ssm", "-yes"}); code != 2 {
+		t.Errorf("delete without -prefix exit = %d, want 2", code)
+	}
+}
+
+func TestDelete_AlsoGitHubApp(t *testing.T) {
+	ctx := context.Background()
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	creds.AppID = 123
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	srv := githubtest.NewServer(t)
+	srv.Handle(http.MethodGet, "/api/v3/app", http.StatusOK,
+		`{"id":123,"slug":"my-app","owner":{"login":"octo-org","type":"Organization"}}`)
+	srv.Handle(http.MethodGet, "/api/v3/app/installations", http.StatusOK, `[{"id":7},{"id":8}]`)
+	srv.Handle(http.MethodDelete, "/api/v3/app/installations/*", http.StatusNoContent, "")
+
+	env := newTestEnv(store)
+	args := []string{"delete", "-also-github-app", "-github-url", srv.URL, "-yes", "-json"}
+	if code := run(ctx, env.cliEnv, args); code != 0 {
+		t.Fatalf("delete exit = %d\nstderr: %s", code, env.stderr)
+	}
+	srv.AssertCalled(t, http.MethodDelete, "/api/v3/app/installations/7")
+	srv.AssertCalled(t, http.MethodDelete, "/api/v3/app/installations/8")
+
+	var result deleteResult
+	if err := json.Unmarshal(env.stdout.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Uninstalled, []int64{7, 8}) {
+		t.Errorf("uninstalled = %v, want [7 8]", result.Uninstalled)
+	}
+	if want := srv.URL + "/organizations/octo-org/settings/apps/my-app/advanced"; result.SettingsURL != want {
+		t.Errorf("settings_url = %q, want %q", result.SettingsURL, want)
+	}
+	if status, _ := store.Status(ctx); status.Registered {
+		t.Error("credentials still stored")
+	}
+}
+
+func TestDelete_AlsoGitHubApp_FailureKeepsCredentials(t *testing.T) {
+	ctx := context.Background()
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	srv := githubtest.NewServer(t) // every request gets 404
+
+	env := newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"delete", "-also-github-app", "-github-url", srv.URL, "-yes"}); code != 1 {
+		t.Fatalf("delete exit = %d, want 1", code)
+	}
+	if status, _ := store.Status(ctx); !status.Registered {
+		t.Error("credentials deleted although the app could not be uninstalled")
+	}
+}
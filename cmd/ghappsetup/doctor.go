@@ -0,0 +1,338 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// minKeyBits is the smallest RSA key size doctor accepts without a
+// warning; GitHub generates 2048-bit keys.
+const minKeyBits = 2048
+
+// orgPattern matches GitHub organization logins.
+var orgPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,37}[A-Za-z0-9])?$`)
+
+// findingStatus is the outcome of a doctor check.
+type findingStatus string
+
+const (
+	statusOK   findingStatus = "ok"
+	statusWarn findingStatus = "warn"
+	statusFail findingStatus = "FAIL"
+)
+
+// finding is the result of one doctor check, with a hint on how to fix
+// it if it did not pass.
+type finding struct {
+	status findingStatus
+	check  string
+	msg    string
+	hint   string
+}
+
+// doctor collects findings.
+type doctor struct {
+	findings []finding
+}
+
+func (d *doctor) ok(check, format string, args ...any) {
+	d.findings = append(d.findings, finding{status: statusOK, check: check, msg: fmt.Sprintf(format, args...)})
+}
+
+func (d *doctor) warn(check, msg, hint string) {
+	d.findings = append(d.findings, finding{status: statusWarn, check: check, msg: msg, hint: hint})
+}
+
+func (d *doctor) fail(check, msg, hint string) {
+	d.findings = append(d.findings, finding{status: statusFail, check: check, msg: msg, hint: hint})
+}
+
+func (d *doctor) failures() int {
+	n := 0
+	for _, f := range d.findings {
+		if f.status == statusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// doctorResult is the -json output of doctor. Status is "ok", "warn",
+// or "fail".
+type doctorResult struct {
+	OK       bool            `json:"ok"`
+	Findings []doctorFinding `json:"findings"`
+}
+
+type doctorFinding struct {
+	Status  string `json:"status"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+func (d *doctor) result() doctorResult {
+	r := doctorResult{OK: d.failures() == 0, Findings: make([]doctorFinding, 0, len(d.findings))}
+	for _, f := range d.findings {
+		r.Findings = append(r.Findings, doctorFinding{
+			Status:  strings.ToLower(string(f.status)),
+			Check:   f.check,
+			Message: f.msg,
+			Hint:    f.hint,
+		})
+	}
+	return r
+}
+
+func (d *doctor) print(w io.Writer) {
+	for _, f := range d.findings {
+		fmt.Fprintf(w, "%-5s %-13s %s\n", f.status, f.check, f.msg)
+		if f.hint != "" {
+			fmt.Fprintf(w, "%-19s hint: %s\n", "", f.hint)
+		}
+	}
+}
+
+// runDoctor implements "ghappsetup doctor".
+//
+// The checks only read: the store is probed with Status and Load, and
+// GitHub with the same GET /app call rotate-key uses. Write permissions
+// are not tested, since testing them would change the store.
+func runDoctor(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "doctor", "[-github-url <url>] [-manifest <file>] [-offline] [-json]")
+	githubURL := fs.String("github-url", os.Getenv(installer.EnvGitHubURL), "GitHub web URL (default $GITHUB_URL, then the app's HTML URL)")
+	manifestFile := fs.String("manifest", "", "manifest JSON file to validate")
+	offline := fs.Bool("offline", false, "skip checks that call GitHub")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	d := &doctor{}
+	d.checkConfig(*githubURL)
+	if *manifestFile != "" {
+		d.checkManifest(*manifestFile)
+	}
+	if creds := d.checkStore(ctx, env); creds != nil {
+		d.checkSecrets(creds)
+		d.checkKey(ctx, creds, *githubURL, *offline)
+	}
+	if *jsonOut {
+		if err := writeJSON(env.stdout, d.result()); err != nil {
+			return err
+		}
+	} else {
+		d.print(env.stdout)
+	}
+
+	if n := d.failures(); n > 0 {
+		return fmt.Errorf("%d of %d checks failed", n, len(d.findings))
+	}
+	return nil
+}
+
+// checkConfig validates the installer's GITHUB_URL and GITHUB_ORG
+// settings.
+func (d *doctor) checkConfig(githubURL string) {
+	if githubURL != "" {
+		if _, _, err := ghauth.APIURLs(githubURL); err != nil {
+			d.fail("github-url", err.Error(),
+				"set GITHUB_URL to the GitHub web URL, e.g. https://github.example.com")
+		} else {
+			d.ok("github-url", "%s", githubURL)
+		}
+	}
+	if org := os.Getenv(installer.EnvGitHubOrg); org != "" {
+		if !orgPattern.MatchString(org) {
+			d.fail("github-org", fmt.Sprintf("%s=%q is not a valid organization login", installer.EnvGitHubOrg, org),
+				"use the organization's login as it appears in its URL, not its display name")
+		} else {
+			d.ok("github-org", "apps are created in organization %s", org)
+		}
+	}
+}
+
+// checkManifest validates a manifest file against the fields GitHub
+// accepts and requires.
+func (d *doctor) checkManifest(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.fail("manifest", err.Error(), "")
+		return
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		d.fail("manifest", fmt.Sprintf("%s is not valid manifest JSON: %v", path, err), "")
+		return
+	}
+
+	m, problems := validateManifest(doc)
+	failed := false
+	for _, p := range problems {
+		if p.fatal {
+			d.fail("manifest", p.msg, p.hint)
+			failed = true
+		} else {
+			d.warn("manifest", p.msg, p.hint)
+		}
+	}
+	if !failed {
+		d.ok("manifest", "%s is valid (%d permissions, %d events)", path, len(m.DefaultPerms), len(m.DefaultEvents))
+	}
+}
+
+// checkStore opens the configured store and loads the credentials. It
+// returns nil if a check failed.
+func (d *doctor) checkStore(ctx context.Context, env *cliEnv) *configstore.AppCredentials {
+	store, err := env.newStore()
+	if err != nil {
+		d.fail("store", err.Error(),
+			"set STORAGE_MODE to envfile, files, or aws-ssm, with STORAGE_DIR or AWS_SSM_PARAMETER_PREFIX")
+		return nil
+	}
+	name := describeStore(store)
+
+	status, err := store.Status(ctx)
+	if err != nil {
+		d.fail("store", fmt.Sprintf("cannot read %s: %v", name, err), storeHint(store, err))
+		return nil
+	}
+	d.ok("store", "%s is reachable", name)
+	if !status.Registered {
+		d.fail("registration", "the store does not hold a complete set of credentials",
+			"run the installer to register the app, or restore a bundle with ghappsetup import")
+		return nil
+	}
+
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		d.warn("registration", "the store cannot load credentials; skipping credential checks", "")
+		return nil
+	}
+	creds, err := loader.Load(ctx)
+	if err != nil {
+		d.fail("registration", fmt.Sprintf("failed to load credentials: %v", err), storeHint(store, err))
+		return nil
+	}
+	if creds.AppSlug != "" {
+		d.ok("registration", "app %d (%s) is registered", creds.AppID, creds.AppSlug)
+	} else {
+		d.ok("registration", "app %d is registered", creds.AppID)
+	}
+	return creds
+}
+
+// checkSecrets checks the webhook secret for mistakes that break
+// signature checks.
+func (d *doctor) checkSecrets(creds *configstore.AppCredentials) {
+	switch envSecret := os.Getenv(configstore.EnvGitHubWebhookSecret); {
+	case creds.WebhookSecret != strings.TrimSpace(creds.WebhookSecret):
+		d.warn("webhook", "the stored webhook secret has leading or trailing whitespace",
+			"GitHub signs with the exact secret, so remove the whitespace unless the app is configured with it")
+	case envSecret != "" && envSecret != creds.WebhookSecret:
+		d.warn("webhook", fmt.Sprintf("%s in the environment differs from the stored secret", configstore.EnvGitHubWebhookSecret),
+			"webhook.EnvSecret reads the environment; unset the variable or reload the stored credentials")
+	default:
+		d.ok("webhook", "webhook secret is present")
+	}
+}
+
+// checkKey checks that the private key parses and, unless offline, that
+// GitHub accepts JWTs signed with it.
+func (d *doctor) checkKey(ctx context.Context, creds *configstore.AppCredentials, githubURL string, offline bool) {
+	key, err := ghauth.ParsePrivateKey([]byte(creds.PrivateKey))
+	if err != nil {
+		d.fail("private-key", fmt.Sprintf("the stored key does not parse: %v", err),
+			"store the PEM file downloaded from the app's settings, or replace it with ghappsetup rotate-key")
+		return
+	}
+	fingerprint, err := ghauth.Fingerprint(key)
+	if err != nil {
+		d.fail("private-key", err.Error(), "")
+		return
+	}
+	if bits := key.N.BitLen(); bits < minKeyBits {
+		d.warn("private-key", fmt.Sprintf("%s is a %d-bit key", fingerprint, bits),
+			"generate a new key in the app's settings and run ghappsetup rotate-key")
+	} else {
+		d.ok("private-key", "%s parses", fingerprint)
+	}
+
+	if offline {
+		return
+	}
+	err = ghauth.VerifyAppKey(ctx, creds.AppID, key, githubOptions(githubURL, creds)...)
+	switch {
+	case errors.Is(err, ghauth.ErrKeyMismatch):
+		d.fail("github", fmt.Sprintf("GitHub rejected the app JWT for app %d", creds.AppID),
+			"the key may have been deleted in the app's settings or belong to another app, or the system clock is off; "+
+				"generate a new key and run ghappsetup rotate-key")
+	case err != nil:
+		d.fail("github", err.Error(),
+			"check network access to GitHub, pass -github-url for GitHub Enterprise Server, or use -offline")
+	default:
+		d.ok("github", "GitHub accepts JWTs for app %d", creds.AppID)
+	}
+}
+
+// describeStore names the store for findings.
+func describeStore(store configstore.Store) string {
+	switch s := store.(type) {
+	case *configstore.LocalEnvFileStore:
+		return fmt.Sprintf("envfile store %s", s.FilePath)
+	case *configstore.LocalFileStore:
+		return fmt.Sprintf("files store %s", s.Dir)
+	case *configstore.AWSSSMStore:
+		return fmt.Sprintf("aws-ssm store %s", s.ParameterPrefix)
+	default:
+		return fmt.Sprintf("%T store", store)
+	}
+}
+
+// storeHint suggests a fix for an error reading the store, naming the
+// IAM permission to grant for SSM access errors.
+func storeHint(store configstore.Store, err error) string {
+	switch s := store.(type) {
+	case *configstore.AWSSSMStore:
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) {
+			return "check the AWS credentials and region, e.g. AWS_PROFILE and AWS_REGION"
+		}
+		code := apiErr.ErrorCode()
+		switch {
+		case strings.HasPrefix(code, "KMS") || code == "InvalidKeyId" ||
+			strings.Contains(strings.ToLower(apiErr.ErrorMessage()), "kms"):
+			return "grant kms:Decrypt on the key that encrypts the parameters"
+		case code == "AccessDeniedException":
+			return fmt.Sprintf("grant ssm:GetParameter on arn:aws:ssm:<region>:<account>:parameter%s*", s.ParameterPrefix)
+		case code == "UnrecognizedClientException" || code == "ExpiredTokenException" ||
+			code == "InvalidSignatureException":
+			return "refresh the AWS credentials"
+		}
+	case *configstore.LocalEnvFileStore:
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Sprintf("make %s readable by this user", s.FilePath)
+		}
+	case *configstore.LocalFileStore:
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Sprintf("make the files in %s readable by this user", s.Dir)
+		}
+	}
+	return ""
+}
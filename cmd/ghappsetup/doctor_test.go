@@ -0,0 +1,124 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/githubtest"
+)
+
+func TestDoctor(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(configstore.EnvGitHubWebhookSecret, "")
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	creds.AppID = 123
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(manifest, []byte(`{"url":"https://example.com","default_permissions":{"issues":"write"},"default_events":["issues"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	srv := appServer(t, githubtest.PrivateKey(t))
+
+	env := newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"doctor", "-github-url", srv.URL, "-manifest", manifest}); code != 0 {
+		t.Fatalf("doctor exit = %d\nstdout: %s\nstderr: %s", code, env.stdout, env.stderr)
+	}
+	for _, check := range []string{"github-url", "manifest", "store", "registration", "webhook", "private-key", "github"} {
+		if !strings.Contains(env.stdout.String(), "ok    "+check+" ") {
+			t.Errorf("output has no passing %s check:\n%s", check, env.stdout)
+		}
+	}
+}
+
+func TestDoctor_Failures(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(configstore.EnvGitHubWebhookSecret, "")
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registered := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	creds.AppID = 123
+	if err := registered.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	badManifest := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(badManifest, []byte(`{"default_permissions":{"issues":"readwrite"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deniedSSM, err := configstore.NewAWSSSMStore("/app/github", configstore.WithSSMClient(deniedSSMClient{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		store configstore.Store
+		args  []string
+		want  []string
+	}{
+		{
+			name:  "key rejected by GitHub",
+			store: registered,
+			args:  []string{"-github-url", appServer(t, otherKey).URL},
+			want:  []string{"FAIL  github", "rotate-key"},
+		},
+		{
+			name:  "unregistered store",
+			store: configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env")),
+			want:  []string{"FAIL  registration", "ghappsetup import"},
+		},
+		{
+			name:  "invalid manifest",
+			store: registered,
+			args:  []string{"-offline", "-manifest", badManifest},
+			want:  []string{"url is required", `permission issues has invalid level "readwrite"`},
+		},
+		{
+			name:  "SSM access denied",
+			store: deniedSSM,
+			want:  []string{"FAIL  store", "grant ssm:GetParameter on arn:aws:ssm:<region>:<account>:parameter/app/github/*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(tt.store)
+			if code := run(ctx, env.cliEnv, append([]string{"doctor"}, tt.args...)); code != 1 {
+				t.Fatalf("doctor exit = %d, want 1\nstdout: %s\nstderr: %s", code, env.stdout, env.stderr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(env.stdout.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, env.stdout)
+				}
+			}
+		})
+	}
+}
+
+// deniedSSMClient fails every call the way SSM does without IAM access.
+type deniedSSMClient struct{}
+
+func (deniedSSMClient) PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform: ssm:PutParameter"}
+}
+
+func (deniedSSMClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform: ssm:GetParameter"}
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+var storageModes = []string{configstore.StorageModeEnvFile, configstore.StorageModeFiles, configstore.StorageModeAWSSSM}
+
+// runInit implements "ghappsetup init", a wizard that asks for the store,
+// GitHub instance, and manifest, writes them to a config file, and then
+// optionally runs "ghappsetup setup" with it.
+func runInit(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "init", "[-config <file>] [-addr <addr>] [-no-setup] [-json]")
+	configFile := fs.String("config", defaultConfigFile, "config file to write")
+	addr := fs.String("addr", defaultSetupAddr, "listen address for the installer")
+	noSetup := fs.Bool("no-setup", false, "only write the config file")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	p := &prompter{in: bufio.NewReader(env.stdin), out: env.stderr}
+	if _, err := os.Stat(*configFile); err == nil {
+		ok, err := p.yesNo(fmt.Sprintf("%s exists. Overwrite it?", *configFile), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted")
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	cfg := &setupConfig{}
+	steps := []func(*prompter, *setupConfig) error{askStorage, askGitHub, askManifest}
+	for _, step := range steps {
+		if err := step(p, cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := writeSetupConfig(*configFile, cfg); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	result := initResult{ConfigFile: *configFile}
+	if !*jsonOut {
+		fmt.Fprintf(env.stdout, "wrote %s\n", *configFile)
+	}
+
+	var start bool
+	var err error
+	if !*noSetup {
+		if start, err = p.yesNo("Create the app on GitHub now?", true); err != nil {
+			return err
+		}
+	}
+	if start {
+		if result.App, err = setupApp(ctx, env, cfg, *addr); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case *jsonOut:
+		return writeJSON(env.stdout, result)
+	case start:
+		result.App.print(env.stdout)
+	case !*noSetup:
+		fmt.Fprintf(env.stdout, "run \"ghappsetup setup -config %s\" to create the app\n", *configFile)
+	}
+	return nil
+}
+
+// initResult is the -json output of init. App is set if init ran setup.
+type initResult struct {
+	ConfigFile string       `json:"config_file"`
+	App        *setupResult `json:"app,omitempty"`
+}
+
+func askStorage(p *prompter, cfg *setupConfig) error {
+	p.section("Credential store")
+	mode, err := p.choose("Backend", storageModes, configstore.StorageModeEnvFile)
+	if err != nil {
+		return err
+	}
+	cfg.Storage.Mode = mode
+	switch mode {
+	case configstore.StorageModeEnvFile:
+		cfg.Storage.Dir, err = p.ask(".env file", "./.env", nil)
+	case configstore.StorageModeFiles:
+		cfg.Storage.Dir, err = p.ask("Directory", "./.env", nil)
+	case configstore.StorageModeAWSSSM:
+		cfg.Storage.Prefix, err = p.ask("Parameter prefix", "", func(v string) error {
+			if !strings.HasPrefix(v, "/") {
+				return errors.New("the prefix must start with /")
+			}
+			return nil
+		})
+		if err == nil {
+			cfg.Storage.KMSKeyID, err = p.ask("KMS key (blank for the AWS managed key)", "", nil)
+		}
+	}
+	return err
+}
+
+func askGitHub(p *prompter, cfg *setupConfig) error {
+	p.section("GitHub")
+	url, err := p.ask("GitHub URL", "https://github.com", func(v string) error {
+		if !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "http://") {
+			return errors.New("enter a URL such as https://github.example.com")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if url = strings.TrimRight(url, "/"); url != "https://github.com" {
+		cfg.GitHub.URL = url
+	}
+	cfg.GitHub.Org, err = p.ask("Organization (blank for your user account)", "", func(v string) error {
+		if v != "" && !orgPattern.MatchString(v) {
+			return errors.New("not a valid organization login")
+		}
+		return nil
+	})
+	return err
+}
+
+func askManifest(p *prompter, cfg *setupConfig) error {
+	p.section("App manifest")
+	for {
+		doc, err := askManifestFields(p, cfg)
+		if err != nil {
+			return err
+		}
+		_, problems := validateManifest(doc)
+		fatal := slices.ContainsFunc(problems, func(mp manifestProblem) bool { return mp.fatal })
+		for _, mp := range problems {
+			p.problem(mp)
+		}
+		if !fatal {
+			cfg.Manifest = doc
+			return nil
+		}
+		fmt.Fprintln(p.out, "Enter the manifest again.")
+	}
+}
+
+func askManifestFields(p *prompter, cfg *setupConfig) (map[string]any, error) {
+	required := func(v string) error {
+		if v == "" {
+			return errors.New("a value is required")
+		}
+		return nil
+	}
+	name, err := p.ask("App name", "", required)
+	if err != nil {
+		return nil, err
+	}
+	homepage, err := p.ask("Homepage URL", "", required)
+	if err != nil {
+		return nil, err
+	}
+	var perms map[string]any
+	_, err = p.ask("Permissions as name=level, comma-separated", "metadata=read", func(v string) (err error) {
+		perms, err = parsePermissions(v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	events, err := p.ask("Webhook events, comma-separated (blank for none)", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.WebhookURL, err = p.ask("Webhook URL (blank to enter it on the setup page)", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	public, err := p.yesNo("Allow any account to install the app?", false)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]any{
+		"name":                name,
+		"url":                 homepage,
+		"public":              public,
+		"default_permissions": perms,
+	}
+	if list := splitList(events); len(list) > 0 {
+		doc["default_events"] = list
+	}
+	return doc, nil
+}
+
+// parsePermissions parses "name=level,..." into a manifest permissions map.
+func parsePermissions(v string) (map[string]any, error) {
+	perms := map[string]any{}
+	for _, pair := range splitList(v) {
+		name, level, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid permission %q: want name=level", pair)
+		}
+		perms[strings.TrimSpace(name)] = strings.TrimSpace(level)
+	}
+	return perms, nil
+}
+
+// splitList splits a comma-separated answer, dropping empty items.
+func splitList(v string) []string {
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// prompter asks questions on out and reads the answers, one per line,
+// from in.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *prompter) section(title string) {
+	fmt.Fprintf(p.out, "\n%s\n%s\n", title, strings.Repeat("-", len(title)))
+}
+
+func (p *prompter) problem(mp manifestProblem) {
+	label := "warning"
+	if mp.fatal {
+		label = "error"
+	}
+	fmt.Fprintf(p.out, "  %s: %s", label, mp.msg)
+	if mp.hint != "" {
+		fmt.Fprintf(p.out, " (%s)", mp.hint)
+	}
+	fmt.Fprintln(p.out)
+}
+
+// readLine reads one answer. A closed input before an answer ends the
+// wizard.
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("input ended before the wizard finished")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask asks label until check accepts the answer, which defaults to def.
+func (p *prompter) ask(label, def string, check func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if check != nil {
+			if err := check(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// choose asks for one of options, by name or 1-based number.
+func (p *prompter) choose(label string, options []string, def string) (string, error) {
+	for i, opt := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, opt)
+	}
+	var choice string
+	_, err := p.ask(label, def, func(v string) error {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= len(options) {
+			choice = options[n-1]
+			return nil
+		}
+		if slices.Contains(options, v) {
+			choice = v
+			return nil
+		}
+		return fmt.Errorf("choose one of %s", strings.Join(options, ", "))
+	})
+	return choice, err
+}
+
+// yesNo asks a yes/no question.
+func (p *prompter) yesNo(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s] ", question, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  answer y or n")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "ghappsetup.yaml")
+	envFile := filepath.Join(dir, "app.env")
+
+	answers := []string{
+		"nope", "1", envFile, // backend, retried
+		"", "my-org", // GitHub URL, organization
+		"my-app", "https://example.com", "issues=rw", "", "", "", // manifest with an invalid level
+		"my-app", "https://example.com", "issues=write, metadata=read", "issues,issue_comment", "https://app.example.com/webhook", "n",
+	}
+	env := newTestEnv(nil)
+	env.stdin = strings.NewReader(strings.Join(answers, "\n") + "\n")
+	if code := run(context.Background(), env.cliEnv, []string{"init", "-config", configFile, "-no-setup"}); code != 0 {
+		t.Fatalf("init exit = %d\nstderr: %s", code, env.stderr)
+	}
+	for _, want := range []string{"choose one of envfile, files, aws-ssm", `permission issues has invalid level "rw"`} {
+		if !strings.Contains(env.stderr.String(), want) {
+			t.Errorf("stderr does not contain %q:\n%s", want, env.stderr)
+		}
+	}
+
+	cfg, err := readSetupConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Storage != (storageConfig{Mode: "envfile", Dir: envFile}) {
+		t.Errorf("storage = %+v", cfg.Storage)
+	}
+	if cfg.GitHub != (githubConfig{Org: "my-org"}) {
+		t.Errorf("github = %+v", cfg.GitHub)
+	}
+	if cfg.WebhookURL != "https://app.example.com/webhook" {
+		t.Errorf("webhook_url = %q", cfg.WebhookURL)
+	}
+	m, err := cfg.manifest()
+	if err != nil {
+		t.Fatalf("manifest() error = %v", err)
+	}
+	if m.Name != "my-app" || m.Public || !reflect.DeepEqual(m.DefaultPerms, map[string]string{"issues": "write", "metadata": "read"}) ||
+		!reflect.DeepEqual(m.DefaultEvents, []string{"issues", "issue_comment"}) {
+		t.Errorf("manifest = %+v", m)
+	}
+	wantVars := map[string]string{"STORAGE_MODE": "envfile", "STORAGE_DIR": envFile, "GITHUB_ORG": "my-org"}
+	if got := cfg.envVars(); !reflect.DeepEqual(got, wantVars) {
+		t.Errorf("envVars() = %v, want %v", got, wantVars)
+	}
+}
+
+func TestInit_InputEnded(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "ghappsetup.yaml")
+	env := newTestEnv(nil)
+	env.stdin = strings.NewReader("aws-ssm\n")
+	if code := run(context.Background(), env.cliEnv, []string{"init", "-config", configFile}); code != 1 {
+		t.Fatalf("init exit = %d, want 1\nstderr: %s", code, env.stderr)
+	}
+	if !strings.Contains(env.stderr.String(), "input ended before the wizard finished") {
+		t.Errorf("stderr = %s", env.stderr)
+	}
+	if _, err := os.Stat(configFile); !os.IsNotExist(err) {
+		t.Errorf("config file written after an unfinished wizard: %v", err)
+	}
+}
+
+func TestSetup_AlreadyRegistered(t *testing.T) {
+	store := registeredStore(t)
+	configFile := filepath.Join(t.TempDir(), "ghappsetup.yaml")
+	err := writeSetupConfig(configFile, &setupConfig{
+		Storage:  storageConfig{Mode: configstore.StorageModeEnvFile, Dir: store.FilePath},
+		Manifest: map[string]any{"name": "my-app", "url": "https://example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := newTestEnv(nil)
+	if code := run(context.Background(), env.cliEnv, []string{"setup", "-config", configFile, "-addr", "127.0.0.1:0"}); code != 0 {
+		t.Fatalf("setup exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if !strings.Contains(env.stdout.String(), "app 123 is already registered") {
+		t.Errorf("stdout = %s", env.stdout)
+	}
+}
+
+func TestSetup_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  setupConfig
+		want string
+	}{
+		{name: "no manifest", cfg: setupConfig{}, want: "config has no manifest"},
+		{name: "invalid manifest", cfg: setupConfig{Manifest: map[string]any{"name": "my-app"}}, want: "url is required"},
+		{name: "unknown mode", cfg: setupConfig{Storage: storageConfig{Mode: "s3"}, Manifest: map[string]any{"url": "https://example.com"}},
+			want: `unknown storage.mode "s3"`},
+		{name: "no prefix", cfg: setupConfig{Storage: storageConfig{Mode: "aws-ssm"}, Manifest: map[string]any{"url": "https://example.com"}},
+			want: "storage.prefix is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "ghappsetup.yaml")
+			if err := writeSetupConfig(configFile, &tt.cfg); err != nil {
+				t.Fatal(err)
+			}
+			env := newTestEnv(nil)
+			if code := run(context.Background(), env.cliEnv, []string{"setup", "-config", configFile}); code != 1 {
+				t.Fatalf("setup exit = %d, want 1\nstderr: %s", code, env.stderr)
+			}
+			if !strings.Contains(env.stderr.String(), tt.want) {
+				t.Errorf("stderr = %s, want it to contain %s", env.stderr, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// runInstaller implements "ghappsetup installer disable|enable".
+func runInstaller(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) == 0 {
+		return usagef("expected a subcommand: disable or enable")
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(env.stderr, "Usage: ghappsetup installer disable|enable [-yes] [-json]")
+		return flag.ErrHelp
+	case "disable", "enable":
+		return runInstallerToggle(ctx, env, args[0], args[1:])
+	default:
+		return usagef("unknown subcommand %q (expected disable or enable)", args[0])
+	}
+}
+
+// runInstallerToggle sets the installer flag in the configured store after
+// confirming the change.
+func runInstallerToggle(ctx context.Context, env *cliEnv, action string, args []string) error {
+	fs := newFlagSet(env, "installer "+action, "[-yes] [-json]")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	store, err := env.newStore()
+	if err != nil {
+		return err
+	}
+	status, err := store.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read installer status: %w", err)
+	}
+	disable := action == "disable"
+	result := installerResult{
+		Installer: action + "d",
+		AppID:     status.AppID,
+		AppSlug:   status.AppSlug,
+		Store:     storeInfoOf(store),
+	}
+	if status.InstallerDisabled == disable {
+		if *jsonOut {
+			return writeJSON(env.stdout, result)
+		}
+		fmt.Fprintf(env.stdout, "installer is already %sd in %s\n", action, describeStore(store))
+		return nil
+	}
+
+	var set func(context.Context) error
+	if disable {
+		set = store.DisableInstaller
+	} else {
+		enabler, ok := store.(configstore.InstallerEnabler)
+		if !ok {
+			return errors.New("store cannot enable the installer")
+		}
+		set = enabler.EnableInstaller
+	}
+
+	target := "the installer"
+	if status.Registered {
+		target = fmt.Sprintf("the installer of app %d", status.AppID)
+		if status.AppSlug != "" {
+			target += fmt.Sprintf(" (%s)", status.AppSlug)
+		}
+	}
+	if !*yes {
+		question := fmt.Sprintf("%s %s in %s?", strings.ToUpper(action[:1])+action[1:], target, describeStore(store))
+		if !disable {
+			question += " Anyone who can reach its URL can then use it."
+		}
+		ok, err := confirm(env, question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted")
+		}
+	}
+
+	if err := set(ctx); err != nil {
+		return fmt.Errorf("failed to %s installer: %w", action, err)
+	}
+	if *jsonOut {
+		result.Changed = true
+		return writeJSON(env.stdout, result)
+	}
+	fmt.Fprintf(env.stdout, "%sd %s; the installer reads the flag on every request\n", action, target)
+	return nil
+}
+
+// installerResult is the -json output of installer disable and enable.
+// Installer is the state after the command, "disabled" or "enabled", and
+// Changed is false if it was already in that state. AppID is 0 if no app
+// is registered.
+type installerResult struct {
+	Installer string    `json:"installer"`
+	Changed   bool      `json:"changed"`
+	AppID     int64     `json:"app_id"`
+	AppSlug   string    `json:"app_slug,omitempty"`
+	Store     storeInfo `json:"store"`
+}
+
+// confirm asks question on stderr and reports whether the answer read
+// from stdin is yes.
+func confirm(env *cliEnv, question string) (bool, error) {
+	fmt.Fprintf(env.stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(env.stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(env.stderr)
+		return false, errors.New("no confirmation on stdin; pass -yes to skip it")
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestInstallerToggle(t *testing.T) {
+	ctx := context.Background()
+	store := registeredStore(t)
+	installerDisabled := func() bool {
+		t.Helper()
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status.InstallerDisabled
+	}
+
+	// Declining the prompt, or no answer at all, changes nothing
+	for _, answer := range []string{"n\n", ""} {
+		env := newTestEnv(store)
+		env.stdin = strings.NewReader(answer)
+		if code := run(ctx, env.cliEnv, []string{"installer", "disable"}); code != 1 {
+			t.Errorf("installer disable answering %q exit = %d, want 1", answer, code)
+		}
+		if installerDisabled() {
+			t.Fatalf("installer disabled after answering %q", answer)
+		}
+	}
+
+	env := newTestEnv(store)
+	env.stdin = strings.NewReader("y\n")
+	if code := run(ctx, env.cliEnv, []string{"installer", "disable"}); code != 0 {
+		t.Fatalf("installer disable exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if !strings.Contains(env.stderr.String(), "app 123 (my-app)") {
+		t.Errorf("prompt does not name the app: %s", env.stderr)
+	}
+	if !installerDisabled() {
+		t.Fatal("installer not disabled")
+	}
+
+	env = newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"installer", "disable"}); code != 0 || !strings.Contains(env.stdout.String(), "already disabled") {
+		t.Errorf("second disable exit = %d, output = %s", code, env.stdout)
+	}
+
+	env = newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"installer", "enable", "-yes"}); code != 0 {
+		t.Fatalf("installer enable exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if installerDisabled() {
+		t.Error("installer still disabled after enable")
+	}
+}
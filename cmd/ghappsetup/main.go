@@ -0,0 +1,218 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Command ghappsetup manages the GitHub App credentials kept in a
+// configstore backend. The store is selected with the same environment
+// variables as configstore.NewFromEnv: STORAGE_MODE, STORAGE_DIR, and
+// AWS_SSM_PARAMETER_PREFIX. Variables that are not set are taken from the
+// config file "ghappsetup init" writes, named by GHAPPSETUP_CONFIG or
+// ./ghappsetup.yaml.
+//
+//	ghappsetup <command> [flags]
+//
+// Run "ghappsetup help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/credbundle"
+)
+
+// command is a ghappsetup subcommand. Commands that dispatch on their
+// first argument list its values in subcommands.
+type command struct {
+	name        string
+	summary     string
+	subcommands []string
+	run         func(ctx context.Context, env *cliEnv, args []string) error
+}
+
+// commands is set in init, since completion refers to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "init", summary: "Walk through configuring the store and manifest, then create the app", run: runInit},
+		{name: "setup", summary: "Create the app from the config file init wrote", run: runSetup},
+		{name: "export", summary: "Export credentials to an encrypted bundle", run: runExport},
+		{name: "import", summary: "Import credentials from an encrypted bundle", run: runImport},
+		{name: "rotate-key", summary: "Replace the stored private key with a verified new one", run: runRotateKey},
+		{name: "sync", summary: "Copy the credentials to a shared volume, once or kept current (sync -watch)", run: runSync},
+		{name: "delete", summary: "Delete the stored credentials, e.g. of a preview environment", run: runDelete},
+		{name: "doctor", summary: "Check the store, credentials, and configuration for problems", run: runDoctor},
+		{name: "serve", summary: "Run a development webhook receiver (serve -dev)", run: runServe},
+		{name: "webhook", summary: "Send a signed sample delivery to a webhook (webhook send)", subcommands: []string{"send"}, run: runWebhook},
+		{name: "bootstrap", summary: "Create placeholder parameters before the first save (bootstrap aws-ssm)", subcommands: []string{configstore.StorageModeAWSSSM}, run: runBootstrap},
+		{name: "installer", summary: "Disable or enable the installer (installer disable|enable)", subcommands: []string{"disable", "enable"}, run: runInstaller},
+		{name: "manifest", summary: "Render a manifest template as validated JSON (manifest render)", subcommands: []string{"render"}, run: runManifest},
+		{name: "completion", summary: "Print a shell completion script (completion bash|zsh|fish)", subcommands: []string{"bash", "zsh", "fish"}, run: runCompletion},
+	}
+}
+
+// cliEnv holds the streams and service constructors commands use, so
+// tests can replace them.
+type cliEnv struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	newStore     func() (configstore.Store, error)
+	newKMSClient func(ctx context.Context) (credbundle.KMSClient, error)
+	newSSMClient func(ctx context.Context) (configstore.SSMClient, error)
+
+	// onHelp, if set, replaces the usage message of a command's flag set;
+	// completion uses it to collect the flags.
+	onHelp func(fs *flag.FlagSet)
+}
+
+func defaultEnv() *cliEnv {
+	return &cliEnv{
+		stdin:        os.Stdin,
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+		newStore:     configstore.NewFromEnv,
+		newKMSClient: newKMSClient,
+		newSSMClient: newSSMClient,
+	}
+}
+
+// usageError is returned for invalid arguments; it exits with status 2.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := applyConfigFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "ghappsetup: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(run(ctx, defaultEnv(), os.Args[1:]))
+}
+
+// run executes the command named by args[0] and returns the exit status.
+func run(ctx context.Context, env *cliEnv, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(env.stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, env, args[1:])
+		var usageErr *usageError
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.As(err, &usageErr):
+			fmt.Fprintf(env.stderr, "ghappsetup %s: %v\n", cmd.name, err)
+			return 2
+		default:
+			fmt.Fprintf(env.stderr, "ghappsetup %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(env.stderr, "ghappsetup: unknown command %q\n\n", args[0])
+	printUsage(env.stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ghappsetup <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The store is selected with STORAGE_MODE, STORAGE_DIR, and AWS_SSM_PARAMETER_PREFIX,")
+	fmt.Fprintln(w, "or with the config file named by GHAPPSETUP_CONFIG or ./ghappsetup.yaml.")
+	fmt.Fprintln(w, `Run "ghappsetup <command> -h" for command flags; most accept -json.`)
+}
+
+// newFlagSet creates a flag set for a command that reports errors
+// through its return value.
+func newFlagSet(env *cliEnv, name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet("ghappsetup "+name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		if env.onHelp != nil {
+			env.onHelp(fs)
+			return
+		}
+		fmt.Fprintf(env.stderr, "Usage: ghappsetup %s %s\n\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args, turning parse failures into usage errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// stringsFlag is a flag that may be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func newKMSClient(ctx context.Context) (credbundle.KMSClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return kms.NewFromConfig(cfg), nil
+}
+
+func newSSMClient(ctx context.Context) (configstore.SSMClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+		if endpoint := os.Getenv(configstore.EnvAWSEndpointURLSSM); endpoint != "" {
+			o.BaseEndpoint = &endpoint
+		}
+	}), nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/credbundle"
+)
+
+// testEnv is a cliEnv whose store is store and whose output is captured.
+type testEnv struct {
+	*cliEnv
+	stdout, stderr *bytes.Buffer
+}
+
+func newTestEnv(store configstore.Store) *testEnv {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &testEnv{
+		cliEnv: &cliEnv{
+			stdin:    strings.NewReader(""),
+			stdout:   stdout,
+			stderr:   stderr,
+			newStore: func() (configstore.Store, error) { return store, nil },
+			newKMSClient: func(ctx context.Context) (credbundle.KMSClient, error) {
+				panic("unexpected KMS client")
+			},
+			newSSMClient: func(ctx context.Context) (configstore.SSMClient, error) {
+				panic("unexpected SSM client")
+			},
+		},
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: nil, want: 2},
+		{args: []string{"help"}, want: 0},
+		{args: []string{"bogus"}, want: 2},
+		{args: []string{"export", "-h"}, want: 0},
+		{args: []string{"export", "-nope"}, want: 2},
+		{args: []string{"export", "extra"}, want: 2},
+	}
+	for _, tt := range tests {
+		env := newTestEnv(nil)
+		if got := run(context.Background(), env.cliEnv, tt.args); got != tt.want {
+			t.Errorf("run(%q) = %d, want %d\nstderr: %s", tt.args, got, tt.want, env.stderr)
+		}
+	}
+}
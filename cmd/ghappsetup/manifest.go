@@ -0,0 +1,259 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// manifestFields are the top-level fields GitHub accepts in an app
+// manifest.
+var manifestFields = []string{
+	"name",
+	"url",
+	"description",
+	"hook_attributes",
+	"redirect_url",
+	"callback_urls",
+	"setup_url",
+	"setup_on_update",
+	"request_oauth_on_install",
+	"public",
+	"default_permissions",
+	"default_events",
+}
+
+// manifestProblem is an issue found in a manifest. Fatal problems make
+// GitHub reject the manifest or the app unusable.
+type manifestProblem struct {
+	fatal bool
+	msg   string
+	hint  string
+}
+
+// validateManifest checks a decoded manifest document against the
+// fields GitHub accepts and requires.
+func validateManifest(doc map[string]any) (installer.Manifest, []manifestProblem) {
+	var m installer.Manifest
+	var problems []manifestProblem
+
+	for _, key := range slices.Sorted(maps.Keys(doc)) {
+		if !slices.Contains(manifestFields, key) {
+			problems = append(problems, manifestProblem{fatal: true,
+				msg:  fmt.Sprintf("unknown field %s", key),
+				hint: "check the spelling against GitHub's app manifest parameters"})
+		}
+	}
+	data, err := json.Marshal(doc)
+	if err == nil {
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return m, append(problems, manifestProblem{fatal: true, msg: fmt.Sprintf("invalid manifest: %v", err)})
+	}
+
+	if m.URL == "" {
+		problems = append(problems, manifestProblem{fatal: true,
+			msg: "url is required", hint: "set url to the homepage of the app"})
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.DefaultPerms)) {
+		if level := m.DefaultPerms[name]; !slices.Contains([]string{"read", "write", "admin"}, level) {
+			problems = append(problems, manifestProblem{fatal: true,
+				msg:  fmt.Sprintf("permission %s has invalid level %q", name, level),
+				hint: `use "read", "write", or "admin"`})
+		}
+	}
+	for i, event := range m.DefaultEvents {
+		if slices.Contains(m.DefaultEvents[:i], event) {
+			problems = append(problems, manifestProblem{msg: fmt.Sprintf("event %s is listed twice", event)})
+		}
+	}
+	if hook := m.HookAttributes.URL; hook != "" && !strings.HasPrefix(hook, "https://") {
+		problems = append(problems, manifestProblem{
+			msg:  fmt.Sprintf("webhook URL %s does not use HTTPS", hook),
+			hint: "GitHub only delivers to public URLs; use HTTPS outside local testing"})
+	}
+	return m, problems
+}
+
+// runManifest implements "ghappsetup manifest".
+func runManifest(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) == 0 {
+		return usagef("expected a subcommand: render")
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(env.stderr, "Usage: ghappsetup manifest render -template <file> [-set key=value]... [-link] [-json]")
+		return flag.ErrHelp
+	case "render":
+		return runManifestRender(ctx, env, args[1:])
+	default:
+		return usagef("unknown subcommand %q (expected render)", args[0])
+	}
+}
+
+// runManifestRender implements "ghappsetup manifest render".
+//
+// The template is a YAML (or JSON) manifest processed with text/template,
+// where each -set key=value is available as {{ .key }}. The result is
+// validated and printed as JSON, or as a data URL that submits it to
+// GitHub when opened.
+func runManifestRender(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "manifest render", "-template <file> [-set key=value]... [-link] [-json]")
+	templateFile := fs.String("template", "", `manifest template, or "-" for stdin (required)`)
+	var sets stringsFlag
+	fs.Var(&sets, "set", "template value as key=value (repeatable)")
+	link := fs.Bool("link", false, "print a data URL that submits the manifest to GitHub")
+	githubURL := fs.String("github-url", configstore.GetEnvDefault(installer.EnvGitHubURL, "https://github.com"), "GitHub web URL for -link")
+	org := fs.String("org", os.Getenv(installer.EnvGitHubOrg), "organization that owns the app for -link (default $GITHUB_ORG, else the user)")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *templateFile == "" {
+		return usagef("-template is required")
+	}
+	values := make(map[string]string, len(sets))
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return usagef("invalid -set %q: want key=value", set)
+		}
+		values[key] = value
+	}
+
+	var src []byte
+	var err error
+	if *templateFile == "-" {
+		src, err = io.ReadAll(env.stdin)
+	} else {
+		src, err = os.ReadFile(*templateFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	manifestJSON, warnings, err := renderManifest(src, values)
+	if err != nil {
+		return err
+	}
+
+	var dataURL string
+	if *link {
+		if dataURL, err = manifestLink(*githubURL, *org, manifestJSON); err != nil {
+			return err
+		}
+	}
+	if *jsonOut {
+		return writeJSON(env.stdout, manifestResult{
+			Manifest: manifestJSON,
+			Warnings: nonNil(warnings),
+			Link:     dataURL,
+		})
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(env.stderr, "warning: %s\n", w)
+	}
+	if *link {
+		_, err = fmt.Fprintln(env.stdout, dataURL)
+	} else {
+		_, err = fmt.Fprintf(env.stdout, "%s\n", manifestJSON)
+	}
+	return err
+}
+
+// manifestResult is the -json output of manifest render. Link is set with
+// -link.
+type manifestResult struct {
+	Manifest json.RawMessage `json:"manifest"`
+	Warnings []string        `json:"warnings"`
+	Link     string          `json:"link,omitempty"`
+}
+
+// renderManifest executes the template with values, validates the result,
+// and returns it as indented JSON along with any warnings.
+func renderManifest(src []byte, values map[string]string) ([]byte, []string, error) {
+	tmpl, err := texttemplate.New("manifest").Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return nil, nil, fmt.Errorf("rendered manifest is not valid YAML: %w", err)
+	}
+	if doc == nil {
+		return nil, nil, fmt.Errorf("rendered manifest is empty")
+	}
+
+	m, problems := validateManifest(doc)
+	if m.RedirectURL == "" {
+		problems = append(problems, manifestProblem{
+			msg:  "redirect_url is not set",
+			hint: "GitHub sends the code to exchange for credentials to redirect_url; set it for manual submission"})
+	}
+	var fatal, warnings []string
+	for _, p := range problems {
+		msg := p.msg
+		if p.hint != "" {
+			msg += " (" + p.hint + ")"
+		}
+		if p.fatal {
+			fatal = append(fatal, msg)
+		} else {
+			warnings = append(warnings, msg)
+		}
+	}
+	if len(fatal) > 0 {
+		return nil, nil, fmt.Errorf("invalid manifest:\n  %s", strings.Join(fatal, "\n  "))
+	}
+
+	manifestJSON, err := json.MarshalIndent(doc, "", "  ")
+	return manifestJSON, warnings, err
+}
+
+var manifestLinkTemplate = template.Must(template.New("link").Parse(
+	`<!DOCTYPE html><form method="post" action="{{.Action}}"><input type="hidden" name="manifest" value="{{.Manifest}}"></form><script>document.forms[0].submit()</script>`))
+
+// manifestLink returns a data URL for a page that posts the manifest to
+// GitHub's new app form, the same form the installer submits.
+func manifestLink(githubURL, org string, manifestJSON []byte) (string, error) {
+	githubURL = strings.TrimRight(githubURL, "/")
+	action := githubURL + "/settings/apps/new"
+	if org != "" {
+		action = fmt.Sprintf("%s/organizations/%s/settings/apps/new", githubURL, org)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, manifestJSON); err != nil {
+		return "", err
+	}
+	var page bytes.Buffer
+	err := manifestLinkTemplate.Execute(&page, struct{ Action, Manifest string }{action, compact.String()})
+	if err != nil {
+		return "", fmt.Errorf("failed to render link: %w", err)
+	}
+	return "data:text/html;base64," + base64.StdEncoding.EncodeToString(page.Bytes()), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testManifestTemplate = `name: {{ .name }}
+url: https://example.com
+description: Syncs issues
+redirect_url: {{ .base_url }}/callback
+hook_attributes:
+  url: {{ .base_url }}/webhook
+default_permissions:
+  issues: write
+default_events:
+  - issues
+`
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestManifestRender(t *testing.T) {
+	tmpl := writeTemplate(t, testManifestTemplate)
+
+	env := newTestEnv(nil)
+	args := []string{"manifest", "render", "-template", tmpl, "-set", "name=my-app", "-set", "base_url=https://app.example.com"}
+	if code := run(context.Background(), env.cliEnv, args); code != 0 {
+		t.Fatalf("manifest render exit = %d\nstderr: %s", code, env.stderr)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(env.stdout.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, env.stdout)
+	}
+	if got["name"] != "my-app" || got["description"] != "Syncs issues" {
+		t.Errorf("manifest = %v", got)
+	}
+	if hook := got["hook_attributes"].(map[string]any)["url"]; hook != "https://app.example.com/webhook" {
+		t.Errorf("hook_attributes.url = %v", hook)
+	}
+	if env.stderr.Len() > 0 {
+		t.Errorf("unexpected warnings: %s", env.stderr)
+	}
+}
+
+func TestManifestRender_Link(t *testing.T) {
+	tmpl := writeTemplate(t, testManifestTemplate)
+
+	env := newTestEnv(nil)
+	args := []string{"manifest", "render", "-template", tmpl, "-set", "name=my-app", "-set", "base_url=https://app.example.com",
+		"-link", "-github-url", "https://github.example.com/", "-org", "my-org"}
+	if code := run(context.Background(), env.cliEnv, args); code != 0 {
+		t.Fatalf("manifest render -link exit = %d\nstderr: %s", code, env.stderr)
+	}
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(env.stdout.String()), "data:text/html;base64,")
+	if !ok {
+		t.Fatalf("output is not a data URL: %s", env.stdout)
+	}
+	page, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`action="https://github.example.com/organizations/my-org/settings/apps/new"`,
+		`&#34;name&#34;:&#34;my-app&#34;`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("page does not contain %s:\n%s", want, page)
+		}
+	}
+}
+
+func TestManifestRender_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "missing value", template: testManifestTemplate, want: `"base_url"`},
+		{name: "unknown field", template: "url: https://example.com\ndefault_permission:\n  issues: write\n", want: "unknown field default_permission"},
+		{name: "invalid level", template: "url: https://example.com\ndefault_permissions:\n  issues: rw\n", want: `permission issues has invalid level "rw"`},
+		{name: "wrong type", template: "url: https://example.com\npublic: maybe\n", want: "invalid manifest"},
+		{name: "not YAML", template: "url: [", want: "not valid YAML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(nil)
+			args := []string{"manifest", "render", "-template", writeTemplate(t, tt.template), "-set", "name=my-app"}
+			if code := run(context.Background(), env.cliEnv, args); code != 1 {
+				t.Fatalf("manifest render exit = %d, want 1\nstderr: %s", code, env.stderr)
+			}
+			if !strings.Contains(env.stderr.String(), tt.want) {
+				t.Errorf("stderr = %s, want it to contain %s", env.stderr, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// The -json output of each command is a single JSON object, except for
+// "serve -dev", which prints one object per line. Its fields are part of
+// the CLI's interface: new fields may be added, but existing ones keep
+// their names and types. Errors are still reported on stderr with a
+// non-zero exit status.
+
+// jsonFlag defines the -json flag on fs.
+func jsonFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("json", false, "print the result as JSON")
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// storeInfo identifies a store in JSON output.
+type storeInfo struct {
+	Mode     string `json:"mode"`
+	Location string `json:"location"`
+}
+
+func storeInfoOf(store configstore.Store) storeInfo {
+	switch s := store.(type) {
+	case *configstore.LocalEnvFileStore:
+		return storeInfo{Mode: configstore.StorageModeEnvFile, Location: s.FilePath}
+	case *configstore.LocalFileStore:
+		return storeInfo{Mode: configstore.StorageModeFiles, Location: s.Dir}
+	case *configstore.AWSSSMStore:
+		return storeInfo{Mode: configstore.StorageModeAWSSSM, Location: s.ParameterPrefix}
+	default:
+		return storeInfo{Mode: fmt.Sprintf("%T", store)}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+func TestJSONOutput(t *testing.T) {
+	ctx := context.Background()
+	hooks, _ := webhookServer(t, "hook")
+	tmpl := writeTemplate(t, testManifestTemplate)
+
+	tests := []struct {
+		name     string
+		args     []string
+		ssm      *memSSMClient
+		wantCode int
+		wantKeys []string
+	}{
+		{
+			name:     "doctor",
+			args:     []string{"doctor", "-offline", "-json"},
+			wantCode: 1, // the test key does not parse
+			wantKeys: []string{"findings", "ok"},
+		},
+		{
+			name:     "installer",
+			args:     []string{"installer", "enable", "-json"},
+			wantKeys: []string{"app_id", "app_slug", "changed", "installer", "store"},
+		},
+		{
+			name:     "manifest render",
+			args:     []string{"manifest", "render", "-template", tmpl, "-set", "name=my-app", "-set", "base_url=https://app.example.com", "-json"},
+			wantKeys: []string{"manifest", "warnings"},
+		},
+		{
+			name:     "webhook send",
+			args:     []string{"webhook", "send", "-event", "ping", "-url", hooks.URL, "-json"},
+			wantKeys: []string{"delivery_id", "event", "response", "status_code", "url"},
+		},
+		{
+			name:     "bootstrap",
+			args:     []string{"bootstrap", "aws-ssm", "-prefix", "/app/", "-json"},
+			ssm:      &memSSMClient{values: map[string]string{}},
+			wantKeys: []string{"created", "existed", "prefix"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(registeredStore(t))
+			if tt.ssm != nil {
+				env.newSSMClient = func(context.Context) (configstore.SSMClient, error) { return tt.ssm, nil }
+			}
+			if code := run(ctx, env.cliEnv, tt.args); code != tt.wantCode {
+				t.Fatalf("exit = %d, want %d\nstderr: %s", code, tt.wantCode, env.stderr)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(env.stdout.Bytes(), &got); err != nil {
+				t.Fatalf("output is not a JSON object: %v\n%s", err, env.stdout)
+			}
+			if keys := slices.Sorted(maps.Keys(got)); !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestDevReceiver_JSON(t *testing.T) {
+	var out bytes.Buffer
+	r := newDevReceiver(&out, "hook", "", false, true)
+	payload := []byte(`{"action":"opened","installation":{"id":7},"repository":{"full_name":"octo-org/hello-world"}}`)
+	for _, id := range []string{"d-1", "d-2"} {
+		if err := r.deliver(context.Background(), &webhook.Delivery{ID: id, Event: "issues", Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := json.NewDecoder(&out)
+	for _, id := range []string{"d-1", "d-2"} {
+		var rec deliveryRecord
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("line for %s: %v", id, err)
+		}
+		if rec.DeliveryID != id || rec.Action != "opened" || rec.Repository != "octo-org/hello-world" ||
+			rec.InstallationID != 7 || !bytes.Equal(rec.Payload, payload) || rec.Forward != nil {
+			t.Errorf("record = %+v", rec)
+		}
+	}
+}
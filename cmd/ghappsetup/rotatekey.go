@@ -0,0 +1,156 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// runRotateKey implements "ghappsetup rotate-key".
+//
+// GitHub has no API to create or delete an app's private keys, so the new
+// key is generated in the app's settings and passed in. The command checks
+// that GitHub accepts a JWT signed with it before replacing the stored
+// key, then names the old key to delete.
+func runRotateKey(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "rotate-key", "-key <file> [-github-url <url>] [-json]")
+	keyFile := fs.String("key", "", `new PEM private key, or "-" for stdin (required)`)
+	githubURL := fs.String("github-url", os.Getenv(installer.EnvGitHubURL), "GitHub web URL (default $GITHUB_URL, then the app's HTML URL)")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return usagef("-key is required")
+	}
+
+	var pemData []byte
+	var err error
+	if *keyFile == "-" {
+		pemData, err = io.ReadAll(env.stdin)
+	} else {
+		pemData, err = os.ReadFile(*keyFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	newKey, err := ghauth.ParsePrivateKey(pemData)
+	if err != nil {
+		return fmt.Errorf("invalid key: %w", err)
+	}
+
+	store, creds, err := loadCredentials(ctx, env)
+	if err != nil {
+		return err
+	}
+
+	newFingerprint, err := ghauth.Fingerprint(newKey)
+	if err != nil {
+		return err
+	}
+	oldFingerprint := "unknown"
+	if oldKey, err := ghauth.ParsePrivateKey([]byte(creds.PrivateKey)); err == nil {
+		if oldFingerprint, err = ghauth.Fingerprint(oldKey); err != nil {
+			return err
+		}
+	}
+	if newFingerprint == oldFingerprint {
+		return fmt.Errorf("key %s is already the stored key", newFingerprint)
+	}
+
+	opts := githubOptions(*githubURL, creds)
+	if err := ghauth.VerifyAppKey(ctx, creds.AppID, newKey, opts...); err != nil {
+		if errors.Is(err, ghauth.ErrKeyMismatch) {
+			return fmt.Errorf("GitHub rejected a JWT signed with the new key; is it a key for app %d? %w", creds.AppID, err)
+		}
+		return fmt.Errorf("failed to verify new key: %w", err)
+	}
+
+	creds.PrivateKey = strings.TrimSpace(string(pemData)) + "\n"
+	if err := store.Save(ctx, creds); err != nil {
+		return fmt.Errorf("failed to save new key: %w", err)
+	}
+	if err := checkStoredKey(ctx, store, newFingerprint); err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		return writeJSON(env.stdout, rotateKeyResult{
+			AppID:          creds.AppID,
+			NewFingerprint: newFingerprint,
+			OldFingerprint: oldFingerprint,
+			HTMLURL:        creds.HTMLURL,
+		})
+	}
+	fmt.Fprintf(env.stdout, "rotated private key for app %d\n", creds.AppID)
+	fmt.Fprintf(env.stdout, "  new key: %s (verified with GitHub)\n", newFingerprint)
+	fmt.Fprintf(env.stdout, "  old key: %s\n", oldFingerprint)
+	fmt.Fprintf(env.stdout, "Once every instance has reloaded, delete the old key in the app's settings")
+	if creds.HTMLURL != "" {
+		fmt.Fprintf(env.stdout, " (%s)", creds.HTMLURL)
+	}
+	fmt.Fprintln(env.stdout, "; GitHub has no API to delete it.")
+	return nil
+}
+
+// rotateKeyResult is the -json output of rotate-key. OldFingerprint is
+// "unknown" if the replaced key did not parse.
+type rotateKeyResult struct {
+	AppID          int64  `json:"app_id"`
+	NewFingerprint string `json:"new_fingerprint"`
+	OldFingerprint string `json:"old_fingerprint"`
+	HTMLURL        string `json:"html_url,omitempty"`
+}
+
+// checkStoredKey reads the key back from store and checks that it is
+// the key with the given fingerprint.
+func checkStoredKey(ctx context.Context, store configstore.Store, fingerprint string) error {
+	saved, err := store.(configstore.CredentialLoader).Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read back new key: %w", err)
+	}
+	key, err := ghauth.ParsePrivateKey([]byte(saved.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("stored key does not parse: %w", err)
+	}
+	if got, _ := ghauth.Fingerprint(key); got != fingerprint {
+		return fmt.Errorf("stored key is %s, want %s", got, fingerprint)
+	}
+	return nil
+}
+
+// loadCredentials opens the configured store and loads its credentials.
+func loadCredentials(ctx context.Context, env *cliEnv) (configstore.Store, *configstore.AppCredentials, error) {
+	store, err := env.newStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return nil, nil, errors.New("store cannot load credentials")
+	}
+	creds, err := loader.Load(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	return store, creds, nil
+}
+
+// githubOptions selects the GitHub instance for API calls: githubURL if
+// set, otherwise the origin of the app's HTML URL.
+func githubOptions(githubURL string, creds *configstore.AppCredentials) []ghauth.TokenSourceOption {
+	if githubURL == "" {
+		githubURL = creds.HTMLURL
+	}
+	return []ghauth.TokenSourceOption{ghauth.WithGitHubURL(githubURL)}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/githubtest"
+)
+
+// appServer serves GET /api/v3/app for app 123, accepting JWTs signed by
+// any of keys.
+func appServer(t *testing.T, keys ...*rsa.PrivateKey) *githubtest.Server {
+	t.Helper()
+	srv := githubtest.NewServer(t)
+	srv.HandleFunc(http.MethodGet, "/api/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		for _, key := range keys {
+			if signedBy(&key.PublicKey, parts) {
+				w.Write([]byte(`{"id":123}`))
+				return
+			}
+		}
+		http.Error(w, `{"message":"A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+	})
+	return srv
+}
+
+// signedBy reports whether the JWT segments carry an RS256 signature by pub.
+func signedBy(pub *rsa.PublicKey, parts []string) bool {
+	if len(parts) != 3 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+}
+
+func writeKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, ghauth.EncodePKCS1(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRotateKey(t *testing.T) {
+	ctx := context.Background()
+	oldKey := githubtest.PrivateKey(t)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	creds.AppID = 123
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	srv := appServer(t, oldKey, newKey)
+
+	env := newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"rotate-key", "-key", writeKey(t, newKey), "-github-url", srv.URL}); code != 0 {
+		t.Fatalf("rotate-key exit = %d\nstderr: %s", code, env.stderr)
+	}
+
+	saved, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ghauth.ParsePrivateKey([]byte(saved.PrivateKey))
+	if err != nil {
+		t.Fatalf("stored key does not parse: %v", err)
+	}
+	if !got.Equal(newKey) {
+		t.Error("stored key is not the new key")
+	}
+	oldFingerprint, _ := ghauth.Fingerprint(oldKey)
+	if !strings.Contains(env.stdout.String(), oldFingerprint) {
+		t.Errorf("output does not name the old key %s:\n%s", oldFingerprint, env.stdout)
+	}
+
+	// Rotating to the key already stored is refused
+	env = newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"rotate-key", "-key", writeKey(t, newKey), "-github-url", srv.URL}); code != 1 {
+		t.Errorf("rotate-key to the same key exit = %d, want 1", code)
+	}
+}
+
+func TestRotateKey_RejectedKeyNotSaved(t *testing.T) {
+	ctx := context.Background()
+	oldKey := githubtest.PrivateKey(t)
+	wrongKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	creds.AppID = 123
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatal(err)
+	}
+	srv := appServer(t, oldKey)
+
+	env := newTestEnv(store)
+	if code := run(ctx, env.cliEnv, []string{"rotate-key", "-key", writeKey(t, wrongKey), "-github-url", srv.URL}); code != 1 {
+		t.Fatalf("rotate-key exit = %d, want 1", code)
+	}
+	if !strings.Contains(env.stderr.String(), "rejected") {
+		t.Errorf("stderr = %q, want the rejection explained", env.stderr)
+	}
+
+	saved, _ := store.Load(ctx)
+	if got, _ := ghauth.ParsePrivateKey([]byte(saved.PrivateKey)); !got.Equal(oldKey) {
+		t.Error("stored key changed after GitHub rejected the new key")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+const (
+	serveReadHeaderTimeout = 10 * time.Second
+	serveShutdownTimeout   = 5 * time.Second
+)
+
+// runServe implements "ghappsetup serve -dev".
+//
+// It is a development receiver: deliveries are verified with the stored
+// webhook secret, printed, and optionally forwarded to the app under
+// development. Production apps serve webhooks through ghappsetup.Runtime.
+func runServe(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "serve", "-dev [-addr <addr>] [-path <path>] [-forward <url>] [-secret-env <name>] [-quiet] [-json]")
+	dev := fs.Bool("dev", false, "run the development webhook receiver (required)")
+	addr := fs.String("addr", defaultServeAddr(), "listen address (default :$PORT, else :8080)")
+	path := fs.String("path", "/webhook", "webhook path")
+	forward := fs.String("forward", "", "forward verified deliveries, re-signed, to this URL")
+	secretEnv := fs.String("secret-env", "", "environment variable holding the secret (default: the stored webhook secret)")
+	quiet := fs.Bool("quiet", false, "print one line per delivery without the payload")
+	jsonOut := fs.Bool("json", false, "print each delivery as a line of JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !*dev {
+		return usagef("only -dev is supported; serve production webhooks with ghappsetup.Runtime")
+	}
+
+	secret, err := webhookSecret(ctx, env, *secretEnv)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	logger := clog.New(slog.NewTextHandler(env.stderr, nil))
+	srv := &http.Server{
+		Handler:           newDevReceiver(env.stdout, secret, *forward, *quiet, *jsonOut).handler(*path),
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return clog.WithLogger(ctx, logger) },
+	}
+
+	// Keep stdout to deliveries in JSON mode
+	status := env.stdout
+	if *jsonOut {
+		status = env.stderr
+	}
+	fmt.Fprintf(status, "receiving webhooks on http://%s%s", ln.Addr(), *path)
+	if *forward != "" {
+		fmt.Fprintf(status, ", forwarding to %s", *forward)
+	}
+	fmt.Fprintln(status)
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func defaultServeAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
+}
+
+// devReceiver prints and optionally forwards verified deliveries.
+type devReceiver struct {
+	secret  string
+	forward string
+	quiet   bool
+	json    bool
+
+	mu  sync.Mutex // serializes output
+	out io.Writer
+}
+
+func newDevReceiver(out io.Writer, secret, forward string, quiet, json bool) *devReceiver {
+	return &devReceiver{out: out, secret: secret, forward: forward, quiet: quiet, json: json}
+}
+
+// deliveryRecord is the -json output of serve -dev, one line per
+// delivery. Payload is omitted with -quiet, and Forward without -forward.
+type deliveryRecord struct {
+	Time           time.Time       `json:"time"`
+	Event          string          `json:"event"`
+	Action         string          `json:"action,omitempty"`
+	DeliveryID     string          `json:"delivery_id"`
+	Repository     string          `json:"repository,omitempty"`
+	Organization   string          `json:"organization,omitempty"`
+	InstallationID int64           `json:"installation_id,omitempty"`
+	Sender         string          `json:"sender,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Forward        *forwardRecord  `json:"forward,omitempty"`
+}
+
+type forwardRecord struct {
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newDeliveryRecord(d *webhook.Delivery) *deliveryRecord {
+	rec := &deliveryRecord{Time: time.Now(), Event: d.Event, DeliveryID: d.ID}
+	if c, err := d.Common(); err == nil {
+		rec.Action = c.Action
+		rec.Repository = c.Repository.FullName
+		rec.Organization = c.Organization.Login
+		rec.InstallationID = c.Installation.ID
+		rec.Sender = c.Sender.Login
+	}
+	return rec
+}
+
+// handler serves the receiver at path, with a /healthz endpoint.
+func (r *devReceiver) handler(path string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(path, webhook.NewHandler(r.deliver, webhook.WithSecret(r.secret)))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+// deliver prints d and forwards it. A failed forward fails the delivery,
+// so the sender sees the downstream error.
+func (r *devReceiver) deliver(ctx context.Context, d *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := newDeliveryRecord(d)
+	if !r.json {
+		r.printText(rec, d.Payload)
+	}
+
+	var err error
+	if r.forward != "" {
+		rec.Forward, err = r.forwardDelivery(ctx, d)
+	}
+
+	if r.json {
+		if !r.quiet && json.Valid(d.Payload) {
+			rec.Payload = d.Payload
+		}
+		if encErr := json.NewEncoder(r.out).Encode(rec); encErr != nil && err == nil {
+			err = encErr
+		}
+	}
+	return err
+}
+
+// printText prints the summary line and, unless quiet, the payload.
+func (r *devReceiver) printText(rec *deliveryRecord, payload []byte) {
+	name := rec.Event
+	if rec.Action != "" {
+		name += "." + rec.Action
+	}
+	fmt.Fprintf(r.out, "%s %s %s", rec.Time.Format(time.TimeOnly), name, rec.DeliveryID)
+	switch {
+	case rec.Repository != "":
+		fmt.Fprintf(r.out, " repo=%s", rec.Repository)
+	case rec.Organization != "":
+		fmt.Fprintf(r.out, " org=%s", rec.Organization)
+	}
+	if rec.InstallationID != 0 {
+		fmt.Fprintf(r.out, " installation=%d", rec.InstallationID)
+	}
+	if rec.Sender != "" {
+		fmt.Fprintf(r.out, " sender=%s", rec.Sender)
+	}
+	fmt.Fprintln(r.out)
+
+	if !r.quiet {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, payload, "  ", "  "); err != nil {
+			pretty.Reset()
+			pretty.Write(payload)
+		}
+		fmt.Fprintf(r.out, "  %s\n", pretty.Bytes())
+	}
+}
+
+// forwardDelivery sends d to the forward URL, re-signed with the secret.
+func (r *devReceiver) forwardDelivery(ctx context.Context, d *webhook.Delivery) (*forwardRecord, error) {
+	resp, err := sendDelivery(ctx, r.forward, d, r.secret)
+	if err != nil {
+		if !r.json {
+			fmt.Fprintf(r.out, "  forward failed: %v\n", err)
+		}
+		return &forwardRecord{Error: err.Error()}, err
+	}
+	resp.Body.Close()
+	if !r.json {
+		fmt.Fprintf(r.out, "  forwarded: %s\n", resp.Status)
+	}
+	rec := &forwardRecord{StatusCode: resp.StatusCode}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("forward target returned %s", resp.Status)
+		rec.Error = err.Error()
+	}
+	return rec, err
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+func TestDevReceiver(t *testing.T) {
+	ctx := context.Background()
+	target, forwarded := webhookServer(t, "hook")
+	var out bytes.Buffer
+	srv := httptest.NewServer(newDevReceiver(&out, "hook", target.URL, false, false).handler("/webhook"))
+	t.Cleanup(srv.Close)
+
+	payload := []byte(`{"action":"opened","installation":{"id":7},"repository":{"full_name":"octo-org/hello-world"}}`)
+	d := &webhook.Delivery{ID: "d-1", Event: "issues", Payload: payload}
+	resp, err := sendDelivery(ctx, srv.URL+"/webhook", d, "hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", resp.StatusCode, out.String())
+	}
+	for _, want := range []string{"issues.opened d-1 repo=octo-org/hello-world installation=7", `"full_name": "octo-org/hello-world"`, "forwarded: 200 OK"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if got := forwarded(); len(got) != 1 || got[0] != "issues opened 7" {
+		t.Errorf("forwarded = %v, want [issues opened 7]", got)
+	}
+
+	// Deliveries signed with another secret are rejected and not printed
+	out.Reset()
+	resp, err = sendDelivery(ctx, srv.URL+"/webhook", d, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || out.Len() > 0 {
+		t.Errorf("bad signature: status = %d, output = %q", resp.StatusCode, out.String())
+	}
+}
+
+func TestDevReceiver_ForwardFailure(t *testing.T) {
+	target, _ := webhookServer(t, "other")
+	var out bytes.Buffer
+	srv := httptest.NewServer(newDevReceiver(&out, "hook", target.URL, true, false).handler("/webhook"))
+	t.Cleanup(srv.Close)
+
+	d := &webhook.Delivery{ID: "d-1", Event: "ping", Payload: []byte(`{"zen":"hi"}`)}
+	resp, err := sendDelivery(context.Background(), srv.URL+"/webhook", d, "hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 when the forward target rejects the delivery", resp.StatusCode)
+	}
+	if strings.Contains(out.String(), "zen") {
+		t.Errorf("-quiet output includes the payload:\n%s", out.String())
+	}
+}
+
+func TestServe_RequiresDev(t *testing.T) {
+	env := newTestEnv(registeredStore(t))
+	if code := run(context.Background(), env.cliEnv, []string{"serve"}); code != 2 {
+		t.Errorf("serve without -dev exit = %d, want 2", code)
+	}
+}
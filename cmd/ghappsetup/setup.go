@@ -0,0 +1,315 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+const (
+	// envConfigFile names the config file main applies; it defaults to
+	// defaultConfigFile when that exists.
+	envConfigFile     = "GHAPPSETUP_CONFIG"
+	defaultConfigFile = "ghappsetup.yaml"
+	defaultSetupAddr  = "localhost:8080"
+)
+
+// setupConfig is the config file "ghappsetup init" writes. It selects the
+// store and GitHub instance and holds the manifest for "ghappsetup setup".
+type setupConfig struct {
+	Storage    storageConfig  `yaml:"storage"`
+	GitHub     githubConfig   `yaml:"github,omitempty"`
+	WebhookURL string         `yaml:"webhook_url,omitempty"`
+	Manifest   map[string]any `yaml:"manifest"`
+}
+
+type storageConfig struct {
+	Mode     string `yaml:"mode"`
+	Dir      string `yaml:"dir,omitempty"`
+	Prefix   string `yaml:"prefix,omitempty"`
+	KMSKeyID string `yaml:"kms_key_id,omitempty"`
+}
+
+type githubConfig struct {
+	URL string `yaml:"url,omitempty"`
+	Org string `yaml:"org,omitempty"`
+}
+
+// readSetupConfig reads and decodes the config file at path.
+func readSetupConfig(path string) (*setupConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg setupConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config %s is not valid YAML: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// writeSetupConfig writes cfg to path. It holds no secrets.
+func writeSetupConfig(path string, cfg *setupConfig) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Written by \"ghappsetup init\". Commands read it from $%s or ./%s.\n", envConfigFile, defaultConfigFile)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// envVars returns the environment variables the config sets, in the form
+// configstore.NewFromEnv and the installer read.
+func (c *setupConfig) envVars() map[string]string {
+	vars := map[string]string{
+		configstore.EnvStorageMode:        c.Storage.Mode,
+		configstore.EnvStorageDir:         c.Storage.Dir,
+		configstore.EnvAWSSSMParameterPfx: c.Storage.Prefix,
+		configstore.EnvAWSSSMKMSKeyID:     c.Storage.KMSKeyID,
+		installer.EnvGitHubURL:            c.GitHub.URL,
+		installer.EnvGitHubOrg:            c.GitHub.Org,
+	}
+	for k, v := range vars {
+		if v == "" {
+			delete(vars, k)
+		}
+	}
+	return vars
+}
+
+// applyConfigFile sets the variables of the config file named by
+// GHAPPSETUP_CONFIG, or of ./ghappsetup.yaml if it exists, that are not
+// already set, so every command uses the store the wizard chose.
+func applyConfigFile() error {
+	path := os.Getenv(envConfigFile)
+	if path == "" {
+		path = defaultConfigFile
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	}
+	cfg, err := readSetupConfig(path)
+	if err != nil {
+		return err
+	}
+	for k, v := range cfg.envVars() {
+		if _, ok := os.LookupEnv(k); !ok {
+			os.Setenv(k, v)
+		}
+	}
+	return nil
+}
+
+// store creates the store the config selects. SSM clients come from env
+// so tests can replace them.
+func (c *setupConfig) store(ctx context.Context, env *cliEnv) (configstore.Store, error) {
+	switch c.Storage.Mode {
+	case configstore.StorageModeEnvFile, "":
+		return configstore.NewLocalEnvFileStore(defaultString(c.Storage.Dir, "./.env")), nil
+	case configstore.StorageModeFiles:
+		return configstore.NewLocalFileStore(defaultString(c.Storage.Dir, "./.env")), nil
+	case configstore.StorageModeAWSSSM:
+		if c.Storage.Prefix == "" {
+			return nil, errors.New("storage.prefix is required for aws-ssm storage")
+		}
+		tags, err := tagsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		client, err := env.newSSMClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts := []configstore.SSMStoreOption{configstore.WithSSMClient(client)}
+		if c.Storage.KMSKeyID != "" {
+			opts = append(opts, configstore.WithKMSKey(c.Storage.KMSKeyID))
+		}
+		if len(tags) > 0 {
+			opts = append(opts, configstore.WithTags(tags))
+		}
+		return configstore.NewAWSSSMStore(c.Storage.Prefix, opts...)
+	default:
+		return nil, fmt.Errorf("unknown storage.mode %q (expected %s, %s, or %s)", c.Storage.Mode,
+			configstore.StorageModeEnvFile, configstore.StorageModeFiles, configstore.StorageModeAWSSSM)
+	}
+}
+
+// manifest validates the config's manifest.
+func (c *setupConfig) manifest() (installer.Manifest, error) {
+	if len(c.Manifest) == 0 {
+		return installer.Manifest{}, errors.New("config has no manifest")
+	}
+	m, problems := validateManifest(c.Manifest)
+	var fatal []string
+	for _, p := range problems {
+		if p.fatal {
+			fatal = append(fatal, p.msg)
+		}
+	}
+	if len(fatal) > 0 {
+		return m, fmt.Errorf("invalid manifest:\n  %s", strings.Join(fatal, "\n  "))
+	}
+	return m, nil
+}
+
+func defaultString(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// setupResult is the -json output of setup, and of init when it creates
+// the app.
+type setupResult struct {
+	AppID   int64     `json:"app_id"`
+	AppSlug string    `json:"app_slug"`
+	HTMLURL string    `json:"html_url,omitempty"`
+	Store   storeInfo `json:"store"`
+	// Created is false if the store already held an app.
+	Created bool `json:"created"`
+
+	storeDesc string
+}
+
+func (r *setupResult) print(w io.Writer) {
+	if !r.Created {
+		fmt.Fprintf(w, "app %d is already registered in %s; nothing to set up\n", r.AppID, r.storeDesc)
+		return
+	}
+	fmt.Fprintf(w, "saved app %d (%s) to %s\n", r.AppID, r.AppSlug, r.storeDesc)
+}
+
+// runSetup implements "ghappsetup setup".
+func runSetup(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "setup", "[-config <file>] [-addr <addr>] [-json]")
+	configFile := fs.String("config", defaultString(os.Getenv(envConfigFile), defaultConfigFile), "config file written by init")
+	addr := fs.String("addr", defaultSetupAddr, "listen address for the installer")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := readSetupConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	result, err := setupApp(ctx, env, cfg, *addr)
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		return writeJSON(env.stdout, result)
+	}
+	result.print(env.stdout)
+	return nil
+}
+
+// setupApp runs the installer for cfg on addr until GitHub has created the
+// app and its credentials are saved, or ctx is done. The URL to open is
+// printed on stderr.
+func setupApp(ctx context.Context, env *cliEnv, cfg *setupConfig, addr string) (*setupResult, error) {
+	m, err := cfg.manifest()
+	if err != nil {
+		return nil, err
+	}
+	store, err := cfg.store(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	result := &setupResult{Store: storeInfoOf(store), storeDesc: describeStore(store)}
+	status, err := store.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installer status: %w", err)
+	}
+	if status.Registered {
+		result.AppID, result.AppSlug, result.HTMLURL = status.AppID, status.AppSlug, status.HTMLURL
+		return result, nil
+	}
+
+	saved := make(chan *configstore.AppCredentials, 1)
+	h, err := installer.New(installer.Config{
+		Store:          store,
+		Manifest:       m,
+		AppDisplayName: m.Name,
+		GitHubURL:      defaultString(cfg.GitHub.URL, "https://github.com"),
+		GitHubOrg:      cfg.GitHub.Org,
+		WebhookURL:     cfg.WebhookURL,
+		OnCredentialsSaved: func(_ context.Context, creds *configstore.AppCredentials) error {
+			select {
+			case saved <- creds:
+			default:
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	logger := clog.New(slog.NewTextHandler(env.stderr, nil))
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return clog.WithLogger(ctx, logger) },
+	}
+	fmt.Fprintf(env.stderr, "open http://%s/setup to create the app on GitHub\n", setupHost(ln.Addr()))
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	var creds *configstore.AppCredentials
+	select {
+	case err := <-errc:
+		return nil, err
+	case creds = <-saved:
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return nil, err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return nil, err
+	}
+	if creds == nil {
+		return nil, errors.New("stopped before the app was created")
+	}
+	result.AppID, result.AppSlug, result.HTMLURL = creds.AppID, creds.AppSlug, creds.HTMLURL
+	result.Created = true
+	return result, nil
+}
+
+// setupHost returns a browsable host:port for a listener address.
+func setupHost(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() || ip.IsLoopback() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
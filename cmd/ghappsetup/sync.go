@@ -0,0 +1,257 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// syncDataLink is the symlink naming the current data directory in the
+// files layout, as in a mounted Kubernetes Secret.
+const syncDataLink = "..data"
+
+// runSync implements "ghappsetup sync".
+//
+// It copies the credentials from the configured store to a shared volume
+// for workloads that cannot read the store themselves. Without -watch it
+// writes once and exits, for an init container; with -watch it keeps the
+// copy current, for a sidecar.
+func runSync(ctx context.Context, env *cliEnv, args []string) error {
+	flags := newFlagSet(env, "sync", "-out <path> [-layout files|envfile] [-file-mode <mode>] [-watch [-interval <d>]] [-json]")
+	out := flags.String("out", "", "directory (files) or file (envfile) to write (required)")
+	layout := flags.String("layout", configstore.StorageModeFiles, "files, one file per value like the files store, or envfile, a single .env file")
+	fileMode := flags.String("file-mode", "", "permissions of the written files in octal, e.g. 0640 (default: as the store writes them)")
+	watch := flags.Bool("watch", false, "keep running and rewrite the output when the credentials change")
+	interval := flags.Duration("interval", time.Minute, "time between store reads with -watch")
+	jsonOut := jsonFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return usagef("-out is required")
+	}
+	if *layout != configstore.StorageModeFiles && *layout != configstore.StorageModeEnvFile {
+		return usagef("unknown -layout %q (expected files or envfile)", *layout)
+	}
+	if *interval <= 0 {
+		return usagef("-interval must be positive")
+	}
+	w := &syncWriter{out: *out, layout: *layout}
+	if *fileMode != "" {
+		mode, err := strconv.ParseUint(*fileMode, 8, 32)
+		if err != nil || mode > 0777 {
+			return usagef("invalid -file-mode %q", *fileMode)
+		}
+		w.mode = fs.FileMode(mode)
+	}
+
+	store, err := env.newStore()
+	if err != nil {
+		return err
+	}
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return errors.New("store cannot load credentials")
+	}
+
+	report := func(creds *configstore.AppCredentials) error {
+		result := syncResult{AppID: creds.AppID, Path: *out, Layout: *layout, Time: time.Now().UTC()}
+		switch {
+		case *jsonOut && *watch:
+			return json.NewEncoder(env.stdout).Encode(result)
+		case *jsonOut:
+			return writeJSON(env.stdout, result)
+		default:
+			fmt.Fprintf(env.stderr, "wrote the credentials of app %d to %s\n", creds.AppID, *out)
+			return nil
+		}
+	}
+
+	if !*watch {
+		creds, err := loader.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load credentials from %s: %w", describeStore(store), err)
+		}
+		if err := w.write(ctx, creds); err != nil {
+			return err
+		}
+		return report(creds)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	var last *configstore.AppCredentials
+	for {
+		creds, err := loader.Load(ctx)
+		switch {
+		case err != nil:
+			// Keep the last copy; the store may be briefly unavailable or
+			// not registered yet
+			fmt.Fprintf(env.stderr, "failed to load credentials from %s: %v\n", describeStore(store), err)
+		case !reflect.DeepEqual(creds, last):
+			if err := w.write(ctx, creds); err != nil {
+				fmt.Fprintf(env.stderr, "%v\n", err)
+				break
+			}
+			last = creds
+			if err := report(creds); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncResult is the -json output of sync, printed for each write. With
+// -watch it is printed as one line per write.
+type syncResult struct {
+	AppID  int64     `json:"app_id"`
+	Path   string    `json:"path"`
+	Layout string    `json:"layout"`
+	Time   time.Time `json:"time"`
+}
+
+// syncWriter replaces the credentials at out atomically, so readers never
+// see a partial copy and file watchers see a single rename.
+type syncWriter struct {
+	out    string
+	layout string
+	mode   fs.FileMode // 0 keeps the store's permissions
+}
+
+func (w *syncWriter) write(ctx context.Context, creds *configstore.AppCredentials) error {
+	if w.layout == configstore.StorageModeEnvFile {
+		return w.writeEnvFile(ctx, creds)
+	}
+	return w.writeFiles(ctx, creds)
+}
+
+// writeEnvFile writes a new .env file next to out and renames it over
+// out.
+func (w *syncWriter) writeEnvFile(ctx context.Context, creds *configstore.AppCredentials) error {
+	dir := filepath.Dir(w.out)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(w.out)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.out, err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := configstore.NewLocalEnvFileStore(tmp.Name()).Save(ctx, creds); err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.out, err)
+	}
+	if err := w.chmod(tmp.Name()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), w.out); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", w.out, err)
+	}
+	return nil
+}
+
+// writeFiles lays out out like a mounted Kubernetes Secret: the files are
+// written to a new hidden directory, the ..data symlink is swapped to it
+// with a rename, and each file name is a symlink through ..data. The files
+// store and readers of mounted Secrets can read the result.
+func (w *syncWriter) writeFiles(ctx context.Context, creds *configstore.AppCredentials) error {
+	if err := os.MkdirAll(w.out, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", w.out, err)
+	}
+	data, err := os.MkdirTemp(w.out, ".."+time.Now().UTC().Format("2006_01_02_15_04_05."))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.out, err)
+	}
+	if err := configstore.NewLocalFileStore(data).Save(ctx, creds); err != nil {
+		os.RemoveAll(data)
+		return fmt.Errorf("failed to write %s: %w", w.out, err)
+	}
+	if err := os.Chmod(data, 0755); err != nil {
+		os.RemoveAll(data)
+		return err
+	}
+	entries, err := os.ReadDir(data)
+	if err != nil {
+		os.RemoveAll(data)
+		return err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		names[entry.Name()] = true
+		if err := w.chmod(filepath.Join(data, entry.Name())); err != nil {
+			os.RemoveAll(data)
+			return err
+		}
+	}
+
+	tmpLink := filepath.Join(w.out, syncDataLink+"_tmp")
+	os.Remove(tmpLink)
+	if err := os.Symlink(filepath.Base(data), tmpLink); err != nil {
+		os.RemoveAll(data)
+		return fmt.Errorf("failed to link %s: %w", data, err)
+	}
+	if err := os.Rename(tmpLink, filepath.Join(w.out, syncDataLink)); err != nil {
+		os.Remove(tmpLink)
+		os.RemoveAll(data)
+		return fmt.Errorf("failed to swap %s: %w", syncDataLink, err)
+	}
+
+	// Link new names, and drop the links and data of earlier writes
+	current, err := os.ReadDir(w.out)
+	if err != nil {
+		return err
+	}
+	for _, entry := range current {
+		name, path := entry.Name(), filepath.Join(w.out, entry.Name())
+		switch {
+		case name == syncDataLink || name == filepath.Base(data):
+		case strings.HasPrefix(name, ".."):
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		case entry.Type()&fs.ModeSymlink == 0:
+			if names[name] {
+				return fmt.Errorf("%s is not a link written by sync; use an empty directory for -out", path)
+			}
+		case !names[name]:
+			if target, _ := os.Readlink(path); strings.HasPrefix(target, syncDataLink+string(filepath.Separator)) {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+			}
+		}
+		delete(names, name)
+	}
+	for name := range names {
+		if err := os.Symlink(filepath.Join(syncDataLink, name), filepath.Join(w.out, name)); err != nil {
+			return fmt.Errorf("failed to link %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (w *syncWriter) chmod(path string) error {
+	if w.mode == 0 {
+		return nil
+	}
+	return os.Chmod(path, w.mode)
+}
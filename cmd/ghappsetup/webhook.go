@@ -0,0 +1,233 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+// samplePayloads holds a sample delivery body for each event "webhook
+// send" can craft, named <event>.json.
+//
+//go:embed payloads/*.json
+var samplePayloads embed.FS
+
+// webhookSendTimeout bounds each delivery, as GitHub does.
+const webhookSendTimeout = 10 * time.Second
+
+// sampleEvents returns the events with a sample payload.
+func sampleEvents() []string {
+	entries, _ := fs.ReadDir(samplePayloads, "payloads")
+	events := make([]string, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	return events
+}
+
+// runWebhook implements "ghappsetup webhook".
+func runWebhook(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) == 0 {
+		return usagef("expected a subcommand: send")
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(env.stderr, "Usage: ghappsetup webhook send -event <name> [-url <url>] [-payload <file>] [-secret-env <name>]")
+		return flag.ErrHelp
+	case "send":
+		return runWebhookSend(ctx, env, args[1:])
+	default:
+		return usagef("unknown subcommand %q (expected send)", args[0])
+	}
+}
+
+// runWebhookSend implements "ghappsetup webhook send". It POSTs a sample
+// or given payload with the headers GitHub sends, signed with the stored
+// webhook secret unless -secret-env names another.
+func runWebhookSend(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newFlagSet(env, "webhook send", "-event <name> [-url <url>] [-payload <file>] [-secret-env <name>] [-json]")
+	event := fs.String("event", "", fmt.Sprintf("event to send (required); samples: %s", strings.Join(sampleEvents(), ", ")))
+	url := fs.String("url", "http://localhost:8080/webhook", "webhook URL")
+	payloadFile := fs.String("payload", "", `payload file instead of the sample, or "-" for stdin`)
+	action := fs.String("action", "", "set the payload's action, e.g. closed")
+	installationID := fs.Int64("installation-id", 0, "set the payload's installation ID")
+	secretEnv := fs.String("secret-env", "", "environment variable holding the secret (default: the stored webhook secret)")
+	jsonOut := jsonFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *event == "" {
+		return usagef("-event is required")
+	}
+
+	payload, err := webhookPayload(env, *event, *payloadFile)
+	if err != nil {
+		return err
+	}
+	if *action != "" || *installationID != 0 {
+		if payload, err = overridePayload(payload, *action, *installationID); err != nil {
+			return err
+		}
+	}
+
+	secret, err := webhookSecret(ctx, env, *secretEnv)
+	if err != nil {
+		return err
+	}
+
+	deliveryID, err := newDeliveryID()
+	if err != nil {
+		return err
+	}
+	d := &webhook.Delivery{ID: deliveryID, Event: *event, Payload: payload}
+	resp, err := sendDelivery(ctx, *url, d, secret)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	body = bytes.TrimSpace(body)
+
+	if *jsonOut {
+		err := writeJSON(env.stdout, webhookSendResult{
+			Event:      *event,
+			DeliveryID: deliveryID,
+			URL:        *url,
+			StatusCode: resp.StatusCode,
+			Response:   string(body),
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(env.stdout, "delivered %s %s to %s: %s\n", *event, deliveryID, *url, resp.Status)
+		if len(body) > 0 {
+			fmt.Fprintf(env.stdout, "%s\n", body)
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookSendResult is the -json output of webhook send. Response holds
+// up to 64 KiB of the response body. It is printed for non-2xx responses
+// too, before the command fails.
+type webhookSendResult struct {
+	Event      string `json:"event"`
+	DeliveryID string `json:"delivery_id"`
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Response   string `json:"response"`
+}
+
+// webhookPayload reads the payload from file, or the sample for event.
+func webhookPayload(env *cliEnv, event, file string) ([]byte, error) {
+	var payload []byte
+	var err error
+	switch file {
+	case "":
+		payload, err = samplePayloads.ReadFile(path.Join("payloads", event+".json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, usagef("no sample payload for %s (samples: %s); pass one with -payload",
+				event, strings.Join(sampleEvents(), ", "))
+		}
+	case "-":
+		payload, err = io.ReadAll(env.stdin)
+	default:
+		payload, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	if !json.Valid(payload) {
+		return nil, errors.New("payload is not valid JSON")
+	}
+	return payload, nil
+}
+
+// overridePayload sets the action and installation ID in payload.
+func overridePayload(payload []byte, action string, installationID int64) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	if action != "" {
+		doc["action"] = action
+	}
+	if installationID != 0 {
+		inst, _ := doc["installation"].(map[string]any)
+		if inst == nil {
+			inst = map[string]any{}
+		}
+		inst["id"] = installationID
+		doc["installation"] = inst
+	}
+	return json.Marshal(doc)
+}
+
+// sendDelivery POSTs d to url with the headers GitHub sends, signed with
+// secret.
+func sendDelivery(ctx context.Context, url string, d *webhook.Delivery, secret string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GitHub-Hookshot/ghappsetup")
+	req.Header.Set(webhook.HeaderEvent, d.Event)
+	req.Header.Set(webhook.HeaderDelivery, d.ID)
+	if d.HookID != "" {
+		req.Header.Set(webhook.HeaderHookID, d.HookID)
+	}
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(d.Payload, secret))
+
+	client := &http.Client{Timeout: webhookSendTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deliver to %s: %w", url, err)
+	}
+	return resp, nil
+}
+
+// webhookSecret reads the secret from the environment variable secretEnv
+// if set, otherwise from the configured store.
+func webhookSecret(ctx context.Context, env *cliEnv, secretEnv string) (string, error) {
+	if secretEnv != "" {
+		return secretFromEnv(secretEnv)
+	}
+	_, creds, err := loadCredentials(ctx, env)
+	if err != nil {
+		return "", fmt.Errorf("%w (use -secret-env to use another secret)", err)
+	}
+	return creds.WebhookSecret, nil
+}
+
+// newDeliveryID returns a random GUID like those GitHub assigns.
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
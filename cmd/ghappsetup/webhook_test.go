@@ -0,0 +1,96 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+// webhookServer verifies deliveries with secret and records each as
+// "event action installation".
+func webhookServer(t *testing.T, secret string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []string
+	router := webhook.NewRouter()
+	router.Fallback(func(ctx context.Context, d *webhook.Delivery) error {
+		if _, err := d.Parse(); err != nil {
+			return err
+		}
+		c, err := d.Common()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, fmt.Sprintf("%s %s %d", d.Event, c.Action, c.Installation.ID))
+		return nil
+	})
+	srv := httptest.NewServer(webhook.NewHandler(router.Dispatch, webhook.WithSecret(secret)))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), got...)
+	}
+}
+
+func TestWebhookSend_Samples(t *testing.T) {
+	srv, deliveries := webhookServer(t, "hook")
+	events := sampleEvents()
+	for _, event := range events {
+		env := newTestEnv(registeredStore(t))
+		if code := run(context.Background(), env.cliEnv, []string{"webhook", "send", "-event", event, "-url", srv.URL}); code != 0 {
+			t.Errorf("webhook send -event %s exit = %d\nstdout: %s\nstderr: %s", event, code, env.stdout, env.stderr)
+		}
+	}
+	if got := deliveries(); len(got) != len(events) {
+		t.Errorf("server accepted %d of %d samples: %v", len(got), len(events), got)
+	}
+}
+
+func TestWebhookSend_Overrides(t *testing.T) {
+	t.Setenv("STAGING_WEBHOOK_SECRET", "staging")
+	srv, deliveries := webhookServer(t, "staging")
+	payload := filepath.Join(t.TempDir(), "payload.json")
+	if err := os.WriteFile(payload, []byte(`{"action":"labeled","installation":{"id":1}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := newTestEnv(nil)
+	args := []string{"webhook", "send", "-event", "issues", "-payload", payload, "-action", "closed", "-installation-id", "99",
+		"-secret-env", "STAGING_WEBHOOK_SECRET", "-url", srv.URL}
+	if code := run(context.Background(), env.cliEnv, args); code != 0 {
+		t.Fatalf("webhook send exit = %d\nstderr: %s", code, env.stderr)
+	}
+	if got := deliveries(); len(got) != 1 || got[0] != "issues closed 99" {
+		t.Errorf("deliveries = %v, want [issues closed 99]", got)
+	}
+}
+
+func TestWebhookSend_Rejected(t *testing.T) {
+	srv, _ := webhookServer(t, "other")
+
+	env := newTestEnv(registeredStore(t))
+	if code := run(context.Background(), env.cliEnv, []string{"webhook", "send", "-event", "ping", "-url", srv.URL}); code != 1 {
+		t.Fatalf("webhook send exit = %d, want 1", code)
+	}
+	if !strings.Contains(env.stdout.String(), "401") {
+		t.Errorf("output does not show the response status:\n%s", env.stdout)
+	}
+
+	env = newTestEnv(nil)
+	if code := run(context.Background(), env.cliEnv, []string{"webhook", "send", "-event", "deployment", "-url", srv.URL}); code != 2 {
+		t.Errorf("webhook send for an event without a sample exit = %d, want 2", code)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package configmap reads non-secret Runtime settings, such as the app
+// manifest, allowed paths, and retry tuning, from a YAML or JSON file
+// mounted from a Kubernetes ConfigMap, and reloads them when the ConfigMap
+// is updated. Set the file as ghappsetup.Config.SettingsFile, or name it in
+// GHAPPSETUP_SETTINGS_FILE; Watcher can also be used on its own.
+package configmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// Settings are the non-secret settings read from a settings file. Zero
+// values mean the setting is not set in the file:
+//
+//	manifest:
+//	  name: my-app
+//	  url: https://example.com
+//	  default_permissions:
+//	    contents: read
+//	  default_events: [push]
+//	allowed_paths: [/healthz, GET /status]
+//	gated_paths: [/webhook]
+//	max_retries: 60
+//	retry_interval: 5s
+//	max_wait: 5m
+//	reload_cooldown: 30s
+type Settings struct {
+	// Manifest is the GitHub App manifest offered by the installer.
+	Manifest *installer.Manifest `json:"manifest,omitempty"`
+
+	// AllowedPaths and GatedPaths use the syntax of
+	// ghappsetup.Config.AllowedPaths and ghappsetup.Config.GatedPaths.
+	AllowedPaths []string `json:"allowed_paths,omitempty"`
+	GatedPaths   []string `json:"gated_paths,omitempty"`
+
+	// MaxRetries, RetryInterval, MaxWait, and ReloadCooldown tune the
+	// Runtime's startup wait and reload rate limiting.
+	MaxRetries     int      `json:"max_retries,omitempty"`
+	RetryInterval  Duration `json:"retry_interval,omitempty"`
+	MaxWait        Duration `json:"max_wait,omitempty"`
+	ReloadCooldown Duration `json:"reload_cooldown,omitempty"`
+}
+
+// Duration is a time.Duration written in settings files as a Go duration
+// string such as "2s" or "5m".
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("duration %q must not be negative", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON formats the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load reads and parses the settings file at path.
+func Load(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses settings from a YAML or JSON document. Unknown fields are
+// rejected so that misspelled settings are not silently ignored. An empty
+// document yields empty settings.
+func Parse(data []byte) (*Settings, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	if doc == nil {
+		return &Settings{}, nil
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return nil, errors.New("failed to parse settings: document must be a mapping")
+	}
+
+	// Settings use the JSON field names of installer.Manifest, so the YAML
+	// document is decoded through JSON
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	var s Settings
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	if s.MaxRetries < 0 {
+		return nil, errors.New("invalid settings: max_retries must not be negative")
+	}
+	return &s, nil
+}
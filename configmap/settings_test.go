@@ -0,0 +1,100 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configmap
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    *Settings
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			doc: `
+manifest:
+  name: my-app
+  url: https://example.com
+  default_permissions:
+    contents: read
+  default_events: [push]
+allowed_paths: [/healthz, GET /status]
+gated_paths: [/webhook]
+max_retries: 60
+retry_interval: 5s
+max_wait: 5m
+reload_cooldown: 30s
+`,
+			want: &Settings{
+				AllowedPaths:   []string{"/healthz", "GET /status"},
+				GatedPaths:     []string{"/webhook"},
+				MaxRetries:     60,
+				RetryInterval:  Duration(5 * time.Second),
+				MaxWait:        Duration(5 * time.Minute),
+				ReloadCooldown: Duration(30 * time.Second),
+			},
+		},
+		{
+			name: "json",
+			doc:  `{"allowed_paths": ["/healthz"], "retry_interval": "1s"}`,
+			want: &Settings{AllowedPaths: []string{"/healthz"}, RetryInterval: Duration(time.Second)},
+		},
+		{name: "empty", doc: "", want: &Settings{}},
+		{name: "unknown field", doc: "allowed_path: [/healthz]", wantErr: true},
+		{name: "numeric duration", doc: "retry_interval: 5", wantErr: true},
+		{name: "invalid duration", doc: "max_wait: soon", wantErr: true},
+		{name: "negative duration", doc: "max_wait: -1s", wantErr: true},
+		{name: "negative retries", doc: "max_retries: -1", wantErr: true},
+		{name: "not a mapping", doc: "- /healthz", wantErr: true},
+		{name: "invalid yaml", doc: "allowed_paths: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.name == "yaml" {
+				m := got.Manifest
+				if m == nil || m.Name != "my-app" || m.DefaultPerms["contents"] != "read" || len(m.DefaultEvents) != 1 {
+					t.Errorf("Manifest = %+v, want parsed manifest", m)
+				}
+				got.Manifest = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of missing file should return error")
+	}
+
+	if err := os.WriteFile(path, []byte("max_retries: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if s.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want 3", s.MaxRetries)
+	}
+}
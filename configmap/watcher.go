@@ -0,0 +1,193 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configmap
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// dataDir is the symlink Kubernetes swaps atomically to publish a new
+	// version of a ConfigMap volume. Files in the volume are symlinks
+	// through it, so their own paths never see write events.
+	dataDir = "..data"
+
+	// DefaultDebounce is how long Watcher waits after the last file event
+	// before reloading, collapsing the burst of events of one update.
+	DefaultDebounce = 100 * time.Millisecond
+)
+
+// Watcher holds the settings read from a file and reloads them when the
+// file changes. It watches the file's directory, so it follows both
+// Kubernetes ConfigMap volume updates, which replace the "..data" symlink,
+// and files that are written in place or replaced by rename. ConfigMaps
+// mounted with subPath are never updated by the kubelet and are only read
+// once.
+type Watcher struct {
+	path     string
+	debounce time.Duration
+	onChange func(*Settings)
+
+	mu       sync.RWMutex
+	settings *Settings
+	sum      [sha256.Size]byte
+}
+
+// WatcherOption is a functional option for configuring Watcher.
+type WatcherOption func(*Watcher)
+
+// WithOnChange sets a function called with the new settings after every
+// reload that changes the file's content. It is not called for the
+// initial load.
+func WithOnChange(fn func(*Settings)) WatcherOption {
+	return func(w *Watcher) {
+		w.onChange = fn
+	}
+}
+
+// WithDebounce sets how long to wait after the last file event before
+// reloading. Defaults to DefaultDebounce.
+func WithDebounce(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// NewWatcher creates a Watcher for the settings file at path and loads it.
+// It returns an error if the file cannot be read or parsed. Call Run to
+// watch for changes.
+func NewWatcher(path string, opts ...WatcherOption) (*Watcher, error) {
+	w := &Watcher{
+		path:     filepath.Clean(path),
+		debounce: DefaultDebounce,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	settings, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", w.path, err)
+	}
+	w.settings = settings
+	w.sum = sha256.Sum256(data)
+	return w, nil
+}
+
+// Path returns the path of the settings file.
+func (w *Watcher) Path() string {
+	return w.path
+}
+
+// Settings returns the most recently loaded settings. The result must not
+// be modified.
+func (w *Watcher) Settings() *Settings {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.settings
+}
+
+// Run watches the settings file and reloads it on changes until ctx is
+// canceled. A file that cannot be read or parsed is logged and the
+// previous settings are kept. Run returns an error only if the watch
+// cannot be set up.
+func (w *Watcher) Run(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer fw.Close()
+
+	if err := fw.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(w.path), err)
+	}
+
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			if w.relevant(ev) {
+				timer.Reset(w.debounce)
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnf("[configmap] file watcher error: %v", err)
+		case <-timer.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+// relevant reports whether ev may have changed the settings file: a new
+// "..data" symlink in a ConfigMap volume, or a change to the file itself.
+func (w *Watcher) relevant(ev fsnotify.Event) bool {
+	name := filepath.Clean(ev.Name)
+	if filepath.Base(name) == dataDir {
+		return ev.Has(fsnotify.Create)
+	}
+	return name == w.path && (ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create) ||
+		ev.Has(fsnotify.Rename) || ev.Has(fsnotify.Remove))
+}
+
+// reload re-reads the settings file, keeping the previous settings if it
+// cannot be read or parsed, and calls the OnChange function if its content
+// changed.
+func (w *Watcher) reload(ctx context.Context) {
+	log := clog.FromContext(ctx)
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		log.Errorf("[configmap] keeping previous settings, failed to read %s: %v", w.path, err)
+		return
+	}
+	sum := sha256.Sum256(data)
+
+	w.mu.Lock()
+	if bytes.Equal(sum[:], w.sum[:]) {
+		w.mu.Unlock()
+		return
+	}
+	settings, err := Parse(data)
+	if err != nil {
+		w.mu.Unlock()
+		log.Errorf("[configmap] keeping previous settings, invalid %s: %v", w.path, err)
+		return
+	}
+	w.settings = settings
+	w.sum = sum
+	w.mu.Unlock()
+
+	log.Infof("[configmap] reloaded settings from %s", w.path)
+	if w.onChange != nil {
+		w.onChange(settings)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// configMapVolume lays out a directory the way the kubelet publishes a
+// ConfigMap volume: each key is a symlink through "..data", which points
+// at a timestamped directory holding the current version.
+type configMapVolume struct {
+	t       *testing.T
+	dir     string
+	version int
+}
+
+func newConfigMapVolume(t *testing.T, content string) *configMapVolume {
+	t.Helper()
+	v := &configMapVolume{t: t, dir: t.TempDir()}
+	v.update(content)
+	if err := os.Symlink(filepath.Join(dataDir, "settings.yaml"), v.path()); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func (v *configMapVolume) path() string {
+	return filepath.Join(v.dir, "settings.yaml")
+}
+
+// update writes a new version and atomically swaps "..data" to it.
+func (v *configMapVolume) update(content string) {
+	v.t.Helper()
+	v.version++
+	version := filepath.Join(v.dir, fmt.Sprintf("..2025_01_01_00_00_%02d", v.version))
+	if err := os.Mkdir(version, 0o755); err != nil {
+		v.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(version, "settings.yaml"), []byte(content), 0o644); err != nil {
+		v.t.Fatal(err)
+	}
+	tmp := filepath.Join(v.dir, "..data_tmp")
+	if err := os.Symlink(filepath.Base(version), tmp); err != nil {
+		v.t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(v.dir, dataDir)); err != nil {
+		v.t.Fatal(err)
+	}
+}
+
+// runWatcher starts w and returns a channel receiving changed settings.
+func runWatcher(t *testing.T, path string) (*Watcher, <-chan *Settings) {
+	t.Helper()
+	changes := make(chan *Settings, 10)
+	w, err := NewWatcher(path,
+		WithDebounce(10*time.Millisecond),
+		WithOnChange(func(s *Settings) { changes <- s }),
+	)
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+	// Give Run time to set up the watch before the test changes files
+	time.Sleep(50 * time.Millisecond)
+	return w, changes
+}
+
+func waitChange(t *testing.T, changes <-chan *Settings) *Settings {
+	t.Helper()
+	select {
+	case s := <-changes:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for settings change")
+		return nil
+	}
+}
+
+func expectNoChange(t *testing.T, changes <-chan *Settings) {
+	t.Helper()
+	select {
+	case s := <-changes:
+		t.Fatalf("unexpected settings change: %+v", s)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatcher_ConfigMapUpdate(t *testing.T) {
+	vol := newConfigMapVolume(t, "max_retries: 1\n")
+	w, changes := runWatcher(t, vol.path())
+
+	if got := w.Settings().MaxRetries; got != 1 {
+		t.Fatalf("initial MaxRetries = %d, want 1", got)
+	}
+
+	vol.update("max_retries: 2\nallowed_paths: [/status]\n")
+	s := waitChange(t, changes)
+	if s.MaxRetries != 2 || len(s.AllowedPaths) != 1 {
+		t.Errorf("changed settings = %+v, want max_retries 2 and /status allowed", s)
+	}
+	if w.Settings() != s {
+		t.Error("Settings() does not return the reloaded settings")
+	}
+
+	// An update with the same content is not reported
+	vol.update("max_retries: 2\nallowed_paths: [/status]\n")
+	expectNoChange(t, changes)
+
+	// An invalid update keeps the previous settings
+	vol.update("max_retries: [")
+	expectNoChange(t, changes)
+	if got := w.Settings().MaxRetries; got != 2 {
+		t.Errorf("MaxRetries after invalid update = %d, want 2", got)
+	}
+
+	vol.update("max_retries: 3\n")
+	if s := waitChange(t, changes); s.MaxRetries != 3 {
+		t.Errorf("MaxRetries after recovery = %d, want 3", s.MaxRetries)
+	}
+}
+
+func TestWatcher_PlainFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.yaml")
+	if err := os.WriteFile(path, []byte("max_retries: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, changes := runWatcher(t, path)
+
+	// Unrelated files in the directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expectNoChange(t, changes)
+
+	if err := os.WriteFile(path, []byte("max_retries: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if s := waitChange(t, changes); s.MaxRetries != 2 {
+		t.Errorf("MaxRetries after write = %d, want 2", s.MaxRetries)
+	}
+
+	// Editors and config management replace files by rename
+	tmp := filepath.Join(dir, "settings.yaml.tmp")
+	if err := os.WriteFile(tmp, []byte("max_retries: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	if s := waitChange(t, changes); s.MaxRetries != 3 {
+		t.Errorf("MaxRetries after rename = %d, want 3", s.MaxRetries)
+	}
+}
+
+func TestNewWatcher_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewWatcher(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("NewWatcher() of missing file should return error")
+	}
+
+	path := filepath.Join(dir, "settings.yaml")
+	if err := os.WriteFile(path, []byte("unknown: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWatcher(path); err == nil {
+		t.Error("NewWatcher() of invalid file should return error")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// PlaceholderValue is the value Bootstrap writes to parameters that do
+// not exist yet. AWSSSMStore treats a parameter holding it as missing, so
+// a bootstrapped prefix reports as not registered until the first Save.
+const PlaceholderValue = "ghappsetup:placeholder"
+
+// bootstrapKeys are the parameters Bootstrap creates: everything the
+// store writes, apart from custom fields.
+var bootstrapKeys = append(append([]string{}, credentialKeys...),
+	EnvGitHubAppInstallerEnabled,
+	EnvGitHubAppInstallations,
+)
+
+// BootstrapResult lists the full names of the parameters Bootstrap
+// created and of those that already existed and were left unchanged.
+type BootstrapResult struct {
+	Created []string
+	Existed []string
+}
+
+type bootstrapConfig struct {
+	policies string
+	extra    []string
+}
+
+// BootstrapOption is a functional option for configuring Bootstrap.
+type BootstrapOption func(*bootstrapConfig)
+
+// WithParameterPolicies attaches SSM parameter policies (a JSON array,
+// e.g. an ExpirationNotification) to the created parameters. Parameter
+// policies require the Advanced tier, which is selected when they are
+// set.
+func WithParameterPolicies(policies string) BootstrapOption {
+	return func(c *bootstrapConfig) {
+		c.policies = policies
+	}
+}
+
+// WithExtraParameters also creates parameters for custom fields the app
+// will save, by name relative to the prefix.
+func WithExtraParameters(names ...string) BootstrapOption {
+	return func(c *bootstrapConfig) {
+		c.extra = append(c.extra, names...)
+	}
+}
+
+// Bootstrap creates every parameter the store writes, holding
+// PlaceholderValue, with the store's KMS key and tags. It lets IAM
+// policies and infrastructure code reference the parameters before the
+// app is registered. Existing parameters are never overwritten, so
+// Bootstrap is safe to run against a registered prefix.
+func (s *AWSSSMStore) Bootstrap(ctx context.Context, opts ...BootstrapOption) (*BootstrapResult, error) {
+	cfg := &bootstrapConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	result := &BootstrapResult{}
+	for _, name := range append(append([]string{}, bootstrapKeys...), cfg.extra...) {
+		input := s.putParameterInput(name, PlaceholderValue)
+		input.Overwrite = aws.Bool(false)
+		if cfg.policies != "" {
+			input.Policies = aws.String(cfg.policies)
+			input.Tier = types.ParameterTierAdvanced
+		}
+
+		_, err := s.ssmClient.PutParameter(ctx, input)
+		var exists *types.ParameterAlreadyExists
+		switch {
+		case errors.As(err, &exists):
+			result.Existed = append(result.Existed, s.ParameterPrefix+name)
+		case err != nil:
+			return result, fmt.Errorf("failed to create parameter %s: %w", name, err)
+		default:
+			result.Created = append(result.Created, s.ParameterPrefix+name)
+		}
+	}
+	return result, nil
+}
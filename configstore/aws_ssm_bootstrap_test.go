@@ -0,0 +1,97 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/cruxstack/github-app-setup-go/ssmresolver"
+)
+
+func TestPlaceholderValue_MatchesResolver(t *testing.T) {
+	// The resolver must recognize the placeholders Bootstrap writes
+	if PlaceholderValue != ssmresolver.PlaceholderValue {
+		t.Errorf("PlaceholderValue = %q, ssmresolver.PlaceholderValue = %q", PlaceholderValue, ssmresolver.PlaceholderValue)
+	}
+}
+
+func TestAWSSSMStore_Bootstrap(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockSSMClient()
+	mock.parameters["/prefix/GITHUB_APP_ID"] = "12345"
+	store, err := NewAWSSSMStore("/prefix/", WithSSMClient(mock), WithKMSKey("alias/app"), WithTags(map[string]string{"team": "platform"}))
+	if err != nil {
+		t.Fatalf("NewAWSSSMStore() error = %v", err)
+	}
+
+	result, err := store.Bootstrap(ctx,
+		WithParameterPolicies(`[{"Type":"NoChangeNotification"}]`),
+		WithExtraParameters("SLACK_TOKEN"))
+	if err != nil {
+		t.Fatalf("Bootstrap() error = %v", err)
+	}
+	if len(result.Existed) != 1 || result.Existed[0] != "/prefix/GITHUB_APP_ID" {
+		t.Errorf("Existed = %v, want [/prefix/GITHUB_APP_ID]", result.Existed)
+	}
+	if want := len(bootstrapKeys); len(result.Created) != want {
+		t.Errorf("Created %d parameters, want %d: %v", len(result.Created), want, result.Created)
+	}
+	if mock.parameters["/prefix/GITHUB_APP_ID"] != "12345" {
+		t.Error("Bootstrap overwrote an existing parameter")
+	}
+	if mock.parameters["/prefix/SLACK_TOKEN"] != PlaceholderValue {
+		t.Error("extra parameter was not created")
+	}
+
+	for _, call := range mock.putCalls {
+		if aws.ToBool(call.Overwrite) {
+			t.Errorf("parameter %s written with Overwrite", *call.Name)
+		}
+		if call.Type != types.ParameterTypeSecureString || aws.ToString(call.KeyId) != "alias/app" {
+			t.Errorf("parameter %s type = %s, key = %s", *call.Name, call.Type, aws.ToString(call.KeyId))
+		}
+		if len(call.Tags) != 1 || call.Tier != types.ParameterTierAdvanced || call.Policies == nil {
+			t.Errorf("parameter %s tags = %v, tier = %s, policies = %v", *call.Name, call.Tags, call.Tier, call.Policies)
+		}
+	}
+}
+
+func TestAWSSSMStore_PlaceholdersAreMissing(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockSSMClient()
+	store, err := NewAWSSSMStore("/prefix/", WithSSMClient(mock))
+	if err != nil {
+		t.Fatalf("NewAWSSSMStore() error = %v", err)
+	}
+	if _, err := store.Bootstrap(ctx); err != nil {
+		t.Fatalf("Bootstrap() error = %v", err)
+	}
+
+	status, err := store.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Registered {
+		t.Error("bootstrapped store reports registered")
+	}
+	if _, err := store.Load(ctx); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Load() error = %v, want ErrNotRegistered", err)
+	}
+	if list, err := store.Installations(ctx); err != nil || list != nil {
+		t.Errorf("Installations() = %v, %v; want none", list, err)
+	}
+
+	creds := &AppCredentials{AppID: 1, ClientID: "c", ClientSecret: "s", WebhookSecret: "w", PrivateKey: "k"}
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if status, err := store.Status(ctx); err != nil || !status.Registered {
+		t.Errorf("Status() after Save = %+v, %v; want registered", status, err)
+	}
+}
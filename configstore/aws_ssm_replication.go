@@ -0,0 +1,137 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ssmReplica is a secondary region AWSSSMStore replicates writes to.
+type ssmReplica struct {
+	region   string
+	kmsKeyID string
+	client   SSMClient
+}
+
+// ReplicationError is returned by AWSSSMStore writes that succeeded in the
+// primary region but could not be replicated to every replica region.
+type ReplicationError struct {
+	// Regions maps each replica region that failed to its error.
+	Regions map[string]error
+}
+
+// Error lists the failed regions in order.
+func (e *ReplicationError) Error() string {
+	parts := make([]string, 0, len(e.Regions))
+	for _, region := range slices.Sorted(maps.Keys(e.Regions)) {
+		parts = append(parts, fmt.Sprintf("%s: %v", region, e.Regions[region]))
+	}
+	return fmt.Sprintf("replication failed in %d region(s): %s", len(e.Regions), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the failed regions.
+func (e *ReplicationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Regions))
+	for _, region := range slices.Sorted(maps.Keys(e.Regions)) {
+		errs = append(errs, e.Regions[region])
+	}
+	return errs
+}
+
+// WithReplicaRegions replicates every write of the store (Save,
+// SaveMetadata, installer flag and installation updates, and Delete) to
+// the same parameters in each region, so disaster recovery regions hold
+// current credentials without a separate sync job. Writes go to the
+// primary region first and then to the replicas concurrently; replica
+// failures are reported as a *ReplicationError. Reads use the primary
+// region only.
+//
+// Replicated parameters are encrypted with the store's KMS key, which
+// must then resolve in every region (an alias or a multi-Region key),
+// unless a key is set per region with WithReplicaKMSKey.
+func WithReplicaRegions(regions ...string) SSMStoreOption {
+	return func(s *AWSSSMStore) {
+		for _, region := range regions {
+			s.replica(region)
+		}
+	}
+}
+
+// WithReplicaKMSKey sets the KMS key for parameters replicated to region,
+// adding it as a replica region if needed.
+func WithReplicaKMSKey(region, keyID string) SSMStoreOption {
+	return func(s *AWSSSMStore) {
+		s.replica(region).kmsKeyID = keyID
+	}
+}
+
+// WithReplicaClient sets a custom SSM client for region, adding it as a
+// replica region if needed.
+func WithReplicaClient(region string, client SSMClient) SSMStoreOption {
+	return func(s *AWSSSMStore) {
+		s.replica(region).client = client
+	}
+}
+
+// ReplicaRegions returns the regions the store replicates writes to.
+func (s *AWSSSMStore) ReplicaRegions() []string {
+	regions := make([]string, len(s.replicas))
+	for i, r := range s.replicas {
+		regions[i] = r.region
+	}
+	return regions
+}
+
+// replica returns the replica for region, adding it if needed.
+func (s *AWSSSMStore) replica(region string) *ssmReplica {
+	region = strings.TrimSpace(region)
+	for _, r := range s.replicas {
+		if r.region == region {
+			return r
+		}
+	}
+	r := &ssmReplica{region: region}
+	s.replicas = append(s.replicas, r)
+	return r
+}
+
+// replicate runs fn for every replica concurrently, returning a
+// *ReplicationError for the regions where it failed.
+func (s *AWSSSMStore) replicate(fn func(r *ssmReplica) error) error {
+	var (
+		mu     sync.Mutex
+		failed = make(map[string]error)
+		wg     sync.WaitGroup
+	)
+	for _, r := range s.replicas {
+		wg.Go(func() {
+			if err := fn(r); err != nil {
+				mu.Lock()
+				failed[r.region] = err
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return &ReplicationError{Regions: failed}
+	}
+	return nil
+}
+
+// parseRegionList splits a comma-separated list of regions.
+func parseRegionList(v string) []string {
+	var regions []string
+	for region := range strings.SplitSeq(v, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// newReplicatedSSMStore creates a store with mock clients for the primary
+// region and each replica region.
+func newReplicatedSSMStore(t *testing.T, opts ...SSMStoreOption) (*AWSSSMStore, *mockSSMClient, map[string]*mockSSMClient) {
+	t.Helper()
+	primary := newMockSSMClient()
+	replicas := map[string]*mockSSMClient{
+		"us-west-2": newMockSSMClient(),
+		"eu-west-1": newMockSSMClient(),
+	}
+	opts = append([]SSMStoreOption{
+		WithSSMClient(primary),
+		WithKMSKey("alias/github-app"),
+		WithReplicaClient("us-west-2", replicas["us-west-2"]),
+		WithReplicaClient("eu-west-1", replicas["eu-west-1"]),
+	}, opts...)
+
+	store, err := NewAWSSSMStore("/app", opts...)
+	if err != nil {
+		t.Fatalf("NewAWSSSMStore() error = %v", err)
+	}
+	return store, primary, replicas
+}
+
+func TestAWSSSMStore_ReplicatesWrites(t *testing.T) {
+	store, primary, replicas := newReplicatedSSMStore(t, WithReplicaKMSKey("eu-west-1", "mrk-eu"))
+	ctx := context.Background()
+
+	if err := store.Save(ctx, &AppCredentials{
+		AppID: 42, ClientID: "id", ClientSecret: "secret",
+		WebhookSecret: "hook", PrivateKey: "key",
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.DisableInstaller(ctx); err != nil {
+		t.Fatalf("DisableInstaller() error = %v", err)
+	}
+	if err := store.SaveInstallation(ctx, Installation{ID: 7, Account: "octo"}); err != nil {
+		t.Fatalf("SaveInstallation() error = %v", err)
+	}
+
+	for region, client := range replicas {
+		for name, want := range primary.parameters {
+			if got := client.parameters[name]; got != want {
+				t.Errorf("%s parameter %s = %q, want %q", region, name, got, want)
+			}
+		}
+	}
+
+	for _, call := range replicas["us-west-2"].putCalls {
+		if got := aws.ToString(call.KeyId); got != "alias/github-app" {
+			t.Errorf("us-west-2 KeyId = %q, want the store's key", got)
+		}
+	}
+	for _, call := range replicas["eu-west-1"].putCalls {
+		if got := aws.ToString(call.KeyId); got != "mrk-eu" {
+			t.Errorf("eu-west-1 KeyId = %q, want the replica key", got)
+		}
+	}
+
+	// Reads use the primary region only
+	if _, err := store.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for region, client := range replicas {
+		if len(client.getCalls) != 0 {
+			t.Errorf("%s received %d reads, want none", region, len(client.getCalls))
+		}
+	}
+
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for region, client := range replicas {
+		if len(client.parameters) != 0 {
+			t.Errorf("%s parameters after Delete() = %v, want none", region, client.parameters)
+		}
+	}
+}
+
+func TestAWSSSMStore_ReplicationError(t *testing.T) {
+	store, primary, replicas := newReplicatedSSMStore(t)
+	replicas["eu-west-1"].putErr = errors.New("AccessDenied")
+
+	err := store.Save(context.Background(), &AppCredentials{AppID: 42, PrivateKey: "key"})
+
+	var replErr *ReplicationError
+	if !errors.As(err, &replErr) {
+		t.Fatalf("Save() error = %v, want *ReplicationError", err)
+	}
+	if len(replErr.Regions) != 1 || replErr.Regions["eu-west-1"] == nil {
+		t.Errorf("Regions = %v, want only eu-west-1", replErr.Regions)
+	}
+	if !strings.Contains(err.Error(), "eu-west-1: failed to save parameter") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Error() = %q, want region and cause", err.Error())
+	}
+
+	// The primary and healthy replica regions were still written
+	if primary.parameters["/app/"+EnvGitHubAppID] != "42" {
+		t.Error("primary region was not written")
+	}
+	if replicas["us-west-2"].parameters["/app/"+EnvGitHubAppID] != "42" {
+		t.Error("healthy replica region was not written")
+	}
+}
+
+func TestAWSSSMStore_PrimaryFailureSkipsReplicas(t *testing.T) {
+	store, primary, replicas := newReplicatedSSMStore(t)
+	primary.putErr = errors.New("throttled")
+
+	err := store.EnableInstaller(context.Background())
+	var replErr *ReplicationError
+	if err == nil || errors.As(err, &replErr) {
+		t.Fatalf("EnableInstaller() error = %v, want primary error", err)
+	}
+	for region, client := range replicas {
+		if len(client.putCalls) != 0 {
+			t.Errorf("%s received %d writes, want none", region, len(client.putCalls))
+		}
+	}
+}
+
+func TestWithReplicaRegions(t *testing.T) {
+	store, err := NewAWSSSMStore("/app",
+		WithSSMClient(newMockSSMClient()),
+		WithReplicaClient("us-west-2", newMockSSMClient()),
+		WithReplicaRegions("us-west-2"),
+		WithReplicaClient("eu-west-1", newMockSSMClient()),
+	)
+	if err != nil {
+		t.Fatalf("NewAWSSSMStore() error = %v", err)
+	}
+	if got := store.ReplicaRegions(); len(got) != 2 || got[0] != "us-west-2" || got[1] != "eu-west-1" {
+		t.Errorf("ReplicaRegions() = %v, want [us-west-2 eu-west-1]", got)
+	}
+
+	if _, err := NewAWSSSMStore("/app", WithSSMClient(newMockSSMClient()), WithReplicaRegions(" ")); err == nil {
+		t.Error("NewAWSSSMStore() with empty replica region should return error")
+	}
+}
@@ -7,13 +7,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
 )
 
 // SSMClient defines the interface for AWS SSM operations.
@@ -24,12 +28,23 @@ type SSMClient interface {
 		optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 }
 
+// SSMParameterDeleter is implemented by SSM clients that can delete
+// parameters, such as *ssm.Client. AWSSSMStore.Delete requires it.
+type SSMParameterDeleter interface {
+	DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput,
+		optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error)
+}
+
 // AWSSSMStore saves credentials to AWS SSM Parameter Store with encryption.
 type AWSSSMStore struct {
 	ParameterPrefix string
 	KMSKeyID        string
 	Tags            map[string]string
 	ssmClient       SSMClient
+	endpoint        string
+	replicas        []*ssmReplica
+
+	installMu sync.Mutex // serializes installation updates in this process
 }
 
 // SSMStoreOption is a functional option for configuring AWSSSMStore.
@@ -56,6 +71,15 @@ func WithSSMClient(client SSMClient) SSMStoreOption {
 	}
 }
 
+// WithEndpoint sets a custom SSM endpoint URL, e.g. a LocalStack instance.
+// It takes precedence over AWS_ENDPOINT_URL_SSM and is ignored when a
+// custom client is set with WithSSMClient.
+func WithEndpoint(url string) SSMStoreOption {
+	return func(s *AWSSSMStore) {
+		s.endpoint = url
+	}
+}
+
 // NewAWSSSMStore creates a new AWS SSM Parameter Store backend.
 // The prefix is normalized to always end with a slash.
 func NewAWSSSMStore(prefix string, opts ...SSMStoreOption) (*AWSSSMStore, error) {
@@ -75,18 +99,49 @@ func NewAWSSSMStore(prefix string, opts ...SSMStoreOption) (*AWSSSMStore, error)
 		opt(store)
 	}
 
+	needsClient := store.ssmClient == nil
+	for _, r := range store.replicas {
+		if r.region == "" {
+			return nil, fmt.Errorf("replica region cannot be empty")
+		}
+		needsClient = needsClient || r.client == nil
+	}
+	if !needsClient {
+		return store, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	endpoint := store.endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv(EnvAWSEndpointURLSSM)
+	}
+	newClient := func(region string) *ssm.Client {
+		return ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+			if endpoint != "" {
+				o.BaseEndpoint = &endpoint
+			}
+			if region != "" {
+				o.Region = region
+			}
+		})
+	}
 	if store.ssmClient == nil {
-		cfg, err := config.LoadDefaultConfig(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		store.ssmClient = newClient("")
+	}
+	for _, r := range store.replicas {
+		if r.client == nil {
+			r.client = newClient(r.region)
 		}
-		store.ssmClient = ssm.NewFromConfig(cfg)
 	}
 
 	return store, nil
 }
 
-// Save writes credentials to AWS SSM as encrypted SecureString parameters.
+// Save writes credentials to AWS SSM as encrypted SecureString parameters,
+// replicating them to the replica regions if any are set.
 func (s *AWSSSMStore) Save(ctx context.Context, creds *AppCredentials) error {
 	parameters := map[string]string{
 		EnvGitHubAppID:         fmt.Sprintf("%d", creds.AppID),
@@ -109,23 +164,48 @@ func (s *AWSSSMStore) Save(ctx context.Context, creds *AppCredentials) error {
 		}
 	}
 
-	for name, value := range parameters {
-		if err := s.putParameter(ctx, name, value); err != nil {
-			return fmt.Errorf("failed to save parameter %s: %w", name, err)
+	return s.putParameters(ctx, parameters)
+}
+
+// putParameter creates or updates a single SSM parameter in the primary
+// and replica regions.
+func (s *AWSSSMStore) putParameter(ctx context.Context, name, value string) error {
+	return s.putParameters(ctx, map[string]string{name: value})
+}
+
+// putParameters creates or updates SSM parameters in the primary region,
+// then in every replica region.
+func (s *AWSSSMStore) putParameters(ctx context.Context, parameters map[string]string) error {
+	write := func(client SSMClient, kmsKeyID string) error {
+		for name, value := range parameters {
+			input := s.putParameterInput(name, value)
+			input.Overwrite = aws.Bool(true)
+			if kmsKeyID != "" {
+				input.KeyId = aws.String(kmsKeyID)
+			}
+			if _, err := client.PutParameter(ctx, input); err != nil {
+				return fmt.Errorf("failed to save parameter %s: %w", name, err)
+			}
 		}
+		return nil
 	}
 
-	return nil
+	if err := write(s.ssmClient, ""); err != nil {
+		return err
+	}
+	return s.replicate(func(r *ssmReplica) error {
+		return write(r.client, r.kmsKeyID)
+	})
 }
 
-// putParameter creates or updates a single SSM parameter.
-func (s *AWSSSMStore) putParameter(ctx context.Context, name, value string) error {
+// putParameterInput builds the input for writing a SecureString parameter
+// with the store's KMS key and tags.
+func (s *AWSSSMStore) putParameterInput(name, value string) *ssm.PutParameterInput {
 	input := &ssm.PutParameterInput{
-		Name:      aws.String(s.ParameterPrefix + name),
-		Value:     aws.String(value),
-		Type:      types.ParameterTypeSecureString,
-		Overwrite: aws.Bool(true),
-		DataType:  aws.String("text"),
+		Name:     aws.String(s.ParameterPrefix + name),
+		Value:    aws.String(value),
+		Type:     types.ParameterTypeSecureString,
+		DataType: aws.String("text"),
 	}
 
 	if s.KMSKeyID != "" {
@@ -143,12 +223,7 @@ func (s *AWSSSMStore) putParameter(ctx context.Context, name, value string) erro
 		input.Tags = tags
 	}
 
-	_, err := s.ssmClient.PutParameter(ctx, input)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return input
 }
 
 // Status returns the current registration state by checking required SSM parameters.
@@ -200,11 +275,155 @@ func (s *AWSSSMStore) Status(ctx context.Context) (*InstallerStatus, error) {
 	return status, nil
 }
 
+// Load reads the credentials back from SSM. When the client implements
+// ssm.GetParametersByPathAPIClient, as *ssm.Client does, Load lists the
+// parameters under the prefix so custom fields are returned too. It falls
+// back to reading only the credential parameters when the client cannot
+// list them, the prefix is not a path, or the caller lacks
+// ssm:GetParametersByPath.
+func (s *AWSSSMStore) Load(ctx context.Context) (*AppCredentials, error) {
+	if lister, ok := s.ssmClient.(ssm.GetParametersByPathAPIClient); ok && strings.HasPrefix(s.ParameterPrefix, "/") {
+		values, err := s.listParameterValues(ctx, lister)
+		if err == nil {
+			return credentialsFromValues(values)
+		}
+		if !isAccessDenied(err) {
+			return nil, err
+		}
+	}
+
+	values := make(map[string]string)
+	for _, key := range credentialKeys {
+		value, err := s.getParameterValue(ctx, key)
+		if err != nil {
+			if isParameterNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		values[key] = value
+	}
+	return credentialsFromValues(values)
+}
+
+// listParameterValues returns the values of the parameters directly under
+// the prefix, keyed by name without the prefix. Placeholders are skipped.
+func (s *AWSSSMStore) listParameterValues(ctx context.Context, client ssm.GetParametersByPathAPIClient) (map[string]string, error) {
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:           aws.String(strings.TrimSuffix(s.ParameterPrefix, "/")),
+		WithDecryption: aws.Bool(true),
+	})
+
+	values := make(map[string]string)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parameters: %w", err)
+		}
+		for _, param := range page.Parameters {
+			value := aws.ToString(param.Value)
+			if value == PlaceholderValue {
+				continue
+			}
+			values[strings.TrimPrefix(aws.ToString(param.Name), s.ParameterPrefix)] = value
+		}
+	}
+	return values, nil
+}
+
 // DisableInstaller sets a parameter to disable the installer.
 func (s *AWSSSMStore) DisableInstaller(ctx context.Context) error {
 	return s.putParameter(ctx, EnvGitHubAppInstallerEnabled, "false")
 }
 
+// EnableInstaller sets the installer parameter to enable the installer.
+func (s *AWSSSMStore) EnableInstaller(ctx context.Context) error {
+	return s.putParameter(ctx, EnvGitHubAppInstallerEnabled, "true")
+}
+
+// Delete removes the credential, installer flag, and installation
+// parameters in the primary and replica regions. Parameters that do not
+// exist are skipped. The SSM clients must implement SSMParameterDeleter.
+func (s *AWSSSMStore) Delete(ctx context.Context) error {
+	if err := s.deleteParameters(ctx, s.ssmClient); err != nil {
+		return err
+	}
+	return s.replicate(func(r *ssmReplica) error {
+		return s.deleteParameters(ctx, r.client)
+	})
+}
+
+// deleteParameters removes the store's parameters with client.
+func (s *AWSSSMStore) deleteParameters(ctx context.Context, client SSMClient) error {
+	deleter, ok := client.(SSMParameterDeleter)
+	if !ok {
+		return errors.New("SSM client cannot delete parameters")
+	}
+
+	names := make([]string, 0, len(deletedKeys))
+	for _, key := range deletedKeys {
+		names = append(names, s.ParameterPrefix+key)
+	}
+	// DeleteParameters accepts at most 10 names per call.
+	for batch := range slices.Chunk(names, 10) {
+		if _, err := deleter.DeleteParameters(ctx, &ssm.DeleteParametersInput{Names: batch}); err != nil {
+			return fmt.Errorf("failed to delete parameters: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveMetadata writes the app slug and HTML URL parameters.
+func (s *AWSSSMStore) SaveMetadata(ctx context.Context, slug, htmlURL string) error {
+	return s.putParameters(ctx, metadataValues(slug, htmlURL))
+}
+
+// Installations returns the installations recorded in the
+// GITHUB_APP_INSTALLATIONS parameter.
+func (s *AWSSSMStore) Installations(ctx context.Context) ([]Installation, error) {
+	value, err := s.getParameterValue(ctx, EnvGitHubAppInstallations)
+	if err != nil {
+		if isParameterNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeInstallations(value)
+}
+
+// SaveInstallation adds or updates an installation in the
+// GITHUB_APP_INSTALLATIONS parameter.
+func (s *AWSSSMStore) SaveInstallation(ctx context.Context, inst Installation) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return upsertInstallation(list, inst)
+	})
+}
+
+// DeleteInstallation removes an installation from the
+// GITHUB_APP_INSTALLATIONS parameter.
+func (s *AWSSSMStore) DeleteInstallation(ctx context.Context, id int64) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return removeInstallation(list, id)
+	})
+}
+
+func (s *AWSSSMStore) updateInstallations(ctx context.Context, fn func([]Installation) []Installation) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	get := func() (string, error) {
+		value, err := s.getParameterValue(ctx, EnvGitHubAppInstallations)
+		if isParameterNotFound(err) {
+			return "", nil
+		}
+		return value, err
+	}
+	put := func(value string) error {
+		return s.putParameter(ctx, EnvGitHubAppInstallations, value)
+	}
+	return updateInstallations(get, put, fn)
+}
+
 func (s *AWSSSMStore) getParameterValue(ctx context.Context, name string) (string, error) {
 	output, err := s.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
 		Name:           aws.String(s.ParameterPrefix + name),
@@ -216,6 +435,9 @@ func (s *AWSSSMStore) getParameterValue(ctx context.Context, name string) (strin
 	if output.Parameter == nil || output.Parameter.Value == nil {
 		return "", fmt.Errorf("parameter %s missing value", name)
 	}
+	if aws.ToString(output.Parameter.Value) == PlaceholderValue {
+		return "", &types.ParameterNotFound{Message: aws.String("parameter " + name + " holds a placeholder")}
+	}
 	return aws.ToString(output.Parameter.Value), nil
 }
 
@@ -223,3 +445,8 @@ func isParameterNotFound(err error) bool {
 	var notFound *types.ParameterNotFound
 	return errors.As(err, &notFound)
 }
+
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException"
+}
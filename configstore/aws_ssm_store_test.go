@@ -6,11 +6,13 @@ package configstore
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
 )
 
 // mockSSMClient implements SSMClient for testing
@@ -18,8 +20,10 @@ type mockSSMClient struct {
 	parameters map[string]string
 	putCalls   []ssm.PutParameterInput
 	getCalls   []ssm.GetParameterInput
+	listCalls  []ssm.GetParametersByPathInput
 	putErr     error
 	getErr     error
+	listErr    error
 }
 
 func newMockSSMClient() *mockSSMClient {
@@ -33,6 +37,9 @@ func (m *mockSSMClient) PutParameter(ctx context.Context, params *ssm.PutParamet
 	if m.putErr != nil {
 		return nil, m.putErr
 	}
+	if _, ok := m.parameters[*params.Name]; ok && !aws.ToBool(params.Overwrite) {
+		return nil, &types.ParameterAlreadyExists{}
+	}
 	m.parameters[*params.Name] = *params.Value
 	return &ssm.PutParameterOutput{}, nil
 }
@@ -54,6 +61,37 @@ func (m *mockSSMClient) GetParameter(ctx context.Context, params *ssm.GetParamet
 	}, nil
 }
 
+func (m *mockSSMClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	m.listCalls = append(m.listCalls, *params)
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	output := &ssm.GetParametersByPathOutput{}
+	prefix := aws.ToString(params.Path) + "/"
+	for name, value := range m.parameters {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && !strings.Contains(rest, "/") {
+			output.Parameters = append(output.Parameters, types.Parameter{
+				Name:  aws.String(name),
+				Value: aws.String(value),
+			})
+		}
+	}
+	return output, nil
+}
+
+func (m *mockSSMClient) DeleteParameters(ctx context.Context, params *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error) {
+	output := &ssm.DeleteParametersOutput{}
+	for _, name := range params.Names {
+		if _, ok := m.parameters[name]; !ok {
+			output.InvalidParameters = append(output.InvalidParameters, name)
+			continue
+		}
+		delete(m.parameters, name)
+		output.DeletedParameters = append(output.DeletedParameters, name)
+	}
+	return output, nil
+}
+
 func TestNewAWSSSMStore(t *testing.T) {
 	t.Run("empty prefix returns error", func(t *testing.T) {
 		_, err := NewAWSSSMStore("")
@@ -111,6 +149,22 @@ func TestAWSSSMStore_WithOptions(t *testing.T) {
 			t.Errorf("Tags[\"env\"] = %q, want %q", store.Tags["env"], "prod")
 		}
 	})
+
+	t.Run("WithEndpoint overrides AWS_ENDPOINT_URL_SSM", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "us-east-1")
+		t.Setenv(EnvAWSEndpointURLSSM, "http://env:4566")
+
+		for endpoint, want := range map[string]string{"": "http://env:4566", "http://localstack:4566": "http://localstack:4566"} {
+			store, err := NewAWSSSMStore("/prefix", WithEndpoint(endpoint))
+			if err != nil {
+				t.Fatalf("NewAWSSSMStore() error = %v", err)
+			}
+			got := store.ssmClient.(*ssm.Client).Options().BaseEndpoint
+			if got == nil || *got != want {
+				t.Errorf("BaseEndpoint = %v, want %q", got, want)
+			}
+		}
+	})
 }
 
 func TestAWSSSMStore_Save(t *testing.T) {
@@ -417,6 +471,90 @@ func TestAWSSSMStore_DisableInstaller_Error(t *testing.T) {
 	}
 }
 
+// newLoadableSSMStore returns a store under /app whose credentials,
+// including the STS_DOMAIN custom field, were saved to mock.
+func newLoadableSSMStore(t *testing.T, mock *mockSSMClient) *AWSSSMStore {
+	t.Helper()
+	store, err := NewAWSSSMStore("/app", WithSSMClient(mock))
+	if err != nil {
+		t.Fatalf("NewAWSSSMStore() error = %v", err)
+	}
+	if err := store.Save(context.Background(), &AppCredentials{
+		AppID: 42, ClientID: "id", ClientSecret: "secret",
+		WebhookSecret: "hook", PrivateKey: "key",
+		CustomFields: map[string]string{"STS_DOMAIN": "sts.example.com"},
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return store
+}
+
+func TestAWSSSMStore_Load_ListsCustomFields(t *testing.T) {
+	mock := newMockSSMClient()
+	store := newLoadableSSMStore(t, mock)
+	mock.parameters["/app/EXTRA"] = PlaceholderValue
+	mock.parameters["/app/nested/IGNORED"] = "x"
+	mock.parameters["/other/GITHUB_APP_ID"] = "7"
+	mock.getCalls = nil
+
+	creds, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if creds.AppID != 42 || creds.PrivateKey != "key" {
+		t.Errorf("Load() = %+v, want app 42 with key", creds)
+	}
+	if len(creds.CustomFields) != 1 || creds.CustomFields["STS_DOMAIN"] != "sts.example.com" {
+		t.Errorf("CustomFields = %v, want only STS_DOMAIN", creds.CustomFields)
+	}
+	if len(mock.listCalls) != 1 || aws.ToString(mock.listCalls[0].Path) != "/app" || !aws.ToBool(mock.listCalls[0].WithDecryption) {
+		t.Errorf("listCalls = %+v, want one decrypted listing of /app", mock.listCalls)
+	}
+	if len(mock.getCalls) != 0 {
+		t.Errorf("getCalls = %d, want none", len(mock.getCalls))
+	}
+}
+
+func TestAWSSSMStore_Load_ListError(t *testing.T) {
+	tests := []struct {
+		name     string
+		listErr  error
+		fallback bool
+	}{
+		{
+			name:     "access denied falls back to reading credentials",
+			listErr:  &smithy.GenericAPIError{Code: "AccessDeniedException"},
+			fallback: true,
+		},
+		{
+			name:    "other errors are returned",
+			listErr: fmt.Errorf("throttled"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockSSMClient()
+			store := newLoadableSSMStore(t, mock)
+			mock.listErr = tt.listErr
+
+			creds, err := store.Load(context.Background())
+			if !tt.fallback {
+				if err == nil {
+					t.Fatal("Load() should return the listing error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if creds.AppID != 42 || len(creds.CustomFields) != 0 {
+				t.Errorf("Load() = %+v, want app 42 without custom fields", creds)
+			}
+		})
+	}
+}
+
 func TestIsParameterNotFound(t *testing.T) {
 	tests := []struct {
 		name string
@@ -449,3 +587,46 @@ func TestIsParameterNotFound(t *testing.T) {
 		})
 	}
 }
+
+// BenchmarkAWSSSMStore_Save reports the SSM API calls made per Save, the
+// dominant cost against the real service.
+func BenchmarkAWSSSMStore_Save(b *testing.B) {
+	mock := newMockSSMClient()
+	store, err := NewAWSSSMStore("/bench/", WithSSMClient(mock))
+	if err != nil {
+		b.Fatal(err)
+	}
+	creds := benchCredentials()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := store.Save(ctx, creds); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(mock.putCalls))/float64(b.N), "puts/op")
+}
+
+// BenchmarkAWSSSMStore_Status reports the SSM API calls made per Status
+// for a registered app.
+func BenchmarkAWSSSMStore_Status(b *testing.B) {
+	mock := newMockSSMClient()
+	store, err := NewAWSSSMStore("/bench/", WithSSMClient(mock))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.Save(ctx, benchCredentials()); err != nil {
+		b.Fatal(err)
+	}
+	mock.getCalls = nil
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.Status(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(mock.getCalls))/float64(b.N), "gets/op")
+}
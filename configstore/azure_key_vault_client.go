@@ -0,0 +1,209 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// keyVaultAPIVersion is the Key Vault REST API version used for secret
+	// operations.
+	keyVaultAPIVersion = "7.4"
+
+	// keyVaultResource is the token audience for Key Vault.
+	keyVaultResource = "https://vault.azure.net"
+
+	// Environment variables set by App Service and Azure Functions for the
+	// managed identity endpoint, and by users to select a user-assigned
+	// identity.
+	envIdentityEndpoint = "IDENTITY_ENDPOINT"
+	envIdentityHeader   = "IDENTITY_HEADER"
+	envAzureClientID    = "AZURE_CLIENT_ID"
+
+	// identityAPIVersion is the App Service managed identity API version.
+	identityAPIVersion = "2019-08-01"
+)
+
+// keyVaultREST is the default KeyVaultClient. It calls the Key Vault REST
+// API with access tokens for the managed identity of the Function App or
+// App Service, read from its local identity endpoint.
+type keyVaultREST struct {
+	vaultURL   string
+	httpClient *http.Client
+
+	identityEndpoint string
+	identityHeader   string
+	clientID         string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newKeyVaultREST creates a REST client for the vault, reading the managed
+// identity endpoint from the environment.
+func newKeyVaultREST(vaultURL string, httpClient *http.Client) *keyVaultREST {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &keyVaultREST{
+		vaultURL:         strings.TrimRight(vaultURL, "/"),
+		httpClient:       httpClient,
+		identityEndpoint: os.Getenv(envIdentityEndpoint),
+		identityHeader:   os.Getenv(envIdentityHeader),
+		clientID:         os.Getenv(envAzureClientID),
+	}
+}
+
+// GetSecret returns the value of the current version of the secret.
+func (c *keyVaultREST) GetSecret(ctx context.Context, name string) (string, error) {
+	var out struct {
+		Value string `json:"value"`
+	}
+	status, err := c.do(ctx, http.MethodGet, c.secretURL(name), nil, &out)
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return out.Value, nil
+}
+
+// SetSecret adds a version holding value to the secret, creating the
+// secret if it does not exist.
+func (c *keyVaultREST) SetSecret(ctx context.Context, name, value string) error {
+	_, err := c.do(ctx, http.MethodPut, c.secretURL(name), map[string]string{"value": value}, nil)
+	return err
+}
+
+// DeleteSecret deletes the secret and all its versions. Secrets that do
+// not exist are ignored.
+func (c *keyVaultREST) DeleteSecret(ctx context.Context, name string) error {
+	status, err := c.do(ctx, http.MethodDelete, c.secretURL(name), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (c *keyVaultREST) secretURL(name string) string {
+	return fmt.Sprintf("%s/secrets/%s?api-version=%s", c.vaultURL, url.PathEscape(name), keyVaultAPIVersion)
+}
+
+// do sends an authorized JSON request and decodes the response into out,
+// if set. It returns the response status code, or zero if no response was
+// received, along with an error for non-2xx responses.
+func (c *keyVaultREST) do(ctx context.Context, method, url string, in, out any) (int, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("key vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read key vault response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("key vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to parse key vault response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// accessToken returns a cached Key Vault access token for the managed
+// identity, fetching a new one when it expires.
+func (c *keyVaultREST) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Until(c.expiry) > tokenExpiryMargin {
+		return c.token, nil
+	}
+	if c.identityEndpoint == "" || c.identityHeader == "" {
+		return "", errors.New("managed identity not available (IDENTITY_ENDPOINT is not set)")
+	}
+
+	q := url.Values{"resource": {keyVaultResource}, "api-version": {identityAPIVersion}}
+	if c.clientID != "" {
+		q.Set("client_id", c.clientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.identityEndpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-IDENTITY-HEADER", c.identityHeader)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("managed identity request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read managed identity response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("managed identity endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	// expires_on is a Unix timestamp, encoded as a string by App Service
+	var tok struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresOn   json.Number `json:"expires_on"`
+	}
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return "", fmt.Errorf("failed to parse managed identity token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("managed identity endpoint returned an empty access token")
+	}
+	expiresOn, err := strconv.ParseInt(tok.ExpiresOn.String(), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse managed identity token expiry: %w", err)
+	}
+	c.token = tok.AccessToken
+	c.expiry = time.Unix(expiresOn, 0)
+	return c.token, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeKeyVault serves the managed identity and Key Vault secret endpoints
+// used by keyVaultREST.
+type fakeKeyVault struct {
+	mu         sync.Mutex
+	secrets    map[string][]string
+	tokenFetch int
+	clientIDs  []string
+}
+
+func newFakeKeyVault(t *testing.T) (*fakeKeyVault, *httptest.Server) {
+	t.Helper()
+	f := &fakeKeyVault{secrets: make(map[string][]string)}
+	srv := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(srv.Close)
+	t.Setenv(envIdentityEndpoint, srv.URL+"/msi/token")
+	t.Setenv(envIdentityHeader, "identity-header")
+	return f, srv
+}
+
+func (f *fakeKeyVault) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/msi/token" {
+		if r.Header.Get("X-IDENTITY-HEADER") != "identity-header" || r.URL.Query().Get("resource") != keyVaultResource {
+			http.Error(w, "bad identity request", http.StatusBadRequest)
+			return
+		}
+		f.tokenFetch++
+		f.clientIDs = append(f.clientIDs, r.URL.Query().Get("client_id"))
+		_, _ = w.Write([]byte(`{"access_token":"tok","expires_on":"4102444800","resource":"https://vault.azure.net","token_type":"Bearer"}`))
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer tok" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Query().Get("api-version") != keyVaultAPIVersion {
+		http.Error(w, "missing api-version", http.StatusBadRequest)
+		return
+	}
+	name, ok := strings.CutPrefix(r.URL.Path, "/secrets/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		var body struct {
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.secrets[name] = append(f.secrets[name], body.Value)
+		_, _ = w.Write([]byte(`{}`))
+	case http.MethodGet:
+		versions := f.secrets[name]
+		if len(versions) == 0 {
+			http.Error(w, `{"error":{"code":"SecretNotFound"}}`, http.StatusNotFound)
+			return
+		}
+		data, _ := json.Marshal(map[string]string{"value": versions[len(versions)-1]})
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		if _, exists := f.secrets[name]; !exists {
+			http.Error(w, `{"error":{"code":"SecretNotFound"}}`, http.StatusNotFound)
+			return
+		}
+		delete(f.secrets, name)
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestKeyVaultREST_RoundTrip(t *testing.T) {
+	fake, srv := newFakeKeyVault(t)
+	t.Setenv(envAzureClientID, "user-assigned")
+	client := newKeyVaultREST(srv.URL+"/", srv.Client())
+	ctx := context.Background()
+
+	if _, err := client.GetSecret(ctx, "app-KEY"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("GetSecret() error = %v, want ErrSecretNotFound", err)
+	}
+	for _, value := range []string{"one", "two\nlines"} {
+		if err := client.SetSecret(ctx, "app-KEY", value); err != nil {
+			t.Fatalf("SetSecret(%q) error = %v", value, err)
+		}
+	}
+	got, err := client.GetSecret(ctx, "app-KEY")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if got != "two\nlines" {
+		t.Errorf("GetSecret() = %q, want %q", got, "two\nlines")
+	}
+
+	if err := client.DeleteSecret(ctx, "app-KEY"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if err := client.DeleteSecret(ctx, "app-KEY"); err != nil {
+		t.Errorf("DeleteSecret() of missing secret error = %v, want nil", err)
+	}
+
+	if fake.tokenFetch != 1 {
+		t.Errorf("token fetched %d times, want 1 (cached)", fake.tokenFetch)
+	}
+	if fake.clientIDs[0] != "user-assigned" {
+		t.Errorf("client_id = %q, want %q", fake.clientIDs[0], "user-assigned")
+	}
+}
+
+func TestKeyVaultREST_NoManagedIdentity(t *testing.T) {
+	t.Setenv(envIdentityEndpoint, "")
+	t.Setenv(envIdentityHeader, "")
+	client := newKeyVaultREST("https://vault.vault.azure.net", nil)
+
+	_, err := client.GetSecret(context.Background(), "app-KEY")
+	if err == nil || !strings.Contains(err.Error(), envIdentityEndpoint) {
+		t.Errorf("GetSecret() error = %v, want missing managed identity error", err)
+	}
+}
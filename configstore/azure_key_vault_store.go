@@ -0,0 +1,297 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeyVaultClient defines the interface for Azure Key Vault secret
+// operations. Names are Key Vault secret names.
+type KeyVaultClient interface {
+	// GetSecret returns the value of the current version of the secret,
+	// or an error wrapping ErrSecretNotFound.
+	GetSecret(ctx context.Context, name string) (string, error)
+	// SetSecret adds a version to the secret, creating the secret if it
+	// does not exist.
+	SetSecret(ctx context.Context, name, value string) error
+}
+
+// KeyVaultDeleter is implemented by Key Vault clients that can delete
+// secrets, such as the default client. AzureKeyVaultStore.Delete requires
+// it.
+type KeyVaultDeleter interface {
+	DeleteSecret(ctx context.Context, name string) error
+}
+
+// AzureKeyVaultStore saves credentials to Azure Key Vault, one secret per
+// value. Key Vault secret names may only contain letters, digits, and
+// dashes, so names are the secret prefix followed by the variable name
+// with underscores replaced by dashes (e.g. "my-app-GITHUB-APP-ID"). Each
+// save adds a new secret version; reads use the current version.
+type AzureKeyVaultStore struct {
+	VaultURL     string
+	SecretPrefix string
+	client       KeyVaultClient
+	httpClient   *http.Client
+
+	installMu sync.Mutex // serializes installation updates in this process
+}
+
+// KeyVaultStoreOption is a functional option for configuring
+// AzureKeyVaultStore.
+type KeyVaultStoreOption func(*AzureKeyVaultStore)
+
+// WithKeyVaultClient sets a custom Key Vault client.
+func WithKeyVaultClient(client KeyVaultClient) KeyVaultStoreOption {
+	return func(s *AzureKeyVaultStore) {
+		s.client = client
+	}
+}
+
+// WithKeyVaultHTTPClient sets the HTTP client used for Key Vault and
+// managed identity requests. It is ignored when a custom client is set
+// with WithKeyVaultClient.
+func WithKeyVaultHTTPClient(client *http.Client) KeyVaultStoreOption {
+	return func(s *AzureKeyVaultStore) {
+		s.httpClient = client
+	}
+}
+
+// NewAzureKeyVaultStore creates a new Azure Key Vault backend for the vault
+// at vaultURL (e.g. "https://my-vault.vault.azure.net"). The prefix may be
+// empty. Unless a custom client is set, requests are authorized with the
+// managed identity of the Function App or App Service, selecting a
+// user-assigned identity by AZURE_CLIENT_ID if set.
+func NewAzureKeyVaultStore(vaultURL, prefix string, opts ...KeyVaultStoreOption) (*AzureKeyVaultStore, error) {
+	if vaultURL == "" {
+		return nil, fmt.Errorf("key vault URL cannot be empty")
+	}
+
+	store := &AzureKeyVaultStore{
+		VaultURL:     strings.TrimRight(vaultURL, "/"),
+		SecretPrefix: prefix,
+	}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	if store.client == nil {
+		store.client = newKeyVaultREST(store.VaultURL, store.httpClient)
+	}
+
+	return store, nil
+}
+
+// Save writes credentials to Key Vault as new secret versions.
+func (s *AzureKeyVaultStore) Save(ctx context.Context, creds *AppCredentials) error {
+	secrets := map[string]string{
+		EnvGitHubAppID:         fmt.Sprintf("%d", creds.AppID),
+		EnvGitHubWebhookSecret: creds.WebhookSecret,
+		EnvGitHubClientID:      creds.ClientID,
+		EnvGitHubClientSecret:  creds.ClientSecret,
+		EnvGitHubAppPrivateKey: creds.PrivateKey,
+	}
+
+	if creds.AppSlug != "" {
+		secrets[EnvGitHubAppSlug] = creds.AppSlug
+	}
+	if creds.HTMLURL != "" {
+		secrets[EnvGitHubAppHTMLURL] = creds.HTMLURL
+	}
+
+	for key, value := range creds.CustomFields {
+		if value != "" {
+			secrets[key] = value
+		}
+	}
+
+	for name, value := range secrets {
+		if err := s.putSecret(ctx, name, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Status returns the current registration state by checking required secrets.
+func (s *AzureKeyVaultStore) Status(ctx context.Context) (*InstallerStatus, error) {
+	status := &InstallerStatus{}
+	required := []string{
+		EnvGitHubAppID,
+		EnvGitHubWebhookSecret,
+		EnvGitHubClientID,
+		EnvGitHubClientSecret,
+		EnvGitHubAppPrivateKey,
+	}
+
+	values := make(map[string]string)
+	for _, key := range required {
+		value, err := s.getSecret(ctx, key)
+		if err != nil {
+			if errors.Is(err, ErrSecretNotFound) {
+				return status, nil
+			}
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	status.Registered = true
+	if id, err := strconv.ParseInt(strings.TrimSpace(values[EnvGitHubAppID]), 10, 64); err == nil {
+		status.AppID = id
+	}
+
+	if slug, err := s.getSecret(ctx, EnvGitHubAppSlug); err == nil {
+		status.AppSlug = slug
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	if html, err := s.getSecret(ctx, EnvGitHubAppHTMLURL); err == nil {
+		status.HTMLURL = html
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	if flag, err := s.getSecret(ctx, EnvGitHubAppInstallerEnabled); err == nil {
+		status.InstallerDisabled = isFalseString(flag)
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// Load reads the credentials back from Key Vault. Custom fields are not
+// returned, since they cannot be discovered without listing secrets.
+func (s *AzureKeyVaultStore) Load(ctx context.Context) (*AppCredentials, error) {
+	values := make(map[string]string)
+	for _, key := range credentialKeys {
+		value, err := s.getSecret(ctx, key)
+		if err != nil {
+			if errors.Is(err, ErrSecretNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		values[key] = value
+	}
+	return credentialsFromValues(values)
+}
+
+// DisableInstaller sets a secret to disable the installer.
+func (s *AzureKeyVaultStore) DisableInstaller(ctx context.Context) error {
+	return s.putSecret(ctx, EnvGitHubAppInstallerEnabled, "false")
+}
+
+// EnableInstaller sets the installer secret to enable the installer.
+func (s *AzureKeyVaultStore) EnableInstaller(ctx context.Context) error {
+	return s.putSecret(ctx, EnvGitHubAppInstallerEnabled, "true")
+}
+
+// Delete removes the credential, installer flag, and installation secrets
+// with all their versions. Secrets that do not exist are skipped. With
+// soft delete enabled on the vault, deleted names stay reserved until the
+// secrets are purged or recovered. The Key Vault client must implement
+// KeyVaultDeleter.
+func (s *AzureKeyVaultStore) Delete(ctx context.Context) error {
+	deleter, ok := s.client.(KeyVaultDeleter)
+	if !ok {
+		return errors.New("key vault client cannot delete secrets")
+	}
+	for _, key := range deletedKeys {
+		if err := deleter.DeleteSecret(ctx, s.secretName(key)); err != nil {
+			return fmt.Errorf("failed to delete secret %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// SaveMetadata writes the app slug and HTML URL secrets.
+func (s *AzureKeyVaultStore) SaveMetadata(ctx context.Context, slug, htmlURL string) error {
+	for name, value := range metadataValues(slug, htmlURL) {
+		if err := s.putSecret(ctx, name, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Installations returns the installations recorded in the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *AzureKeyVaultStore) Installations(ctx context.Context) ([]Installation, error) {
+	value, err := s.getSecret(ctx, EnvGitHubAppInstallations)
+	if err != nil {
+		if errors.Is(err, ErrSecretNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeInstallations(value)
+}
+
+// SaveInstallation adds or updates an installation in the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *AzureKeyVaultStore) SaveInstallation(ctx context.Context, inst Installation) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return upsertInstallation(list, inst)
+	})
+}
+
+// DeleteInstallation removes an installation from the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *AzureKeyVaultStore) DeleteInstallation(ctx context.Context, id int64) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return removeInstallation(list, id)
+	})
+}
+
+func (s *AzureKeyVaultStore) updateInstallations(ctx context.Context, fn func([]Installation) []Installation) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	get := func() (string, error) {
+		value, err := s.getSecret(ctx, EnvGitHubAppInstallations)
+		if errors.Is(err, ErrSecretNotFound) {
+			return "", nil
+		}
+		return value, err
+	}
+	put := func(value string) error {
+		if err := s.putSecret(ctx, EnvGitHubAppInstallations, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", EnvGitHubAppInstallations, err)
+		}
+		return nil
+	}
+	return updateInstallations(get, put, fn)
+}
+
+func (s *AzureKeyVaultStore) putSecret(ctx context.Context, name, value string) error {
+	return s.client.SetSecret(ctx, s.secretName(name), value)
+}
+
+func (s *AzureKeyVaultStore) getSecret(ctx context.Context, name string) (string, error) {
+	value, err := s.client.GetSecret(ctx, s.secretName(name))
+	if err != nil {
+		return "", err
+	}
+	if value == PlaceholderValue {
+		return "", fmt.Errorf("%w: secret %s holds a placeholder", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// secretName returns the Key Vault secret name for a variable name.
+func (s *AzureKeyVaultStore) secretName(name string) string {
+	return strings.ReplaceAll(s.SecretPrefix+name, "_", "-")
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// mockKeyVaultClient implements KeyVaultClient for testing
+type mockKeyVaultClient struct {
+	secrets map[string]string
+	setErr  error
+	getErr  error
+}
+
+func newMockKeyVaultClient() *mockKeyVaultClient {
+	return &mockKeyVaultClient{
+		secrets: make(map[string]string),
+	}
+}
+
+func (m *mockKeyVaultClient) GetSecret(ctx context.Context, name string) (string, error) {
+	if m.getErr != nil {
+		return "", m.getErr
+	}
+	value, ok := m.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+func (m *mockKeyVaultClient) SetSecret(ctx context.Context, name, value string) error {
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.secrets[name] = value
+	return nil
+}
+
+func (m *mockKeyVaultClient) DeleteSecret(ctx context.Context, name string) error {
+	delete(m.secrets, name)
+	return nil
+}
+
+func newTestKeyVaultStore(t *testing.T, client KeyVaultClient) *AzureKeyVaultStore {
+	t.Helper()
+	store, err := NewAzureKeyVaultStore("https://vault.vault.azure.net/", "app-", WithKeyVaultClient(client))
+	if err != nil {
+		t.Fatalf("NewAzureKeyVaultStore() error = %v", err)
+	}
+	return store
+}
+
+func TestNewAzureKeyVaultStore(t *testing.T) {
+	if _, err := NewAzureKeyVaultStore("", "app-"); err == nil {
+		t.Error("NewAzureKeyVaultStore(\"\") should return error")
+	}
+
+	store := newTestKeyVaultStore(t, newMockKeyVaultClient())
+	if store.VaultURL != "https://vault.vault.azure.net" {
+		t.Errorf("VaultURL = %q, want trailing slash trimmed", store.VaultURL)
+	}
+}
+
+func TestAzureKeyVaultStore_SaveAndLoad(t *testing.T) {
+	client := newMockKeyVaultClient()
+	store := newTestKeyVaultStore(t, client)
+	ctx := context.Background()
+
+	creds := &AppCredentials{
+		AppID:         12345,
+		AppSlug:       "test-app",
+		ClientID:      "client-id",
+		ClientSecret:  "client-secret",
+		WebhookSecret: "webhook-secret",
+		PrivateKey:    "private-key",
+		CustomFields:  map[string]string{"MY_SETTING": "value"},
+	}
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Underscores are not allowed in Key Vault secret names
+	want := map[string]string{
+		"app-GITHUB-APP-ID":          "12345",
+		"app-GITHUB-APP-SLUG":        "test-app",
+		"app-GITHUB-APP-PRIVATE-KEY": "private-key",
+		"app-GITHUB-WEBHOOK-SECRET":  "webhook-secret",
+		"app-MY-SETTING":             "value",
+	}
+	for name, value := range want {
+		if got := client.secrets[name]; got != value {
+			t.Errorf("secret %s = %q, want %q", name, got, value)
+		}
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.AppID != 12345 || loaded.ClientSecret != "client-secret" {
+		t.Errorf("Load() = %+v, want saved credentials", loaded)
+	}
+}
+
+func TestAzureKeyVaultStore_Status(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not registered", func(t *testing.T) {
+		store := newTestKeyVaultStore(t, newMockKeyVaultClient())
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Registered {
+			t.Error("Registered = true, want false")
+		}
+	})
+
+	t.Run("registered with installer disabled", func(t *testing.T) {
+		store := newTestKeyVaultStore(t, newMockKeyVaultClient())
+		if err := store.Save(ctx, &AppCredentials{
+			AppID: 42, ClientID: "id", ClientSecret: "secret",
+			WebhookSecret: "hook", PrivateKey: "key",
+		}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := store.DisableInstaller(ctx); err != nil {
+			t.Fatalf("DisableInstaller() error = %v", err)
+		}
+
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if !status.Registered || status.AppID != 42 || !status.InstallerDisabled {
+			t.Errorf("Status() = %+v, want registered app 42 with installer disabled", status)
+		}
+	})
+
+	t.Run("access error", func(t *testing.T) {
+		client := newMockKeyVaultClient()
+		client.getErr = errors.New("forbidden")
+		store := newTestKeyVaultStore(t, client)
+		if _, err := store.Status(ctx); err == nil {
+			t.Error("Status() should return error")
+		}
+	})
+}
+
+func TestAzureKeyVaultStore_InstallationsAndDelete(t *testing.T) {
+	client := newMockKeyVaultClient()
+	store := newTestKeyVaultStore(t, client)
+	ctx := context.Background()
+
+	if err := store.SaveInstallation(ctx, Installation{ID: 7, Account: "octo"}); err != nil {
+		t.Fatalf("SaveInstallation() error = %v", err)
+	}
+	list, err := store.Installations(ctx)
+	if err != nil {
+		t.Fatalf("Installations() error = %v", err)
+	}
+	if len(list) != 1 || list[0].ID != 7 {
+		t.Errorf("Installations() = %+v, want installation 7", list)
+	}
+
+	if err := store.Save(ctx, &AppCredentials{AppID: 1, PrivateKey: "key"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(client.secrets) != 0 {
+		t.Errorf("secrets after Delete() = %v, want none", client.secrets)
+	}
+}
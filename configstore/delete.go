@@ -0,0 +1,21 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"slices"
+)
+
+// CredentialDeleter is implemented by stores that can remove what Save,
+// DisableInstaller, and the installation methods wrote, e.g. to tear down
+// a preview environment. Custom fields are kept, since they may be shared
+// with other configuration. Deleting from an empty store is not an error.
+// All built-in stores implement it.
+type CredentialDeleter interface {
+	Delete(ctx context.Context) error
+}
+
+// deletedKeys are the keys Delete removes.
+var deletedKeys = append(slices.Clone(credentialKeys), EnvGitHubAppInstallerEnabled, EnvGitHubAppInstallations)
@@ -0,0 +1,256 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSecretManagerEndpoint is the Google Secret Manager REST API.
+	DefaultSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+
+	// envGCEMetadataHost overrides the metadata server host, as it does
+	// for the Google Cloud client libraries.
+	envGCEMetadataHost = "GCE_METADATA_HOST"
+
+	defaultGCEMetadataHost = "metadata.google.internal"
+
+	// tokenExpiryMargin is how long before expiry a cached access token is
+	// replaced.
+	tokenExpiryMargin = time.Minute
+)
+
+// secretManagerREST is the default SecretManagerClient. It calls the
+// Secret Manager REST API with access tokens for the service account of
+// the Cloud Run service or function, read from the metadata server.
+type secretManagerREST struct {
+	project    string
+	endpoint   string
+	httpClient *http.Client
+	metadata   string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newSecretManagerREST creates a REST client for the project. Empty
+// endpoint and metadataHost select the defaults.
+func newSecretManagerREST(project, endpoint, metadataHost string, httpClient *http.Client) *secretManagerREST {
+	if endpoint == "" {
+		endpoint = DefaultSecretManagerEndpoint
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &secretManagerREST{
+		project:    project,
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		httpClient: httpClient,
+		metadata:   metadataURL(metadataHost),
+	}
+}
+
+// AccessSecret returns the payload of the latest version of the secret.
+func (c *secretManagerREST) AccessSecret(ctx context.Context, name string) (string, error) {
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	status, err := c.do(ctx, http.MethodGet, c.secretURL(name)+"/versions/latest:access", nil, &out)
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// AddSecretVersion adds a version holding value to the secret, creating
+// the secret with automatic replication first if it does not exist.
+func (c *secretManagerREST) AddSecretVersion(ctx context.Context, name, value string) error {
+	body := map[string]any{
+		"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(value))},
+	}
+	status, err := c.do(ctx, http.MethodPost, c.secretURL(name)+":addVersion", body, nil)
+	if status != http.StatusNotFound {
+		return err
+	}
+
+	create := map[string]any{
+		"replication": map[string]any{"automatic": map[string]any{}},
+	}
+	createURL := fmt.Sprintf("%s/v1/projects/%s/secrets?secretId=%s", c.endpoint, url.PathEscape(c.project), url.QueryEscape(name))
+	// A concurrent writer may have created the secret in the meantime
+	if status, err := c.do(ctx, http.MethodPost, createURL, create, nil); err != nil && status != http.StatusConflict {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, c.secretURL(name)+":addVersion", body, nil)
+	return err
+}
+
+// DeleteSecret deletes the secret and all its versions. Secrets that do
+// not exist are ignored.
+func (c *secretManagerREST) DeleteSecret(ctx context.Context, name string) error {
+	status, err := c.do(ctx, http.MethodDelete, c.secretURL(name), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (c *secretManagerREST) secretURL(name string) string {
+	return fmt.Sprintf("%s/v1/projects/%s/secrets/%s", c.endpoint, url.PathEscape(c.project), url.PathEscape(name))
+}
+
+// do sends an authorized JSON request and decodes the response into out,
+// if set. It returns the response status code, or zero if no response was
+// received, along with an error for non-2xx responses.
+func (c *secretManagerREST) do(ctx context.Context, method, url string, in, out any) (int, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("secret manager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read secret manager response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("secret manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to parse secret manager response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// accessToken returns a cached access token for the default service
+// account, fetching a new one from the metadata server when it expires.
+func (c *secretManagerREST) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Until(c.expiry) > tokenExpiryMargin {
+		return c.token, nil
+	}
+
+	data, err := c.metadataGet(ctx, "instance/service-accounts/default/token")
+	if err != nil {
+		return "", err
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return "", fmt.Errorf("failed to parse metadata token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("metadata server returned an empty access token")
+	}
+	c.token = tok.AccessToken
+	c.expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// projectID reads the project ID from the metadata server.
+func (c *secretManagerREST) projectID(ctx context.Context) (string, error) {
+	data, err := c.metadataGet(ctx, "project/project-id")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// metadataGet reads a path below computeMetadata/v1 from the metadata
+// server.
+func (c *secretManagerREST) metadataGet(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.metadata+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("metadata server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server returned %d for %s", resp.StatusCode, path)
+	}
+	return data, nil
+}
+
+// metadataURL returns the computeMetadata/v1 base URL for host, which may
+// be a bare host or a URL with a scheme. An empty host selects
+// GCE_METADATA_HOST or the default metadata server.
+func metadataURL(host string) string {
+	if host == "" {
+		host = GetEnvDefault(envGCEMetadataHost, defaultGCEMetadataHost)
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/") + "/computeMetadata/v1/"
+}
+
+// gcpProjectFromEnv returns the project ID set by the platform or the
+// user, or an empty string.
+func gcpProjectFromEnv() string {
+	for _, key := range []string{EnvGCPProject, envGCPProjectLegacy} {
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSecretManager serves the metadata server and Secret Manager REST
+// endpoints used by secretManagerREST.
+type fakeSecretManager struct {
+	mu          sync.Mutex
+	secrets     map[string][]string
+	tokenFetch  int
+	authHeaders []string
+}
+
+func newFakeSecretManager(t *testing.T) (*fakeSecretManager, *httptest.Server) {
+	t.Helper()
+	f := &fakeSecretManager{secrets: make(map[string][]string)}
+	srv := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeSecretManager) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/computeMetadata/v1/") {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor", http.StatusForbidden)
+			return
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/computeMetadata/v1/") {
+		case "instance/service-accounts/default/token":
+			f.tokenFetch++
+			_, _ = w.Write([]byte(`{"access_token":"tok","expires_in":3600,"token_type":"Bearer"}`))
+		case "project/project-id":
+			_, _ = w.Write([]byte("meta-proj"))
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	f.authHeaders = append(f.authHeaders, r.Header.Get("Authorization"))
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/projects/proj/secrets")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && rest == "":
+		id := r.URL.Query().Get("secretId")
+		if _, exists := f.secrets[id]; exists {
+			http.Error(w, "already exists", http.StatusConflict)
+			return
+		}
+		f.secrets[id] = nil
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(rest, ":addVersion"):
+		id := strings.TrimSuffix(strings.TrimPrefix(rest, "/"), ":addVersion")
+		versions, exists := f.secrets[id]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Payload struct {
+				Data string `json:"data"`
+			} `json:"payload"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := base64.StdEncoding.DecodeString(body.Payload.Data)
+		f.secrets[id] = append(versions, string(data))
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && strings.HasSuffix(rest, "/versions/latest:access"):
+		id := strings.TrimSuffix(strings.TrimPrefix(rest, "/"), "/versions/latest:access")
+		versions := f.secrets[id]
+		if len(versions) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		data := base64.StdEncoding.EncodeToString([]byte(versions[len(versions)-1]))
+		_, _ = w.Write([]byte(`{"payload":{"data":"` + data + `"}}`))
+	case r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(rest, "/")
+		if _, exists := f.secrets[id]; !exists {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.secrets, id)
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestSecretManagerREST_RoundTrip(t *testing.T) {
+	fake, srv := newFakeSecretManager(t)
+	client := newSecretManagerREST("proj", srv.URL, srv.URL, srv.Client())
+	ctx := context.Background()
+
+	if _, err := client.AccessSecret(ctx, "app-KEY"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("AccessSecret() error = %v, want ErrSecretNotFound", err)
+	}
+
+	// The first version creates the secret, the second is added to it
+	for _, value := range []string{"one", "two\nlines"} {
+		if err := client.AddSecretVersion(ctx, "app-KEY", value); err != nil {
+			t.Fatalf("AddSecretVersion(%q) error = %v", value, err)
+		}
+	}
+	got, err := client.AccessSecret(ctx, "app-KEY")
+	if err != nil {
+		t.Fatalf("AccessSecret() error = %v", err)
+	}
+	if got != "two\nlines" {
+		t.Errorf("AccessSecret() = %q, want %q", got, "two\nlines")
+	}
+	if n := len(fake.secrets["app-KEY"]); n != 2 {
+		t.Errorf("secret has %d versions, want 2", n)
+	}
+
+	if err := client.DeleteSecret(ctx, "app-KEY"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if err := client.DeleteSecret(ctx, "app-KEY"); err != nil {
+		t.Errorf("DeleteSecret() of missing secret error = %v, want nil", err)
+	}
+
+	if fake.tokenFetch != 1 {
+		t.Errorf("token fetched %d times, want 1 (cached)", fake.tokenFetch)
+	}
+	for _, h := range fake.authHeaders {
+		if h != "Bearer tok" {
+			t.Errorf("Authorization = %q, want %q", h, "Bearer tok")
+		}
+	}
+}
+
+func TestSecretManagerREST_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/computeMetadata/") {
+			_, _ = w.Write([]byte(`{"access_token":"tok","expires_in":3600}`))
+			return
+		}
+		http.Error(w, `{"error":{"status":"PERMISSION_DENIED"}}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := newSecretManagerREST("proj", srv.URL, srv.URL, srv.Client())
+	_, err := client.AccessSecret(context.Background(), "app-KEY")
+	if err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("AccessSecret() error = %v, want permission error", err)
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Errorf("error = %v, want status code", err)
+	}
+}
+
+func TestNewGCPSecretManagerStore_ProjectFromMetadata(t *testing.T) {
+	_, srv := newFakeSecretManager(t)
+	t.Setenv(EnvGCPProject, "")
+	t.Setenv(envGCPProjectLegacy, "")
+	t.Setenv(envGCEMetadataHost, srv.URL)
+
+	store, err := NewGCPSecretManagerStore("app-", WithSecretManagerEndpoint(srv.URL), WithSecretManagerHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewGCPSecretManagerStore() error = %v", err)
+	}
+	if store.Project != "meta-proj" {
+		t.Errorf("Project = %q, want %q", store.Project, "meta-proj")
+	}
+}
+
+func TestMetadataURL(t *testing.T) {
+	tests := []struct {
+		host string
+		env  string
+		want string
+	}{
+		{"", "", "http://metadata.google.internal/computeMetadata/v1/"},
+		{"", "169.254.169.254", "http://169.254.169.254/computeMetadata/v1/"},
+		{"http://127.0.0.1:8080/", "", "http://127.0.0.1:8080/computeMetadata/v1/"},
+	}
+	for _, tt := range tests {
+		t.Setenv(envGCEMetadataHost, tt.env)
+		if got := metadataURL(tt.host); got != tt.want {
+			t.Errorf("metadataURL(%q) with %s=%q = %q, want %q", tt.host, envGCEMetadataHost, tt.env, got, tt.want)
+		}
+	}
+}
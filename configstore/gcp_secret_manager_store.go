@@ -0,0 +1,316 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrSecretNotFound is returned by SecretManagerClient and KeyVaultClient
+// implementations for secrets that do not exist or have no versions.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretManagerClient defines the interface for Google Secret Manager
+// operations. Names are secret IDs within the store's project.
+type SecretManagerClient interface {
+	// AccessSecret returns the payload of the latest version of the
+	// secret, or an error wrapping ErrSecretNotFound.
+	AccessSecret(ctx context.Context, name string) (string, error)
+	// AddSecretVersion adds a version to the secret, creating the secret
+	// if it does not exist.
+	AddSecretVersion(ctx context.Context, name, value string) error
+}
+
+// SecretManagerDeleter is implemented by Secret Manager clients that can
+// delete secrets, such as the default client. GCPSecretManagerStore.Delete
+// requires it.
+type SecretManagerDeleter interface {
+	DeleteSecret(ctx context.Context, name string) error
+}
+
+// GCPSecretManagerStore saves credentials to Google Secret Manager, one
+// secret per value, named by the secret prefix followed by the variable
+// name (e.g. "my-app-GITHUB_APP_ID"). Each save adds a new secret version;
+// reads use the latest version.
+type GCPSecretManagerStore struct {
+	SecretPrefix string
+	Project      string
+	client       SecretManagerClient
+	endpoint     string
+	httpClient   *http.Client
+
+	installMu sync.Mutex // serializes installation updates in this process
+}
+
+// SecretManagerStoreOption is a functional option for configuring
+// GCPSecretManagerStore.
+type SecretManagerStoreOption func(*GCPSecretManagerStore)
+
+// WithSecretManagerClient sets a custom Secret Manager client.
+func WithSecretManagerClient(client SecretManagerClient) SecretManagerStoreOption {
+	return func(s *GCPSecretManagerStore) {
+		s.client = client
+	}
+}
+
+// WithGCPProject sets the project holding the secrets. It defaults to
+// GOOGLE_CLOUD_PROJECT, then GCP_PROJECT, then the project reported by the
+// metadata server.
+func WithGCPProject(project string) SecretManagerStoreOption {
+	return func(s *GCPSecretManagerStore) {
+		s.Project = project
+	}
+}
+
+// WithSecretManagerEndpoint sets a custom Secret Manager endpoint URL,
+// e.g. a regional endpoint. It is ignored when a custom client is set with
+// WithSecretManagerClient.
+func WithSecretManagerEndpoint(url string) SecretManagerStoreOption {
+	return func(s *GCPSecretManagerStore) {
+		s.endpoint = url
+	}
+}
+
+// WithSecretManagerHTTPClient sets the HTTP client used for Secret Manager
+// and metadata server requests. It is ignored when a custom client is set
+// with WithSecretManagerClient.
+func WithSecretManagerHTTPClient(client *http.Client) SecretManagerStoreOption {
+	return func(s *GCPSecretManagerStore) {
+		s.httpClient = client
+	}
+}
+
+// NewGCPSecretManagerStore creates a new Google Secret Manager backend.
+// The prefix may be empty. Unless a custom client is set, requests are
+// authorized as the service account of the Cloud Run service or function
+// using tokens from the metadata server.
+func NewGCPSecretManagerStore(prefix string, opts ...SecretManagerStoreOption) (*GCPSecretManagerStore, error) {
+	store := &GCPSecretManagerStore{
+		SecretPrefix: prefix,
+		Project:      gcpProjectFromEnv(),
+	}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	if store.client == nil {
+		rest := newSecretManagerREST(store.Project, store.endpoint, "", store.httpClient)
+		if store.Project == "" {
+			project, err := rest.projectID(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine GCP project (set %s): %w", EnvGCPProject, err)
+			}
+			store.Project = project
+			rest.project = project
+		}
+		store.client = rest
+	}
+
+	return store, nil
+}
+
+// Save writes credentials to Secret Manager as new secret versions.
+func (s *GCPSecretManagerStore) Save(ctx context.Context, creds *AppCredentials) error {
+	secrets := map[string]string{
+		EnvGitHubAppID:         fmt.Sprintf("%d", creds.AppID),
+		EnvGitHubWebhookSecret: creds.WebhookSecret,
+		EnvGitHubClientID:      creds.ClientID,
+		EnvGitHubClientSecret:  creds.ClientSecret,
+		EnvGitHubAppPrivateKey: creds.PrivateKey,
+	}
+
+	if creds.AppSlug != "" {
+		secrets[EnvGitHubAppSlug] = creds.AppSlug
+	}
+	if creds.HTMLURL != "" {
+		secrets[EnvGitHubAppHTMLURL] = creds.HTMLURL
+	}
+
+	for key, value := range creds.CustomFields {
+		if value != "" {
+			secrets[key] = value
+		}
+	}
+
+	for name, value := range secrets {
+		if err := s.putSecret(ctx, name, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Status returns the current registration state by checking required secrets.
+func (s *GCPSecretManagerStore) Status(ctx context.Context) (*InstallerStatus, error) {
+	status := &InstallerStatus{}
+	required := []string{
+		EnvGitHubAppID,
+		EnvGitHubWebhookSecret,
+		EnvGitHubClientID,
+		EnvGitHubClientSecret,
+		EnvGitHubAppPrivateKey,
+	}
+
+	values := make(map[string]string)
+	for _, key := range required {
+		value, err := s.getSecret(ctx, key)
+		if err != nil {
+			if errors.Is(err, ErrSecretNotFound) {
+				return status, nil
+			}
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	status.Registered = true
+	if id, err := strconv.ParseInt(strings.TrimSpace(values[EnvGitHubAppID]), 10, 64); err == nil {
+		status.AppID = id
+	}
+
+	if slug, err := s.getSecret(ctx, EnvGitHubAppSlug); err == nil {
+		status.AppSlug = slug
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	if html, err := s.getSecret(ctx, EnvGitHubAppHTMLURL); err == nil {
+		status.HTMLURL = html
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	if flag, err := s.getSecret(ctx, EnvGitHubAppInstallerEnabled); err == nil {
+		status.InstallerDisabled = isFalseString(flag)
+	} else if !errors.Is(err, ErrSecretNotFound) {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// Load reads the credentials back from Secret Manager. Custom fields are
+// not returned, since they cannot be discovered without listing secrets.
+func (s *GCPSecretManagerStore) Load(ctx context.Context) (*AppCredentials, error) {
+	values := make(map[string]string)
+	for _, key := range credentialKeys {
+		value, err := s.getSecret(ctx, key)
+		if err != nil {
+			if errors.Is(err, ErrSecretNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		values[key] = value
+	}
+	return credentialsFromValues(values)
+}
+
+// DisableInstaller sets a secret to disable the installer.
+func (s *GCPSecretManagerStore) DisableInstaller(ctx context.Context) error {
+	return s.putSecret(ctx, EnvGitHubAppInstallerEnabled, "false")
+}
+
+// EnableInstaller sets the installer secret to enable the installer.
+func (s *GCPSecretManagerStore) EnableInstaller(ctx context.Context) error {
+	return s.putSecret(ctx, EnvGitHubAppInstallerEnabled, "true")
+}
+
+// Delete removes the credential, installer flag, and installation secrets
+// with all their versions. Secrets that do not exist are skipped. The
+// Secret Manager client must implement SecretManagerDeleter.
+func (s *GCPSecretManagerStore) Delete(ctx context.Context) error {
+	deleter, ok := s.client.(SecretManagerDeleter)
+	if !ok {
+		return errors.New("secret manager client cannot delete secrets")
+	}
+	for _, key := range deletedKeys {
+		if err := deleter.DeleteSecret(ctx, s.SecretPrefix+key); err != nil {
+			return fmt.Errorf("failed to delete secret %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// SaveMetadata writes the app slug and HTML URL secrets.
+func (s *GCPSecretManagerStore) SaveMetadata(ctx context.Context, slug, htmlURL string) error {
+	for name, value := range metadataValues(slug, htmlURL) {
+		if err := s.putSecret(ctx, name, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Installations returns the installations recorded in the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *GCPSecretManagerStore) Installations(ctx context.Context) ([]Installation, error) {
+	value, err := s.getSecret(ctx, EnvGitHubAppInstallations)
+	if err != nil {
+		if errors.Is(err, ErrSecretNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeInstallations(value)
+}
+
+// SaveInstallation adds or updates an installation in the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *GCPSecretManagerStore) SaveInstallation(ctx context.Context, inst Installation) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return upsertInstallation(list, inst)
+	})
+}
+
+// DeleteInstallation removes an installation from the
+// GITHUB_APP_INSTALLATIONS secret.
+func (s *GCPSecretManagerStore) DeleteInstallation(ctx context.Context, id int64) error {
+	return s.updateInstallations(ctx, func(list []Installation) []Installation {
+		return removeInstallation(list, id)
+	})
+}
+
+func (s *GCPSecretManagerStore) updateInstallations(ctx context.Context, fn func([]Installation) []Installation) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	get := func() (string, error) {
+		value, err := s.getSecret(ctx, EnvGitHubAppInstallations)
+		if errors.Is(err, ErrSecretNotFound) {
+			return "", nil
+		}
+		return value, err
+	}
+	put := func(value string) error {
+		if err := s.putSecret(ctx, EnvGitHubAppInstallations, value); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", EnvGitHubAppInstallations, err)
+		}
+		return nil
+	}
+	return updateInstallations(get, put, fn)
+}
+
+func (s *GCPSecretManagerStore) putSecret(ctx context.Context, name, value string) error {
+	return s.client.AddSecretVersion(ctx, s.SecretPrefix+name, value)
+}
+
+func (s *GCPSecretManagerStore) getSecret(ctx context.Context, name string) (string, error) {
+	value, err := s.client.AccessSecret(ctx, s.SecretPrefix+name)
+	if err != nil {
+		return "", err
+	}
+	if value == PlaceholderValue {
+		return "", fmt.Errorf("%w: secret %s holds a placeholder", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// mockSecretManagerClient implements SecretManagerClient for testing
+type mockSecretManagerClient struct {
+	secrets  map[string][]string
+	addErr   error
+	getErr   error
+	accessed []string
+}
+
+func newMockSecretManagerClient() *mockSecretManagerClient {
+	return &mockSecretManagerClient{
+		secrets: make(map[string][]string),
+	}
+}
+
+func (m *mockSecretManagerClient) AccessSecret(ctx context.Context, name string) (string, error) {
+	m.accessed = append(m.accessed, name)
+	if m.getErr != nil {
+		return "", m.getErr
+	}
+	versions := m.secrets[name]
+	if len(versions) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return versions[len(versions)-1], nil
+}
+
+func (m *mockSecretManagerClient) AddSecretVersion(ctx context.Context, name, value string) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.secrets[name] = append(m.secrets[name], value)
+	return nil
+}
+
+func (m *mockSecretManagerClient) DeleteSecret(ctx context.Context, name string) error {
+	delete(m.secrets, name)
+	return nil
+}
+
+func (m *mockSecretManagerClient) latest(name string) string {
+	versions := m.secrets[name]
+	if len(versions) == 0 {
+		return ""
+	}
+	return versions[len(versions)-1]
+}
+
+func newTestSecretManagerStore(t *testing.T, client SecretManagerClient) *GCPSecretManagerStore {
+	t.Helper()
+	store, err := NewGCPSecretManagerStore("app-", WithGCPProject("proj"), WithSecretManagerClient(client))
+	if err != nil {
+		t.Fatalf("NewGCPSecretManagerStore() error = %v", err)
+	}
+	return store
+}
+
+func TestNewGCPSecretManagerStore_ProjectFromEnv(t *testing.T) {
+	t.Setenv(EnvGCPProject, "")
+	t.Setenv(envGCPProjectLegacy, "legacy-proj")
+
+	store, err := NewGCPSecretManagerStore("", WithSecretManagerClient(newMockSecretManagerClient()))
+	if err != nil {
+		t.Fatalf("NewGCPSecretManagerStore() error = %v", err)
+	}
+	if store.Project != "legacy-proj" {
+		t.Errorf("Project = %q, want %q", store.Project, "legacy-proj")
+	}
+
+	t.Setenv(EnvGCPProject, "proj")
+	store, err = NewGCPSecretManagerStore("", WithSecretManagerClient(newMockSecretManagerClient()))
+	if err != nil {
+		t.Fatalf("NewGCPSecretManagerStore() error = %v", err)
+	}
+	if store.Project != "proj" {
+		t.Errorf("Project = %q, want %q", store.Project, "proj")
+	}
+}
+
+func TestGCPSecretManagerStore_SaveAndLoad(t *testing.T) {
+	client := newMockSecretManagerClient()
+	store := newTestSecretManagerStore(t, client)
+	ctx := context.Background()
+
+	creds := &AppCredentials{
+		AppID:         12345,
+		AppSlug:       "test-app",
+		ClientID:      "client-id",
+		ClientSecret:  "client-secret",
+		WebhookSecret: "webhook-secret",
+		PrivateKey:    "private-key",
+		HTMLURL:       "https://github.com/apps/test-app",
+		CustomFields:  map[string]string{"CUSTOM": "value", "EMPTY": ""},
+	}
+	if err := store.Save(ctx, creds); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	want := map[string]string{
+		"app-" + EnvGitHubAppID:         "12345",
+		"app-" + EnvGitHubAppSlug:       "test-app",
+		"app-" + EnvGitHubClientID:      "client-id",
+		"app-" + EnvGitHubClientSecret:  "client-secret",
+		"app-" + EnvGitHubWebhookSecret: "webhook-secret",
+		"app-" + EnvGitHubAppPrivateKey: "private-key",
+		"app-" + EnvGitHubAppHTMLURL:    "https://github.com/apps/test-app",
+		"app-CUSTOM":                    "value",
+	}
+	for name, value := range want {
+		if got := client.latest(name); got != value {
+			t.Errorf("secret %s = %q, want %q", name, got, value)
+		}
+	}
+	if _, ok := client.secrets["app-EMPTY"]; ok {
+		t.Error("empty custom field should not be saved")
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.AppID != 12345 || loaded.PrivateKey != "private-key" || loaded.WebhookSecret != "webhook-secret" {
+		t.Errorf("Load() = %+v, want saved credentials", loaded)
+	}
+}
+
+func TestGCPSecretManagerStore_Status(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not registered", func(t *testing.T) {
+		store := newTestSecretManagerStore(t, newMockSecretManagerClient())
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Registered {
+			t.Error("Registered = true, want false")
+		}
+	})
+
+	t.Run("registered with installer disabled", func(t *testing.T) {
+		client := newMockSecretManagerClient()
+		store := newTestSecretManagerStore(t, client)
+		if err := store.Save(ctx, &AppCredentials{
+			AppID: 42, AppSlug: "slug", ClientID: "id", ClientSecret: "secret",
+			WebhookSecret: "hook", PrivateKey: "key",
+		}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := store.DisableInstaller(ctx); err != nil {
+			t.Fatalf("DisableInstaller() error = %v", err)
+		}
+
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if !status.Registered || status.AppID != 42 || status.AppSlug != "slug" || !status.InstallerDisabled {
+			t.Errorf("Status() = %+v, want registered app 42 with installer disabled", status)
+		}
+
+		if err := store.EnableInstaller(ctx); err != nil {
+			t.Fatalf("EnableInstaller() error = %v", err)
+		}
+		if got := client.latest("app-" + EnvGitHubAppInstallerEnabled); got != "true" {
+			t.Errorf("installer secret = %q, want %q", got, "true")
+		}
+	})
+
+	t.Run("placeholder counts as missing", func(t *testing.T) {
+		client := newMockSecretManagerClient()
+		store := newTestSecretManagerStore(t, client)
+		for _, key := range credentialKeys {
+			client.secrets["app-"+key] = []string{"value"}
+		}
+		client.secrets["app-"+EnvGitHubAppPrivateKey] = []string{PlaceholderValue}
+
+		status, err := store.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Registered {
+			t.Error("Registered = true, want false for placeholder secret")
+		}
+	})
+
+	t.Run("access error", func(t *testing.T) {
+		client := newMockSecretManagerClient()
+		client.getErr = errors.New("permission denied")
+		store := newTestSecretManagerStore(t, client)
+		if _, err := store.Status(ctx); err == nil {
+			t.Error("Status() should return error")
+		}
+	})
+}
+
+func TestGCPSecretManagerStore_Save_Error(t *testing.T) {
+	client := newMockSecretManagerClient()
+	client.addErr = errors.New("quota exceeded")
+	store := newTestSecretManagerStore(t, client)
+
+	if err := store.Save(context.Background(), &AppCredentials{AppID: 1}); err == nil {
+		t.Error("Save() should return error")
+	}
+}
+
+func TestGCPSecretManagerStore_Installations(t *testing.T) {
+	client := newMockSecretManagerClient()
+	store := newTestSecretManagerStore(t, client)
+	ctx := context.Background()
+
+	if err := store.SaveInstallation(ctx, Installation{ID: 1, Account: "octo"}); err != nil {
+		t.Fatalf("SaveInstallation() error = %v", err)
+	}
+	if err := store.SaveInstallation(ctx, Installation{ID: 2, Account: "cat"}); err != nil {
+		t.Fatalf("SaveInstallation() error = %v", err)
+	}
+	if err := store.DeleteInstallation(ctx, 1); err != nil {
+		t.Fatalf("DeleteInstallation() error = %v", err)
+	}
+
+	list, err := store.Installations(ctx)
+	if err != nil {
+		t.Fatalf("Installations() error = %v", err)
+	}
+	if len(list) != 1 || list[0].ID != 2 {
+		t.Errorf("Installations() = %+v, want only installation 2", list)
+	}
+}
+
+func TestGCPSecretManagerStore_Delete(t *testing.T) {
+	client := newMockSecretManagerClient()
+	store := newTestSecretManagerStore(t, client)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, &AppCredentials{AppID: 1, PrivateKey: "key"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for _, key := range deletedKeys {
+		if _, ok := client.secrets["app-"+key]; ok {
+			t.Errorf("secret %s still exists after Delete()", key)
+		}
+	}
+}
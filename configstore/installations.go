@@ -0,0 +1,97 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EnvGitHubAppInstallations holds the JSON list of the app's known
+// installations in backends that store values by key.
+const EnvGitHubAppInstallations = "GITHUB_APP_INSTALLATIONS"
+
+// Installation records where the GitHub App is installed.
+type Installation struct {
+	ID                  int64  `json:"id"`
+	Account             string `json:"account"`
+	AccountType         string `json:"account_type,omitempty"`
+	RepositorySelection string `json:"repository_selection,omitempty"`
+	Suspended           bool   `json:"suspended,omitempty"`
+}
+
+// InstallationStore is implemented by stores that also persist the app's
+// installations, typically kept current from installation webhooks. All
+// built-in stores implement it.
+type InstallationStore interface {
+	Installations(ctx context.Context) ([]Installation, error)
+	SaveInstallation(ctx context.Context, inst Installation) error
+	DeleteInstallation(ctx context.Context, id int64) error
+}
+
+// decodeInstallations parses a stored installation list. An empty value
+// means no installations.
+func decodeInstallations(value string) ([]Installation, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var list []Installation
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", EnvGitHubAppInstallations, err)
+	}
+	return list, nil
+}
+
+// encodeInstallations serializes an installation list, ordered by ID.
+func encodeInstallations(list []Installation) (string, error) {
+	slices.SortFunc(list, func(a, b Installation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", EnvGitHubAppInstallations, err)
+	}
+	return string(data), nil
+}
+
+// upsertInstallation replaces the installation with inst's ID, or appends
+// inst if it is new.
+func upsertInstallation(list []Installation, inst Installation) []Installation {
+	for i := range list {
+		if list[i].ID == inst.ID {
+			list[i] = inst
+			return list
+		}
+	}
+	return append(list, inst)
+}
+
+// removeInstallation drops the installation with the given ID.
+func removeInstallation(list []Installation, id int64) []Installation {
+	return slices.DeleteFunc(list, func(inst Installation) bool {
+		return inst.ID == id
+	})
+}
+
+// updateInstallations applies fn to the list stored under get and writes
+// the result with put.
+func updateInstallations(get func() (string, error), put func(string) error, fn func([]Installation) []Installation) error {
+	value, err := get()
+	if err != nil {
+		return err
+	}
+	list, err := decodeInstallations(value)
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeInstallations(fn(list))
+	if err != nil {
+		return err
+	}
+	return put(encoded)
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallationStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) InstallationStore{
+		"envfile": func(t *testing.T) InstallationStore {
+			return NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+		},
+		"files": func(t *testing.T) InstallationStore {
+			return NewLocalFileStore(filepath.Join(t.TempDir(), "creds"))
+		},
+		"aws-ssm": func(t *testing.T) InstallationStore {
+			store, err := NewAWSSSMStore("/app/", WithSSMClient(newMockSSMClient()))
+			if err != nil {
+				t.Fatalf("NewAWSSSMStore() error = %v", err)
+			}
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			if got, err := store.Installations(ctx); err != nil || len(got) != 0 {
+				t.Fatalf("Installations() = %v, %v; want empty", got, err)
+			}
+
+			acme := Installation{ID: 20, Account: "acme", AccountType: "Organization", RepositorySelection: "all"}
+			octo := Installation{ID: 10, Account: "octocat", AccountType: "User"}
+			for _, inst := range []Installation{acme, octo} {
+				if err := store.SaveInstallation(ctx, inst); err != nil {
+					t.Fatalf("SaveInstallation() error = %v", err)
+				}
+			}
+			acme.Suspended = true
+			if err := store.SaveInstallation(ctx, acme); err != nil {
+				t.Fatalf("SaveInstallation() update error = %v", err)
+			}
+
+			got, err := store.Installations(ctx)
+			if err != nil {
+				t.Fatalf("Installations() error = %v", err)
+			}
+			if len(got) != 2 || got[0] != octo || got[1] != acme {
+				t.Errorf("Installations() = %+v, want [%+v %+v]", got, octo, acme)
+			}
+
+			if err := store.DeleteInstallation(ctx, 10); err != nil {
+				t.Fatalf("DeleteInstallation() error = %v", err)
+			}
+			got, _ = store.Installations(ctx)
+			if len(got) != 1 || got[0] != acme {
+				t.Errorf("Installations() after delete = %+v, want [%+v]", got, acme)
+			}
+		})
+	}
+}
+
+func TestLocalEnvFileStore_InstallationsPreserveCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("# app\nGITHUB_APP_ID=123\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewLocalEnvFileStore(path)
+	if err := store.SaveInstallation(context.Background(), Installation{ID: 1, Account: "acme"}); err != nil {
+		t.Fatalf("SaveInstallation() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# app\nGITHUB_APP_ID=123\n") {
+		t.Errorf(".env file = %q, want existing content preserved", data)
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import "context"
+
+// InstallerEnabler is implemented by stores that can undo
+// DisableInstaller, e.g. to reopen the installer after an incident. All
+// built-in stores implement it.
+type InstallerEnabler interface {
+	EnableInstaller(ctx context.Context) error
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNotRegistered is returned by Load when the store does not hold a
+// complete set of credentials.
+var ErrNotRegistered = errors.New("app credentials are not registered")
+
+// CredentialLoader is implemented by stores that can read back the
+// credentials they saved, e.g. to back them up or copy them to another
+// store. All built-in stores implement it.
+type CredentialLoader interface {
+	Load(ctx context.Context) (*AppCredentials, error)
+}
+
+// credentialKeys are the keys Save writes for the credentials themselves,
+// as opposed to custom fields.
+var credentialKeys = []string{
+	EnvGitHubAppID,
+	EnvGitHubAppSlug,
+	EnvGitHubAppHTMLURL,
+	EnvGitHubAppPrivateKey,
+	EnvGitHubWebhookSecret,
+	EnvGitHubClientID,
+	EnvGitHubClientSecret,
+}
+
+// credentialsFromValues builds credentials from values keyed by
+// environment variable names. Keys other than the credential keys, the
+// installer flag, and the installation list become custom fields.
+func credentialsFromValues(values map[string]string) (*AppCredentials, error) {
+	if !hasAllValues(values,
+		EnvGitHubAppID,
+		EnvGitHubWebhookSecret,
+		EnvGitHubClientID,
+		EnvGitHubClientSecret,
+		EnvGitHubAppPrivateKey,
+	) {
+		return nil, ErrNotRegistered
+	}
+
+	appID, err := strconv.ParseInt(strings.TrimSpace(values[EnvGitHubAppID]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvGitHubAppID, err)
+	}
+
+	creds := &AppCredentials{
+		AppID:         appID,
+		AppSlug:       values[EnvGitHubAppSlug],
+		HTMLURL:       values[EnvGitHubAppHTMLURL],
+		PrivateKey:    values[EnvGitHubAppPrivateKey],
+		WebhookSecret: values[EnvGitHubWebhookSecret],
+		ClientID:      values[EnvGitHubClientID],
+		ClientSecret:  values[EnvGitHubClientSecret],
+	}
+
+	for key, value := range values {
+		if value == "" || slices.Contains(credentialKeys, key) ||
+			key == EnvGitHubAppInstallerEnabled || key == EnvGitHubAppInstallations {
+			continue
+		}
+		if creds.CustomFields == nil {
+			creds.CustomFields = make(map[string]string)
+		}
+		creds.CustomFields[key] = value
+	}
+	return creds, nil
+}
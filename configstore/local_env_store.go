@@ -7,15 +7,20 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // LocalEnvFileStore saves credentials to a .env file.
 type LocalEnvFileStore struct {
 	FilePath string
+
+	fileMu sync.Mutex // serializes read-modify-write updates
 }
 
 // NewLocalEnvFileStore creates a store that saves credentials to the given path.
@@ -115,8 +120,9 @@ func parseEnvFile(path string) (map[string]string, []string, error) {
 		value := strings.TrimSpace(line[idx+1:])
 
 		if len(value) >= 2 {
-			if (strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"")) ||
-				(strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")) {
+			if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+				value = strings.ReplaceAll(value[1:len(value)-1], "\\\"", "\"")
+			} else if strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
 				value = value[1 : len(value)-1]
 			}
 		}
@@ -159,9 +165,10 @@ func writeEnvFile(path string, values map[string]string, originalLines []string)
 		}
 	}
 
-	for key, value := range values {
+	// Append new keys in sorted order so the file content is stable.
+	for _, key := range slices.Sorted(maps.Keys(values)) {
 		if !writtenKeys[key] {
-			outputLines = append(outputLines, formatEnvLine(key, value))
+			outputLines = append(outputLines, formatEnvLine(key, values[key]))
 		}
 	}
 
@@ -174,7 +181,10 @@ func writeEnvFile(path string, values map[string]string, originalLines []string)
 }
 
 func formatEnvLine(key, value string) string {
-	needsQuotes := strings.ContainsAny(value, " \t\n\r\"'\\#") || strings.Contains(value, "\\n")
+	// Values with surrounding whitespace of any kind are quoted, since
+	// parseEnvFile trims unquoted values.
+	needsQuotes := strings.ContainsAny(value, " \t\n\r\"'\\#") || strings.Contains(value, "\\n") ||
+		strings.TrimSpace(value) != value
 
 	if needsQuotes {
 		escaped := strings.ReplaceAll(value, "\"", "\\\"")
@@ -218,6 +228,26 @@ func (s *LocalEnvFileStore) Status(ctx context.Context) (*InstallerStatus, error
 	return status, nil
 }
 
+// Load reads the credentials back from the .env file. Every other key in
+// the file, apart from the installer flag and installation list, is
+// returned as a custom field.
+func (s *LocalEnvFileStore) Load(ctx context.Context) (*AppCredentials, error) {
+	values, _, err := parseEnvFile(s.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotRegistered
+		}
+		return nil, err
+	}
+
+	creds, err := credentialsFromValues(values)
+	if err != nil {
+		return nil, err
+	}
+	creds.PrivateKey = strings.ReplaceAll(creds.PrivateKey, "\\n", "\n")
+	return creds, nil
+}
+
 // DisableInstaller sets GITHUB_APP_INSTALLER_ENABLED=false in the .env file.
 func (s *LocalEnvFileStore) DisableInstaller(ctx context.Context) error {
 	dir := filepath.Dir(s.FilePath)
@@ -243,3 +273,152 @@ func (s *LocalEnvFileStore) DisableInstaller(ctx context.Context) error {
 
 	return nil
 }
+
+// EnableInstaller sets GITHUB_APP_INSTALLER_ENABLED=true in the .env file.
+func (s *LocalEnvFileStore) EnableInstaller(ctx context.Context) error {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	values, originalLines, err := parseEnvFile(s.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	values[EnvGitHubAppInstallerEnabled] = "true"
+
+	if err := writeEnvFile(s.FilePath, values, originalLines); err != nil {
+		return fmt.Errorf("failed to persist installer flag: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the credentials, installer flag, and installation list
+// from the .env file and the current process environment. Other lines are
+// kept; the file is removed if none are left.
+func (s *LocalEnvFileStore) Delete(ctx context.Context) error {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	values, originalLines, err := parseEnvFile(s.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read existing .env file: %w", err)
+	}
+
+	var kept []string
+	empty := true
+	for _, line := range originalLines {
+		if idx := strings.Index(line, "="); idx != -1 && !strings.HasPrefix(strings.TrimSpace(line), "#") &&
+			slices.Contains(deletedKeys, strings.TrimSpace(line[:idx])) {
+			continue
+		}
+		kept = append(kept, line)
+		if strings.TrimSpace(line) != "" {
+			empty = false
+		}
+	}
+	for _, key := range deletedKeys {
+		delete(values, key)
+	}
+
+	if empty {
+		if err := os.Remove(s.FilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove .env file: %w", err)
+		}
+	} else if err := writeEnvFile(s.FilePath, values, kept); err != nil {
+		return fmt.Errorf("failed to write .env file: %w", err)
+	}
+
+	for _, key := range deletedKeys {
+		os.Unsetenv(key)
+	}
+	return nil
+}
+
+// SaveMetadata updates the app slug and HTML URL in the .env file and the
+// current process environment.
+func (s *LocalEnvFileStore) SaveMetadata(ctx context.Context, slug, htmlURL string) error {
+	updates := metadataValues(slug, htmlURL)
+	if len(updates) == 0 {
+		return nil
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	values, originalLines, err := parseEnvFile(s.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read existing .env file: %w", err)
+	}
+	for key, value := range updates {
+		values[key] = value
+	}
+	if err := writeEnvFile(s.FilePath, values, originalLines); err != nil {
+		return fmt.Errorf("failed to persist app metadata: %w", err)
+	}
+
+	for key, value := range updates {
+		os.Setenv(key, value)
+	}
+	return nil
+}
+
+// Installations returns the installations recorded in the .env file.
+func (s *LocalEnvFileStore) Installations(ctx context.Context) ([]Installation, error) {
+	values, _, err := parseEnvFile(s.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeInstallations(values[EnvGitHubAppInstallations])
+}
+
+// SaveInstallation adds or updates an installation in the .env file.
+func (s *LocalEnvFileStore) SaveInstallation(ctx context.Context, inst Installation) error {
+	return s.updateInstallations(func(list []Installation) []Installation {
+		return upsertInstallation(list, inst)
+	})
+}
+
+// DeleteInstallation removes an installation from the .env file.
+func (s *LocalEnvFileStore) DeleteInstallation(ctx context.Context, id int64) error {
+	return s.updateInstallations(func(list []Installation) []Installation {
+		return removeInstallation(list, id)
+	})
+}
+
+func (s *LocalEnvFileStore) updateInstallations(fn func([]Installation) []Installation) error {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	dir := filepath.Dir(s.FilePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	values, originalLines, err := parseEnvFile(s.FilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing .env file: %w", err)
+	}
+	if values == nil {
+		values = make(map[string]string)
+	}
+
+	get := func() (string, error) { return values[EnvGitHubAppInstallations], nil }
+	put := func(value string) error {
+		values[EnvGitHubAppInstallations] = value
+		if err := writeEnvFile(s.FilePath, values, originalLines); err != nil {
+			return fmt.Errorf("failed to persist installations: %w", err)
+		}
+		return nil
+	}
+	return updateInstallations(get, put, fn)
+}
@@ -6,15 +6,20 @@ package configstore
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // LocalFileStore saves credentials as individual files in a directory.
 type LocalFileStore struct {
 	Dir string
+
+	installMu sync.Mutex // serializes installation updates
 }
 
 // NewLocalFileStore creates a store that saves credentials as files in dir.
@@ -120,6 +125,64 @@ func (s *LocalFileStore) Status(ctx context.Context) (*InstallerStatus, error) {
 	return status, nil
 }
 
+// credentialFiles maps the files Save writes for the credentials to the
+// keys they hold.
+var credentialFiles = map[string]string{
+	"app-id":          EnvGitHubAppID,
+	"app-slug":        EnvGitHubAppSlug,
+	"app-html-url":    EnvGitHubAppHTMLURL,
+	"private-key.pem": EnvGitHubAppPrivateKey,
+	"webhook-secret":  EnvGitHubWebhookSecret,
+	"client-id":       EnvGitHubClientID,
+	"client-secret":   EnvGitHubClientSecret,
+}
+
+// Load reads the credentials back from the store directory. Other files,
+// apart from the installer marker and installation list, are returned as
+// custom fields keyed by their upper-cased names with dashes replaced by
+// underscores, reversing how Save names them. Symlinked files are
+// followed and entries starting with ".." are skipped, so a directory
+// laid out like a mounted Kubernetes Secret can be read.
+func (s *LocalFileStore) Load(ctx context.Context) (*AppCredentials, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotRegistered
+		}
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "..") || name == "installer-disabled" || name == installationsFile {
+			continue
+		}
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(s.Dir, name))
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+		} else if !entry.Type().IsRegular() {
+			continue
+		}
+		key, ok := credentialFiles[name]
+		if !ok {
+			key = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if key == EnvGitHubAppPrivateKey {
+			values[key] = string(data)
+		} else {
+			values[key] = strings.TrimSpace(string(data))
+		}
+	}
+	return credentialsFromValues(values)
+}
+
 // DisableInstaller creates a marker file to disable the installer.
 func (s *LocalFileStore) DisableInstaller(ctx context.Context) error {
 	if err := os.MkdirAll(s.Dir, 0700); err != nil {
@@ -134,6 +197,60 @@ func (s *LocalFileStore) DisableInstaller(ctx context.Context) error {
 	return nil
 }
 
+// EnableInstaller removes the marker file that disables the installer.
+func (s *LocalFileStore) EnableInstaller(ctx context.Context) error {
+	path := filepath.Join(s.Dir, "installer-disabled")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// Delete removes the credential files, the installer marker, and
+// installations.json. The directory is removed if nothing else is left in
+// it.
+func (s *LocalFileStore) Delete(ctx context.Context) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	names := append(slices.Collect(maps.Keys(credentialFiles)), "installer-disabled", installationsFile)
+	for _, name := range names {
+		path := filepath.Join(s.Dir, name)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(entries) == 0 {
+		if err := os.Remove(s.Dir); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove directory %s: %w", s.Dir, err)
+		}
+	}
+	return nil
+}
+
+// SaveMetadata writes the app slug and HTML URL files.
+func (s *LocalFileStore) SaveMetadata(ctx context.Context, slug, htmlURL string) error {
+	files := map[string]string{"app-slug": slug, "app-html-url": htmlURL}
+	for name, content := range files {
+		if content == "" {
+			continue
+		}
+		path := filepath.Join(s.Dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 func readTrimmedFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -141,3 +258,57 @@ func readTrimmedFile(path string) (string, error) {
 	}
 	return strings.TrimSpace(string(data)), nil
 }
+
+// installationsFile is the file holding the JSON installation list.
+const installationsFile = "installations.json"
+
+// Installations returns the installations recorded in installations.json.
+func (s *LocalFileStore) Installations(ctx context.Context) ([]Installation, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, installationsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeInstallations(string(data))
+}
+
+// SaveInstallation adds or updates an installation in installations.json.
+func (s *LocalFileStore) SaveInstallation(ctx context.Context, inst Installation) error {
+	return s.updateInstallations(func(list []Installation) []Installation {
+		return upsertInstallation(list, inst)
+	})
+}
+
+// DeleteInstallation removes an installation from installations.json.
+func (s *LocalFileStore) DeleteInstallation(ctx context.Context, id int64) error {
+	return s.updateInstallations(func(list []Installation) []Installation {
+		return removeInstallation(list, id)
+	})
+}
+
+func (s *LocalFileStore) updateInstallations(fn func([]Installation) []Installation) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", s.Dir, err)
+	}
+
+	path := filepath.Join(s.Dir, installationsFile)
+	get := func() (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		return string(data), nil
+	}
+	put := func(value string) error {
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+	return updateInstallations(get, put, fn)
+}
@@ -0,0 +1,27 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configstore
+
+import "context"
+
+// MetadataStore is implemented by stores that can update the app's slug
+// and HTML URL without rewriting the other credentials, e.g. to backfill
+// them for credentials that were provisioned by hand. Empty values are
+// left unchanged. All built-in stores implement it.
+type MetadataStore interface {
+	SaveMetadata(ctx context.Context, slug, htmlURL string) error
+}
+
+// metadataValues returns the non-empty metadata values keyed by their
+// environment variable names.
+func metadataValues(slug, htmlURL string) map[string]string {
+	values := make(map[string]string)
+	if slug != "" {
+		values[EnvGitHubAppSlug] = slug
+	}
+	if htmlURL != "" {
+		values[EnvGitHubAppHTMLURL] = htmlURL
+	}
+	return values
+}
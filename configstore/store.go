@@ -31,6 +31,29 @@ const (
 	EnvAWSSSMParameterPfx        = "AWS_SSM_PARAMETER_PREFIX"
 	EnvAWSSSMKMSKeyID            = "AWS_SSM_KMS_KEY_ID"
 	EnvAWSSSMTags                = "AWS_SSM_TAGS"
+	EnvAWSSSMReplicaRegions      = "AWS_SSM_REPLICA_REGIONS"
+	EnvAWSEndpointURLSSM         = "AWS_ENDPOINT_URL_SSM"
+	EnvGCPSecretManagerPrefix    = "GCP_SECRET_MANAGER_PREFIX"
+	EnvGCPProject                = "GOOGLE_CLOUD_PROJECT"
+	EnvAzureKeyVaultURL          = "AZURE_KEY_VAULT_URL"
+	EnvAzureKeyVaultPrefix       = "AZURE_KEY_VAULT_SECRET_PREFIX"
+)
+
+const (
+	// envGCPProjectLegacy is the project variable set by first-generation
+	// Cloud Functions runtimes.
+	envGCPProjectLegacy = "GCP_PROJECT"
+
+	// Environment variables set by Cloud Run and Cloud Functions, used to
+	// select Secret Manager when STORAGE_MODE is unset.
+	envCloudRunService     = "K_SERVICE"
+	envCloudRunJob         = "CLOUD_RUN_JOB"
+	envCloudFunctionTarget = "FUNCTION_TARGET"
+
+	// Environment variables set by Azure Functions, used to select Key
+	// Vault when STORAGE_MODE is unset.
+	envAzureFunctionsRuntime     = "FUNCTIONS_WORKER_RUNTIME"
+	envAzureFunctionsEnvironment = "AZURE_FUNCTIONS_ENVIRONMENT"
 )
 
 // Storage mode constants for STORAGE_MODE environment variable.
@@ -41,6 +64,12 @@ const (
 	StorageModeFiles = "files"
 	// StorageModeAWSSSM saves credentials to AWS SSM Parameter Store.
 	StorageModeAWSSSM = "aws-ssm"
+	// StorageModeGCPSecretManager saves credentials to Google Secret
+	// Manager (default mode on Cloud Run and Cloud Functions).
+	StorageModeGCPSecretManager = "gcp-secret-manager"
+	// StorageModeAzureKeyVault saves credentials to Azure Key Vault
+	// (default mode on Azure Functions).
+	StorageModeAzureKeyVault = "azure-key-vault"
 )
 
 // HookConfig contains webhook configuration returned from GitHub.
@@ -83,11 +112,18 @@ type Store interface {
 // It reads STORAGE_MODE to determine the backend type:
 //   - "envfile" (default): saves to a .env file at STORAGE_DIR (default: ./.env)
 //   - "files": saves to individual files in STORAGE_DIR directory
-//   - "aws-ssm": saves to AWS SSM Parameter Store with AWS_SSM_PARAMETER_PREFIX
+//   - "aws-ssm": saves to AWS SSM Parameter Store with AWS_SSM_PARAMETER_PREFIX,
+//     replicating writes to the regions in AWS_SSM_REPLICA_REGIONS
+//   - "gcp-secret-manager": saves to Google Secret Manager with secret IDs
+//     prefixed by GCP_SECRET_MANAGER_PREFIX (default on Cloud Run and
+//     Cloud Functions)
+//   - "azure-key-vault": saves to the Azure Key Vault at AZURE_KEY_VAULT_URL
+//     with secret names prefixed by AZURE_KEY_VAULT_SECRET_PREFIX (default
+//     on Azure Functions)
 //
 // Returns an error if configuration is invalid or store creation fails.
 func NewFromEnv() (Store, error) {
-	mode := GetEnvDefault(EnvStorageMode, StorageModeEnvFile)
+	mode := GetEnvDefault(EnvStorageMode, defaultStorageMode())
 
 	switch mode {
 	case StorageModeFiles:
@@ -118,12 +154,45 @@ func NewFromEnv() (Store, error) {
 			opts = append(opts, WithTags(tags))
 		}
 
+		if regions := parseRegionList(os.Getenv(EnvAWSSSMReplicaRegions)); len(regions) > 0 {
+			opts = append(opts, WithReplicaRegions(regions...))
+		}
+
 		return NewAWSSSMStore(prefix, opts...)
 
+	case StorageModeGCPSecretManager:
+		return NewGCPSecretManagerStore(os.Getenv(EnvGCPSecretManagerPrefix))
+
+	case StorageModeAzureKeyVault:
+		vaultURL := os.Getenv(EnvAzureKeyVaultURL)
+		if vaultURL == "" {
+			return nil, fmt.Errorf("%s is required when using %s storage mode", EnvAzureKeyVaultURL, StorageModeAzureKeyVault)
+		}
+		return NewAzureKeyVaultStore(vaultURL, os.Getenv(EnvAzureKeyVaultPrefix))
+
 	default:
-		return nil, fmt.Errorf("unknown %s: %s (expected '%s', '%s', or '%s')",
-			EnvStorageMode, mode, StorageModeEnvFile, StorageModeFiles, StorageModeAWSSSM)
+		return nil, fmt.Errorf("unknown %s: %s (expected '%s', '%s', '%s', '%s', or '%s')",
+			EnvStorageMode, mode, StorageModeEnvFile, StorageModeFiles, StorageModeAWSSSM,
+			StorageModeGCPSecretManager, StorageModeAzureKeyVault)
+	}
+}
+
+// defaultStorageMode returns the storage mode used when STORAGE_MODE is
+// unset: Secret Manager on Cloud Run and Cloud Functions, whose file
+// systems are not persistent, Key Vault on Azure Functions, whose
+// instances scale out and are replaced freely, and a .env file elsewhere.
+func defaultStorageMode() string {
+	for _, key := range []string{envCloudRunService, envCloudRunJob, envCloudFunctionTarget} {
+		if os.Getenv(key) != "" {
+			return StorageModeGCPSecretManager
+		}
+	}
+	for _, key := range []string{envAzureFunctionsRuntime, envAzureFunctionsEnvironment} {
+		if os.Getenv(key) != "" {
+			return StorageModeAzureKeyVault
+		}
 	}
+	return StorageModeEnvFile
 }
 
 // InstallerEnabled returns true if the installer is enabled via environment variable.
@@ -5,7 +5,10 @@ package configstore
 
 import (
 	"os"
+	"slices"
 	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
 func TestInstallerEnabled(t *testing.T) {
@@ -240,6 +243,71 @@ func TestNewFromEnv_StorageModes(t *testing.T) {
 		}
 	})
 
+	t.Run("default mode on Cloud Run creates GCPSecretManagerStore", func(t *testing.T) {
+		os.Unsetenv(EnvStorageMode)
+		t.Setenv(envCloudRunService, "svc")
+		t.Setenv(EnvGCPProject, "proj")
+		t.Setenv(EnvGCPSecretManagerPrefix, "app-")
+
+		store, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("NewFromEnv() error = %v", err)
+		}
+
+		smStore, ok := store.(*GCPSecretManagerStore)
+		if !ok {
+			t.Fatalf("NewFromEnv() returned %T, want *GCPSecretManagerStore", store)
+		}
+		if smStore.SecretPrefix != "app-" || smStore.Project != "proj" {
+			t.Errorf("store = %q in %q, want prefix %q in %q", smStore.SecretPrefix, smStore.Project, "app-", "proj")
+		}
+	})
+
+	t.Run("explicit mode overrides Cloud Run default", func(t *testing.T) {
+		os.Setenv(EnvStorageMode, StorageModeEnvFile)
+		defer os.Unsetenv(EnvStorageMode)
+		t.Setenv(envCloudFunctionTarget, "handler")
+
+		store, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("NewFromEnv() error = %v", err)
+		}
+
+		if _, ok := store.(*LocalEnvFileStore); !ok {
+			t.Errorf("NewFromEnv() returned %T, want *LocalEnvFileStore", store)
+		}
+	})
+
+	t.Run("default mode on Azure Functions creates AzureKeyVaultStore", func(t *testing.T) {
+		os.Unsetenv(EnvStorageMode)
+		t.Setenv(envAzureFunctionsRuntime, "custom")
+		t.Setenv(EnvAzureKeyVaultURL, "https://vault.vault.azure.net")
+		t.Setenv(EnvAzureKeyVaultPrefix, "app-")
+
+		store, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("NewFromEnv() error = %v", err)
+		}
+
+		kvStore, ok := store.(*AzureKeyVaultStore)
+		if !ok {
+			t.Fatalf("NewFromEnv() returned %T, want *AzureKeyVaultStore", store)
+		}
+		if kvStore.SecretPrefix != "app-" || kvStore.VaultURL != "https://vault.vault.azure.net" {
+			t.Errorf("store = %q in %q, want prefix %q in vault", kvStore.SecretPrefix, kvStore.VaultURL, "app-")
+		}
+	})
+
+	t.Run("azure-key-vault mode requires vault URL", func(t *testing.T) {
+		os.Setenv(EnvStorageMode, StorageModeAzureKeyVault)
+		defer os.Unsetenv(EnvStorageMode)
+		t.Setenv(EnvAzureKeyVaultURL, "")
+
+		if _, err := NewFromEnv(); err == nil {
+			t.Error("NewFromEnv() with azure-key-vault and no vault URL should return error")
+		}
+	})
+
 	t.Run("unknown mode returns error", func(t *testing.T) {
 		os.Setenv(EnvStorageMode, "invalid-mode")
 		defer os.Unsetenv(EnvStorageMode)
@@ -310,3 +378,25 @@ func TestNewFromEnv_AWSSSMTags(t *testing.T) {
 		}
 	})
 }
+
+func TestNewFromEnv_AWSSSMReplicaRegions(t *testing.T) {
+	t.Setenv(EnvStorageMode, StorageModeAWSSSM)
+	t.Setenv(EnvAWSSSMParameterPfx, "/test/prefix")
+	t.Setenv(EnvAWSSSMReplicaRegions, "us-west-2, eu-west-1,")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	store, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	ssmStore, ok := store.(*AWSSSMStore)
+	if !ok {
+		t.Fatalf("NewFromEnv() returned %T, want *AWSSSMStore", store)
+	}
+	if got := ssmStore.ReplicaRegions(); !slices.Equal(got, []string{"us-west-2", "eu-west-1"}) {
+		t.Errorf("ReplicaRegions() = %v, want [us-west-2 eu-west-1]", got)
+	}
+	if got := ssmStore.replicas[0].client.(*ssm.Client).Options().Region; got != "us-west-2" {
+		t.Errorf("replica client region = %q, want us-west-2", got)
+	}
+}
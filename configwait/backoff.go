@@ -0,0 +1,90 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Delay returns the time to wait after the given failed attempt (starting
+// at 1): RetryInterval scaled by Multiplier for each earlier attempt,
+// capped at MaxInterval, then randomized by Jitter.
+func (c Config) Delay(attempt int) time.Duration {
+	d := c.nominalDelay(attempt)
+	if jitter := min(max(c.Jitter, 0), 1); jitter > 0 {
+		d *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// RemainingWait returns the expected total delay still to come once the
+// given attempt (starting at 1) has failed, until MaxRetries is exhausted.
+// Jitter and the duration of the attempts themselves are not included.
+func (c Config) RemainingWait(attempt int) time.Duration {
+	var total float64
+	for i := max(attempt, 1); i < c.MaxRetries; i++ {
+		total += c.nominalDelay(i)
+		if total > math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+	}
+	return time.Duration(total)
+}
+
+// BudgetDelay returns the delay after the given failed attempt once elapsed
+// time has already been spent waiting, shortened so the next attempt does
+// not start after MaxWait. It returns false if MaxWait has been reached and
+// no further attempt should be made.
+func (c Config) BudgetDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	delay := c.Delay(attempt)
+	if c.MaxWait <= 0 {
+		return delay, true
+	}
+	remaining := c.MaxWait - elapsed
+	if remaining <= 0 {
+		return 0, false
+	}
+	return min(delay, remaining), true
+}
+
+// nominalDelay returns the delay after the given attempt before jitter.
+func (c Config) nominalDelay(attempt int) float64 {
+	d := float64(c.RetryInterval)
+	if c.Multiplier > 1 && attempt > 1 {
+		d *= math.Pow(c.Multiplier, float64(attempt-1))
+	}
+	if c.MaxInterval > 0 && d > float64(c.MaxInterval) {
+		d = float64(c.MaxInterval)
+	}
+	return d
+}
+
+// backoffFromEnv reads the backoff multiplier, maximum interval, and
+// jitter from environment variables, returning zero values for unset or
+// invalid entries.
+func backoffFromEnv() (multiplier float64, maxInterval time.Duration, jitter float64) {
+	if v := os.Getenv(EnvBackoffMultiplier); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			multiplier = f
+		}
+	}
+	if v := os.Getenv(EnvMaxInterval); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			maxInterval = d
+		}
+	}
+	if v := os.Getenv(EnvJitter); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+			jitter = f
+		}
+	}
+	return multiplier, maxInterval, jitter
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Delay(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		attempt int
+		want    time.Duration
+	}{
+		{"fixed", Config{RetryInterval: time.Second}, 5, time.Second},
+		{"first attempt", Config{RetryInterval: time.Second, Multiplier: 2}, 1, time.Second},
+		{"exponential", Config{RetryInterval: time.Second, Multiplier: 2}, 4, 8 * time.Second},
+		{"capped", Config{RetryInterval: time.Second, Multiplier: 2, MaxInterval: 5 * time.Second}, 4, 5 * time.Second},
+		{"overflow capped", Config{RetryInterval: time.Second, Multiplier: 10, MaxInterval: time.Minute}, 100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Delay(tt.attempt); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_RemainingWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		attempt int
+		want    time.Duration
+	}{
+		{"fixed", Config{MaxRetries: 5, RetryInterval: time.Second}, 1, 4 * time.Second},
+		{"before first attempt", Config{MaxRetries: 5, RetryInterval: time.Second}, 0, 4 * time.Second},
+		{"exponential", Config{MaxRetries: 4, RetryInterval: time.Second, Multiplier: 2}, 1, 7 * time.Second},
+		{"capped", Config{MaxRetries: 4, RetryInterval: time.Second, Multiplier: 2, MaxInterval: 3 * time.Second}, 2, 5 * time.Second},
+		{"exhausted", Config{MaxRetries: 3, RetryInterval: time.Second}, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.RemainingWait(tt.attempt); got != tt.want {
+				t.Errorf("RemainingWait(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_BudgetDelay(t *testing.T) {
+	cfg := Config{RetryInterval: time.Second, MaxWait: 10 * time.Second}
+
+	if d, ok := cfg.BudgetDelay(1, 2*time.Second); !ok || d != time.Second {
+		t.Errorf("BudgetDelay() = %v, %v, want 1s, true", d, ok)
+	}
+	if d, ok := cfg.BudgetDelay(1, 9500*time.Millisecond); !ok || d != 500*time.Millisecond {
+		t.Errorf("BudgetDelay() = %v, %v, want 500ms, true", d, ok)
+	}
+	if _, ok := cfg.BudgetDelay(1, 10*time.Second); ok {
+		t.Error("BudgetDelay() should report false once MaxWait is reached")
+	}
+
+	cfg.MaxWait = 0
+	if d, ok := cfg.BudgetDelay(1, time.Hour); !ok || d != time.Second {
+		t.Errorf("BudgetDelay() = %v, %v, want 1s, true without MaxWait", d, ok)
+	}
+}
+
+func TestConfig_Delay_Jitter(t *testing.T) {
+	cfg := Config{RetryInterval: time.Second, Jitter: 0.2}
+	varied := false
+	for i := 0; i < 100; i++ {
+		d := cfg.Delay(1)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("Delay() = %v, want within ±20%% of 1s", d)
+		}
+		if d != time.Second {
+			varied = true
+		}
+	}
+	if !varied {
+		t.Error("Delay() should vary with jitter")
+	}
+}
+
+func TestNewConfigFromEnv_Backoff(t *testing.T) {
+	t.Setenv(EnvBackoffMultiplier, "1.5")
+	t.Setenv(EnvMaxInterval, "30s")
+	t.Setenv(EnvJitter, "0.1")
+	t.Setenv(EnvMaxWait, "2m")
+
+	cfg := NewConfigFromEnv()
+	if cfg.Multiplier != 1.5 || cfg.MaxInterval != 30*time.Second || cfg.Jitter != 0.1 {
+		t.Errorf("NewConfigFromEnv() = %+v, want multiplier 1.5, max 30s, jitter 0.1", cfg)
+	}
+	if cfg.MaxWait != 2*time.Minute {
+		t.Errorf("MaxWait = %v, want 2m", cfg.MaxWait)
+	}
+
+	t.Setenv(EnvBackoffMultiplier, "fast")
+	if cfg := NewConfigFromEnv(); cfg.Multiplier != 0 {
+		t.Errorf("Multiplier = %v, want 0 for invalid value", cfg.Multiplier)
+	}
+}
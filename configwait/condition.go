@@ -0,0 +1,84 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import "sync/atomic"
+
+// Condition is a named prerequisite of a ReadyGate, such as "config" or
+// "db". A gate with conditions only ungates once SetReady has been called
+// and every condition is set.
+type Condition struct {
+	gate *ReadyGate
+	name string
+	met  atomic.Bool
+}
+
+// ConditionStatus reports the state of a single Condition.
+type ConditionStatus struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+// Name returns the condition name.
+func (c *Condition) Name() string {
+	return c.name
+}
+
+// Set marks the condition as met.
+func (c *Condition) Set() {
+	c.met.Store(true)
+	c.gate.notify()
+}
+
+// Clear marks the condition as not met, closing the gate until it is set
+// again.
+func (c *Condition) Clear() {
+	c.met.Store(false)
+	c.gate.notify()
+}
+
+// IsSet returns true if the condition is met.
+func (c *Condition) IsSet() bool {
+	return c.met.Load()
+}
+
+// Condition returns the condition registered under name, registering a new
+// unset condition if none exists. Registering a condition on a ready gate
+// closes it until the condition is set.
+func (rg *ReadyGate) Condition(name string) *Condition {
+	rg.condMu.Lock()
+	defer rg.condMu.Unlock()
+	for _, c := range rg.conditions {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &Condition{gate: rg, name: name}
+	rg.conditions = append(rg.conditions, c)
+	return c
+}
+
+// Conditions returns the status of every registered condition in
+// registration order.
+func (rg *ReadyGate) Conditions() []ConditionStatus {
+	rg.condMu.RLock()
+	defer rg.condMu.RUnlock()
+	out := make([]ConditionStatus, len(rg.conditions))
+	for i, c := range rg.conditions {
+		out[i] = ConditionStatus{Name: c.name, Ready: c.IsSet()}
+	}
+	return out
+}
+
+// conditionsMet reports whether every registered condition is set.
+func (rg *ReadyGate) conditionsMet() bool {
+	rg.condMu.RLock()
+	defer rg.condMu.RUnlock()
+	for _, c := range rg.conditions {
+		if !c.IsSet() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyGate_Conditions(t *testing.T) {
+	gate := NewReadyGate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), nil)
+	status := func() int {
+		rec := httptest.NewRecorder()
+		gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+		return rec.Code
+	}
+
+	config := gate.Condition("config")
+	db := gate.Condition("db")
+	if gate.Condition("config") != config {
+		t.Error("Condition() should return the registered condition")
+	}
+
+	gate.SetReady()
+	config.Set()
+	if gate.IsReady() || status() != http.StatusServiceUnavailable {
+		t.Error("gate should stay closed until every condition is set")
+	}
+
+	conds := gate.Conditions()
+	if len(conds) != 2 || conds[0] != (ConditionStatus{"config", true}) || conds[1] != (ConditionStatus{"db", false}) {
+		t.Errorf("Conditions() = %+v", conds)
+	}
+
+	db.Set()
+	if !gate.IsReady() || status() != http.StatusOK {
+		t.Error("gate should open once every condition is set")
+	}
+
+	db.Clear()
+	if gate.IsReady() || status() != http.StatusServiceUnavailable {
+		t.Error("gate should close when a condition is cleared")
+	}
+}
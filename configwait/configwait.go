@@ -7,7 +7,6 @@ package configwait
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"os"
 	"strconv"
@@ -20,8 +19,12 @@ import (
 )
 
 const (
-	EnvMaxRetries    = "CONFIG_WAIT_MAX_RETRIES"
-	EnvRetryInterval = "CONFIG_WAIT_RETRY_INTERVAL"
+	EnvMaxRetries        = "CONFIG_WAIT_MAX_RETRIES"
+	EnvRetryInterval     = "CONFIG_WAIT_RETRY_INTERVAL"
+	EnvBackoffMultiplier = "CONFIG_WAIT_BACKOFF_MULTIPLIER"
+	EnvMaxInterval       = "CONFIG_WAIT_MAX_INTERVAL"
+	EnvJitter            = "CONFIG_WAIT_JITTER"
+	EnvMaxWait           = "CONFIG_WAIT_MAX_WAIT"
 )
 
 const (
@@ -31,8 +34,38 @@ const (
 
 // Config configures the wait behavior.
 type Config struct {
-	MaxRetries    int
+	MaxRetries int
+
+	// RetryInterval is the delay before the first retry.
 	RetryInterval time.Duration
+
+	// Multiplier scales the delay after each failed attempt for exponential
+	// backoff (e.g. 2 doubles it). Values of 1 or less keep a fixed delay.
+	Multiplier float64
+
+	// MaxInterval caps the delay between attempts. If zero, the delay is
+	// not capped.
+	MaxInterval time.Duration
+
+	// Jitter randomizes each delay by up to this fraction in either
+	// direction (e.g. 0.2 for ±20%), so many instances starting together
+	// do not retry in lockstep. It is clamped to [0, 1].
+	Jitter float64
+
+	// MaxWait bounds the total time spent waiting, independently of
+	// MaxRetries; Wait gives up at whichever limit is reached first. The
+	// final delay is shortened so one last attempt is made at the
+	// deadline. An attempt already in progress is not interrupted. If
+	// zero, only MaxRetries applies.
+	MaxWait time.Duration
+
+	// OnAttempt, if set, is called after every load attempt with the
+	// attempt number (starting at 1) and its error, nil on success, so
+	// callers can surface retry progress to their own logs, metrics, or UI.
+	OnAttempt func(attempt int, err error)
+
+	// History, if set, records every attempt for StatusHandler.
+	History *History
 }
 
 // NewConfigFromEnv creates a Config from environment variables.
@@ -54,28 +87,47 @@ func NewConfigFromEnv() Config {
 		}
 	}
 
+	if v := os.Getenv(EnvMaxWait); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.MaxWait = d
+		}
+	}
+
+	cfg.Multiplier, cfg.MaxInterval, cfg.Jitter = backoffFromEnv()
 	return cfg
 }
 
 // LoadFunc attempts to load configuration; returns nil on success.
 type LoadFunc func(ctx context.Context) error
 
-// Wait blocks until load succeeds or max retries is reached.
+// Wait blocks until load succeeds or max retries (or max wait) is reached,
+// waiting between attempts according to the backoff policy in cfg.
 func Wait(ctx context.Context, cfg Config, load LoadFunc) error {
 	log := clog.FromContext(ctx)
+	start := time.Now()
 	var lastErr error
 
 	for attempt := 1; attempt <= cfg.MaxRetries; attempt++ {
-		if err := load(ctx); err != nil {
+		started := time.Now()
+		err := load(ctx)
+		cfg.History.RecordAttempt(AttemptLoad, attempt, started, err)
+		if cfg.OnAttempt != nil {
+			cfg.OnAttempt(attempt, err)
+		}
+		if err != nil {
 			lastErr = err
 			log.Warnf("[configwait] attempt %d/%d failed: %v", attempt, cfg.MaxRetries, err)
 
 			if attempt < cfg.MaxRetries {
+				delay, ok := cfg.BudgetDelay(attempt, time.Since(start))
+				if !ok {
+					log.Warnf("[configwait] giving up after %d attempts: max wait %v exceeded", attempt, cfg.MaxWait)
+					return lastErr
+				}
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
-				case <-time.After(cfg.RetryInterval):
-
+				case <-time.After(delay):
 				}
 			}
 		} else {
@@ -92,23 +144,57 @@ func Wait(ctx context.Context, cfg Config, load LoadFunc) error {
 // ReadyGate gates HTTP requests until the service is ready.
 type ReadyGate struct {
 	inner        http.Handler
-	allowedPaths []string
+	rulesMu      sync.RWMutex
+	allowedPaths []pathRule
+	gatedPaths   []pathRule
 	ready        atomic.Bool
 	handler      atomic.Value // stores http.Handler once ready
+	unavailable  UnavailableFunc
+	fallbacks    []fallbackRule
+	onNotReady   func(r *http.Request)
+
+	condMu     sync.RWMutex
+	conditions []*Condition
+
+	retryAfter func() time.Duration
+
+	hold     *holdPolicy
+	held     atomic.Int64
+	changeMu sync.Mutex
+	changed  chan struct{}
 
 	mu           sync.Mutex
 	handlerReady chan struct{}
 }
 
+// ReadyGateOption is a functional option for configuring ReadyGate.
+type ReadyGateOption func(*ReadyGate)
+
+// WithGatedPaths switches the gate to inverse mode: only requests matching
+// one of the given path prefixes wait for readiness, and all other paths
+// pass through immediately. Allowed paths are still always forwarded.
+// Entries accept the same method prefixes as allowedPaths.
+func WithGatedPaths(paths []string) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rg.gatedPaths = parsePathRules(paths)
+	}
+}
+
 // NewReadyGate creates a ReadyGate wrapping the given handler.
 // The allowedPaths are path prefixes always allowed through (e.g., "/setup").
+// An entry may be prefixed with a comma-separated list of HTTP methods to
+// restrict it to those methods (e.g., "GET /" or "GET,POST /setup"). As
+// with http.ServeMux patterns, GET also matches HEAD.
 // The inner handler can be nil initially; call SetHandler() once ready.
-func NewReadyGate(inner http.Handler, allowedPaths []string) *ReadyGate {
+func NewReadyGate(inner http.Handler, allowedPaths []string, opts ...ReadyGateOption) *ReadyGate {
 	rg := &ReadyGate{
 		inner:        inner,
-		allowedPaths: allowedPaths,
+		allowedPaths: parsePathRules(allowedPaths),
 		handlerReady: make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(rg)
+	}
 	if inner != nil {
 		rg.handler.Store(inner)
 	}
@@ -118,6 +204,15 @@ func NewReadyGate(inner http.Handler, allowedPaths []string) *ReadyGate {
 // SetReady marks the service as ready to handle all requests.
 func (rg *ReadyGate) SetReady() {
 	rg.ready.Store(true)
+	rg.notify()
+}
+
+// SetNotReady marks the service as not ready again, so gated requests are
+// rejected (or served by fallback handlers) until the next SetReady. Use it
+// to shed traffic during maintenance or after a failed reload.
+func (rg *ReadyGate) SetNotReady() {
+	rg.ready.Store(false)
+	rg.notify()
 }
 
 // SetHandler sets the main handler to use once ready.
@@ -130,48 +225,140 @@ func (rg *ReadyGate) SetHandler(h http.Handler) {
 	default:
 		close(rg.handlerReady)
 	}
+	rg.notify()
+}
+
+// AllowPaths adds path prefixes that are always allowed through, using the
+// same syntax as the allowedPaths passed to NewReadyGate. It is safe to call
+// while the gate is serving requests.
+func (rg *ReadyGate) AllowPaths(paths ...string) {
+	rules := parsePathRules(paths)
+	rg.rulesMu.Lock()
+	defer rg.rulesMu.Unlock()
+	rg.allowedPaths = append(rg.allowedPaths, rules...)
 }
 
-// IsReady returns true if the service is ready.
+// SetAllowedPaths replaces the path prefixes that are always allowed
+// through, including those added with AllowPaths. It is safe to call while
+// the gate is serving requests.
+func (rg *ReadyGate) SetAllowedPaths(paths ...string) {
+	rules := parsePathRules(paths)
+	rg.rulesMu.Lock()
+	defer rg.rulesMu.Unlock()
+	rg.allowedPaths = rules
+}
+
+// SetGatedPaths replaces the gated path prefixes set with WithGatedPaths.
+// With no paths, every request that is not explicitly allowed is gated
+// again. It is safe to call while the gate is serving requests.
+func (rg *ReadyGate) SetGatedPaths(paths ...string) {
+	rules := parsePathRules(paths)
+	rg.rulesMu.Lock()
+	defer rg.rulesMu.Unlock()
+	rg.gatedPaths = rules
+}
+
+// IsReady returns true if the service is ready: SetReady has been called
+// and every registered Condition is set.
 func (rg *ReadyGate) IsReady() bool {
-	return rg.ready.Load()
+	return rg.ready.Load() && rg.conditionsMet()
 }
 
 // ServeHTTP implements http.Handler.
 func (rg *ReadyGate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if rg.isAllowedPath(r.URL.Path) {
+	if rg.isAllowed(r) || !rg.isGated(r) {
 		h := rg.getHandler()
 		if h != nil {
 			h.ServeHTTP(w, r)
 			return
 		}
-		rg.serveUnavailable(w, r, "service starting up")
+		rg.serveNotReady(w, r, "service starting up")
 		return
 	}
 
-	if !rg.ready.Load() {
-		rg.serveUnavailable(w, r, "service not ready, configuration loading")
+	if !rg.IsReady() && !rg.holdUntilReady(r) {
+		rg.serveNotReady(w, r, "service not ready, configuration loading")
 		return
 	}
 
 	h := rg.getHandler()
 	if h == nil {
-		rg.serveUnavailable(w, r, "service starting up")
+		rg.serveNotReady(w, r, "service starting up")
 		return
 	}
 	h.ServeHTTP(w, r)
 }
 
-// isAllowedPath checks if the path matches any allowed path prefix.
-func (rg *ReadyGate) isAllowedPath(path string) bool {
-	for _, allowed := range rg.allowedPaths {
-		if allowed == "/" {
-			if path == "/" {
-				return true
+// isAllowed checks if the request matches any allowed path rule.
+func (rg *ReadyGate) isAllowed(r *http.Request) bool {
+	rg.rulesMu.RLock()
+	defer rg.rulesMu.RUnlock()
+	return matchesAnyRule(r, rg.allowedPaths)
+}
+
+// isGated checks if the request requires readiness. Without gated paths
+// configured, every request that is not explicitly allowed is gated.
+func (rg *ReadyGate) isGated(r *http.Request) bool {
+	rg.rulesMu.RLock()
+	defer rg.rulesMu.RUnlock()
+	if len(rg.gatedPaths) == 0 {
+		return true
+	}
+	return matchesAnyRule(r, rg.gatedPaths)
+}
+
+// pathRule is a parsed path prefix with an optional method restriction.
+type pathRule struct {
+	methods []string
+	prefix  string
+}
+
+// parsePathRules parses entries of the form "/prefix" or "METHOD[,METHOD] /prefix".
+func parsePathRules(entries []string) []pathRule {
+	rules := make([]pathRule, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		rule := pathRule{prefix: entry}
+		if methods, prefix, ok := strings.Cut(entry, " "); ok {
+			rule.prefix = strings.TrimSpace(prefix)
+			for _, m := range strings.Split(methods, ",") {
+				if m = strings.ToUpper(strings.TrimSpace(m)); m != "" {
+					rule.methods = append(rule.methods, m)
+				}
 			}
-			continue
 		}
-		if strings.HasPrefix(path, allowed) {
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matches checks the request method and path against the rule.
+// The root prefix "/" only matches the root path exactly.
+func (pr pathRule) matches(r *http.Request) bool {
+	if !pr.matchesMethod(r.Method) {
+		return false
+	}
+	if pr.prefix == "/" {
+		return r.URL.Path == "/"
+	}
+	return strings.HasPrefix(r.URL.Path, pr.prefix)
+}
+
+func (pr pathRule) matchesMethod(method string) bool {
+	if len(pr.methods) == 0 {
+		return true
+	}
+	for _, m := range pr.methods {
+		if m == method || (m == http.MethodGet && method == http.MethodHead) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyRule(r *http.Request, rules []pathRule) bool {
+	for _, rule := range rules {
+		if rule.matches(r) {
 			return true
 		}
 	}
@@ -187,17 +374,28 @@ func (rg *ReadyGate) getHandler() http.Handler {
 	return h.(http.Handler)
 }
 
-// serveUnavailable writes a 503 response.
+// serveUnavailable writes the not-ready response built by the gate's
+// UnavailableFunc.
 func (rg *ReadyGate) serveUnavailable(w http.ResponseWriter, r *http.Request, message string) {
 	log := clog.FromContext(r.Context())
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Retry-After", "5")
-	w.WriteHeader(http.StatusServiceUnavailable)
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"error":   "service_unavailable",
-		"message": message,
-	}); err != nil {
+	build := rg.unavailable
+	if build == nil {
+		build = DefaultUnavailableResponse
+	}
+	resp := build(r, message)
+	if resp.Status == 0 {
+		resp.Status = http.StatusServiceUnavailable
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	if w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", rg.retryAfterHeader())
+	}
+	w.WriteHeader(resp.Status)
+	if _, err := w.Write(resp.Body); err != nil {
 		log.Errorf("[configwait] failed to write unavailable response: %v", err)
 	}
 }
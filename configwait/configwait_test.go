@@ -80,6 +80,69 @@ func TestWait_MaxRetriesExceeded(t *testing.T) {
 	}
 }
 
+func TestWait_MaxWaitExceeded(t *testing.T) {
+	cfg := Config{
+		MaxRetries:    100,
+		RetryInterval: 20 * time.Millisecond,
+		MaxWait:       50 * time.Millisecond,
+	}
+
+	callCount := 0
+	expectedErr := errors.New("always fail")
+	start := time.Now()
+	err := Wait(context.Background(), cfg, func(ctx context.Context) error {
+		callCount++
+		return expectedErr
+	})
+
+	if err != expectedErr {
+		t.Errorf("Wait() error = %v, want %v", err, expectedErr)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Wait() took %v, want it bounded by MaxWait", elapsed)
+	}
+	if callCount < 2 || callCount >= 100 {
+		t.Errorf("Load function called %d times, want a few attempts before MaxWait", callCount)
+	}
+}
+
+func TestWait_OnAttempt(t *testing.T) {
+	type call struct {
+		attempt int
+		failed  bool
+	}
+	var calls []call
+	cfg := Config{
+		MaxRetries:    5,
+		RetryInterval: time.Millisecond,
+		OnAttempt: func(attempt int, err error) {
+			calls = append(calls, call{attempt, err != nil})
+		},
+	}
+
+	count := 0
+	err := Wait(context.Background(), cfg, func(ctx context.Context) error {
+		count++
+		if count < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	want := []call{{1, true}, {2, true}, {3, false}}
+	if len(calls) != len(want) {
+		t.Fatalf("OnAttempt called %d times, want %d", len(calls), len(want))
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("OnAttempt call %d = %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
+
 func TestWait_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cfg := Config{
@@ -189,6 +252,155 @@ func TestReadyGate_AllowedPathsPassThrough(t *testing.T) {
 	}
 }
 
+func TestReadyGate_AllowPaths(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	gate := NewReadyGate(inner, []string{"/healthz"})
+	gate.AllowPaths("/setup", "GET /")
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{http.MethodGet, "/healthz", http.StatusOK},
+		{http.MethodGet, "/setup/", http.StatusOK},
+		{http.MethodGet, "/", http.StatusOK},
+		{http.MethodPost, "/", http.StatusServiceUnavailable},
+		{http.MethodGet, "/webhook", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			gate.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestReadyGate_MethodAwareAllowedPaths(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	gate := NewReadyGate(inner, []string{"GET /", "GET,POST /setup", "/healthz"})
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{http.MethodGet, "/", http.StatusOK},
+		{http.MethodHead, "/", http.StatusOK},
+		{http.MethodPost, "/", http.StatusServiceUnavailable},
+		{http.MethodGet, "/setup", http.StatusOK},
+		{http.MethodPost, "/setup/disable", http.StatusOK},
+		{http.MethodDelete, "/setup", http.StatusServiceUnavailable},
+		{http.MethodPut, "/healthz", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			gate.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestReadyGate_SetPaths(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	gate := NewReadyGate(inner, []string{"/healthz"})
+	gate.AllowPaths("/setup")
+	gate.SetAllowedPaths("/status")
+	gate.SetGatedPaths("/webhook")
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{"/status", http.StatusOK},
+		{"/healthz", http.StatusOK},
+		{"/setup", http.StatusOK},
+		{"/webhook", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantStatus {
+			t.Errorf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+		}
+	}
+
+	// Clearing the gated paths gates everything not allowed again
+	gate.SetGatedPaths()
+	for path, want := range map[string]int{"/status": http.StatusOK, "/healthz": http.StatusServiceUnavailable} {
+		rec := httptest.NewRecorder()
+		gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s status = %d, want %d after clearing gated paths", path, rec.Code, want)
+		}
+	}
+}
+
+func TestReadyGate_GatedPaths(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	gate := NewReadyGate(inner, []string{"/webhook/ping"}, WithGatedPaths([]string{"/webhook"}))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{"/", http.StatusOK},
+		{"/setup", http.StatusOK},
+		{"/api/data", http.StatusOK},
+		{"/webhook", http.StatusServiceUnavailable},
+		{"/webhook/github", http.StatusServiceUnavailable},
+		{"/webhook/ping", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			gate.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	gate.SetReady()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d after SetReady()", rec.Code, http.StatusOK)
+	}
+}
+
 func TestReadyGate_IsReady(t *testing.T) {
 	gate := NewReadyGate(nil, nil)
 
@@ -201,6 +413,12 @@ func TestReadyGate_IsReady(t *testing.T) {
 	if !gate.IsReady() {
 		t.Error("IsReady() = false, want true after SetReady()")
 	}
+
+	gate.SetNotReady()
+
+	if gate.IsReady() {
+		t.Error("IsReady() = true, want false after SetNotReady()")
+	}
 }
 
 func TestReadyGate_SetHandler(t *testing.T) {
@@ -333,6 +551,47 @@ func TestReloader_ReloadError(t *testing.T) {
 	}
 }
 
+func TestReloader_HooksAndLastResult(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expectedErr := errors.New("reload failed")
+	var started atomic.Int32
+	done := make(chan error, 1)
+	reloader := NewReloader(ctx, NewReadyGate(nil, nil),
+		func(ctx context.Context) error { return expectedErr },
+		WithOnReloadStart(func() { started.Add(1) }),
+		WithOnReloadDone(func(err error) { done <- err }),
+	)
+
+	if _, ok := reloader.LastResult(); ok {
+		t.Error("LastResult() should report false before any reload")
+	}
+
+	reloader.Start()
+	reloader.Trigger()
+
+	select {
+	case err := <-done:
+		if err != expectedErr {
+			t.Errorf("OnReloadDone error = %v, want %v", err, expectedErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnReloadDone was not called")
+	}
+
+	if got := started.Load(); got != 1 {
+		t.Errorf("OnReloadStart called %d times, want 1", got)
+	}
+	result, ok := reloader.LastResult()
+	if !ok {
+		t.Fatal("LastResult() should report true after a reload")
+	}
+	if result.Err != expectedErr || result.Started.IsZero() {
+		t.Errorf("LastResult() = %+v, want error %v and start time", result, expectedErr)
+	}
+}
+
 func TestReloader_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -358,3 +617,35 @@ func TestReloader_ContextCancellation(t *testing.T) {
 		t.Error("Reloader did not stop after context cancellation")
 	}
 }
+
+// BenchmarkReadyGate_ServeHTTP measures the gate's overhead over calling
+// the inner handler directly.
+func BenchmarkReadyGate_ServeHTTP(b *testing.B) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	allowed := []string{"/healthz", "/setup", "/callback", "GET /"}
+
+	run := func(b *testing.B, h http.Handler, path string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		b.ReportAllocs()
+		for b.Loop() {
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	b.Run("baseline", func(b *testing.B) {
+		run(b, inner, "/api/data")
+	})
+	b.Run("ready", func(b *testing.B) {
+		gate := NewReadyGate(inner, allowed)
+		gate.SetReady()
+		run(b, gate, "/api/data")
+	})
+	b.Run("not_ready", func(b *testing.B) {
+		run(b, NewReadyGate(inner, allowed), "/api/data")
+	})
+	b.Run("not_ready_allowed", func(b *testing.B) {
+		run(b, NewReadyGate(inner, allowed), "/healthz")
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultReadyWaitTimeout is how long a long-poll request to
+	// ReadyEventsHandler waits for readiness unless ?timeout= is given.
+	DefaultReadyWaitTimeout = 30 * time.Second
+
+	// MaxReadyWaitTimeout caps the ?timeout= of long-poll requests.
+	MaxReadyWaitTimeout = 5 * time.Minute
+
+	// readyEventsKeepAlive is how often an idle event stream sends a
+	// comment so proxies do not close it.
+	readyEventsKeepAlive = 15 * time.Second
+)
+
+// readyStatus is the payload of readiness events and long-poll responses.
+type readyStatus struct {
+	Ready bool `json:"ready"`
+}
+
+// ReadyEventsHandler returns an HTTP handler that lets clients wait for the
+// gate to become ready without polling a health endpoint.
+//
+// Requests accepting text/event-stream receive server-sent events: a
+// "status" event with the current state, another on every readiness change,
+// and a final "ready" event once the gate is ready, after which the stream
+// ends. Other requests long-poll: the response is held until the gate is
+// ready (200) or the wait times out (503 with Retry-After). The wait
+// defaults to DefaultReadyWaitTimeout and can be set with a ?timeout=
+// duration, capped at MaxReadyWaitTimeout. Both bodies are JSON of the form
+// {"ready":true}.
+//
+// Mount the handler on a path outside the gate, or add its path to the
+// allowed paths, so it is reachable while not ready.
+func (rg *ReadyGate) ReadyEventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flusher, ok := w.(http.Flusher); ok && acceptsEventStream(r) {
+			rg.serveReadyEvents(w, r, flusher)
+			return
+		}
+		rg.serveReadyLongPoll(w, r)
+	})
+}
+
+// serveReadyEvents streams readiness changes as server-sent events until
+// the gate is ready or the client disconnects.
+func (rg *ReadyGate) serveReadyEvents(w http.ResponseWriter, r *http.Request, flusher http.Flusher) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(readyEventsKeepAlive)
+	defer keepAlive.Stop()
+
+	last := false
+	first := true
+	for {
+		changed := rg.changes()
+		ready := rg.IsReady()
+		if first || ready != last {
+			writeReadyEvent(w, "status", ready)
+			first, last = false, ready
+		}
+		if ready {
+			writeReadyEvent(w, "ready", true)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// serveReadyLongPoll holds the request until the gate is ready or the wait
+// times out.
+func (rg *ReadyGate) serveReadyLongPoll(w http.ResponseWriter, r *http.Request) {
+	timeout := DefaultReadyWaitTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			timeout = min(d, MaxReadyWaitTimeout)
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ready := rg.waitReady(r, timer.C)
+	if r.Context().Err() != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	status := http.StatusOK
+	if !ready {
+		w.Header().Set("Retry-After", rg.retryAfterHeader())
+		status = http.StatusServiceUnavailable
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(readyStatus{Ready: ready})
+}
+
+// waitReady blocks until the gate is ready, returning false if expired
+// fires or the request is canceled first.
+func (rg *ReadyGate) waitReady(r *http.Request, expired <-chan time.Time) bool {
+	for {
+		// Take the change channel before checking so a state change between
+		// the check and the wait is not missed.
+		changed := rg.changes()
+		if rg.IsReady() {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-expired:
+			return false
+		case <-r.Context().Done():
+			return false
+		}
+	}
+}
+
+// writeReadyEvent writes a single server-sent event with a readyStatus
+// payload.
+func writeReadyEvent(w http.ResponseWriter, event string, ready bool) {
+	data, _ := json.Marshal(readyStatus{Ready: ready})
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// acceptsEventStream reports whether the request asks for server-sent
+// events.
+func acceptsEventStream(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
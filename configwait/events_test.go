@@ -0,0 +1,118 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadyEventsHandler_LongPoll(t *testing.T) {
+	gate := NewReadyGate(nil, nil)
+	handler := gate.ReadyEventsHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready?timeout=10ms", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d on timeout", rec.Code, http.StatusServiceUnavailable)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After should be set on timeout")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		gate.SetReady()
+	}()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready?timeout=5s", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d once ready", rec.Code, http.StatusOK)
+	}
+	var body readyStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !body.Ready {
+		t.Errorf("Body = %q, want {\"ready\":true}", rec.Body.String())
+	}
+}
+
+func TestReadyEventsHandler_Stream(t *testing.T) {
+	gate := NewReadyGate(nil, nil)
+	server := httptest.NewServer(gate.ReadyEventsHandler())
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+			return ""
+		}
+	}
+	expectEvent := func(event, data string) {
+		t.Helper()
+		if got := next(); got != "event: "+event {
+			t.Fatalf("line = %q, want event %q", got, event)
+		}
+		if got := next(); got != "data: "+data {
+			t.Fatalf("line = %q, want data %q", got, data)
+		}
+		next()
+	}
+
+	expectEvent("status", `{"ready":false}`)
+	gate.SetReady()
+	expectEvent("status", `{"ready":true}`)
+	expectEvent("ready", `{"ready":true}`)
+
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Error("stream should end after the ready event")
+		}
+	case <-time.After(time.Second):
+		t.Error("stream did not end after the ready event")
+	}
+}
+
+func TestAcceptsEventStream(t *testing.T) {
+	for accept, want := range map[string]bool{
+		"":                             false,
+		"application/json":             false,
+		"text/event-stream":            true,
+		"text/html, text/event-stream": true,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		if got := acceptsEventStream(req); got != want {
+			t.Errorf("acceptsEventStream(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+)
+
+// fallbackRule pairs a path rule with the handler serving matching requests
+// while the gate is not ready.
+type fallbackRule struct {
+	rule    pathRule
+	handler http.Handler
+}
+
+// WithFallbackHandler serves requests matching path with h while the
+// service is not ready, instead of the not-ready response (e.g. a static
+// status page at "/" or a wait page at "/dashboard"). The path accepts the
+// same method prefixes as allowedPaths. When several fallbacks match, the
+// longest path wins. Once ready, requests go to the main handler.
+func WithFallbackHandler(path string, h http.Handler) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rules := parsePathRules([]string{path})
+		rg.fallbacks = append(rg.fallbacks, fallbackRule{rule: rules[0], handler: h})
+	}
+}
+
+// WithOnNotReady sets a hook called for every request the gate serves
+// with the not-ready response or a fallback handler instead of the main
+// handler, e.g. to count gated requests.
+func WithOnNotReady(fn func(r *http.Request)) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rg.onNotReady = fn
+	}
+}
+
+// serveNotReady serves a request that cannot reach the main handler yet,
+// using a matching fallback handler if one is registered.
+func (rg *ReadyGate) serveNotReady(w http.ResponseWriter, r *http.Request, message string) {
+	if rg.onNotReady != nil {
+		rg.onNotReady(r)
+	}
+	if h := rg.fallbackFor(r); h != nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+	rg.serveUnavailable(w, r, message)
+}
+
+// fallbackFor returns the handler of the longest fallback path matching r.
+func (rg *ReadyGate) fallbackFor(r *http.Request) http.Handler {
+	var best *fallbackRule
+	for i := range rg.fallbacks {
+		fb := &rg.fallbacks[i]
+		if fb.rule.matches(r) && (best == nil || len(fb.rule.prefix) > len(best.rule.prefix)) {
+			best = fb
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.handler
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyGate_FallbackHandlers(t *testing.T) {
+	body := func(s string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(s))
+		})
+	}
+	gate := NewReadyGate(body("main"), nil,
+		WithFallbackHandler("/", body("status page")),
+		WithFallbackHandler("/dashboard", body("wait page")),
+		WithFallbackHandler("GET /dashboard/live", body("live wait page")),
+	)
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{http.MethodGet, "/", http.StatusOK, "status page"},
+		{http.MethodGet, "/dashboard/settings", http.StatusOK, "wait page"},
+		{http.MethodGet, "/dashboard/live", http.StatusOK, "live wait page"},
+		{http.MethodPost, "/dashboard/live", http.StatusOK, "wait page"},
+		{http.MethodGet, "/webhook", http.StatusServiceUnavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			gate.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("Body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+
+	gate.SetReady()
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	if rec.Body.String() != "main" {
+		t.Errorf("Body = %q, want main handler once ready", rec.Body.String())
+	}
+}
+
+func TestReadyGate_OnNotReady(t *testing.T) {
+	var gated []string
+	gate := NewReadyGate(http.NotFoundHandler(), []string{"/healthz"},
+		WithFallbackHandler("/", http.NotFoundHandler()),
+		WithOnNotReady(func(r *http.Request) { gated = append(gated, r.URL.Path) }),
+	)
+	for _, path := range []string{"/healthz", "/", "/webhook"} {
+		gate.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	gate.SetReady()
+	gate.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/webhook", nil))
+
+	if len(gated) != 2 || gated[0] != "/" || gated[1] != "/webhook" {
+		t.Errorf("gated = %v, want [/ /webhook]", gated)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// Attempt kinds recorded in a History.
+const (
+	// AttemptLoad is a startup load attempt made before the first success.
+	AttemptLoad = "load"
+	// AttemptReload is a reload of already loaded configuration.
+	AttemptReload = "reload"
+)
+
+// DefaultHistorySize is the number of attempts a History retains when
+// created with a non-positive size.
+const DefaultHistorySize = 50
+
+// AttemptRecord describes a single load or reload attempt.
+type AttemptRecord struct {
+	// Kind is AttemptLoad or AttemptReload.
+	Kind string `json:"kind"`
+
+	// Attempt is the attempt number within a Wait, starting at 1. It is
+	// zero for reloads.
+	Attempt int `json:"attempt,omitempty"`
+
+	// Started is when the attempt began.
+	Started time.Time `json:"started"`
+
+	// Duration is how long the attempt ran.
+	Duration time.Duration `json:"-"`
+
+	// Error is the attempt's error message, empty on success.
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes Duration as a Go duration string (e.g. "1.5s").
+func (a AttemptRecord) MarshalJSON() ([]byte, error) {
+	type record AttemptRecord
+	return json.Marshal(struct {
+		record
+		Duration string `json:"duration"`
+	}{record(a), a.Duration.String()})
+}
+
+// History keeps the most recent load and reload attempts for debugging.
+// Record attempts by setting Config.History for Wait and WithReloadHistory
+// for a Reloader, then expose them with StatusHandler. A History is safe
+// for concurrent use.
+type History struct {
+	mu      sync.Mutex
+	size    int
+	records []AttemptRecord
+}
+
+// NewHistory creates a History retaining up to size attempts, or
+// DefaultHistorySize if size is not positive.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{size: size}
+}
+
+// Record appends an attempt, discarding the oldest once full.
+func (h *History) Record(rec AttemptRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.records) >= h.size {
+		h.records = append(h.records[:0], h.records[len(h.records)-h.size+1:]...)
+	}
+	h.records = append(h.records, rec)
+}
+
+// Records returns the retained attempts, oldest first.
+func (h *History) Records() []AttemptRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]AttemptRecord, len(h.records))
+	copy(out, h.records)
+	return out
+}
+
+// RecordAttempt appends a finished attempt that started at the given
+// time. The attempt number is kept for loads only. It is a no-op on a nil
+// History.
+func (h *History) RecordAttempt(kind string, attempt int, started time.Time, err error) {
+	if h == nil {
+		return
+	}
+	rec := AttemptRecord{
+		Kind:     kind,
+		Started:  started,
+		Duration: time.Since(started),
+	}
+	if kind == AttemptLoad {
+		rec.Attempt = attempt
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	h.Record(rec)
+}
+
+// StatusHandler returns an HTTP handler exposing the attempt history as
+// JSON, oldest first, for mounting under a debug path.
+func StatusHandler(h *History) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Attempts []AttemptRecord `json:"attempts"`
+		}{h.Records()}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			clog.FromContext(r.Context()).Errorf("[configwait] failed to write status response: %v", err)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistory_DiscardsOldest(t *testing.T) {
+	h := NewHistory(2)
+	for i := 1; i <= 3; i++ {
+		h.Record(AttemptRecord{Kind: AttemptLoad, Attempt: i})
+	}
+
+	records := h.Records()
+	if len(records) != 2 || records[0].Attempt != 2 || records[1].Attempt != 3 {
+		t.Errorf("Records() = %+v, want attempts 2 and 3", records)
+	}
+}
+
+func TestHistory_RecordAttempt(t *testing.T) {
+	var nilHistory *History
+	nilHistory.RecordAttempt(AttemptLoad, 1, time.Now(), nil)
+
+	h := NewHistory(0)
+	h.RecordAttempt(AttemptLoad, 2, time.Now(), errors.New("timeout"))
+	h.RecordAttempt(AttemptReload, 3, time.Now(), nil)
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("Records() = %+v, want 2 attempts", records)
+	}
+	if records[0].Attempt != 2 || records[0].Error != "timeout" {
+		t.Errorf("load record = %+v, want attempt 2 with error", records[0])
+	}
+	if records[1].Attempt != 0 || records[1].Error != "" {
+		t.Errorf("reload record = %+v, want no attempt number or error", records[1])
+	}
+}
+
+func TestWait_RecordsHistory(t *testing.T) {
+	h := NewHistory(0)
+	count := 0
+	err := Wait(context.Background(), Config{MaxRetries: 3, RetryInterval: time.Millisecond, History: h},
+		func(ctx context.Context) error {
+			count++
+			if count == 1 {
+				return errors.New("not ready")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("Records() has %d entries, want 2", len(records))
+	}
+	if records[0].Kind != AttemptLoad || records[0].Attempt != 1 || records[0].Error != "not ready" {
+		t.Errorf("first record = %+v, want failed load attempt 1", records[0])
+	}
+	if records[1].Attempt != 2 || records[1].Error != "" {
+		t.Errorf("second record = %+v, want successful attempt 2", records[1])
+	}
+}
+
+func TestReloader_RecordsHistory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewHistory(0)
+	done := make(chan struct{})
+	reloader := NewReloader(ctx, nil,
+		func(ctx context.Context) error { return errors.New("bad config") },
+		WithReloadHistory(h),
+		WithOnReloadDone(func(error) { close(done) }),
+	)
+	reloader.Start()
+	reloader.Trigger()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reload did not complete")
+	}
+
+	records := h.Records()
+	if len(records) != 1 || records[0].Kind != AttemptReload || records[0].Error != "bad config" {
+		t.Errorf("Records() = %+v, want one failed reload", records)
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	h := NewHistory(0)
+	h.Record(AttemptRecord{
+		Kind:     AttemptLoad,
+		Attempt:  1,
+		Started:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration: 1500 * time.Millisecond,
+		Error:    "timeout",
+	})
+
+	rec := httptest.NewRecorder()
+	StatusHandler(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/configwait", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body struct {
+		Attempts []map[string]any `json:"attempts"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body.Attempts) != 1 {
+		t.Fatalf("attempts = %v, want 1 entry", body.Attempts)
+	}
+	got := body.Attempts[0]
+	if got["kind"] != AttemptLoad || got["duration"] != "1.5s" || got["error"] != "timeout" || got["started"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("attempt = %v, want kind, duration, error, and start time", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+	"time"
+)
+
+// holdPolicy bounds how many requests are parked and for how long.
+type holdPolicy struct {
+	max     int64
+	timeout time.Duration
+}
+
+// WithHoldRequests parks gated requests that arrive while the service is
+// not ready, instead of rejecting them immediately, and releases them to
+// the inner handler once the gate opens. At most maxHeld requests are
+// parked at once and each waits at most timeout; requests beyond the limit,
+// or whose wait times out, get the not-ready response. This avoids bursts
+// of 503s during the few seconds of a rolling restart.
+func WithHoldRequests(maxHeld int, timeout time.Duration) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		if maxHeld <= 0 || timeout <= 0 {
+			rg.hold = nil
+			return
+		}
+		rg.hold = &holdPolicy{max: int64(maxHeld), timeout: timeout}
+	}
+}
+
+// Held returns the number of requests currently parked waiting for
+// readiness.
+func (rg *ReadyGate) Held() int {
+	return int(rg.held.Load())
+}
+
+// holdUntilReady parks r until the gate is ready with a handler set,
+// returning false if holding is disabled, the limit is reached, the hold
+// times out, or the request is canceled.
+func (rg *ReadyGate) holdUntilReady(r *http.Request) bool {
+	if rg.hold == nil {
+		return false
+	}
+	if rg.held.Add(1) > rg.hold.max {
+		rg.held.Add(-1)
+		return false
+	}
+	defer rg.held.Add(-1)
+
+	timer := time.NewTimer(rg.hold.timeout)
+	defer timer.Stop()
+	for {
+		// Take the change channel before checking so a state change between
+		// the check and the wait is not missed.
+		changed := rg.changes()
+		if rg.IsReady() && rg.getHandler() != nil {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		case <-r.Context().Done():
+			return false
+		}
+	}
+}
+
+// changes returns a channel that is closed on the next readiness change.
+func (rg *ReadyGate) changes() <-chan struct{} {
+	rg.changeMu.Lock()
+	defer rg.changeMu.Unlock()
+	if rg.changed == nil {
+		rg.changed = make(chan struct{})
+	}
+	return rg.changed
+}
+
+// notify wakes held requests so they re-check readiness.
+func (rg *ReadyGate) notify() {
+	rg.changeMu.Lock()
+	defer rg.changeMu.Unlock()
+	if rg.changed != nil {
+		close(rg.changed)
+		rg.changed = nil
+	}
+}
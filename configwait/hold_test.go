@@ -0,0 +1,73 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestReadyGate_HoldReleasesOnReady(t *testing.T) {
+	gate := NewReadyGate(okHandler(), nil, WithHoldRequests(2, time.Second))
+
+	codes := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			rec := httptest.NewRecorder()
+			gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+			codes <- rec.Code
+		}()
+	}
+
+	// The third request exceeds the hold limit and is rejected immediately
+	select {
+	case code := <-codes:
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("Status = %d, want %d beyond hold limit", code, http.StatusServiceUnavailable)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("request beyond hold limit was not rejected")
+	}
+	if held := gate.Held(); held != 2 {
+		t.Errorf("Held() = %d, want 2", held)
+	}
+
+	gate.SetReady()
+	for i := 0; i < 2; i++ {
+		select {
+		case code := <-codes:
+			if code != http.StatusOK {
+				t.Errorf("Status = %d, want %d once ready", code, http.StatusOK)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("held request was not released")
+		}
+	}
+}
+
+func TestReadyGate_HoldTimeout(t *testing.T) {
+	gate := NewReadyGate(okHandler(), nil, WithHoldRequests(1, 20*time.Millisecond))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d after hold timeout", rec.Code, http.StatusServiceUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("request rejected after %v, want it held for the timeout", elapsed)
+	}
+	if held := gate.Held(); held != 0 {
+		t.Errorf("Held() = %d, want 0", held)
+	}
+}
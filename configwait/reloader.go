@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/chainguard-dev/clog"
 )
@@ -16,25 +17,81 @@ import (
 // ReloadFunc is called when a reload is triggered.
 type ReloadFunc func(ctx context.Context) error
 
+// ReloadResult describes a completed reload.
+type ReloadResult struct {
+	// Started is when the reload began.
+	Started time.Time
+
+	// Duration is how long the reload function ran.
+	Duration time.Duration
+
+	// Err is the error returned by the reload function, nil on success.
+	Err error
+}
+
 // Reloader manages configuration reloading via SIGHUP or programmatic triggers.
 type Reloader struct {
 	gate       *ReadyGate
 	reloadFunc ReloadFunc
 	ctx        context.Context
+	onStart    func()
+	onDone     func(err error)
+	history    *History
 
 	mu        sync.Mutex
 	reloading bool
 	reloadCh  chan struct{}
+	last      *ReloadResult
+}
+
+// ReloaderOption is a functional option for configuring Reloader.
+type ReloaderOption func(*Reloader)
+
+// WithOnReloadStart sets a hook called before each reload runs.
+func WithOnReloadStart(fn func()) ReloaderOption {
+	return func(r *Reloader) {
+		r.onStart = fn
+	}
+}
+
+// WithOnReloadDone sets a hook called after each reload with its error,
+// nil on success.
+func WithOnReloadDone(fn func(err error)) ReloaderOption {
+	return func(r *Reloader) {
+		r.onDone = fn
+	}
+}
+
+// WithReloadHistory records every reload in h for StatusHandler.
+func WithReloadHistory(h *History) ReloaderOption {
+	return func(r *Reloader) {
+		r.history = h
+	}
 }
 
 // NewReloader creates a Reloader that calls reloadFunc when triggered.
-func NewReloader(ctx context.Context, gate *ReadyGate, reloadFunc ReloadFunc) *Reloader {
-	return &Reloader{
+func NewReloader(ctx context.Context, gate *ReadyGate, reloadFunc ReloadFunc, opts ...ReloaderOption) *Reloader {
+	r := &Reloader{
 		gate:       gate,
 		reloadFunc: reloadFunc,
 		ctx:        ctx,
 		reloadCh:   make(chan struct{}, 1),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// LastResult returns the result of the most recent completed reload of
+// this Reloader. It returns false if no reload has completed yet.
+func (r *Reloader) LastResult() (ReloadResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		return ReloadResult{}, false
+	}
+	return *r.last, true
 }
 
 // Start begins listening for SIGHUP signals and programmatic triggers.
@@ -98,8 +155,23 @@ func (r *Reloader) doReload() {
 	}()
 
 	log.Infof("[reloader] starting configuration reload...")
+	if r.onStart != nil {
+		r.onStart()
+	}
+
+	started := time.Now()
+	err := r.reloadFunc(r.ctx)
+
+	r.mu.Lock()
+	r.last = &ReloadResult{Started: started, Duration: time.Since(started), Err: err}
+	r.mu.Unlock()
+	r.history.RecordAttempt(AttemptReload, 0, started, err)
+
+	if r.onDone != nil {
+		r.onDone(err)
+	}
 
-	if err := r.reloadFunc(r.ctx); err != nil {
+	if err != nil {
 		log.Errorf("[reloader] reload failed: %v", err)
 		return
 	}
@@ -0,0 +1,134 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"encoding/json"
+	"html"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnavailableResponse is the response written for a request the ReadyGate
+// rejects because the service is not ready.
+type UnavailableResponse struct {
+	// Status is the HTTP status code. If zero, 503 is used.
+	Status int
+
+	// Header holds response headers. The gate's Retry-After (see
+	// WithRetryAfter) is added unless one is set.
+	Header http.Header
+
+	// Body is the response body.
+	Body []byte
+}
+
+// DefaultRetryAfter is the Retry-After sent with not-ready responses unless
+// overridden with WithRetryAfter or WithRetryAfterFunc.
+const DefaultRetryAfter = 5 * time.Second
+
+// WithRetryAfter sets a fixed Retry-After for not-ready responses.
+func WithRetryAfter(d time.Duration) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rg.retryAfter = func() time.Duration { return d }
+	}
+}
+
+// WithRetryAfterFunc computes the Retry-After for each not-ready response,
+// e.g. from the remaining expected startup wait (see Config.RemainingWait),
+// so well-behaved clients back off appropriately.
+func WithRetryAfterFunc(fn func() time.Duration) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rg.retryAfter = fn
+	}
+}
+
+// retryAfterHeader returns the Retry-After header value in whole seconds,
+// rounded up and at least 1.
+func (rg *ReadyGate) retryAfterHeader() string {
+	d := DefaultRetryAfter
+	if rg.retryAfter != nil {
+		d = rg.retryAfter()
+	}
+	secs := int64(math.Ceil(d.Seconds()))
+	return strconv.FormatInt(max(secs, 1), 10)
+}
+
+// UnavailableFunc builds the response for a rejected request. The message
+// describes why the request was rejected (e.g. "service starting up").
+type UnavailableFunc func(r *http.Request, message string) UnavailableResponse
+
+// WithUnavailableResponse replaces the gate's not-ready response, e.g. to
+// serve a branded "starting up" page. The default is
+// DefaultUnavailableResponse.
+func WithUnavailableResponse(fn UnavailableFunc) ReadyGateOption {
+	return func(rg *ReadyGate) {
+		rg.unavailable = fn
+	}
+}
+
+// DefaultUnavailableResponse returns a 503 with an HTML "starting up" page
+// for browsers (requests preferring text/html) and a JSON error otherwise.
+func DefaultUnavailableResponse(r *http.Request, message string) UnavailableResponse {
+	if prefersHTML(r) {
+		return HTMLUnavailableResponse(r, message)
+	}
+	return JSONUnavailableResponse(r, message)
+}
+
+// JSONUnavailableResponse returns a 503 with a JSON body of the form
+// {"error":"service_unavailable","message":"..."}.
+func JSONUnavailableResponse(r *http.Request, message string) UnavailableResponse {
+	body, _ := json.Marshal(map[string]string{
+		"error":   "service_unavailable",
+		"message": message,
+	})
+	return UnavailableResponse{
+		Status: http.StatusServiceUnavailable,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   append(body, '\n'),
+	}
+}
+
+// HTMLUnavailableResponse returns a 503 with a minimal HTML page that shows
+// message and refreshes itself every 5 seconds.
+func HTMLUnavailableResponse(r *http.Request, message string) UnavailableResponse {
+	body := `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>Starting up</title>
+</head>
+<body>
+<h1>Starting up</h1>
+<p>` + html.EscapeString(message) + `. This page will refresh automatically.</p>
+</body>
+</html>
+`
+	return UnavailableResponse{
+		Status: http.StatusServiceUnavailable,
+		Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:   []byte(body),
+	}
+}
+
+// prefersHTML reports whether the Accept header lists text/html before
+// any JSON media type. Browsers send text/html first; API clients usually
+// send application/json or nothing.
+func prefersHTML(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		switch mediaType = strings.ToLower(strings.TrimSpace(mediaType)); {
+		case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+			return true
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package configwait
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadyGate_ContentNegotiation(t *testing.T) {
+	gate := NewReadyGate(http.NotFoundHandler(), nil)
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json"},
+		{"application/json", "application/json"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "text/html; charset=utf-8"},
+		{"application/json, text/html", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			gate.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Errorf("Status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if rec.Header().Get("Retry-After") != "5" {
+				t.Errorf("Retry-After = %q, want %q", rec.Header().Get("Retry-After"), "5")
+			}
+		})
+	}
+}
+
+func TestReadyGate_WithUnavailableResponse(t *testing.T) {
+	gate := NewReadyGate(http.NotFoundHandler(), nil, WithUnavailableResponse(
+		func(r *http.Request, message string) UnavailableResponse {
+			return UnavailableResponse{
+				Status: http.StatusTooManyRequests,
+				Header: http.Header{"Retry-After": {"30"}, "Content-Type": {"text/plain"}},
+				Body:   []byte("busy: " + message),
+			}
+		},
+	))
+
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("Retry-After = %q, want %q", rec.Header().Get("Retry-After"), "30")
+	}
+	if !strings.HasPrefix(rec.Body.String(), "busy: ") {
+		t.Errorf("Body = %q, want custom body", rec.Body.String())
+	}
+}
+
+func TestHTMLUnavailableResponse_EscapesMessage(t *testing.T) {
+	resp := HTMLUnavailableResponse(httptest.NewRequest(http.MethodGet, "/", nil), "<script>")
+	if strings.Contains(string(resp.Body), "<script>") {
+		t.Error("HTMLUnavailableResponse() should escape the message")
+	}
+}
+
+func TestReadyGate_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ReadyGateOption
+		want string
+	}{
+		{"default", nil, "5"},
+		{"fixed", []ReadyGateOption{WithRetryAfter(30 * time.Second)}, "30"},
+		{"rounds up", []ReadyGateOption{WithRetryAfter(1500 * time.Millisecond)}, "2"},
+		{"minimum", []ReadyGateOption{WithRetryAfter(0)}, "1"},
+		{"func", []ReadyGateOption{WithRetryAfterFunc(func() time.Duration { return time.Minute })}, "60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := NewReadyGate(http.NotFoundHandler(), nil, tt.opts...)
+			rec := httptest.NewRecorder()
+			gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if got := rec.Header().Get("Retry-After"); got != tt.want {
+				t.Errorf("Retry-After = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
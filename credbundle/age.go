@@ -0,0 +1,68 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package credbundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"filippo.io/age"
+)
+
+// AgeSealer encrypts bundles to age recipients. The output is a standard
+// age file, so it can be decrypted with the age CLI when this tool is
+// unavailable.
+type AgeSealer struct {
+	recipients []age.Recipient
+}
+
+// NewAgeSealer creates a sealer for the given recipients, e.g. parsed
+// with age.ParseRecipients or created with age.NewScryptRecipient.
+func NewAgeSealer(recipients ...age.Recipient) *AgeSealer {
+	return &AgeSealer{recipients: recipients}
+}
+
+// Seal implements Sealer.
+func (s *AgeSealer) Seal(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if len(s.recipients) == 0 {
+		return nil, errors.New("no age recipients configured")
+	}
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, s.recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AgeOpener decrypts age-encrypted bundles.
+type AgeOpener struct {
+	identities []age.Identity
+}
+
+// NewAgeOpener creates an opener for the given identities, e.g. parsed
+// with age.ParseIdentities or created with age.NewScryptIdentity.
+func NewAgeOpener(identities ...age.Identity) *AgeOpener {
+	return &AgeOpener{identities: identities}
+}
+
+// Open implements Opener.
+func (o *AgeOpener) Open(ctx context.Context, data []byte) ([]byte, error) {
+	if len(o.identities) == 0 {
+		return nil, errors.New("no age identities configured")
+	}
+	r, err := age.Decrypt(bytes.NewReader(data), o.identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package credbundle exports GitHub App credentials from a configstore
+// backend into an encrypted bundle and imports them into another, for
+// backups, cloning an environment, and break-glass recovery.
+//
+// Bundles are encrypted either with age, so they can also be opened with
+// the age CLI, or with an AWS KMS data key:
+//
+//	data, err := credbundle.Export(ctx, store, credbundle.NewAgeSealer(recipient))
+//	...
+//	bundle, err := credbundle.Import(ctx, data, credbundle.NewAgeOpener(identity), target)
+package credbundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// Version is the bundle format version written by Export.
+const Version = 1
+
+// Bundle is the plaintext content of an encrypted bundle.
+type Bundle struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Credentials  *configstore.AppCredentials `json:"credentials"`
+	CustomFields map[string]string           `json:"custom_fields,omitempty"`
+
+	InstallerDisabled bool                       `json:"installer_disabled,omitempty"`
+	Installations     []configstore.Installation `json:"installations,omitempty"`
+}
+
+// Sealer encrypts a bundle's plaintext.
+type Sealer interface {
+	Seal(ctx context.Context, plaintext []byte) ([]byte, error)
+}
+
+// Opener decrypts data produced by the matching Sealer.
+type Opener interface {
+	Open(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// Export reads the credentials, installer state, and installations from
+// store and returns them sealed by s. The store must implement
+// configstore.CredentialLoader; installations are included when it
+// implements configstore.InstallationStore.
+func Export(ctx context.Context, store configstore.Store, s Sealer) ([]byte, error) {
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return nil, errors.New("store cannot load credentials")
+	}
+	creds, err := loader.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	status, err := store.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installer status: %w", err)
+	}
+
+	bundle := &Bundle{
+		Version:           Version,
+		CreatedAt:         time.Now().UTC(),
+		Credentials:       creds,
+		CustomFields:      creds.CustomFields,
+		InstallerDisabled: status.InstallerDisabled,
+	}
+	if is, ok := store.(configstore.InstallationStore); ok {
+		if bundle.Installations, err = is.Installations(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read installations: %w", err)
+		}
+	}
+
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	return s.Seal(ctx, plaintext)
+}
+
+// Open decrypts data with o and decodes the bundle without writing it
+// anywhere.
+func Open(ctx context.Context, data []byte, o Opener) (*Bundle, error) {
+	plaintext, err := o.Open(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	if bundle.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	if bundle.Credentials == nil {
+		return nil, errors.New("bundle has no credentials")
+	}
+	bundle.Credentials.CustomFields = bundle.CustomFields
+	return &bundle, nil
+}
+
+// Import decrypts data with o and saves the bundle to store: the
+// credentials, the installations when store implements
+// configstore.InstallationStore, and the disabled installer flag.
+func Import(ctx context.Context, data []byte, o Opener, store configstore.Store) (*Bundle, error) {
+	bundle, err := Open(ctx, data, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Save(ctx, bundle.Credentials); err != nil {
+		return nil, fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if is, ok := store.(configstore.InstallationStore); ok {
+		for _, inst := range bundle.Installations {
+			if err := is.SaveInstallation(ctx, inst); err != nil {
+				return nil, fmt.Errorf("failed to save installation %d: %w", inst.ID, err)
+			}
+		}
+	}
+	if bundle.InstallerDisabled {
+		if err := store.DisableInstaller(ctx); err != nil {
+			return nil, fmt.Errorf("failed to disable installer: %w", err)
+		}
+	}
+	return bundle, nil
+}
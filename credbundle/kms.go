@@ -0,0 +1,127 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package credbundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// kmsFormat identifies KMS envelopes. It is also the AES-GCM additional
+// data, binding the ciphertext to the format.
+const kmsFormat = "ghappsetup-kms-v1"
+
+// KMSClient defines the KMS operations used for envelope encryption.
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// kmsEnvelope is the JSON document KMSSealer writes: the bundle
+// encrypted with AES-256-GCM under a data key, and the data key
+// encrypted by KMS.
+type kmsEnvelope struct {
+	Format       string `json:"format"`
+	KeyID        string `json:"key_id"`
+	EncryptedKey []byte `json:"encrypted_key"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// KMSSealer encrypts bundles with a data key generated by an AWS KMS key
+// and decrypts them with KMS. Opening needs only kms:Decrypt on the key;
+// the key is identified by the encrypted data key.
+type KMSSealer struct {
+	client KMSClient
+	keyID  string
+}
+
+// NewKMSSealer creates a KMS sealer. keyID may be a key ID, ARN, or alias
+// such as "alias/github-app-backup"; it is only needed to seal.
+func NewKMSSealer(client KMSClient, keyID string) *KMSSealer {
+	return &KMSSealer{client: client, keyID: keyID}
+}
+
+// IsKMSEnvelope reports whether data was sealed by a KMSSealer.
+func IsKMSEnvelope(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+	var env kmsEnvelope
+	return json.Unmarshal(data, &env) == nil && env.Format == kmsFormat
+}
+
+// Seal implements Sealer.
+func (s *KMSSealer) Seal(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if s.keyID == "" {
+		return nil, errors.New("no KMS key configured")
+	}
+	key, err := s.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(s.keyID),
+		KeySpec: types.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	gcm, err := newGCM(key.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	keyID := aws.ToString(key.KeyId)
+	if keyID == "" {
+		keyID = s.keyID
+	}
+	return json.MarshalIndent(kmsEnvelope{
+		Format:       kmsFormat,
+		KeyID:        keyID,
+		EncryptedKey: key.CiphertextBlob,
+		Nonce:        nonce,
+		Ciphertext:   gcm.Seal(nil, nonce, plaintext, []byte(kmsFormat)),
+	}, "", "  ")
+}
+
+// Open implements Opener.
+func (s *KMSSealer) Open(ctx context.Context, data []byte) ([]byte, error) {
+	var env kmsEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Format != kmsFormat {
+		return nil, errors.New("not a KMS-encrypted bundle")
+	}
+
+	key, err := s.client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: env.EncryptedKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	gcm, err := newGCM(key.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return gcm.Open(nil, env.Nonce, env.Ciphertext, []byte(kmsFormat))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package credbundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// fakeKMS wraps data keys by prefixing them with the key ID.
+type fakeKMS struct {
+	keyID string
+}
+
+func (f *fakeKMS) GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return &kms.GenerateDataKeyOutput{
+		KeyId:          aws.String(f.keyID),
+		Plaintext:      key,
+		CiphertextBlob: append([]byte(f.keyID+":"), key...),
+	}, nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	key, ok := bytes.CutPrefix(params.CiphertextBlob, []byte(f.keyID+":"))
+	if !ok {
+		return nil, errors.New("access denied")
+	}
+	return &kms.DecryptOutput{Plaintext: key}, nil
+}
+
+func TestKMSSealer(t *testing.T) {
+	ctx := context.Background()
+	sealer := NewKMSSealer(&fakeKMS{keyID: "arn:aws:kms:us-east-1:123:key/abc"}, "alias/backup")
+
+	data, err := sealer.Seal(ctx, []byte("secret bundle"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !IsKMSEnvelope(data) {
+		t.Fatal("IsKMSEnvelope() = false for a KMS bundle")
+	}
+	if bytes.Contains(data, []byte("secret bundle")) {
+		t.Error("sealed bundle contains the plaintext")
+	}
+
+	var env kmsEnvelope
+	_ = json.Unmarshal(data, &env)
+	if env.KeyID != "arn:aws:kms:us-east-1:123:key/abc" {
+		t.Errorf("envelope key_id = %q, want the resolved key ARN", env.KeyID)
+	}
+
+	// Opening needs no key ID
+	got, err := NewKMSSealer(&fakeKMS{keyID: "arn:aws:kms:us-east-1:123:key/abc"}, "").Open(ctx, data)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if string(got) != "secret bundle" {
+		t.Errorf("Open() = %q, want %q", got, "secret bundle")
+	}
+
+	if _, err := NewKMSSealer(&fakeKMS{keyID: "other"}, "").Open(ctx, data); err == nil {
+		t.Error("Open() with another key succeeded")
+	}
+
+	env.Ciphertext[0] ^= 0xff
+	tampered, _ := json.Marshal(env)
+	if _, err := sealer.Open(ctx, tampered); err == nil {
+		t.Error("Open() of a tampered bundle succeeded")
+	}
+}
+
+func TestIsKMSEnvelope(t *testing.T) {
+	tests := map[string][]byte{
+		"age file":     []byte("age-encryption.org/v1\n-> X25519 abc\n"),
+		"other json":   []byte(`{"format":"something-else"}`),
+		"invalid json": []byte(`{"format":`),
+	}
+	for name, data := range tests {
+		if IsKMSEnvelope(data) {
+			t.Errorf("IsKMSEnvelope(%s) = true, want false", name)
+		}
+	}
+}
@@ -7,10 +7,6 @@ package main
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -20,9 +16,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cruxstack/github-app-setup-go/appinfo"
 	"github.com/cruxstack/github-app-setup-go/configstore"
 	"github.com/cruxstack/github-app-setup-go/ghappsetup"
 	"github.com/cruxstack/github-app-setup-go/installer"
+	"github.com/cruxstack/github-app-setup-go/webhook"
 )
 
 const (
@@ -45,16 +43,12 @@ func main() {
 
 	installerEnabled := configstore.InstallerEnabled()
 
-	// Determine allowed paths for the ReadyGate
-	allowedPaths := []string{"/healthz"}
-	if installerEnabled {
-		allowedPaths = append(allowedPaths, "/setup", "/callback", "/")
-	}
-
 	// Create the Runtime with unified lifecycle management
 	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
 		LoadFunc:     func(ctx context.Context) error { return loadConfig(ctx, log) },
-		AllowedPaths: allowedPaths,
+		AllowedPaths: []string{"/healthz"},
+		// Fill in the app slug and HTML URL when the credentials lack them
+		AfterLoad: appinfo.BackfillOnLoad,
 	})
 	if err != nil {
 		log.Error("failed to create runtime", "error", err)
@@ -66,7 +60,8 @@ func main() {
 	mux.HandleFunc("/healthz", runtime.HealthHandler())
 	mux.HandleFunc("/webhook", webhookHandler(log))
 
-	// Set up installer if enabled (using Option B: convenience method)
+	// Set up installer if enabled; MountInstaller registers the installer
+	// routes and keeps them reachable before configuration loads
 	if installerEnabled {
 		manifest := installer.Manifest{
 			URL:    "https://github.com/cruxstack/github-app-setup-go",
@@ -81,66 +76,32 @@ func main() {
 			},
 		}
 
-		installerHandler, err := runtime.InstallerHandler(installer.Config{
+		if err := runtime.MountInstaller(mux, installer.Config{
 			Manifest:       manifest,
 			AppDisplayName: "Simple Webhook App",
 			GitHubURL:      configstore.GetEnvDefault("GITHUB_URL", "https://github.com"),
 			GitHubOrg:      os.Getenv("GITHUB_ORG"),
-		})
-		if err != nil {
-			log.Error("failed to create installer handler", "error", err)
+		}); err != nil {
+			log.Error("failed to mount installer", "error", err)
 			os.Exit(1)
 		}
 
-		mux.Handle("/setup", installerHandler)
-		mux.Handle("/setup/", installerHandler)
-		mux.Handle("/callback", installerHandler)
-		mux.Handle("/", installerHandler)
-
 		log.Info("installer enabled, visit /setup to create GitHub App")
 	}
 
-	// Create server with Runtime's handler (includes ReadyGate)
-	srv := &http.Server{
-		Addr:              fmt.Sprintf(":%d", port),
-		ReadHeaderTimeout: defaultReadHeaderTimeout,
-		Handler:           runtime.Handler(mux),
-	}
-
 	log.Info("starting HTTP server", "port", port, "installer_enabled", installerEnabled)
 
-	// Start the HTTP server
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error("server error", "error", err)
-			os.Exit(1)
-		}
-	}()
-
-	// Start configuration loading (blocks until config is loaded)
-	go func() {
-		if err := runtime.Start(ctx); err != nil {
-			log.Error("failed to load configuration after retries", "error", err)
-			os.Exit(1)
-		}
-		log.Info("configuration loaded, service is ready")
-
-		// Listen for reload triggers (SIGHUP or installer callback)
-		done := runtime.ListenForReloads(ctx)
-		log.Info("configuration reloader started (send SIGHUP to reload)")
-		<-done
-	}()
-
-	<-ctx.Done()
-	log.Info("shutting down server...")
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
-	defer shutdownCancel()
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Error("server shutdown error", "error", err)
+	// Run the server: serves behind the ReadyGate, blocks until config is
+	// loaded, listens for reloads (SIGHUP or installer callback), and shuts
+	// down gracefully when ctx is canceled.
+	if err := runtime.RunHTTP(ctx, fmt.Sprintf(":%d", port), mux,
+		ghappsetup.WithReadHeaderTimeout(defaultReadHeaderTimeout),
+		ghappsetup.WithShutdownTimeout(defaultShutdownTimeout),
+	); err != nil {
+		log.Error("server error", "error", err)
 		os.Exit(1)
 	}
+	log.Info("server stopped")
 }
 
 // loadConfig loads configuration from environment variables.
@@ -178,7 +139,7 @@ func webhookHandler(log *slog.Logger) http.HandlerFunc {
 		// Get webhook secret from environment (loaded by loadConfig)
 		secret := os.Getenv(configstore.EnvGitHubWebhookSecret)
 		signature := r.Header.Get("X-Hub-Signature-256")
-		if !validateSignature(body, signature, secret) {
+		if !webhook.VerifySignature(body, signature, secret) {
 			log.Warn("webhook signature validation failed",
 				"remote_addr", r.RemoteAddr,
 				"has_signature", signature != "",
@@ -190,17 +151,11 @@ func webhookHandler(log *slog.Logger) http.HandlerFunc {
 		eventType := r.Header.Get("X-GitHub-Event")
 		deliveryID := r.Header.Get("X-GitHub-Delivery")
 
-		var payload struct {
-			Action     string `json:"action"`
-			Repository struct {
-				FullName string `json:"full_name"`
-			} `json:"repository"`
-			Sender struct {
-				Login string `json:"login"`
-			} `json:"sender"`
-		}
-		if err := json.Unmarshal(body, &payload); err != nil {
+		delivery := &webhook.Delivery{ID: deliveryID, Event: eventType, Payload: body}
+		payload, err := delivery.Common()
+		if err != nil {
 			log.Warn("failed to parse webhook payload", "error", err)
+			payload = &webhook.Common{}
 		}
 
 		log.Info("received webhook",
@@ -217,24 +172,6 @@ func webhookHandler(log *slog.Logger) http.HandlerFunc {
 	}
 }
 
-// validateSignature validates the GitHub webhook signature.
-func validateSignature(payload []byte, signature, secret string) bool {
-	if signature == "" || secret == "" {
-		return false
-	}
-
-	if !strings.HasPrefix(signature, "sha256=") {
-		return false
-	}
-	sig := strings.TrimPrefix(signature, "sha256=")
-
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(payload)
-	expected := hex.EncodeToString(mac.Sum(nil))
-
-	return hmac.Equal([]byte(sig), []byte(expected))
-}
-
 // setupLogger creates a slog.Logger based on LOG_FORMAT environment variable.
 func setupLogger() *slog.Logger {
 	format := strings.ToLower(os.Getenv("LOG_FORMAT"))
@@ -0,0 +1,218 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+	"github.com/chainguard-dev/clog"
+)
+
+// DefaultPollInterval is how often AppConfig is polled unless
+// WithPollInterval is set. AppConfig may ask for longer intervals.
+const DefaultPollInterval = 30 * time.Second
+
+// pollTimeout bounds each AppConfig poll, which runs detached from the
+// evaluation that started it.
+const pollTimeout = 30 * time.Second
+
+// AppConfigDataClient defines the interface for AWS AppConfig Data
+// operations.
+type AppConfigDataClient interface {
+	StartConfigurationSession(ctx context.Context, params *appconfigdata.StartConfigurationSessionInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error)
+	GetLatestConfiguration(ctx context.Context, params *appconfigdata.GetLatestConfigurationInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error)
+}
+
+// AppConfig is a Provider reading flags from an AWS AppConfig
+// configuration profile, either a feature flag profile or a freeform JSON
+// object of booleans. The profile is fetched on first use and polled again
+// in the background when the poll interval has passed at the next
+// evaluation, so flag changes apply shortly after one interval without a
+// restart. If a poll fails, the flags from the last successful poll stay
+// in use.
+type AppConfig struct {
+	Application string
+	Environment string
+	Profile     string
+
+	client       AppConfigDataClient
+	pollInterval time.Duration
+	now          func() time.Time
+
+	// token is only used by the running poll
+	token string
+
+	mu       sync.Mutex
+	flags    map[string]bool
+	nextPoll time.Time
+	polling  chan struct{} // closed when the running poll finishes
+	pollErr  error
+}
+
+// AppConfigOption is a functional option for configuring AppConfig.
+type AppConfigOption func(*AppConfig)
+
+// WithAppConfigDataClient sets a custom AppConfig Data client.
+func WithAppConfigDataClient(client AppConfigDataClient) AppConfigOption {
+	return func(a *AppConfig) {
+		a.client = client
+	}
+}
+
+// WithPollInterval sets the minimum interval between polls. AppConfig
+// rejects intervals under 15 seconds. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) AppConfigOption {
+	return func(a *AppConfig) {
+		a.pollInterval = d
+	}
+}
+
+// NewAppConfig creates a provider for the configuration profile of the
+// application and environment, each given by name or ID.
+func NewAppConfig(application, environment, profile string, opts ...AppConfigOption) (*AppConfig, error) {
+	a := &AppConfig{
+		Application:  application,
+		Environment:  environment,
+		Profile:      profile,
+		pollInterval: DefaultPollInterval,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.client == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		a.client = appconfigdata.NewFromConfig(cfg)
+	}
+	return a, nil
+}
+
+// Enabled reports whether the flag is enabled. Once the interval has
+// passed it starts a poll in the background and answers from the flags
+// already fetched, so evaluations never wait on AppConfig after the first
+// one. It returns ErrNotFound for flags missing from the profile.
+func (a *AppConfig) Enabled(ctx context.Context, key string) (bool, error) {
+	flags, err := a.currentFlags(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	enabled, ok := flags[key]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return enabled, nil
+}
+
+// currentFlags returns the flags from the last successful poll, starting a
+// poll if the interval has passed. Until a poll has succeeded, it waits for
+// the poll in flight and returns its error.
+func (a *AppConfig) currentFlags(ctx context.Context) (map[string]bool, error) {
+	a.mu.Lock()
+	if a.polling == nil && !a.now().Before(a.nextPoll) {
+		a.polling = make(chan struct{})
+		// The poll outlives the evaluation that started it
+		go a.poll(context.WithoutCancel(ctx), a.polling)
+	}
+	flags, done := a.flags, a.polling
+	a.mu.Unlock()
+
+	if flags != nil {
+		return flags, nil
+	}
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.flags == nil {
+		return nil, a.pollErr
+	}
+	return a.flags, nil
+}
+
+// poll fetches the latest configuration, bounded by pollTimeout, and
+// closes done when the result is stored. If it fails, the flags from the
+// last successful poll stay in use.
+func (a *AppConfig) poll(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	fetchCtx, cancel := context.WithTimeout(ctx, pollTimeout)
+	flags, interval, err := a.fetch(fetchCtx)
+	cancel()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.polling = nil
+	a.pollErr = err
+	// Poll again after the interval even if this poll fails
+	a.nextPoll = a.now().Add(max(interval, a.pollInterval))
+	if err != nil {
+		if a.flags != nil {
+			clog.FromContext(ctx).Warnf("[featureflag] keeping previous flags: %v", err)
+		}
+		return
+	}
+	if flags != nil {
+		a.flags = flags
+	} else if a.flags == nil {
+		a.flags = map[string]bool{}
+	}
+}
+
+// fetch gets the latest configuration, starting a session first if there
+// is none, and returns the flags and the interval AppConfig asks for. The
+// flags are nil if the configuration has not changed since the last poll.
+// Only one poll runs at a time, so token is owned by the running poll.
+func (a *AppConfig) fetch(ctx context.Context) (map[string]bool, time.Duration, error) {
+	if a.token == "" {
+		out, err := a.client.StartConfigurationSession(ctx, &appconfigdata.StartConfigurationSessionInput{
+			ApplicationIdentifier:                aws.String(a.Application),
+			EnvironmentIdentifier:                aws.String(a.Environment),
+			ConfigurationProfileIdentifier:       aws.String(a.Profile),
+			RequiredMinimumPollIntervalInSeconds: aws.Int32(int32(a.pollInterval / time.Second)),
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("featureflag: failed to start AppConfig session: %w", err)
+		}
+		a.token = aws.ToString(out.InitialConfigurationToken)
+	}
+
+	out, err := a.client.GetLatestConfiguration(ctx, &appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: aws.String(a.token),
+	})
+	if err != nil {
+		// Tokens expire after 24 hours without a poll; start a new session
+		// next time
+		a.token = ""
+		return nil, 0, fmt.Errorf("featureflag: failed to get AppConfig configuration: %w", err)
+	}
+	a.token = aws.ToString(out.NextPollConfigurationToken)
+	interval := time.Duration(out.NextPollIntervalInSeconds) * time.Second
+
+	// An empty configuration means it has not changed since the last poll
+	if len(out.Configuration) == 0 {
+		return nil, interval, nil
+	}
+	flags, err := parseFlags(out.Configuration)
+	if err != nil {
+		return nil, interval, err
+	}
+	return flags, interval, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+)
+
+// mockAppConfigDataClient serves configs in order, one per poll; an empty
+// config means unchanged.
+type mockAppConfigDataClient struct {
+	configs  []string
+	err      error
+	block    chan struct{} // if set, polls wait for it to be closed
+	sessions int
+	polls    int
+	tokens   []string
+}
+
+func (m *mockAppConfigDataClient) StartConfigurationSession(ctx context.Context, params *appconfigdata.StartConfigurationSessionInput, optFns ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error) {
+	m.sessions++
+	return &appconfigdata.StartConfigurationSessionOutput{InitialConfigurationToken: aws.String("initial")}, nil
+}
+
+func (m *mockAppConfigDataClient) GetLatestConfiguration(ctx context.Context, params *appconfigdata.GetLatestConfigurationInput, optFns ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error) {
+	m.tokens = append(m.tokens, aws.ToString(params.ConfigurationToken))
+	if m.block != nil {
+		<-m.block
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	var config string
+	if m.polls < len(m.configs) {
+		config = m.configs[m.polls]
+	}
+	m.polls++
+	return &appconfigdata.GetLatestConfigurationOutput{
+		Configuration:              []byte(config),
+		NextPollConfigurationToken: aws.String("next"),
+	}, nil
+}
+
+func newTestAppConfig(t *testing.T, client *mockAppConfigDataClient) (*AppConfig, *time.Time) {
+	t.Helper()
+	a, err := NewAppConfig("app", "prod", "flags", WithAppConfigDataClient(client), WithPollInterval(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+// waitPoll waits for the background poll in flight, if any.
+func waitPoll(a *AppConfig) {
+	a.mu.Lock()
+	done := a.polling
+	a.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func TestAppConfig_Enabled(t *testing.T) {
+	ctx := context.Background()
+	client := &mockAppConfigDataClient{configs: []string{
+		`{"installer_enabled":{"enabled":true}}`,
+		``,
+		`{"installer_enabled":{"enabled":false}}`,
+	}}
+	a, now := newTestAppConfig(t, client)
+
+	if on, err := a.Enabled(ctx, "installer_enabled"); err != nil || !on {
+		t.Fatalf("Enabled() = %v, %v; want true", on, err)
+	}
+	if _, err := a.Enabled(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Enabled(missing) error = %v, want ErrNotFound", err)
+	}
+	if client.polls != 1 {
+		t.Errorf("polls = %d within the interval, want 1", client.polls)
+	}
+
+	// Unchanged configuration keeps the flags
+	*now = now.Add(time.Minute)
+	a.Enabled(ctx, "installer_enabled")
+	waitPoll(a)
+	if on, _ := a.Enabled(ctx, "installer_enabled"); !on {
+		t.Error("Enabled() = false after an unchanged poll, want true")
+	}
+
+	*now = now.Add(time.Minute)
+	a.Enabled(ctx, "installer_enabled")
+	waitPoll(a)
+	if on, _ := a.Enabled(ctx, "installer_enabled"); on {
+		t.Error("Enabled() = true after the flag was turned off, want false")
+	}
+	if client.sessions != 1 || client.tokens[1] != "next" {
+		t.Errorf("sessions = %d, tokens = %v; want one session reusing the next token", client.sessions, client.tokens)
+	}
+}
+
+func TestAppConfig_PollFailure(t *testing.T) {
+	ctx := context.Background()
+	client := &mockAppConfigDataClient{err: errors.New("throttled")}
+	a, now := newTestAppConfig(t, client)
+
+	// Nothing fetched yet, so the error is returned
+	if _, err := a.Enabled(ctx, "installer_enabled"); err == nil {
+		t.Fatal("Enabled() with a failing first poll should return error")
+	}
+
+	client.err = nil
+	client.configs = []string{`{"installer_enabled":true}`}
+	if _, err := a.Enabled(ctx, "installer_enabled"); err == nil {
+		t.Fatal("Enabled() within the interval after a failed first poll should return error")
+	}
+
+	*now = now.Add(time.Minute)
+	if on, err := a.Enabled(ctx, "installer_enabled"); err != nil || !on {
+		t.Fatalf("Enabled() = %v, %v; want true", on, err)
+	}
+	if client.sessions != 2 {
+		t.Errorf("sessions = %d, want a new session after the failed poll", client.sessions)
+	}
+
+	// Later failures keep the previous flags
+	client.err = errors.New("throttled")
+	*now = now.Add(time.Minute)
+	a.Enabled(ctx, "installer_enabled")
+	waitPoll(a)
+	if on, err := a.Enabled(ctx, "installer_enabled"); err != nil || !on {
+		t.Errorf("Enabled() = %v, %v; want the previous flags", on, err)
+	}
+}
+
+func TestAppConfig_PollsInBackground(t *testing.T) {
+	client := &mockAppConfigDataClient{configs: []string{`{"installer_enabled":true}`, `{"installer_enabled":false}`}}
+	a, now := newTestAppConfig(t, client)
+	if on, err := a.Enabled(context.Background(), "installer_enabled"); err != nil || !on {
+		t.Fatalf("Enabled() = %v, %v; want true", on, err)
+	}
+
+	// A slow poll does not hold up evaluations, even after the evaluation
+	// that started it is canceled
+	client.block = make(chan struct{})
+	*now = now.Add(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	if on, err := a.Enabled(ctx, "installer_enabled"); err != nil || !on {
+		t.Fatalf("Enabled() during a poll = %v, %v; want the cached flags", on, err)
+	}
+	cancel()
+	if on, err := a.Enabled(context.Background(), "installer_enabled"); err != nil || !on {
+		t.Fatalf("Enabled() during a poll = %v, %v; want the cached flags", on, err)
+	}
+
+	close(client.block)
+	waitPoll(a)
+	if on, _ := a.Enabled(context.Background(), "installer_enabled"); on {
+		t.Error("Enabled() = true after the background poll, want false")
+	}
+	if client.polls != 2 {
+		t.Errorf("polls = %d, want 2", client.polls)
+	}
+}
+
+func TestAppConfig_FirstPollHonorsContext(t *testing.T) {
+	client := &mockAppConfigDataClient{block: make(chan struct{})}
+	a, _ := newTestAppConfig(t, client)
+	defer func() {
+		close(client.block)
+		waitPoll(a)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := a.Enabled(ctx, "installer_enabled"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Enabled() error = %v, want the caller's deadline", err)
+	}
+}
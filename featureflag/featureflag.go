@@ -0,0 +1,79 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package featureflag evaluates boolean feature flags from a provider such
+// as AWS AppConfig. The installer uses one (see installer.Config.Flags) to
+// decide at request time whether it is enabled, so it can be switched off
+// fleet-wide without redeploying or editing every store.
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvAppConfigProfile names the AppConfig configuration profile NewFromEnv
+// reads flags from, as "application/environment/profile".
+const EnvAppConfigProfile = "AWS_APPCONFIG_PROFILE"
+
+// ErrNotFound is returned by providers for flags they do not define.
+var ErrNotFound = errors.New("featureflag: flag not found")
+
+// Provider evaluates feature flags. Implementations must be safe for
+// concurrent use and fast enough to call on every request, e.g. by caching.
+type Provider interface {
+	Enabled(ctx context.Context, key string) (bool, error)
+}
+
+// ProviderFunc adapts a function to a Provider, e.g. to wrap a
+// third-party feature flag SDK.
+type ProviderFunc func(ctx context.Context, key string) (bool, error)
+
+// Enabled calls f.
+func (f ProviderFunc) Enabled(ctx context.Context, key string) (bool, error) {
+	return f(ctx, key)
+}
+
+// NewFromEnv creates a Provider based on environment variable
+// configuration. If AWS_APPCONFIG_PROFILE is set, it returns an AppConfig
+// provider for that profile; otherwise it returns nil.
+func NewFromEnv() (Provider, error) {
+	profile := strings.TrimSpace(os.Getenv(EnvAppConfigProfile))
+	if profile == "" {
+		return nil, nil
+	}
+	parts := strings.Split(profile, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid %s %q (expected 'application/environment/profile')", EnvAppConfigProfile, profile)
+	}
+	return NewAppConfig(parts[0], parts[1], parts[2])
+}
+
+// parseFlags decodes a JSON object of flags. Values are either booleans or
+// objects with an "enabled" boolean, as returned for AppConfig feature
+// flag profiles; other values are ignored.
+func parseFlags(data []byte) (map[string]bool, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("featureflag: failed to parse flags: %w", err)
+	}
+	flags := make(map[string]bool, len(raw))
+	for key, value := range raw {
+		var enabled bool
+		if err := json.Unmarshal(value, &enabled); err == nil {
+			flags[key] = enabled
+			continue
+		}
+		var flag struct {
+			Enabled *bool `json:"enabled"`
+		}
+		if err := json.Unmarshal(value, &flag); err == nil && flag.Enabled != nil {
+			flags[key] = *flag.Enabled
+		}
+	}
+	return flags, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package featureflag
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "feature flag profile",
+			data: `{"installer_enabled":{"enabled":true},"beta":{"enabled":false,"rollout":"10%"}}`,
+			want: map[string]bool{"installer_enabled": true, "beta": false},
+		},
+		{
+			name: "freeform booleans",
+			data: `{"installer_enabled":false,"name":"ignored","other":{"value":1}}`,
+			want: map[string]bool{"installer_enabled": false},
+		},
+		{
+			name:    "not an object",
+			data:    `[true]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlags([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("parseFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderFunc(t *testing.T) {
+	var p Provider = ProviderFunc(func(ctx context.Context, key string) (bool, error) {
+		return key == "on", nil
+	})
+	if on, _ := p.Enabled(context.Background(), "on"); !on {
+		t.Error("Enabled(on) = false, want true")
+	}
+	if off, _ := p.Enabled(context.Background(), "off"); off {
+		t.Error("Enabled(off) = true, want false")
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("unset returns nil", func(t *testing.T) {
+		t.Setenv(EnvAppConfigProfile, "")
+		p, err := NewFromEnv()
+		if err != nil || p != nil {
+			t.Errorf("NewFromEnv() = %v, %v; want nil, nil", p, err)
+		}
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		for _, v := range []string{"app/env", "app//profile", "a/b/c/d"} {
+			t.Setenv(EnvAppConfigProfile, v)
+			if _, err := NewFromEnv(); err == nil {
+				t.Errorf("NewFromEnv() with %q should return error", v)
+			}
+		}
+	})
+
+	t.Run("valid profile", func(t *testing.T) {
+		t.Setenv(EnvAppConfigProfile, "my-app/prod/flags")
+		t.Setenv("AWS_REGION", "us-east-1")
+		p, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("NewFromEnv() error = %v", err)
+		}
+		a, ok := p.(*AppConfig)
+		if !ok || a.Application != "my-app" || a.Environment != "prod" || a.Profile != "flags" {
+			t.Errorf("NewFromEnv() = %#v, want AppConfig for my-app/prod/flags", p)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// throttledReloadTimeout bounds reloads run inside a request on platforms
+// that throttle CPU between requests, well within the default Cloud Run
+// request timeout.
+const throttledReloadTimeout = 30 * time.Second
+
+// CPUThrottled reports whether the runtime assumes CPU is throttled
+// outside of request processing: on Cloud Run services and Cloud Functions
+// unless Config.CPUAlwaysAllocated is set. Goroutines keep running there
+// but get next to no CPU between requests, so the Runtime reloads inside
+// the triggering request and drives RefreshInterval refreshes from
+// requests. Applications should do the same with their own background
+// pollers, e.g. by running them as Cloud Scheduler requests instead.
+func (r *Runtime) CPUThrottled() bool {
+	return r.platform.cpuThrottled() && !r.config.CPUAlwaysAllocated
+}
+
+// reloadInRequest reloads synchronously for ReloadCallback when CPU is
+// throttled. Failures are logged by load and leave the previous
+// configuration in place.
+func (r *Runtime) reloadInRequest() {
+	ctx, cancel := context.WithTimeout(context.Background(), throttledReloadTimeout)
+	defer cancel()
+	if err := r.Reload(ctx); err != nil {
+		clog.FromContext(ctx).Warnf("[ghappsetup] reload failed: %v", err)
+	}
+}
+
+// refreshOnRequest runs a RefreshInterval refresh alongside a request once
+// the interval has elapsed, and holds the response until the refresh
+// finishes so that it runs while the request keeps CPU allocated. Other
+// requests are not delayed.
+func (r *Runtime) refreshOnRequest(next http.Handler) http.Handler {
+	state := r.getLambdaState()
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		state.mu.Lock()
+		refresh := r.IsReady() && r.shouldRefresh(state)
+		state.mu.Unlock()
+		if !refresh {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.refresh(context.WithoutCancel(req.Context()), state)
+		}()
+		next.ServeHTTP(w, req)
+		<-done
+	})
+}
+
+// markRefreshed records a successful load as the last refresh, so the
+// first request-driven refresh happens RefreshInterval after startup.
+func (r *Runtime) markRefreshed() {
+	state := r.getLambdaState()
+	state.mu.Lock()
+	state.lastRefresh = time.Now()
+	state.mu.Unlock()
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newCloudRunRuntime creates a Runtime detected as a Cloud Run service
+// whose LoadFunc counts its calls.
+func newCloudRunRuntime(t *testing.T, cfg Config) (*Runtime, *atomic.Int32) {
+	t.Helper()
+	clearPlatformEnv(t)
+	t.Setenv(EnvCPUAlwaysAllocated, "")
+	t.Setenv(envCloudRunService, "svc")
+
+	var loads atomic.Int32
+	cfg.Store = &mockStore{}
+	cfg.LoadFunc = func(ctx context.Context) error {
+		loads.Add(1)
+		return nil
+	}
+	runtime, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	t.Cleanup(runtime.ResetLoadState)
+	return runtime, &loads
+}
+
+func TestRuntime_CPUThrottled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		cfg  Config
+		want bool
+	}{
+		{
+			name: "cloud run service",
+			env:  map[string]string{envCloudRunService: "svc"},
+			want: true,
+		},
+		{
+			name: "cloud run job",
+			env:  map[string]string{envCloudRunJob: "job"},
+			want: false,
+		},
+		{
+			name: "cloud functions",
+			env:  map[string]string{envCloudFunctionTarget: "Handle", envCloudRunService: "fn"},
+			want: true,
+		},
+		{
+			name: "cpu always allocated",
+			env:  map[string]string{envCloudRunService: "svc"},
+			cfg:  Config{CPUAlwaysAllocated: true},
+			want: false,
+		},
+		{
+			name: "cpu always allocated from env",
+			env:  map[string]string{envCloudRunService: "svc", EnvCPUAlwaysAllocated: "true"},
+			want: false,
+		},
+		{
+			name: "ecs",
+			env:  map[string]string{envAWSExecutionEnv: "AWS_ECS_FARGATE"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearPlatformEnv(t)
+			t.Setenv(EnvCPUAlwaysAllocated, "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg := tt.cfg
+			cfg.Store = &mockStore{}
+			cfg.LoadFunc = func(ctx context.Context) error { return nil }
+			runtime, err := NewRuntime(cfg)
+			if err != nil {
+				t.Fatalf("NewRuntime() error = %v", err)
+			}
+			if got := runtime.CPUThrottled(); got != tt.want {
+				t.Errorf("CPUThrottled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatform_ShutdownSignals(t *testing.T) {
+	for _, p := range []Platform{PlatformECS, PlatformCloudRun, PlatformCloudFunctions} {
+		if !slices.Contains(p.shutdownSignals(), os.Signal(syscall.SIGTERM)) {
+			t.Errorf("%v.shutdownSignals() = %v, want SIGTERM", p, p.shutdownSignals())
+		}
+	}
+	for _, p := range []Platform{PlatformGeneric, PlatformLambda, PlatformKubernetes} {
+		if sigs := p.shutdownSignals(); len(sigs) != 0 {
+			t.Errorf("%v.shutdownSignals() = %v, want none", p, sigs)
+		}
+	}
+}
+
+func TestRuntime_ReloadCallback_CPUThrottled(t *testing.T) {
+	runtime, loads := newCloudRunRuntime(t, Config{ReloadCooldown: time.Hour})
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// No listener runs: the callback reloads before returning
+	callback := runtime.ReloadCallback()
+	callback()
+	callback()
+
+	if got := loads.Load(); got != 3 {
+		t.Errorf("LoadFunc called %d times, want 3", got)
+	}
+}
+
+func TestRuntime_Handler_RefreshOnRequest(t *testing.T) {
+	runtime, loads := newCloudRunRuntime(t, Config{RefreshInterval: 50 * time.Millisecond})
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+
+	serve()
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("LoadFunc called %d times before the interval, want 1", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	serve()
+	// The refresh completes before the response is returned
+	if got := loads.Load(); got != 2 {
+		t.Errorf("LoadFunc called %d times after the interval, want 2", got)
+	}
+
+	serve()
+	if got := loads.Load(); got != 2 {
+		t.Errorf("LoadFunc called %d times within the next interval, want 2", got)
+	}
+}
+
+func TestRuntime_Handler_NoRefreshWithCPU(t *testing.T) {
+	runtime, loads := newCloudRunRuntime(t, Config{
+		RefreshInterval:    time.Nanosecond,
+		CPUAlwaysAllocated: true,
+	})
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("LoadFunc called %d times, want 1", got)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+)
+
+// runtimeKey is the context key under which a Runtime is stored.
+type runtimeKey struct{}
+
+// NewContext returns a copy of ctx that carries the given Runtime.
+func NewContext(ctx context.Context, r *Runtime) context.Context {
+	return context.WithValue(ctx, runtimeKey{}, r)
+}
+
+// FromContext returns the Runtime stored in ctx by NewContext or
+// Middleware, or nil if ctx does not carry one. This lets handler code and
+// libraries access readiness, the store, and the reload trigger without
+// global variables.
+func FromContext(ctx context.Context) *Runtime {
+	r, _ := ctx.Value(runtimeKey{}).(*Runtime)
+	return r
+}
+
+// Middleware returns a handler that stores the Runtime in each request's
+// context before calling next. Handler applies it automatically; use it
+// directly when serving routes outside of Handler.
+func (r *Runtime) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(w, req.WithContext(NewContext(req.Context(), r)))
+	})
+}
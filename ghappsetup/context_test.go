@@ -0,0 +1,54 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestNewContext_FromContext(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("FromContext() = %v, want nil for empty context", got)
+	}
+
+	ctx := NewContext(context.Background(), runtime)
+	if got := FromContext(ctx); got != runtime {
+		t.Errorf("FromContext() = %v, want %v", got, runtime)
+	}
+}
+
+func TestRuntime_Handler_InjectsRuntime(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		LoadFunc:     func(ctx context.Context) error { return nil },
+		AllowedPaths: []string{"/healthz"},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	var got *Runtime
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if got != runtime {
+		t.Errorf("FromContext() in handler = %v, want runtime", got)
+	}
+}
@@ -20,7 +20,7 @@
 //
 //	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
 //	    LoadFunc:     loadConfig,
-//	    AllowedPaths: []string{"/healthz", "/setup", "/callback", "/"},
+//	    AllowedPaths: []string{"/healthz"},
 //	})
 //	if err != nil {
 //	    log.Fatal(err)
@@ -37,13 +37,14 @@
 //	    OnReloadNeeded: runtime.ReloadCallback(),
 //	})
 //
-//	// Option B: Convenience method (recommended)
-//	installerHandler, _ := runtime.InstallerHandler(installer.Config{
+//	mux.Handle("/setup", installerHandler)
+//
+//	// Option B: Convenience method (recommended); also adds the installer
+//	// routes to AllowedPaths
+//	runtime.MountInstaller(mux, installer.Config{
 //	    Manifest: manifest,
 //	})
 //
-//	mux.Handle("/setup", installerHandler)
-//
 //	srv := &http.Server{Handler: runtime.Handler(mux)}
 //	go srv.ListenAndServe()
 //
@@ -77,14 +78,28 @@
 //	    return handleRequest(ctx, req)
 //	}
 //
-// # Environment Detection
+// Functions using provisioned concurrency can set Config.PreloadOnInit so
+// that NewRuntime loads configuration during the init phase. This happens
+// automatically when AWS_LAMBDA_INITIALIZATION_TYPE is
+// "provisioned-concurrency".
 //
-// The Runtime automatically detects whether it's running in an HTTP server
-// or Lambda environment by checking for the AWS_LAMBDA_FUNCTION_NAME
-// environment variable. This affects default retry settings:
+// # Environment Detection
 //
-//   - HTTP: 30 retries with 2-second intervals (suitable for startup)
-//   - Lambda: 5 retries with 1-second intervals (suitable for cold starts)
+// The Runtime automatically detects the hosting platform from well-known
+// environment variables: AWS Lambda (AWS_LAMBDA_FUNCTION_NAME), ECS and
+// Fargate (ECS_CONTAINER_METADATA_URI_V4), Google Cloud Functions
+// (FUNCTION_TARGET), Google Cloud Run (K_SERVICE), Azure Functions
+// (FUNCTIONS_WORKER_RUNTIME), and Kubernetes (KUBERNETES_SERVICE_HOST).
+// Lambda uses the lazy EnsureLoaded lifecycle; every other platform is
+// treated as an HTTP server. The platform selects default retry settings:
+//
+//   - Lambda, Cloud Functions: 5 retries with 1-second intervals
+//     (suitable for cold starts)
+//   - Azure Functions: 10 retries with 2-second intervals
+//   - Cloud Run: 15 retries with 2-second intervals
+//   - ECS: 60 retries with 2-second intervals
+//   - Kubernetes and others: 30 retries with 2-second intervals
+//     (suitable for startup)
 //
 // # Installer Integration
 //
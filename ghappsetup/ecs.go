@@ -0,0 +1,101 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ecsMetadataTimeout bounds requests to the ECS task metadata endpoint,
+// which is local to the task and answers quickly when available.
+const ecsMetadataTimeout = 2 * time.Second
+
+// ECSTask describes the ECS task the Runtime runs in, as reported by the
+// task metadata endpoint (version 4).
+type ECSTask struct {
+	Cluster          string `json:"cluster"`
+	TaskARN          string `json:"task_arn"`
+	Family           string `json:"family"`
+	Revision         string `json:"revision"`
+	ServiceName      string `json:"service_name,omitempty"`
+	LaunchType       string `json:"launch_type,omitempty"`
+	AvailabilityZone string `json:"availability_zone,omitempty"`
+}
+
+// ECSTask returns the ECS task the Runtime runs in, read from the task
+// metadata endpoint named by ECS_CONTAINER_METADATA_URI_V4. Task metadata
+// does not change while the task runs, so it is fetched once and cached.
+// It returns an error off ECS or when the endpoint is unavailable.
+func (r *Runtime) ECSTask(ctx context.Context) (*ECSTask, error) {
+	r.ecsMu.Lock()
+	defer r.ecsMu.Unlock()
+	if r.ecsTask != nil {
+		return r.ecsTask, nil
+	}
+
+	base := os.Getenv(envECSContainerMetadataURIV4)
+	if r.platform != PlatformECS || base == "" {
+		return nil, errors.New("ghappsetup: ECS task metadata endpoint not available")
+	}
+	task, err := fetchECSTask(ctx, strings.TrimRight(base, "/")+"/task")
+	if err != nil {
+		return nil, err
+	}
+	r.ecsTask = task
+	return task, nil
+}
+
+// fetchECSTask reads the task metadata response at url.
+func fetchECSTask(ctx context.Context, url string) (*ECSTask, error) {
+	ctx, cancel := context.WithTimeout(ctx, ecsMetadataTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ghappsetup: failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ghappsetup: failed to read ECS task metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ghappsetup: failed to read ECS task metadata: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ghappsetup: ECS task metadata endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var meta struct {
+		Cluster          string `json:"Cluster"`
+		TaskARN          string `json:"TaskARN"`
+		Family           string `json:"Family"`
+		Revision         string `json:"Revision"`
+		ServiceName      string `json:"ServiceName"`
+		LaunchType       string `json:"LaunchType"`
+		AvailabilityZone string `json:"AvailabilityZone"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, fmt.Errorf("ghappsetup: failed to parse ECS task metadata: %w", err)
+	}
+	return &ECSTask{
+		Cluster:          meta.Cluster,
+		TaskARN:          meta.TaskARN,
+		Family:           meta.Family,
+		Revision:         meta.Revision,
+		ServiceName:      meta.ServiceName,
+		LaunchType:       meta.LaunchType,
+		AvailabilityZone: meta.AvailabilityZone,
+	}, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// ecsMetadataServer serves a task metadata response and counts requests.
+func ecsMetadataServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v4/abc/task" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"Cluster": "arn:aws:ecs:us-east-1:111111111111:cluster/prod",
+			"TaskARN": "arn:aws:ecs:us-east-1:111111111111:task/prod/0123",
+			"Family": "github-app",
+			"Revision": "7",
+			"ServiceName": "github-app",
+			"LaunchType": "FARGATE",
+			"AvailabilityZone": "us-east-1a",
+			"Containers": []
+		}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newECSRuntime(t *testing.T, metadataURL string) *Runtime {
+	t.Helper()
+	clearPlatformEnv(t)
+	t.Setenv(envECSContainerMetadataURIV4, metadataURL)
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	return runtime
+}
+
+func TestRuntime_ECSTask(t *testing.T) {
+	srv, requests := ecsMetadataServer(t)
+	runtime := newECSRuntime(t, srv.URL+"/v4/abc")
+
+	want := ECSTask{
+		Cluster:          "arn:aws:ecs:us-east-1:111111111111:cluster/prod",
+		TaskARN:          "arn:aws:ecs:us-east-1:111111111111:task/prod/0123",
+		Family:           "github-app",
+		Revision:         "7",
+		ServiceName:      "github-app",
+		LaunchType:       "FARGATE",
+		AvailabilityZone: "us-east-1a",
+	}
+	for range 2 {
+		task, err := runtime.ECSTask(context.Background())
+		if err != nil {
+			t.Fatalf("ECSTask() error = %v", err)
+		}
+		if *task != want {
+			t.Errorf("ECSTask() = %+v, want %+v", *task, want)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("metadata requests = %d, want 1", n)
+	}
+}
+
+func TestRuntime_ECSTask_Unavailable(t *testing.T) {
+	clearPlatformEnv(t)
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runtime.ECSTask(context.Background()); err == nil {
+		t.Error("ECSTask() off ECS should return error")
+	}
+
+	srv, _ := ecsMetadataServer(t)
+	runtime = newECSRuntime(t, srv.URL+"/v4/missing")
+	if _, err := runtime.ECSTask(context.Background()); err == nil {
+		t.Error("ECSTask() with a failing endpoint should return error")
+	}
+}
+
+func TestRuntime_InfoHandler_ECS(t *testing.T) {
+	srv, _ := ecsMetadataServer(t)
+	runtime := newECSRuntime(t, srv.URL+"/v4/abc")
+
+	rec := httptest.NewRecorder()
+	runtime.InfoHandler()(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+
+	var info Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if info.Platform != "ecs" || info.ECS == nil || info.ECS.Family != "github-app" || info.ECS.Revision != "7" {
+		t.Errorf("Info = %+v, ECS = %+v", info, info.ECS)
+	}
+}
+
+func TestNewRuntime_ECSDrainPeriod(t *testing.T) {
+	runtime := newECSRuntime(t, "http://169.254.170.2/v4/abc")
+	if runtime.config.DrainPeriod != defaultECSDrainPeriod {
+		t.Errorf("DrainPeriod = %v, want %v", runtime.config.DrainPeriod, defaultECSDrainPeriod)
+	}
+}
+
+func TestRuntime_RunHTTP_ECSSigterm(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(envECSContainerMetadataURIV4, "http://169.254.170.2/v4/abc")
+	runtime, err := NewRuntime(Config{
+		Store:       &mockStore{},
+		LoadFunc:    func(ctx context.Context) error { return nil },
+		DrainPeriod: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- runtime.RunHTTP(context.Background(), "", http.NewServeMux(), WithListener(listener))
+	}()
+	// RunHTTP subscribes to SIGTERM before loading configuration
+	if err := runtime.WaitReady(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		t.Skipf("cannot send SIGTERM: %v", err)
+	}
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("RunHTTP() error = %v, want nil after SIGTERM", err)
+		}
+		if !runtime.IsShuttingDown() {
+			t.Error("RunHTTP() returned without draining")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunHTTP() did not return after SIGTERM")
+	}
+}
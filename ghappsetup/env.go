@@ -0,0 +1,172 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+// Environment variables read by NewRuntime when the corresponding Config
+// field is zero.
+const (
+	EnvMaxRetries         = "GHAPPSETUP_MAX_RETRIES"
+	EnvRetryInterval      = "GHAPPSETUP_RETRY_INTERVAL"
+	EnvMaxWait            = "GHAPPSETUP_MAX_WAIT"
+	EnvBackoffMultiplier  = "GHAPPSETUP_BACKOFF_MULTIPLIER"
+	EnvMaxInterval        = "GHAPPSETUP_MAX_INTERVAL"
+	EnvJitter             = "GHAPPSETUP_JITTER"
+	EnvAllowedPaths       = "GHAPPSETUP_ALLOWED_PATHS"
+	EnvGatedPaths         = "GHAPPSETUP_GATED_PATHS"
+	EnvRefreshInterval    = "GHAPPSETUP_REFRESH_INTERVAL"
+	EnvPreloadOnInit      = "GHAPPSETUP_PRELOAD_ON_INIT"
+	EnvReloadCooldown     = "GHAPPSETUP_RELOAD_COOLDOWN"
+	EnvRecoverPanics      = "GHAPPSETUP_RECOVER_PANICS"
+	EnvDrainPeriod        = "GHAPPSETUP_DRAIN_PERIOD"
+	EnvEMFNamespace       = "GHAPPSETUP_EMF_NAMESPACE"
+	EnvEventBus           = "GHAPPSETUP_EVENT_BUS"
+	EnvCPUAlwaysAllocated = "GHAPPSETUP_CPU_ALWAYS_ALLOCATED"
+	EnvReloadToken        = "GHAPPSETUP_RELOAD_TOKEN"
+	EnvReloadNoAuth       = "GHAPPSETUP_RELOAD_UNAUTHENTICATED"
+	EnvSettingsFile       = "GHAPPSETUP_SETTINGS_FILE"
+)
+
+// applyEnvDefaults fills zero-valued Config fields from environment
+// variables. The GHAPPSETUP_* variables take precedence over the configwait
+// CONFIG_WAIT_* variables. Invalid values are ignored so the platform
+// defaults apply instead.
+func applyEnvDefaults(cfg *Config) {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = envPositiveInt(EnvMaxRetries, configwait.EnvMaxRetries)
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = envPositiveDuration(EnvRetryInterval, configwait.EnvRetryInterval)
+	}
+	if cfg.MaxWait == 0 {
+		cfg.MaxWait = envPositiveDuration(EnvMaxWait, configwait.EnvMaxWait)
+	}
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = envPositiveFloat(EnvBackoffMultiplier, configwait.EnvBackoffMultiplier)
+	}
+	if cfg.MaxInterval == 0 {
+		cfg.MaxInterval = envPositiveDuration(EnvMaxInterval, configwait.EnvMaxInterval)
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = envPositiveFloat(EnvJitter, configwait.EnvJitter)
+	}
+	if cfg.RefreshInterval == 0 {
+		cfg.RefreshInterval = envPositiveDuration(EnvRefreshInterval)
+	}
+	if cfg.ReloadCooldown == 0 {
+		cfg.ReloadCooldown = envPositiveDuration(EnvReloadCooldown)
+	}
+	if cfg.DrainPeriod == 0 {
+		cfg.DrainPeriod = envPositiveDuration(EnvDrainPeriod)
+	}
+	if len(cfg.AllowedPaths) == 0 {
+		cfg.AllowedPaths = splitPathList(os.Getenv(EnvAllowedPaths))
+	}
+	if len(cfg.GatedPaths) == 0 {
+		cfg.GatedPaths = splitPathList(os.Getenv(EnvGatedPaths))
+	}
+	if !cfg.PreloadOnInit {
+		cfg.PreloadOnInit = envBool(EnvPreloadOnInit)
+	}
+	if !cfg.RecoverPanics {
+		cfg.RecoverPanics = envBool(EnvRecoverPanics)
+	}
+	if !cfg.CPUAlwaysAllocated {
+		cfg.CPUAlwaysAllocated = envBool(EnvCPUAlwaysAllocated)
+	}
+	if cfg.ReloadToken == "" {
+		cfg.ReloadToken = strings.TrimSpace(os.Getenv(EnvReloadToken))
+	}
+	if !cfg.AllowUnauthenticatedReload {
+		cfg.AllowUnauthenticatedReload = envBool(EnvReloadNoAuth)
+	}
+	if cfg.SettingsFile == "" {
+		cfg.SettingsFile = strings.TrimSpace(os.Getenv(EnvSettingsFile))
+	}
+	if cfg.Metrics == nil {
+		if ns := strings.TrimSpace(os.Getenv(EnvEMFNamespace)); ns != "" {
+			cfg.Metrics = metrics.NewEMF(ns)
+		}
+	}
+}
+
+// envBool reports whether key is set to "true", "1", or "yes".
+func envBool(key string) bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// envPositiveInt returns the first positive integer found in keys, or zero.
+func envPositiveInt(keys ...string) int {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// envPositiveFloat returns the first positive number found in keys, or zero.
+func envPositiveFloat(keys ...string) float64 {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+				return f
+			}
+		}
+	}
+	return 0
+}
+
+// envPositiveDuration returns the first positive duration found in keys, or zero.
+func envPositiveDuration(keys ...string) time.Duration {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// splitPathList splits a comma-separated list of path entries. Because
+// method-restricted entries may themselves contain commas (e.g.
+// "GET,POST /setup"), a token without a "/" is treated as a method and
+// joined with the token that follows it.
+func splitPathList(v string) []string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+
+	var entries []string
+	var methods []string
+	for _, token := range strings.Split(v, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if !strings.Contains(token, "/") {
+			methods = append(methods, token)
+			continue
+		}
+		if len(methods) > 0 {
+			token = strings.Join(methods, ",") + "," + token
+			methods = nil
+		}
+		entries = append(entries, token)
+	}
+	return entries
+}
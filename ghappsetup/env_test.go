@@ -0,0 +1,187 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+func TestNewRuntime_EnvOverrides(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvMaxRetries, "7")
+	t.Setenv(EnvRetryInterval, "250ms")
+	t.Setenv(EnvAllowedPaths, "/healthz, GET,HEAD /setup ,/callback")
+	t.Setenv(EnvGatedPaths, "/webhook")
+	t.Setenv(EnvRefreshInterval, "5m")
+	t.Setenv(EnvReloadCooldown, "30s")
+	t.Setenv(EnvMaxWait, "2m")
+	t.Setenv(EnvBackoffMultiplier, "2")
+	t.Setenv(EnvMaxInterval, "10s")
+	t.Setenv(configwait.EnvJitter, "0.2")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if runtime.config.MaxRetries != 7 {
+		t.Errorf("MaxRetries = %d, want 7", runtime.config.MaxRetries)
+	}
+	if runtime.config.RetryInterval != 250*time.Millisecond {
+		t.Errorf("RetryInterval = %v, want 250ms", runtime.config.RetryInterval)
+	}
+	if runtime.config.MaxWait != 2*time.Minute {
+		t.Errorf("MaxWait = %v, want 2m", runtime.config.MaxWait)
+	}
+	if runtime.config.Multiplier != 2 || runtime.config.MaxInterval != 10*time.Second || runtime.config.Jitter != 0.2 {
+		t.Errorf("backoff = %v, %v, %v, want 2, 10s, 0.2", runtime.config.Multiplier, runtime.config.MaxInterval, runtime.config.Jitter)
+	}
+	wait := runtime.waitConfig()
+	if wait.Multiplier != 2 || wait.MaxInterval != 10*time.Second || wait.Jitter != 0.2 {
+		t.Errorf("waitConfig() = %+v, want the backoff settings", wait)
+	}
+	if runtime.config.RefreshInterval != 5*time.Minute {
+		t.Errorf("RefreshInterval = %v, want 5m", runtime.config.RefreshInterval)
+	}
+	if runtime.config.ReloadCooldown != 30*time.Second {
+		t.Errorf("ReloadCooldown = %v, want 30s", runtime.config.ReloadCooldown)
+	}
+	wantAllowed := []string{"/healthz", "GET,HEAD /setup", "/callback"}
+	if !reflect.DeepEqual(runtime.config.AllowedPaths, wantAllowed) {
+		t.Errorf("AllowedPaths = %q, want %q", runtime.config.AllowedPaths, wantAllowed)
+	}
+	if !reflect.DeepEqual(runtime.config.GatedPaths, []string{"/webhook"}) {
+		t.Errorf("GatedPaths = %q, want %q", runtime.config.GatedPaths, []string{"/webhook"})
+	}
+}
+
+func TestNewRuntime_EMFNamespaceEnv(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvEMFNamespace, "GitHubApp")
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	if _, ok := runtime.Metrics().(*metrics.EMF); !ok {
+		t.Errorf("Metrics() = %T, want *metrics.EMF", runtime.Metrics())
+	}
+}
+
+func TestNewRuntime_EventBusEnv(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvEventBus, "platform-events")
+	t.Setenv("AWS_REGION", "us-east-1")
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	p, ok := runtime.Events().(*lifecycle.EventBridgePublisher)
+	if !ok || p.BusName != "platform-events" {
+		t.Errorf("Events() = %#v, want an EventBridge publisher for platform-events", runtime.Events())
+	}
+}
+
+func TestNewRuntime_ConfigTakesPrecedenceOverEnv(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvMaxRetries, "7")
+	t.Setenv(EnvAllowedPaths, "/from-env")
+
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		LoadFunc:     func(ctx context.Context) error { return nil },
+		MaxRetries:   3,
+		AllowedPaths: []string{"/from-config"},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if runtime.config.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want 3", runtime.config.MaxRetries)
+	}
+	if !reflect.DeepEqual(runtime.config.AllowedPaths, []string{"/from-config"}) {
+		t.Errorf("AllowedPaths = %q, want %q", runtime.config.AllowedPaths, []string{"/from-config"})
+	}
+}
+
+func TestNewRuntime_ConfigWaitEnvFallback(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvMaxRetries, "")
+	t.Setenv(EnvRetryInterval, "")
+	t.Setenv("CONFIG_WAIT_MAX_RETRIES", "4")
+	t.Setenv("CONFIG_WAIT_RETRY_INTERVAL", "3s")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if runtime.config.MaxRetries != 4 {
+		t.Errorf("MaxRetries = %d, want 4", runtime.config.MaxRetries)
+	}
+	if runtime.config.RetryInterval != 3*time.Second {
+		t.Errorf("RetryInterval = %v, want 3s", runtime.config.RetryInterval)
+	}
+}
+
+func TestNewRuntime_InvalidEnvIgnored(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(EnvMaxRetries, "not-a-number")
+	t.Setenv(EnvRetryInterval, "-1s")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if runtime.config.MaxRetries != defaultHTTPMaxRetries {
+		t.Errorf("MaxRetries = %d, want %d", runtime.config.MaxRetries, defaultHTTPMaxRetries)
+	}
+	if runtime.config.RetryInterval != defaultHTTPRetryInterval {
+		t.Errorf("RetryInterval = %v, want %v", runtime.config.RetryInterval, defaultHTTPRetryInterval)
+	}
+}
+
+func TestSplitPathList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"/healthz", []string{"/healthz"}},
+		{"/a, /b", []string{"/a", "/b"}},
+		{"GET /,POST /setup", []string{"GET /", "POST /setup"}},
+		{"GET,POST /setup,/callback", []string{"GET,POST /setup", "/callback"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := splitPathList(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPathList(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
+)
+
+// eventPublishTimeout bounds how long a load waits for a lifecycle event
+// to be published.
+const eventPublishTimeout = 5 * time.Second
+
+// credentialEnvKeys are the variables compared to detect rotated
+// credentials.
+var credentialEnvKeys = []string{
+	configstore.EnvGitHubAppID,
+	configstore.EnvGitHubAppPrivateKey,
+	configstore.EnvGitHubWebhookSecret,
+	configstore.EnvGitHubClientSecret,
+}
+
+// Events returns the lifecycle event publisher set with Config.Events, or
+// nil.
+func (r *Runtime) Events() lifecycle.Publisher {
+	return r.config.Events
+}
+
+// credentialsSum returns a digest of the loaded credentials, or "" if none
+// are set. Only the digest is kept so the Runtime holds no extra copy of
+// the secrets.
+func credentialsSum() string {
+	h := sha256.New()
+	var found bool
+	for _, key := range credentialEnvKeys {
+		v := os.Getenv(key)
+		found = found || v != ""
+		h.Write([]byte(key + "=" + v + "\x00"))
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// updateCredentialsSum records the digest of the credentials loaded by a
+// successful load and reports whether they replaced different ones. The
+// caller must hold loadMu.
+func (r *Runtime) updateCredentialsSum() bool {
+	sum := credentialsSum()
+	rotated := r.credsSum != "" && sum != "" && sum != r.credsSum
+	if sum != "" {
+		r.credsSum = sum
+	}
+	return rotated
+}
+
+// publishLoadEvents publishes the lifecycle events for a finished load.
+func (r *Runtime) publishLoadEvents(ctx context.Context, kind string, rotated bool, err error) {
+	switch {
+	case err != nil && kind == configwait.AttemptReload:
+		r.publishEvent(ctx, lifecycle.ReloadFailed, err)
+	case rotated:
+		r.publishEvent(ctx, lifecycle.CredentialsRotated, nil)
+	}
+}
+
+// publishEvent publishes an event describing the loaded app, if a publisher
+// is configured. Failures are logged; they never fail the load.
+func (r *Runtime) publishEvent(ctx context.Context, eventType string, err error) {
+	if r.config.Events == nil {
+		return
+	}
+	e := lifecycle.Event{
+		Type:       eventType,
+		AppSlug:    os.Getenv(configstore.EnvGitHubAppSlug),
+		HTMLURL:    os.Getenv(configstore.EnvGitHubAppHTMLURL),
+		Generation: r.Generation(),
+	}
+	if id, parseErr := strconv.ParseInt(os.Getenv(configstore.EnvGitHubAppID), 10, 64); parseErr == nil {
+		e.AppID = id
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	// Publish even if the caller that started the load has gone away
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventPublishTimeout)
+	defer cancel()
+	if pubErr := r.config.Events.Publish(ctx, e); pubErr != nil {
+		clog.FromContext(ctx).Warnf("[ghappsetup] failed to publish %s event: %v", eventType, pubErr)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
+)
+
+// recordingPublisher records published lifecycle events.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []lifecycle.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, e lifecycle.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+	return nil
+}
+
+func (p *recordingPublisher) types() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var types []string
+	for _, e := range p.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestRuntime_LifecycleEvents(t *testing.T) {
+	clearPlatformEnv(t)
+	t.Setenv(configstore.EnvGitHubAppID, "")
+	t.Setenv(configstore.EnvGitHubAppSlug, "my-app")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, "")
+	t.Setenv(configstore.EnvGitHubWebhookSecret, "")
+	t.Setenv(configstore.EnvGitHubClientSecret, "")
+
+	events := &recordingPublisher{}
+	var fail atomic.Bool
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if fail.Load() {
+				return errors.New("parameter not found")
+			}
+			return nil
+		},
+		Events: events,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// Loading without credentials, then the first credentials, is not a
+	// rotation
+	if err := runtime.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(configstore.EnvGitHubAppID, "42")
+	os.Setenv(configstore.EnvGitHubAppPrivateKey, "key-1")
+	runtime.Reload(ctx)
+	runtime.Reload(ctx)
+	if got := events.types(); len(got) != 0 {
+		t.Fatalf("events = %v, want none", got)
+	}
+
+	os.Setenv(configstore.EnvGitHubAppPrivateKey, "key-2")
+	runtime.Reload(ctx)
+	fail.Store(true)
+	runtime.Reload(ctx)
+
+	got := events.types()
+	if len(got) != 2 || got[0] != lifecycle.CredentialsRotated || got[1] != lifecycle.ReloadFailed {
+		t.Fatalf("events = %v, want [CredentialsRotated ReloadFailed]", got)
+	}
+	rotated, failed := events.events[0], events.events[1]
+	if rotated.AppID != 42 || rotated.AppSlug != "my-app" || rotated.Generation != 4 {
+		t.Errorf("CredentialsRotated = %+v", rotated)
+	}
+	if failed.Error != "parameter not found" || failed.Generation != 4 {
+		t.Errorf("ReloadFailed = %+v", failed)
+	}
+}
+
+func TestRuntime_LifecycleEvents_FailedFirstLoad(t *testing.T) {
+	clearPlatformEnv(t)
+	events := &recordingPublisher{}
+	runtime, err := NewRuntime(Config{
+		Store:      &mockStore{},
+		LoadFunc:   func(ctx context.Context) error { return errors.New("not ready") },
+		MaxRetries: 1,
+		Events:     events,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.Reload(context.Background())
+	if got := events.types(); len(got) != 0 {
+		t.Errorf("events = %v, want none before the first successful load", got)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+)
+
+// defaultReadinessCheckTimeout bounds each readiness check run by Health.
+const defaultReadinessCheckTimeout = 5 * time.Second
+
+// Health status values reported in HealthReport.Status.
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusNotReady = "not ready"
+)
+
+// ReadinessCheck reports whether an external dependency is available.
+// It should return nil when the dependency is usable.
+type ReadinessCheck func(ctx context.Context) error
+
+// namedCheck is a registered readiness check.
+type namedCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// CheckResult is the outcome of a single readiness check.
+type CheckResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport summarizes configuration readiness, startup progress,
+// pipeline stages, and the results of registered readiness checks.
+type HealthReport struct {
+	Status      string        `json:"status"`
+	Ready       bool          `json:"ready"`
+	Degraded    bool          `json:"degraded,omitempty"`
+	Draining    bool          `json:"draining,omitempty"`
+	Maintenance bool          `json:"maintenance,omitempty"`
+	Shedding    bool          `json:"shedding,omitempty"`
+	Progress    Progress      `json:"progress"`
+	Stages      []StageStatus `json:"stages,omitempty"`
+	Checks      []CheckResult `json:"checks,omitempty"`
+
+	// Conditions lists the ReadyGate conditions registered with Condition.
+	Conditions []configwait.ConditionStatus `json:"conditions,omitempty"`
+}
+
+// AddReadinessCheck registers a named check for an external dependency
+// (e.g., GitHub API reachability or a database). Checks run on every
+// health request and the runtime is reported as not ready while any check
+// fails, so that "configuration loaded" is not conflated with "able to
+// serve". Checks do not affect IsReady or the ReadyGate.
+func (r *Runtime) AddReadinessCheck(name string, check ReadinessCheck) {
+	r.checksMu.Lock()
+	defer r.checksMu.Unlock()
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Condition returns the named ReadyGate condition, registering it if
+// needed (see configwait.ReadyGate.Condition). Gated requests wait until
+// configuration has loaded and every condition is set, and Health reports
+// not ready while any condition is unset. It returns nil outside HTTP
+// environments, which have no ReadyGate.
+func (r *Runtime) Condition(name string) *configwait.Condition {
+	if r.gate == nil {
+		return nil
+	}
+	return r.gate.Condition(name)
+}
+
+// Health runs all registered readiness checks concurrently and returns a
+// report of the runtime's overall health. Each check is bounded by a
+// 5-second timeout.
+func (r *Runtime) Health(ctx context.Context) HealthReport {
+	report := HealthReport{
+		Ready:       r.IsReady(),
+		Degraded:    r.IsDegraded(),
+		Draining:    r.IsShuttingDown(),
+		Maintenance: r.InMaintenance(),
+		Shedding:    r.shedding.Load(),
+		Progress:    r.Progress(),
+		Stages:      r.Stages(),
+		Checks:      r.runChecks(ctx),
+	}
+	if r.gate != nil {
+		report.Conditions = r.gate.Conditions()
+	}
+
+	checksOK := true
+	for _, c := range report.Checks {
+		checksOK = checksOK && c.OK
+	}
+	for _, c := range report.Conditions {
+		checksOK = checksOK && c.Ready
+	}
+
+	switch {
+	case !report.Ready || report.Draining || report.Maintenance || report.Shedding || !checksOK:
+		report.Status = HealthStatusNotReady
+	case report.Degraded:
+		report.Status = HealthStatusDegraded
+	default:
+		report.Status = HealthStatusOK
+	}
+	return report
+}
+
+// runChecks executes registered checks in parallel, preserving
+// registration order in the results.
+func (r *Runtime) runChecks(ctx context.Context) []CheckResult {
+	r.checksMu.Lock()
+	checks := make([]namedCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.checksMu.Unlock()
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, defaultReadinessCheckTimeout)
+			defer cancel()
+
+			results[i] = CheckResult{Name: c.name, OK: true}
+			if err := c.check(checkCtx); err != nil {
+				results[i].OK = false
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// wantsJSON reports whether the client asked for a JSON health report via
+// the Accept header or a format=json query parameter.
+func wantsJSON(req *http.Request) bool {
+	if req.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(req.Header.Get("Accept"), "application/json")
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRuntime_Health_ReadinessChecks(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	dbErr := errors.New("connection refused")
+	dbHealthy := false
+	runtime.AddReadinessCheck("github", func(ctx context.Context) error { return nil })
+	runtime.AddReadinessCheck("database", func(ctx context.Context) error {
+		if dbHealthy {
+			return nil
+		}
+		return dbErr
+	})
+
+	report := runtime.Health(context.Background())
+	if report.Status != HealthStatusNotReady {
+		t.Errorf("Status = %q, want %q with failing check", report.Status, HealthStatusNotReady)
+	}
+	if len(report.Checks) != 2 {
+		t.Fatalf("len(Checks) = %d, want 2", len(report.Checks))
+	}
+	if report.Checks[0].Name != "github" || !report.Checks[0].OK {
+		t.Errorf("Checks[0] = %+v, want github ok", report.Checks[0])
+	}
+	if report.Checks[1].Name != "database" || report.Checks[1].OK || report.Checks[1].Error != dbErr.Error() {
+		t.Errorf("Checks[1] = %+v, want database failed", report.Checks[1])
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if want := "not ready: check database failed"; rec.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rec.Body.String(), want)
+	}
+
+	dbHealthy = true
+	if report := runtime.Health(context.Background()); report.Status != HealthStatusOK {
+		t.Errorf("Status = %q, want %q once checks pass", report.Status, HealthStatusOK)
+	}
+}
+
+func TestRuntime_HealthHandler_JSON(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.AddReadinessCheck("github", func(ctx context.Context) error { return nil })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/healthz?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		runtime.HealthHandler()(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want %q", ct, "application/json")
+		}
+
+		var report HealthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if report.Ready || report.Status != HealthStatusNotReady {
+			t.Errorf("report = %+v, want not ready", report)
+		}
+		if len(report.Checks) != 1 || !report.Checks[0].OK {
+			t.Errorf("Checks = %+v, want one passing check", report.Checks)
+		}
+	}
+}
+
+func TestRuntime_Condition(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	db := runtime.Condition("db")
+	runtime.setReady(true)
+
+	if code := gateStatus(runtime); code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d with unset condition", code, http.StatusServiceUnavailable)
+	}
+	if text := healthText(runtime.Health(context.Background())); text != "not ready: condition db unset" {
+		t.Errorf("health = %q, want %q", text, "not ready: condition db unset")
+	}
+
+	db.Set()
+	if code := gateStatus(runtime); code != http.StatusOK {
+		t.Errorf("Status = %d, want %d once condition is set", code, http.StatusOK)
+	}
+	if report := runtime.Health(context.Background()); report.Status != HealthStatusOK || len(report.Conditions) != 1 {
+		t.Errorf("report = %+v, want ok with 1 condition", report)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// modulePath is the import path of this library, used to report its version
+// from the embedding binary's build info.
+const modulePath = "github.com/cruxstack/github-app-setup-go"
+
+// readBuildInfo is replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// Info describes the running binary and the registered GitHub App.
+type Info struct {
+	GoVersion      string `json:"go_version,omitempty"`
+	Module         string `json:"module,omitempty"`
+	Version        string `json:"version,omitempty"`
+	LibraryVersion string `json:"library_version,omitempty"`
+	Revision       string `json:"revision,omitempty"`
+	RevisionTime   string `json:"revision_time,omitempty"`
+	Modified       bool   `json:"modified,omitempty"`
+	Environment    string `json:"environment"`
+	Platform       string `json:"platform"`
+	Ready          bool   `json:"ready"`
+	Degraded       bool   `json:"degraded,omitempty"`
+	Registered     bool   `json:"registered"`
+	AppID          int64  `json:"app_id,omitempty"`
+	AppSlug        string `json:"app_slug,omitempty"`
+
+	// ECS describes the task on ECS (see Runtime.ECSTask).
+	ECS *ECSTask `json:"ecs,omitempty"`
+}
+
+// InfoHandler returns an http.HandlerFunc that reports build and app
+// information as JSON. Build details come from debug.ReadBuildInfo and
+// include the main module version, this library's version, and the VCS
+// revision. App details come from the Store's status. The endpoint exposes
+// no secrets and is suitable for fleet inventory.
+func (r *Runtime) InfoHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := clog.FromContext(req.Context())
+
+		info := buildInfo()
+		info.Environment = r.env.String()
+		info.Platform = r.platform.String()
+		info.Ready = r.IsReady()
+		info.Degraded = r.IsDegraded()
+		if r.platform == PlatformECS {
+			task, err := r.ECSTask(req.Context())
+			if err != nil {
+				log.Warnf("[ghappsetup] failed to read ECS task metadata for info endpoint: %v", err)
+			}
+			info.ECS = task
+		}
+
+		status, err := r.store.Status(req.Context())
+		if err != nil {
+			log.Warnf("[ghappsetup] failed to read installer status for info endpoint: %v", err)
+		} else if status != nil {
+			info.Registered = status.Registered
+			info.AppID = status.AppID
+			info.AppSlug = status.AppSlug
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(info); err != nil {
+			log.Errorf("[ghappsetup] failed to write info response: %v", err)
+		}
+	}
+}
+
+// buildInfo extracts version details from the binary's embedded build info.
+func buildInfo() Info {
+	var info Info
+
+	bi, ok := readBuildInfo()
+	if !ok || bi == nil {
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+	info.Module = bi.Main.Path
+	info.Version = bi.Main.Version
+
+	if bi.Main.Path == modulePath {
+		info.LibraryVersion = bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == modulePath {
+			info.LibraryVersion = dep.Version
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				info.LibraryVersion = dep.Replace.Version
+			}
+			break
+		}
+	}
+
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.RevisionTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func TestRuntime_InfoHandler(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	orig := readBuildInfo
+	defer func() { readBuildInfo = orig }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.25.0",
+			Main:      debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+			Deps: []*debug.Module{
+				{Path: modulePath, Version: "v0.4.0"},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abc123"},
+				{Key: "vcs.time", Value: "2025-01-01T00:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	runtime, err := NewRuntime(Config{
+		Store: &infoMockStore{status: &configstore.InstallerStatus{
+			Registered: true,
+			AppID:      42,
+			AppSlug:    "my-app",
+		}},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.InfoHandler()(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", ct, "application/json")
+	}
+
+	var info Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+
+	want := Info{
+		GoVersion:      "go1.25.0",
+		Module:         "example.com/app",
+		Version:        "v1.2.3",
+		LibraryVersion: "v0.4.0",
+		Revision:       "abc123",
+		RevisionTime:   "2025-01-01T00:00:00Z",
+		Modified:       true,
+		Environment:    "http",
+		Platform:       detectPlatform().String(),
+		Registered:     true,
+		AppID:          42,
+		AppSlug:        "my-app",
+	}
+	if info != want {
+		t.Errorf("Info = %+v, want %+v", info, want)
+	}
+}
+
+func TestRuntime_InfoHandler_StatusError(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &infoMockStore{err: errors.New("store unavailable")},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.InfoHandler()(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var info Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if info.Registered {
+		t.Error("Registered should be false when status cannot be read")
+	}
+}
+
+func TestEnvironment_String(t *testing.T) {
+	if got := EnvironmentHTTP.String(); got != "http" {
+		t.Errorf("EnvironmentHTTP.String() = %q, want %q", got, "http")
+	}
+	if got := EnvironmentLambda.String(); got != "lambda" {
+		t.Errorf("EnvironmentLambda.String() = %q, want %q", got, "lambda")
+	}
+}
+
+// infoMockStore returns a fixed status for info endpoint tests.
+type infoMockStore struct {
+	status *configstore.InstallerStatus
+	err    error
+}
+
+func (m *infoMockStore) Save(ctx context.Context, creds *configstore.AppCredentials) error {
+	return nil
+}
+
+func (m *infoMockStore) Status(ctx context.Context) (*configstore.InstallerStatus, error) {
+	return m.status, m.err
+}
+
+func (m *infoMockStore) DisableInstaller(ctx context.Context) error {
+	return nil
+}
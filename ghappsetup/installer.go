@@ -30,10 +30,51 @@ import (
 //
 // The Config.Store and Config.OnReloadNeeded fields are automatically set
 // by this method and should not be provided in the input config.
+// Config.Events defaults to the Runtime's publisher (see Runtime.Events),
+// and the manifest of Config.SettingsFile, when it has one, takes
+// precedence over Config.Manifest unless Config.ManifestFunc is set.
 func (r *Runtime) InstallerHandler(cfg installer.Config) (http.Handler, error) {
 	// Set store and reload callback automatically
 	cfg.Store = r.store
 	cfg.OnReloadNeeded = r.ReloadCallback()
+	if cfg.Events == nil {
+		cfg.Events = r.config.Events
+	}
+	if cfg.ManifestFunc == nil && r.settings != nil {
+		cfg.ManifestFunc = r.settingsManifest
+	}
 
 	return installer.New(cfg)
 }
+
+// installerPaths are the mux patterns served by the installer handler.
+var installerPaths = []string{"/setup", "/setup/", "/callback", "/"}
+
+// MountInstaller creates an installer handler with InstallerHandler and
+// registers it on mux at /setup, /setup/, /callback, and /. The same paths
+// are added to the Runtime's allowed paths so the installer stays reachable
+// before configuration loads, replacing the manual mounting shown on
+// InstallerHandler:
+//
+//	if err := runtime.MountInstaller(mux, installer.Config{
+//	    Manifest:       manifest,
+//	    AppDisplayName: "My App",
+//	}); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// As with http.ServeMux.Handle, MountInstaller panics if any of these
+// patterns is already registered on mux.
+func (r *Runtime) MountInstaller(mux *http.ServeMux, cfg installer.Config) error {
+	handler, err := r.InstallerHandler(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range installerPaths {
+		mux.Handle(path, handler)
+	}
+
+	r.allowPaths(installerPaths...)
+	return nil
+}
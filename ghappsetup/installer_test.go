@@ -0,0 +1,75 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+func TestRuntime_MountInstaller(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		LoadFunc:     func(ctx context.Context) error { return nil },
+		AllowedPaths: []string{"/healthz"},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	if err := runtime.MountInstaller(mux, installer.Config{
+		Manifest: installer.Manifest{URL: "https://example.com"},
+	}); err != nil {
+		t.Fatalf("MountInstaller() error = %v", err)
+	}
+
+	handler := runtime.Handler(mux)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{"/setup", http.StatusOK},
+		{"/setup/", http.StatusOK},
+		{"/", http.StatusFound},
+		{"/webhook", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRuntime_MountInstaller_Error(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.store = nil
+
+	if err := runtime.MountInstaller(http.NewServeMux(), installer.Config{}); err == nil {
+		t.Error("MountInstaller() should return error when installer cannot be created")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+// SetMaintenance enables or disables maintenance mode. While enabled, the
+// ReadyGate rejects gated requests and Health reports not ready, without
+// affecting whether configuration is loaded. Disabling it reopens the gate
+// if the runtime is otherwise ready.
+func (r *Runtime) SetMaintenance(on bool) {
+	r.maintenance.Store(on)
+	r.syncGate()
+}
+
+// InMaintenance returns true while maintenance mode is enabled.
+func (r *Runtime) InMaintenance() bool {
+	return r.maintenance.Load()
+}
+
+// serving reports whether the runtime should accept gated traffic: it is
+// ready, not in maintenance, and not shedding after a failed reload.
+func (r *Runtime) serving() bool {
+	return r.IsReady() && !r.maintenance.Load() && !r.shedding.Load()
+}
+
+// syncGate opens or closes the ReadyGate to match serving.
+func (r *Runtime) syncGate() {
+	if r.gate == nil {
+		return
+	}
+	if r.serving() {
+		r.gate.SetReady()
+	} else {
+		r.gate.SetNotReady()
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// gateStatus returns the status code the runtime's handler returns for a
+// gated request.
+func gateStatus(r *Runtime) int {
+	rec := httptest.NewRecorder()
+	r.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	return rec.Code
+}
+
+func TestRuntime_SetMaintenance(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	runtime.SetMaintenance(true)
+	if code := gateStatus(runtime); code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d in maintenance", code, http.StatusServiceUnavailable)
+	}
+	if text := healthText(runtime.Health(context.Background())); text != "not ready: maintenance" {
+		t.Errorf("health = %q, want %q", text, "not ready: maintenance")
+	}
+	if !runtime.IsReady() {
+		t.Error("IsReady() should stay true in maintenance")
+	}
+
+	runtime.SetMaintenance(false)
+	if code := gateStatus(runtime); code != http.StatusOK {
+		t.Errorf("Status = %d, want %d after maintenance", code, http.StatusOK)
+	}
+}
+
+func TestRuntime_ShedOnReloadFailure(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var loadErr error
+	runtime, err := NewRuntime(Config{
+		Store:               &mockStore{},
+		LoadFunc:            func(ctx context.Context) error { return loadErr },
+		ShedOnReloadFailure: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	loadErr = errors.New("bad config")
+	_ = runtime.Reload(context.Background())
+	if code := gateStatus(runtime); code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d after failed reload", code, http.StatusServiceUnavailable)
+	}
+	if text := healthText(runtime.Health(context.Background())); text != "not ready: reload failed" {
+		t.Errorf("health = %q, want %q", text, "not ready: reload failed")
+	}
+
+	loadErr = nil
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if code := gateStatus(runtime); code != http.StatusOK {
+		t.Errorf("Status = %d, want %d after successful reload", code, http.StatusOK)
+	}
+}
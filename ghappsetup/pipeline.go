@@ -0,0 +1,178 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// NamedLoadFunc is a single named stage of a multi-stage load pipeline.
+// Stages run in order and each stage only runs after the previous one
+// succeeded.
+type NamedLoadFunc struct {
+	// Name identifies the stage in logs, errors, and health output.
+	Name string
+
+	// Func performs the stage's work.
+	Func LoadFunc
+
+	// MaxRetries is the number of attempts made for this stage within a
+	// single pipeline run. If zero, the stage is attempted once and the
+	// Runtime retry policy re-runs the pipeline.
+	MaxRetries int
+
+	// RetryInterval is the time to wait between attempts of this stage.
+	RetryInterval time.Duration
+
+	// Timeout bounds each attempt of this stage. If zero, attempts are
+	// bounded only by the caller's context.
+	Timeout time.Duration
+}
+
+// StageState describes the progress of a pipeline stage.
+type StageState string
+
+const (
+	// StagePending indicates the stage has not started in the current run.
+	StagePending StageState = "pending"
+	// StageRunning indicates the stage is currently executing.
+	StageRunning StageState = "running"
+	// StageSucceeded indicates the stage completed successfully.
+	StageSucceeded StageState = "succeeded"
+	// StageFailed indicates the stage exhausted its attempts.
+	StageFailed StageState = "failed"
+)
+
+// StageStatus reports the state of a single pipeline stage.
+type StageStatus struct {
+	Name      string     `json:"name"`
+	State     StageState `json:"state"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// pipeline runs NamedLoadFuncs in order and tracks per-stage status.
+type pipeline struct {
+	stages []NamedLoadFunc
+
+	mu        sync.Mutex
+	status    []StageStatus
+	completed bool
+}
+
+func newPipeline(stages []NamedLoadFunc) (*pipeline, error) {
+	status := make([]StageStatus, len(stages))
+	for i, stage := range stages {
+		if stage.Name == "" {
+			return nil, fmt.Errorf("ghappsetup: LoadFuncs[%d] has no Name", i)
+		}
+		if stage.Func == nil {
+			return nil, fmt.Errorf("ghappsetup: LoadFuncs[%d] (%s) has no Func", i, stage.Name)
+		}
+		status[i] = StageStatus{Name: stage.Name, State: StagePending}
+	}
+	return &pipeline{stages: stages, status: status}, nil
+}
+
+// run executes every stage in order. It satisfies LoadFunc so the pipeline
+// can be driven by the same retry and reload paths as a single LoadFunc.
+func (p *pipeline) run(ctx context.Context) error {
+	p.mu.Lock()
+	for i := range p.status {
+		p.status[i].State = StagePending
+		p.status[i].LastError = ""
+		if p.completed {
+			p.status[i].Attempts = 0
+		}
+	}
+	p.completed = false
+	p.mu.Unlock()
+
+	for i, stage := range p.stages {
+		if err := p.runStage(ctx, i, stage); err != nil {
+			return fmt.Errorf("stage %s: %w", stage.Name, err)
+		}
+	}
+
+	p.mu.Lock()
+	p.completed = true
+	p.mu.Unlock()
+	return nil
+}
+
+// runStage runs a single stage with its own retry policy.
+func (p *pipeline) runStage(ctx context.Context, i int, stage NamedLoadFunc) error {
+	log := clog.FromContext(ctx)
+
+	maxAttempts := stage.MaxRetries
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	p.update(i, func(s *StageStatus) { s.State = StageRunning })
+	ReportPhase(ctx, stage.Name)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := p.attempt(ctx, stage)
+		p.update(i, func(s *StageStatus) {
+			s.Attempts++
+			if err != nil {
+				s.LastError = err.Error()
+			}
+		})
+		if err == nil {
+			p.update(i, func(s *StageStatus) {
+				s.State = StageSucceeded
+				s.LastError = ""
+			})
+			return nil
+		}
+
+		lastErr = err
+		log.Warnf("[ghappsetup] stage %s attempt %d/%d failed: %v", stage.Name, attempt, maxAttempts, err)
+
+		if attempt < maxAttempts {
+			select {
+			case <-ctx.Done():
+				p.update(i, func(s *StageStatus) { s.State = StageFailed })
+				return ctx.Err()
+			case <-time.After(stage.RetryInterval):
+			}
+		}
+	}
+
+	p.update(i, func(s *StageStatus) { s.State = StageFailed })
+	return lastErr
+}
+
+// attempt runs the stage once, applying the stage timeout if configured.
+func (p *pipeline) attempt(ctx context.Context, stage NamedLoadFunc) error {
+	if stage.Timeout <= 0 {
+		return stage.Func(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
+	defer cancel()
+	return stage.Func(attemptCtx)
+}
+
+func (p *pipeline) update(i int, fn func(*StageStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(&p.status[i])
+}
+
+// snapshot returns a copy of the current stage status.
+func (p *pipeline) snapshot() []StageStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]StageStatus, len(p.status))
+	copy(out, p.status)
+	return out
+}
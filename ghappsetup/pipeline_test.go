@@ -0,0 +1,224 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRuntime_LoadFuncsValidation(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			name: "both LoadFunc and LoadFuncs",
+			cfg: Config{
+				LoadFunc:  noop,
+				LoadFuncs: []NamedLoadFunc{{Name: "a", Func: noop}},
+			},
+		},
+		{
+			name: "stage without name",
+			cfg:  Config{LoadFuncs: []NamedLoadFunc{{Func: noop}}},
+		},
+		{
+			name: "stage without func",
+			cfg:  Config{LoadFuncs: []NamedLoadFunc{{Name: "a"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Store = &mockStore{}
+			if _, err := NewRuntime(tt.cfg); err == nil {
+				t.Error("NewRuntime() should return error")
+			}
+		})
+	}
+}
+
+func TestRuntime_Pipeline_RunsStagesInOrder(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var order []string
+	stage := func(name string) NamedLoadFunc {
+		return NamedLoadFunc{
+			Name: name,
+			Func: func(ctx context.Context) error {
+				order = append(order, name)
+				return nil
+			},
+		}
+	}
+
+	runtime, err := NewRuntime(Config{
+		Store:         &mockStore{},
+		LoadFuncs:     []NamedLoadFunc{stage("resolve"), stage("parse"), stage("client")},
+		MaxRetries:    1,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if got := strings.Join(order, ","); got != "resolve,parse,client" {
+		t.Errorf("stage order = %q, want %q", got, "resolve,parse,client")
+	}
+	for _, s := range runtime.Stages() {
+		if s.State != StageSucceeded {
+			t.Errorf("stage %s state = %s, want %s", s.Name, s.State, StageSucceeded)
+		}
+	}
+}
+
+func TestRuntime_Pipeline_StageRetries(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var firstCount, secondCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFuncs: []NamedLoadFunc{
+			{
+				Name: "first",
+				Func: func(ctx context.Context) error {
+					firstCount.Add(1)
+					return nil
+				},
+			},
+			{
+				Name: "second",
+				Func: func(ctx context.Context) error {
+					if secondCount.Add(1) < 3 {
+						return errors.New("not yet")
+					}
+					return nil
+				},
+				MaxRetries:    3,
+				RetryInterval: 5 * time.Millisecond,
+			},
+		},
+		MaxRetries:    1,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if firstCount.Load() != 1 {
+		t.Errorf("first stage called %d times, want 1", firstCount.Load())
+	}
+	stages := runtime.Stages()
+	if stages[1].Attempts != 3 {
+		t.Errorf("second stage attempts = %d, want 3", stages[1].Attempts)
+	}
+}
+
+func TestRuntime_Pipeline_FailureNamesStage(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	expectedErr := errors.New("bad key")
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFuncs: []NamedLoadFunc{
+			{Name: "resolve-ssm", Func: func(ctx context.Context) error { return nil }},
+			{Name: "parse-credentials", Func: func(ctx context.Context) error { return expectedErr }},
+			{Name: "build-client", Func: func(ctx context.Context) error { return nil }},
+		},
+		MaxRetries:    2,
+		RetryInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	err = runtime.Start(context.Background())
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Start() error = %v, want %v", err, expectedErr)
+	}
+	if !strings.Contains(err.Error(), "parse-credentials") {
+		t.Errorf("Start() error = %q, should name the failed stage", err.Error())
+	}
+
+	stages := runtime.Stages()
+	if stages[1].State != StageFailed {
+		t.Errorf("parse-credentials state = %s, want %s", stages[1].State, StageFailed)
+	}
+	if stages[1].Attempts != 2 {
+		t.Errorf("parse-credentials attempts = %d, want 2", stages[1].Attempts)
+	}
+	if stages[2].State != StagePending {
+		t.Errorf("build-client state = %s, want %s", stages[2].State, StagePending)
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	want := "not ready: stage parse-credentials failed"
+	if rec.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+func TestRuntime_Pipeline_StageTimeout(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFuncs: []NamedLoadFunc{
+			{
+				Name: "slow",
+				Func: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+				Timeout: 10 * time.Millisecond,
+			},
+		},
+		MaxRetries:    1,
+		RetryInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	err = runtime.Start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Start() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRuntime_Stages_NilWithoutPipeline(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if stages := runtime.Stages(); stages != nil {
+		t.Errorf("Stages() = %v, want nil", stages)
+	}
+}
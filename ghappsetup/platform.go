@@ -0,0 +1,191 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	// Environment variables used to detect hosting platforms.
+	envECSContainerMetadataURIV4 = "ECS_CONTAINER_METADATA_URI_V4"
+	envECSContainerMetadataURI   = "ECS_CONTAINER_METADATA_URI"
+	envAWSExecutionEnv           = "AWS_EXECUTION_ENV"
+	envCloudRunService           = "K_SERVICE"
+	envCloudRunJob               = "CLOUD_RUN_JOB"
+	envCloudFunctionTarget       = "FUNCTION_TARGET"
+	envAzureFunctionsRuntime     = "FUNCTIONS_WORKER_RUNTIME"
+	envAzureFunctionsEnvironment = "AZURE_FUNCTIONS_ENVIRONMENT"
+	envKubernetesServiceHost     = "KUBERNETES_SERVICE_HOST"
+
+	// Default retry settings for Google Cloud Functions.
+	defaultFunctionsMaxRetries    = 5
+	defaultFunctionsRetryInterval = 1 * time.Second
+
+	// Default retry settings for Azure Functions. Cold starts take longer
+	// than on Lambda, and the managed identity endpoint used for Key Vault
+	// may fail for a few seconds while an instance starts.
+	defaultAzureFunctionsMaxRetries    = 10
+	defaultAzureFunctionsRetryInterval = 2 * time.Second
+
+	// Default retry settings for Google Cloud Run.
+	defaultCloudRunMaxRetries    = 15
+	defaultCloudRunRetryInterval = 2 * time.Second
+
+	// Default retry settings for ECS. Tasks often start before their
+	// secrets or sidecars are ready, and the container health check's start
+	// period allows for a longer wait than other HTTP servers get.
+	defaultECSMaxRetries    = 60
+	defaultECSRetryInterval = 2 * time.Second
+
+	// Default drain period for ECS, leaving time for in-flight requests
+	// within the default 30-second stop timeout.
+	defaultECSDrainPeriod = 5 * time.Second
+)
+
+// Platform identifies the hosting platform the Runtime is running on.
+// Unlike Environment, which selects between the HTTP and Lambda lifecycles,
+// Platform is informational and selects per-platform retry defaults.
+type Platform int
+
+const (
+	// PlatformGeneric indicates no known hosting platform was detected.
+	PlatformGeneric Platform = iota
+	// PlatformLambda indicates AWS Lambda.
+	PlatformLambda
+	// PlatformECS indicates AWS ECS, including Fargate.
+	PlatformECS
+	// PlatformCloudRun indicates a Google Cloud Run service or job.
+	PlatformCloudRun
+	// PlatformCloudFunctions indicates Google Cloud Functions.
+	PlatformCloudFunctions
+	// PlatformAzureFunctions indicates Azure Functions.
+	PlatformAzureFunctions
+	// PlatformKubernetes indicates a Kubernetes pod.
+	PlatformKubernetes
+)
+
+// String returns a short lowercase name for the platform.
+func (p Platform) String() string {
+	switch p {
+	case PlatformGeneric:
+		return "generic"
+	case PlatformLambda:
+		return "lambda"
+	case PlatformECS:
+		return "ecs"
+	case PlatformCloudRun:
+		return "cloud-run"
+	case PlatformCloudFunctions:
+		return "cloud-functions"
+	case PlatformAzureFunctions:
+		return "azure-functions"
+	case PlatformKubernetes:
+		return "kubernetes"
+	default:
+		return "unknown"
+	}
+}
+
+// Environment returns the lifecycle used on the platform. Only Lambda uses
+// the lazy EnsureLoaded lifecycle; every other platform runs an HTTP server.
+func (p Platform) Environment() Environment {
+	if p == PlatformLambda {
+		return EnvironmentLambda
+	}
+	return EnvironmentHTTP
+}
+
+// retryDefaults returns the default retry policy for the platform.
+func (p Platform) retryDefaults() (int, time.Duration) {
+	switch p {
+	case PlatformLambda:
+		return defaultLambdaMaxRetries, defaultLambdaRetryInterval
+	case PlatformCloudFunctions:
+		return defaultFunctionsMaxRetries, defaultFunctionsRetryInterval
+	case PlatformAzureFunctions:
+		return defaultAzureFunctionsMaxRetries, defaultAzureFunctionsRetryInterval
+	case PlatformCloudRun:
+		return defaultCloudRunMaxRetries, defaultCloudRunRetryInterval
+	case PlatformECS:
+		return defaultECSMaxRetries, defaultECSRetryInterval
+	default:
+		return defaultHTTPMaxRetries, defaultHTTPRetryInterval
+	}
+}
+
+// drainDefault returns the default Config.DrainPeriod for the platform.
+func (p Platform) drainDefault() time.Duration {
+	if p == PlatformECS {
+		return defaultECSDrainPeriod
+	}
+	return 0
+}
+
+// shutdownSignals returns the signals the platform sends to stop the
+// process, which RunHTTP treats like a canceled context. ECS sends SIGTERM
+// and kills the container once the stop timeout expires; Cloud Run and
+// Cloud Functions send SIGTERM 10 seconds before SIGKILL.
+func (p Platform) shutdownSignals() []os.Signal {
+	switch p {
+	case PlatformECS, PlatformCloudRun, PlatformCloudFunctions:
+		return []os.Signal{syscall.SIGTERM}
+	default:
+		return nil
+	}
+}
+
+// reloadSignals returns the signals ListenForReloads treats as reload
+// triggers. Azure Functions gives no way to signal a custom handler
+// process, which may not even run on a system with SIGHUP, so reloads
+// there come from ReloadCallback and ReloadHandler only.
+func (p Platform) reloadSignals() []os.Signal {
+	if p == PlatformAzureFunctions {
+		return nil
+	}
+	return []os.Signal{syscall.SIGHUP}
+}
+
+// cpuThrottled reports whether the platform throttles CPU outside of
+// request processing by default. Cloud Run services and Cloud Functions
+// use request-based billing unless configured otherwise; Cloud Run jobs
+// always have CPU.
+func (p Platform) cpuThrottled() bool {
+	switch p {
+	case PlatformCloudFunctions:
+		return true
+	case PlatformCloudRun:
+		return os.Getenv(envCloudRunJob) == ""
+	default:
+		return false
+	}
+}
+
+// detectPlatform checks well-known environment variables set by each
+// hosting platform. More specific platforms are checked first: Cloud
+// Functions also sets K_SERVICE, and ECS tasks may run on hosts that are
+// otherwise indistinguishable from a generic container.
+func detectPlatform() Platform {
+	switch {
+	case os.Getenv(envLambdaFunctionName) != "":
+		return PlatformLambda
+	case os.Getenv(envECSContainerMetadataURIV4) != "",
+		os.Getenv(envECSContainerMetadataURI) != "",
+		os.Getenv(envAWSExecutionEnv) == "AWS_ECS_FARGATE",
+		os.Getenv(envAWSExecutionEnv) == "AWS_ECS_EC2":
+		return PlatformECS
+	case os.Getenv(envCloudFunctionTarget) != "":
+		return PlatformCloudFunctions
+	case os.Getenv(envCloudRunService) != "", os.Getenv(envCloudRunJob) != "":
+		return PlatformCloudRun
+	case os.Getenv(envAzureFunctionsRuntime) != "", os.Getenv(envAzureFunctionsEnvironment) != "":
+		return PlatformAzureFunctions
+	case os.Getenv(envKubernetesServiceHost) != "":
+		return PlatformKubernetes
+	default:
+		return PlatformGeneric
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// platformEnvVars lists every variable consulted by detectPlatform.
+var platformEnvVars = []string{
+	envLambdaFunctionName,
+	envECSContainerMetadataURIV4,
+	envECSContainerMetadataURI,
+	envAWSExecutionEnv,
+	envCloudRunService,
+	envCloudRunJob,
+	envCloudFunctionTarget,
+	envAzureFunctionsRuntime,
+	envAzureFunctionsEnvironment,
+	envKubernetesServiceHost,
+}
+
+func clearPlatformEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range platformEnvVars {
+		t.Setenv(key, "")
+	}
+}
+
+func TestDetectPlatform(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Platform
+	}{
+		{
+			name: "no indicators",
+			want: PlatformGeneric,
+		},
+		{
+			name: "lambda",
+			env:  map[string]string{envLambdaFunctionName: "fn"},
+			want: PlatformLambda,
+		},
+		{
+			name: "ecs metadata v4",
+			env:  map[string]string{envECSContainerMetadataURIV4: "http://169.254.170.2/v4/abc"},
+			want: PlatformECS,
+		},
+		{
+			name: "fargate execution env",
+			env:  map[string]string{envAWSExecutionEnv: "AWS_ECS_FARGATE"},
+			want: PlatformECS,
+		},
+		{
+			name: "cloud run service",
+			env:  map[string]string{envCloudRunService: "svc"},
+			want: PlatformCloudRun,
+		},
+		{
+			name: "cloud run job",
+			env:  map[string]string{envCloudRunJob: "job"},
+			want: PlatformCloudRun,
+		},
+		{
+			name: "cloud functions gen2 sets both",
+			env:  map[string]string{envCloudFunctionTarget: "Handler", envCloudRunService: "fn"},
+			want: PlatformCloudFunctions,
+		},
+		{
+			name: "azure functions",
+			env:  map[string]string{envAzureFunctionsRuntime: "custom"},
+			want: PlatformAzureFunctions,
+		},
+		{
+			name: "kubernetes",
+			env:  map[string]string{envKubernetesServiceHost: "10.0.0.1"},
+			want: PlatformKubernetes,
+		},
+		{
+			name: "ecs takes precedence over kubernetes",
+			env: map[string]string{
+				envECSContainerMetadataURIV4: "http://169.254.170.2/v4/abc",
+				envKubernetesServiceHost:     "10.0.0.1",
+			},
+			want: PlatformECS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearPlatformEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			if got := detectPlatform(); got != tt.want {
+				t.Errorf("detectPlatform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRuntime_PlatformDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantEnv    Environment
+		wantPlat   Platform
+		wantMax    int
+		wantPeriod time.Duration
+	}{
+		{
+			name:       "cloud run",
+			env:        map[string]string{envCloudRunService: "svc"},
+			wantEnv:    EnvironmentHTTP,
+			wantPlat:   PlatformCloudRun,
+			wantMax:    defaultCloudRunMaxRetries,
+			wantPeriod: defaultCloudRunRetryInterval,
+		},
+		{
+			name:       "azure functions",
+			env:        map[string]string{envAzureFunctionsRuntime: "custom"},
+			wantEnv:    EnvironmentHTTP,
+			wantPlat:   PlatformAzureFunctions,
+			wantMax:    defaultAzureFunctionsMaxRetries,
+			wantPeriod: defaultAzureFunctionsRetryInterval,
+		},
+		{
+			name:       "ecs",
+			env:        map[string]string{envAWSExecutionEnv: "AWS_ECS_FARGATE"},
+			wantEnv:    EnvironmentHTTP,
+			wantPlat:   PlatformECS,
+			wantMax:    defaultECSMaxRetries,
+			wantPeriod: defaultECSRetryInterval,
+		},
+		{
+			name:       "kubernetes",
+			env:        map[string]string{envKubernetesServiceHost: "10.0.0.1"},
+			wantEnv:    EnvironmentHTTP,
+			wantPlat:   PlatformKubernetes,
+			wantMax:    defaultHTTPMaxRetries,
+			wantPeriod: defaultHTTPRetryInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearPlatformEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			runtime, err := NewRuntime(Config{
+				Store:    &mockStore{},
+				LoadFunc: func(ctx context.Context) error { return nil },
+			})
+			if err != nil {
+				t.Fatalf("NewRuntime() error = %v", err)
+			}
+
+			if runtime.Platform() != tt.wantPlat {
+				t.Errorf("Platform() = %v, want %v", runtime.Platform(), tt.wantPlat)
+			}
+			if runtime.Environment() != tt.wantEnv {
+				t.Errorf("Environment() = %v, want %v", runtime.Environment(), tt.wantEnv)
+			}
+			if runtime.config.MaxRetries != tt.wantMax {
+				t.Errorf("MaxRetries = %d, want %d", runtime.config.MaxRetries, tt.wantMax)
+			}
+			if runtime.config.RetryInterval != tt.wantPeriod {
+				t.Errorf("RetryInterval = %v, want %v", runtime.config.RetryInterval, tt.wantPeriod)
+			}
+		})
+	}
+}
+
+func TestPlatform_String(t *testing.T) {
+	if got := PlatformCloudRun.String(); got != "cloud-run" {
+		t.Errorf("PlatformCloudRun.String() = %q, want %q", got, "cloud-run")
+	}
+	if got := Platform(99).String(); got != "unknown" {
+		t.Errorf("Platform(99).String() = %q, want %q", got, "unknown")
+	}
+}
+
+func TestPlatform_ReloadSignals(t *testing.T) {
+	if sigs := PlatformAzureFunctions.reloadSignals(); len(sigs) != 0 {
+		t.Errorf("PlatformAzureFunctions.reloadSignals() = %v, want none", sigs)
+	}
+	if sigs := PlatformKubernetes.reloadSignals(); len(sigs) != 1 || sigs[0] != syscall.SIGHUP {
+		t.Errorf("PlatformKubernetes.reloadSignals() = %v, want SIGHUP", sigs)
+	}
+}
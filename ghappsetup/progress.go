@@ -0,0 +1,109 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/cruxstack/github-app-setup-go/configwait"
+)
+
+// Startup phases reported by Progress. LoadFunc implementations and
+// pipeline stages may report finer-grained phases with ReportPhase.
+const (
+	PhaseStarting = "starting"
+	PhaseLoading  = "loading"
+	PhaseReady    = "ready"
+)
+
+// Progress describes which phase of startup is in progress, so that a
+// runtime stuck at not ready can be diagnosed from its health report.
+type Progress struct {
+	// Phase is the current phase: PhaseStarting, PhaseLoading, PhaseReady,
+	// or a phase reported with ReportPhase (e.g. "resolve-ssm").
+	Phase string `json:"phase"`
+
+	// Attempt is the number of the load attempt in progress, counted since
+	// the last successful load.
+	Attempt int `json:"attempt,omitempty"`
+
+	// Since is when the current phase began.
+	Since time.Time `json:"since"`
+}
+
+// progressTracker records startup progress for a Runtime.
+type progressTracker struct {
+	mu       sync.Mutex
+	progress Progress
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{progress: Progress{Phase: PhaseStarting, Since: time.Now()}}
+}
+
+// beginAttempt records the start of a load attempt and returns its number.
+func (t *progressTracker) beginAttempt(ctx context.Context) int {
+	t.mu.Lock()
+	t.progress.Attempt++
+	t.progress.Phase = PhaseLoading
+	t.progress.Since = time.Now()
+	attempt := t.progress.Attempt
+	t.mu.Unlock()
+
+	clog.FromContext(ctx).Debugf("[ghappsetup] startup phase %s (attempt %d)", PhaseLoading, attempt)
+	return attempt
+}
+
+// setPhase records a phase within the current attempt.
+func (t *progressTracker) setPhase(ctx context.Context, phase string) {
+	t.mu.Lock()
+	t.progress.Phase = phase
+	t.progress.Since = time.Now()
+	attempt := t.progress.Attempt
+	t.mu.Unlock()
+
+	clog.FromContext(ctx).Debugf("[ghappsetup] startup phase %s (attempt %d)", phase, attempt)
+}
+
+// finish records the outcome of a load attempt. A successful attempt moves
+// to PhaseReady and resets the attempt counter.
+func (t *progressTracker) finish(err error) {
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = Progress{Phase: PhaseReady, Since: time.Now()}
+}
+
+func (t *progressTracker) snapshot() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
+
+// Progress returns the runtime's current startup progress.
+func (r *Runtime) Progress() Progress {
+	return r.progress.snapshot()
+}
+
+// History returns the runtime's load and reload attempt history, suitable
+// for configwait.StatusHandler.
+func (r *Runtime) History() *configwait.History {
+	return r.config.History
+}
+
+// ReportPhase records a named phase of configuration loading (e.g.
+// "resolve-ssm" or "build-clients") for the Runtime carried by ctx. It is
+// intended to be called from a LoadFunc, whose context always carries its
+// Runtime; pipeline stages report their names automatically. ReportPhase is
+// a no-op if ctx does not carry a Runtime.
+func ReportPhase(ctx context.Context, phase string) {
+	if r := FromContext(ctx); r != nil {
+		r.progress.setPhase(ctx, phase)
+	}
+}
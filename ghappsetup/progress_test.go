@@ -0,0 +1,146 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+)
+
+func TestRuntime_Progress(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var attempts atomic.Int32
+	inPhase := make(chan struct{})
+	release := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("not yet")
+			}
+			ReportPhase(ctx, "resolve-ssm")
+			close(inPhase)
+			<-release
+			return nil
+		},
+		MaxRetries:    3,
+		RetryInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if p := runtime.Progress(); p.Phase != PhaseStarting {
+		t.Errorf("Progress().Phase = %q, want %q", p.Phase, PhaseStarting)
+	}
+
+	errCh := runtime.StartAsync(context.Background())
+	<-inPhase
+
+	p := runtime.Progress()
+	if p.Phase != "resolve-ssm" || p.Attempt != 2 {
+		t.Errorf("Progress() = %+v, want phase resolve-ssm attempt 2", p)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz?format=json", nil)
+	runtime.HealthHandler()(rec, req)
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if report.Progress.Phase != "resolve-ssm" {
+		t.Errorf("report.Progress.Phase = %q, want %q", report.Progress.Phase, "resolve-ssm")
+	}
+
+	close(release)
+	if err := <-errCh; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if p := runtime.Progress(); p.Phase != PhaseReady || p.Attempt != 0 {
+		t.Errorf("Progress() = %+v, want phase ready", p)
+	}
+}
+
+func TestReportPhase_WithoutRuntime(t *testing.T) {
+	// Must not panic when the context carries no Runtime
+	ReportPhase(context.Background(), "resolve-ssm")
+}
+
+func TestRuntime_Progress_PipelineStages(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var phase string
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFuncs: []NamedLoadFunc{
+			{Name: "build-clients", Func: func(ctx context.Context) error {
+				phase = FromContext(ctx).Progress().Phase
+				return nil
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if phase != "build-clients" {
+		t.Errorf("phase during stage = %q, want %q", phase, "build-clients")
+	}
+}
+
+func TestRuntime_History(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var attempts atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("not yet")
+			}
+			return nil
+		},
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	records := runtime.History().Records()
+	if len(records) != 3 {
+		t.Fatalf("History() has %d records, want 3", len(records))
+	}
+	if records[0].Kind != configwait.AttemptLoad || records[0].Attempt != 1 || records[0].Error != "not yet" {
+		t.Errorf("records[0] = %+v, want failed load attempt 1", records[0])
+	}
+	if records[1].Kind != configwait.AttemptLoad || records[1].Attempt != 2 {
+		t.Errorf("records[1] = %+v, want load attempt 2", records[1])
+	}
+	if records[2].Kind != configwait.AttemptReload || records[2].Attempt != 0 {
+		t.Errorf("records[2] = %+v, want reload", records[2])
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// PanicsRecovered returns the number of handler panics recovered when
+// Config.RecoverPanics is enabled.
+func (r *Runtime) PanicsRecovered() uint64 {
+	return r.panics.Load()
+}
+
+// recoverer wraps next so that a panic in the handler is logged with its
+// stack trace and answered with 500 Internal Server Error instead of
+// tearing down the connection. http.ErrAbortHandler is re-panicked so that
+// deliberate aborts keep their net/http semantics.
+func (r *Runtime) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			r.panics.Add(1)
+			clog.FromContext(req.Context()).Errorf("[ghappsetup] recovered panic serving %s %s: %v\n%s",
+				req.Method, req.URL.Path, v, debug.Stack())
+			if r.config.OnPanic != nil {
+				r.config.OnPanic(req, v)
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRuntime_Handler_RecoverPanics(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var hookValue any
+	runtime, err := NewRuntime(Config{
+		Store:         &mockStore{},
+		LoadFunc:      func(ctx context.Context) error { return nil },
+		RecoverPanics: true,
+		OnPanic:       func(req *http.Request, v any) { hookValue = v },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if runtime.PanicsRecovered() != 1 {
+		t.Errorf("PanicsRecovered() = %d, want 1", runtime.PanicsRecovered())
+	}
+	if hookValue != "boom" {
+		t.Errorf("OnPanic value = %v, want %q", hookValue, "boom")
+	}
+}
+
+func TestRuntime_Handler_RecoverPanics_ErrAbortHandler(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:         &mockStore{},
+		LoadFunc:      func(ctx context.Context) error { return nil },
+		RecoverPanics: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recover() = %v, want http.ErrAbortHandler", v)
+		}
+		if runtime.PanicsRecovered() != 0 {
+			t.Errorf("PanicsRecovered() = %d, want 0", runtime.PanicsRecovered())
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
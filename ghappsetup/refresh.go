@@ -0,0 +1,36 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// EnvRefresher re-resolves environment variables derived from indirect
+// references and reports which variables changed. *ssmresolver.Resolver
+// implements it.
+type EnvRefresher interface {
+	ReResolveChanged(ctx context.Context) ([]string, error)
+}
+
+// refreshEnv runs the configured EnvRefresher, if any.
+func (r *Runtime) refreshEnv(ctx context.Context) error {
+	if r.config.EnvRefresher == nil {
+		return nil
+	}
+
+	log := clog.FromContext(ctx)
+	changed, err := r.config.EnvRefresher.ReResolveChanged(ctx)
+	if err != nil {
+		log.Errorf("[ghappsetup] failed to refresh environment: %v", err)
+		return fmt.Errorf("refresh environment: %w", err)
+	}
+	if len(changed) > 0 {
+		log.Infof("[ghappsetup] refreshed environment variables: %v", changed)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/ssmresolver"
+)
+
+var _ EnvRefresher = (*ssmresolver.Resolver)(nil)
+
+// mockRefresher records calls and returns err.
+type mockRefresher struct {
+	calls int
+	err   error
+}
+
+func (m *mockRefresher) ReResolveChanged(ctx context.Context) ([]string, error) {
+	m.calls++
+	return []string{"GITHUB_APP_PRIVATE_KEY"}, m.err
+}
+
+// countingRefresher counts calls and is safe for concurrent use.
+type countingRefresher struct {
+	calls atomic.Int32
+}
+
+func (c *countingRefresher) ReResolveChanged(ctx context.Context) ([]string, error) {
+	c.calls.Add(1)
+	return nil, nil
+}
+
+func TestRuntime_Reload_RefreshFailureIsRecorded(t *testing.T) {
+	refresher := &mockRefresher{}
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		EnvRefresher: refresher,
+		LoadFunc:     func(ctx context.Context) error { return nil },
+		MaxRetries:   1,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	refresher.err = errors.New("throttled")
+	if err := runtime.Reload(context.Background()); !errors.Is(err, refresher.err) {
+		t.Fatalf("Reload() error = %v, want %v", err, refresher.err)
+	}
+	if !runtime.IsDegraded() {
+		t.Error("IsDegraded() = false after a failed refresh, want true")
+	}
+	attempts := runtime.History().Records()
+	if len(attempts) != 2 || attempts[1].Error == "" {
+		t.Errorf("History() = %+v, want the failed refresh recorded", attempts)
+	}
+}
+
+func TestRuntime_Reload_RefreshesEnvironment(t *testing.T) {
+	refresher := &mockRefresher{}
+	var loads int
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		EnvRefresher: refresher,
+		LoadFunc: func(ctx context.Context) error {
+			loads++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if refresher.calls != 1 || loads != 1 {
+		t.Errorf("refresh calls = %d, loads = %d, want 1 and 1", refresher.calls, loads)
+	}
+
+	refresher.err = errors.New("throttled")
+	if err := runtime.Reload(context.Background()); !errors.Is(err, refresher.err) {
+		t.Errorf("Reload() error = %v, want %v", err, refresher.err)
+	}
+	if loads != 1 {
+		t.Errorf("LoadFunc called %d times, want 1 after failed refresh", loads)
+	}
+}
+
+// blockingRefresher blocks in ReResolveChanged until release is closed and
+// records the peak number of concurrent calls.
+type blockingRefresher struct {
+	started chan struct{}
+	release chan struct{}
+	active  atomic.Int32
+	peak    atomic.Int32
+}
+
+func (b *blockingRefresher) ReResolveChanged(ctx context.Context) ([]string, error) {
+	if n := b.active.Add(1); n > b.peak.Load() {
+		b.peak.Store(n)
+	}
+	defer b.active.Add(-1)
+	select {
+	case b.started <- struct{}{}:
+	default:
+	}
+	<-b.release
+	return nil, nil
+}
+
+func TestRuntime_Reload_CoalescesRefresh(t *testing.T) {
+	refresher := &blockingRefresher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		EnvRefresher: refresher,
+		LoadFunc:     func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() { _ = runtime.Reload(context.Background()) })
+	<-refresher.started
+	for range 3 {
+		wg.Go(func() { _ = runtime.Reload(context.Background()) })
+	}
+	close(refresher.release)
+	wg.Wait()
+
+	if peak := refresher.peak.Load(); peak != 1 {
+		t.Errorf("concurrent refreshes = %d, want 1", peak)
+	}
+}
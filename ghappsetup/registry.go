@@ -0,0 +1,300 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Registry manages multiple named Runtimes in one process, e.g. one per
+// GitHub App or tenant. It provides aggregate readiness, a combined health
+// handler, and per-runtime reload triggers.
+type Registry struct {
+	mu       sync.RWMutex
+	names    []string
+	runtimes map[string]*Runtime
+
+	listenMu  sync.Mutex
+	listening map[*ReloadListener]struct{}
+	listeners sync.WaitGroup
+}
+
+// RegistryHealthReport is the combined health of every Runtime in a
+// Registry, keyed by runtime name.
+type RegistryHealthReport struct {
+	Status   string                  `json:"status"`
+	Runtimes map[string]HealthReport `json:"runtimes"`
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{runtimes: make(map[string]*Runtime)}
+}
+
+// Register adds a Runtime under the given name. Names must be non-empty and
+// unique within the Registry.
+func (g *Registry) Register(name string, r *Runtime) error {
+	if name == "" {
+		return errors.New("ghappsetup: registry name is required")
+	}
+	if r == nil {
+		return fmt.Errorf("ghappsetup: runtime %q is nil", name)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.runtimes[name]; ok {
+		return fmt.Errorf("ghappsetup: runtime %q already registered", name)
+	}
+	g.runtimes[name] = r
+	g.names = append(g.names, name)
+	return nil
+}
+
+// Add creates a Runtime from cfg and registers it under name.
+func (g *Registry) Add(name string, cfg Config) (*Runtime, error) {
+	r, err := NewRuntime(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Register(name, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get returns the Runtime registered under name.
+func (g *Registry) Get(name string) (*Runtime, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	r, ok := g.runtimes[name]
+	return r, ok
+}
+
+// Names returns the registered runtime names in registration order.
+func (g *Registry) Names() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]string, len(g.names))
+	copy(out, g.names)
+	return out
+}
+
+// IsReady returns true if every registered Runtime is ready. An empty
+// Registry is not ready.
+func (g *Registry) IsReady() bool {
+	entries := g.entries()
+	if len(entries) == 0 {
+		return false
+	}
+	for _, e := range entries {
+		if !e.runtime.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitReady blocks until every registered Runtime is ready or the context
+// is canceled.
+func (g *Registry) WaitReady(ctx context.Context) error {
+	for _, e := range g.entries() {
+		if err := e.runtime.WaitReady(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start starts every registered Runtime concurrently and blocks until all
+// have loaded or failed. Errors are joined and name the failed runtime.
+func (g *Registry) Start(ctx context.Context) error {
+	entries := g.entries()
+	errs := make([]error, len(entries))
+
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.runtime.Start(ctx); err != nil {
+				errs[i] = fmt.Errorf("runtime %s: %w", e.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Reload reloads the Runtime registered under name.
+func (g *Registry) Reload(ctx context.Context, name string) error {
+	r, ok := g.Get(name)
+	if !ok {
+		return fmt.Errorf("ghappsetup: runtime %q not registered", name)
+	}
+	return r.Reload(ctx)
+}
+
+// ReloadCallback returns the reload trigger for the Runtime registered
+// under name, suitable for that tenant's installer.Config.OnReloadNeeded.
+// It returns nil if no such Runtime is registered.
+func (g *Registry) ReloadCallback(name string) func() {
+	r, ok := g.Get(name)
+	if !ok {
+		return nil
+	}
+	return r.ReloadCallback()
+}
+
+// ListenForReloads starts reload listeners on every registered Runtime as
+// StartReloadListener does. The returned channel is closed once all
+// listeners have stopped after the context is canceled.
+func (g *Registry) ListenForReloads(ctx context.Context) <-chan struct{} {
+	return g.StartReloadListener(ctx).Done()
+}
+
+// StartReloadListener calls StartReloadListener on every registered Runtime.
+// A SIGHUP therefore reloads every runtime, while reload callbacks only
+// reload their own runtime. Stopping the returned listener, canceling ctx,
+// or calling Shutdown stops the listeners started by this call; runtimes
+// that were already listening keep their own listener.
+func (g *Registry) StartReloadListener(ctx context.Context) *ReloadListener {
+	ctx, cancel := context.WithCancel(ctx)
+	l := &ReloadListener{cancel: cancel, done: make(chan struct{})}
+
+	g.listenMu.Lock()
+	if g.listening == nil {
+		g.listening = make(map[*ReloadListener]struct{})
+	}
+	g.listening[l] = struct{}{}
+	g.listeners.Add(1)
+	g.listenMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range g.entries() {
+		wg.Add(1)
+		listener, started := e.runtime.startReloadListener(ctx)
+		go func() {
+			defer wg.Done()
+			if !started {
+				<-ctx.Done()
+				return
+			}
+			// Canceling ctx stops the listener, so wait for its goroutines
+			// rather than returning as soon as ctx is done
+			<-listener.Done()
+		}()
+	}
+	go func() {
+		defer g.listeners.Done()
+		wg.Wait()
+		cancel()
+		g.listenMu.Lock()
+		delete(g.listening, l)
+		g.listenMu.Unlock()
+		close(l.done)
+	}()
+
+	return l
+}
+
+// Shutdown stops the reload listeners started through the Registry and
+// waits for their goroutines to exit. It returns ctx.Err() if ctx is done
+// first.
+func (g *Registry) Shutdown(ctx context.Context) error {
+	g.listenMu.Lock()
+	for l := range g.listening {
+		l.cancel()
+	}
+	g.listenMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.listeners.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Health returns the health of every registered Runtime. The combined
+// status is not ready if any runtime is not ready (or none are registered),
+// degraded if any runtime is degraded, and ok otherwise.
+func (g *Registry) Health(ctx context.Context) RegistryHealthReport {
+	entries := g.entries()
+	report := RegistryHealthReport{
+		Status:   HealthStatusOK,
+		Runtimes: make(map[string]HealthReport, len(entries)),
+	}
+	if len(entries) == 0 {
+		report.Status = HealthStatusNotReady
+	}
+
+	for _, e := range entries {
+		h := e.runtime.Health(ctx)
+		report.Runtimes[e.name] = h
+		switch {
+		case h.Status == HealthStatusNotReady:
+			report.Status = HealthStatusNotReady
+		case h.Status == HealthStatusDegraded && report.Status == HealthStatusOK:
+			report.Status = HealthStatusDegraded
+		}
+	}
+	return report
+}
+
+// HealthHandler returns an HTTP handler reporting the combined health of
+// every registered Runtime. It returns 200 when all runtimes are ready and
+// 503 otherwise, naming the runtimes that are not ready in the plain-text
+// body. JSON is returned when requested, as with Runtime.HealthHandler.
+func (g *Registry) HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		report := g.Health(req.Context())
+		writeHealth(w, req, report.Status, report, g.healthText(report))
+	}
+}
+
+// healthText renders the plain-text health body for a registry report.
+func (g *Registry) healthText(report RegistryHealthReport) string {
+	if report.Status != HealthStatusNotReady {
+		return report.Status
+	}
+
+	var notReady []string
+	for _, name := range g.Names() {
+		if h, ok := report.Runtimes[name]; ok && h.Status == HealthStatusNotReady {
+			notReady = append(notReady, name)
+		}
+	}
+	if len(notReady) == 0 {
+		return HealthStatusNotReady
+	}
+	return "not ready: " + strings.Join(notReady, ", ")
+}
+
+// registryEntry pairs a Runtime with its registered name.
+type registryEntry struct {
+	name    string
+	runtime *Runtime
+}
+
+// entries returns the registered runtimes in registration order.
+func (g *Registry) entries() []registryEntry {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]registryEntry, len(g.names))
+	for i, name := range g.names {
+		out[i] = registryEntry{name: name, runtime: g.runtimes[name]}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+
+	if _, err := registry.Add("", Config{Store: &mockStore{}, LoadFunc: func(ctx context.Context) error { return nil }}); err == nil {
+		t.Error("Add() should return error for empty name")
+	}
+
+	tenantA, err := registry.Add("tenant-a", Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := registry.Register("tenant-a", tenantA); err == nil {
+		t.Error("Register() should return error for duplicate name")
+	}
+
+	if got, ok := registry.Get("tenant-a"); !ok || got != tenantA {
+		t.Errorf("Get() = %v, %v, want tenant-a runtime", got, ok)
+	}
+	if _, ok := registry.Get("missing"); ok {
+		t.Error("Get() should return false for unknown name")
+	}
+	if names := registry.Names(); len(names) != 1 || names[0] != "tenant-a" {
+		t.Errorf("Names() = %v, want [tenant-a]", names)
+	}
+}
+
+func TestRegistry_StartAndAggregateReadiness(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	registry := NewRegistry()
+	if registry.IsReady() {
+		t.Error("IsReady() should be false for an empty registry")
+	}
+
+	expectedErr := errors.New("missing key")
+	for name, loadErr := range map[string]error{"tenant-a": nil, "tenant-b": expectedErr} {
+		_, err := registry.Add(name, Config{
+			Store:         &mockStore{},
+			LoadFunc:      func(ctx context.Context) error { return loadErr },
+			MaxRetries:    1,
+			RetryInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+	}
+
+	err := registry.Start(context.Background())
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Start() error = %v, want %v", err, expectedErr)
+	}
+	if !strings.Contains(err.Error(), "tenant-b") {
+		t.Errorf("Start() error = %q, should name the failed runtime", err.Error())
+	}
+	if registry.IsReady() {
+		t.Error("IsReady() should be false while a runtime is not ready")
+	}
+
+	rec := httptest.NewRecorder()
+	registry.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if want := "not ready: tenant-b"; rec.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rec.Body.String(), want)
+	}
+
+	tenantB, _ := registry.Get("tenant-b")
+	tenantB.setReady(true)
+
+	if !registry.IsReady() {
+		t.Error("IsReady() should be true once all runtimes are ready")
+	}
+
+	rec = httptest.NewRecorder()
+	registry.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz?format=json", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var report RegistryHealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if report.Status != HealthStatusOK || len(report.Runtimes) != 2 {
+		t.Errorf("report = %+v, want ok with 2 runtimes", report)
+	}
+}
+
+func TestRegistry_PerTenantReload(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	registry := NewRegistry()
+	var countA, countB atomic.Int32
+	for name, count := range map[string]*atomic.Int32{"tenant-a": &countA, "tenant-b": &countB} {
+		_, err := registry.Add(name, Config{
+			Store: &mockStore{},
+			LoadFunc: func(ctx context.Context) error {
+				count.Add(1)
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+	}
+
+	if err := registry.Reload(context.Background(), "tenant-a"); err != nil {
+		t.Errorf("Reload() error = %v", err)
+	}
+	if err := registry.Reload(context.Background(), "missing"); err == nil {
+		t.Error("Reload() should return error for unknown name")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := registry.ListenForReloads(ctx)
+
+	registry.ReloadCallback("tenant-b")()
+	time.Sleep(50 * time.Millisecond)
+
+	if countA.Load() != 1 {
+		t.Errorf("tenant-a reload count = %d, want 1", countA.Load())
+	}
+	if countB.Load() != 1 {
+		t.Errorf("tenant-b reload count = %d, want 1", countB.Load())
+	}
+	if registry.ReloadCallback("missing") != nil {
+		t.Error("ReloadCallback() should return nil for unknown name")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("ListenForReloads did not stop after context cancellation")
+	}
+}
+
+func TestRegistry_ShutdownWaitsForListeners(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	registry := NewRegistry()
+	var runtimes []*Runtime
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		r, err := registry.Add(name, Config{
+			Store:    &mockStore{},
+			LoadFunc: func(ctx context.Context) error { return nil },
+		})
+		if err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+		runtimes = append(runtimes, r)
+	}
+
+	listener := registry.StartReloadListener(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := registry.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	select {
+	case <-listener.Done():
+	default:
+		t.Error("listener should be done once Shutdown() returns")
+	}
+	// The runtime listeners have exited, so new ones can be started
+	for _, r := range runtimes {
+		if _, started := r.startReloadListener(context.Background()); !started {
+			t.Error("runtime listener should have stopped during Shutdown()")
+		}
+		r.StartReloadListener(context.Background()).Stop()
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// ReloadHandler returns a handler that reloads configuration on POST and
+// responds once the reload has finished: 200 with body "reloaded", or 500
+// with body "reload failed" (the error is logged, not returned). It is the
+// reload trigger for platforms that cannot signal the process, such as
+// Azure Functions, where it is typically mapped to an HTTP trigger, and
+// for deployment tooling that changes stored credentials.
+//
+// Requests must carry Config.ReloadToken as a bearer token and are
+// otherwise rejected with 401. Without a token, every request is rejected
+// with 403 unless Config.AllowUnauthenticatedReload is set. Reloads run
+// through Reload, so Config.ReloadCooldown does not apply; concurrent
+// requests share one reload.
+func (r *Runtime) ReloadHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.config.ReloadToken == "" && !r.config.AllowUnauthenticatedReload {
+			clog.FromContext(req.Context()).Errorf("[ghappsetup] reload requested over HTTP but no reload token is configured")
+			http.Error(w, "reload endpoint disabled", http.StatusForbidden)
+			return
+		}
+		if !r.reloadAuthorized(req) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := NewContext(req.Context(), r)
+		if err := r.Reload(ctx); err != nil {
+			clog.FromContext(ctx).Errorf("[ghappsetup] reload requested over HTTP failed: %v", err)
+			http.Error(w, "reload failed", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("reloaded"))
+	}
+}
+
+// reloadAuthorized reports whether req carries Config.ReloadToken, or true
+// if no token is configured and unauthenticated reloads are allowed.
+func (r *Runtime) reloadAuthorized(req *http.Request) bool {
+	if r.config.ReloadToken == "" {
+		return r.config.AllowUnauthenticatedReload
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.config.ReloadToken)) == 1
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRuntime_ReloadHandler(t *testing.T) {
+	var loads atomic.Int32
+	var fail atomic.Bool
+	runtime, err := NewRuntime(Config{
+		Store:       &mockStore{},
+		ReloadToken: "s3cret",
+		LoadFunc: func(ctx context.Context) error {
+			loads.Add(1)
+			if fail.Load() {
+				return errors.New("store unavailable")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	handler := runtime.ReloadHandler()
+
+	tests := []struct {
+		name      string
+		method    string
+		auth      string
+		fail      bool
+		wantCode  int
+		wantLoads int32
+	}{
+		{name: "wrong method", method: http.MethodGet, auth: "Bearer s3cret", wantCode: http.StatusMethodNotAllowed},
+		{name: "missing token", method: http.MethodPost, wantCode: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, auth: "Bearer nope", wantCode: http.StatusUnauthorized},
+		{name: "reloads", method: http.MethodPost, auth: "Bearer s3cret", wantCode: http.StatusOK, wantLoads: 1},
+		{name: "reload fails", method: http.MethodPost, auth: "Bearer s3cret", fail: true, wantCode: http.StatusInternalServerError, wantLoads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loads.Store(0)
+			fail.Store(tt.fail)
+
+			req := httptest.NewRequest(tt.method, "/reload", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := loads.Load(); got != tt.wantLoads {
+				t.Errorf("LoadFunc called %d times, want %d", got, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestRuntime_ReloadHandler_NoToken(t *testing.T) {
+	t.Setenv(EnvReloadToken, "")
+	t.Setenv(EnvReloadNoAuth, "")
+
+	tests := []struct {
+		name      string
+		allow     bool
+		wantCode  int
+		wantLoads int32
+	}{
+		{"rejected by default", false, http.StatusForbidden, 0},
+		{"explicitly allowed", true, http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loads atomic.Int32
+			runtime, err := NewRuntime(Config{
+				Store: &mockStore{},
+				LoadFunc: func(ctx context.Context) error {
+					loads.Add(1)
+					return nil
+				},
+				AllowUnauthenticatedReload: tt.allow,
+			})
+			if err != nil {
+				t.Fatalf("NewRuntime() error = %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			runtime.ReloadHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reload", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if loads.Load() != tt.wantLoads {
+				t.Errorf("LoadFunc called %d times, want %d", loads.Load(), tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestNewRuntime_ReloadTokenEnv(t *testing.T) {
+	t.Setenv(EnvReloadToken, " from-env ")
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	if runtime.config.ReloadToken != "from-env" {
+		t.Errorf("ReloadToken = %q, want %q", runtime.config.ReloadToken, "from-env")
+	}
+
+	t.Setenv(EnvReloadNoAuth, "true")
+	runtime, err = NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	if !runtime.config.AllowUnauthenticatedReload {
+		t.Error("AllowUnauthenticatedReload = false, want true from environment")
+	}
+}
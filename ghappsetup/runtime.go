@@ -7,18 +7,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configmap"
 	"github.com/cruxstack/github-app-setup-go/configstore"
 	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
+	"github.com/cruxstack/github-app-setup-go/metrics"
 )
 
 const (
 	// Environment variable used to detect Lambda runtime.
 	envLambdaFunctionName = "AWS_LAMBDA_FUNCTION_NAME"
 
+	// Environment variable set by Lambda to describe how the execution
+	// environment was initialized.
+	envLambdaInitializationType = "AWS_LAMBDA_INITIALIZATION_TYPE"
+
+	// Initialization type reported for provisioned concurrency environments.
+	lambdaInitProvisionedConcurrency = "provisioned-concurrency"
+
 	// Default retry settings for HTTP servers.
 	defaultHTTPMaxRetries    = 30
 	defaultHTTPRetryInterval = 2 * time.Second
@@ -38,6 +53,18 @@ const (
 	EnvironmentLambda
 )
 
+// String returns a short lowercase name for the environment.
+func (e Environment) String() string {
+	switch e {
+	case EnvironmentHTTP:
+		return "http"
+	case EnvironmentLambda:
+		return "lambda"
+	default:
+		return "unknown"
+	}
+}
+
 // LoadFunc is the function called to load application configuration.
 // It should return an error if configuration is not yet available,
 // which will trigger a retry according to the configured retry policy.
@@ -49,67 +76,344 @@ type Config struct {
 	// automatically using configstore.NewFromEnv().
 	Store configstore.Store
 
-	// LoadFunc is called to load application configuration. This is required.
+	// LoadFunc is called to load application configuration. Either LoadFunc
+	// or LoadFuncs is required.
 	// The function should read configuration from environment variables or
 	// other sources and initialize application state. It will be called
 	// during startup and on reload triggers.
 	LoadFunc LoadFunc
 
+	// LoadFuncs defines a multi-stage load pipeline as an alternative to
+	// LoadFunc. Stages run in order, each with its own retry policy and
+	// timeout, and their progress is reported by Stages and HealthHandler.
+	// The whole pipeline is re-run on retries and reloads.
+	LoadFuncs []NamedLoadFunc
+
+	// AfterLoad, if set, is called after every successful load and reload
+	// with the Runtime in its context (see FromContext), for best-effort
+	// follow-up work such as appinfo.BackfillOnLoad. It runs once the new
+	// configuration is active and cannot fail the load, so it should log
+	// its own errors.
+	AfterLoad func(ctx context.Context)
+
 	// AllowedPaths specifies HTTP paths that should be served even before
 	// configuration is loaded. This is typically used for health checks and
-	// installer endpoints. Only applicable in HTTP environments.
+	// installer endpoints. Entries may be restricted to specific HTTP
+	// methods using a "METHOD /path" prefix (e.g. "GET /"), so read-only
+	// pages stay reachable while mutating routes remain gated. Only
+	// applicable in HTTP environments.
 	AllowedPaths []string
 
+	// GatedPaths is the inverse of AllowedPaths: when set, only requests to
+	// these path prefixes wait for readiness and all other paths are served
+	// immediately. AllowedPaths entries are still always served. Only
+	// applicable in HTTP environments.
+	GatedPaths []string
+
+	// NotReadyResponse builds the response for requests rejected while not
+	// ready. If nil, configwait.DefaultUnavailableResponse is used, which
+	// serves an HTML page to browsers and JSON otherwise. Applies in HTTP
+	// environments and to the Lambda event wrappers (e.g. WrapALB).
+	NotReadyResponse configwait.UnavailableFunc
+
+	// NotReadyHandlers maps paths to handlers that serve matching requests
+	// while not ready, instead of NotReadyResponse (e.g. a static status
+	// page at "/"). Keys accept the same method prefixes as AllowedPaths.
+	// Only applicable in HTTP environments.
+	NotReadyHandlers map[string]http.Handler
+
+	// HoldRequests is the maximum number of gated requests parked while not
+	// ready, each for up to HoldTimeout, and released to the handler once
+	// ready instead of being rejected (see configwait.WithHoldRequests).
+	// Holding is disabled unless both are set. Only applicable in HTTP
+	// environments.
+	HoldRequests int
+
+	// HoldTimeout is how long a parked request waits for readiness before
+	// receiving the not-ready response.
+	HoldTimeout time.Duration
+
+	// RetryAfter sets the Retry-After header of not-ready responses. If
+	// zero, configwait.DefaultRetryAfter is used unless DynamicRetryAfter
+	// is set. Applies in HTTP environments and to the Lambda event
+	// wrappers.
+	RetryAfter time.Duration
+
+	// DynamicRetryAfter computes the Retry-After header from the expected
+	// remaining startup wait (the delays left before MaxRetries is
+	// exhausted, see configwait.Config.RemainingWait) instead of using a
+	// fixed value. Applies in HTTP environments and to the Lambda event
+	// wrappers.
+	DynamicRetryAfter bool
+
 	// MaxRetries is the maximum number of times to retry loading configuration.
-	// If zero, defaults are used based on detected environment:
-	// HTTP: 30 retries, Lambda: 5 retries.
+	// If zero, defaults are used based on the detected platform:
+	// Lambda and Cloud Functions: 5 retries, Azure Functions: 10 retries,
+	// Cloud Run: 15 retries, ECS: 60 retries, all others: 30 retries.
 	MaxRetries int
 
 	// RetryInterval is the time to wait between retry attempts.
-	// If zero, defaults are used based on detected environment:
-	// HTTP: 2 seconds, Lambda: 1 second.
+	// If zero, defaults are used based on the detected platform:
+	// Lambda and Cloud Functions: 1 second, all others: 2 seconds.
 	RetryInterval time.Duration
+
+	// Multiplier scales RetryInterval after each failed attempt for
+	// exponential backoff (see configwait.Config.Multiplier). If zero, the
+	// interval is fixed.
+	Multiplier float64
+
+	// MaxInterval caps the delay between attempts when Multiplier is set.
+	// If zero, the delay is not capped.
+	MaxInterval time.Duration
+
+	// Jitter randomizes each delay by up to this fraction in either
+	// direction (e.g. 0.2 for ±20%). If zero, delays are not randomized.
+	Jitter float64
+
+	// MaxWait bounds the total startup wait made by Start or EnsureLoaded,
+	// independently of MaxRetries; loading gives up at whichever limit is
+	// reached first. If zero, only MaxRetries applies.
+	MaxWait time.Duration
+
+	// History records every load and reload attempt for
+	// configwait.StatusHandler. If nil, a History of
+	// configwait.DefaultHistorySize is created; see Runtime.History.
+	History *configwait.History
+
+	// OnAttempt, if set, is called after every startup load attempt made by
+	// Start or EnsureLoaded with the attempt number (starting at 1) and its
+	// error, nil on success. Reloads and refreshes are not reported.
+	OnAttempt func(attempt int, err error)
+
+	// RefreshInterval enables opportunistic background refreshes in Lambda
+	// environments. When set, an EnsureLoaded call made more than
+	// RefreshInterval after the last successful load re-runs LoadFunc in a
+	// background goroutine so rotated credentials are picked up without a
+	// redeploy. If zero, configuration is loaded once per execution
+	// environment. On platforms that throttle CPU between requests (see
+	// Runtime.CPUThrottled), refreshes are driven by requests to Handler
+	// in the same way. Not applicable to other HTTP environments.
+	RefreshInterval time.Duration
+
+	// PreloadOnInit loads configuration inside NewRuntime instead of on the
+	// first EnsureLoaded call, moving load latency out of the request path.
+	// Preloading is enabled automatically when Lambda reports the
+	// provisioned-concurrency initialization type. A failed preload is
+	// logged and retried by the next EnsureLoaded call. Only applicable in
+	// Lambda environments.
+	PreloadOnInit bool
+
+	// RetainLastKnownGood restores the environment variables written by a
+	// failed reload to their values after the last successful load, so that
+	// a LoadFunc failing partway does not leave half-applied values in
+	// place. Variables the reload did not write are left alone.
+	// Regardless of this setting, a failed reload after a successful load
+	// marks the runtime as degraded (see IsDegraded).
+	RetainLastKnownGood bool
+
+	// ShedOnReloadFailure closes the ReadyGate and reports not ready when a
+	// reload fails after a successful load, instead of continuing to serve
+	// with the previous configuration. The gate reopens on the next
+	// successful load.
+	ShedOnReloadFailure bool
+
+	// ReloadCooldown is the minimum interval between reloads triggered by
+	// SIGHUP or ReloadCallback. Triggers that arrive during the cooldown are
+	// collapsed into a single reload that runs once the cooldown elapses,
+	// protecting remote stores from reload storms. If zero, every trigger
+	// reloads immediately. Direct Reload calls are not rate limited.
+	ReloadCooldown time.Duration
+
+	// ReloadSignals, if set, replaces the process signals handled by the
+	// reload listener: each value received on the channel triggers a reload
+	// as SIGHUP would, and no signal handler is registered. Tests use it to
+	// drive signal-triggered reloads without signaling the process.
+	ReloadSignals <-chan os.Signal
+
+	// RecoverPanics makes Handler recover panics raised by the inner
+	// handler. A recovered panic is logged with its stack trace, counted
+	// (see PanicsRecovered), reported to OnPanic, and answered with 500
+	// Internal Server Error, keeping the server up during handler bugs.
+	RecoverPanics bool
+
+	// OnPanic is called with the request and recovered value for every
+	// panic recovered by Handler, e.g. to increment a metric. Only used
+	// when RecoverPanics is enabled.
+	OnPanic func(req *http.Request, v any)
+
+	// EnvRefresher, if set, re-resolves indirect environment values (such as
+	// SSM references) before LoadFunc runs on every Reload and Lambda
+	// background refresh (see RefreshInterval), so they pick up rotated
+	// secrets instead of re-reading stale resolved values. It is typically
+	// the *ssmresolver.Resolver that resolved the environment at startup. A
+	// failed refresh fails the reload without calling LoadFunc and is
+	// reported like a failed LoadFunc.
+	EnvRefresher EnvRefresher
+
+	// ReloadToken is the bearer token ReloadHandler requires in the
+	// Authorization header. If empty, ReloadHandler rejects every request
+	// unless AllowUnauthenticatedReload is set.
+	ReloadToken string
+
+	// AllowUnauthenticatedReload lets ReloadHandler accept every POST when
+	// no ReloadToken is set. Only set it when the handler is mounted behind
+	// other authentication, such as an Azure Functions HTTP trigger with the
+	// function authorization level, since each request reads the store and
+	// bypasses ReloadCooldown.
+	AllowUnauthenticatedReload bool
+
+	// CPUAlwaysAllocated declares that CPU stays allocated between
+	// requests on Cloud Run or Cloud Functions (instance-based billing),
+	// which cannot be detected from the environment. When unset on those
+	// platforms, the Runtime assumes CPU is throttled outside requests and
+	// does its background work inside requests instead (see
+	// Runtime.CPUThrottled).
+	CPUAlwaysAllocated bool
+
+	// SettingsFile is a YAML or JSON file of non-secret settings, typically
+	// mounted from a Kubernetes ConfigMap (see the configmap package). Its
+	// max_retries, retry_interval, max_wait, and reload_cooldown fill the
+	// corresponding fields left unset by Config and the environment, and
+	// its allowed_paths and gated_paths are added to AllowedPaths and
+	// GatedPaths. Its manifest is offered by InstallerHandler unless
+	// installer.Config.ManifestFunc is set. ListenForReloads watches the
+	// file and applies changes to the paths and manifest without a
+	// restart; the retry and cooldown settings are only read by NewRuntime.
+	SettingsFile string
+
+	// DrainPeriod is how long BeginShutdown reports the runtime as not
+	// ready before signaling that shutdown can proceed, giving load
+	// balancers time to stop routing new requests. If zero, shutdown
+	// proceeds immediately, except on ECS, where it defaults to 5 seconds.
+	DrainPeriod time.Duration
+
+	// Metrics receives load durations, load failures, reload counts, and
+	// gated requests, and is used by ghauth token sources bound to the
+	// Runtime for token cache hits (see the metrics package for names). If
+	// nil and GHAPPSETUP_EMF_NAMESPACE is set, a metrics.EMF sink writing
+	// to stdout in that namespace is used.
+	Metrics metrics.Sink
+
+	// Events receives lifecycle.CredentialsRotated when a reload loads
+	// different credentials and lifecycle.ReloadFailed when a reload fails,
+	// and is passed to the installer by InstallerHandler for
+	// lifecycle.AppRegistered. If nil and GHAPPSETUP_EVENT_BUS is set, a
+	// lifecycle.EventBridgePublisher for that bus is used.
+	Events lifecycle.Publisher
 }
 
 // Runtime coordinates GitHub App configuration loading, readiness gating,
 // and hot reloading. It provides a unified interface for both HTTP servers
 // and Lambda functions.
 type Runtime struct {
-	config Config
-	store  configstore.Store
-	gate   *configwait.ReadyGate
-	env    Environment
+	config   Config
+	store    configstore.Store
+	gate     *configwait.ReadyGate
+	env      Environment
+	platform Platform
+	pipeline *pipeline
 
 	mu       sync.RWMutex
 	ready    bool
+	readyCh  chan struct{}
 	reloadCh chan struct{}
+
+	loadMu   sync.Mutex
+	inflight *loadCall
+	degraded bool
+	lastGood envSnapshot
+	credsSum string
+
+	checksMu sync.Mutex
+	checks   []namedCheck
+
+	panics     atomic.Uint64
+	generation atomic.Uint64
+
+	progress *progressTracker
+
+	listenerMu sync.Mutex
+	listener   *ReloadListener
+
+	drainOnce sync.Once
+	draining  atomic.Bool
+	drained   chan struct{}
+
+	maintenance atomic.Bool
+	shedding    atomic.Bool
+
+	ecsMu   sync.Mutex
+	ecsTask *ECSTask
+
+	settings     *configmap.Watcher
+	pathsMu      sync.Mutex
+	mountedPaths []string
+
+	sharedMu sync.Mutex
+	shared   map[any]any
+}
+
+// loadCall tracks an in-progress LoadFunc invocation shared by concurrent
+// callers.
+type loadCall struct {
+	done chan struct{}
+	err  error
 }
 
 // NewRuntime creates a new Runtime with the given configuration.
-// It auto-detects the runtime environment (HTTP vs Lambda) and applies
-// appropriate defaults for retry behavior.
+// It auto-detects the hosting platform and runtime environment (HTTP vs
+// Lambda) and applies appropriate defaults for retry behavior. Zero-valued
+// Config fields are first read from GHAPPSETUP_* environment variables
+// (see EnvMaxRetries and related constants), then from Config.SettingsFile.
 func NewRuntime(cfg Config) (*Runtime, error) {
-	if cfg.LoadFunc == nil {
-		return nil, errors.New("ghappsetup: LoadFunc is required")
+	if cfg.LoadFunc == nil && len(cfg.LoadFuncs) == 0 {
+		return nil, errors.New("ghappsetup: LoadFunc or LoadFuncs is required")
+	}
+	if cfg.LoadFunc != nil && len(cfg.LoadFuncs) > 0 {
+		return nil, errors.New("ghappsetup: LoadFunc and LoadFuncs are mutually exclusive")
 	}
 
-	// Auto-detect environment
-	env := detectEnvironment()
+	var p *pipeline
+	if len(cfg.LoadFuncs) > 0 {
+		var err error
+		p, err = newPipeline(cfg.LoadFuncs)
+		if err != nil {
+			return nil, err
+		}
+		cfg.LoadFunc = p.run
+	}
 
-	// Apply defaults based on environment
-	if cfg.MaxRetries == 0 {
-		if env == EnvironmentLambda {
-			cfg.MaxRetries = defaultLambdaMaxRetries
-		} else {
-			cfg.MaxRetries = defaultHTTPMaxRetries
+	// Auto-detect platform and environment
+	platform := detectPlatform()
+	env := platform.Environment()
+
+	// Apply environment variable overrides and the settings file, then
+	// defaults based on platform
+	applyEnvDefaults(&cfg)
+	var r *Runtime
+	var settings *configmap.Watcher
+	if cfg.SettingsFile != "" {
+		var err error
+		settings, err = configmap.NewWatcher(cfg.SettingsFile, configmap.WithOnChange(func(s *configmap.Settings) {
+			r.applySettings(s)
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("ghappsetup: failed to load settings file: %w", err)
 		}
+		applySettingsDefaults(&cfg, settings.Settings())
+	}
+	defaultMaxRetries, defaultRetryInterval := platform.retryDefaults()
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
 	}
 	if cfg.RetryInterval == 0 {
-		if env == EnvironmentLambda {
-			cfg.RetryInterval = defaultLambdaRetryInterval
-		} else {
-			cfg.RetryInterval = defaultHTTPRetryInterval
-		}
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	if cfg.DrainPeriod == 0 {
+		cfg.DrainPeriod = platform.drainDefault()
+	}
+	if cfg.History == nil {
+		cfg.History = configwait.NewHistory(0)
 	}
 
 	// Create store if not provided
@@ -122,19 +426,60 @@ func NewRuntime(cfg Config) (*Runtime, error) {
 		}
 	}
 
-	// Create ready gate for HTTP environments
-	var gate *configwait.ReadyGate
-	if env == EnvironmentHTTP {
-		gate = configwait.NewReadyGate(nil, cfg.AllowedPaths)
+	if cfg.Events == nil {
+		if bus := strings.TrimSpace(os.Getenv(EnvEventBus)); bus != "" {
+			events, err := lifecycle.NewEventBridgePublisher(bus)
+			if err != nil {
+				return nil, fmt.Errorf("ghappsetup: failed to create event publisher: %w", err)
+			}
+			cfg.Events = events
+		}
 	}
 
-	return &Runtime{
+	r = &Runtime{
 		config:   cfg,
 		store:    store,
-		gate:     gate,
 		env:      env,
+		platform: platform,
+		pipeline: p,
+		readyCh:  make(chan struct{}),
 		reloadCh: make(chan struct{}, 1),
-	}, nil
+		progress: newProgressTracker(),
+		drained:  make(chan struct{}),
+		settings: settings,
+	}
+	if env == EnvironmentHTTP {
+		r.gate = r.newReadyGate()
+	}
+
+	if env == EnvironmentLambda && (cfg.PreloadOnInit || isProvisionedConcurrency()) {
+		r.preload(context.Background())
+	}
+
+	return r, nil
+}
+
+// Metrics returns the sink set with Config.Metrics, or nil.
+func (r *Runtime) Metrics() metrics.Sink {
+	return r.config.Metrics
+}
+
+// recordMetric records a value if a metrics sink is configured.
+func (r *Runtime) recordMetric(name string, value float64, unit metrics.Unit) {
+	if r.config.Metrics != nil {
+		r.config.Metrics.Record(name, value, unit)
+	}
+}
+
+// recordLoadMetrics records the duration and outcome of a load or reload.
+func (r *Runtime) recordLoadMetrics(kind string, d time.Duration, err error) {
+	r.recordMetric(metrics.LoadDuration, float64(d)/float64(time.Millisecond), metrics.UnitMilliseconds)
+	if kind == configwait.AttemptReload {
+		r.recordMetric(metrics.Reloads, 1, metrics.UnitCount)
+	}
+	if err != nil {
+		r.recordMetric(metrics.LoadFailures, 1, metrics.UnitCount)
+	}
 }
 
 // Store returns the credential storage backend used by this Runtime.
@@ -148,6 +493,20 @@ func (r *Runtime) Environment() Environment {
 	return r.env
 }
 
+// Platform returns the detected hosting platform.
+func (r *Runtime) Platform() Platform {
+	return r.platform
+}
+
+// Stages returns the status of each stage when Config.LoadFuncs is used.
+// It returns nil for runtimes configured with a single LoadFunc.
+func (r *Runtime) Stages() []StageStatus {
+	if r.pipeline == nil {
+		return nil
+	}
+	return r.pipeline.snapshot()
+}
+
 // IsReady returns true if configuration has been successfully loaded.
 func (r *Runtime) IsReady() bool {
 	r.mu.RLock()
@@ -155,27 +514,221 @@ func (r *Runtime) IsReady() bool {
 	return r.ready
 }
 
+// IsDegraded returns true if the most recent reload failed after
+// configuration had previously loaded successfully. A degraded runtime keeps
+// serving with its previous configuration; the flag clears on the next
+// successful load.
+func (r *Runtime) IsDegraded() bool {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+	return r.degraded
+}
+
+// recordLoadResult updates last-known-good state after a LoadFunc call.
+// before is the environment captured when the call started, or nil if
+// Config.RetainLastKnownGood is not set. The caller must hold loadMu.
+func (r *Runtime) recordLoadResult(ctx context.Context, err error, before map[string]string) {
+	if err == nil {
+		r.degraded = false
+		r.shedding.Store(false)
+		if before != nil {
+			r.lastGood = r.lastGood.merge(writtenSince(before))
+		}
+		return
+	}
+
+	if !r.IsReady() {
+		// Nothing loaded yet, so there is no previous configuration to keep
+		return
+	}
+
+	log := clog.FromContext(ctx)
+	r.degraded = true
+	if r.config.ShedOnReloadFailure {
+		r.shedding.Store(true)
+		log.Warnf("[ghappsetup] reload failed, shedding traffic until the next successful load: %v", err)
+	}
+	if r.lastGood != nil && before != nil {
+		r.lastGood.restore(writtenSince(before), before)
+		log.Warnf("[ghappsetup] reload failed, restored last-known-good configuration: %v", err)
+		return
+	}
+	log.Warnf("[ghappsetup] reload failed, runtime degraded: %v", err)
+}
+
+// ReadyChan returns a channel that is closed once configuration has been
+// successfully loaded. If the runtime is later reset to not ready, a new
+// channel is returned by subsequent calls.
+func (r *Runtime) ReadyChan() <-chan struct{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.readyCh
+}
+
+// WaitReady blocks until configuration has been successfully loaded or the
+// context is canceled. It is intended for background workers that must not
+// start processing before the runtime is ready.
+func (r *Runtime) WaitReady(ctx context.Context) error {
+	select {
+	case <-r.ReadyChan():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // setReady marks the runtime as ready and updates the HTTP gate if present.
 func (r *Runtime) setReady(ready bool) {
 	r.mu.Lock()
 	r.ready = ready
+	select {
+	case <-r.readyCh:
+		if !ready {
+			r.readyCh = make(chan struct{})
+		}
+	default:
+		if ready {
+			close(r.readyCh)
+		}
+	}
 	r.mu.Unlock()
 
-	if ready && r.gate != nil {
-		r.gate.SetReady()
-	}
+	r.syncGate()
 }
 
-// Reload triggers a configuration reload by calling LoadFunc.
+// Reload triggers a configuration reload by calling LoadFunc, after
+// refreshing the environment with Config.EnvRefresher if one is set.
 // This is safe to call from multiple goroutines; concurrent reload
-// requests are coalesced.
+// requests are coalesced so that only one LoadFunc runs at a time and
+// callers that arrive while it is running share its result.
 func (r *Runtime) Reload(ctx context.Context) error {
-	return r.config.LoadFunc(ctx)
+	return r.loadRefreshed(ctx, true)
+}
+
+// load runs LoadFunc, coalescing concurrent callers into a single call.
+// Every load path (Start, EnsureLoaded, refreshes, and reloads) goes through
+// load so LoadFunc never runs concurrently with itself. The shared call runs
+// with the context of the caller that started it, which also carries the
+// Runtime (see FromContext); other callers stop waiting if their own context
+// is canceled.
+func (r *Runtime) load(ctx context.Context) error {
+	return r.loadRefreshed(ctx, false)
+}
+
+// loadRefreshed is load, first refreshing the environment with
+// Config.EnvRefresher if refresh is set. The refresh runs inside the shared
+// call so concurrent reloads never re-resolve the environment at the same
+// time. A failed refresh fails the call without running LoadFunc and is
+// recorded like a failed LoadFunc.
+func (r *Runtime) loadRefreshed(ctx context.Context, refresh bool) error {
+	r.loadMu.Lock()
+	if call := r.inflight; call != nil {
+		r.loadMu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &loadCall{done: make(chan struct{})}
+	r.inflight = call
+	r.loadMu.Unlock()
+
+	// A panicking LoadFunc must not leave the shared call open, or every
+	// later caller would wait on it forever
+	var released bool
+	defer func() {
+		if v := recover(); v != nil {
+			if !released {
+				call.err = fmt.Errorf("ghappsetup: load panicked: %v", v)
+				r.loadMu.Lock()
+				r.inflight = nil
+				r.loadMu.Unlock()
+				close(call.done)
+			}
+			panic(v)
+		}
+	}()
+
+	kind := configwait.AttemptLoad
+	if r.IsReady() {
+		kind = configwait.AttemptReload
+	}
+	attempt := r.progress.beginAttempt(ctx)
+	started := time.Now()
+	var before map[string]string
+	if r.config.RetainLastKnownGood {
+		before = captureEnv()
+	}
+	if refresh {
+		call.err = r.refreshEnv(ctx)
+	}
+	if call.err == nil {
+		call.err = r.config.LoadFunc(NewContext(ctx, r))
+	}
+	r.progress.finish(call.err)
+	r.config.History.RecordAttempt(kind, attempt, started, call.err)
+	r.recordLoadMetrics(kind, time.Since(started), call.err)
+
+	r.loadMu.Lock()
+	r.recordLoadResult(ctx, call.err, before)
+	var rotated bool
+	if call.err == nil {
+		r.generation.Add(1)
+		rotated = r.updateCredentialsSum()
+	}
+	r.inflight = nil
+	r.loadMu.Unlock()
+	r.syncGate()
+	close(call.done)
+	released = true
+
+	r.publishLoadEvents(ctx, kind, rotated, call.err)
+	if call.err == nil && r.config.AfterLoad != nil {
+		r.config.AfterLoad(NewContext(ctx, r))
+	}
+
+	return call.err
+}
+
+// Generation returns the number of successful loads so far. It changes
+// whenever configuration is (re)loaded, so caches derived from loaded
+// credentials, such as GitHub installation tokens, can detect reloads and
+// rotation.
+func (r *Runtime) Generation() uint64 {
+	return r.generation.Load()
+}
+
+// Shared returns the value stored on the Runtime under key, storing the
+// result of newValue on first use. Packages built on the Runtime, such as
+// ghauth, use it to share state among everything created for one Runtime
+// without a package-level map that would keep the Runtime alive. As with
+// context values, key should be of an unexported type.
+func (r *Runtime) Shared(key any, newValue func() any) any {
+	r.sharedMu.Lock()
+	defer r.sharedMu.Unlock()
+	if v, ok := r.shared[key]; ok {
+		return v
+	}
+	if r.shared == nil {
+		r.shared = make(map[any]any)
+	}
+	v := newValue()
+	r.shared[key] = v
+	return v
 }
 
 // ReloadCallback returns a function suitable for use as installer.Config.OnReloadNeeded.
-// The returned function triggers an asynchronous reload.
+// The returned function triggers an asynchronous reload handled by
+// ListenForReloads. When CPU is throttled between requests (see
+// CPUThrottled), it instead reloads before returning, bypassing
+// Config.ReloadCooldown, so the reload runs while the request that
+// triggered it still has CPU.
 func (r *Runtime) ReloadCallback() func() {
+	if r.CPUThrottled() {
+		return r.reloadInRequest
+	}
 	return func() {
 		select {
 		case r.reloadCh <- struct{}{}:
@@ -190,13 +743,16 @@ func (r *Runtime) waitConfig() configwait.Config {
 	return configwait.Config{
 		MaxRetries:    r.config.MaxRetries,
 		RetryInterval: r.config.RetryInterval,
+		Multiplier:    r.config.Multiplier,
+		MaxInterval:   r.config.MaxInterval,
+		Jitter:        r.config.Jitter,
+		MaxWait:       r.config.MaxWait,
+		OnAttempt:     r.config.OnAttempt,
 	}
 }
 
-// detectEnvironment checks for Lambda environment indicators.
-func detectEnvironment() Environment {
-	if os.Getenv(envLambdaFunctionName) != "" {
-		return EnvironmentLambda
-	}
-	return EnvironmentHTTP
+// isProvisionedConcurrency reports whether the Lambda execution environment
+// was initialized for provisioned concurrency.
+func isProvisionedConcurrency() bool {
+	return os.Getenv(envLambdaInitializationType) == lambdaInitProvisionedConcurrency
 }
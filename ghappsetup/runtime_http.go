@@ -5,14 +5,68 @@ package ghappsetup
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"syscall"
+	"time"
+
+	"github.com/chainguard-dev/clog"
 
 	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+const (
+	// Default timeouts for servers created by RunHTTP.
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultShutdownTimeout   = 30 * time.Second
 )
 
+// HTTPServerOption is a functional option for configuring RunHTTP.
+type HTTPServerOption func(*httpServerOptions)
+
+type httpServerOptions struct {
+	readHeaderTimeout time.Duration
+	shutdownTimeout   time.Duration
+	listener          net.Listener
+	configure         []func(*http.Server)
+}
+
+// WithReadHeaderTimeout sets the server's ReadHeaderTimeout.
+// Defaults to 10 seconds.
+func WithReadHeaderTimeout(d time.Duration) HTTPServerOption {
+	return func(o *httpServerOptions) {
+		o.readHeaderTimeout = d
+	}
+}
+
+// WithShutdownTimeout sets how long RunHTTP waits for in-flight requests
+// to complete during graceful shutdown. Defaults to 30 seconds.
+func WithShutdownTimeout(d time.Duration) HTTPServerOption {
+	return func(o *httpServerOptions) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithListener serves on the given listener instead of listening on addr.
+func WithListener(l net.Listener) HTTPServerOption {
+	return func(o *httpServerOptions) {
+		o.listener = l
+	}
+}
+
+// WithServerConfig applies fn to the http.Server before it starts, for
+// settings not covered by other options (e.g., TLSConfig, IdleTimeout).
+func WithServerConfig(fn func(*http.Server)) HTTPServerOption {
+	return func(o *httpServerOptions) {
+		o.configure = append(o.configure, fn)
+	}
+}
+
 // Start blocks until configuration is successfully loaded, then marks the
 // runtime as ready. It uses the configured retry policy to attempt loading.
 // Returns an error if configuration cannot be loaded after all retries.
@@ -20,10 +74,11 @@ import (
 // This method is intended for HTTP server environments. For Lambda, use
 // EnsureLoaded instead.
 func (r *Runtime) Start(ctx context.Context) error {
-	err := configwait.Wait(ctx, r.waitConfig(), configwait.LoadFunc(r.config.LoadFunc))
+	err := configwait.Wait(ctx, r.waitConfig(), configwait.LoadFunc(r.load))
 	if err != nil {
 		return err
 	}
+	r.markRefreshed()
 	r.setReady(true)
 	return nil
 }
@@ -48,10 +103,21 @@ func (r *Runtime) StartAsync(ctx context.Context) <-chan error {
 // Handler wraps the given http.Handler with a ReadyGate that returns 503
 // Service Unavailable for requests to non-allowed paths before the runtime
 // is ready. Paths specified in Config.AllowedPaths are always forwarded
-// to the inner handler.
+// to the inner handler. The Runtime is injected into each request's
+// context and can be retrieved with FromContext. If Config.RecoverPanics is
+// set, panics in the inner handler are recovered and answered with 500.
+// When CPU is throttled between requests (see CPUThrottled), requests also
+// drive Config.RefreshInterval refreshes.
 //
 // The returned handler should be used as the server's main handler.
 func (r *Runtime) Handler(inner http.Handler) http.Handler {
+	if r.config.RecoverPanics {
+		inner = r.recoverer(inner)
+	}
+	if r.CPUThrottled() && r.config.RefreshInterval > 0 {
+		inner = r.refreshOnRequest(inner)
+	}
+	inner = r.Middleware(inner)
 	if r.gate == nil {
 		// No gate (e.g., Lambda environment) - return inner directly
 		return inner
@@ -60,57 +126,384 @@ func (r *Runtime) Handler(inner http.Handler) http.Handler {
 	return r.gate
 }
 
-// ListenForReloads starts listening for SIGHUP signals and reload triggers
+// ReadyEventsHandler returns a handler that lets splash pages and
+// deployment scripts wait for readiness via server-sent events or long
+// polling instead of polling HealthHandler (see
+// configwait.ReadyGate.ReadyEventsHandler). Mount it outside Handler or list
+// its path in AllowedPaths. It returns nil outside HTTP environments, which
+// have no ReadyGate.
+func (r *Runtime) ReadyEventsHandler() http.Handler {
+	if r.gate == nil {
+		return nil
+	}
+	return r.gate.ReadyEventsHandler()
+}
+
+// ReloadListener is a handle to the reload listener started by
+// StartReloadListener.
+type ReloadListener struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Stop stops the listener and waits for its goroutine to exit and its
+// SIGHUP registration to be removed. It is safe to call more than once.
+func (l *ReloadListener) Stop() {
+	l.cancel()
+	<-l.done
+}
+
+// Done returns a channel that is closed once the listener has stopped.
+func (l *ReloadListener) Done() <-chan struct{} {
+	return l.done
+}
+
+// ListenForReloads starts the Runtime's reload listener as
+// StartReloadListener does. The returned channel is closed when the context
+// is canceled. Use StartReloadListener to stop the listener without
+// canceling the context.
+func (r *Runtime) ListenForReloads(ctx context.Context) <-chan struct{} {
+	return r.StartReloadListener(ctx).Done()
+}
+
+// StartReloadListener starts listening for SIGHUP signals and reload triggers
 // from ReloadCallback. When a reload is triggered, LoadFunc is called.
-// The returned channel is closed when the context is canceled.
+// If Config.ReloadCooldown is set, triggers arriving within the cooldown of
+// the previous reload are deferred and collapsed into one reload. On Azure
+// Functions, which cannot signal the process, SIGHUP is not registered.
+// When Config.SettingsFile is set, the listener also watches it and applies
+// changes to allowed paths, gated paths, and the installer manifest.
+//
+// The listener stops when ctx is canceled or Stop is called on the returned
+// handle, releasing its goroutines and SIGHUP registration. Only one
+// listener runs per Runtime: while one is active, later calls return the
+// existing listener instead of registering SIGHUP again.
 //
 // This should be called after Start() completes successfully.
-func (r *Runtime) ListenForReloads(ctx context.Context) <-chan struct{} {
-	done := make(chan struct{})
+func (r *Runtime) StartReloadListener(ctx context.Context) *ReloadListener {
+	l, _ := r.startReloadListener(ctx)
+	return l
+}
 
-	// Set up SIGHUP signal handling
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGHUP)
+// startReloadListener implements StartReloadListener and reports whether
+// this call started the listener, as opposed to returning an active one.
+func (r *Runtime) startReloadListener(ctx context.Context) (*ReloadListener, bool) {
+	r.listenerMu.Lock()
+	defer r.listenerMu.Unlock()
+	if l := r.listener; l != nil {
+		select {
+		case <-l.done:
+		default:
+			return l, false
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	l := &ReloadListener{cancel: cancel, done: make(chan struct{})}
+	r.listener = l
+
+	// Set up SIGHUP signal handling where the platform can deliver it,
+	// unless the signals are injected
+	sigCh := r.config.ReloadSignals
+	stopSignals := func() {}
+	if sigCh == nil {
+		notifyCh := make(chan os.Signal, 1)
+		if sigs := r.platform.reloadSignals(); len(sigs) > 0 {
+			signal.Notify(notifyCh, sigs...)
+		}
+		sigCh, stopSignals = notifyCh, func() { signal.Stop(notifyCh) }
+	}
 
 	go func() {
-		defer close(done)
-		defer signal.Stop(sigCh)
+		defer close(l.done)
+		defer cancel()
+		defer stopSignals()
+
+		var (
+			lastReload time.Time
+			deferred   *time.Timer
+			deferredCh <-chan time.Time
+		)
+		defer func() {
+			if deferred != nil {
+				deferred.Stop()
+			}
+		}()
+
+		// The loop below only returns once ctx is done, which stops the
+		// settings watcher too
+		watchDone := make(chan struct{})
+		go func() {
+			defer close(watchDone)
+			r.watchSettings(ctx)
+		}()
+		defer func() { <-watchDone }()
+
+		reload := func() {
+			r.doReload(ctx)
+			lastReload = time.Now()
+		}
+		trigger := func() {
+			if deferredCh != nil {
+				// A deferred reload is already queued
+				return
+			}
+			if wait := r.config.ReloadCooldown - time.Since(lastReload); !lastReload.IsZero() && wait > 0 {
+				clog.FromContext(ctx).Debugf("[ghappsetup] reload deferred for %v by cooldown", wait)
+				deferred = time.NewTimer(wait)
+				deferredCh = deferred.C
+				return
+			}
+			reload()
+		}
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-sigCh:
-				r.doReload(ctx)
+				trigger()
 			case <-r.reloadCh:
-				r.doReload(ctx)
+				trigger()
+			case <-deferredCh:
+				deferred, deferredCh = nil, nil
+				reload()
 			}
 		}
 	}()
 
-	return done
+	return l, true
+}
+
+// newReadyGate creates the ReadyGate for HTTP environments from the
+// runtime configuration.
+func (r *Runtime) newReadyGate() *configwait.ReadyGate {
+	cfg := r.config
+	var opts []configwait.ReadyGateOption
+	if gated := r.gatedPaths(); len(gated) > 0 {
+		opts = append(opts, configwait.WithGatedPaths(gated))
+	}
+	if cfg.NotReadyResponse != nil {
+		opts = append(opts, configwait.WithUnavailableResponse(cfg.NotReadyResponse))
+	}
+	if cfg.HoldRequests > 0 && cfg.HoldTimeout > 0 {
+		opts = append(opts, configwait.WithHoldRequests(cfg.HoldRequests, cfg.HoldTimeout))
+	}
+	if cfg.Metrics != nil {
+		opts = append(opts, configwait.WithOnNotReady(func(*http.Request) {
+			r.recordMetric(metrics.GatedRequests, 1, metrics.UnitCount)
+		}))
+	}
+	for path, h := range cfg.NotReadyHandlers {
+		opts = append(opts, configwait.WithFallbackHandler(path, h))
+	}
+	switch {
+	case cfg.RetryAfter > 0:
+		opts = append(opts, configwait.WithRetryAfter(cfg.RetryAfter))
+	case cfg.DynamicRetryAfter:
+		opts = append(opts, configwait.WithRetryAfterFunc(r.estimatedRetryAfter))
+	}
+	return configwait.NewReadyGate(nil, r.allowedPaths(), opts...)
+}
+
+// estimatedRetryAfter returns the expected remaining startup wait given the
+// current load attempt, falling back to the retry interval once no retries
+// remain.
+func (r *Runtime) estimatedRetryAfter() time.Duration {
+	if remaining := r.waitConfig().RemainingWait(r.Progress().Attempt); remaining > 0 {
+		return remaining
+	}
+	return r.config.RetryInterval
 }
 
 // doReload performs the actual reload operation.
 func (r *Runtime) doReload(ctx context.Context) {
-	if err := r.config.LoadFunc(ctx); err != nil {
+	if err := r.Reload(ctx); err != nil {
 		// Log error but don't crash - reload failures are non-fatal
 		// The application continues running with the previous configuration
 		return
 	}
 }
 
+// RunHTTP runs a managed HTTP server for the runtime. It serves handler
+// behind the ReadyGate on addr, blocks until configuration loads, listens
+// for reloads, and gracefully shuts the server down when ctx is canceled.
+//
+// When ctx is canceled, RunHTTP calls BeginShutdown and waits for
+// Config.DrainPeriod to elapse before shutting the server down. On ECS,
+// Cloud Run, and Cloud Functions, SIGTERM from the platform starts the
+// same graceful shutdown.
+//
+// RunHTTP returns nil after a clean shutdown triggered by ctx. It returns an
+// error if the server fails, if configuration cannot be loaded after all
+// retries, or if graceful shutdown does not complete in time.
+//
+// This method is intended for HTTP server environments and replaces the
+// usual Handler, Start, ListenForReloads, and Shutdown wiring:
+//
+//	if err := runtime.RunHTTP(ctx, ":8080", mux); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Runtime) RunHTTP(ctx context.Context, addr string, handler http.Handler, opts ...HTTPServerOption) error {
+	log := clog.FromContext(ctx)
+
+	if sigs := r.platform.shutdownSignals(); len(sigs) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, sigs...)
+		defer stop()
+	}
+
+	// Canceled on return so the startup load and reload listener never
+	// outlive RunHTTP.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	o := httpServerOptions{
+		readHeaderTimeout: defaultReadHeaderTimeout,
+		shutdownTimeout:   defaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           r.Handler(handler),
+		ReadHeaderTimeout: o.readHeaderTimeout,
+	}
+	for _, fn := range o.configure {
+		fn(srv)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		var err error
+		if o.listener != nil {
+			err = srv.Serve(o.listener)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		serveErr <- err
+	}()
+
+	var runErr error
+	loaded := r.StartAsync(ctx)
+	select {
+	case err := <-serveErr:
+		cancel()
+		<-loaded
+		return fmt.Errorf("ghappsetup: http server failed: %w", err)
+	case err := <-loaded:
+		if err != nil && ctx.Err() == nil {
+			runErr = fmt.Errorf("ghappsetup: failed to load configuration: %w", err)
+			break
+		}
+		if err == nil {
+			log.Infof("[ghappsetup] configuration loaded, service is ready")
+			r.ListenForReloads(ctx)
+		}
+		select {
+		case <-ctx.Done():
+		case err := <-serveErr:
+			if err != nil {
+				return fmt.Errorf("ghappsetup: http server failed: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if r.config.DrainPeriod > 0 {
+		log.Infof("[ghappsetup] draining for %v before shutdown", r.config.DrainPeriod)
+	}
+	<-r.BeginShutdown()
+
+	log.Infof("[ghappsetup] shutting down http server")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), o.shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return errors.Join(runErr, fmt.Errorf("ghappsetup: http server shutdown failed: %w", err))
+	}
+	return runErr
+}
+
 // HealthHandler returns an http.HandlerFunc that reports the runtime's
-// readiness status. It returns 200 OK with body "ok" when ready, or
-// 503 Service Unavailable with body "not ready" when not ready.
+// readiness status. It returns 200 OK with body "ok" when ready (or
+// "degraded" if the last reload failed and the previous configuration is
+// still in use), or 503 Service Unavailable with body "not ready" when not
+// ready. When a multi-stage pipeline is configured, the not-ready body also
+// names the stage that has not yet succeeded (e.g. "not ready: stage
+// resolve-ssm failed"), and a failing readiness check is named the same way.
+// After BeginShutdown the body is "not ready: draining", in maintenance
+// mode "not ready: maintenance", and while shedding traffic after a failed
+// reload "not ready: reload failed".
+//
+// Clients that send "Accept: application/json" or "?format=json" receive
+// the full HealthReport as JSON instead, with the same status code.
 func (r *Runtime) HealthHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		if r.IsReady() {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte("ok"))
-		} else {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_, _ = w.Write([]byte("not ready"))
+		report := r.Health(req.Context())
+		writeHealth(w, req, report.Status, report, healthText(report))
+	}
+}
+
+// writeHealth writes a health response, returning 503 when status is not
+// ready. The report is encoded as JSON when the client asks for it and
+// text is written otherwise.
+func writeHealth(w http.ResponseWriter, req *http.Request, status string, report any, text string) {
+	code := http.StatusOK
+	if status == HealthStatusNotReady {
+		code = http.StatusServiceUnavailable
+	}
+
+	if wantsJSON(req) {
+		log := clog.FromContext(req.Context())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			log.Errorf("[ghappsetup] failed to write health response: %v", err)
+		}
+		return
+	}
+
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(text))
+}
+
+// healthText renders the plain-text health body for a report.
+func healthText(report HealthReport) string {
+	if report.Status != HealthStatusNotReady {
+		return report.Status
+	}
+	if report.Draining {
+		return "not ready: draining"
+	}
+	if report.Maintenance {
+		return "not ready: maintenance"
+	}
+	if report.Shedding {
+		return "not ready: reload failed"
+	}
+	if !report.Ready {
+		for _, stage := range report.Stages {
+			if stage.State != StageSucceeded {
+				return fmt.Sprintf("not ready: stage %s %s", stage.Name, stage.State)
+			}
+		}
+		return HealthStatusNotReady
+	}
+	for _, check := range report.Checks {
+		if !check.OK {
+			return fmt.Sprintf("not ready: check %s failed", check.Name)
+		}
+	}
+	for _, cond := range report.Conditions {
+		if !cond.Ready {
+			return fmt.Sprintf("not ready: condition %s unset", cond.Name)
 		}
 	}
+	return HealthStatusNotReady
 }
@@ -6,10 +6,12 @@ package ghappsetup
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -187,6 +189,90 @@ func TestRuntime_Handler_GatesRequests(t *testing.T) {
 	}
 }
 
+func TestRuntime_Handler_GatedPaths(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:      &mockStore{},
+		LoadFunc:   func(ctx context.Context) error { return nil },
+		GatedPaths: []string{"/webhook"},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d for ungated path", rec.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d for gated path", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestRuntime_Handler_DynamicRetryAfter(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:             &mockStore{},
+		LoadFunc:          func(ctx context.Context) error { return nil },
+		MaxRetries:        4,
+		RetryInterval:     10 * time.Second,
+		DynamicRetryAfter: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	handler := runtime.Handler(http.NotFoundHandler())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want %q", got, "30")
+	}
+}
+
+func TestRuntime_ReadyEventsHandler(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	handler := runtime.ReadyEventsHandler()
+	if handler == nil {
+		t.Fatal("ReadyEventsHandler() should not be nil in HTTP environments")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		runtime.setReady(true)
+	}()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready?timeout=5s", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d once ready", rec.Code, http.StatusOK)
+	}
+}
+
 func TestRuntime_HealthHandler(t *testing.T) {
 	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
 
@@ -227,6 +313,182 @@ func TestRuntime_HealthHandler(t *testing.T) {
 	}
 }
 
+func TestRuntime_RunHTTP(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	release := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+		AllowedPaths:  []string{"/healthz"},
+		MaxRetries:    1,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	baseURL := "http://" + listener.Addr().String()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", runtime.HealthHandler())
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- runtime.RunHTTP(ctx, "", mux, WithListener(listener), WithShutdownTimeout(time.Second))
+	}()
+
+	resp, err := client.Get(baseURL + "/api")
+	if err != nil {
+		t.Fatalf("GET /api error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d before ready", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+	if err := runtime.WaitReady(ctx); err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
+
+	resp, err = client.Get(baseURL + "/api")
+	if err != nil {
+		t.Fatalf("GET /api error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Status = %d, want %d after ready", resp.StatusCode, http.StatusOK)
+	}
+
+	cancel()
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("RunHTTP() error = %v, want nil after cancel", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunHTTP() did not return after context cancellation")
+	}
+}
+
+func TestRuntime_RunHTTP_LoadFailure(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	expectedErr := errors.New("always fail")
+	runtime, err := NewRuntime(Config{
+		Store:         &mockStore{},
+		LoadFunc:      func(ctx context.Context) error { return expectedErr },
+		MaxRetries:    2,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+
+	err = runtime.RunHTTP(context.Background(), "", http.NewServeMux(), WithListener(listener))
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("RunHTTP() error = %v, want %v", err, expectedErr)
+	}
+}
+
+func TestRuntime_RunHTTP_ListenError(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return errors.New("not ready") },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = runtime.RunHTTP(ctx, listener.Addr().String(), http.NewServeMux())
+	if err == nil || ctx.Err() != nil {
+		t.Errorf("RunHTTP() error = %v, want listen error", err)
+	}
+}
+
+func TestRuntime_RunHTTP_ListenErrorStopsLoad(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var loading atomic.Bool
+	started := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			loading.Store(true)
+			defer loading.Store(false)
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	// Fail the server once the load is underway
+	failing := &startedListener{Listener: listener, started: started}
+	if err := runtime.RunHTTP(context.Background(), "", http.NewServeMux(), WithListener(failing)); err == nil {
+		t.Fatal("RunHTTP() error = nil, want server error")
+	}
+	if loading.Load() {
+		t.Error("LoadFunc still running after RunHTTP returned")
+	}
+}
+
+// startedListener fails Accept once started is closed.
+type startedListener struct {
+	net.Listener
+	started chan struct{}
+}
+
+func (l *startedListener) Accept() (net.Conn, error) {
+	<-l.started
+	return nil, errors.New("listener failed")
+}
+
 func TestRuntime_ListenForReloads(t *testing.T) {
 	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
 
@@ -267,6 +529,130 @@ func TestRuntime_ListenForReloads(t *testing.T) {
 	}
 }
 
+func TestRuntime_StartReloadListener_Idempotent(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var reloadCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			reloadCount.Add(1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	first := runtime.StartReloadListener(context.Background())
+	second := runtime.StartReloadListener(context.Background())
+	if first != second {
+		t.Error("StartReloadListener() should return the active listener on later calls")
+	}
+
+	runtime.ReloadCallback()()
+	time.Sleep(50 * time.Millisecond)
+	if reloadCount.Load() != 1 {
+		t.Errorf("Reload count = %d, want 1", reloadCount.Load())
+	}
+
+	first.Stop()
+	first.Stop()
+	select {
+	case <-first.Done():
+	default:
+		t.Error("Done() should be closed after Stop()")
+	}
+
+	// Triggers are no longer handled once stopped
+	runtime.ReloadCallback()()
+	time.Sleep(20 * time.Millisecond)
+	if reloadCount.Load() != 1 {
+		t.Errorf("Reload count = %d, want 1 after Stop()", reloadCount.Load())
+	}
+
+	// A new listener can be started after the previous one stopped and
+	// picks up the pending trigger
+	third := runtime.StartReloadListener(context.Background())
+	defer third.Stop()
+	if third == first {
+		t.Error("StartReloadListener() should start a new listener after Stop()")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if reloadCount.Load() != 2 {
+		t.Errorf("Reload count = %d, want 2", reloadCount.Load())
+	}
+}
+
+func TestRuntime_ListenForReloads_Cooldown(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var reloadCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			reloadCount.Add(1)
+			return nil
+		},
+		ReloadCooldown: 150 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runtime.ListenForReloads(ctx)
+
+	callback := runtime.ReloadCallback()
+	callback()
+	time.Sleep(30 * time.Millisecond)
+	if reloadCount.Load() != 1 {
+		t.Fatalf("Reload count = %d, want 1 after first trigger", reloadCount.Load())
+	}
+
+	// A storm of triggers during the cooldown collapses into one reload
+	for i := 0; i < 5; i++ {
+		callback()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if reloadCount.Load() != 1 {
+		t.Errorf("Reload count = %d, want 1 during cooldown", reloadCount.Load())
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if reloadCount.Load() != 2 {
+		t.Errorf("Reload count = %d, want 2 after cooldown", reloadCount.Load())
+	}
+}
+
+func TestRuntime_ListenForReloads_ReloadSignals(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var reloadCount atomic.Int32
+	signals := make(chan os.Signal, 1)
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			reloadCount.Add(1)
+			return nil
+		},
+		ReloadSignals: signals,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	listener := runtime.StartReloadListener(context.Background())
+	defer listener.Stop()
+
+	signals <- syscall.SIGHUP
+	time.Sleep(50 * time.Millisecond)
+	if reloadCount.Load() != 1 {
+		t.Errorf("Reload count = %d, want 1", reloadCount.Load())
+	}
+}
+
 // mockStore for HTTP tests
 type httpMockStore struct{}
 
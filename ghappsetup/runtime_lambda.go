@@ -13,10 +13,12 @@ import (
 
 // lambdaState tracks Lambda-specific initialization state.
 type lambdaState struct {
-	mu        sync.Mutex
-	loading   bool
-	loaded    bool
-	lastError error
+	mu          sync.Mutex
+	loading     bool
+	loaded      bool
+	refreshing  bool
+	lastError   error
+	lastRefresh time.Time
 }
 
 var lambdaStates = struct {
@@ -46,6 +48,10 @@ func (r *Runtime) getLambdaState() *lambdaState {
 // Subsequent calls return immediately if already loaded, or return the
 // last error if loading failed.
 //
+// If Config.RefreshInterval is set, a call made after the interval has
+// elapsed also starts a background refresh. The refresh does not block the
+// caller, and a failed refresh leaves the previous configuration in place.
+//
 // This method is intended for Lambda environments where configuration
 // loading happens lazily on first request. For HTTP servers, use Start instead.
 //
@@ -63,7 +69,11 @@ func (r *Runtime) EnsureLoaded(ctx context.Context) error {
 	// Fast path: already loaded
 	state.mu.Lock()
 	if state.loaded {
+		refresh := r.shouldRefresh(state)
 		state.mu.Unlock()
+		if refresh {
+			go r.refresh(context.WithoutCancel(ctx), state)
+		}
 		return nil
 	}
 
@@ -84,6 +94,7 @@ func (r *Runtime) EnsureLoaded(ctx context.Context) error {
 	state.loading = false
 	if err == nil {
 		state.loaded = true
+		state.lastRefresh = time.Now()
 		r.setReady(true)
 	} else {
 		state.lastError = err
@@ -93,6 +104,51 @@ func (r *Runtime) EnsureLoaded(ctx context.Context) error {
 	return err
 }
 
+// shouldRefresh reports whether a background refresh is due and, if so,
+// marks one as in progress. The caller must hold state.mu.
+func (r *Runtime) shouldRefresh(state *lambdaState) bool {
+	if r.config.RefreshInterval <= 0 || state.refreshing {
+		return false
+	}
+	if time.Since(state.lastRefresh) < r.config.RefreshInterval {
+		return false
+	}
+	state.refreshing = true
+	return true
+}
+
+// refresh re-resolves the environment with Config.EnvRefresher and re-runs
+// LoadFunc once. Failures are logged and the previous configuration remains
+// in use until the next refresh attempt.
+func (r *Runtime) refresh(ctx context.Context, state *lambdaState) {
+	log := clog.FromContext(ctx)
+
+	err := r.loadRefreshed(ctx, true)
+
+	state.mu.Lock()
+	state.refreshing = false
+	state.lastRefresh = time.Now()
+	state.mu.Unlock()
+
+	if err != nil {
+		log.Warnf("[ghappsetup] background refresh failed: %v", err)
+		return
+	}
+	log.Debugf("[ghappsetup] background refresh completed")
+}
+
+// preload runs EnsureLoaded during initialization. Errors are not fatal;
+// the next EnsureLoaded call attempts loading again.
+func (r *Runtime) preload(ctx context.Context) {
+	log := clog.FromContext(ctx)
+
+	if err := r.EnsureLoaded(ctx); err != nil {
+		log.Warnf("[ghappsetup] preload during init failed, deferring to first invocation: %v", err)
+		return
+	}
+	log.Infof("[ghappsetup] configuration preloaded during init")
+}
+
 // waitForLoad waits for another goroutine to finish loading.
 func (r *Runtime) waitForLoad(ctx context.Context, state *lambdaState) error {
 	ticker := time.NewTicker(50 * time.Millisecond)
@@ -122,18 +178,29 @@ func (r *Runtime) waitForLoad(ctx context.Context, state *lambdaState) error {
 // loadWithRetry attempts to load configuration with retry logic.
 func (r *Runtime) loadWithRetry(ctx context.Context) error {
 	log := clog.FromContext(ctx)
+	cfg := r.waitConfig()
+	start := time.Now()
 	var lastErr error
 
 	for attempt := 1; attempt <= r.config.MaxRetries; attempt++ {
-		if err := r.config.LoadFunc(ctx); err != nil {
+		err := r.load(ctx)
+		if r.config.OnAttempt != nil {
+			r.config.OnAttempt(attempt, err)
+		}
+		if err != nil {
 			lastErr = err
 			log.Warnf("[ghappsetup] attempt %d/%d failed: %v", attempt, r.config.MaxRetries, err)
 
 			if attempt < r.config.MaxRetries {
+				delay, ok := cfg.BudgetDelay(attempt, time.Since(start))
+				if !ok {
+					log.Warnf("[ghappsetup] giving up after %d attempts: max wait %v exceeded", attempt, cfg.MaxWait)
+					return lastErr
+				}
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
-				case <-time.After(r.config.RetryInterval):
+				case <-time.After(delay):
 				}
 			}
 		} else {
@@ -155,9 +222,9 @@ func (r *Runtime) ResetLoadState() {
 	defer state.mu.Unlock()
 	state.loaded = false
 	state.loading = false
+	state.refreshing = false
 	state.lastError = nil
+	state.lastRefresh = time.Time{}
 
-	r.mu.Lock()
-	r.ready = false
-	r.mu.Unlock()
+	r.setReady(false)
 }
@@ -0,0 +1,144 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+// ALBHandler handles requests from an Application Load Balancer target
+// group.
+type ALBHandler func(ctx context.Context, req events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error)
+
+// WrapALB wraps an ALB target group handler with EnsureLoaded. Requests
+// received before the configuration loads are answered with the not-ready
+// response instead of reaching handler, and the Runtime is injected into
+// the handler's context (see FromContext).
+//
+// The not-ready response is built like the HTTP ReadyGate's: by
+// Config.NotReadyResponse, or configwait.DefaultUnavailableResponse, with
+// a Retry-After header following Config.RetryAfter and
+// Config.DynamicRetryAfter. Its headers are returned as multi-value headers
+// when the target group has them enabled.
+//
+//	lambda.Start(runtime.WrapALB(func(ctx context.Context, req events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+//	    return events.ALBTargetGroupResponse{StatusCode: 200, Body: "ok"}, nil
+//	}))
+func (r *Runtime) WrapALB(handler ALBHandler) ALBHandler {
+	return func(ctx context.Context, req events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+		if err := r.EnsureLoaded(ctx); err != nil {
+			clog.FromContext(ctx).Warnf("[ghappsetup] rejecting %s %s: configuration not loaded: %v", req.HTTPMethod, req.Path, err)
+			resp := r.lambdaUnavailable(ctx, req.HTTPMethod, req.Path, eventHeader(req.Headers, req.MultiValueHeaders))
+
+			out := events.ALBTargetGroupResponse{
+				StatusCode:        resp.Status,
+				StatusDescription: strconv.Itoa(resp.Status) + " " + http.StatusText(resp.Status),
+				Body:              string(resp.Body),
+			}
+			if len(req.MultiValueHeaders) > 0 {
+				out.MultiValueHeaders = resp.Header
+			} else {
+				out.Headers = singleValueHeaders(resp.Header)
+			}
+			return out, nil
+		}
+		return handler(NewContext(ctx, r), req)
+	}
+}
+
+// APIGatewayV1Handler handles requests from an API Gateway REST API (v1)
+// Lambda proxy integration.
+type APIGatewayV1Handler func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// WrapAPIGatewayV1 wraps an API Gateway REST API handler with
+// EnsureLoaded, like WrapALB: requests received before the configuration
+// loads get the not-ready response, and the Runtime is injected into the
+// handler's context.
+//
+//	lambda.Start(runtime.WrapAPIGatewayV1(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+//	    return events.APIGatewayProxyResponse{StatusCode: 200, Body: "ok"}, nil
+//	}))
+func (r *Runtime) WrapAPIGatewayV1(handler APIGatewayV1Handler) APIGatewayV1Handler {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if err := r.EnsureLoaded(ctx); err != nil {
+			clog.FromContext(ctx).Warnf("[ghappsetup] rejecting %s %s: configuration not loaded: %v", req.HTTPMethod, req.Path, err)
+			resp := r.lambdaUnavailable(ctx, req.HTTPMethod, req.Path, eventHeader(req.Headers, req.MultiValueHeaders))
+			return events.APIGatewayProxyResponse{
+				StatusCode: resp.Status,
+				Headers:    singleValueHeaders(resp.Header),
+				Body:       string(resp.Body),
+			}, nil
+		}
+		return handler(NewContext(ctx, r), req)
+	}
+}
+
+// eventHeader converts the headers of a Lambda HTTP event, which may be
+// lowercase and come in single- and multi-value forms, to an http.Header.
+func eventHeader(single map[string]string, multi map[string][]string) http.Header {
+	header := make(http.Header, len(single))
+	for k, v := range single {
+		header.Set(k, v)
+	}
+	for k, vs := range multi {
+		header[http.CanonicalHeaderKey(k)] = vs
+	}
+	return header
+}
+
+// singleValueHeaders returns the first value of each header, for event
+// responses without multi-value headers.
+func singleValueHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for k := range header {
+		out[k] = header.Get(k)
+	}
+	return out
+}
+
+// lambdaUnavailable builds the not-ready response for a Lambda event
+// wrapper, as the ReadyGate builds it for HTTP requests.
+func (r *Runtime) lambdaUnavailable(ctx context.Context, method, path string, header http.Header) configwait.UnavailableResponse {
+	r.recordMetric(metrics.GatedRequests, 1, metrics.UnitCount)
+	req := (&http.Request{
+		Method: method,
+		URL:    &url.URL{Path: path},
+		Header: header,
+	}).WithContext(ctx)
+
+	build := r.config.NotReadyResponse
+	if build == nil {
+		build = configwait.DefaultUnavailableResponse
+	}
+	resp := build(req, "service not ready, configuration loading")
+	if resp.Status == 0 {
+		resp.Status = http.StatusServiceUnavailable
+	}
+	// The header may be shared between responses
+	resp.Header = resp.Header.Clone()
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		retryAfter := configwait.DefaultRetryAfter
+		switch {
+		case r.config.RetryAfter > 0:
+			retryAfter = r.config.RetryAfter
+		case r.config.DynamicRetryAfter:
+			retryAfter = r.estimatedRetryAfter()
+		}
+		resp.Header.Set("Retry-After", strconv.FormatInt(max(int64(math.Ceil(retryAfter.Seconds())), 1), 10))
+	}
+	return resp
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/cruxstack/github-app-setup-go/configwait"
+)
+
+// newEventRuntime returns a Lambda Runtime whose loads fail until ready is
+// set.
+func newEventRuntime(t *testing.T, cfg Config) (*Runtime, *atomic.Bool) {
+	t.Helper()
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	var ready atomic.Bool
+	cfg.Store = &lambdaMockStore{}
+	cfg.LoadFunc = func(ctx context.Context) error {
+		if !ready.Load() {
+			return errors.New("not ready")
+		}
+		return nil
+	}
+	cfg.MaxRetries = 1
+	runtime, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	return runtime, &ready
+}
+
+func albHandler(ctx context.Context, req events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	if FromContext(ctx) == nil {
+		return events.ALBTargetGroupResponse{StatusCode: 500}, nil
+	}
+	return events.ALBTargetGroupResponse{StatusCode: 200, StatusDescription: "200 OK", Body: "ok"}, nil
+}
+
+func TestRuntime_WrapALB(t *testing.T) {
+	runtime, ready := newEventRuntime(t, Config{})
+	handler := runtime.WrapALB(albHandler)
+	ctx := context.Background()
+	req := events.ALBTargetGroupRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/webhook",
+		Headers:    map[string]string{"accept": "application/json"},
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if resp.StatusCode != 503 || resp.StatusDescription != "503 Service Unavailable" {
+		t.Errorf("status = %d %q, want 503", resp.StatusCode, resp.StatusDescription)
+	}
+	if resp.Headers["Content-Type"] != "application/json" || resp.Headers["Retry-After"] != "5" {
+		t.Errorf("headers = %v", resp.Headers)
+	}
+	if resp.MultiValueHeaders != nil {
+		t.Errorf("multi-value headers set without multi-value requests: %v", resp.MultiValueHeaders)
+	}
+	if !strings.Contains(resp.Body, "service_unavailable") {
+		t.Errorf("body = %q", resp.Body)
+	}
+
+	ready.Store(true)
+	resp, err = handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if resp.StatusCode != 200 || resp.Body != "ok" {
+		t.Errorf("response after loading = %+v, want the handler's", resp)
+	}
+}
+
+func TestRuntime_WrapALB_MultiValueHeaders(t *testing.T) {
+	runtime, _ := newEventRuntime(t, Config{})
+	resp, err := runtime.WrapALB(albHandler)(context.Background(), events.ALBTargetGroupRequest{
+		HTTPMethod:        http.MethodGet,
+		Path:              "/",
+		MultiValueHeaders: map[string][]string{"accept": {"text/html,application/xhtml+xml"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Headers != nil {
+		t.Errorf("single-value headers set for a multi-value request: %v", resp.Headers)
+	}
+	if got := resp.MultiValueHeaders["Content-Type"]; len(got) != 1 || !strings.HasPrefix(got[0], "text/html") {
+		t.Errorf("Content-Type = %v, want the HTML page for browsers", got)
+	}
+}
+
+func TestRuntime_WrapALB_NotReadyResponse(t *testing.T) {
+	shared := http.Header{"Content-Type": {"text/plain"}}
+	runtime, _ := newEventRuntime(t, Config{
+		NotReadyResponse: func(r *http.Request, message string) configwait.UnavailableResponse {
+			return configwait.UnavailableResponse{Status: http.StatusTooManyRequests, Header: shared, Body: []byte(r.URL.Path)}
+		},
+		RetryAfter: 90 * time.Second,
+	})
+	resp, err := runtime.WrapALB(albHandler)(context.Background(), events.ALBTargetGroupRequest{HTTPMethod: http.MethodGet, Path: "/setup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 429 || resp.StatusDescription != "429 Too Many Requests" || resp.Body != "/setup" {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Headers["Retry-After"] != "90" {
+		t.Errorf("Retry-After = %q, want 90", resp.Headers["Retry-After"])
+	}
+	if shared.Get("Retry-After") != "" {
+		t.Error("the NotReadyResponse header was modified")
+	}
+}
+
+func TestRuntime_WrapAPIGatewayV1(t *testing.T) {
+	runtime, ready := newEventRuntime(t, Config{DynamicRetryAfter: true, RetryInterval: 3 * time.Second})
+	handler := runtime.WrapAPIGatewayV1(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if FromContext(ctx) == nil {
+			return events.APIGatewayProxyResponse{StatusCode: 500}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: req.Path}, nil
+	})
+	ctx := context.Background()
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:        http.MethodPost,
+		Path:              "/webhook",
+		Headers:           map[string]string{"Accept": "application/json"},
+		MultiValueHeaders: map[string][]string{"Accept": {"application/json"}},
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if resp.StatusCode != 503 || !strings.Contains(resp.Body, "service_unavailable") {
+		t.Errorf("response = %+v, want the JSON 503", resp)
+	}
+	// No retries remain, so the dynamic Retry-After is the retry interval
+	if resp.Headers["Content-Type"] != "application/json" || resp.Headers["Retry-After"] != "3" {
+		t.Errorf("headers = %v", resp.Headers)
+	}
+
+	ready.Store(true)
+	resp, err = handler(ctx, req)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if resp.StatusCode != 200 || resp.Body != "/webhook" {
+		t.Errorf("response after loading = %+v, want the handler's", resp)
+	}
+}
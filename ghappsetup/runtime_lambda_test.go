@@ -117,6 +117,39 @@ func TestRuntime_EnsureLoaded_RetryThenSuccess(t *testing.T) {
 	}
 }
 
+func TestRuntime_EnsureLoaded_OnAttempt(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var attempts []int
+	var lastErr error
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if len(attempts) < 1 {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+		OnAttempt: func(attempt int, err error) {
+			attempts = append(attempts, attempt)
+			lastErr = err
+		},
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.EnsureLoaded(context.Background()); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[1] != 2 || lastErr != nil {
+		t.Errorf("OnAttempt attempts = %v, last error = %v, want [1 2] and nil", attempts, lastErr)
+	}
+}
+
 func TestRuntime_EnsureLoaded_MaxRetriesExceeded(t *testing.T) {
 	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
 	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
@@ -145,6 +178,34 @@ func TestRuntime_EnsureLoaded_MaxRetriesExceeded(t *testing.T) {
 	}
 }
 
+func TestRuntime_EnsureLoaded_MaxWaitExceeded(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	expectedErr := errors.New("always fail")
+	var calls atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			calls.Add(1)
+			return expectedErr
+		},
+		MaxRetries:    100,
+		RetryInterval: 20 * time.Millisecond,
+		MaxWait:       50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.EnsureLoaded(context.Background()); err != expectedErr {
+		t.Errorf("EnsureLoaded() error = %v, want %v", err, expectedErr)
+	}
+	if n := calls.Load(); n < 2 || n >= 100 {
+		t.Errorf("LoadFunc called %d times, want a few attempts before MaxWait", n)
+	}
+}
+
 func TestRuntime_EnsureLoaded_ContextCancellation(t *testing.T) {
 	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
 	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
@@ -263,6 +324,250 @@ func TestRuntime_EnsureLoaded_ConcurrentCalls(t *testing.T) {
 	}
 }
 
+func TestRuntime_EnsureLoaded_BackgroundRefresh(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			return nil
+		},
+		MaxRetries:      3,
+		RetryInterval:   10 * time.Millisecond,
+		RefreshInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+
+	// Within the interval no refresh should be started
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+	if callCount.Load() != 1 {
+		t.Errorf("LoadFunc called %d times, want 1 before interval elapses", callCount.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for callCount.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if callCount.Load() != 2 {
+		t.Errorf("LoadFunc called %d times, want 2 after refresh", callCount.Load())
+	}
+}
+
+func TestRuntime_EnsureLoaded_BackgroundRefreshResolvesEnv(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	refresher := &countingRefresher{}
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store:        &lambdaMockStore{},
+		EnvRefresher: refresher,
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			return nil
+		},
+		MaxRetries:      3,
+		RetryInterval:   10 * time.Millisecond,
+		RefreshInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+	if refresher.calls.Load() != 0 {
+		t.Errorf("refresh calls = %d after the initial load, want 0", refresher.calls.Load())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for callCount.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if refresher.calls.Load() != 1 || callCount.Load() != 2 {
+		t.Errorf("refresh calls = %d, loads = %d; want the interval refresh to re-resolve before loading",
+			refresher.calls.Load(), callCount.Load())
+	}
+}
+
+func TestRuntime_EnsureLoaded_BackgroundRefreshFailureKeepsReady(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if callCount.Add(1) > 1 {
+				return errors.New("refresh failed")
+			}
+			return nil
+		},
+		MaxRetries:      3,
+		RetryInterval:   10 * time.Millisecond,
+		RefreshInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Fatalf("EnsureLoaded() error = %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := runtime.EnsureLoaded(ctx); err != nil {
+		t.Errorf("EnsureLoaded() error = %v, want nil while refresh fails", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for callCount.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !runtime.IsReady() {
+		t.Error("IsReady() should remain true after a failed refresh")
+	}
+}
+
+func TestNewRuntime_PreloadOnInit(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			return nil
+		},
+		MaxRetries:    3,
+		RetryInterval: 10 * time.Millisecond,
+		PreloadOnInit: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if !runtime.IsReady() {
+		t.Error("IsReady() should be true after preload")
+	}
+
+	if err := runtime.EnsureLoaded(context.Background()); err != nil {
+		t.Errorf("EnsureLoaded() error = %v", err)
+	}
+	if callCount.Load() != 1 {
+		t.Errorf("LoadFunc called %d times, want 1", callCount.Load())
+	}
+}
+
+func TestNewRuntime_PreloadProvisionedConcurrency(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	os.Setenv("AWS_LAMBDA_INITIALIZATION_TYPE", "provisioned-concurrency")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+	defer os.Unsetenv("AWS_LAMBDA_INITIALIZATION_TYPE")
+
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			return nil
+		},
+		MaxRetries:    3,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if callCount.Load() != 1 {
+		t.Errorf("LoadFunc called %d times, want 1", callCount.Load())
+	}
+	if !runtime.IsReady() {
+		t.Error("IsReady() should be true after provisioned concurrency preload")
+	}
+}
+
+func TestNewRuntime_PreloadFailureDefersToInvocation(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	var callCount atomic.Int32
+	runtime, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if callCount.Add(1) == 1 {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+		MaxRetries:    1,
+		RetryInterval: 10 * time.Millisecond,
+		PreloadOnInit: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if runtime.IsReady() {
+		t.Error("IsReady() should be false after failed preload")
+	}
+
+	if err := runtime.EnsureLoaded(context.Background()); err != nil {
+		t.Errorf("EnsureLoaded() error = %v, want nil on retry", err)
+	}
+	if !runtime.IsReady() {
+		t.Error("IsReady() should be true after EnsureLoaded()")
+	}
+}
+
+func TestNewRuntime_NoPreloadByDefault(t *testing.T) {
+	os.Setenv("AWS_LAMBDA_FUNCTION_NAME", "test-function")
+	defer os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+	os.Unsetenv("AWS_LAMBDA_INITIALIZATION_TYPE")
+
+	var callCount atomic.Int32
+	_, err := NewRuntime(Config{
+		Store: &lambdaMockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if callCount.Load() != 0 {
+		t.Errorf("LoadFunc called %d times, want 0 without preload", callCount.Load())
+	}
+}
+
 // lambdaMockStore for Lambda tests
 type lambdaMockStore struct{}
 
@@ -6,11 +6,17 @@ package ghappsetup
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/metrics"
 )
 
 func TestNewRuntime_RequiresLoadFunc(t *testing.T) {
@@ -150,6 +156,102 @@ func TestRuntime_IsReady(t *testing.T) {
 	}
 }
 
+func TestRuntime_Shared(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	type key struct{}
+	var calls int
+	newValue := func() any {
+		calls++
+		return new(int)
+	}
+	first := runtime.Shared(key{}, newValue)
+	if second := runtime.Shared(key{}, newValue); second != first || calls != 1 {
+		t.Errorf("Shared() created %d values, want one value reused", calls)
+	}
+}
+
+func TestRuntime_WaitReady(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runtime.WaitReady(context.Background())
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("WaitReady() returned before runtime was ready")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	runtime.setReady(true)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WaitReady() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("WaitReady() did not return after setReady(true)")
+	}
+
+	select {
+	case <-runtime.ReadyChan():
+	default:
+		t.Error("ReadyChan() should be closed once ready")
+	}
+}
+
+func TestRuntime_WaitReady_ContextCanceled(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := runtime.WaitReady(ctx); err != context.DeadlineExceeded {
+		t.Errorf("WaitReady() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRuntime_ReadyChan_ResetsAfterNotReady(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	runtime.setReady(true)
+	runtime.setReady(true)
+	runtime.setReady(false)
+
+	select {
+	case <-runtime.ReadyChan():
+		t.Error("ReadyChan() should not be closed after setReady(false)")
+	default:
+	}
+}
+
 func TestRuntime_ReloadCallback(t *testing.T) {
 	runtime, err := NewRuntime(Config{
 		Store:    &mockStore{},
@@ -216,7 +318,232 @@ func TestRuntime_Reload_Error(t *testing.T) {
 	}
 }
 
+func TestRuntime_AfterLoad(t *testing.T) {
+	loadErr := errors.New("load failed")
+	var calls []uint64
+	var runtime *Runtime
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			return loadErr
+		},
+		AfterLoad: func(ctx context.Context) {
+			if FromContext(ctx) != runtime {
+				t.Error("AfterLoad context should carry the Runtime")
+			}
+			calls = append(calls, runtime.Generation())
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Reload(context.Background()); err != loadErr {
+		t.Fatalf("Reload() error = %v, want %v", err, loadErr)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("AfterLoad called %d times after a failed load, want 0", len(calls))
+	}
+
+	loadErr = nil
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(calls) != 1 || calls[0] != 1 {
+		t.Errorf("AfterLoad generations = %v, want [1]", calls)
+	}
+}
+
+func TestRuntime_Reload_PanicReleasesSharedCall(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+				panic("boom")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	recovered := make(chan any)
+	go func() {
+		defer func() { recovered <- recover() }()
+		runtime.Reload(context.Background())
+	}()
+	<-started
+
+	waiter := make(chan error)
+	go func() { waiter <- runtime.Reload(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if v := <-recovered; v != "boom" {
+		t.Errorf("recovered %v, want the LoadFunc panic to propagate", v)
+	}
+	if err := <-waiter; err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("waiting Reload() error = %v, want the panic", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runtime.Reload(ctx); err != nil {
+		t.Errorf("Reload() after a panic error = %v, want a fresh load", err)
+	}
+}
+
+func TestRuntime_Reload_CoalescesConcurrentCalls(t *testing.T) {
+	var callCount, running, maxRunning atomic.Int32
+	release := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			callCount.Add(1)
+			n := running.Add(1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			<-release
+			running.Add(-1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- runtime.Reload(context.Background())
+		}()
+	}
+
+	// Give all goroutines time to join the in-flight call
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Reload() error = %v", err)
+		}
+	}
+	if callCount.Load() != 1 {
+		t.Errorf("LoadFunc called %d times, want 1", callCount.Load())
+	}
+	if maxRunning.Load() != 1 {
+		t.Errorf("LoadFunc ran %d times concurrently, want 1", maxRunning.Load())
+	}
+}
+
+func TestRuntime_Reload_SharesError(t *testing.T) {
+	expectedErr := errors.New("load failed")
+	release := make(chan struct{})
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			<-release
+			return expectedErr
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- runtime.Reload(context.Background()) }()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != expectedErr {
+			t.Errorf("Reload() error = %v, want %v", err, expectedErr)
+		}
+	}
+
+	// A later call starts a fresh LoadFunc invocation
+	release = make(chan struct{})
+	close(release)
+	if err := runtime.Reload(context.Background()); err != expectedErr {
+		t.Errorf("Reload() error = %v, want %v", err, expectedErr)
+	}
+}
+
 // mockStore is a minimal Store implementation for testing.
+// recordingSink records metric values by name.
+type recordingSink struct {
+	mu     sync.Mutex
+	values map[string][]float64
+}
+
+func (s *recordingSink) Record(name string, value float64, unit metrics.Unit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string][]float64)
+	}
+	s.values[name] = append(s.values[name], value)
+}
+
+func (s *recordingSink) count(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.values[name])
+}
+
+func TestRuntime_Metrics(t *testing.T) {
+	clearPlatformEnv(t)
+	sink := &recordingSink{}
+	var fail atomic.Bool
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if fail.Load() {
+				return errors.New("load failed")
+			}
+			return nil
+		},
+		Metrics: sink,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	handler := runtime.Handler(http.NotFoundHandler())
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	fail.Store(true)
+	runtime.Reload(context.Background())
+
+	for name, want := range map[string]int{
+		metrics.LoadDuration:  2,
+		metrics.Reloads:       1,
+		metrics.LoadFailures:  1,
+		metrics.GatedRequests: 1,
+	} {
+		if got := sink.count(name); got != want {
+			t.Errorf("%s recorded %d times, want %d", name, got, want)
+		}
+	}
+}
+
 type mockStore struct{}
 
 func (m *mockStore) Save(ctx context.Context, creds *configstore.AppCredentials) error {
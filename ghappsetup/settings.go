@@ -0,0 +1,106 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configmap"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// applySettingsDefaults fills zero-valued retry and reload Config fields
+// from the settings file.
+func applySettingsDefaults(cfg *Config, s *configmap.Settings) {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = s.MaxRetries
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = time.Duration(s.RetryInterval)
+	}
+	if cfg.MaxWait == 0 {
+		cfg.MaxWait = time.Duration(s.MaxWait)
+	}
+	if cfg.ReloadCooldown == 0 {
+		cfg.ReloadCooldown = time.Duration(s.ReloadCooldown)
+	}
+}
+
+// Settings returns the settings most recently read from
+// Config.SettingsFile, or nil if no settings file is configured. The
+// result must not be modified.
+func (r *Runtime) Settings() *configmap.Settings {
+	if r.settings == nil {
+		return nil
+	}
+	return r.settings.Settings()
+}
+
+// allowedPaths returns Config.AllowedPaths, the paths registered by
+// MountInstaller, and the allowed paths of the settings file. Callers
+// other than NewRuntime must hold pathsMu.
+func (r *Runtime) allowedPaths() []string {
+	paths := slices.Concat(r.config.AllowedPaths, r.mountedPaths)
+	if s := r.Settings(); s != nil {
+		paths = append(paths, s.AllowedPaths...)
+	}
+	return paths
+}
+
+// gatedPaths returns Config.GatedPaths and the gated paths of the settings
+// file.
+func (r *Runtime) gatedPaths() []string {
+	paths := slices.Clone(r.config.GatedPaths)
+	if s := r.Settings(); s != nil {
+		paths = append(paths, s.GatedPaths...)
+	}
+	return paths
+}
+
+// allowPaths adds paths that are always allowed through the ReadyGate,
+// keeping them across settings file changes.
+func (r *Runtime) allowPaths(paths ...string) {
+	if r.gate == nil {
+		return
+	}
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+	r.mountedPaths = append(r.mountedPaths, paths...)
+	r.gate.AllowPaths(paths...)
+}
+
+// applySettings applies a changed settings file to the ReadyGate. The
+// manifest is read on every installer request and needs no update.
+func (r *Runtime) applySettings(*configmap.Settings) {
+	if r.gate == nil {
+		return
+	}
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+	r.gate.SetAllowedPaths(r.allowedPaths()...)
+	r.gate.SetGatedPaths(r.gatedPaths()...)
+}
+
+// settingsManifest returns the manifest of the settings file, or nil if it
+// has none, for installer.Config.ManifestFunc.
+func (r *Runtime) settingsManifest() *installer.Manifest {
+	if s := r.Settings(); s != nil {
+		return s.Manifest
+	}
+	return nil
+}
+
+// watchSettings watches the settings file, if any, until ctx is canceled.
+func (r *Runtime) watchSettings(ctx context.Context) {
+	if r.settings == nil {
+		return
+	}
+	if err := r.settings.Run(ctx); err != nil {
+		clog.FromContext(ctx).Errorf("[ghappsetup] settings file changes will not be applied: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+func writeSettingsFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewRuntime_SettingsFile(t *testing.T) {
+	clearPlatformEnv(t)
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeSettingsFile(t, path, "max_retries: 7\nretry_interval: 3s\nmax_wait: 1m\nreload_cooldown: 10s\n")
+
+	t.Run("fills unset fields", func(t *testing.T) {
+		t.Setenv(EnvSettingsFile, path)
+		runtime, err := NewRuntime(Config{
+			Store:    &mockStore{},
+			LoadFunc: func(ctx context.Context) error { return nil },
+		})
+		if err != nil {
+			t.Fatalf("NewRuntime() error = %v", err)
+		}
+		cfg := runtime.config
+		if cfg.MaxRetries != 7 || cfg.RetryInterval != 3*time.Second || cfg.MaxWait != time.Minute || cfg.ReloadCooldown != 10*time.Second {
+			t.Errorf("config = %+v, want settings file values", cfg)
+		}
+	})
+
+	t.Run("config and environment take precedence", func(t *testing.T) {
+		t.Setenv(EnvRetryInterval, "5s")
+		runtime, err := NewRuntime(Config{
+			Store:        &mockStore{},
+			LoadFunc:     func(ctx context.Context) error { return nil },
+			SettingsFile: path,
+			MaxRetries:   2,
+		})
+		if err != nil {
+			t.Fatalf("NewRuntime() error = %v", err)
+		}
+		if runtime.config.MaxRetries != 2 || runtime.config.RetryInterval != 5*time.Second {
+			t.Errorf("MaxRetries = %d, RetryInterval = %v, want 2 and 5s", runtime.config.MaxRetries, runtime.config.RetryInterval)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "settings.yaml")
+		writeSettingsFile(t, bad, "max_retries: many\n")
+		_, err := NewRuntime(Config{
+			Store:        &mockStore{},
+			LoadFunc:     func(ctx context.Context) error { return nil },
+			SettingsFile: bad,
+		})
+		if err == nil || !strings.Contains(err.Error(), "settings file") {
+			t.Errorf("NewRuntime() error = %v, want settings file error", err)
+		}
+	})
+}
+
+func TestRuntime_SettingsFileHotReload(t *testing.T) {
+	clearPlatformEnv(t)
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeSettingsFile(t, path, "allowed_paths: [/status]\nmanifest:\n  name: first-app\n")
+
+	runtime, err := NewRuntime(Config{
+		Store:        &mockStore{},
+		LoadFunc:     func(ctx context.Context) error { return nil },
+		AllowedPaths: []string{"/healthz"},
+		SettingsFile: path,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	setup, err := runtime.InstallerHandler(installer.Config{Manifest: installer.Manifest{Name: "static-app"}})
+	if err != nil {
+		t.Fatalf("InstallerHandler() error = %v", err)
+	}
+	mux.Handle("/setup", setup)
+	runtime.allowPaths("/setup")
+	handler := runtime.Handler(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	for _, path := range []string{"/healthz", "/status", "/setup"} {
+		if code := get(path).Code; code != http.StatusOK {
+			t.Errorf("GET %s status = %d before change, want 200", path, code)
+		}
+	}
+	if body := get("/setup").Body.String(); !strings.Contains(body, "first-app") {
+		t.Error("installer does not offer the settings file manifest")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := runtime.StartReloadListener(ctx)
+	defer listener.Stop()
+	defer cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	writeSettingsFile(t, path, "allowed_paths: [/metrics]\nmanifest:\n  name: second-app\n")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for get("/metrics").Code != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("settings file change was not applied")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if code := get("/status").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("GET /status status = %d after change, want 503", code)
+	}
+	for _, path := range []string{"/healthz", "/setup"} {
+		if code := get(path).Code; code != http.StatusOK {
+			t.Errorf("GET %s status = %d after change, want 200", path, code)
+		}
+	}
+	if body := get("/setup").Body.String(); !strings.Contains(body, "second-app") {
+		t.Error("installer does not offer the changed manifest")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import "time"
+
+// BeginShutdown starts draining the runtime ahead of a graceful shutdown.
+// It immediately marks the runtime as not ready in Health and
+// HealthHandler so load balancer health checks fail, while requests keep
+// being served. The returned channel is closed once Config.DrainPeriod has
+// elapsed, signaling that the server can be shut down without dropping
+// requests still routed to it. RunHTTP calls BeginShutdown automatically.
+//
+// BeginShutdown is idempotent; later calls return the same channel.
+func (r *Runtime) BeginShutdown() <-chan struct{} {
+	r.drainOnce.Do(func() {
+		r.draining.Store(true)
+		go func() {
+			if r.config.DrainPeriod > 0 {
+				time.Sleep(r.config.DrainPeriod)
+			}
+			close(r.drained)
+		}()
+	})
+	return r.drained
+}
+
+// IsShuttingDown returns true once BeginShutdown has been called.
+func (r *Runtime) IsShuttingDown() bool {
+	return r.draining.Load()
+}
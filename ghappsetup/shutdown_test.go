@@ -0,0 +1,80 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRuntime_BeginShutdown(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:       &mockStore{},
+		LoadFunc:    func(ctx context.Context) error { return nil },
+		DrainPeriod: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	runtime.setReady(true)
+
+	start := time.Now()
+	done := runtime.BeginShutdown()
+	if again := runtime.BeginShutdown(); again != done {
+		t.Error("BeginShutdown() should return the same channel on later calls")
+	}
+	if !runtime.IsShuttingDown() {
+		t.Error("IsShuttingDown() should be true after BeginShutdown()")
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d while draining", rec.Code, http.StatusServiceUnavailable)
+	}
+	if want := "not ready: draining"; rec.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rec.Body.String(), want)
+	}
+
+	// Requests are still served while draining
+	handler := runtime.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d for requests while draining", rec.Code, http.StatusOK)
+	}
+
+	select {
+	case <-done:
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("drain completed after %v, want at least 50ms", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BeginShutdown() did not signal completion")
+	}
+}
+
+func TestRuntime_BeginShutdown_NoDrainPeriod(t *testing.T) {
+	runtime, err := NewRuntime(Config{
+		Store:    &mockStore{},
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	select {
+	case <-runtime.BeginShutdown():
+	case <-time.After(time.Second):
+		t.Fatal("BeginShutdown() should complete immediately without DrainPeriod")
+	}
+}
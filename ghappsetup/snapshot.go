@@ -0,0 +1,82 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"maps"
+	"os"
+	"strings"
+)
+
+// envSnapshot records the environment variables written by loads.
+// Configuration in this library is delivered through environment variables
+// (the envfile store and ssmresolver both write to the process
+// environment), so the values written by successful loads are the
+// last-known-good configuration. Variables no load wrote belong to the
+// rest of the process and are never recorded or restored.
+type envSnapshot map[string]envValue
+
+// envValue is the recorded value of an environment variable; set is false
+// if the variable was unset.
+type envValue struct {
+	value string
+	set   bool
+}
+
+// captureEnv returns a copy of the current process environment.
+func captureEnv() map[string]string {
+	env := os.Environ()
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// writtenSince returns the variables set, changed, or unset since before
+// was captured, with their current values.
+func writtenSince(before map[string]string) envSnapshot {
+	after := captureEnv()
+	written := make(envSnapshot)
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			written[k] = envValue{value: v, set: true}
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			written[k] = envValue{}
+		}
+	}
+	return written
+}
+
+// merge records the values in written, replacing earlier values of the
+// same variables.
+func (s envSnapshot) merge(written envSnapshot) envSnapshot {
+	if s == nil {
+		s = make(envSnapshot, len(written))
+	}
+	maps.Copy(s, written)
+	return s
+}
+
+// restore resets the variables a failed load wrote. Variables recorded in
+// the snapshot get their last-known-good value; others get the value they
+// had in before, the environment captured when the failed load started.
+func (s envSnapshot) restore(written envSnapshot, before map[string]string) {
+	for k := range written {
+		v, ok := s[k]
+		if !ok {
+			v.value, v.set = before[k]
+		}
+		if v.set {
+			_ = os.Setenv(k, v.value)
+		} else {
+			_ = os.Unsetenv(k)
+		}
+	}
+}
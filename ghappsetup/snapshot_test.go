@@ -0,0 +1,185 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghappsetup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRuntime_RetainLastKnownGood(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+	t.Setenv("GHAPPSETUP_TEST_APP_ID", "")
+	t.Setenv("GHAPPSETUP_TEST_PARTIAL", "")
+	os.Unsetenv("GHAPPSETUP_TEST_PARTIAL")
+
+	fail := false
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if fail {
+				os.Setenv("GHAPPSETUP_TEST_APP_ID", "999")
+				os.Setenv("GHAPPSETUP_TEST_PARTIAL", "half-applied")
+				return errors.New("failed partway")
+			}
+			os.Setenv("GHAPPSETUP_TEST_APP_ID", "123")
+			return nil
+		},
+		MaxRetries:          1,
+		RetryInterval:       10 * time.Millisecond,
+		RetainLastKnownGood: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if runtime.IsDegraded() {
+		t.Error("IsDegraded() should be false after successful start")
+	}
+
+	fail = true
+	if err := runtime.Reload(context.Background()); err == nil {
+		t.Fatal("Reload() should return error")
+	}
+
+	if got := os.Getenv("GHAPPSETUP_TEST_APP_ID"); got != "123" {
+		t.Errorf("GHAPPSETUP_TEST_APP_ID = %q, want %q after rollback", got, "123")
+	}
+	if _, ok := os.LookupEnv("GHAPPSETUP_TEST_PARTIAL"); ok {
+		t.Error("GHAPPSETUP_TEST_PARTIAL should be unset after rollback")
+	}
+	if !runtime.IsDegraded() {
+		t.Error("IsDegraded() should be true after failed reload")
+	}
+	if !runtime.IsReady() {
+		t.Error("IsReady() should remain true after failed reload")
+	}
+
+	rec := httptest.NewRecorder()
+	runtime.HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "degraded" {
+		t.Errorf("HealthHandler() = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusOK, "degraded")
+	}
+
+	fail = false
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if runtime.IsDegraded() {
+		t.Error("IsDegraded() should clear after successful reload")
+	}
+}
+
+func TestRuntime_RetainLastKnownGood_OnlyRestoresWrittenVars(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+	t.Setenv("GHAPPSETUP_TEST_APP_ID", "")
+	t.Setenv("GHAPPSETUP_TEST_PREEXISTING", "original")
+	t.Setenv("GHAPPSETUP_TEST_UNRELATED", "")
+
+	fail := false
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if fail {
+				os.Setenv("GHAPPSETUP_TEST_APP_ID", "999")
+				os.Setenv("GHAPPSETUP_TEST_PREEXISTING", "half-applied")
+				return errors.New("failed partway")
+			}
+			os.Setenv("GHAPPSETUP_TEST_APP_ID", "123")
+			return nil
+		},
+		MaxRetries:          1,
+		RetryInterval:       10 * time.Millisecond,
+		RetainLastKnownGood: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Changed by the rest of the process after the successful load
+	os.Setenv("GHAPPSETUP_TEST_UNRELATED", "changed")
+
+	fail = true
+	if err := runtime.Reload(context.Background()); err == nil {
+		t.Fatal("Reload() should return error")
+	}
+
+	if got := os.Getenv("GHAPPSETUP_TEST_APP_ID"); got != "123" {
+		t.Errorf("GHAPPSETUP_TEST_APP_ID = %q, want %q after rollback", got, "123")
+	}
+	if got := os.Getenv("GHAPPSETUP_TEST_PREEXISTING"); got != "original" {
+		t.Errorf("GHAPPSETUP_TEST_PREEXISTING = %q, want %q after rollback", got, "original")
+	}
+	if got := os.Getenv("GHAPPSETUP_TEST_UNRELATED"); got != "changed" {
+		t.Errorf("GHAPPSETUP_TEST_UNRELATED = %q, want %q left alone", got, "changed")
+	}
+}
+
+func TestRuntime_DegradedWithoutRetention(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+	t.Setenv("GHAPPSETUP_TEST_APP_ID", "")
+
+	fail := false
+	runtime, err := NewRuntime(Config{
+		Store: &mockStore{},
+		LoadFunc: func(ctx context.Context) error {
+			if fail {
+				os.Setenv("GHAPPSETUP_TEST_APP_ID", "999")
+				return errors.New("failed partway")
+			}
+			return nil
+		},
+		MaxRetries:    1,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	if err := runtime.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	fail = true
+	_ = runtime.Reload(context.Background())
+
+	if !runtime.IsDegraded() {
+		t.Error("IsDegraded() should be true after failed reload")
+	}
+	if got := os.Getenv("GHAPPSETUP_TEST_APP_ID"); got != "999" {
+		t.Errorf("GHAPPSETUP_TEST_APP_ID = %q, want %q without retention", got, "999")
+	}
+}
+
+func TestRuntime_NotDegradedBeforeFirstLoad(t *testing.T) {
+	os.Unsetenv("AWS_LAMBDA_FUNCTION_NAME")
+
+	runtime, err := NewRuntime(Config{
+		Store:               &mockStore{},
+		LoadFunc:            func(ctx context.Context) error { return errors.New("not ready") },
+		MaxRetries:          1,
+		RetryInterval:       10 * time.Millisecond,
+		RetainLastKnownGood: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	_ = runtime.Start(context.Background())
+
+	if runtime.IsDegraded() {
+		t.Error("IsDegraded() should be false when nothing has loaded yet")
+	}
+}
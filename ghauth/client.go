@@ -0,0 +1,81 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+)
+
+// tokenSourceKey is the Runtime.Shared key of the TokenSource shared by
+// every client created for a Runtime.
+type tokenSourceKey struct{}
+
+// TokenSourceFor returns the TokenSource shared by clients of the Runtime,
+// so they reuse each other's cached installation tokens. It reads the
+// Runtime's loaded credentials from the environment, and drops cached
+// tokens and derives the API URLs again whenever the Runtime reloads.
+func TokenSourceFor(r *ghappsetup.Runtime) *TokenSource {
+	return r.Shared(tokenSourceKey{}, func() any {
+		return NewTokenSource(WithRuntime(r))
+	}).(*TokenSource)
+}
+
+// NewClient returns a go-github client for the Runtime's GitHub App,
+// authenticated as the given installation, or as the app itself if
+// installationID is zero (see Transport for how each request is
+// authenticated). The API URLs follow GITHUB_URL or the stored app HTML
+// URL as of the last load, so GitHub Enterprise Server needs no extra
+// configuration; a client keeps the URLs it was created with, so create
+// clients after reloads that change them. Clients for the same Runtime
+// share one token cache. The app credentials must already be loaded.
+func NewClient(ctx context.Context, r *ghappsetup.Runtime, installationID int64) (*github.Client, error) {
+	if r == nil {
+		return nil, errors.New("ghauth: runtime is required")
+	}
+	src := TokenSourceFor(r)
+	if _, err := src.credentials(ctx); err != nil {
+		return nil, err
+	}
+	return NewClientFromSource(ctx, src, installationID)
+}
+
+// NewClientFromSource returns a go-github client authenticated with src as
+// the given installation, or as the app itself if installationID is zero.
+// The client uses the API and upload URLs of src, so it works for clients
+// outside a Runtime, such as one built from StaticCredentials.
+func NewClientFromSource(ctx context.Context, src *TokenSource, installationID int64) (*github.Client, error) {
+	if src == nil {
+		return nil, errors.New("ghauth: token source is required")
+	}
+	baseURL, uploadURL, err := src.apiURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := github.NewClient(&http.Client{
+		Transport: NewTransport(src, installationID),
+		Timeout:   httpClientTimeout,
+	})
+	if baseURL == DefaultAPIURL && uploadURL == DefaultUploadURL {
+		return client, nil
+	}
+
+	base, err := url.Parse(baseURL + "/")
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: invalid base URL %q: %w", baseURL, err)
+	}
+	upload, err := url.Parse(uploadURL + "/")
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: invalid upload URL %q: %w", uploadURL, err)
+	}
+	client.BaseURL, client.UploadURL = base, upload
+	return client, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
+		Store:    configstore.NewLocalEnvFileStore(t.TempDir() + "/.env"),
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	t.Setenv(configstore.EnvGitHubAppID, "")
+	if _, err := NewClient(context.Background(), runtime, 10); err == nil {
+		t.Error("NewClient() should fail before credentials are loaded")
+	}
+
+	t.Setenv(configstore.EnvGitHubAppID, "1")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, pkcs1PEM(testKey(t)))
+	client, err := NewClient(context.Background(), runtime, 10)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := client.BaseURL.String(); got != DefaultAPIURL+"/" {
+		t.Errorf("BaseURL = %q, want %q", got, DefaultAPIURL+"/")
+	}
+	if TokenSourceFor(runtime) != TokenSourceFor(runtime) {
+		t.Error("TokenSourceFor() should return the same source for a Runtime")
+	}
+}
+
+func TestNewClient_URLsFollowReloads(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv(configstore.EnvGitHubAppID, "1")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, pkcs1PEM(testKey(t)))
+	t.Setenv(installer.EnvGitHubURL, "")
+	t.Setenv(configstore.EnvGitHubAppHTMLURL, "")
+
+	var webURL string
+	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
+		Store: configstore.NewLocalEnvFileStore(t.TempDir() + "/.env"),
+		LoadFunc: func(ctx context.Context) error {
+			t.Setenv(installer.EnvGitHubURL, webURL)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+
+	// A client created before the first load must not pin the source to
+	// github.com
+	ctx := context.Background()
+	if _, err := NewClient(ctx, runtime, 10); err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	webURL = "https://github.example.com"
+	if err := runtime.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	client, err := NewClient(ctx, runtime, 10)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got, want := client.BaseURL.String(), "https://github.example.com/api/v3/"; got != want {
+		t.Errorf("BaseURL = %q, want %q", got, want)
+	}
+	if got, want := TokenSourceFor(runtime).UploadURL(), "https://github.example.com/api/uploads"; got != want {
+		t.Errorf("UploadURL() = %q, want %q", got, want)
+	}
+}
+
+func TestNewClientFromSource_RequiresSource(t *testing.T) {
+	if _, err := NewClientFromSource(context.Background(), nil, 0); err == nil {
+		t.Error("NewClientFromSource() should fail without a token source")
+	}
+}
+
+func TestNewClientFromSource_AuthenticatesRequests(t *testing.T) {
+	var issuesAuth, appAuth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/10/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Token{Token: "ghs_install", ExpiresAt: time.Now().Add(time.Hour)})
+	})
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		appAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"id":1,"slug":"my-app"}`))
+	})
+	mux.HandleFunc("GET /repos/o/r/issues", func(w http.ResponseWriter, r *http.Request) {
+		issuesAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	src := NewTokenSource(WithBaseURL(server.URL), WithCredentials(StaticCredentials(1, testKey(t))))
+	client, err := NewClientFromSource(context.Background(), src, 10)
+	if err != nil {
+		t.Fatalf("NewClientFromSource() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if _, _, err := client.Issues.ListByRepo(ctx, "o", "r", nil); err != nil {
+		t.Fatalf("ListByRepo() error = %v", err)
+	}
+	if issuesAuth != "Bearer ghs_install" {
+		t.Errorf("issues Authorization = %q, want installation token", issuesAuth)
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		t.Fatalf("Apps.Get() error = %v", err)
+	}
+	if app.GetSlug() != "my-app" {
+		t.Errorf("app slug = %q, want my-app", app.GetSlug())
+	}
+	if strings.Count(strings.TrimPrefix(appAuth, "Bearer "), ".") != 2 {
+		t.Errorf("app Authorization = %q, want JWT", appAuth)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package ghauth authenticates requests to the GitHub API as a GitHub App.
+// It signs app JWTs with the stored private key and exchanges them for
+// installation access tokens, which are cached per installation and
+// refreshed before they expire.
+package ghauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// DefaultAPIURL is the GitHub API base URL for github.com.
+const DefaultAPIURL = "https://api.github.com"
+
+// Credentials identify a GitHub App for signing app JWTs.
+type Credentials struct {
+	AppID      int64
+	PrivateKey *rsa.PrivateKey
+}
+
+// CredentialsFunc returns the current app credentials. It is called for
+// every JWT, so implementations should be cheap and pick up rotated
+// credentials.
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// EnvCredentials reads the app ID and PEM-encoded private key from the
+// GITHUB_APP_ID and GITHUB_APP_PRIVATE_KEY environment variables, which
+// the configstore backends populate. The parsed key is reused until the
+// variable changes.
+func EnvCredentials() CredentialsFunc {
+	var cached struct {
+		sync.Mutex
+		pem string
+		key *rsa.PrivateKey
+	}
+	return func(ctx context.Context) (Credentials, error) {
+		rawID := strings.TrimSpace(os.Getenv(configstore.EnvGitHubAppID))
+		if rawID == "" {
+			return Credentials{}, fmt.Errorf("%s is not set", configstore.EnvGitHubAppID)
+		}
+		appID, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("invalid %s: %w", configstore.EnvGitHubAppID, err)
+		}
+
+		pemData := os.Getenv(configstore.EnvGitHubAppPrivateKey)
+		if pemData == "" {
+			return Credentials{}, fmt.Errorf("%s is not set", configstore.EnvGitHubAppPrivateKey)
+		}
+
+		cached.Lock()
+		defer cached.Unlock()
+		if pemData != cached.pem {
+			key, err := ParsePrivateKey([]byte(pemData))
+			if err != nil {
+				return Credentials{}, fmt.Errorf("invalid %s: %w", configstore.EnvGitHubAppPrivateKey, err)
+			}
+			cached.pem, cached.key = pemData, key
+		}
+		return Credentials{AppID: appID, PrivateKey: cached.key}, nil
+	}
+}
+
+// StaticCredentials returns a CredentialsFunc that always returns the given
+// app ID and key.
+func StaticCredentials(appID int64, key *rsa.PrivateKey) CredentialsFunc {
+	return func(ctx context.Context) (Credentials, error) {
+		return Credentials{AppID: appID, PrivateKey: key}, nil
+	}
+}
+
+// credentialsID identifies credentials without retaining key material, so
+// caches can tell when the app ID or key has been rotated.
+func credentialsID(c Credentials) string {
+	if c.PrivateKey == nil {
+		return strconv.FormatInt(c.AppID, 10)
+	}
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(&c.PrivateKey.PublicKey))
+	return strconv.FormatInt(c.AppID, 10) + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"sync"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKeyVal  *rsa.PrivateKey
+)
+
+// testKey returns an RSA key shared by the package tests.
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	testKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		testKeyVal = key
+	})
+	return testKeyVal
+}
+
+func pkcs1PEM(key *rsa.PrivateKey) string {
+	return string(EncodePKCS1(key))
+}
+
+func TestEnvCredentials(t *testing.T) {
+	key := testKey(t)
+	t.Setenv(configstore.EnvGitHubAppID, "123")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, pkcs1PEM(key))
+
+	creds, err := EnvCredentials()(context.Background())
+	if err != nil {
+		t.Fatalf("EnvCredentials() error = %v", err)
+	}
+	if creds.AppID != 123 || !creds.PrivateKey.Equal(key) {
+		t.Errorf("EnvCredentials() = app %d, want 123 with the configured key", creds.AppID)
+	}
+
+	t.Setenv(configstore.EnvGitHubAppID, "")
+	if _, err := EnvCredentials()(context.Background()); err == nil {
+		t.Error("EnvCredentials() should fail without GITHUB_APP_ID")
+	}
+}
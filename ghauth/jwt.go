@@ -0,0 +1,99 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultJWTLifetime is how long app JWTs are valid.
+	DefaultJWTLifetime = 9 * time.Minute
+
+	// MaxJWTLifetime is the longest JWT lifetime GitHub accepts. GitHub
+	// rejects JWTs that expire further than this into the future by its
+	// own clock.
+	MaxJWTLifetime = 10 * time.Minute
+
+	// DefaultJWTClockSkew backdates the issued-at time to tolerate clock
+	// drift between this host and GitHub.
+	DefaultJWTClockSkew = 60 * time.Second
+)
+
+// jwtConfig holds the claim timing used by AppJWT.
+type jwtConfig struct {
+	lifetime  time.Duration
+	clockSkew time.Duration
+}
+
+// JWTOption is a functional option for configuring the timing of app JWTs.
+type JWTOption func(*jwtConfig)
+
+// WithJWTLifetime sets how long app JWTs are valid, up to MaxJWTLifetime.
+// The default is DefaultJWTLifetime. Hosts whose clock runs ahead of
+// GitHub's fail with "'Expiration time' claim is too far in the future";
+// a shorter lifetime leaves room for that drift.
+func WithJWTLifetime(d time.Duration) JWTOption {
+	return func(c *jwtConfig) {
+		c.lifetime = d
+	}
+}
+
+// WithJWTClockSkew sets how far the issued-at time is backdated, which
+// tolerates a host clock running behind GitHub's. The default is
+// DefaultJWTClockSkew. It must not be negative or exceed MaxJWTLifetime.
+func WithJWTClockSkew(d time.Duration) JWTOption {
+	return func(c *jwtConfig) {
+		c.clockSkew = d
+	}
+}
+
+// AppJWT returns a JWT authenticating as the GitHub App, signed with RS256.
+// By default it is issued DefaultJWTClockSkew before now and expires
+// DefaultJWTLifetime after now; WithJWTClockSkew and WithJWTLifetime
+// adjust either.
+func AppJWT(creds Credentials, now time.Time, opts ...JWTOption) (string, error) {
+	if creds.AppID <= 0 {
+		return "", errors.New("ghauth: app ID is required")
+	}
+	if creds.PrivateKey == nil {
+		return "", errors.New("ghauth: private key is required")
+	}
+
+	cfg := jwtConfig{lifetime: DefaultJWTLifetime, clockSkew: DefaultJWTClockSkew}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.lifetime <= 0 || cfg.lifetime > MaxJWTLifetime {
+		return "", fmt.Errorf("ghauth: JWT lifetime %s must be positive and at most %s", cfg.lifetime, MaxJWTLifetime)
+	}
+	if cfg.clockSkew < 0 || cfg.clockSkew > MaxJWTLifetime {
+		return "", fmt.Errorf("ghauth: JWT clock skew %s must be between 0 and %s", cfg.clockSkew, MaxJWTLifetime)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iat": now.Add(-cfg.clockSkew).Unix(),
+		"exp": now.Add(cfg.lifetime).Unix(),
+		"iss": strconv.FormatInt(creds.AppID, 10),
+	})
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, creds.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("ghauth: sign JWT: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
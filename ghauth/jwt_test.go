@@ -0,0 +1,120 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppJWT(t *testing.T) {
+	key := testKey(t)
+	now := time.Unix(1700000000, 0)
+
+	token, err := AppJWT(Credentials{AppID: 42, PrivateKey: key}, now)
+	if err != nil {
+		t.Fatalf("AppJWT() error = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("AppJWT() = %q, want three segments", token)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	if claims.Iss != "42" || claims.Iat != now.Unix()-60 || claims.Exp != now.Add(9*time.Minute).Unix() {
+		t.Errorf("claims = %+v, want iss 42, iat now-60s, exp now+9m", claims)
+	}
+}
+
+// jwtClaims decodes the timing claims of a JWT without verifying it.
+func jwtClaims(t *testing.T, token string) (iat, exp int64) {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT = %q, want three segments", token)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Iat int64 `json:"iat"`
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	return claims.Iat, claims.Exp
+}
+
+func TestAppJWT_Timing(t *testing.T) {
+	creds := Credentials{AppID: 42, PrivateKey: testKey(t)}
+	now := time.Unix(1700000000, 0)
+
+	token, err := AppJWT(creds, now, WithJWTClockSkew(2*time.Minute), WithJWTLifetime(5*time.Minute))
+	if err != nil {
+		t.Fatalf("AppJWT() error = %v", err)
+	}
+	if iat, exp := jwtClaims(t, token); iat != now.Unix()-120 || exp != now.Unix()+300 {
+		t.Errorf("iat, exp = %d, %d; want now-2m, now+5m", iat-now.Unix(), exp-now.Unix())
+	}
+
+	invalid := map[string]JWTOption{
+		"lifetime too long": WithJWTLifetime(11 * time.Minute),
+		"zero lifetime":     WithJWTLifetime(0),
+		"negative skew":     WithJWTClockSkew(-time.Second),
+		"skew too large":    WithJWTClockSkew(time.Hour),
+	}
+	for name, opt := range invalid {
+		if _, err := AppJWT(creds, now, opt); err == nil {
+			t.Errorf("%s: AppJWT() should fail", name)
+		}
+	}
+}
+
+func TestTokenSource_JWTOptions(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	src := NewTokenSource(
+		WithCredentials(StaticCredentials(42, testKey(t))),
+		WithJWTOptions(WithJWTLifetime(3*time.Minute)),
+	)
+	src.now = func() time.Time { return now }
+
+	token, err := src.AppJWT(t.Context())
+	if err != nil {
+		t.Fatalf("AppJWT() error = %v", err)
+	}
+	if _, exp := jwtClaims(t, token); exp != now.Add(3*time.Minute).Unix() {
+		t.Errorf("exp = now%+ds, want now+180s", exp-now.Unix())
+	}
+}
+
+func TestAppJWT_RequiresCredentials(t *testing.T) {
+	if _, err := AppJWT(Credentials{PrivateKey: testKey(t)}, time.Now()); err == nil {
+		t.Error("AppJWT() should fail without an app ID")
+	}
+	if _, err := AppJWT(Credentials{AppID: 1}, time.Now()); err == nil {
+		t.Error("AppJWT() should fail without a private key")
+	}
+}
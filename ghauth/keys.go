@@ -0,0 +1,147 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrKeyMismatch is returned by VerifyAppKey when GitHub rejects a JWT
+// signed with the key, i.e. the key does not belong to the app.
+var ErrKeyMismatch = errors.New("ghauth: private key does not match app")
+
+// ParsePrivateKey decodes a PEM-encoded RSA private key in PKCS#1 or
+// PKCS#8 form. Escaped newlines ("\n"), as found in single-line
+// environment variables, are accepted.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	if !strings.Contains(string(data), "\n") {
+		data = []byte(strings.ReplaceAll(string(data), `\n`, "\n"))
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, want RSA", parsed)
+	}
+	return key, nil
+}
+
+// EncodePKCS1 returns key as a PKCS#1 "RSA PRIVATE KEY" PEM block, the
+// format GitHub issues app keys in.
+func EncodePKCS1(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// EncodePKCS8 returns key as a PKCS#8 "PRIVATE KEY" PEM block, as
+// expected by some secret managers and JWT libraries.
+func EncodePKCS8(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to encode PKCS#8 key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ToPKCS1 converts a PEM-encoded private key in either form to PKCS#1.
+func ToPKCS1(data []byte) ([]byte, error) {
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: %w", err)
+	}
+	return EncodePKCS1(key), nil
+}
+
+// ToPKCS8 converts a PEM-encoded private key in either form to PKCS#8.
+func ToPKCS8(data []byte) ([]byte, error) {
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: %w", err)
+	}
+	return EncodePKCS8(key)
+}
+
+// Fingerprint returns the key's SHA256 fingerprint as GitHub displays it
+// in the app's settings ("SHA256:" followed by the base64 SHA-256 digest
+// of the DER-encoded public key), so a stored key can be matched to the
+// one listed on GitHub.
+func Fingerprint(key *rsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("ghauth: failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// VerifyAppKey checks against the GitHub API that key belongs to the app
+// by fetching the app with a JWT signed by it. It returns ErrKeyMismatch
+// if GitHub rejects the JWT, e.g. for a revoked key or the wrong app ID.
+// Options such as WithGitHubURL and WithHTTPClient select the GitHub
+// instance; any WithCredentials option is ignored.
+func VerifyAppKey(ctx context.Context, appID int64, key *rsa.PrivateKey, opts ...TokenSourceOption) error {
+	opts = append(opts, WithCredentials(StaticCredentials(appID, key)))
+	src := NewTokenSource(opts...)
+	jwt, err := src.AppJWT(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.BaseURL()+"/app", nil)
+	if err != nil {
+		return fmt.Errorf("ghauth: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	resp, err := src.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ghauth: failed to call GitHub API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("ghauth: failed to read response: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w %d: %s", ErrKeyMismatch, appID, string(body))
+	default:
+		return fmt.Errorf("ghauth: GitHub API returned %d for app %d: %s", resp.StatusCode, appID, string(body))
+	}
+
+	var app struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(body, &app); err != nil {
+		return fmt.Errorf("ghauth: failed to parse response: %w", err)
+	}
+	if app.ID != appID {
+		return fmt.Errorf("%w %d: GitHub returned app %d", ErrKeyMismatch, appID, app.ID)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// DefaultUserTokenRefreshBefore is how long before expiry a UserTokenSource
+// refreshes an expiring user access token.
+const DefaultUserTokenRefreshBefore = 5 * time.Minute
+
+// UserToken is a user-to-server access token from the app's OAuth web
+// flow. ExpiresAt and the refresh token are only set when the app has
+// expiring user tokens enabled.
+type UserToken struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type,omitempty"`
+	Scope                 string    `json:"scope,omitempty"`
+	ExpiresAt             time.Time `json:"expires_at,omitzero"`
+	RefreshToken          string    `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at,omitzero"`
+}
+
+// OAuthError is an error returned by GitHub's OAuth token endpoint, such
+// as "bad_verification_code" for an expired or reused code.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	URI         string `json:"error_uri"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("ghauth: oauth error %s: %s", e.Code, e.Description)
+	}
+	return "ghauth: oauth error " + e.Code
+}
+
+// OAuth implements the GitHub App OAuth web flow for user-to-server
+// tokens: building the authorize URL, exchanging the callback code, and
+// refreshing expiring tokens.
+type OAuth struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	webURL       string
+	client       *http.Client
+	now          func() time.Time
+}
+
+// OAuthOption is a functional option for configuring OAuth.
+type OAuthOption func(*OAuth)
+
+// WithOAuthClientCredentials sets the app's OAuth client ID and secret.
+// By default they are read from GITHUB_CLIENT_ID and GITHUB_CLIENT_SECRET
+// on every call, so reloaded credentials are picked up.
+func WithOAuthClientCredentials(clientID, clientSecret string) OAuthOption {
+	return func(o *OAuth) {
+		o.clientID, o.clientSecret = clientID, clientSecret
+	}
+}
+
+// WithOAuthRedirectURL sets the redirect_uri sent with authorize and
+// token requests. It must match one of the app's callback URLs. If unset,
+// GitHub uses the app's first callback URL.
+func WithOAuthRedirectURL(u string) OAuthOption {
+	return func(o *OAuth) {
+		o.redirectURL = u
+	}
+}
+
+// WithOAuthGitHubURL sets the GitHub web URL hosting the OAuth endpoints
+// (e.g. "https://github.example.com"). The default follows GITHUB_URL or
+// the origin of the stored GITHUB_APP_HTML_URL, then github.com.
+func WithOAuthGitHubURL(webURL string) OAuthOption {
+	return func(o *OAuth) {
+		o.webURL = strings.TrimRight(webURL, "/")
+	}
+}
+
+// WithOAuthHTTPClient sets the HTTP client used for token requests.
+func WithOAuthHTTPClient(c *http.Client) OAuthOption {
+	return func(o *OAuth) {
+		o.client = c
+	}
+}
+
+// NewOAuth creates an OAuth helper for the app's web flow.
+func NewOAuth(opts ...OAuthOption) *OAuth {
+	o := &OAuth{
+		client: &http.Client{Timeout: httpClientTimeout},
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.webURL == "" {
+		o.webURL = envWebURL()
+	}
+	return o
+}
+
+// envWebURL returns the origin of GITHUB_URL or the stored app HTML URL,
+// falling back to github.com.
+func envWebURL() string {
+	for _, key := range []string{installer.EnvGitHubURL, configstore.EnvGitHubAppHTMLURL} {
+		if u, err := url.Parse(strings.TrimSpace(os.Getenv(key))); err == nil && u.Scheme != "" && u.Host != "" {
+			return u.Scheme + "://" + u.Host
+		}
+	}
+	return DefaultWebURL
+}
+
+// credentials returns the configured client ID and secret, or those in
+// the environment.
+func (o *OAuth) credentials() (clientID, clientSecret string, err error) {
+	clientID, clientSecret = o.clientID, o.clientSecret
+	if clientID == "" {
+		clientID = os.Getenv(configstore.EnvGitHubClientID)
+	}
+	if clientSecret == "" {
+		clientSecret = os.Getenv(configstore.EnvGitHubClientSecret)
+	}
+	if clientID == "" || clientSecret == "" {
+		return "", "", fmt.Errorf("ghauth: %s and %s are required for OAuth",
+			configstore.EnvGitHubClientID, configstore.EnvGitHubClientSecret)
+	}
+	return clientID, clientSecret, nil
+}
+
+// NewOAuthState returns a random value for the authorize request's state
+// parameter. Store it (e.g. in a cookie) and compare it with the state
+// returned to the callback to prevent cross-site request forgery.
+func NewOAuthState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("ghauth: failed to generate state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthorizeURL returns the URL that sends the user to GitHub to authorize
+// the app. GitHub redirects back to the callback URL with the code and
+// state query parameters.
+func (o *OAuth) AuthorizeURL(state string) (string, error) {
+	clientID, _, err := o.credentials()
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{"client_id": {clientID}}
+	if state != "" {
+		q.Set("state", state)
+	}
+	if o.redirectURL != "" {
+		q.Set("redirect_uri", o.redirectURL)
+	}
+	return o.webURL + "/login/oauth/authorize?" + q.Encode(), nil
+}
+
+// Exchange trades the code from the OAuth callback for a user token.
+func (o *OAuth) Exchange(ctx context.Context, code string) (*UserToken, error) {
+	if code == "" {
+		return nil, errors.New("ghauth: oauth code is required")
+	}
+	form := url.Values{"code": {code}}
+	if o.redirectURL != "" {
+		form.Set("redirect_uri", o.redirectURL)
+	}
+	return o.token(ctx, form)
+}
+
+// Refresh trades a refresh token for a new user token. GitHub invalidates
+// the old refresh token, so persist the returned one.
+func (o *OAuth) Refresh(ctx context.Context, refreshToken string) (*UserToken, error) {
+	if refreshToken == "" {
+		return nil, errors.New("ghauth: refresh token is required")
+	}
+	return o.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// token calls the OAuth token endpoint with the client credentials added
+// to form.
+func (o *OAuth) token(ctx context.Context, form url.Values) (*UserToken, error) {
+	clientID, clientSecret, err := o.credentials()
+	if err != nil {
+		return nil, err
+	}
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.webURL+"/login/oauth/access_token",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to call GitHub OAuth endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ghauth: GitHub OAuth endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	// GitHub reports OAuth errors with a 200 status and an error field.
+	var result struct {
+		OAuthError
+		AccessToken           string `json:"access_token"`
+		TokenType             string `json:"token_type"`
+		Scope                 string `json:"scope"`
+		ExpiresIn             int64  `json:"expires_in"`
+		RefreshToken          string `json:"refresh_token"`
+		RefreshTokenExpiresIn int64  `json:"refresh_token_expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("ghauth: failed to parse response: %w", err)
+	}
+	if result.Code != "" {
+		oauthErr := result.OAuthError
+		return nil, &oauthErr
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("ghauth: GitHub OAuth endpoint returned an empty token")
+	}
+
+	now := o.now()
+	tok := &UserToken{
+		AccessToken:  result.AccessToken,
+		TokenType:    result.TokenType,
+		Scope:        result.Scope,
+		RefreshToken: result.RefreshToken,
+	}
+	if result.ExpiresIn > 0 {
+		tok.ExpiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	if result.RefreshTokenExpiresIn > 0 {
+		tok.RefreshTokenExpiresAt = now.Add(time.Duration(result.RefreshTokenExpiresIn) * time.Second)
+	}
+	return tok, nil
+}
+
+// UserTokenSource returns a user's access token, refreshing it shortly
+// before it expires. The onRefresh callback, if not nil, receives every
+// refreshed token so it can be persisted; GitHub invalidates the previous
+// refresh token, so the refreshed token is kept even if onRefresh fails.
+// A UserTokenSource is safe for concurrent use.
+type UserTokenSource struct {
+	oauth     *OAuth
+	onRefresh func(ctx context.Context, tok *UserToken) error
+
+	mu    sync.Mutex
+	token *UserToken
+}
+
+// TokenSource returns a UserTokenSource starting from tok.
+func (o *OAuth) TokenSource(tok *UserToken, onRefresh func(ctx context.Context, tok *UserToken) error) *UserTokenSource {
+	return &UserTokenSource{oauth: o, onRefresh: onRefresh, token: tok}
+}
+
+// Token returns a current user token, refreshing it if it expires within
+// DefaultUserTokenRefreshBefore. Tokens without an expiry are returned
+// unchanged. If onRefresh fails, Token returns the refreshed token together
+// with the error, since it is the only token GitHub still accepts.
+func (s *UserTokenSource) Token(ctx context.Context) (*UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tok := s.token
+	if tok == nil {
+		return nil, errors.New("ghauth: no user token")
+	}
+	if tok.ExpiresAt.IsZero() || s.oauth.now().Add(DefaultUserTokenRefreshBefore).Before(tok.ExpiresAt) {
+		return tok, nil
+	}
+	if tok.RefreshToken == "" {
+		return nil, errors.New("ghauth: user token expired and has no refresh token")
+	}
+
+	fresh, err := s.oauth.Refresh(ctx, tok.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	s.token = fresh
+	if s.onRefresh != nil {
+		if err := s.onRefresh(ctx, fresh); err != nil {
+			return fresh, fmt.Errorf("ghauth: failed to persist refreshed user token: %w", err)
+		}
+	}
+	return fresh, nil
+}
+
+// RoundTrip authenticates req with the user token, so a UserTokenSource
+// can be used as an http.Client Transport for user-scoped API calls. The
+// request is sent with the transport of the client set with
+// WithOAuthHTTPClient, or http.DefaultTransport if it has none.
+func (s *UserTokenSource) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok, err := s.Token(req.Context())
+	if tok == nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	if err != nil {
+		// The refreshed token is usable even though it was not persisted
+		clog.FromContext(req.Context()).Warnf("[ghauth] %v", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return s.oauth.transport().RoundTrip(req)
+}
+
+// transport returns the transport of the OAuth HTTP client, or
+// http.DefaultTransport if it has none.
+func (o *OAuth) transport() http.RoundTripper {
+	if o.client != nil && o.client.Transport != nil {
+		return o.client.Transport
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// newOAuthServer fakes GitHub's OAuth token endpoint. Codes and refresh
+// tokens are accepted once each.
+func newOAuthServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var refreshes atomic.Int32
+	used := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/login/oauth/access_token" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("client_id") != "Iv1.abc" || r.Form.Get("client_secret") != "shh" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "incorrect_client_credentials"})
+			return
+		}
+
+		grant := r.Form.Get("code")
+		if r.Form.Get("grant_type") == "refresh_token" {
+			grant = r.Form.Get("refresh_token")
+			refreshes.Add(1)
+		}
+		if grant == "" || used[grant] {
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"error":             "bad_verification_code",
+				"error_description": "The code passed is incorrect or expired.",
+			})
+			return
+		}
+		used[grant] = true
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":             "ghu_" + grant,
+			"token_type":               "bearer",
+			"expires_in":               28800,
+			"refresh_token":            "ghr_" + grant,
+			"refresh_token_expires_in": 15897600,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &refreshes
+}
+
+func TestOAuth_AuthorizeURL(t *testing.T) {
+	t.Setenv(configstore.EnvGitHubClientID, "Iv1.env")
+	t.Setenv(configstore.EnvGitHubClientSecret, "env-secret")
+	t.Setenv(configstore.EnvGitHubAppHTMLURL, "https://github.example.com/github-apps/my-app")
+
+	o := NewOAuth(WithOAuthRedirectURL("https://app.example.com/callback"))
+	got, err := o.AuthorizeURL("xyz")
+	if err != nil {
+		t.Fatalf("AuthorizeURL() error = %v", err)
+	}
+	u, _ := url.Parse(got)
+	if u.Host != "github.example.com" || u.Path != "/login/oauth/authorize" {
+		t.Errorf("AuthorizeURL() = %s, want GHES authorize endpoint", got)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "Iv1.env" || q.Get("state") != "xyz" || q.Get("redirect_uri") != "https://app.example.com/callback" {
+		t.Errorf("AuthorizeURL() query = %v", q)
+	}
+}
+
+func TestOAuth_RequiresClientCredentials(t *testing.T) {
+	t.Setenv(configstore.EnvGitHubClientID, "")
+	t.Setenv(configstore.EnvGitHubClientSecret, "")
+	if _, err := NewOAuth().AuthorizeURL("s"); err == nil {
+		t.Error("AuthorizeURL() should fail without a client ID")
+	}
+}
+
+func TestOAuth_ExchangeAndRefresh(t *testing.T) {
+	srv, _ := newOAuthServer(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := NewOAuth(WithOAuthClientCredentials("Iv1.abc", "shh"), WithOAuthGitHubURL(srv.URL))
+	o.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	tok, err := o.Exchange(ctx, "code1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if tok.AccessToken != "ghu_code1" || tok.RefreshToken != "ghr_code1" || !tok.ExpiresAt.Equal(now.Add(8*time.Hour)) {
+		t.Errorf("Exchange() = %+v", tok)
+	}
+
+	var oauthErr *OAuthError
+	if _, err := o.Exchange(ctx, "code1"); !errors.As(err, &oauthErr) || oauthErr.Code != "bad_verification_code" {
+		t.Errorf("reused code error = %v, want bad_verification_code", err)
+	}
+
+	refreshed, err := o.Refresh(ctx, tok.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if refreshed.AccessToken != "ghu_ghr_code1" {
+		t.Errorf("Refresh() = %+v", refreshed)
+	}
+}
+
+func TestUserTokenSource(t *testing.T) {
+	srv, refreshes := newOAuthServer(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := NewOAuth(WithOAuthClientCredentials("Iv1.abc", "shh"), WithOAuthGitHubURL(srv.URL))
+	o.now = func() time.Time { return now }
+
+	var persisted *UserToken
+	src := o.TokenSource(&UserToken{
+		AccessToken:  "ghu_old",
+		ExpiresAt:    now.Add(time.Hour),
+		RefreshToken: "ghr_old",
+	}, func(ctx context.Context, tok *UserToken) error {
+		persisted = tok
+		return nil
+	})
+
+	ctx := context.Background()
+	if tok, err := src.Token(ctx); err != nil || tok.AccessToken != "ghu_old" {
+		t.Fatalf("Token() = %+v, %v; want the unexpired token", tok, err)
+	}
+
+	now = now.Add(58 * time.Minute)
+	tok, err := src.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok.AccessToken != "ghu_ghr_old" || persisted != tok {
+		t.Errorf("Token() = %+v, persisted %+v; want refreshed token persisted", tok, persisted)
+	}
+	if _, err := src.Token(ctx); err != nil || refreshes.Load() != 1 {
+		t.Errorf("Token() refreshed %d times, want 1", refreshes.Load())
+	}
+}
+
+func TestUserTokenSource_PersistFailureKeepsToken(t *testing.T) {
+	srv, refreshes := newOAuthServer(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := NewOAuth(WithOAuthClientCredentials("Iv1.abc", "shh"), WithOAuthGitHubURL(srv.URL))
+	o.now = func() time.Time { return now }
+
+	persistErr := errors.New("store unavailable")
+	src := o.TokenSource(&UserToken{
+		AccessToken:  "ghu_old",
+		ExpiresAt:    now.Add(time.Minute),
+		RefreshToken: "ghr_old",
+	}, func(ctx context.Context, tok *UserToken) error {
+		return persistErr
+	})
+
+	ctx := context.Background()
+	tok, err := src.Token(ctx)
+	if !errors.Is(err, persistErr) {
+		t.Errorf("Token() error = %v, want the persist error", err)
+	}
+	if tok == nil || tok.AccessToken != "ghu_ghr_old" {
+		t.Fatalf("Token() = %+v, want the refreshed token", tok)
+	}
+
+	// The old refresh token is spent; the source keeps using the new one
+	if tok, err := src.Token(ctx); err != nil || tok.AccessToken != "ghu_ghr_old" {
+		t.Errorf("Token() = %+v, %v; want the kept refreshed token", tok, err)
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes.Load())
+	}
+}
+
+// recordingTransport records the requests it sends with http.DefaultTransport.
+type recordingTransport struct {
+	auth []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.auth = append(rt.auth, req.Header.Get("Authorization"))
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestUserTokenSource_RoundTripUsesClientTransport(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer api.Close()
+
+	rt := &recordingTransport{}
+	o := NewOAuth(WithOAuthClientCredentials("Iv1.abc", "shh"), WithOAuthHTTPClient(&http.Client{Transport: rt}))
+	client := &http.Client{Transport: o.TokenSource(&UserToken{AccessToken: "ghu_abc"}, nil)}
+
+	resp, err := client.Get(api.URL + "/user")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(rt.auth) != 1 || rt.auth[0] != "Bearer ghu_abc" {
+		t.Errorf("client transport saw %q, want one request with the user token", rt.auth)
+	}
+}
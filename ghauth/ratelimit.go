@@ -0,0 +1,269 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+)
+
+const (
+	// DefaultRateLimitRetries is how many times a rate-limited request is
+	// retried.
+	DefaultRateLimitRetries = 3
+
+	// DefaultRateLimitMaxWait is the longest a request sleeps for a rate
+	// limit before the limited response is returned instead.
+	DefaultRateLimitMaxWait = time.Minute
+
+	// DefaultSecondaryBackoff is the first wait after a secondary rate
+	// limit response that has no Retry-After header, doubled on every
+	// further attempt. GitHub asks clients to wait at least a minute.
+	DefaultSecondaryBackoff = time.Minute
+
+	// maxLimitBody bounds how much of a 403 body is read to detect a
+	// secondary rate limit.
+	maxLimitBody = 64 << 10
+)
+
+// RateLimit is the most recently reported quota of a rate limit resource
+// (e.g. "core", "search", or "graphql"), from GitHub's X-RateLimit-*
+// headers.
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RateLimitTransport is an http.RoundTripper that records GitHub rate
+// limit headers and retries requests rejected by primary or secondary rate
+// limits. It sleeps until the reset time or Retry-After, or backs off
+// exponentially for secondary limits that give neither, as long as the wait
+// does not exceed the configured maximum; otherwise the limited response is
+// returned to the caller. Requests whose body cannot be replayed are never
+// retried.
+type RateLimitTransport struct {
+	base             http.RoundTripper
+	retries          int
+	maxWait          time.Duration
+	secondaryBackoff time.Duration
+	onRateLimit      func(RateLimit)
+	now              func() time.Time
+
+	mu     sync.Mutex
+	limits map[string]RateLimit
+}
+
+// RateLimitOption is a functional option for configuring
+// RateLimitTransport.
+type RateLimitOption func(*RateLimitTransport)
+
+// WithRateLimitRetries sets how many times a rate-limited request is
+// retried. The default is DefaultRateLimitRetries.
+func WithRateLimitRetries(n int) RateLimitOption {
+	return func(t *RateLimitTransport) {
+		t.retries = max(n, 0)
+	}
+}
+
+// WithRateLimitMaxWait sets the longest single wait for a rate limit. The
+// default is DefaultRateLimitMaxWait.
+func WithRateLimitMaxWait(d time.Duration) RateLimitOption {
+	return func(t *RateLimitTransport) {
+		t.maxWait = d
+	}
+}
+
+// WithSecondaryBackoff sets the first wait after a secondary rate limit
+// without Retry-After. The default is DefaultSecondaryBackoff.
+func WithSecondaryBackoff(d time.Duration) RateLimitOption {
+	return func(t *RateLimitTransport) {
+		t.secondaryBackoff = d
+	}
+}
+
+// WithOnRateLimit sets a hook called with every rate limit update, e.g.
+// to export remaining quota as a metric.
+func WithOnRateLimit(fn func(RateLimit)) RateLimitOption {
+	return func(t *RateLimitTransport) {
+		t.onRateLimit = fn
+	}
+}
+
+// NewRateLimitTransport wraps base, or http.DefaultTransport if nil. To
+// combine it with authentication, use it as the Transport's base:
+//
+//	rt := ghauth.NewTransport(tokens, id, ghauth.WithBaseTransport(ghauth.NewRateLimitTransport(nil)))
+func NewRateLimitTransport(base http.RoundTripper, opts ...RateLimitOption) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &RateLimitTransport{
+		base:             base,
+		retries:          DefaultRateLimitRetries,
+		maxWait:          DefaultRateLimitMaxWait,
+		secondaryBackoff: DefaultSecondaryBackoff,
+		now:              time.Now,
+		limits:           make(map[string]RateLimit),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RateLimits returns the latest known quota of every rate limit resource
+// seen so far, keyed by resource.
+func (t *RateLimitTransport) RateLimits() map[string]RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]RateLimit, len(t.limits))
+	for k, v := range t.limits {
+		out[k] = v
+	}
+	return out
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	log := clog.FromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp)
+
+		wait, limited := t.limitWait(resp, attempt)
+		if !limited || attempt > t.retries || wait > t.maxWait || !replayable(req) {
+			return resp, nil
+		}
+
+		log.Warnf("[ghauth] rate limited on %s %s (status %d), retrying in %v", req.Method, req.URL.Path, resp.StatusCode, wait)
+		drain(resp)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// record stores the rate limit reported by resp, if any.
+func (t *RateLimitTransport) record(resp *http.Response) {
+	h := resp.Header
+	if h.Get("X-RateLimit-Limit") == "" {
+		return
+	}
+	limit := RateLimit{
+		Resource:  h.Get("X-RateLimit-Resource"),
+		Limit:     headerInt(h, "X-RateLimit-Limit"),
+		Remaining: headerInt(h, "X-RateLimit-Remaining"),
+		Used:      headerInt(h, "X-RateLimit-Used"),
+		Reset:     time.Unix(int64(headerInt(h, "X-RateLimit-Reset")), 0),
+	}
+	if limit.Resource == "" {
+		limit.Resource = "core"
+	}
+
+	t.mu.Lock()
+	t.limits[limit.Resource] = limit
+	t.mu.Unlock()
+	if t.onRateLimit != nil {
+		t.onRateLimit(limit)
+	}
+}
+
+// limitWait reports whether resp was rejected by a rate limit and how long
+// to wait before the next attempt.
+func (t *RateLimitTransport) limitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset := time.Unix(int64(headerInt(resp.Header, "X-RateLimit-Reset")), 0)
+		return max(reset.Sub(t.now()), 0) + time.Second, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || isSecondaryLimit(resp) {
+		return t.secondaryBackoff << (attempt - 1), true
+	}
+	return 0, false
+}
+
+// isSecondaryLimit reports whether a 403 body describes a secondary
+// (abuse) rate limit. The body is restored for the caller.
+func isSecondaryLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxLimitBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse")
+}
+
+// replayable reports whether req can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body for another attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// drain discards and closes a response that will not be returned.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxLimitBody))
+	resp.Body.Close()
+}
+
+// sleepContext waits for d or until ctx is canceled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// headerInt parses an integer header, returning zero if absent or invalid.
+func headerInt(h http.Header, key string) int {
+	n, _ := strconv.Atoi(h.Get(key))
+	return n
+}
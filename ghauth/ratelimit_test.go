@@ -0,0 +1,214 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// limitedResponses returns a base transport that replies with each
+// response in turn, recording the request bodies it was sent.
+func limitedResponses(t *testing.T, bodies *[]string, responses ...func() *http.Response) http.RoundTripper {
+	t.Helper()
+	calls := 0
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			b, _ := io.ReadAll(req.Body)
+			*bodies = append(*bodies, string(b))
+		}
+		if calls >= len(responses) {
+			t.Fatalf("unexpected request %d", calls+1)
+		}
+		resp := responses[calls]()
+		calls++
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+func rateLimitResponse(status int, body string, headers map[string]string) func() *http.Response {
+	return func() *http.Response {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		for k, v := range headers {
+			resp.Header.Set(k, v)
+		}
+		return resp
+	}
+}
+
+func TestRateLimitTransport_RetriesSecondaryLimits(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Unix(), 10)
+	tests := []struct {
+		name  string
+		first func() *http.Response
+	}{
+		{"retry after", rateLimitResponse(http.StatusForbidden, "", map[string]string{"Retry-After": "0"})},
+		{"secondary body", rateLimitResponse(http.StatusForbidden, `{"message":"You have exceeded a secondary rate limit."}`, nil)},
+		{"too many requests", rateLimitResponse(http.StatusTooManyRequests, "", nil)},
+		{"primary exhausted", rateLimitResponse(http.StatusForbidden, "", map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     reset,
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			base := limitedResponses(t, &bodies, tt.first, rateLimitResponse(http.StatusCreated, "ok", nil))
+			rt := NewRateLimitTransport(base, WithSecondaryBackoff(time.Millisecond), WithRateLimitMaxWait(2*time.Second))
+
+			req := httptest.NewRequest(http.MethodPost, "https://api.github.com/repos/o/r/issues", strings.NewReader("payload"))
+			req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("payload")), nil }
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusCreated {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+			}
+			if len(bodies) != 2 || bodies[1] != "payload" {
+				t.Errorf("request bodies = %q, want payload replayed", bodies)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport_ReturnsLimitedResponse(t *testing.T) {
+	farReset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	tests := []struct {
+		name    string
+		opts    []RateLimitOption
+		req     func() *http.Request
+		limited func() *http.Response
+		want    int
+	}{
+		{
+			name: "wait exceeds max",
+			req:  func() *http.Request { return httptest.NewRequest(http.MethodGet, "https://api.github.com/user", nil) },
+			limited: rateLimitResponse(http.StatusForbidden, "", map[string]string{
+				"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": farReset,
+			}),
+			want: 1,
+		},
+		{
+			name: "body not replayable",
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "https://api.github.com/user", strings.NewReader("x"))
+				req.GetBody = nil
+				return req
+			},
+			limited: rateLimitResponse(http.StatusForbidden, "", map[string]string{"Retry-After": "0"}),
+			want:    1,
+		},
+		{
+			name:    "retries exhausted",
+			opts:    []RateLimitOption{WithRateLimitRetries(2)},
+			req:     func() *http.Request { return httptest.NewRequest(http.MethodGet, "https://api.github.com/user", nil) },
+			limited: rateLimitResponse(http.StatusForbidden, "", map[string]string{"Retry-After": "0"}),
+			want:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return tt.limited(), nil
+			})
+			rt := NewRateLimitTransport(base, tt.opts...)
+			resp, err := rt.RoundTrip(tt.req())
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+			}
+			if calls != tt.want {
+				t.Errorf("requests = %d, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport_IgnoresPlainForbidden(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return rateLimitResponse(http.StatusForbidden, `{"message":"Resource not accessible by integration"}`, nil)(), nil
+	})
+	rt := NewRateLimitTransport(base, WithSecondaryBackoff(time.Millisecond))
+
+	resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r", nil))
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if calls != 1 {
+		t.Errorf("requests = %d, want 1", calls)
+	}
+	if !strings.Contains(string(body), "Resource not accessible") {
+		t.Errorf("body = %q, want original body restored", body)
+	}
+}
+
+func TestRateLimitTransport_RecordsRateLimits(t *testing.T) {
+	var updates []RateLimit
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return rateLimitResponse(http.StatusOK, "", map[string]string{
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Remaining": "4990",
+			"X-RateLimit-Used":      "10",
+			"X-RateLimit-Reset":     "1700000000",
+			"X-RateLimit-Resource":  "search",
+		})(), nil
+	})
+	rt := NewRateLimitTransport(base, WithOnRateLimit(func(l RateLimit) { updates = append(updates, l) }))
+
+	resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.github.com/search/code", nil))
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	got, ok := rt.RateLimits()["search"]
+	if !ok {
+		t.Fatalf("RateLimits() = %v, want search resource", rt.RateLimits())
+	}
+	if got.Limit != 5000 || got.Remaining != 4990 || got.Used != 10 || !got.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("RateLimit = %+v", got)
+	}
+	if len(updates) != 1 || updates[0] != got {
+		t.Errorf("OnRateLimit updates = %+v, want one matching update", updates)
+	}
+}
+
+func TestRateLimitTransport_StopsOnContextCancel(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return rateLimitResponse(http.StatusForbidden, "", map[string]string{"Retry-After": "30"})(), nil
+	})
+	rt := NewRateLimitTransport(base)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/user", nil).WithContext(ctx)
+	if _, err := rt.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() error = %v, want context.DeadlineExceeded", err)
+	}
+}
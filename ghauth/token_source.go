@@ -0,0 +1,381 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+const (
+	// DefaultRefreshBefore is how long before expiry a cached installation
+	// token is replaced.
+	DefaultRefreshBefore = 5 * time.Minute
+
+	// apiVersion is the GitHub REST API version requested.
+	apiVersion = "2022-11-28"
+
+	httpClientTimeout = 30 * time.Second
+)
+
+// Token is an installation access token.
+type Token struct {
+	Token               string            `json:"token"`
+	ExpiresAt           time.Time         `json:"expires_at"`
+	Permissions         map[string]string `json:"permissions,omitempty"`
+	RepositorySelection string            `json:"repository_selection,omitempty"`
+}
+
+// TokenSource mints app JWTs and installation access tokens. Installation
+// tokens are cached per installation and replaced once they are within
+// the refresh window of expiring. Concurrent requests for the same
+// installation share a single exchange. A TokenSource is safe for
+// concurrent use.
+//
+// The cache is dropped whenever the credentials change (a different app ID
+// or private key), when Invalidate is called, and when the Runtime set with
+// WithRuntime, or otherwise the one carried by the request context (see
+// ghappsetup.FromContext), has reloaded its configuration since the tokens
+// were minted.
+type TokenSource struct {
+	creds         CredentialsFunc
+	client        *http.Client
+	refreshBefore time.Duration
+	jwtOpts       []JWTOption
+	runtime       *ghappsetup.Runtime
+	metrics       metrics.Sink
+	now           func() time.Time
+
+	mu         sync.Mutex
+	tokens     map[int64]*Token
+	inflight   map[int64]*tokenCall
+	credsID    string
+	generation uint64
+	epoch      uint64 // incremented whenever the cache is dropped
+
+	baseURL   string
+	uploadURL string
+	urlErr    error
+	urlsFixed bool   // set with WithBaseURL or WithGitHubURL
+	urlsGen   uint64 // Runtime generation the URLs were derived at
+}
+
+// tokenCall tracks an in-progress token exchange shared by concurrent
+// callers.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// TokenSourceOption is a functional option for configuring TokenSource.
+type TokenSourceOption func(*TokenSource)
+
+// WithCredentials sets where app credentials come from. The default is
+// EnvCredentials.
+func WithCredentials(fn CredentialsFunc) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.creds = fn
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for token exchanges.
+func WithHTTPClient(c *http.Client) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.client = c
+	}
+}
+
+// WithBaseURL sets the GitHub REST API base URL (e.g.
+// "https://github.example.com/api/v3"). A GitHub Enterprise Server
+// "/api/v3" base implies the matching "/api/uploads" upload URL. The
+// default is derived from the environment; see EnvAPIURLs.
+func WithBaseURL(u string) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.baseURL = strings.TrimRight(u, "/")
+		s.uploadURL = s.baseURL
+		if origin, ok := strings.CutSuffix(s.baseURL, "/api/v3"); ok {
+			s.uploadURL = origin + "/api/uploads"
+		}
+		s.urlErr = nil
+		s.urlsFixed = true
+	}
+}
+
+// WithGitHubURL sets the API and upload base URLs from a GitHub web URL
+// such as "https://github.example.com" (see APIURLs).
+func WithGitHubURL(webURL string) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.baseURL, s.uploadURL, s.urlErr = APIURLs(webURL)
+		s.urlsFixed = true
+	}
+}
+
+// WithRefreshBefore sets how long before expiry a cached installation
+// token is replaced. The default is DefaultRefreshBefore.
+func WithRefreshBefore(d time.Duration) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.refreshBefore = d
+	}
+}
+
+// WithJWTOptions sets the claim timing of the app JWTs the TokenSource
+// signs (see WithJWTLifetime and WithJWTClockSkew).
+func WithJWTOptions(opts ...JWTOption) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.jwtOpts = append(s.jwtOpts, opts...)
+	}
+}
+
+// WithRuntime drops cached tokens whenever the Runtime reloads its
+// configuration, even for requests whose context does not carry it. It
+// takes precedence over the Runtime in the request context.
+func WithRuntime(r *ghappsetup.Runtime) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.runtime = r
+	}
+}
+
+// WithMetrics reports installation token cache hits and misses to sink.
+// Defaults to the sink of the Runtime set with WithRuntime, if any.
+func WithMetrics(sink metrics.Sink) TokenSourceOption {
+	return func(s *TokenSource) {
+		s.metrics = sink
+	}
+}
+
+// NewTokenSource creates a TokenSource. Unless set with WithBaseURL or
+// WithGitHubURL, the API URLs are derived from GITHUB_URL or the stored
+// GITHUB_APP_HTML_URL (see EnvAPIURLs), so the same binary works against
+// github.com and GitHub Enterprise Server. They are derived again whenever
+// the Runtime reloads, like the token cache.
+func NewTokenSource(opts ...TokenSourceOption) *TokenSource {
+	baseURL, uploadURL, urlErr := EnvAPIURLs()
+	s := &TokenSource{
+		creds:         EnvCredentials(),
+		client:        &http.Client{Timeout: httpClientTimeout},
+		baseURL:       baseURL,
+		uploadURL:     uploadURL,
+		urlErr:        urlErr,
+		refreshBefore: DefaultRefreshBefore,
+		now:           time.Now,
+		tokens:        make(map[int64]*Token),
+		inflight:      make(map[int64]*tokenCall),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.metrics == nil && s.runtime != nil {
+		s.metrics = s.runtime.Metrics()
+	}
+	return s
+}
+
+// AppJWT returns a freshly signed JWT authenticating as the app itself,
+// for app-level endpoints such as listing installations.
+func (s *TokenSource) AppJWT(ctx context.Context) (string, error) {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return AppJWT(creds, s.now(), s.jwtOpts...)
+}
+
+// InstallationToken returns an access token for the installation, minting
+// one if none is cached or the cached token is about to expire.
+func (s *TokenSource) InstallationToken(ctx context.Context, installationID int64) (*Token, error) {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, _, err := s.apiURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.checkGeneration(ctx, creds)
+	if tok, ok := s.tokens[installationID]; ok && s.fresh(tok) {
+		s.mu.Unlock()
+		s.recordMetric(metrics.TokenCacheHits)
+		return tok, nil
+	}
+	s.recordMetric(metrics.TokenCacheMisses)
+	call, ok := s.inflight[installationID]
+	if !ok {
+		call = &tokenCall{done: make(chan struct{})}
+		s.inflight[installationID] = call
+		go s.runExchange(ctx, call, baseURL, creds, installationID, s.epoch)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runExchange performs the token exchange shared by every caller waiting
+// on call. It runs detached from the caller that started it, so callers
+// can give up without failing the exchange for the others, and caches the
+// token unless the cache was dropped since epoch.
+func (s *TokenSource) runExchange(ctx context.Context, call *tokenCall, baseURL string, creds Credentials, installationID int64, epoch uint64) {
+	exchangeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), httpClientTimeout)
+	call.token, call.err = s.exchange(exchangeCtx, baseURL, creds, installationID)
+	cancel()
+
+	s.mu.Lock()
+	delete(s.inflight, installationID)
+	// Only cache if the cache was not dropped during the exchange.
+	if call.err == nil && s.epoch == epoch {
+		s.tokens[installationID] = call.token
+	}
+	s.mu.Unlock()
+	close(call.done)
+}
+
+func (s *TokenSource) recordMetric(name string) {
+	if s.metrics != nil {
+		s.metrics.Record(name, 1, metrics.UnitCount)
+	}
+}
+
+// Invalidate drops all cached installation tokens, e.g. after the app's
+// credentials were rotated outside of a Runtime.
+func (s *TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropTokens()
+}
+
+// dropTokens clears the cache. The caller must hold s.mu.
+func (s *TokenSource) dropTokens() {
+	clear(s.tokens)
+	s.epoch++
+}
+
+// BaseURL returns the GitHub REST API base URL tokens are minted from.
+func (s *TokenSource) BaseURL() string {
+	baseURL, _, _ := s.apiURLs(context.Background())
+	return baseURL
+}
+
+// UploadURL returns the GitHub upload API base URL.
+func (s *TokenSource) UploadURL() string {
+	_, uploadURL, _ := s.apiURLs(context.Background())
+	return uploadURL
+}
+
+// apiURLs returns the API and upload base URLs. URLs derived from the
+// environment are derived again once the Runtime has reloaded since, so a
+// reload that changes GITHUB_URL or the app HTML URL takes effect.
+func (s *TokenSource) apiURLs(ctx context.Context) (baseURL, uploadURL string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen, ok := s.runtimeGeneration(ctx); ok && !s.urlsFixed && gen != s.urlsGen {
+		s.baseURL, s.uploadURL, s.urlErr = EnvAPIURLs()
+		s.urlsGen = gen
+	}
+	return s.baseURL, s.uploadURL, s.urlErr
+}
+
+// credentials returns the current app credentials, or the configuration
+// error if the API URLs could not be derived.
+func (s *TokenSource) credentials(ctx context.Context) (Credentials, error) {
+	if _, _, err := s.apiURLs(ctx); err != nil {
+		return Credentials{}, err
+	}
+	creds, err := s.creds(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("ghauth: load credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// checkGeneration drops cached tokens if the credentials changed or a
+// Runtime reloaded since they were minted. The caller must hold s.mu.
+func (s *TokenSource) checkGeneration(ctx context.Context, creds Credentials) {
+	id := credentialsID(creds)
+	gen, ok := s.runtimeGeneration(ctx)
+	if !ok {
+		gen = s.generation
+	}
+
+	if id != s.credsID || gen != s.generation {
+		s.dropTokens()
+		s.credsID = id
+		s.generation = gen
+	}
+}
+
+// runtimeGeneration returns the generation of the Runtime set with
+// WithRuntime, or otherwise the one carried by ctx. It returns false if
+// there is neither.
+func (s *TokenSource) runtimeGeneration(ctx context.Context) (uint64, bool) {
+	if s.runtime != nil {
+		return s.runtime.Generation(), true
+	}
+	if rt := ghappsetup.FromContext(ctx); rt != nil {
+		return rt.Generation(), true
+	}
+	return 0, false
+}
+
+// fresh reports whether tok is outside the refresh window.
+func (s *TokenSource) fresh(tok *Token) bool {
+	return s.now().Add(s.refreshBefore).Before(tok.ExpiresAt)
+}
+
+// exchange creates an installation access token using an app JWT.
+func (s *TokenSource) exchange(ctx context.Context, baseURL string, creds Credentials, installationID int64) (*Token, error) {
+	jwt, err := AppJWT(creds, s.now(), s.jwtOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", baseURL, installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to call GitHub API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ghauth: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("ghauth: GitHub API returned %d for installation %d: %s",
+			resp.StatusCode, installationID, string(body))
+	}
+
+	var tok Token
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return nil, fmt.Errorf("ghauth: failed to parse response: %w", err)
+	}
+	if tok.Token == "" {
+		return nil, errors.New("ghauth: GitHub API returned an empty token")
+	}
+	return &tok, nil
+}
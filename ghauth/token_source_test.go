@@ -0,0 +1,269 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+	"github.com/cruxstack/github-app-setup-go/metrics"
+)
+
+// tokenServer mimics the installation access token endpoint, issuing a new
+// token on every call.
+type tokenServer struct {
+	*httptest.Server
+	calls   atomic.Int32
+	expires time.Duration
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{expires: time.Hour}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/access_tokens") {
+			http.NotFound(w, r)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, "missing JWT", http.StatusUnauthorized)
+			return
+		}
+		n := ts.calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Token{
+			Token:     fmt.Sprintf("ghs_%d", n),
+			ExpiresAt: time.Now().Add(ts.expires),
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func newTestTokenSource(t *testing.T, ts *tokenServer, opts ...TokenSourceOption) *TokenSource {
+	t.Helper()
+	opts = append([]TokenSourceOption{
+		WithBaseURL(ts.URL),
+		WithCredentials(StaticCredentials(1, testKey(t))),
+	}, opts...)
+	return NewTokenSource(opts...)
+}
+
+func TestTokenSource_CachesPerInstallation(t *testing.T) {
+	ts := newTokenServer(t)
+	src := newTestTokenSource(t, ts)
+	ctx := context.Background()
+
+	first, err := src.InstallationToken(ctx, 10)
+	if err != nil {
+		t.Fatalf("InstallationToken() error = %v", err)
+	}
+	again, _ := src.InstallationToken(ctx, 10)
+	if again.Token != first.Token {
+		t.Errorf("InstallationToken() = %q, want cached %q", again.Token, first.Token)
+	}
+	other, _ := src.InstallationToken(ctx, 20)
+	if other.Token == first.Token {
+		t.Error("InstallationToken() should mint a separate token per installation")
+	}
+	if got := ts.calls.Load(); got != 2 {
+		t.Errorf("token exchanges = %d, want 2", got)
+	}
+}
+
+func TestTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	ts := newTokenServer(t)
+	ts.expires = 3 * time.Minute
+	src := newTestTokenSource(t, ts)
+
+	first, _ := src.InstallationToken(context.Background(), 10)
+	second, err := src.InstallationToken(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("InstallationToken() error = %v", err)
+	}
+	if second.Token == first.Token {
+		t.Error("InstallationToken() should refresh a token inside the refresh window")
+	}
+}
+
+func TestTokenSource_Invalidate(t *testing.T) {
+	ts := newTokenServer(t)
+	src := newTestTokenSource(t, ts)
+
+	first, _ := src.InstallationToken(context.Background(), 10)
+	src.Invalidate()
+	second, _ := src.InstallationToken(context.Background(), 10)
+	if second.Token == first.Token {
+		t.Error("InstallationToken() should mint a new token after Invalidate")
+	}
+}
+
+func TestTokenSource_CredentialRotation(t *testing.T) {
+	ts := newTokenServer(t)
+	t.Setenv(configstore.EnvGitHubAppID, "1")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, pkcs1PEM(testKey(t)))
+	src := NewTokenSource(WithBaseURL(ts.URL))
+
+	first, err := src.InstallationToken(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("InstallationToken() error = %v", err)
+	}
+	t.Setenv(configstore.EnvGitHubAppID, "2")
+	second, _ := src.InstallationToken(context.Background(), 10)
+	if second.Token == first.Token {
+		t.Error("InstallationToken() should mint a new token after the app ID changes")
+	}
+}
+
+func TestTokenSource_RuntimeReload(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	ts := newTokenServer(t)
+	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
+		Store:    configstore.NewLocalEnvFileStore(t.TempDir() + "/.env"),
+		LoadFunc: func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	src := newTestTokenSource(t, ts)
+	ctx := ghappsetup.NewContext(context.Background(), runtime)
+
+	first, _ := src.InstallationToken(ctx, 10)
+	if err := runtime.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	second, _ := src.InstallationToken(ctx, 10)
+	if second.Token == first.Token {
+		t.Error("InstallationToken() should mint a new token after the Runtime reloads")
+	}
+}
+
+func TestTokenSource_CoalescesConcurrentCalls(t *testing.T) {
+	ts := newTokenServer(t)
+	src := newTestTokenSource(t, ts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := src.InstallationToken(context.Background(), 10); err != nil {
+				t.Errorf("InstallationToken() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := ts.calls.Load(); got != 1 {
+		t.Errorf("token exchanges = %d, want 1", got)
+	}
+}
+
+func TestTokenSource_CanceledCallerDoesNotFailWaiters(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Token{Token: "ghs_shared", ExpiresAt: time.Now().Add(time.Hour)})
+	}))
+	defer server.Close()
+	src := NewTokenSource(WithBaseURL(server.URL), WithCredentials(StaticCredentials(1, testKey(t))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := src.InstallationToken(ctx, 10)
+		first <- err
+	}()
+	<-started
+
+	waiter := make(chan *Token, 1)
+	go func() {
+		tok, err := src.InstallationToken(context.Background(), 10)
+		if err != nil {
+			t.Errorf("waiter InstallationToken() error = %v", err)
+		}
+		waiter <- tok
+	}()
+
+	// The canceled caller returns without waiting for the exchange
+	cancel()
+	select {
+	case err := <-first:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("canceled InstallationToken() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("canceled caller blocked on the in-flight exchange")
+	}
+	close(release)
+
+	if tok := <-waiter; tok == nil || tok.Token != "ghs_shared" {
+		t.Errorf("waiter token = %+v, want the shared token", tok)
+	}
+}
+
+func TestTokenSource_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	src := NewTokenSource(WithBaseURL(server.URL), WithCredentials(StaticCredentials(1, testKey(t))))
+	_, err := src.InstallationToken(context.Background(), 10)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("InstallationToken() error = %v, want 401 error", err)
+	}
+}
+
+// countingSink counts recorded metrics by name.
+type countingSink struct {
+	mu     sync.Mutex
+	counts map[string]float64
+}
+
+func (s *countingSink) Record(name string, value float64, unit metrics.Unit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[string]float64)
+	}
+	s.counts[name] += value
+}
+
+func TestTokenSource_Metrics(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	sink := &countingSink{}
+	runtime, err := ghappsetup.NewRuntime(ghappsetup.Config{
+		Store:    configstore.NewLocalEnvFileStore(t.TempDir() + "/.env"),
+		LoadFunc: func(ctx context.Context) error { return nil },
+		Metrics:  sink,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntime() error = %v", err)
+	}
+	src := newTestTokenSource(t, newTokenServer(t), WithRuntime(runtime))
+	ctx := context.Background()
+	for range 3 {
+		if _, err := src.InstallationToken(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sink.counts[metrics.TokenCacheHits] != 2 || sink.counts[metrics.TokenCacheMisses] != 1 {
+		t.Errorf("counts = %v, want 2 hits and 1 miss", sink.counts)
+	}
+}
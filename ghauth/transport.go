@@ -0,0 +1,189 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// authKind selects how a request is authenticated.
+type authKind int
+
+const (
+	authDefault authKind = iota
+	authApp
+	authInstallation
+)
+
+type authContextKey struct{}
+
+// authChoice is the per-request authentication override stored in a
+// context.
+type authChoice struct {
+	kind           authKind
+	installationID int64
+}
+
+// ContextWithInstallation returns a context whose requests through a
+// Transport authenticate as the given installation, overriding the
+// Transport's default installation.
+func ContextWithInstallation(ctx context.Context, installationID int64) context.Context {
+	return context.WithValue(ctx, authContextKey{}, authChoice{kind: authInstallation, installationID: installationID})
+}
+
+// ContextAsApp returns a context whose requests through a Transport
+// authenticate as the app itself with a JWT.
+func ContextAsApp(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, authChoice{kind: authApp})
+}
+
+// Transport is an http.RoundTripper that adds an Authorization header to
+// GitHub API requests. For each request it chooses:
+//
+//   - the app JWT if the context came from ContextAsApp, or the endpoint
+//     requires app authentication (/app/... and the /installation lookups
+//     under /repos, /orgs, and /users);
+//   - otherwise an installation token for the installation from
+//     ContextWithInstallation, falling back to the Transport's default
+//     installation, or the app JWT if there is none.
+//
+// Requests that already carry an Authorization header, and requests to
+// hosts other than the token source's API and uploads hosts, such as
+// redirects to asset storage, are sent unchanged so tokens never leave
+// GitHub.
+type Transport struct {
+	source         *TokenSource
+	installationID int64
+	base           http.RoundTripper
+}
+
+// TransportOption is a functional option for configuring Transport.
+type TransportOption func(*Transport)
+
+// WithBaseTransport sets the RoundTripper that sends the authenticated
+// requests. The default is http.DefaultTransport.
+func WithBaseTransport(rt http.RoundTripper) TransportOption {
+	return func(t *Transport) {
+		t.base = rt
+	}
+}
+
+// NewTransport creates a Transport minting tokens from source. Requests
+// authenticate as installationID unless overridden per request; pass zero
+// to authenticate as the app by default. Wrap any HTTP client in one line:
+//
+//	client := &http.Client{Transport: ghauth.NewTransport(tokens, installationID)}
+func NewTransport(source *TokenSource, installationID int64, opts ...TransportOption) *Transport {
+	t := &Transport{
+		source:         source,
+		installationID: installationID,
+		base:           http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	if t.source == nil {
+		closeBody(req)
+		return nil, errors.New("ghauth: transport has no token source")
+	}
+
+	ctx := req.Context()
+	githubHost, err := t.githubHost(req)
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+	if !githubHost {
+		return t.base.RoundTrip(req)
+	}
+
+	var token string
+	if id, ok := t.installationFor(req); ok {
+		var tok *Token
+		if tok, err = t.source.InstallationToken(ctx, id); err == nil {
+			token = tok.Token
+		}
+	} else {
+		token, err = t.source.AppJWT(ctx)
+	}
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	req = req.Clone(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// githubHost reports whether req is sent to the API or uploads host of
+// the token source.
+func (t *Transport) githubHost(req *http.Request) (bool, error) {
+	baseURL, uploadURL, err := t.source.apiURLs(req.Context())
+	if err != nil {
+		return false, err
+	}
+	for _, raw := range []string{baseURL, uploadURL} {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" && strings.EqualFold(u.Host, req.URL.Host) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// installationFor returns the installation to authenticate req as, or false
+// if the request should use the app JWT.
+func (t *Transport) installationFor(req *http.Request) (int64, bool) {
+	choice, _ := req.Context().Value(authContextKey{}).(authChoice)
+	switch {
+	case choice.kind == authApp:
+		return 0, false
+	case requiresAppAuth(req.URL.Path):
+		return 0, false
+	case choice.kind == authInstallation:
+		return choice.installationID, true
+	case t.installationID != 0:
+		return t.installationID, true
+	default:
+		return 0, false
+	}
+}
+
+// requiresAppAuth reports whether the REST endpoint at path only accepts
+// app JWTs. A GitHub Enterprise Server "/api/v3" prefix is ignored.
+func requiresAppAuth(path string) bool {
+	path = strings.TrimPrefix(path, "/api/v3")
+	path = strings.TrimSuffix(path, "/")
+	if path == "/app" || strings.HasPrefix(path, "/app/") {
+		return true
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch {
+	case len(parts) == 4 && parts[0] == "repos" && parts[3] == "installation":
+		return true
+	case len(parts) == 3 && (parts[0] == "orgs" || parts[0] == "users") && parts[2] == "installation":
+		return true
+	}
+	return false
+}
+
+// closeBody closes the request body, as RoundTrippers must even on error.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
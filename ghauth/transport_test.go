@@ -0,0 +1,132 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_ChoosesAuthPerRequest(t *testing.T) {
+	ts := newTokenServer(t)
+	src := newTestTokenSource(t, ts)
+
+	var gotAuth string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotAuth = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	client := &http.Client{Transport: NewTransport(src, 10, WithBaseTransport(base))}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		path    string
+		wantJWT bool
+	}{
+		{"default installation", context.Background(), "/repos/o/r/issues", false},
+		{"app endpoint", context.Background(), "/app/installations", true},
+		{"installation lookup", context.Background(), "/repos/o/r/installation", true},
+		{"GHES app endpoint", context.Background(), "/api/v3/app", true},
+		{"as app", ContextAsApp(context.Background()), "/repos/o/r/issues", true},
+		{"other installation", ContextWithInstallation(context.Background(), 20), "/repos/o/r/issues", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(tt.ctx, http.MethodGet, ts.URL+tt.path, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			token := strings.TrimPrefix(gotAuth, "Bearer ")
+			isJWT := strings.Count(token, ".") == 2
+			if isJWT != tt.wantJWT {
+				t.Errorf("Authorization = %q, want JWT %v", gotAuth, tt.wantJWT)
+			}
+		})
+	}
+
+	if got := ts.calls.Load(); got != 2 {
+		t.Errorf("token exchanges = %d, want 2 (installations 10 and 20)", got)
+	}
+}
+
+func TestTransport_SkipsForeignHosts(t *testing.T) {
+	ts := newTokenServer(t)
+	src := newTestTokenSource(t, ts)
+
+	var gotAuth []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotAuth = append(gotAuth, req.Header.Get("Authorization"))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	rt := NewTransport(src, 10, WithBaseTransport(base))
+
+	for _, target := range []string{
+		"https://objects.githubusercontent.com/release-assets/1",
+		"https://bucket.s3.amazonaws.com/archive.zip",
+	} {
+		if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, target, nil)); err != nil {
+			t.Fatalf("RoundTrip(%s) error = %v", target, err)
+		}
+	}
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, ts.URL+"/repos/o/r", nil)); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	if len(gotAuth) != 3 || gotAuth[0] != "" || gotAuth[1] != "" || gotAuth[2] == "" {
+		t.Errorf("Authorization headers = %q, want only the API request authenticated", gotAuth)
+	}
+	if got := ts.calls.Load(); got != 1 {
+		t.Errorf("token exchanges = %d, want 1", got)
+	}
+}
+
+func TestTransport_KeepsExplicitAuthorization(t *testing.T) {
+	var gotAuth string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotAuth = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	rt := NewTransport(nil, 10, WithBaseTransport(base))
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	req.Header.Set("Authorization", "Bearer user-token")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if gotAuth != "Bearer user-token" {
+		t.Errorf("Authorization = %q, want caller's header", gotAuth)
+	}
+}
+
+func TestRequiresAppAuth(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/app":                           true,
+		"/app/installations/1":           true,
+		"/orgs/acme/installation":        true,
+		"/users/octocat/installation":    true,
+		"/repos/o/r/installation":        true,
+		"/installation/repositories":     false,
+		"/repos/o/r/pulls":               false,
+		"/apps/my-app":                   false,
+		"/api/v3/orgs/acme/installation": true,
+	} {
+		if got := requiresAppAuth(path); got != want {
+			t.Errorf("requiresAppAuth(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
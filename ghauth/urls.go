@@ -0,0 +1,58 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+const (
+	// DefaultWebURL is the web URL of github.com.
+	DefaultWebURL = "https://github.com"
+
+	// DefaultUploadURL is the upload API base URL for github.com.
+	DefaultUploadURL = "https://uploads.github.com"
+)
+
+// APIURLs returns the REST API and upload base URLs for a GitHub web URL
+// (e.g. "https://github.com" or "https://github.example.com"). github.com
+// uses api.github.com and uploads.github.com; GitHub Enterprise Server
+// hosts use "/api/v3" and "/api/uploads" on the same origin. Any path on
+// the web URL, such as an app's HTML URL, is ignored.
+func APIURLs(webURL string) (apiURL, uploadURL string, err error) {
+	if strings.TrimSpace(webURL) == "" {
+		return DefaultAPIURL, DefaultUploadURL, nil
+	}
+	u, err := url.Parse(strings.TrimSpace(webURL))
+	if err != nil {
+		return "", "", fmt.Errorf("ghauth: invalid GitHub URL %q: %w", webURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("ghauth: invalid GitHub URL %q: scheme and host are required", webURL)
+	}
+
+	switch strings.ToLower(u.Hostname()) {
+	case "github.com", "www.github.com", "api.github.com":
+		return DefaultAPIURL, DefaultUploadURL, nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	return origin + "/api/v3", origin + "/api/uploads", nil
+}
+
+// EnvAPIURLs returns the API and upload base URLs for the GitHub instance
+// named by GITHUB_URL, falling back to the origin of the stored
+// GITHUB_APP_HTML_URL and then to github.com.
+func EnvAPIURLs() (apiURL, uploadURL string, err error) {
+	webURL := os.Getenv(installer.EnvGitHubURL)
+	if webURL == "" {
+		webURL = os.Getenv(configstore.EnvGitHubAppHTMLURL)
+	}
+	return APIURLs(webURL)
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ghauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+func TestAPIURLs(t *testing.T) {
+	tests := []struct {
+		webURL     string
+		wantAPI    string
+		wantUpload string
+	}{
+		{"", DefaultAPIURL, DefaultUploadURL},
+		{"https://github.com", DefaultAPIURL, DefaultUploadURL},
+		{"https://github.com/apps/my-app", DefaultAPIURL, DefaultUploadURL},
+		{"https://github.example.com", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"https://github.example.com/apps/my-app", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"http://ghes.local:8080/", "http://ghes.local:8080/api/v3", "http://ghes.local:8080/api/uploads"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.webURL, func(t *testing.T) {
+			api, upload, err := APIURLs(tt.webURL)
+			if err != nil {
+				t.Fatalf("APIURLs() error = %v", err)
+			}
+			if api != tt.wantAPI || upload != tt.wantUpload {
+				t.Errorf("APIURLs() = %q, %q, want %q, %q", api, upload, tt.wantAPI, tt.wantUpload)
+			}
+		})
+	}
+
+	if _, _, err := APIURLs("github.example.com"); err == nil {
+		t.Error("APIURLs() should fail without a scheme")
+	}
+}
+
+func TestEnvAPIURLs(t *testing.T) {
+	t.Setenv(installer.EnvGitHubURL, "")
+	t.Setenv(configstore.EnvGitHubAppHTMLURL, "https://ghes.example.com/apps/my-app")
+	if api, _, _ := EnvAPIURLs(); api != "https://ghes.example.com/api/v3" {
+		t.Errorf("EnvAPIURLs() = %q, want the app HTML URL's enterprise API", api)
+	}
+
+	t.Setenv(installer.EnvGitHubURL, "https://github.com")
+	if api, _, _ := EnvAPIURLs(); api != DefaultAPIURL {
+		t.Errorf("EnvAPIURLs() = %q, want GITHUB_URL to take precedence", api)
+	}
+}
+
+func TestNewTokenSource_EnterpriseURLs(t *testing.T) {
+	t.Setenv(installer.EnvGitHubURL, "https://ghes.example.com")
+	src := NewTokenSource()
+	if src.BaseURL() != "https://ghes.example.com/api/v3" || src.UploadURL() != "https://ghes.example.com/api/uploads" {
+		t.Errorf("URLs = %q, %q, want enterprise URLs", src.BaseURL(), src.UploadURL())
+	}
+
+	client, err := NewClientFromSource(context.Background(), src, 1)
+	if err != nil {
+		t.Fatalf("NewClientFromSource() error = %v", err)
+	}
+	if client.BaseURL.String() != "https://ghes.example.com/api/v3/" || client.UploadURL.String() != "https://ghes.example.com/api/uploads/" {
+		t.Errorf("client URLs = %s, %s, want enterprise URLs", client.BaseURL, client.UploadURL)
+	}
+
+	t.Setenv(installer.EnvGitHubURL, "not a url")
+	if _, err := NewTokenSource(WithCredentials(StaticCredentials(1, testKey(t)))).AppJWT(t.Context()); err == nil {
+		t.Error("AppJWT() should report an invalid GITHUB_URL")
+	}
+}
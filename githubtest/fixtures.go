@@ -0,0 +1,91 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// sharedKey is generated once per test binary, since RSA key generation
+// is slow.
+var sharedKey struct {
+	once sync.Once
+	key  *rsa.PrivateKey
+	err  error
+}
+
+// PrivateKey returns an RSA private key for signing test JWTs. The same
+// key is returned for the whole test binary.
+func PrivateKey(t testing.TB) *rsa.PrivateKey {
+	t.Helper()
+	sharedKey.once.Do(func() {
+		sharedKey.key, sharedKey.err = rsa.GenerateKey(rand.Reader, 2048)
+	})
+	if sharedKey.err != nil {
+		t.Fatalf("githubtest: generate RSA key: %v", sharedKey.err)
+	}
+	return sharedKey.key
+}
+
+// PrivateKeyPEM returns PrivateKey encoded as a PKCS#1 PEM block, the
+// format GitHub issues app keys in.
+func PrivateKeyPEM(t testing.TB) string {
+	t.Helper()
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(PrivateKey(t)),
+	}))
+}
+
+// AppCredentials returns a complete set of credentials for a test app
+// with ID 12345 and slug "test-app", signed with PrivateKey.
+func AppCredentials(t testing.TB) *configstore.AppCredentials {
+	t.Helper()
+	return &configstore.AppCredentials{
+		AppID:         12345,
+		AppSlug:       "test-app",
+		ClientID:      "Iv1.test123",
+		ClientSecret:  "test-client-secret",
+		WebhookSecret: "test-webhook-secret",
+		PrivateKey:    PrivateKeyPEM(t),
+		HTMLURL:       "https://github.com/apps/test-app",
+	}
+}
+
+// ManifestConversion returns the response GitHub gives when the manifest
+// flow's code is exchanged for creds. Use "*" as the code to accept any
+// code.
+func ManifestConversion(code string, creds *configstore.AppCredentials) Response {
+	body, _ := json.Marshal(creds)
+	return Response{
+		Method:     http.MethodPost,
+		Path:       manifestConversionPath(code),
+		StatusCode: http.StatusCreated,
+		Body:       string(body),
+	}
+}
+
+// ManifestConversionError returns a failed manifest code exchange, such
+// as 404 Not Found for an unknown or expired code.
+func ManifestConversionError(code string, status int) Response {
+	return Response{
+		Method:     http.MethodPost,
+		Path:       manifestConversionPath(code),
+		StatusCode: status,
+		Body:       `{"message":"` + http.StatusText(status) + `"}`,
+	}
+}
+
+func manifestConversionPath(code string) string {
+	return "/api/v3/app-manifests/" + code + "/conversions"
+}
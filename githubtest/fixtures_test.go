@@ -0,0 +1,59 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+func TestManifestConversion_InstallerFlow(t *testing.T) {
+	creds := AppCredentials(t)
+	srv := NewServer(t,
+		ManifestConversion("*", creds),
+		ManifestConversionError("expiredcode1234567890", http.StatusNotFound),
+	)
+
+	store := configstore.NewLocalFileStore(filepath.Join(t.TempDir(), "creds"))
+	handler, err := installer.New(installer.Config{Store: store, GitHubURL: srv.URL, AppDisplayName: "Test"})
+	if err != nil {
+		t.Fatalf("installer.New() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?code=expiredcode1234567890", nil))
+	if status, _ := store.Status(context.Background()); status.Registered {
+		t.Fatal("an expired code should not register the app")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?code=validcode1234567890", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback status = %d: %s", rec.Code, rec.Body)
+	}
+
+	status, err := store.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Registered || status.AppID != creds.AppID || status.AppSlug != creds.AppSlug {
+		t.Errorf("Status() = %+v, want the fixture app registered", status)
+	}
+	srv.AssertCallCount(t, http.MethodPost, "/api/v3/app-manifests/*/conversions", 2)
+}
+
+func TestPrivateKeyPEM(t *testing.T) {
+	if PrivateKey(t) != PrivateKey(t) {
+		t.Error("PrivateKey() should reuse the shared key")
+	}
+	if PrivateKeyPEM(t) == "" {
+		t.Error("PrivateKeyPEM() returned an empty key")
+	}
+}
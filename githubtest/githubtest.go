@@ -0,0 +1,252 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package githubtest provides a mock GitHub API server for testing apps
+// built on this module. Responses are registered per method and path
+// (with "*" matching one path segment), every request is recorded, and
+// assertion helpers check the calls a test made:
+//
+//	srv := githubtest.NewServer(t, githubtest.ManifestConversion("*", creds))
+//	handler, _ := installer.New(installer.Config{Store: store, GitHubURL: srv.URL})
+//	// ... drive the installer ...
+//	srv.AssertCalled(t, "POST", "/api/v3/app-manifests/*/conversions")
+//
+// GitHubURL values other than github.com use GitHub Enterprise Server
+// paths, so API routes on the mock are prefixed with "/api/v3".
+package githubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Request captures an HTTP request made to a Mock.
+type Request struct {
+	Timestamp time.Time
+	Method    string
+	Path      string
+	Query     string
+	Headers   http.Header
+	Body      string
+}
+
+// Response is a canned response for requests matching Method and Path.
+// Path segments of "*" match any single segment. The YAML tags allow
+// responses to be loaded from test fixtures.
+type Response struct {
+	Method     string            `yaml:"method"`
+	Path       string            `yaml:"path"`
+	StatusCode int               `yaml:"status"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
+	Body       string            `yaml:"body"`
+
+	handler http.HandlerFunc
+}
+
+// Mock is an http.Handler simulating the GitHub API. Requests matching a
+// registered response get it; exact paths take precedence over wildcard
+// patterns, and other requests get 404 Not Found. A Mock is safe for
+// concurrent use.
+type Mock struct {
+	// Logf, if set, logs every request and unmatched path (e.g. t.Logf).
+	Logf func(format string, args ...any)
+
+	mu        sync.Mutex
+	requests  []Request
+	responses []Response
+}
+
+// NewMock creates a Mock serving the given responses.
+func NewMock(responses ...Response) *Mock {
+	m := &Mock{}
+	for _, resp := range responses {
+		m.Add(resp)
+	}
+	return m
+}
+
+// Add registers resp, replacing any response for the same method and path.
+func (m *Mock) Add(resp Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.responses {
+		if existing.Method == resp.Method && existing.Path == resp.Path {
+			m.responses[i] = resp
+			return
+		}
+	}
+	m.responses = append(m.responses, resp)
+}
+
+// Handle registers a response with a raw body.
+func (m *Mock) Handle(method, path string, status int, body string) {
+	m.Add(Response{Method: method, Path: path, StatusCode: status, Body: body})
+}
+
+// HandleJSON registers a response whose body is v encoded as JSON. It
+// panics if v cannot be encoded.
+func (m *Mock) HandleJSON(method, path string, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("githubtest: encode response for %s %s: %v", method, path, err))
+	}
+	m.Handle(method, path, status, string(body))
+}
+
+// HandleFunc registers fn to serve matching requests, for responses that
+// depend on the request. The request body has already been recorded and
+// can be read again.
+func (m *Mock) HandleFunc(method, path string, fn http.HandlerFunc) {
+	m.Add(Response{Method: method, Path: path, handler: fn})
+}
+
+// ServeHTTP implements http.Handler.
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	m.mu.Lock()
+	m.requests = append(m.requests, Request{
+		Timestamp: time.Now(),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Query:     r.URL.RawQuery,
+		Headers:   r.Header.Clone(),
+		Body:      string(body),
+	})
+	resp, ok := m.match(r.Method, r.URL.Path)
+	m.mu.Unlock()
+
+	m.logf("[githubtest] %s %s", r.Method, r.URL.Path)
+	if !ok {
+		m.logf("[githubtest] no mock response for %s %s", r.Method, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+		return
+	}
+	if resp.handler != nil {
+		resp.handler(w, r)
+		return
+	}
+
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	status := resp.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(resp.Body))
+}
+
+// match finds the response for a request, preferring exact paths over
+// wildcard patterns. The caller must hold m.mu.
+func (m *Mock) match(method, path string) (Response, bool) {
+	for _, resp := range m.responses {
+		if resp.Method == method && resp.Path == path {
+			return resp, true
+		}
+	}
+	for _, resp := range m.responses {
+		if resp.Method == method && MatchPath(path, resp.Path) {
+			return resp, true
+		}
+	}
+	return Response{}, false
+}
+
+func (m *Mock) logf(format string, args ...any) {
+	if m.Logf != nil {
+		m.Logf(format, args...)
+	}
+}
+
+// Requests returns all recorded requests in the order they arrived.
+func (m *Mock) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// Calls returns the recorded requests matching method and path pattern.
+func (m *Mock) Calls(method, path string) []Request {
+	var calls []Request
+	for _, req := range m.Requests() {
+		if req.Method == method && MatchPath(req.Path, path) {
+			calls = append(calls, req)
+		}
+	}
+	return calls
+}
+
+// Reset clears all recorded requests. Registered responses are kept.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = nil
+}
+
+// AssertCalled fails the test unless a request matched method and path.
+func (m *Mock) AssertCalled(t testing.TB, method, path string) {
+	t.Helper()
+	if len(m.Calls(method, path)) == 0 {
+		t.Errorf("githubtest: expected call not found: %s %s\n%s", method, path, m.callList())
+	}
+}
+
+// AssertNotCalled fails the test if any request matched method and path.
+func (m *Mock) AssertNotCalled(t testing.TB, method, path string) {
+	t.Helper()
+	if n := len(m.Calls(method, path)); n > 0 {
+		t.Errorf("githubtest: unexpected %d call(s) to %s %s", n, method, path)
+	}
+}
+
+// AssertCallCount fails the test unless exactly n requests matched method
+// and path.
+func (m *Mock) AssertCallCount(t testing.TB, method, path string, n int) {
+	t.Helper()
+	if got := len(m.Calls(method, path)); got != n {
+		t.Errorf("githubtest: %s %s called %d times, want %d\n%s", method, path, got, n, m.callList())
+	}
+}
+
+// callList formats the recorded requests for failure messages.
+func (m *Mock) callList() string {
+	var b strings.Builder
+	b.WriteString("actual calls:")
+	for _, req := range m.Requests() {
+		fmt.Fprintf(&b, "\n  %s %s", req.Method, req.Path)
+	}
+	return b.String()
+}
+
+// MatchPath reports whether path matches pattern, where a "*" segment in
+// pattern matches any single path segment.
+func MatchPath(path, pattern string) bool {
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+
+	if len(pathParts) != len(patternParts) {
+		return false
+	}
+	for i, patternPart := range patternParts {
+		if patternPart != "*" && pathParts[i] != patternPart {
+			return false
+		}
+	}
+	return true
+}
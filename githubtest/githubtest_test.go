@@ -0,0 +1,98 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		want    bool
+	}{
+		{"/app-manifests/abc123/conversions", "/app-manifests/*/conversions", true},
+		{"/repos/owner/repo/pulls/123", "/repos/*/*/pulls/*", true},
+		{"/repos/owner/repo/pulls", "/repos/*/*/pulls/*", false},
+		{"/exact/match", "/exact/match", true},
+		{"/exact/mismatch", "/exact/match", false},
+		{"/api/v3/app-manifests/code/conversions", "/api/v3/app-manifests/*/conversions", true},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.path, tt.pattern); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func serve(m *Mock, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestMock(t *testing.T) {
+	m := NewMock(Response{Method: "GET", Path: "/repos/*/*", StatusCode: 200, Body: `{"wildcard":true}`})
+	m.HandleJSON("GET", "/repos/acme/widgets", 200, map[string]bool{"exact": true})
+	m.HandleFunc("POST", "/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	})
+
+	if rec := serve(m, "GET", "/repos/acme/widgets", ""); rec.Body.String() != `{"exact":true}` {
+		t.Errorf("exact match body = %s", rec.Body)
+	}
+	if rec := serve(m, "GET", "/repos/acme/gadgets", ""); rec.Body.String() != `{"wildcard":true}` {
+		t.Errorf("wildcard match body = %s", rec.Body)
+	}
+	if rec := serve(m, "POST", "/echo", "ping"); rec.Code != http.StatusCreated || rec.Body.String() != "ping" {
+		t.Errorf("HandleFunc response = %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(m, "DELETE", "/repos/acme/widgets", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unmatched status = %d, want 404", rec.Code)
+	}
+
+	m.AssertCalled(t, "GET", "/repos/*/*")
+	m.AssertCallCount(t, "GET", "/repos/*/*", 2)
+	m.AssertNotCalled(t, "PATCH", "/repos/*/*")
+	if calls := m.Calls("POST", "/echo"); len(calls) != 1 || calls[0].Body != "ping" {
+		t.Errorf("Calls() = %+v, want the recorded body", calls)
+	}
+
+	m.Reset()
+	if len(m.Requests()) != 0 {
+		t.Error("Reset() should clear recorded requests")
+	}
+	if rec := serve(m, "GET", "/repos/acme/widgets", ""); rec.Code != http.StatusOK {
+		t.Error("Reset() should keep registered responses")
+	}
+}
+
+func TestMock_AssertionsFail(t *testing.T) {
+	m := NewMock()
+	serve(m, "GET", "/app", "")
+
+	ft := &fakeTB{TB: t}
+	m.AssertCalled(ft, "GET", "/rate_limit")
+	m.AssertNotCalled(ft, "GET", "/app")
+	m.AssertCallCount(ft, "GET", "/app", 2)
+	if ft.errors != 3 {
+		t.Errorf("failed assertions = %d, want 3", ft.errors)
+	}
+}
+
+// fakeTB records failures instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errors int
+}
+
+func (f *fakeTB) Helper()                           {}
+func (f *fakeTB) Errorf(format string, args ...any) { f.errors++ }
@@ -0,0 +1,251 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvRecord enables record mode for Cassette when set to "1" or "true".
+const EnvRecord = "GITHUBTEST_RECORD"
+
+// Redacted replaces sensitive values in recorded fixtures.
+const Redacted = "REDACTED"
+
+// sensitiveKeys are JSON fields whose values are redacted from recorded
+// response bodies.
+var sensitiveKeys = map[string]bool{
+	"token":          true,
+	"access_token":   true,
+	"refresh_token":  true,
+	"client_secret":  true,
+	"webhook_secret": true,
+	"pem":            true,
+	"secret":         true,
+}
+
+// keptHeaders are the response headers worth replaying. Everything else,
+// including cookies and request IDs, is dropped from recordings.
+var keptHeaders = []string{
+	"Content-Type",
+	"Link",
+	"Retry-After",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"X-RateLimit-Resource",
+}
+
+// Recorder is an http.RoundTripper that forwards requests to a real
+// GitHub API and records each exchange as a sanitized Response, so the
+// recordings can be saved as fixtures and replayed by a Mock. Response
+// headers are reduced to those relevant to clients, and secrets in JSON
+// bodies (tokens, client and webhook secrets, private keys) are replaced
+// with Redacted. Request credentials are never recorded.
+type Recorder struct {
+	base     http.RoundTripper
+	sanitize func(*Response)
+
+	mu        sync.Mutex
+	responses []Response
+}
+
+// RecorderOption is a functional option for configuring Recorder.
+type RecorderOption func(*Recorder)
+
+// WithRecorderTransport sets the RoundTripper that sends the real
+// requests. The default is http.DefaultTransport.
+func WithRecorderTransport(rt http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.base = rt
+	}
+}
+
+// WithSanitizer adds a function applied to every recorded Response after
+// the built-in sanitization, e.g. to replace account names or IDs.
+func WithSanitizer(fn func(*Response)) RecorderOption {
+	return func(r *Recorder) {
+		r.sanitize = fn
+	}
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder(opts ...RecorderOption) *Recorder {
+	r := &Recorder{base: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("githubtest: read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := Response{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		StatusCode: resp.StatusCode,
+		Headers:    make(map[string]string),
+		Body:       redactJSON(body),
+	}
+	for _, name := range keptHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			rec.Headers[name] = v
+		}
+	}
+	if r.sanitize != nil {
+		r.sanitize(&rec)
+	}
+
+	r.mu.Lock()
+	r.responses = append(r.responses, rec)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Responses returns the recorded responses in the order they were made.
+// A later exchange for the same method and path replaces an earlier one
+// when loaded into a Mock.
+func (r *Recorder) Responses() []Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Response(nil), r.responses...)
+}
+
+// Save writes the recorded responses to path as YAML, in the format read
+// by LoadResponses and by scenario mock_responses.
+func (r *Recorder) Save(path string) error {
+	data, err := yaml.Marshal(r.Responses())
+	if err != nil {
+		return fmt.Errorf("githubtest: encode recording: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("githubtest: create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("githubtest: write recording: %w", err)
+	}
+	return nil
+}
+
+// LoadResponses reads responses saved by Recorder.Save.
+func LoadResponses(path string) ([]Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("githubtest: read recording: %w", err)
+	}
+	var responses []Response
+	if err := yaml.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("githubtest: parse recording %s: %w", path, err)
+	}
+	return responses, nil
+}
+
+// Cassette returns an http.RoundTripper backed by the fixture at path.
+// By default it replays the fixture without network access, answering
+// requests to any host by method and path like a Mock. With
+// GITHUBTEST_RECORD=1 it sends requests through base (or
+// http.DefaultTransport if nil) and overwrites the fixture with the
+// sanitized exchanges when the test ends.
+func Cassette(t testing.TB, path string, base http.RoundTripper, opts ...RecorderOption) http.RoundTripper {
+	t.Helper()
+	if v := strings.ToLower(os.Getenv(EnvRecord)); v == "1" || v == "true" {
+		if base != nil {
+			opts = append([]RecorderOption{WithRecorderTransport(base)}, opts...)
+		}
+		rec := NewRecorder(opts...)
+		t.Cleanup(func() {
+			if err := rec.Save(path); err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+		return rec
+	}
+
+	responses, err := LoadResponses(path)
+	if err != nil {
+		t.Fatalf("%v (record it with %s=1)", err, EnvRecord)
+	}
+	m := NewMock(responses...)
+	m.Logf = t.Logf
+	return replayTransport{mock: m}
+}
+
+// replayTransport answers requests from a Mock without network access.
+type replayTransport struct {
+	mock *Mock
+}
+
+func (rt replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	served := req.Clone(req.Context())
+	if served.Body == nil {
+		served.Body = http.NoBody
+	}
+	rec := httptest.NewRecorder()
+	rt.mock.ServeHTTP(rec, served)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// redactJSON replaces sensitive fields in a JSON body. Bodies that are
+// not JSON are returned unchanged.
+func redactJSON(body []byte) string {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return string(body)
+	}
+	if !redact(doc) {
+		return string(body)
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+// redact replaces sensitive string values in place and reports whether
+// anything changed.
+func redact(v any) bool {
+	changed := false
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			if s, ok := child.(string); ok && s != "" && sensitiveKeys[strings.ToLower(k)] {
+				node[k] = Redacted
+				changed = true
+				continue
+			}
+			changed = redact(child) || changed
+		}
+	case []any:
+		for _, child := range node {
+			changed = redact(child) || changed
+		}
+	}
+	return changed
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newUpstream fakes the real GitHub API being recorded.
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		switch r.URL.Path {
+		case "/app/installations/1/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token":"ghs_live","expires_at":"2030-01-01T00:00:00Z"}`))
+		default:
+			w.Write([]byte(`{"id":1,"slug":"live-app","owner":{"login":"acme"}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, rt http.RoundTripper, method, url string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	req.Header.Set("Authorization", "Bearer real-jwt")
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestRecorder(t *testing.T) {
+	upstream := newUpstream(t)
+	rec := NewRecorder(WithSanitizer(func(r *Response) {
+		r.Body = strings.ReplaceAll(r.Body, "acme", "example-org")
+	}))
+
+	if status, body := get(t, rec, "POST", upstream.URL+"/app/installations/1/access_tokens"); status != 201 || !strings.Contains(body, "ghs_live") {
+		t.Errorf("recorded call should pass the real response through, got %d %s", status, body)
+	}
+	get(t, rec, "GET", upstream.URL+"/app")
+
+	responses := rec.Responses()
+	if len(responses) != 2 {
+		t.Fatalf("Responses() = %d, want 2", len(responses))
+	}
+	token := responses[0]
+	if strings.Contains(token.Body, "ghs_live") || !strings.Contains(token.Body, Redacted) {
+		t.Errorf("token body = %s, want the token redacted", token.Body)
+	}
+	if _, ok := token.Headers["Set-Cookie"]; ok {
+		t.Error("Set-Cookie should not be recorded")
+	}
+	if token.Headers["X-RateLimit-Remaining"] != "4999" {
+		t.Errorf("headers = %v, want rate limit headers kept", token.Headers)
+	}
+	if !strings.Contains(responses[1].Body, "example-org") {
+		t.Errorf("custom sanitizer not applied: %s", responses[1].Body)
+	}
+}
+
+func TestCassette_RecordThenReplay(t *testing.T) {
+	upstream := newUpstream(t)
+	path := filepath.Join(t.TempDir(), "fixtures", "app.yaml")
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(EnvRecord, "1")
+		get(t, Cassette(t, path, nil), "GET", upstream.URL+"/app")
+	})
+
+	upstream.Close()
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv(EnvRecord, "")
+		status, body := get(t, Cassette(t, path, nil), "GET", "https://api.github.com/app")
+		if status != http.StatusOK || !strings.Contains(body, "live-app") {
+			t.Errorf("replayed response = %d %s", status, body)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package githubtest
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// Server is a Mock listening on a local address. Its URL can be used as
+// the installer's GitHubURL.
+type Server struct {
+	*Mock
+	*httptest.Server
+}
+
+// NewServer starts an HTTP Server serving the given responses. It is
+// closed when the test ends, and requests are logged with t.Logf.
+func NewServer(t testing.TB, responses ...Response) *Server {
+	t.Helper()
+	m := NewMock(responses...)
+	m.Logf = t.Logf
+	s := &Server{Mock: m, Server: httptest.NewServer(m)}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// NewTLSServer is like NewServer but serves HTTPS with a self-signed
+// certificate. Use the Server's Client, or trust its Certificate, to
+// connect.
+func NewTLSServer(t testing.TB, responses ...Response) *Server {
+	t.Helper()
+	m := NewMock(responses...)
+	m.Logf = t.Logf
+	s := &Server{Mock: m, Server: httptest.NewTLSServer(m)}
+	t.Cleanup(s.Close)
+	return s
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -21,6 +22,8 @@ import (
 	"github.com/chainguard-dev/clog"
 
 	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/featureflag"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
 )
 
 //go:embed templates/*
@@ -34,6 +37,10 @@ const (
 	EnvGitHubURL      = "GITHUB_URL"
 	EnvGitHubOrg      = "GITHUB_ORG"
 	disableSetupPath  = "/setup/disable"
+
+	// DefaultFlagKey is the feature flag that enables the installer when
+	// Config.Flags is set and Config.FlagKey is not.
+	DefaultFlagKey = "installer_enabled"
 )
 
 // CredentialsSavedFunc is called after credentials are saved.
@@ -50,10 +57,30 @@ type Config struct {
 	WebhookURL         string
 	OnCredentialsSaved CredentialsSavedFunc
 
+	// ManifestFunc, if set, is called on every request to the index page
+	// for the manifest to offer, so the manifest can change without a
+	// restart (e.g. when read from a mounted ConfigMap). If it returns nil,
+	// Manifest is used.
+	ManifestFunc func() *Manifest
+
 	// OnReloadNeeded is called after credentials are saved to trigger
 	// a configuration reload. This should be wired to the Runtime's
 	// ReloadCallback() or a custom reload function.
 	OnReloadNeeded func()
+
+	// Events, if set, receives a lifecycle.AppRegistered event after the
+	// credentials of a newly created app are saved. A failure to publish is
+	// logged and does not fail the installation.
+	Events lifecycle.Publisher
+
+	// Flags, if set, is asked on every request whether the installer is
+	// enabled, so it can be switched off fleet-wide, e.g. with an AWS
+	// AppConfig feature flag (see featureflag.NewFromEnv). While the flag
+	// is off, or cannot be evaluated, every installer path returns 404.
+	Flags featureflag.Provider
+
+	// FlagKey is the flag evaluated with Flags. Defaults to DefaultFlagKey.
+	FlagKey string
 }
 
 // NewConfigFromEnv creates a Config from environment variables.
@@ -101,6 +128,9 @@ func New(cfg Config) (*Handler, error) {
 	if cfg.AppDisplayName == "" {
 		cfg.AppDisplayName = "GitHub App"
 	}
+	if cfg.FlagKey == "" {
+		cfg.FlagKey = DefaultFlagKey
+	}
 	return &Handler{config: cfg}, nil
 }
 
@@ -108,6 +138,11 @@ func New(cfg Config) (*Handler, error) {
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 
+	if !h.flagEnabled(r.Context()) {
+		http.NotFound(w, r)
+		return
+	}
+
 	switch {
 	case (r.Method == http.MethodGet || r.Method == http.MethodHead) && (path == "/" || path == ""):
 		h.handleRoot(w, r)
@@ -123,6 +158,31 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// flagEnabled reports whether Config.Flags enables the installer. It fails
+// closed: a flag that cannot be evaluated disables the installer.
+func (h *Handler) flagEnabled(ctx context.Context) bool {
+	if h.config.Flags == nil {
+		return true
+	}
+	enabled, err := h.config.Flags.Enabled(ctx, h.config.FlagKey)
+	if err != nil {
+		clog.FromContext(ctx).Errorf("[installer] failed to evaluate flag %s, installer disabled: %v", h.config.FlagKey, err)
+		return false
+	}
+	return enabled
+}
+
+// manifest returns a copy of the manifest to offer, from
+// Config.ManifestFunc if set and otherwise from Config.Manifest.
+func (h *Handler) manifest() *Manifest {
+	if h.config.ManifestFunc != nil {
+		if m := h.config.ManifestFunc(); m != nil {
+			return m.Clone()
+		}
+	}
+	return h.config.Manifest.Clone()
+}
+
 // handleRoot redirects to /setup if enabled, otherwise returns 404.
 func (h *Handler) handleRoot(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -177,10 +237,7 @@ func (h *Handler) handleIndex(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	manifest := h.config.Manifest.Clone()
-	if manifest == nil {
-		manifest = &Manifest{}
-	}
+	manifest := h.manifest()
 	manifest.RedirectURL = redirectURL + "/callback"
 	manifest.HookAttributes.URL = webhookURL
 	manifest.HookAttributes.Active = webhookURL != ""
@@ -277,7 +334,13 @@ func (h *Handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := h.config.Store.Save(ctx, creds); err != nil {
+	// A replication error means the credentials were saved in the primary
+	// region; the manifest code is single-use, so carry on and let the
+	// failed regions be repaired out of band.
+	var replErr *configstore.ReplicationError
+	if err := h.config.Store.Save(ctx, creds); errors.As(err, &replErr) {
+		log.Errorf("[installer] failed to replicate credentials: %v", err)
+	} else if err != nil {
 		log.Errorf("[installer] failed to save credentials: %v", err)
 		http.Error(w, "Failed to save credentials", http.StatusInternalServerError)
 		return
@@ -285,6 +348,18 @@ func (h *Handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 
 	log.Infof("[installer] successfully created github app: slug=%s app_id=%d", creds.AppSlug, creds.AppID)
 
+	if h.config.Events != nil {
+		err := h.config.Events.Publish(ctx, lifecycle.Event{
+			Type:    lifecycle.AppRegistered,
+			AppID:   creds.AppID,
+			AppSlug: creds.AppSlug,
+			HTMLURL: creds.HTMLURL,
+		})
+		if err != nil {
+			log.Errorf("[installer] failed to publish %s event: %v", lifecycle.AppRegistered, err)
+		}
+	}
+
 	if h.config.OnReloadNeeded != nil {
 		log.Infof("[installer] triggering configuration reload")
 		h.config.OnReloadNeeded()
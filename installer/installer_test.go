@@ -5,11 +5,15 @@ package installer
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/featureflag"
+	"github.com/cruxstack/github-app-setup-go/lifecycle"
 )
 
 func TestGetBaseURL(t *testing.T) {
@@ -409,6 +413,49 @@ func TestHandler_handleIndex_AlreadyRegistered(t *testing.T) {
 	}
 }
 
+func TestHandler_handleIndex_ManifestFunc(t *testing.T) {
+	store := &mockStore{
+		statusFunc: func(ctx context.Context) (*configstore.InstallerStatus, error) {
+			return &configstore.InstallerStatus{}, nil
+		},
+	}
+
+	var current *Manifest
+	h, _ := New(Config{
+		Store:        store,
+		Manifest:     Manifest{Name: "static-app"},
+		ManifestFunc: func() *Manifest { return current },
+	})
+
+	tests := []struct {
+		name     string
+		manifest *Manifest
+		want     string
+	}{
+		{"falls back to Manifest", nil, "static-app"},
+		{"uses ManifestFunc", &Manifest{Name: "mounted-app"}, "mounted-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current = tt.manifest
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/setup", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("handleIndex() status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("handleIndex() body does not contain manifest name %q", tt.want)
+			}
+		})
+	}
+
+	if current.RedirectURL != "" {
+		t.Errorf("ManifestFunc result was modified: RedirectURL = %q", current.RedirectURL)
+	}
+}
+
 func TestIsValidOAuthCode(t *testing.T) {
 	tests := []struct {
 		name string
@@ -501,3 +548,130 @@ func (m *mockStore) DisableInstaller(ctx context.Context) error {
 	}
 	return nil
 }
+
+// recordingPublisher records published lifecycle events.
+type recordingPublisher struct {
+	events []lifecycle.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, e lifecycle.Event) error {
+	p.events = append(p.events, e)
+	return p.err
+}
+
+func TestHandler_handleCallback_PublishesAppRegistered(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/app-manifests/abcdefghij123/conversions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":42,"slug":"my-app","html_url":"https://github.example.com/apps/my-app","pem":"key"}`))
+	}))
+	defer github.Close()
+
+	var saved bool
+	events := &recordingPublisher{err: errors.New("bus unavailable")}
+	h, err := New(Config{
+		Store:     &mockStore{saveFunc: func(ctx context.Context, creds *configstore.AppCredentials) error { saved = true; return nil }},
+		GitHubURL: github.URL,
+		Events:    events,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?code=abcdefghij123", nil))
+
+	// A failed publish must not fail the installation
+	if rec.Code != http.StatusOK || !saved {
+		t.Fatalf("callback status = %d, saved = %v; want 200 and saved", rec.Code, saved)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(events.events))
+	}
+	e := events.events[0]
+	if e.Type != lifecycle.AppRegistered || e.AppID != 42 || e.AppSlug != "my-app" || e.HTMLURL != "https://github.example.com/apps/my-app" {
+		t.Errorf("event = %+v", e)
+	}
+}
+
+func TestHandler_handleCallback_SaveErrors(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":42,"slug":"my-app","pem":"key"}`))
+	}))
+	defer github.Close()
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"save failure", errors.New("access denied"), http.StatusInternalServerError},
+		{"replication failure", &configstore.ReplicationError{Regions: map[string]error{"us-west-2": errors.New("access denied")}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := New(Config{
+				Store:     &mockStore{saveFunc: func(ctx context.Context, creds *configstore.AppCredentials) error { return tt.err }},
+				GitHubURL: github.URL,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?code=abcdefghij123", nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("callback status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler_Flags(t *testing.T) {
+	store := &mockStore{}
+	var flagKey string
+	var enabled bool
+	var flagErr error
+	flags := featureflag.ProviderFunc(func(ctx context.Context, key string) (bool, error) {
+		flagKey = key
+		return enabled, flagErr
+	})
+	h, err := New(Config{Store: store, Flags: flags})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		err        error
+		wantStatus int
+	}{
+		{"flag on serves the installer", true, nil, http.StatusOK},
+		{"flag off returns 404", false, nil, http.StatusNotFound},
+		{"flag error returns 404", true, featureflag.ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabled, flagErr = tt.enabled, tt.err
+			for _, path := range []string{"/setup", "/callback?code=abcdefghij123"} {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if path == "/setup" && rec.Code != tt.wantStatus {
+					t.Errorf("GET %s status = %d, want %d", path, rec.Code, tt.wantStatus)
+				}
+				if tt.wantStatus == http.StatusNotFound && rec.Code != http.StatusNotFound {
+					t.Errorf("GET %s status = %d, want 404", path, rec.Code)
+				}
+			}
+		})
+	}
+	if flagKey != DefaultFlagKey {
+		t.Errorf("evaluated flag %q, want %q", flagKey, DefaultFlagKey)
+	}
+}
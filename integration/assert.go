@@ -0,0 +1,149 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/githubtest"
+)
+
+// manifestInputPattern finds the manifest hidden input on the setup page.
+var manifestInputPattern = regexp.MustCompile(`name="manifest"[^>]*value='([^']*)'`)
+
+// lookupJSONPath resolves a dotted path such as "hook_attributes.url" or
+// "events.0" in a decoded JSON value.
+func lookupJSONPath(v any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// normalizeJSON round-trips v through JSON so values decoded from YAML
+// compare equal to values decoded from JSON.
+func normalizeJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// jsonMismatches returns a description of each path in want whose value
+// in body differs.
+func jsonMismatches(body []byte, want map[string]any) []string {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return []string{fmt.Sprintf("body is not JSON: %v", err)}
+	}
+	var problems []string
+	for path, expected := range want {
+		got, ok := lookupJSONPath(doc, path)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing", path))
+			continue
+		}
+		if !reflect.DeepEqual(normalizeJSON(got), normalizeJSON(expected)) {
+			problems = append(problems, fmt.Sprintf("%s = %v, want %v", path, got, expected))
+		}
+	}
+	return problems
+}
+
+// headerMismatches returns a description of each header in want whose
+// value differs.
+func headerMismatches(got http.Header, want map[string]string) []string {
+	var problems []string
+	for name, expected := range want {
+		if v := got.Get(name); v != expected {
+			problems = append(problems, fmt.Sprintf("header %s = %q, want %q", name, v, expected))
+		}
+	}
+	return problems
+}
+
+// extractManifest returns the manifest JSON embedded in the setup page.
+func extractManifest(body []byte) ([]byte, error) {
+	m := manifestInputPattern.FindSubmatch(body)
+	if m == nil {
+		return nil, fmt.Errorf("no manifest input on page")
+	}
+	return []byte(html.UnescapeString(string(m[1]))), nil
+}
+
+// callMismatches returns why req does not satisfy the body, header, and
+// JSON constraints of expected.
+func callMismatches(req githubtest.Request, expected ExpectedCall) []string {
+	var problems []string
+	for _, s := range expected.BodyContains {
+		if !strings.Contains(req.Body, s) {
+			problems = append(problems, fmt.Sprintf("body does not contain %q", s))
+		}
+	}
+	problems = append(problems, headerMismatches(req.Headers, expected.Headers)...)
+	if len(expected.JSON) > 0 {
+		problems = append(problems, jsonMismatches([]byte(req.Body), expected.JSON)...)
+	}
+	return problems
+}
+
+// assertCalls checks the expected and forbidden calls to mock GitHub.
+func assertCalls(t *testing.T, mock *githubtest.Mock, expected, unexpected []ExpectedCall) {
+	t.Helper()
+	for _, exp := range expected {
+		calls := mock.Calls(exp.Method, exp.Path)
+		if exp.Times > 0 {
+			mock.AssertCallCount(t, exp.Method, exp.Path, exp.Times)
+		} else {
+			mock.AssertCalled(t, exp.Method, exp.Path)
+		}
+
+		var problems []string
+		matched := len(calls) == 0
+		for _, req := range calls {
+			p := callMismatches(req, exp)
+			if len(p) == 0 {
+				matched = true
+				break
+			}
+			problems = append(problems, p...)
+		}
+		if !matched {
+			t.Errorf("no %s %s call matched: %s", exp.Method, exp.Path, strings.Join(problems, "; "))
+		}
+	}
+	for _, exp := range unexpected {
+		mock.AssertNotCalled(t, exp.Method, exp.Path)
+	}
+}
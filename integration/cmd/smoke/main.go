@@ -0,0 +1,91 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+// Command smoke runs the integration smoke scenarios against a deployed
+// installer, for post-deploy checks in pipelines:
+//
+//	go run -tags=integration ./integration/cmd/smoke -base-url https://app.example.com
+//
+// It exits 1 if any scenario fails and 2 on usage errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/integration"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	baseURL := flag.String("base-url", os.Getenv("SMOKE_BASE_URL"), "base URL of the deployed installer (default $SMOKE_BASE_URL)")
+	scenariosPath := flag.String("scenarios", "integration/testdata/scenarios.yaml", "scenarios file")
+	runPattern := flag.String("run", "", "only run smoke scenarios whose name matches this regexp")
+	insecure := flag.Bool("insecure", false, "skip TLS certificate verification")
+	timeout := flag.Duration("timeout", integration.DefaultSmokeTimeout, "timeout for each request")
+	flag.Parse()
+
+	if *baseURL == "" {
+		fmt.Fprintln(os.Stderr, "smoke: -base-url or SMOKE_BASE_URL is required")
+		return 2
+	}
+	var filter *regexp.Regexp
+	if *runPattern != "" {
+		re, err := regexp.Compile(*runPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "smoke: invalid -run pattern: %v\n", err)
+			return 2
+		}
+		filter = re
+	}
+
+	scenarios, err := integration.LoadScenarios(*scenariosPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "smoke: %v\n", err)
+		return 2
+	}
+
+	client := integration.NewSmokeClient(*insecure, *timeout)
+	ran, failed := 0, 0
+	for _, scenario := range integration.SmokeScenarios(scenarios) {
+		if filter != nil && !filter.MatchString(scenario.Name) {
+			continue
+		}
+		ran++
+
+		start := time.Now()
+		problems, err := integration.RunSmoke(client, *baseURL, scenario)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err == nil && len(problems) == 0 {
+			fmt.Printf("PASS %s (%v)\n", scenario.Name, elapsed)
+			continue
+		}
+
+		failed++
+		fmt.Printf("FAIL %s (%v)\n", scenario.Name, elapsed)
+		for _, problem := range problems {
+			fmt.Printf("    %s\n", problem)
+		}
+		if err != nil {
+			fmt.Printf("    %v\n", err)
+		}
+	}
+
+	if ran == 0 {
+		fmt.Fprintln(os.Stderr, "smoke: no smoke scenarios matched")
+		return 2
+	}
+	fmt.Printf("%d of %d smoke scenarios passed against %s\n", ran-failed, ran, *baseURL)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
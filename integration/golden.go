@@ -0,0 +1,157 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// goldenDir holds the golden fixtures referenced by expected_store.golden.
+var goldenDir = filepath.Join("testdata", "golden")
+
+// updateGolden reports whether golden fixtures should be rewritten from
+// the actual artifacts instead of compared.
+func updateGolden() bool {
+	v := os.Getenv("UPDATE_GOLDEN")
+	return v == "1" || v == "true"
+}
+
+// secretPlaceholders maps the backend keys holding secrets to the
+// placeholder that replaces their values in golden fixtures.
+var secretPlaceholders = map[string]map[string]string{
+	backendEnvFile: {
+		"GITHUB_APP_PRIVATE_KEY": "{{PRIVATE_KEY}}",
+		"GITHUB_CLIENT_SECRET":   "{{CLIENT_SECRET}}",
+		"GITHUB_WEBHOOK_SECRET":  "{{WEBHOOK_SECRET}}",
+	},
+	backendFiles: {
+		"private-key.pem": "{{PRIVATE_KEY}}",
+		"client-secret":   "{{CLIENT_SECRET}}",
+		"webhook-secret":  "{{WEBHOOK_SECRET}}",
+	},
+}
+
+// assertGolden compares the file or directory the store persisted to
+// the golden fixture name, with secret values replaced by placeholders.
+// With UPDATE_GOLDEN=1 the fixture is rewritten instead.
+func assertGolden(store *scenarioStore, name string) []string {
+	if store.artifact == "" {
+		return []string{fmt.Sprintf("golden files are not supported for the %s store", store.backend)}
+	}
+
+	values, err := store.values()
+	if err != nil {
+		return []string{fmt.Sprintf("read %s store: %v", store.backend, err)}
+	}
+	mask := secretMasker(values, secretPlaceholders[store.backend])
+
+	actual, err := readArtifact(store.artifact)
+	if err != nil {
+		return []string{fmt.Sprintf("read %s: %v", store.artifact, err)}
+	}
+	for file, content := range actual {
+		actual[file] = mask.Replace(content)
+	}
+
+	path := filepath.Join(goldenDir, name)
+	if updateGolden() {
+		if err := writeArtifact(path, actual, store.backend == backendFiles); err != nil {
+			return []string{fmt.Sprintf("update golden %s: %v", path, err)}
+		}
+		return nil
+	}
+
+	want, err := readArtifact(path)
+	if err != nil {
+		return []string{fmt.Sprintf("read golden %s: %v (create it with UPDATE_GOLDEN=1)", path, err)}
+	}
+
+	var problems []string
+	for _, file := range slices.Sorted(maps.Keys(want)) {
+		got, ok := actual[file]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("golden %s: %s was not written", name, file))
+		case got != want[file]:
+			problems = append(problems, fmt.Sprintf("golden %s differs\n--- got ---\n%s\n--- want ---\n%s", filepath.Join(name, file), got, want[file]))
+		}
+	}
+	for _, file := range slices.Sorted(maps.Keys(actual)) {
+		if _, ok := want[file]; !ok {
+			problems = append(problems, fmt.Sprintf("golden %s: unexpected file %s", name, file))
+		}
+	}
+	return problems
+}
+
+// secretMasker replaces the values of the secret keys with their
+// placeholders, longest values first so one secret containing another is
+// masked whole.
+func secretMasker(values, placeholders map[string]string) *strings.Replacer {
+	type secret struct{ value, placeholder string }
+	var secrets []secret
+	for key, placeholder := range placeholders {
+		if v := values[key]; v != "" {
+			secrets = append(secrets, secret{v, placeholder})
+		}
+	}
+	slices.SortFunc(secrets, func(a, b secret) int {
+		return cmp.Compare(len(b.value), len(a.value))
+	})
+
+	var pairs []string
+	for _, s := range secrets {
+		pairs = append(pairs, s.value, s.placeholder)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// readArtifact reads a file, or every file in a directory, keyed by file
+// name. A single file is keyed by the empty string.
+func readArtifact(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readDir(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"": string(data)}, nil
+}
+
+// writeArtifact writes files read by readArtifact back to path, as a
+// directory if dir is set and as a single file otherwise.
+func writeArtifact(path string, files map[string]string, dir bool) error {
+	if !dir {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(files[""]), 0644)
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
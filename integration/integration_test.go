@@ -34,29 +34,3 @@ func TestIntegrationScenarios(t *testing.T) {
 		runner.Run(scenario)
 	}
 }
-
-// TestMatchPath validates the path matching logic used by the mock server.
-func TestMatchPath(t *testing.T) {
-	tests := []struct {
-		path    string
-		pattern string
-		want    bool
-	}{
-		{"/app-manifests/abc123/conversions", "/app-manifests/*/conversions", true},
-		{"/app-manifests/xyz/conversions", "/app-manifests/*/conversions", true},
-		{"/repos/owner/repo/pulls/123", "/repos/*/*/pulls/*", true},
-		{"/repos/owner/repo/pulls", "/repos/*/*/pulls/*", false}, // different segment count
-		{"/exact/match", "/exact/match", true},
-		{"/exact/mismatch", "/exact/match", false},
-		{"/api/v3/app-manifests/code/conversions", "/api/v3/app-manifests/*/conversions", true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.path+"_"+tt.pattern, func(t *testing.T) {
-			got := matchPath(tt.path, tt.pattern)
-			if got != tt.want {
-				t.Errorf("matchPath(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
-			}
-		})
-	}
-}
@@ -0,0 +1,210 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cruxstack/github-app-setup-go/ghappsetup"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// appPath is the gated application route served in runtime mode. It
+// answers 200 with "app ready" once the runtime serves traffic.
+const appPath = "/app"
+
+// defaultRuntimeTimeout bounds how long an assert_runtime step waits for
+// the runtime to reach the expected state.
+const defaultRuntimeTimeout = 2 * time.Second
+
+// RuntimeConfig enables runtime mode for a scenario. The installer is
+// mounted with Runtime.MountInstaller and served behind Runtime.Handler,
+// along with /healthz and the gated /app and /webhook routes.
+type RuntimeConfig struct {
+	// AllowedPaths are served before the runtime is ready, in addition to
+	// /healthz and the installer paths.
+	AllowedPaths []string `yaml:"allowed_paths,omitempty"`
+}
+
+// scenarioRuntime is the ghappsetup.Runtime under test. Its LoadFunc
+// succeeds once the store is registered and picks up the webhook secret,
+// like a typical app's.
+type scenarioRuntime struct {
+	rt       *ghappsetup.Runtime
+	listener *ghappsetup.ReloadListener
+	signals  chan os.Signal
+	store    *scenarioStore
+	webhook  *scenarioWebhook
+
+	reloads       atomic.Int64
+	failLoads     atomic.Bool
+	webhookSecret atomic.Value
+}
+
+// startScenarioRuntime creates a Runtime for store, mounts the installer,
+// and starts it the way RunHTTP does: loading is retried in the background
+// until the store is registered, and reloads are handled until ctx is
+// canceled. It returns the handler to serve.
+func startScenarioRuntime(ctx context.Context, store *scenarioStore, cfg installer.Config, rc RuntimeConfig) (*scenarioRuntime, http.Handler, error) {
+	sr := &scenarioRuntime{store: store, signals: make(chan os.Signal, 1)}
+	sr.webhookSecret.Store("")
+	sr.webhook = newScenarioWebhook(func() string {
+		return sr.webhookSecret.Load().(string)
+	})
+	rt, err := ghappsetup.NewRuntime(ghappsetup.Config{
+		Store:         store.Store,
+		LoadFunc:      sr.load,
+		AllowedPaths:  append([]string{"/healthz"}, rc.AllowedPaths...),
+		MaxRetries:    1000,
+		RetryInterval: 10 * time.Millisecond,
+		EnvRefresher:  sr,
+		ReloadSignals: sr.signals,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sr.rt = rt
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", rt.HealthHandler())
+	mux.HandleFunc(appPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "app ready (generation %d)\n", rt.Generation())
+	})
+	mux.Handle(webhookPath, sr.webhook.handler)
+	if err := rt.MountInstaller(mux, cfg); err != nil {
+		return nil, nil, err
+	}
+	handler := rt.Handler(mux)
+
+	rt.StartAsync(ctx)
+	sr.listener = rt.StartReloadListener(ctx)
+
+	return sr, handler, nil
+}
+
+// ReResolveChanged implements ghappsetup.EnvRefresher. The runtime calls
+// it at the start of every Reload, but not for startup loads, so it
+// counts reloads from the installer, SIGHUP, and reload steps.
+func (sr *scenarioRuntime) ReResolveChanged(ctx context.Context) ([]string, error) {
+	sr.reloads.Add(1)
+	return nil, nil
+}
+
+// load is the runtime's LoadFunc. The webhook endpoint verifies
+// deliveries with the secret read by the most recent successful load.
+func (sr *scenarioRuntime) load(ctx context.Context) error {
+	if sr.failLoads.Load() {
+		return errors.New("injected load failure")
+	}
+	status, err := sr.rt.Store().Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.Registered {
+		return errors.New("app not registered")
+	}
+	secret, err := storedWebhookSecret(sr.store)
+	if err != nil {
+		return err
+	}
+	sr.webhookSecret.Store(secret)
+	return nil
+}
+
+// stop stops the reload listener.
+func (sr *scenarioRuntime) stop() {
+	if sr.listener != nil {
+		sr.listener.Stop()
+	}
+}
+
+// executeRuntimeStep runs a runtime lifecycle step.
+func (r *ScenarioRunner) executeRuntimeStep(t *testing.T, ctx context.Context, sr *scenarioRuntime, step Step) {
+	t.Helper()
+	if sr == nil {
+		t.Fatalf("%s step requires the scenario runtime section", step.Action)
+	}
+
+	switch step.Action {
+	case "reload":
+		err := sr.rt.Reload(ctx)
+		if step.ExpectError && err == nil {
+			t.Errorf("reload: expected an error")
+		} else if !step.ExpectError && err != nil {
+			t.Errorf("reload: %v", err)
+		}
+	case "sighup":
+		// Delivered through Config.ReloadSignals rather than to the test
+		// process, which other tests' listeners would also receive
+		select {
+		case sr.signals <- syscall.SIGHUP:
+		case <-ctx.Done():
+			t.Fatalf("send SIGHUP: %v", ctx.Err())
+		}
+	case "maintenance":
+		sr.rt.SetMaintenance(step.Enabled)
+	case "fail_loads":
+		sr.failLoads.Store(step.Enabled)
+	case "assert_runtime":
+		r.assertRuntime(t, sr, step)
+	}
+}
+
+// assertRuntime waits for the runtime to reach the state expected by an
+// assert_runtime step, since reloads triggered by the installer and
+// SIGHUP run asynchronously.
+func (r *ScenarioRunner) assertRuntime(t *testing.T, sr *scenarioRuntime, step Step) {
+	t.Helper()
+	timeout := defaultRuntimeTimeout
+	if step.Timeout != "" {
+		d, err := time.ParseDuration(step.Timeout)
+		if err != nil {
+			t.Fatalf("assert_runtime: invalid timeout %q: %v", step.Timeout, err)
+		}
+		timeout = d
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		problems := runtimeMismatches(sr, step)
+		if len(problems) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			for _, problem := range problems {
+				t.Errorf("assert_runtime: %s (after %v)", problem, timeout)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// runtimeMismatches describes each runtime state in step that differs
+// from the current state.
+func runtimeMismatches(sr *scenarioRuntime, step Step) []string {
+	var problems []string
+	check := func(name string, want *bool, got bool) {
+		if want != nil && *want != got {
+			problems = append(problems, fmt.Sprintf("%s = %v, want %v", name, got, *want))
+		}
+	}
+	check("ready", step.ExpectReady, sr.rt.IsReady())
+	check("degraded", step.ExpectDegraded, sr.rt.IsDegraded())
+	check("maintenance", step.ExpectMaintenance, sr.rt.InMaintenance())
+	if step.ExpectReloads != nil && sr.reloads.Load() != int64(*step.ExpectReloads) {
+		problems = append(problems, fmt.Sprintf("reloads = %d, want %d", sr.reloads.Load(), *step.ExpectReloads))
+	}
+	return problems
+}
@@ -12,8 +12,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -22,6 +23,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/githubtest"
 	"github.com/cruxstack/github-app-setup-go/installer"
 )
 
@@ -30,11 +32,23 @@ type Scenario struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description,omitempty"`
 
+	// Smoke marks scenarios that also run against deployed installers in
+	// smoke mode. They may only use request steps whose outcome does not
+	// depend on the installer's registration state.
+	Smoke bool `yaml:"smoke,omitempty"`
+
 	// Config overrides for the installer
 	Config ScenarioConfig `yaml:"config,omitempty"`
 
+	// Store backend: envfile (default), files, ssm-mock, or memory
+	Store string `yaml:"store,omitempty"`
+
+	// Runtime, if set, serves the installer through a ghappsetup.Runtime
+	// and enables the runtime lifecycle steps
+	Runtime *RuntimeConfig `yaml:"runtime,omitempty"`
+
 	// Mock responses from GitHub API
-	MockResponses []MockResponse `yaml:"mock_responses,omitempty"`
+	MockResponses []githubtest.Response `yaml:"mock_responses,omitempty"`
 
 	// Preset credentials to seed the store before the test
 	PresetCredentials *PresetCredentials `yaml:"preset_credentials,omitempty"`
@@ -48,8 +62,15 @@ type Scenario struct {
 	// Expected HTTP calls to mock GitHub
 	ExpectedCalls []ExpectedCall `yaml:"expected_calls,omitempty"`
 
+	// HTTP calls to mock GitHub that must not happen
+	UnexpectedCalls []ExpectedCall `yaml:"unexpected_calls,omitempty"`
+
 	// Whether a reload should have been triggered
 	ExpectReload bool `yaml:"expect_reload,omitempty"`
+
+	// Deliveries the webhook endpoint must have accepted, in order, as
+	// "event" or "event.action"
+	ExpectedDeliveries []string `yaml:"expected_deliveries,omitempty"`
 }
 
 // ScenarioConfig holds installer configuration overrides.
@@ -72,12 +93,51 @@ type PresetCredentials struct {
 
 // Step defines a single action in the test scenario.
 type Step struct {
-	Action             string   `yaml:"action"`
-	Method             string   `yaml:"method,omitempty"`
-	Path               string   `yaml:"path,omitempty"`
-	ExpectStatus       int      `yaml:"expect_status,omitempty"`
-	ExpectBodyContains []string `yaml:"expect_body_contains,omitempty"`
-	ExpectRedirect     string   `yaml:"expect_redirect,omitempty"`
+	Action string `yaml:"action"`
+	Method string `yaml:"method,omitempty"`
+	Path   string `yaml:"path,omitempty"`
+
+	// Request contents. Body and Form are mutually exclusive; Form fields
+	// are sent in the query string for GET and HEAD requests and as a
+	// form-encoded body otherwise. A "Host" header overrides the request
+	// host.
+	Body    string            `yaml:"body,omitempty"`
+	Form    map[string]string `yaml:"form,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Cookies map[string]string `yaml:"cookies,omitempty"`
+
+	ExpectStatus          int               `yaml:"expect_status,omitempty"`
+	ExpectBodyContains    []string          `yaml:"expect_body_contains,omitempty"`
+	ExpectBodyNotContains []string          `yaml:"expect_body_not_contains,omitempty"`
+	ExpectRedirect        string            `yaml:"expect_redirect,omitempty"`
+	ExpectHeaders         map[string]string `yaml:"expect_headers,omitempty"`
+
+	// ExpectJSON maps dotted JSON paths (e.g. "checks.0.name") in the
+	// response body to their expected values.
+	ExpectJSON map[string]any `yaml:"expect_json,omitempty"`
+
+	// ExpectManifest maps dotted JSON paths in the manifest embedded in the
+	// setup page to their expected values.
+	ExpectManifest map[string]any `yaml:"expect_manifest,omitempty"`
+
+	// Runtime lifecycle steps. Enabled switches maintenance mode for
+	// "maintenance" and injected load failures for "fail_loads";
+	// ExpectError applies to "reload". The Expect* pointers are checked by
+	// "assert_runtime", which waits up to Timeout (default 2s) for them.
+	Enabled           bool   `yaml:"enabled,omitempty"`
+	ExpectError       bool   `yaml:"expect_error,omitempty"`
+	ExpectReady       *bool  `yaml:"expect_ready,omitempty"`
+	ExpectDegraded    *bool  `yaml:"expect_degraded,omitempty"`
+	ExpectMaintenance *bool  `yaml:"expect_maintenance,omitempty"`
+	ExpectReloads     *int   `yaml:"expect_reloads,omitempty"`
+	Timeout           string `yaml:"timeout,omitempty"`
+
+	// Webhook delivery steps. Body is sent to /webhook (or Path) as an
+	// Event delivery signed with the webhook secret in the store, or with
+	// Secret if set. DeliveryID defaults to a unique ID per step.
+	Event      string `yaml:"event,omitempty"`
+	DeliveryID string `yaml:"delivery_id,omitempty"`
+	Secret     string `yaml:"secret,omitempty"`
 }
 
 // ExpectedStore defines the expected state of the store after the test.
@@ -86,12 +146,34 @@ type ExpectedStore struct {
 	InstallerDisabled bool   `yaml:"installer_disabled,omitempty"`
 	AppID             int64  `yaml:"app_id,omitempty"`
 	AppSlug           string `yaml:"app_slug,omitempty"`
+
+	// Values and Absent check what the backend persisted, keyed the way
+	// the backend names values: env var names for envfile and memory,
+	// file names for files, and parameter names without the prefix for
+	// ssm-mock.
+	Values map[string]string `yaml:"values,omitempty"`
+	Absent []string          `yaml:"absent,omitempty"`
+
+	// SSMParameterType is the type every parameter must have (ssm-mock
+	// only), e.g. SecureString.
+	SSMParameterType string `yaml:"ssm_parameter_type,omitempty"`
+
+	// Golden names a fixture under testdata/golden that the persisted .env
+	// file (envfile) or directory (files) must match. Secret values are
+	// replaced with placeholders such as {{PRIVATE_KEY}} before comparing.
+	Golden string `yaml:"golden,omitempty"`
 }
 
-// ExpectedCall defines an expected HTTP call to the mock server.
+// ExpectedCall defines an expected HTTP call to the mock server. When
+// body, header, or JSON constraints are set, at least one matching call
+// must satisfy all of them.
 type ExpectedCall struct {
-	Method string `yaml:"method"`
-	Path   string `yaml:"path"`
+	Method       string            `yaml:"method"`
+	Path         string            `yaml:"path"`
+	Times        int               `yaml:"times,omitempty"`
+	BodyContains []string          `yaml:"body_contains,omitempty"`
+	Headers      map[string]string `yaml:"headers,omitempty"`
+	JSON         map[string]any    `yaml:"json,omitempty"`
 }
 
 // LoadScenarios reads scenarios from a YAML file.
@@ -137,7 +219,10 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 		}
 
 		// Create mock GitHub server
-		mockGitHub := NewMockGitHubServer(scenario.MockResponses, r.verbose)
+		mockGitHub := githubtest.NewMock(scenario.MockResponses...)
+		if r.verbose {
+			mockGitHub.Logf = t.Logf
+		}
 		githubServer := httptest.NewUnstartedServer(mockGitHub)
 		githubServer.TLS = &tls.Config{
 			Certificates: []tls.Certificate{tlsCert},
@@ -145,12 +230,11 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 		githubServer.StartTLS()
 		defer githubServer.Close()
 
-		// Create temp directory for .env file
-		tempDir := t.TempDir()
-		envFilePath := filepath.Join(tempDir, ".env")
-
-		// Create store
-		store := configstore.NewLocalEnvFileStore(envFilePath)
+		// Create the scenario's store in a fresh temp directory
+		store, err := newScenarioStore(scenario.Store, t.TempDir())
+		if err != nil {
+			t.Fatalf("create store: %v", err)
+		}
 
 		// Preset credentials if specified
 		if scenario.PresetCredentials != nil {
@@ -181,7 +265,7 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 
 		// Create installer handler
 		cfg := installer.Config{
-			Store:          store,
+			Store:          store.Store,
 			GitHubURL:      githubServer.URL,
 			AppDisplayName: "GitHub App",
 		}
@@ -195,14 +279,41 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 			cfg.WebhookURL = scenario.Config.WebhookURL
 		}
 
-		// Set up reload callback to track reload calls
-		cfg.OnReloadNeeded = func() {
-			reloadCount.Add(1)
-		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-		handler, err := installer.New(cfg)
-		if err != nil {
-			t.Fatalf("create installer: %v", err)
+		var handler http.Handler
+		var appRuntime *scenarioRuntime
+		var appWebhook *scenarioWebhook
+		if scenario.Runtime != nil {
+			// The runtime wires its own reload callback into the installer
+			appRuntime, handler, err = startScenarioRuntime(ctx, store, cfg, *scenario.Runtime)
+			if err != nil {
+				t.Fatalf("create runtime: %v", err)
+			}
+			defer appRuntime.stop()
+			appWebhook = appRuntime.webhook
+		} else {
+			// Set up reload callback to track reload calls
+			cfg.OnReloadNeeded = func() {
+				reloadCount.Add(1)
+			}
+
+			installerHandler, err := installer.New(cfg)
+			if err != nil {
+				t.Fatalf("create installer: %v", err)
+			}
+
+			// Without a runtime, the webhook endpoint reads the secret
+			// from the store on every delivery
+			appWebhook = newScenarioWebhook(func() string {
+				secret, _ := storedWebhookSecret(store)
+				return secret
+			})
+			mux := http.NewServeMux()
+			mux.Handle(webhookPath, appWebhook.handler)
+			mux.Handle("/", installerHandler)
+			handler = mux
 		}
 
 		// Create test server for installer (also HTTPS)
@@ -244,6 +355,10 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 			switch step.Action {
 			case "request":
 				r.executeRequestStep(t, httpClient, installerServer.URL, step)
+			case "webhook":
+				r.executeWebhookStep(t, httpClient, installerServer.URL, store, appWebhook, step)
+			case "reload", "sighup", "maintenance", "fail_loads", "assert_runtime":
+				r.executeRuntimeStep(t, ctx, appRuntime, step)
 			default:
 				t.Fatalf("unknown action: %s", step.Action)
 			}
@@ -253,49 +368,27 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 
 		// Verify expected store state
 		if scenario.ExpectedStore != nil {
-			status, err := store.Status(context.Background())
-			if err != nil {
-				t.Fatalf("get store status: %v", err)
-			}
-
-			if status.Registered != scenario.ExpectedStore.Registered {
-				t.Errorf("store.Registered = %v, want %v", status.Registered, scenario.ExpectedStore.Registered)
-			}
-			if status.InstallerDisabled != scenario.ExpectedStore.InstallerDisabled {
-				t.Errorf("store.InstallerDisabled = %v, want %v", status.InstallerDisabled, scenario.ExpectedStore.InstallerDisabled)
-			}
-			if scenario.ExpectedStore.AppID != 0 && status.AppID != scenario.ExpectedStore.AppID {
-				t.Errorf("store.AppID = %d, want %d", status.AppID, scenario.ExpectedStore.AppID)
-			}
-			if scenario.ExpectedStore.AppSlug != "" && status.AppSlug != scenario.ExpectedStore.AppSlug {
-				t.Errorf("store.AppSlug = %q, want %q", status.AppSlug, scenario.ExpectedStore.AppSlug)
+			for _, problem := range assertStore(context.Background(), store, scenario.ExpectedStore) {
+				t.Error(problem)
 			}
 		}
 
 		// Verify expected HTTP calls to mock GitHub
-		if len(scenario.ExpectedCalls) > 0 {
-			requests := mockGitHub.GetRequests()
-			for _, expected := range scenario.ExpectedCalls {
-				found := false
-				for _, req := range requests {
-					if req.Method == expected.Method && matchPath(req.Path, expected.Path) {
-						found = true
-						break
-					}
-				}
-				if !found {
-					t.Errorf("expected call not found: %s %s", expected.Method, expected.Path)
-					t.Logf("actual calls:")
-					for _, req := range requests {
-						t.Logf("  %s %s", req.Method, req.Path)
-					}
-				}
+		assertCalls(t, mockGitHub, scenario.ExpectedCalls, scenario.UnexpectedCalls)
+
+		// Verify the webhook endpoint accepted the expected deliveries
+		if scenario.ExpectedDeliveries != nil {
+			if got := appWebhook.deliveries(); !slices.Equal(got, scenario.ExpectedDeliveries) {
+				t.Errorf("webhook deliveries = %q, want %q", got, scenario.ExpectedDeliveries)
 			}
 		}
 
 		// Verify reload was triggered if expected
 		if scenario.ExpectReload {
 			count := reloadCount.Load()
+			if appRuntime != nil {
+				count = appRuntime.reloads.Load()
+			}
 			if count == 0 {
 				t.Errorf("expected reload to be triggered, but it was not")
 			}
@@ -304,32 +397,77 @@ func (r *ScenarioRunner) Run(scenario Scenario) {
 }
 
 func (r *ScenarioRunner) executeRequestStep(t *testing.T, client *http.Client, baseURL string, step Step) {
-	url := baseURL + step.Path
-	req, err := http.NewRequest(step.Method, url, nil)
+	problems, err := requestMismatches(client, baseURL, step)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
+
+// requestMismatches sends the request described by step to baseURL and
+// describes each way the response differs from the step's expectations.
+// It returns an error if the request could not be made.
+func requestMismatches(client *http.Client, baseURL string, step Step) ([]string, error) {
+	req, err := newStepRequest(baseURL, step)
 	if err != nil {
-		t.Fatalf("create request: %v", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		t.Fatalf("execute request: %v", err)
+		return nil, fmt.Errorf("execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		t.Fatalf("read response body: %v", err)
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var problems []string
+	fail := func(format string, args ...any) {
+		prefix := fmt.Sprintf("%s %s: ", step.Method, step.Path)
+		problems = append(problems, prefix+fmt.Sprintf(format, args...))
 	}
 
 	// Check status code
 	if step.ExpectStatus != 0 && resp.StatusCode != step.ExpectStatus {
-		t.Errorf("%s %s: status = %d, want %d\nBody: %s", step.Method, step.Path, resp.StatusCode, step.ExpectStatus, string(body))
+		fail("status = %d, want %d\nBody: %s", resp.StatusCode, step.ExpectStatus, string(body))
 	}
 
 	// Check body contains expected strings
 	for _, expected := range step.ExpectBodyContains {
 		if !strings.Contains(string(body), expected) {
-			t.Errorf("%s %s: body does not contain %q\nBody: %s", step.Method, step.Path, expected, string(body))
+			fail("body does not contain %q\nBody: %s", expected, string(body))
+		}
+	}
+
+	for _, unexpected := range step.ExpectBodyNotContains {
+		if strings.Contains(string(body), unexpected) {
+			fail("body contains %q\nBody: %s", unexpected, string(body))
+		}
+	}
+
+	for _, problem := range headerMismatches(resp.Header, step.ExpectHeaders) {
+		fail("%s", problem)
+	}
+
+	if len(step.ExpectJSON) > 0 {
+		for _, problem := range jsonMismatches(body, step.ExpectJSON) {
+			fail("%s\nBody: %s", problem, string(body))
+		}
+	}
+
+	if len(step.ExpectManifest) > 0 {
+		manifest, err := extractManifest(body)
+		if err != nil {
+			fail("%v", err)
+		} else {
+			for _, problem := range jsonMismatches(manifest, step.ExpectManifest) {
+				fail("manifest %s\nManifest: %s", problem, manifest)
+			}
 		}
 	}
 
@@ -337,7 +475,55 @@ func (r *ScenarioRunner) executeRequestStep(t *testing.T, client *http.Client, b
 	if step.ExpectRedirect != "" {
 		location := resp.Header.Get("Location")
 		if location != step.ExpectRedirect {
-			t.Errorf("%s %s: redirect = %q, want %q", step.Method, step.Path, location, step.ExpectRedirect)
+			fail("redirect = %q, want %q", location, step.ExpectRedirect)
 		}
 	}
+	return problems, nil
+}
+
+// newStepRequest builds the HTTP request described by a request step.
+func newStepRequest(baseURL string, step Step) (*http.Request, error) {
+	if step.Body != "" && len(step.Form) > 0 {
+		return nil, fmt.Errorf("step %s %s: body and form are mutually exclusive", step.Method, step.Path)
+	}
+
+	target := baseURL + step.Path
+	body := step.Body
+	var form url.Values
+	if len(step.Form) > 0 {
+		form = make(url.Values)
+		for k, v := range step.Form {
+			form.Set(k, v)
+		}
+	}
+
+	inQuery := step.Method == "" || step.Method == http.MethodGet || step.Method == http.MethodHead
+	if form != nil && inQuery {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + form.Encode()
+	} else if form != nil {
+		body = form.Encode()
+	}
+
+	req, err := http.NewRequest(step.Method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if form != nil && !inQuery {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for k, v := range step.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	for name, value := range step.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	return req, nil
 }
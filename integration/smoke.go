@@ -0,0 +1,97 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultSmokeTimeout bounds each smoke request.
+const DefaultSmokeTimeout = 10 * time.Second
+
+// SmokeScenarios returns the scenarios marked smoke, in order.
+func SmokeScenarios(scenarios []Scenario) []Scenario {
+	var smoke []Scenario
+	for _, scenario := range scenarios {
+		if scenario.Smoke {
+			smoke = append(smoke, scenario)
+		}
+	}
+	return smoke
+}
+
+// NewSmokeClient returns the HTTP client used for smoke runs. Like the
+// scenario runner's client, it does not follow redirects. Insecure skips
+// certificate verification, for staging installers with self-signed
+// certificates.
+func NewSmokeClient(insecure bool, timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultSmokeTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: timeout,
+	}
+}
+
+// RunSmoke runs the request steps of a smoke scenario against a deployed
+// installer at baseURL and describes each failed expectation. Nothing is
+// mocked or seeded, so the store, GitHub call, reload, and delivery
+// expectations are not checked. It returns an error if the scenario
+// cannot run against a live endpoint or a request could not be made.
+func RunSmoke(client *http.Client, baseURL string, scenario Scenario) ([]string, error) {
+	if err := validateSmoke(scenario); err != nil {
+		return nil, err
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	var problems []string
+	for _, step := range scenario.Steps {
+		stepProblems, err := requestMismatches(client, baseURL, step)
+		if err != nil {
+			return problems, err
+		}
+		problems = append(problems, stepProblems...)
+	}
+	return problems, nil
+}
+
+// validateSmoke reports why a scenario cannot run in smoke mode: it
+// depends on state the runner can only set up locally, or uses steps
+// other than plain requests.
+func validateSmoke(scenario Scenario) error {
+	var errs []error
+	if len(scenario.MockResponses) > 0 {
+		errs = append(errs, errors.New("mock_responses require the mock GitHub server"))
+	}
+	if scenario.PresetCredentials != nil {
+		errs = append(errs, errors.New("preset_credentials require a local store"))
+	}
+	if scenario.Runtime != nil {
+		errs = append(errs, errors.New("runtime requires a local runtime"))
+	}
+	for i, step := range scenario.Steps {
+		if step.Action != "request" {
+			errs = append(errs, fmt.Errorf("step %d: %s steps are not supported", i+1, step.Action))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("scenario %s cannot run in smoke mode: %w", scenario.Name, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// TestSmokeScenarios runs the smoke scenarios against SMOKE_BASE_URL, or
+// against a local installer when it is unset so they stay valid.
+func TestSmokeScenarios(t *testing.T) {
+	scenarios, err := LoadScenarios(filepath.Join("testdata", "scenarios.yaml"))
+	if err != nil {
+		t.Fatalf("load scenarios: %v", err)
+	}
+	smoke := SmokeScenarios(scenarios)
+	if len(smoke) == 0 {
+		t.Fatal("no smoke scenarios found in scenarios.yaml")
+	}
+
+	baseURL := os.Getenv("SMOKE_BASE_URL")
+	insecure := os.Getenv("SMOKE_INSECURE") == "1" || os.Getenv("SMOKE_INSECURE") == "true"
+	if baseURL == "" {
+		handler, err := installer.New(installer.Config{
+			Store:     configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env")),
+			GitHubURL: "https://github.invalid",
+		})
+		if err != nil {
+			t.Fatalf("create installer: %v", err)
+		}
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+		baseURL = server.URL
+		insecure = true
+	}
+
+	client := NewSmokeClient(insecure, DefaultSmokeTimeout)
+	for _, scenario := range smoke {
+		t.Run(scenario.Name, func(t *testing.T) {
+			problems, err := RunSmoke(client, baseURL, scenario)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, problem := range problems {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+func TestRunSmoke_RejectsLocalOnlyScenarios(t *testing.T) {
+	scenario := Scenario{
+		Name:              "local_only",
+		PresetCredentials: &PresetCredentials{AppID: 1},
+		Steps: []Step{
+			{Action: "request", Method: "GET", Path: "/setup"},
+			{Action: "reload"},
+		},
+	}
+	_, err := RunSmoke(NewSmokeClient(false, 0), "https://example.invalid", scenario)
+	if err == nil {
+		t.Fatal("RunSmoke() error = nil, want error")
+	}
+	for _, want := range []string{"preset_credentials", "step 2: reload"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("RunSmoke() error = %q, want it to mention %q", err, want)
+		}
+	}
+}
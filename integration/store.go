@@ -0,0 +1,353 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// Store backends a scenario can select with the store field.
+const (
+	backendEnvFile = "envfile"
+	backendFiles   = "files"
+	backendSSMMock = "ssm-mock"
+	backendMemory  = "memory"
+)
+
+// ssmPrefix is the parameter prefix used by the ssm-mock backend.
+const ssmPrefix = "/integration/app/"
+
+// scenarioStore is a store under test together with raw access to what
+// it persisted, for backend-specific assertions.
+type scenarioStore struct {
+	configstore.Store
+
+	// backend is the backend name from the scenario.
+	backend string
+
+	// values returns the raw persisted values keyed the way the backend
+	// names them: env var names for envfile and memory, file names for
+	// files, and parameter names relative to the prefix for ssm-mock.
+	values func() (map[string]string, error)
+
+	// artifact is the file or directory the backend writes, compared
+	// against golden fixtures. It is empty for backends without one.
+	artifact string
+
+	// ssm is set for the ssm-mock backend.
+	ssm *fakeSSM
+}
+
+// newScenarioStore creates the store backend named by backend in dir. An
+// empty backend selects envfile.
+func newScenarioStore(backend, dir string) (*scenarioStore, error) {
+	switch backend {
+	case "", backendEnvFile:
+		path := filepath.Join(dir, ".env")
+		return &scenarioStore{
+			Store:    configstore.NewLocalEnvFileStore(path),
+			backend:  backendEnvFile,
+			values:   func() (map[string]string, error) { return readEnvFile(path) },
+			artifact: path,
+		}, nil
+	case backendFiles:
+		filesDir := filepath.Join(dir, "app")
+		return &scenarioStore{
+			Store:    configstore.NewLocalFileStore(filesDir),
+			backend:  backendFiles,
+			values:   func() (map[string]string, error) { return readDir(filesDir) },
+			artifact: filesDir,
+		}, nil
+	case backendSSMMock:
+		client := newFakeSSM()
+		store, err := configstore.NewAWSSSMStore(ssmPrefix, configstore.WithSSMClient(client))
+		if err != nil {
+			return nil, err
+		}
+		return &scenarioStore{
+			Store:   store,
+			backend: backendSSMMock,
+			values:  func() (map[string]string, error) { return client.values(ssmPrefix), nil },
+			ssm:     client,
+		}, nil
+	case backendMemory:
+		store := newMemoryStore()
+		return &scenarioStore{
+			Store:   store,
+			backend: backendMemory,
+			values:  func() (map[string]string, error) { return store.snapshot(), nil },
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// assertStore checks the store state and backend-specific expectations.
+func assertStore(ctx context.Context, store *scenarioStore, expected *ExpectedStore) []string {
+	var problems []string
+
+	status, err := store.Status(ctx)
+	if err != nil {
+		return []string{fmt.Sprintf("get store status: %v", err)}
+	}
+	if status.Registered != expected.Registered {
+		problems = append(problems, fmt.Sprintf("store.Registered = %v, want %v", status.Registered, expected.Registered))
+	}
+	if status.InstallerDisabled != expected.InstallerDisabled {
+		problems = append(problems, fmt.Sprintf("store.InstallerDisabled = %v, want %v", status.InstallerDisabled, expected.InstallerDisabled))
+	}
+	if expected.AppID != 0 && status.AppID != expected.AppID {
+		problems = append(problems, fmt.Sprintf("store.AppID = %d, want %d", status.AppID, expected.AppID))
+	}
+	if expected.AppSlug != "" && status.AppSlug != expected.AppSlug {
+		problems = append(problems, fmt.Sprintf("store.AppSlug = %q, want %q", status.AppSlug, expected.AppSlug))
+	}
+
+	if expected.Golden != "" {
+		problems = append(problems, assertGolden(store, expected.Golden)...)
+	}
+
+	if len(expected.Values) == 0 && len(expected.Absent) == 0 && expected.SSMParameterType == "" {
+		return problems
+	}
+
+	values, err := store.values()
+	if err != nil {
+		return append(problems, fmt.Sprintf("read %s store: %v", store.backend, err))
+	}
+	for key, want := range expected.Values {
+		got, ok := values[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s store: %s missing", store.backend, key))
+		} else if got != want {
+			problems = append(problems, fmt.Sprintf("%s store: %s = %q, want %q", store.backend, key, got, want))
+		}
+	}
+	for _, key := range expected.Absent {
+		if _, ok := values[key]; ok {
+			problems = append(problems, fmt.Sprintf("%s store: %s should not be set", store.backend, key))
+		}
+	}
+
+	if expected.SSMParameterType != "" {
+		if store.ssm == nil {
+			return append(problems, fmt.Sprintf("ssm_parameter_type requires the %s store, scenario uses %s", backendSSMMock, store.backend))
+		}
+		for name, typ := range store.ssm.types() {
+			if string(typ) != expected.SSMParameterType {
+				problems = append(problems, fmt.Sprintf("ssm parameter %s type = %s, want %s", name, typ, expected.SSMParameterType))
+			}
+		}
+	}
+	return problems
+}
+
+// readEnvFile reads the values of a .env file, unquoting them the same
+// way configstore does.
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 {
+			if strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+				value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+			} else if strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+				value = value[1 : len(value)-1]
+			}
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, nil
+}
+
+// readDir reads every regular file in dir, keyed by file name.
+func readDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		values[entry.Name()] = string(data)
+	}
+	return values, nil
+}
+
+// fakeSSM is an in-memory configstore.SSMClient.
+type fakeSSM struct {
+	mu     sync.Mutex
+	params map[string]types.Parameter
+}
+
+func newFakeSSM() *fakeSSM {
+	return &fakeSSM{params: make(map[string]types.Parameter)}
+}
+
+func (f *fakeSSM) PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.ToString(params.Name)
+	if _, exists := f.params[name]; exists && !aws.ToBool(params.Overwrite) {
+		return nil, &types.ParameterAlreadyExists{}
+	}
+	f.params[name] = types.Parameter{
+		Name:  params.Name,
+		Value: params.Value,
+		Type:  params.Type,
+	}
+	return &ssm.PutParameterOutput{}, nil
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	param, ok := f.params[aws.ToString(params.Name)]
+	if !ok {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &param}, nil
+}
+
+// values returns the parameter values under prefix, keyed by name with
+// the prefix removed.
+func (f *fakeSSM) values(prefix string) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	values := make(map[string]string)
+	for name, param := range f.params {
+		if key, ok := strings.CutPrefix(name, prefix); ok {
+			values[key] = aws.ToString(param.Value)
+		}
+	}
+	return values
+}
+
+// types returns the type of every stored parameter.
+func (f *fakeSSM) types() map[string]types.ParameterType {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]types.ParameterType)
+	for name, param := range f.params {
+		out[name] = param.Type
+	}
+	return out
+}
+
+// memoryStore is a configstore.Store that keeps credentials in a map,
+// using the same keys as the envfile backend.
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string]string)}
+}
+
+func (s *memoryStore) Save(ctx context.Context, creds *configstore.AppCredentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range creds.CustomFields {
+		if value != "" {
+			s.values[key] = value
+		}
+	}
+	s.values[configstore.EnvGitHubAppID] = strconv.FormatInt(creds.AppID, 10)
+	s.values[configstore.EnvGitHubWebhookSecret] = creds.WebhookSecret
+	s.values[configstore.EnvGitHubClientID] = creds.ClientID
+	s.values[configstore.EnvGitHubClientSecret] = creds.ClientSecret
+	s.values[configstore.EnvGitHubAppPrivateKey] = creds.PrivateKey
+	if creds.AppSlug != "" {
+		s.values[configstore.EnvGitHubAppSlug] = creds.AppSlug
+	}
+	if creds.HTMLURL != "" {
+		s.values[configstore.EnvGitHubAppHTMLURL] = creds.HTMLURL
+	}
+	return nil
+}
+
+func (s *memoryStore) Status(ctx context.Context) (*configstore.InstallerStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := &configstore.InstallerStatus{}
+	for _, key := range []string{
+		configstore.EnvGitHubAppID,
+		configstore.EnvGitHubWebhookSecret,
+		configstore.EnvGitHubClientID,
+		configstore.EnvGitHubClientSecret,
+		configstore.EnvGitHubAppPrivateKey,
+	} {
+		if s.values[key] == "" {
+			return status, nil
+		}
+	}
+	status.Registered = true
+	status.AppID, _ = strconv.ParseInt(s.values[configstore.EnvGitHubAppID], 10, 64)
+	status.AppSlug = s.values[configstore.EnvGitHubAppSlug]
+	status.HTMLURL = s.values[configstore.EnvGitHubAppHTMLURL]
+	switch strings.ToLower(s.values[configstore.EnvGitHubAppInstallerEnabled]) {
+	case "false", "0", "no":
+		status.InstallerDisabled = true
+	}
+	return status, nil
+}
+
+func (s *memoryStore) DisableInstaller(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[configstore.EnvGitHubAppInstallerEnabled] = "false"
+	return nil
+}
+
+// snapshot returns a copy of the stored values.
+func (s *memoryStore) snapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string, len(s.values))
+	for key, value := range s.values {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/webhook"
+)
+
+// webhookPath is the route the scenario app serves webhook deliveries on.
+const webhookPath = "/webhook"
+
+// scenarioWebhook is the app's webhook endpoint: a webhook.Handler
+// verifying signatures with the secret the store holds, and a router that
+// records every delivery it accepts.
+type scenarioWebhook struct {
+	handler http.Handler
+
+	mu       sync.Mutex
+	received []string
+
+	seq atomic.Int64
+}
+
+// newScenarioWebhook creates the webhook endpoint. secret supplies the
+// verification secret for each delivery.
+func newScenarioWebhook(secret webhook.SecretFunc) *scenarioWebhook {
+	sw := &scenarioWebhook{}
+	router := webhook.NewRouter()
+	router.Fallback(sw.record)
+	sw.handler = webhook.NewHandler(router.Dispatch, webhook.WithSecretFunc(secret))
+	return sw
+}
+
+// record notes an accepted delivery as "event" or "event.action".
+func (sw *scenarioWebhook) record(ctx context.Context, d *webhook.Delivery) error {
+	name := d.Event
+	if action := d.Action(); action != "" {
+		name += "." + action
+	}
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.received = append(sw.received, name)
+	return nil
+}
+
+// deliveries returns the deliveries accepted so far, in order.
+func (sw *scenarioWebhook) deliveries() []string {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return slices.Clone(sw.received)
+}
+
+// webhookSecretKey returns the key the backend stores the webhook secret
+// under.
+func webhookSecretKey(backend string) string {
+	if backend == backendFiles {
+		return "webhook-secret"
+	}
+	return configstore.EnvGitHubWebhookSecret
+}
+
+// storedWebhookSecret reads the webhook secret the store currently holds,
+// which is "" until credentials are saved.
+func storedWebhookSecret(store *scenarioStore) (string, error) {
+	values, err := store.values()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(values[webhookSecretKey(store.backend)]), nil
+}
+
+// executeWebhookStep signs step.Body with the stored webhook secret, or
+// step.Secret if set, and posts it to the webhook endpoint as a step.Event
+// delivery. The response is checked like a request step's.
+func (r *ScenarioRunner) executeWebhookStep(t *testing.T, client *http.Client, baseURL string, store *scenarioStore, sw *scenarioWebhook, step Step) {
+	t.Helper()
+	if step.Event == "" {
+		t.Fatalf("webhook step requires an event")
+	}
+
+	secret := step.Secret
+	if secret == "" {
+		stored, err := storedWebhookSecret(store)
+		if err != nil {
+			t.Fatalf("webhook: read stored secret: %v", err)
+		}
+		if stored == "" {
+			t.Fatalf("webhook: the store holds no webhook secret to sign with")
+		}
+		secret = stored
+	}
+
+	delivery := step.DeliveryID
+	if delivery == "" {
+		delivery = fmt.Sprintf("scenario-delivery-%d", sw.seq.Add(1))
+	}
+
+	step.Method = http.MethodPost
+	if step.Path == "" {
+		step.Path = webhookPath
+	}
+	headers := map[string]string{
+		"Content-Type":          "application/json",
+		webhook.HeaderEvent:     step.Event,
+		webhook.HeaderDelivery:  delivery,
+		webhook.HeaderSignature: webhook.Sign([]byte(step.Body), secret),
+	}
+	for key, value := range step.Headers {
+		headers[key] = value
+	}
+	step.Headers = headers
+
+	r.executeRequestStep(t, client, baseURL, step)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package lambdaext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Client reads the credentials served by the extension.
+type Client struct {
+	// URL is the credential endpoint. Defaults to CredentialsPath on
+	// 127.0.0.1 and GHAPPSETUP_EXTENSION_PORT, or DefaultPort.
+	URL string
+
+	// Token is sent in TokenHeader. Defaults to AWS_SESSION_TOKEN.
+	Token string
+
+	// HTTPClient makes the requests. Defaults to a client with a
+	// 10-second timeout.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client for the extension running in the current
+// execution environment.
+func NewClient() *Client {
+	return &Client{
+		URL:        "http://127.0.0.1:" + strconv.Itoa(envPort()) + CredentialsPath,
+		Token:      os.Getenv(envSessionToken),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Values returns the credentials keyed by environment variable name, as
+// the stores save them (GITHUB_APP_ID, GITHUB_APP_PRIVATE_KEY, and so on,
+// plus any custom fields).
+func (c *Client) Values(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set(TokenHeader, c.Token)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the credentials extension: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			return nil, fmt.Errorf("credentials extension: %s", body.Error)
+		}
+		return nil, fmt.Errorf("credentials extension: %s", resp.Status)
+	}
+	var values map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials from the extension: %w", err)
+	}
+	return values, nil
+}
+
+// ResolveEnvironment sets the credentials as environment variables, where
+// the rest of the library and LoadFunc implementations read them.
+func (c *Client) ResolveEnvironment(ctx context.Context) error {
+	values, err := c.Values(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ResolveEnvironment sets the credentials served by the extension in the
+// current execution environment as environment variables. It is meant to
+// be called from a Runtime's LoadFunc:
+//
+//	runtime, _ := ghappsetup.NewRuntime(ghappsetup.Config{
+//	    LoadFunc: lambdaext.ResolveEnvironment,
+//	})
+func ResolveEnvironment(ctx context.Context) error {
+	return NewClient().ResolveEnvironment(ctx)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package lambdaext
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func newTestClient(t *testing.T, loader *fakeLoader, token string) *Client {
+	t.Helper()
+	e := New(loader, WithToken("session-token"))
+	srv := httptest.NewServer(e.Handler())
+	t.Cleanup(srv.Close)
+	return &Client{URL: srv.URL + CredentialsPath, Token: token}
+}
+
+func TestClient_ResolveEnvironment(t *testing.T) {
+	t.Setenv(configstore.EnvGitHubAppID, "")
+	t.Setenv(configstore.EnvGitHubAppPrivateKey, "")
+	t.Setenv("LOG_LEVEL", "")
+
+	loader := newFakeLoader()
+	client := newTestClient(t, loader, "session-token")
+	if err := client.ResolveEnvironment(context.Background()); err != nil {
+		t.Fatalf("ResolveEnvironment() error = %v", err)
+	}
+	if got := os.Getenv(configstore.EnvGitHubAppID); got != "123" {
+		t.Errorf("%s = %q", configstore.EnvGitHubAppID, got)
+	}
+	if got := os.Getenv(configstore.EnvGitHubAppPrivateKey); got != loader.creds.PrivateKey {
+		t.Errorf("%s = %q", configstore.EnvGitHubAppPrivateKey, got)
+	}
+	if got := os.Getenv("LOG_LEVEL"); got != "debug" {
+		t.Errorf("LOG_LEVEL = %q", got)
+	}
+
+	// Later requests are served from the cache
+	if _, err := client.Values(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if loader.count() != 1 {
+		t.Errorf("loads = %d, want 1", loader.count())
+	}
+}
+
+func TestClient_Values_Token(t *testing.T) {
+	for _, token := range []string{"", "other-token"} {
+		_, err := newTestClient(t, newFakeLoader(), token).Values(context.Background())
+		if err == nil || !strings.Contains(err.Error(), TokenHeader) {
+			t.Errorf("Values() with token %q error = %v, want a token error", token, err)
+		}
+	}
+}
+
+func TestClient_Values_LoadFailure(t *testing.T) {
+	loader := newFakeLoader()
+	loader.set("", errors.New("AccessDeniedException"))
+	client := newTestClient(t, loader, "session-token")
+	_, err := client.Values(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "AccessDeniedException") {
+		t.Fatalf("Values() error = %v, want the store error", err)
+	}
+
+	// Nothing was cached, so the next request tries the store again
+	loader.set("hook", nil)
+	values, err := client.Values(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[configstore.EnvGitHubWebhookSecret] != "hook" {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestClient_Values_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+	client := &Client{URL: srv.URL + CredentialsPath}
+	if _, err := client.Values(context.Background()); err == nil {
+		t.Error("Values() without an extension succeeded")
+	}
+}
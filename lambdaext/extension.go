@@ -0,0 +1,412 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package lambdaext caches GitHub App credentials in an AWS Lambda
+// extension. The extension (see cmd/ghappextension) runs beside the
+// function in the same execution environment, loads the credentials from
+// the configured store once, and serves them to the handler over a
+// localhost endpoint, so handler cold starts and reloads skip the store.
+// The handler reads them with Client, typically from its LoadFunc.
+package lambdaext
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// Environment variables read by the extension and Client. Both run in the
+// function's environment, so they see the same values.
+const (
+	EnvPort            = "GHAPPSETUP_EXTENSION_PORT"
+	EnvRefreshInterval = "GHAPPSETUP_EXTENSION_REFRESH_INTERVAL"
+)
+
+const (
+	// DefaultPort is the localhost port the extension listens on.
+	DefaultPort = 2790
+
+	// DefaultRefreshInterval is how long the extension serves credentials
+	// before reading the store again.
+	DefaultRefreshInterval = 5 * time.Minute
+
+	// CredentialsPath is the extension endpoint serving the credentials.
+	CredentialsPath = "/credentials"
+
+	// TokenHeader carries the function's AWS_SESSION_TOKEN on credential
+	// requests, so only code holding the function's AWS credentials can
+	// read them (e.g. not a request forged through an SSRF).
+	TokenHeader = "X-Ghappsetup-Extension-Token"
+)
+
+const (
+	envRuntimeAPI   = "AWS_LAMBDA_RUNTIME_API"
+	envSessionToken = "AWS_SESSION_TOKEN"
+
+	extensionAPIVersion = "2020-01-01"
+	headerName          = "Lambda-Extension-Name"
+	headerIdentifier    = "Lambda-Extension-Identifier"
+	headerErrorType     = "Lambda-Extension-Function-Error-Type"
+
+	eventInvoke   = "INVOKE"
+	eventShutdown = "SHUTDOWN"
+)
+
+// Extension is a Lambda external extension serving cached credentials.
+type Extension struct {
+	loader          configstore.CredentialLoader
+	name            string
+	addr            string
+	runtimeAPI      string
+	token           string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	loadMu sync.Mutex // serializes store reads
+
+	mu       sync.RWMutex
+	values   map[string]string
+	loadedAt time.Time
+}
+
+// Option configures an Extension.
+type Option func(*Extension)
+
+// WithName sets the name the extension registers with, which must match
+// its file name in /opt/extensions. Defaults to the executable's name.
+func WithName(name string) Option {
+	return func(e *Extension) {
+		e.name = name
+	}
+}
+
+// WithAddr sets the address the credential endpoint listens on. Defaults
+// to 127.0.0.1 and GHAPPSETUP_EXTENSION_PORT, or DefaultPort.
+func WithAddr(addr string) Option {
+	return func(e *Extension) {
+		e.addr = addr
+	}
+}
+
+// WithRuntimeAPI sets the host and port of the Lambda Extensions API.
+// Defaults to AWS_LAMBDA_RUNTIME_API.
+func WithRuntimeAPI(hostport string) Option {
+	return func(e *Extension) {
+		e.runtimeAPI = hostport
+	}
+}
+
+// WithToken sets the value TokenHeader must carry. Defaults to
+// AWS_SESSION_TOKEN; an empty token disables the check.
+func WithToken(token string) Option {
+	return func(e *Extension) {
+		e.token = token
+	}
+}
+
+// WithRefreshInterval sets how long credentials are served before the
+// store is read again. Defaults to GHAPPSETUP_EXTENSION_REFRESH_INTERVAL,
+// or DefaultRefreshInterval.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(e *Extension) {
+		e.refreshInterval = d
+	}
+}
+
+// WithHTTPClient sets the client used for the Extensions API. Its timeout
+// must allow for the long poll of the next event, so the default has none.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Extension) {
+		e.client = client
+	}
+}
+
+// New creates an Extension serving the credentials loader reads.
+func New(loader configstore.CredentialLoader, opts ...Option) *Extension {
+	e := &Extension{
+		loader:          loader,
+		name:            filepath.Base(os.Args[0]),
+		addr:            "127.0.0.1:" + strconv.Itoa(envPort()),
+		runtimeAPI:      os.Getenv(envRuntimeAPI),
+		token:           os.Getenv(envSessionToken),
+		refreshInterval: DefaultRefreshInterval,
+		client:          &http.Client{},
+		now:             time.Now,
+	}
+	if v := os.Getenv(EnvRefreshInterval); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			e.refreshInterval = d
+		}
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// envPort returns GHAPPSETUP_EXTENSION_PORT, or DefaultPort if it is unset
+// or invalid.
+func envPort() int {
+	if n, err := strconv.Atoi(os.Getenv(EnvPort)); err == nil && n > 0 && n < 1<<16 {
+		return n
+	}
+	return DefaultPort
+}
+
+// Run registers the extension, serves the credential endpoint, and
+// processes lifecycle events until Lambda shuts the environment down.
+//
+// The credentials are loaded during the init phase, before the function's
+// first invocation. Once they are older than the refresh interval, the next
+// INVOKE event reads the store again while the handler runs; the handler
+// keeps receiving the cached copy meanwhile, and a failed read keeps it in
+// use. Refreshes happen on invocations because Lambda freezes the
+// environment between them.
+func (e *Extension) Run(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+	if e.runtimeAPI == "" {
+		return fmt.Errorf("%s is not set; the extension must run in Lambda", envRuntimeAPI)
+	}
+
+	id, err := e.register(ctx)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", e.addr)
+	if err != nil {
+		err = fmt.Errorf("failed to listen on %s: %w", e.addr, err)
+		e.reportError(ctx, id, "init", "Extension.ListenError", err)
+		return err
+	}
+	srv := &http.Server{Handler: e.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+	defer srv.Close()
+	log.Infof("[lambdaext] serving credentials on http://%s%s", e.addr, CredentialsPath)
+
+	if err := e.load(ctx); err != nil {
+		// Not fatal: the store may not be registered yet, and each request
+		// for the credentials tries again
+		log.Warnf("[lambdaext] failed to load credentials during init: %v", err)
+	}
+
+	for {
+		event, err := e.next(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			e.reportError(ctx, id, "exit", "Extension.EventError", err)
+			return err
+		}
+		switch event.EventType {
+		case eventShutdown:
+			log.Infof("[lambdaext] shutting down: %s", event.ShutdownReason)
+			return nil
+		case eventInvoke:
+			if e.refreshDue() {
+				if err := e.load(ctx); err != nil {
+					log.Warnf("[lambdaext] refresh failed, serving the cached credentials: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Handler returns the handler of the credential endpoint. A GET of
+// CredentialsPath returns the credentials as a JSON object keyed by
+// environment variable name. If none are cached it loads them first, and
+// responds 503 if that fails.
+func (e *Extension) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+CredentialsPath, func(w http.ResponseWriter, r *http.Request) {
+		if e.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(e.token)) != 1 {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "missing or invalid " + TokenHeader})
+			return
+		}
+		values, err := e.credentials(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, values)
+	})
+	return mux
+}
+
+// credentials returns the cached credentials, loading them if there are
+// none.
+func (e *Extension) credentials(ctx context.Context) (map[string]string, error) {
+	e.mu.RLock()
+	values := e.values
+	e.mu.RUnlock()
+	if values != nil {
+		return values, nil
+	}
+
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+	e.mu.RLock()
+	values = e.values
+	e.mu.RUnlock()
+	if values != nil {
+		// Loaded while this request waited
+		return values, nil
+	}
+	if err := e.loadLocked(ctx); err != nil {
+		return nil, err
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.values, nil
+}
+
+func (e *Extension) refreshDue() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.values == nil || e.now().Sub(e.loadedAt) >= e.refreshInterval
+}
+
+// load reads the credentials from the store. On failure the cached
+// credentials, if any, stay in use.
+func (e *Extension) load(ctx context.Context) error {
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+	return e.loadLocked(ctx)
+}
+
+func (e *Extension) loadLocked(ctx context.Context) error {
+	creds, err := e.loader.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+	values := credentialValues(creds)
+	e.mu.Lock()
+	e.values, e.loadedAt = values, e.now()
+	e.mu.Unlock()
+	return nil
+}
+
+// credentialValues returns the credentials keyed by the environment
+// variables the stores use.
+func credentialValues(creds *configstore.AppCredentials) map[string]string {
+	values := make(map[string]string, len(creds.CustomFields)+7)
+	for key, value := range creds.CustomFields {
+		values[key] = value
+	}
+	values[configstore.EnvGitHubAppID] = strconv.FormatInt(creds.AppID, 10)
+	values[configstore.EnvGitHubAppPrivateKey] = creds.PrivateKey
+	values[configstore.EnvGitHubWebhookSecret] = creds.WebhookSecret
+	values[configstore.EnvGitHubClientID] = creds.ClientID
+	values[configstore.EnvGitHubClientSecret] = creds.ClientSecret
+	if creds.AppSlug != "" {
+		values[configstore.EnvGitHubAppSlug] = creds.AppSlug
+	}
+	if creds.HTMLURL != "" {
+		values[configstore.EnvGitHubAppHTMLURL] = creds.HTMLURL
+	}
+	return values
+}
+
+// event is an event from the Extensions API.
+type event struct {
+	EventType      string `json:"eventType"`
+	RequestID      string `json:"requestId"`
+	DeadlineMs     int64  `json:"deadlineMs"`
+	ShutdownReason string `json:"shutdownReason"`
+}
+
+// register registers the extension for INVOKE and SHUTDOWN events and
+// returns its identifier.
+func (e *Extension) register(ctx context.Context) (string, error) {
+	body, _ := json.Marshal(map[string][]string{"events": {eventInvoke, eventShutdown}})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.apiURL("register"), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(headerName, e.name)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to register extension: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to register extension: %s", responseError(resp))
+	}
+	id := resp.Header.Get(headerIdentifier)
+	if id == "" {
+		return "", errors.New("failed to register extension: no identifier returned")
+	}
+	return id, nil
+}
+
+// next blocks until the next lifecycle event.
+func (e *Extension) next(ctx context.Context, id string) (*event, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.apiURL("event/next"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(headerIdentifier, id)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get next event: %s", responseError(resp))
+	}
+	var ev event
+	if err := json.NewDecoder(resp.Body).Decode(&ev); err != nil {
+		return nil, fmt.Errorf("failed to decode next event: %w", err)
+	}
+	return &ev, nil
+}
+
+// reportError reports a failure to the Extensions API during init or
+// before exiting, so Lambda logs it and resets the environment.
+func (e *Extension) reportError(ctx context.Context, id, phase, errorType string, cause error) {
+	body, _ := json.Marshal(map[string]string{"errorMessage": cause.Error(), "errorType": errorType})
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, e.apiURL(phase+"/error"), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set(headerIdentifier, id)
+	req.Header.Set(headerErrorType, errorType)
+	if resp, err := e.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+func (e *Extension) apiURL(path string) string {
+	return "http://" + e.runtimeAPI + "/" + extensionAPIVersion + "/extension/" + path
+}
+
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if len(body) == 0 {
+		return resp.Status
+	}
+	return resp.Status + ": " + string(bytes.TrimSpace(body))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
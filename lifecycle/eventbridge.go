@@ -0,0 +1,120 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// DefaultSource is the EventBridge source of published events unless
+// WithSource is set.
+const DefaultSource = "ghappsetup"
+
+// EventBridgeClient defines the interface for Amazon EventBridge
+// operations.
+type EventBridgeClient interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput,
+		optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// EventBridgePublisher publishes events to an EventBridge bus. Each entry
+// has the publisher's source, the event type as its detail type, and the
+// event's JSON encoding as its detail, so rules can match on them:
+//
+//	{
+//	  "source": ["ghappsetup"],
+//	  "detail-type": ["CredentialsRotated"]
+//	}
+type EventBridgePublisher struct {
+	BusName string
+	source  string
+	client  EventBridgeClient
+	now     func() time.Time
+}
+
+// EventBridgeOption is a functional option for configuring
+// EventBridgePublisher.
+type EventBridgeOption func(*EventBridgePublisher)
+
+// WithEventBridgeClient sets a custom EventBridge client.
+func WithEventBridgeClient(client EventBridgeClient) EventBridgeOption {
+	return func(p *EventBridgePublisher) {
+		p.client = client
+	}
+}
+
+// WithSource sets the source of published events, e.g. to tell apps
+// sharing a bus apart. Defaults to DefaultSource.
+func WithSource(source string) EventBridgeOption {
+	return func(p *EventBridgePublisher) {
+		p.source = source
+	}
+}
+
+// NewEventBridgePublisher creates a publisher for the bus, given by name
+// or ARN. An ARN lets events go to a bus in another account whose policy
+// allows it.
+func NewEventBridgePublisher(busName string, opts ...EventBridgeOption) (*EventBridgePublisher, error) {
+	if busName == "" {
+		return nil, errors.New("lifecycle: event bus name cannot be empty")
+	}
+	p := &EventBridgePublisher{
+		BusName: busName,
+		source:  DefaultSource,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.client == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		p.client = eventbridge.NewFromConfig(cfg)
+	}
+	return p, nil
+}
+
+// Publish sends e to the bus.
+func (p *EventBridgePublisher) Publish(ctx context.Context, e Event) error {
+	if e.Version == "" {
+		e.Version = SchemaVersion
+	}
+	if e.Time.IsZero() {
+		e.Time = p.now()
+	}
+	detail, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("lifecycle: failed to encode %s event: %w", e.Type, err)
+	}
+
+	out, err := p.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{{
+			EventBusName: aws.String(p.BusName),
+			Source:       aws.String(p.source),
+			DetailType:   aws.String(e.Type),
+			Detail:       aws.String(string(detail)),
+			Time:         aws.Time(e.Time),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("lifecycle: failed to publish %s event: %w", e.Type, err)
+	}
+	if out.FailedEntryCount > 0 && len(out.Entries) > 0 {
+		entry := out.Entries[0]
+		return fmt.Errorf("lifecycle: failed to publish %s event: %s: %s",
+			e.Type, aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage))
+	}
+	return nil
+}
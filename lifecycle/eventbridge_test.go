@@ -0,0 +1,107 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// mockEventBridgeClient implements EventBridgeClient for testing
+type mockEventBridgeClient struct {
+	inputs []*eventbridge.PutEventsInput
+	output *eventbridge.PutEventsOutput
+	err    error
+}
+
+func (m *mockEventBridgeClient) PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	m.inputs = append(m.inputs, params)
+	if m.output != nil {
+		return m.output, m.err
+	}
+	return &eventbridge.PutEventsOutput{}, m.err
+}
+
+func TestNewEventBridgePublisher_EmptyBus(t *testing.T) {
+	if _, err := NewEventBridgePublisher("", WithEventBridgeClient(&mockEventBridgeClient{})); err == nil {
+		t.Error("NewEventBridgePublisher() with empty bus should return error")
+	}
+}
+
+func TestEventBridgePublisher_Publish(t *testing.T) {
+	client := &mockEventBridgeClient{}
+	p, err := NewEventBridgePublisher("arn:aws:events:us-east-1:111111111111:event-bus/platform",
+		WithEventBridgeClient(client), WithSource("my-service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	if err := p.Publish(context.Background(), Event{Type: CredentialsRotated, AppID: 42, Generation: 2}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(client.inputs) != 1 || len(client.inputs[0].Entries) != 1 {
+		t.Fatalf("PutEvents inputs = %+v, want one entry", client.inputs)
+	}
+	entry := client.inputs[0].Entries[0]
+	if aws.ToString(entry.EventBusName) != p.BusName {
+		t.Errorf("EventBusName = %q, want %q", aws.ToString(entry.EventBusName), p.BusName)
+	}
+	if aws.ToString(entry.Source) != "my-service" {
+		t.Errorf("Source = %q, want my-service", aws.ToString(entry.Source))
+	}
+	if aws.ToString(entry.DetailType) != CredentialsRotated {
+		t.Errorf("DetailType = %q, want %q", aws.ToString(entry.DetailType), CredentialsRotated)
+	}
+	if want := `{"version":"1","app_id":42,"generation":2}`; aws.ToString(entry.Detail) != want {
+		t.Errorf("Detail = %s, want %s", aws.ToString(entry.Detail), want)
+	}
+	if entry.Time == nil || !entry.Time.Equal(now) {
+		t.Errorf("Time = %v, want %v", entry.Time, now)
+	}
+}
+
+func TestEventBridgePublisher_Publish_DefaultSource(t *testing.T) {
+	client := &mockEventBridgeClient{}
+	p, _ := NewEventBridgePublisher("default", WithEventBridgeClient(client))
+	if err := p.Publish(context.Background(), Event{Type: AppRegistered}); err != nil {
+		t.Fatal(err)
+	}
+	if got := aws.ToString(client.inputs[0].Entries[0].Source); got != DefaultSource {
+		t.Errorf("Source = %q, want %q", got, DefaultSource)
+	}
+}
+
+func TestEventBridgePublisher_Publish_Errors(t *testing.T) {
+	t.Run("request error", func(t *testing.T) {
+		p, _ := NewEventBridgePublisher("default", WithEventBridgeClient(&mockEventBridgeClient{err: errors.New("throttled")}))
+		err := p.Publish(context.Background(), Event{Type: ReloadFailed})
+		if err == nil || !strings.Contains(err.Error(), "throttled") {
+			t.Errorf("Publish() error = %v, want the request error", err)
+		}
+	})
+
+	t.Run("failed entry", func(t *testing.T) {
+		client := &mockEventBridgeClient{output: &eventbridge.PutEventsOutput{
+			FailedEntryCount: 1,
+			Entries: []types.PutEventsResultEntry{{
+				ErrorCode:    aws.String("AccessDeniedException"),
+				ErrorMessage: aws.String("not authorized"),
+			}},
+		}}
+		p, _ := NewEventBridgePublisher("default", WithEventBridgeClient(client))
+		err := p.Publish(context.Background(), Event{Type: ReloadFailed})
+		if err == nil || !strings.Contains(err.Error(), "AccessDeniedException") {
+			t.Errorf("Publish() error = %v, want the entry error", err)
+		}
+	})
+}
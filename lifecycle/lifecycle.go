@@ -0,0 +1,71 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package lifecycle publishes GitHub App lifecycle events, such as an app
+// being registered through the installer or its credentials being
+// rotated, so platform automation can react to them. EventBridgePublisher
+// sends them to an Amazon EventBridge bus; set it as
+// ghappsetup.Config.Events, or name the bus in GHAPPSETUP_EVENT_BUS.
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Event types, used as the EventBridge detail type.
+const (
+	// AppRegistered is published by the installer after it saves the
+	// credentials of a newly created app.
+	AppRegistered = "AppRegistered"
+
+	// CredentialsRotated is published by the Runtime when a reload loads
+	// credentials (app ID, private key, webhook secret, or client secret)
+	// that differ from the previous load's.
+	CredentialsRotated = "CredentialsRotated"
+
+	// ReloadFailed is published by the Runtime when a reload fails after
+	// a successful load. The previous configuration stays in use.
+	ReloadFailed = "ReloadFailed"
+)
+
+// SchemaVersion is the version of the event detail schema. It changes only
+// when fields are removed or change meaning; fields may be added.
+const SchemaVersion = "1"
+
+// Event is a lifecycle event. Its JSON encoding is the event detail:
+//
+//	{
+//	  "version": "1",
+//	  "app_id": 123456,
+//	  "app_slug": "my-app",
+//	  "html_url": "https://github.com/apps/my-app",
+//	  "error": "failed to load credentials: ...",
+//	  "generation": 3
+//	}
+//
+// Fields without a value are omitted; error is set on ReloadFailed only.
+type Event struct {
+	// Type is the event type, e.g. AppRegistered.
+	Type string `json:"-"`
+
+	// Time is when the event happened. Publishers use the current time if
+	// it is zero.
+	Time time.Time `json:"-"`
+
+	Version string `json:"version"`
+	AppID   int64  `json:"app_id,omitempty"`
+	AppSlug string `json:"app_slug,omitempty"`
+	HTMLURL string `json:"html_url,omitempty"`
+	Error   string `json:"error,omitempty"`
+
+	// Generation is the Runtime's count of successful loads (see
+	// ghappsetup.Runtime.Generation), set on Runtime events.
+	Generation uint64 `json:"generation,omitempty"`
+}
+
+// Publisher publishes lifecycle events. Implementations must be safe for
+// concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
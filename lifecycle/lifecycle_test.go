@@ -0,0 +1,47 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEvent_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{
+			name: "app registered",
+			event: Event{
+				Type:    AppRegistered,
+				Time:    time.Now(),
+				Version: SchemaVersion,
+				AppID:   42,
+				AppSlug: "my-app",
+				HTMLURL: "https://github.com/apps/my-app",
+			},
+			want: `{"version":"1","app_id":42,"app_slug":"my-app","html_url":"https://github.com/apps/my-app"}`,
+		},
+		{
+			name:  "reload failed",
+			event: Event{Type: ReloadFailed, Version: SchemaVersion, AppID: 42, Error: "boom", Generation: 3},
+			want:  `{"version":"1","app_id":42,"error":"boom","generation":3}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EMF is a Sink writing each value as one CloudWatch Embedded Metric
+// Format line. On Lambda, and on ECS with the awslogs driver, lines written
+// to stdout become CloudWatch metrics without an agent or API calls.
+type EMF struct {
+	namespace  string
+	dimensions map[string]string
+	keys       []string // dimension names, sorted
+	w          io.Writer
+	now        func() time.Time
+
+	mu sync.Mutex // serializes writes so lines do not interleave
+}
+
+// EMFOption configures an EMF sink.
+type EMFOption func(*EMF)
+
+// WithWriter sets where the lines are written. Defaults to os.Stdout.
+func WithWriter(w io.Writer) EMFOption {
+	return func(e *EMF) {
+		e.w = w
+	}
+}
+
+// WithDimensions sets dimensions added to every metric, e.g. the service
+// name or stage. Each distinct set of values is a separate CloudWatch
+// metric, so keep their cardinality low.
+func WithDimensions(dimensions map[string]string) EMFOption {
+	return func(e *EMF) {
+		e.dimensions = maps.Clone(dimensions)
+	}
+}
+
+// NewEMF creates an EMF sink publishing metrics in the CloudWatch
+// namespace.
+func NewEMF(namespace string, opts ...EMFOption) *EMF {
+	e := &EMF{
+		namespace: namespace,
+		w:         os.Stdout,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.keys = append([]string{}, slices.Sorted(maps.Keys(e.dimensions))...)
+	return e
+}
+
+// emfMetadata is the "_aws" member of an EMF line.
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+type emfDirective struct {
+	Namespace  string         `json:"Namespace"`
+	Dimensions [][]string     `json:"Dimensions"`
+	Metrics    []emfMetricDef `json:"Metrics"`
+}
+
+type emfMetricDef struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit"`
+}
+
+// Record writes the value. Write errors are ignored, as a failed metric
+// must not fail the operation it measures.
+func (e *EMF) Record(name string, value float64, unit Unit) {
+	line := make(map[string]any, len(e.dimensions)+2)
+	for k, v := range e.dimensions {
+		line[k] = v
+	}
+	line[name] = value
+	line["_aws"] = emfMetadata{
+		Timestamp: e.now().UnixMilli(),
+		CloudWatchMetrics: []emfDirective{{
+			Namespace:  e.namespace,
+			Dimensions: [][]string{e.keys},
+			Metrics:    []emfMetricDef{{Name: name, Unit: unit}},
+		}},
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEMF_Record(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewEMF("GitHubApp", WithWriter(&buf), WithDimensions(map[string]string{"Stage": "prod", "Service": "bot"}))
+	sink.now = func() time.Time { return time.UnixMilli(1700000000123) }
+
+	sink.Record(LoadDuration, 12.5, UnitMilliseconds)
+	sink.Record(GatedRequests, 1, UnitCount)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2:\n%s", len(lines), buf.String())
+	}
+	var line struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		Service      string
+		Stage        string
+		LoadDuration float64
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line.AWS.Timestamp != 1700000000123 || line.LoadDuration != 12.5 || line.Service != "bot" || line.Stage != "prod" {
+		t.Errorf("line = %s", lines[0])
+	}
+	if len(line.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("directives = %s", lines[0])
+	}
+	d := line.AWS.CloudWatchMetrics[0]
+	if d.Namespace != "GitHubApp" || len(d.Dimensions) != 1 || strings.Join(d.Dimensions[0], ",") != "Service,Stage" {
+		t.Errorf("directive = %+v", d)
+	}
+	if len(d.Metrics) != 1 || d.Metrics[0].Name != "LoadDuration" || d.Metrics[0].Unit != "Milliseconds" {
+		t.Errorf("metrics = %+v", d.Metrics)
+	}
+}
+
+func TestEMF_NoDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	NewEMF("GitHubApp", WithWriter(&buf)).Record(Reloads, 1, UnitCount)
+	// CloudWatch requires a dimension set, which may be empty
+	if !strings.Contains(buf.String(), `"Dimensions":[[]]`) {
+		t.Errorf("line = %s", buf.String())
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package metrics defines the sink the other packages report operational
+// metrics to, and an EMF sink that writes them as CloudWatch Embedded
+// Metric Format log lines. Set it as ghappsetup.Config.Metrics (or with
+// GHAPPSETUP_EMF_NAMESPACE) where running a metrics scraper is
+// impractical, e.g. on Lambda or Fargate.
+package metrics
+
+// Unit is the unit of a metric value, named as CloudWatch names it.
+type Unit string
+
+const (
+	UnitCount        Unit = "Count"
+	UnitMilliseconds Unit = "Milliseconds"
+)
+
+// Metric names reported by this module.
+const (
+	// LoadDuration is the duration of each load or reload, in milliseconds.
+	LoadDuration = "LoadDuration"
+
+	// LoadFailures counts failed loads and reloads.
+	LoadFailures = "LoadFailures"
+
+	// Reloads counts reloads, successful or not, after the first load.
+	Reloads = "Reloads"
+
+	// GatedRequests counts requests answered with the not-ready response
+	// (or a not-ready fallback handler) instead of reaching the handler.
+	GatedRequests = "GatedRequests"
+
+	// TokenCacheHits counts installation tokens served from the cache.
+	TokenCacheHits = "TokenCacheHits"
+
+	// TokenCacheMisses counts installation token requests that needed an
+	// exchange with GitHub, including ones sharing another caller's.
+	TokenCacheMisses = "TokenCacheMisses"
+)
+
+// Sink receives metric values. Implementations must be safe for
+// concurrent use and should not block.
+type Sink interface {
+	Record(name string, value float64, unit Unit)
+}
@@ -0,0 +1,371 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package rotation implements an AWS Secrets Manager rotation function for
+// GitHub App credentials. Scheduled rotation of the secret generates a new
+// webhook secret and, when a new key has been staged, switches to that
+// private key. Both are written through the configured configstore.Store
+// so reloading apps pick them up, and the webhook secret is then set on
+// the app through the GitHub API.
+//
+// GitHub has no API to create or delete app private keys, so a new key must
+// be generated in the app's settings and provided with WithNextPrivateKey;
+// without one, rotation keeps the current key.
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v82/github"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/installer"
+)
+
+// Rotation steps, as sent in the Step field of the rotation event.
+const (
+	StepCreateSecret = "createSecret"
+	StepSetSecret    = "setSecret"
+	StepTestSecret   = "testSecret"
+	StepFinishSecret = "finishSecret"
+)
+
+// SecretsManagerClient defines the interface for AWS Secrets Manager
+// operations used by rotation.
+type SecretsManagerClient interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+}
+
+// NextKeyFunc returns the PEM-encoded private key to rotate to, or nil to
+// keep the current key.
+type NextKeyFunc func(ctx context.Context) ([]byte, error)
+
+// Rotator handles Secrets Manager rotation events for a GitHub App.
+type Rotator struct {
+	store      configstore.Store
+	loader     configstore.CredentialLoader
+	client     SecretsManagerClient
+	nextKey    NextKeyFunc
+	githubURL  string
+	githubOpts []ghauth.TokenSourceOption
+}
+
+// Option is a functional option for configuring Rotator.
+type Option func(*Rotator)
+
+// WithSecretsManagerClient sets a custom Secrets Manager client.
+func WithSecretsManagerClient(client SecretsManagerClient) Option {
+	return func(r *Rotator) {
+		r.client = client
+	}
+}
+
+// WithNextPrivateKey sets the source of the private key to rotate to. It is
+// called once per rotation, in the createSecret step. The key is checked
+// against GitHub in the testSecret step, before it is saved.
+func WithNextPrivateKey(fn NextKeyFunc) Option {
+	return func(r *Rotator) {
+		r.nextKey = fn
+	}
+}
+
+// WithGitHubURL sets the GitHub web URL for API calls. Defaults to
+// GITHUB_URL, then the stored app HTML URL.
+func WithGitHubURL(webURL string) Option {
+	return func(r *Rotator) {
+		r.githubURL = webURL
+	}
+}
+
+// WithGitHubOptions sets options for the token sources signing GitHub API
+// calls, such as ghauth.WithBaseURL or ghauth.WithHTTPClient. They are
+// applied after WithGitHubURL.
+func WithGitHubOptions(opts ...ghauth.TokenSourceOption) Option {
+	return func(r *Rotator) {
+		r.githubOpts = append(r.githubOpts, opts...)
+	}
+}
+
+// New creates a Rotator saving rotated credentials to store, which must
+// also be able to load them (see configstore.CredentialLoader).
+func New(store configstore.Store, opts ...Option) (*Rotator, error) {
+	loader, ok := store.(configstore.CredentialLoader)
+	if !ok {
+		return nil, errors.New("rotation: store cannot load credentials")
+	}
+	r := &Rotator{
+		store:     store,
+		loader:    loader,
+		githubURL: os.Getenv(installer.EnvGitHubURL),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.client == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		r.client = secretsmanager.NewFromConfig(cfg)
+	}
+	return r, nil
+}
+
+// Handle runs one rotation step. Pass it to lambda.Start:
+//
+//	rotator, err := rotation.New(store)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	lambda.Start(rotator.Handle)
+//
+// Every step is safe to retry, as Secrets Manager does when a step fails.
+func (r *Rotator) Handle(ctx context.Context, event events.SecretsManagerSecretRotationEvent) error {
+	log := clog.FromContext(ctx)
+
+	secret, err := r.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(event.SecretID),
+	})
+	if err != nil {
+		return fmt.Errorf("rotation: failed to describe secret: %w", err)
+	}
+	if !aws.ToBool(secret.RotationEnabled) {
+		return fmt.Errorf("rotation: rotation is not enabled for secret %s", event.SecretID)
+	}
+	stages, ok := secret.VersionIdsToStages[event.ClientRequestToken]
+	if !ok {
+		return fmt.Errorf("rotation: secret %s has no version %s", event.SecretID, event.ClientRequestToken)
+	}
+	if slices.Contains(stages, stageCurrent) {
+		log.Infof("[rotation] version %s of secret %s is already current", event.ClientRequestToken, event.SecretID)
+		return nil
+	}
+	if !slices.Contains(stages, stagePending) {
+		return fmt.Errorf("rotation: version %s of secret %s is not pending rotation", event.ClientRequestToken, event.SecretID)
+	}
+
+	switch event.Step {
+	case StepCreateSecret:
+		return r.createSecret(ctx, event.SecretID, event.ClientRequestToken)
+	case StepSetSecret:
+		return r.setSecret(ctx, event.SecretID, event.ClientRequestToken)
+	case StepTestSecret:
+		return r.testSecret(ctx, event.SecretID, event.ClientRequestToken)
+	case StepFinishSecret:
+		return r.finishSecret(ctx, event.SecretID, event.ClientRequestToken, secret.VersionIdsToStages)
+	default:
+		return fmt.Errorf("rotation: unknown step %q", event.Step)
+	}
+}
+
+// createSecret stores the new credentials as the pending version: the
+// stored private key, or the next key if one is staged, and a new webhook
+// secret.
+func (r *Rotator) createSecret(ctx context.Context, secretID, token string) error {
+	log := clog.FromContext(ctx)
+
+	_, err := r.getSecretValue(ctx, secretID, token, stagePending)
+	if err == nil {
+		log.Infof("[rotation] pending version %s of secret %s already exists", token, secretID)
+		return nil
+	}
+	if !isNotFound(err) {
+		return fmt.Errorf("rotation: failed to get pending secret: %w", err)
+	}
+
+	creds, err := r.loader.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("rotation: failed to load credentials: %w", err)
+	}
+	value := newSecretValue(creds)
+	if value.WebhookSecret, err = newWebhookSecret(); err != nil {
+		return err
+	}
+	if r.nextKey != nil {
+		pemData, err := r.nextKey(ctx)
+		if err != nil {
+			return fmt.Errorf("rotation: failed to get next private key: %w", err)
+		}
+		if pemData != nil {
+			if _, err := ghauth.ParsePrivateKey(pemData); err != nil {
+				return fmt.Errorf("rotation: invalid next private key: %w", err)
+			}
+			value.PrivateKey = string(pemData)
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("rotation: failed to encode secret value: %w", err)
+	}
+	_, err = r.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:           aws.String(secretID),
+		ClientRequestToken: aws.String(token),
+		SecretString:       aws.String(string(data)),
+		VersionStages:      []string{stagePending},
+	})
+	if err != nil {
+		return fmt.Errorf("rotation: failed to put pending secret: %w", err)
+	}
+	log.Infof("[rotation] created pending version %s of secret %s", token, secretID)
+	return nil
+}
+
+// setSecret only checks that the pending version exists. The webhook
+// secret is set on the app by finishSecret once the store holds it, so
+// GitHub and the apps disagree on the secret only while the apps reload.
+func (r *Rotator) setSecret(ctx context.Context, secretID, token string) error {
+	if _, err := r.getSecretValue(ctx, secretID, token, stagePending); err != nil {
+		return fmt.Errorf("rotation: failed to get pending secret: %w", err)
+	}
+	clog.FromContext(ctx).Infof("[rotation] webhook secret of version %s will be set on GitHub after it is saved", token)
+	return nil
+}
+
+// testSecret checks that GitHub accepts the pending private key. GitHub
+// does not return webhook secrets, so the secret set by finishSecret
+// cannot be checked.
+func (r *Rotator) testSecret(ctx context.Context, secretID, token string) error {
+	value, err := r.getSecretValue(ctx, secretID, token, stagePending)
+	if err != nil {
+		return fmt.Errorf("rotation: failed to get pending secret: %w", err)
+	}
+	appID, err := value.appID()
+	if err != nil {
+		return err
+	}
+	key, err := ghauth.ParsePrivateKey([]byte(value.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("rotation: invalid pending private key: %w", err)
+	}
+	opts, err := r.githubOptions(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ghauth.VerifyAppKey(ctx, appID, key, opts...); err != nil {
+		return fmt.Errorf("rotation: pending private key failed verification: %w", err)
+	}
+	return nil
+}
+
+// finishSecret saves the pending credentials to the store, sets the
+// pending webhook secret on the app, then marks the pending version as
+// current. Each step is repeated by a retry if a later one fails. Apps
+// that reload before GitHub switches secrets reject deliveries until it
+// does, and apps that reload after reject them until they reload.
+func (r *Rotator) finishSecret(ctx context.Context, secretID, token string, versions map[string][]string) error {
+	log := clog.FromContext(ctx)
+
+	value, err := r.getSecretValue(ctx, secretID, token, stagePending)
+	if err != nil {
+		return fmt.Errorf("rotation: failed to get pending secret: %w", err)
+	}
+	creds, err := r.loader.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("rotation: failed to load credentials: %w", err)
+	}
+	keyChanged := creds.PrivateKey != value.PrivateKey
+	creds.PrivateKey = value.PrivateKey
+	creds.WebhookSecret = value.WebhookSecret
+	if err := r.store.Save(ctx, creds); err != nil {
+		return fmt.Errorf("rotation: failed to save credentials: %w", err)
+	}
+	if err := r.setWebhookSecret(ctx, value); err != nil {
+		return err
+	}
+
+	var current string
+	for id, stages := range versions {
+		if slices.Contains(stages, stageCurrent) {
+			current = id
+			break
+		}
+	}
+	input := &secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:        aws.String(secretID),
+		VersionStage:    aws.String(stageCurrent),
+		MoveToVersionId: aws.String(token),
+	}
+	if current != "" {
+		input.RemoveFromVersionId = aws.String(current)
+	}
+	if _, err := r.client.UpdateSecretVersionStage(ctx, input); err != nil {
+		return fmt.Errorf("rotation: failed to promote pending secret: %w", err)
+	}
+
+	log.Infof("[rotation] rotated credentials of app %s to version %s", value.AppID, token)
+	if keyChanged {
+		log.Infof("[rotation] once every instance has reloaded, delete the old private key in the app's settings")
+	}
+	return nil
+}
+
+// setWebhookSecret sets the webhook secret in value on the app,
+// authenticating with the private key in value.
+func (r *Rotator) setWebhookSecret(ctx context.Context, value *SecretValue) error {
+	client, err := r.appClient(ctx, value)
+	if err != nil {
+		return err
+	}
+	_, _, err = client.Apps.UpdateHookConfig(ctx, &github.HookConfig{
+		Secret: github.Ptr(value.WebhookSecret),
+	})
+	if err != nil {
+		return fmt.Errorf("rotation: failed to update webhook secret: %w", err)
+	}
+	clog.FromContext(ctx).Infof("[rotation] set webhook secret of app %s", value.AppID)
+	return nil
+}
+
+// appClient returns a go-github client authenticating as the app with the
+// credentials in value.
+func (r *Rotator) appClient(ctx context.Context, value *SecretValue) (*github.Client, error) {
+	appID, err := value.appID()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ghauth.ParsePrivateKey([]byte(value.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("rotation: invalid pending private key: %w", err)
+	}
+	opts, err := r.githubOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, ghauth.WithCredentials(ghauth.StaticCredentials(appID, key)))
+	return ghauth.NewClientFromSource(ctx, ghauth.NewTokenSource(opts...), 0)
+}
+
+// githubOptions selects the GitHub instance for API calls: the configured
+// GitHub URL if set, otherwise the origin of the stored app HTML URL,
+// followed by the options set with WithGitHubOptions.
+func (r *Rotator) githubOptions(ctx context.Context) ([]ghauth.TokenSourceOption, error) {
+	webURL := r.githubURL
+	if webURL == "" {
+		creds, err := r.loader.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("rotation: failed to load credentials: %w", err)
+		}
+		webURL = creds.HTMLURL
+	}
+	opts := []ghauth.TokenSourceOption{ghauth.WithGitHubURL(webURL)}
+	return append(opts, r.githubOpts...), nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package rotation
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+	"github.com/cruxstack/github-app-setup-go/ghauth"
+	"github.com/cruxstack/github-app-setup-go/githubtest"
+)
+
+const testSecretID = "github-app"
+
+// fakeSecretsManager keeps secret versions and their stages in memory.
+type fakeSecretsManager struct {
+	mu       sync.Mutex
+	disabled bool
+	values   map[string]string   // version ID -> secret string
+	stages   map[string][]string // version ID -> stages
+}
+
+func newFakeSecretsManager(currentValue string) *fakeSecretsManager {
+	return &fakeSecretsManager{
+		values: map[string]string{"v1": currentValue},
+		stages: map[string][]string{"v1": {stageCurrent}},
+	}
+}
+
+// startRotation adds a version labeled AWSPENDING without a value, as
+// Secrets Manager does before calling createSecret.
+func (f *fakeSecretsManager) startRotation(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stages[token] = []string{stagePending}
+}
+
+func (f *fakeSecretsManager) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions := make(map[string][]string, len(f.stages))
+	for id, stages := range f.stages {
+		versions[id] = slices.Clone(stages)
+	}
+	return &secretsmanager.DescribeSecretOutput{
+		RotationEnabled:    aws.Bool(!f.disabled),
+		VersionIdsToStages: versions,
+	}, nil
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(params.VersionId)
+	value, ok := f.values[id]
+	if !ok || !slices.Contains(f.stages[id], aws.ToString(params.VersionStage)) {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("version not found")}
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func (f *fakeSecretsManager) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(params.ClientRequestToken)
+	f.values[id] = aws.ToString(params.SecretString)
+	f.stages[id] = params.VersionStages
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (f *fakeSecretsManager) UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stage := aws.ToString(params.VersionStage)
+	if from := aws.ToString(params.RemoveFromVersionId); from != "" {
+		f.stages[from] = slices.DeleteFunc(f.stages[from], func(s string) bool { return s == stage })
+	}
+	to := aws.ToString(params.MoveToVersionId)
+	f.stages[to] = append(f.stages[to], stage)
+	return &secretsmanager.UpdateSecretVersionStageOutput{}, nil
+}
+
+func (f *fakeSecretsManager) current() SecretValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var v SecretValue
+	for id, stages := range f.stages {
+		if slices.Contains(stages, stageCurrent) {
+			json.Unmarshal([]byte(f.values[id]), &v)
+		}
+	}
+	return v
+}
+
+// appServer serves the app and hook config endpoints for app 12345,
+// accepting JWTs signed by any of keys, and records the webhook secrets
+// set.
+func appServer(t *testing.T, secrets *[]string, keys ...*rsa.PrivateKey) *githubtest.Server {
+	t.Helper()
+	srv := githubtest.NewServer(t)
+	authorized := func(r *http.Request) bool {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		for _, key := range keys {
+			if signedBy(&key.PublicKey, parts) {
+				return true
+			}
+		}
+		return false
+	}
+	srv.HandleFunc(http.MethodGet, "/api/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			http.Error(w, `{"message":"A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":12345}`))
+	})
+	srv.HandleFunc(http.MethodPatch, "/api/v3/app/hook/config", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			http.Error(w, `{"message":"A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var cfg struct {
+			Secret string `json:"secret"`
+		}
+		json.Unmarshal(body, &cfg)
+		*secrets = append(*secrets, cfg.Secret)
+		w.Write([]byte(`{"content_type":"json","secret":"********"}`))
+	})
+	return srv
+}
+
+// signedBy reports whether the JWT segments carry an RS256 signature by pub.
+func signedBy(pub *rsa.PublicKey, parts []string) bool {
+	if len(parts) != 3 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+}
+
+// newTestStore returns an env file store holding the test app's
+// credentials.
+func newTestStore(t *testing.T) (*configstore.LocalEnvFileStore, *configstore.AppCredentials) {
+	t.Helper()
+	store := configstore.NewLocalEnvFileStore(filepath.Join(t.TempDir(), ".env"))
+	creds := githubtest.AppCredentials(t)
+	if err := store.Save(context.Background(), creds); err != nil {
+		t.Fatal(err)
+	}
+	return store, creds
+}
+
+// rotate runs every rotation step for token, stopping at the first error.
+func rotate(ctx context.Context, r *Rotator, token string) error {
+	for _, step := range []string{StepCreateSecret, StepSetSecret, StepTestSecret, StepFinishSecret} {
+		err := r.Handle(ctx, events.SecretsManagerSecretRotationEvent{
+			Step:               step,
+			SecretID:           testSecretID,
+			ClientRequestToken: token,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRotator_RotatesWebhookSecret(t *testing.T) {
+	ctx := context.Background()
+	store, creds := newTestStore(t)
+	var secrets []string
+	srv := appServer(t, &secrets, githubtest.PrivateKey(t))
+	sm := newFakeSecretsManager(`{}`)
+	r, err := New(store, WithSecretsManagerClient(sm), WithGitHubURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm.startRotation("v2")
+	if err := rotate(ctx, r, "v2"); err != nil {
+		t.Fatalf("rotation error = %v", err)
+	}
+
+	saved, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.WebhookSecret == creds.WebhookSecret || len(secrets) != 1 || secrets[0] != saved.WebhookSecret {
+		t.Errorf("stored webhook secret = %q, set on GitHub = %v; want the same new secret", saved.WebhookSecret, secrets)
+	}
+	if saved.PrivateKey != creds.PrivateKey || saved.AppID != creds.AppID || saved.ClientSecret != creds.ClientSecret {
+		t.Errorf("credentials other than the webhook secret changed: %+v", saved)
+	}
+	if current := sm.current(); current.WebhookSecret != saved.WebhookSecret || current.AppID != "12345" {
+		t.Errorf("current secret = %+v, want the rotated credentials", current)
+	}
+
+	// Secrets Manager retries steps; a finished version is left alone
+	if err := rotate(ctx, r, "v2"); err != nil || len(secrets) != 1 {
+		t.Errorf("retrying a finished rotation: error = %v, secrets set = %d", err, len(secrets))
+	}
+}
+
+func TestRotator_SetsWebhookSecretAfterSave(t *testing.T) {
+	ctx := context.Background()
+	store, creds := newTestStore(t)
+	var secrets []string
+	srv := appServer(t, &secrets, githubtest.PrivateKey(t))
+	sm := newFakeSecretsManager(`{}`)
+	r, err := New(store, WithSecretsManagerClient(sm), WithGitHubURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm.startRotation("v2")
+	for _, step := range []string{StepCreateSecret, StepSetSecret, StepTestSecret} {
+		err := r.Handle(ctx, events.SecretsManagerSecretRotationEvent{
+			Step: step, SecretID: testSecretID, ClientRequestToken: "v2",
+		})
+		if err != nil {
+			t.Fatalf("%s error = %v", step, err)
+		}
+	}
+	if len(secrets) != 0 {
+		t.Fatalf("webhook secret set on GitHub before finishSecret: %v", secrets)
+	}
+	if saved, _ := store.Load(ctx); saved.WebhookSecret != creds.WebhookSecret {
+		t.Fatal("webhook secret saved before finishSecret")
+	}
+
+	err = r.Handle(ctx, events.SecretsManagerSecretRotationEvent{
+		Step: StepFinishSecret, SecretID: testSecretID, ClientRequestToken: "v2",
+	})
+	if err != nil {
+		t.Fatalf("finishSecret error = %v", err)
+	}
+	saved, _ := store.Load(ctx)
+	if len(secrets) != 1 || secrets[0] != saved.WebhookSecret {
+		t.Errorf("set on GitHub = %v, want the saved secret %q", secrets, saved.WebhookSecret)
+	}
+}
+
+func TestRotator_RotatesStagedPrivateKey(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPEM := ghauth.EncodePKCS1(newKey)
+	var secrets []string
+	srv := appServer(t, &secrets, githubtest.PrivateKey(t), newKey)
+	sm := newFakeSecretsManager(`{}`)
+	r, err := New(store,
+		WithSecretsManagerClient(sm),
+		WithGitHubURL(srv.URL),
+		WithNextPrivateKey(func(ctx context.Context) ([]byte, error) { return newPEM, nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm.startRotation("v2")
+	if err := rotate(ctx, r, "v2"); err != nil {
+		t.Fatalf("rotation error = %v", err)
+	}
+	saved, _ := store.Load(ctx)
+	key, err := ghauth.ParsePrivateKey([]byte(saved.PrivateKey))
+	if err != nil || !key.Equal(newKey) {
+		t.Errorf("stored private key is not the staged key (error %v)", err)
+	}
+}
+
+func TestRotator_RejectedKeyIsNotSaved(t *testing.T) {
+	ctx := context.Background()
+	store, creds := newTestStore(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var secrets []string
+	// GitHub only accepts the current key
+	srv := appServer(t, &secrets, githubtest.PrivateKey(t))
+	sm := newFakeSecretsManager(`{}`)
+	r, err := New(store,
+		WithSecretsManagerClient(sm),
+		WithGitHubURL(srv.URL),
+		WithNextPrivateKey(func(ctx context.Context) ([]byte, error) { return ghauth.EncodePKCS1(otherKey), nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm.startRotation("v2")
+	err = rotate(ctx, r, "v2")
+	if err == nil {
+		t.Fatal("rotation with a key GitHub rejects succeeded")
+	}
+	saved, _ := store.Load(ctx)
+	if saved.PrivateKey != creds.PrivateKey || saved.WebhookSecret != creds.WebhookSecret {
+		t.Error("credentials were saved despite the failed rotation")
+	}
+	if sm.current().AppID != "" {
+		t.Error("the pending version was promoted despite the failed rotation")
+	}
+}
+
+func TestRotator_Handle_Errors(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	tests := []struct {
+		name  string
+		setup func(sm *fakeSecretsManager)
+		event events.SecretsManagerSecretRotationEvent
+		want  string
+	}{
+		{
+			name:  "rotation disabled",
+			setup: func(sm *fakeSecretsManager) { sm.disabled = true; sm.startRotation("v2") },
+			event: events.SecretsManagerSecretRotationEvent{Step: StepCreateSecret, SecretID: testSecretID, ClientRequestToken: "v2"},
+			want:  "not enabled",
+		},
+		{
+			name:  "unknown version",
+			setup: func(sm *fakeSecretsManager) {},
+			event: events.SecretsManagerSecretRotationEvent{Step: StepCreateSecret, SecretID: testSecretID, ClientRequestToken: "v9"},
+			want:  "has no version",
+		},
+		{
+			name:  "version not pending",
+			setup: func(sm *fakeSecretsManager) { sm.stages["v2"] = []string{"AWSPREVIOUS"} },
+			event: events.SecretsManagerSecretRotationEvent{Step: StepCreateSecret, SecretID: testSecretID, ClientRequestToken: "v2"},
+			want:  "not pending",
+		},
+		{
+			name:  "unknown step",
+			setup: func(sm *fakeSecretsManager) { sm.startRotation("v2") },
+			event: events.SecretsManagerSecretRotationEvent{Step: "rollbackSecret", SecretID: testSecretID, ClientRequestToken: "v2"},
+			want:  "unknown step",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := newFakeSecretsManager(`{}`)
+			tt.setup(sm)
+			r, err := New(store, WithSecretsManagerClient(sm))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = r.Handle(ctx, tt.event)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Handle() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_StoreWithoutLoader(t *testing.T) {
+	if _, err := New(saveOnlyStore{}, WithSecretsManagerClient(newFakeSecretsManager(`{}`))); err == nil {
+		t.Error("New() with a store that cannot load credentials should return error")
+	}
+}
+
+// saveOnlyStore is a configstore.Store without Load.
+type saveOnlyStore struct{}
+
+func (saveOnlyStore) Save(ctx context.Context, creds *configstore.AppCredentials) error { return nil }
+
+func (saveOnlyStore) Status(ctx context.Context) (*configstore.InstallerStatus, error) {
+	return &configstore.InstallerStatus{}, nil
+}
+
+func (saveOnlyStore) DisableInstaller(ctx context.Context) error { return nil }
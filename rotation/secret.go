@@ -0,0 +1,87 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package rotation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// Secrets Manager version stages used by the rotation steps.
+const (
+	stageCurrent = "AWSCURRENT"
+	stagePending = "AWSPENDING"
+)
+
+// SecretValue is the JSON value of each secret version. The keys are the
+// environment variable names the credentials are loaded into, so ECS task
+// definitions can reference single keys of the secret.
+type SecretValue struct {
+	AppID         string `json:"GITHUB_APP_ID"`
+	PrivateKey    string `json:"GITHUB_APP_PRIVATE_KEY"`
+	WebhookSecret string `json:"GITHUB_WEBHOOK_SECRET"`
+}
+
+// appID returns the app ID as a number.
+func (v *SecretValue) appID() (int64, error) {
+	id, err := strconv.ParseInt(v.AppID, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("rotation: invalid app ID %q in secret", v.AppID)
+	}
+	return id, nil
+}
+
+// newSecretValue returns the value of the stored credentials.
+func newSecretValue(creds *configstore.AppCredentials) SecretValue {
+	return SecretValue{
+		AppID:         strconv.FormatInt(creds.AppID, 10),
+		PrivateKey:    creds.PrivateKey,
+		WebhookSecret: creds.WebhookSecret,
+	}
+}
+
+// newWebhookSecret returns a random webhook secret.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rotation: failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// getSecretValue reads and decodes the version of the secret with the
+// given ID and stage.
+func (r *Rotator) getSecretValue(ctx context.Context, secretID, versionID, stage string) (*SecretValue, error) {
+	out, err := r.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(secretID),
+		VersionId:    aws.String(versionID),
+		VersionStage: aws.String(stage),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var v SecretValue
+	if err := json.Unmarshal([]byte(aws.ToString(out.SecretString)), &v); err != nil {
+		return nil, fmt.Errorf("rotation: failed to parse %s secret value: %w", stage, err)
+	}
+	return &v, nil
+}
+
+// isNotFound reports whether err is Secrets Manager's
+// ResourceNotFoundException, returned for a version that does not exist.
+func isNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
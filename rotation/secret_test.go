@@ -0,0 +1,54 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package rotation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func TestSecretValue_JSON(t *testing.T) {
+	v := newSecretValue(&configstore.AppCredentials{AppID: 123, PrivateKey: "pem", WebhookSecret: "secret"})
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"GITHUB_APP_ID":"123","GITHUB_APP_PRIVATE_KEY":"pem","GITHUB_WEBHOOK_SECRET":"secret"}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
+
+func TestSecretValue_AppID(t *testing.T) {
+	tests := []struct {
+		appID   string
+		want    int64
+		wantErr bool
+	}{
+		{"123", 123, false},
+		{"", 0, true},
+		{"0", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		v := &SecretValue{AppID: tt.appID}
+		got, err := v.appID()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("appID(%q) = %d, %v; want %d, error %v", tt.appID, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewWebhookSecret(t *testing.T) {
+	a, err := newWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := newWebhookSecret()
+	if len(a) != 64 || a == b {
+		t.Errorf("newWebhookSecret() = %q, %q; want distinct 64-character secrets", a, b)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chainguard-dev/clog"
+)
+
+// HandleEventBridge is a Lambda handler for Parameter Store change events
+// delivered by an EventBridge rule such as:
+//
+//	{
+//	  "source": ["aws.ssm"],
+//	  "detail-type": ["Parameter Store Change"],
+//	  "detail": {"name": [{"prefix": "/my-app/"}]}
+//	}
+//
+// Changes to parameters outside the Notifier's prefix, and other events,
+// are ignored. A failed reload is returned so that Lambda retries the
+// event.
+//
+//	lambda.Start(notifier.HandleEventBridge)
+func (n *Notifier) HandleEventBridge(ctx context.Context, e events.CloudWatchEvent) error {
+	log := clog.FromContext(ctx)
+
+	change, err := ParseEvent(e)
+	if err != nil {
+		log.Warnf("[ssmreload] ignoring event %s: %v", e.ID, err)
+		return nil
+	}
+	if !n.Matches(change) {
+		log.Debugf("[ssmreload] ignoring change to %s outside prefix %s", change.Name, n.Prefix)
+		return nil
+	}
+
+	log.Infof("[ssmreload] parameter %s changed (%s), reloading", change.Name, change.Operation)
+	return n.Reload(ctx)
+}
+
+// HandleSQS is a Lambda handler for Parameter Store change events
+// delivered through an SQS queue targeted by an EventBridge rule, which
+// lets a burst of edits share one reload. The batch triggers at most one
+// reload; if it fails, the matching messages are reported as batch item
+// failures so that they are retried, which requires ReportBatchItemFailures
+// on the event source mapping. Messages that are not parameter changes are
+// dropped.
+//
+//	lambda.Start(notifier.HandleSQS)
+func (n *Notifier) HandleSQS(ctx context.Context, e events.SQSEvent) (events.SQSEventResponse, error) {
+	log := clog.FromContext(ctx)
+
+	var matched []string
+	for _, msg := range e.Records {
+		change, err := ParseMessage(msg.Body)
+		if err != nil {
+			log.Warnf("[ssmreload] dropping message %s: %v", msg.MessageId, err)
+			continue
+		}
+		if !n.Matches(change) {
+			continue
+		}
+		log.Infof("[ssmreload] parameter %s changed (%s)", change.Name, change.Operation)
+		matched = append(matched, msg.MessageId)
+	}
+
+	var resp events.SQSEventResponse
+	if len(matched) == 0 {
+		return resp, nil
+	}
+	if err := n.Reload(ctx); err != nil {
+		log.Errorf("[ssmreload] reload failed, retrying %d messages: %v", len(matched), err)
+		for _, id := range matched {
+			resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: id})
+		}
+	}
+	return resp, nil
+}
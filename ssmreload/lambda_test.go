@@ -0,0 +1,105 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func newTestNotifier(t *testing.T, target Target) *Notifier {
+	t.Helper()
+	n, err := New(WithParameterPrefix("/my-app/"), WithTarget(target))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return n
+}
+
+func TestNotifier_HandleEventBridge(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reloads on matching change", func(t *testing.T) {
+		target := &countingTarget{}
+		n := newTestNotifier(t, target)
+		if err := n.HandleEventBridge(ctx, changeEvent("/my-app/GITHUB_APP_ID")); err != nil {
+			t.Fatalf("HandleEventBridge() error = %v", err)
+		}
+		if target.reloads != 1 {
+			t.Errorf("reloads = %d, want 1", target.reloads)
+		}
+	})
+
+	t.Run("ignores other parameters and events", func(t *testing.T) {
+		target := &countingTarget{}
+		n := newTestNotifier(t, target)
+		other := changeEvent("/my-app/GITHUB_APP_ID")
+		other.DetailType = "Parameter Store Policy Action"
+		for _, e := range []events.CloudWatchEvent{changeEvent("/other/GITHUB_APP_ID"), other} {
+			if err := n.HandleEventBridge(ctx, e); err != nil {
+				t.Errorf("HandleEventBridge() error = %v", err)
+			}
+		}
+		if target.reloads != 0 {
+			t.Errorf("reloads = %d, want 0", target.reloads)
+		}
+	})
+
+	t.Run("returns reload failure", func(t *testing.T) {
+		n := newTestNotifier(t, &countingTarget{err: errors.New("unavailable")})
+		if err := n.HandleEventBridge(ctx, changeEvent("/my-app/GITHUB_APP_ID")); err == nil {
+			t.Error("HandleEventBridge() should return reload error")
+		}
+	})
+}
+
+func TestNotifier_HandleSQS(t *testing.T) {
+	ctx := context.Background()
+	batch := events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "1", Body: changeMessage("/my-app/GITHUB_APP_ID")},
+		{MessageId: "2", Body: changeMessage("/my-app/GITHUB_APP_PRIVATE_KEY")},
+		{MessageId: "3", Body: changeMessage("/other/GITHUB_APP_ID")},
+		{MessageId: "4", Body: "not json"},
+	}}
+
+	t.Run("one reload per batch", func(t *testing.T) {
+		target := &countingTarget{}
+		resp, err := newTestNotifier(t, target).HandleSQS(ctx, batch)
+		if err != nil {
+			t.Fatalf("HandleSQS() error = %v", err)
+		}
+		if target.reloads != 1 {
+			t.Errorf("reloads = %d, want 1", target.reloads)
+		}
+		if len(resp.BatchItemFailures) != 0 {
+			t.Errorf("BatchItemFailures = %v, want none", resp.BatchItemFailures)
+		}
+	})
+
+	t.Run("failed reload retries matching messages", func(t *testing.T) {
+		target := &countingTarget{err: errors.New("unavailable")}
+		resp, err := newTestNotifier(t, target).HandleSQS(ctx, batch)
+		if err != nil {
+			t.Fatalf("HandleSQS() error = %v", err)
+		}
+		var ids []string
+		for _, f := range resp.BatchItemFailures {
+			ids = append(ids, f.ItemIdentifier)
+		}
+		if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+			t.Errorf("BatchItemFailures = %v, want messages 1 and 2", ids)
+		}
+	})
+
+	t.Run("no matching messages", func(t *testing.T) {
+		target := &countingTarget{}
+		_, _ = newTestNotifier(t, target).HandleSQS(ctx, events.SQSEvent{Records: batch.Records[2:]})
+		if target.reloads != 0 {
+			t.Errorf("reloads = %d, want 0", target.reloads)
+		}
+	})
+}
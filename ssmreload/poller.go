@@ -0,0 +1,191 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/chainguard-dev/clog"
+)
+
+const (
+	// pollWaitSeconds is the long-polling wait of each receive, the
+	// maximum SQS allows.
+	pollWaitSeconds = 20
+
+	// DefaultErrorBackoff is how long Poller waits after a failed receive
+	// or reload unless WithErrorBackoff is set.
+	DefaultErrorBackoff = 5 * time.Second
+)
+
+// SQSClient defines the interface for AWS SQS operations.
+type SQSClient interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput,
+		optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput,
+		optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error)
+}
+
+// Poller long-polls an SQS queue targeted by a Parameter Store change
+// rule and reloads the Notifier's targets, for services that consume
+// change events themselves instead of through a Lambda function. Each
+// service instance needs its own queue, since every message is delivered
+// to one consumer only:
+//
+//	notifier, _ := ssmreload.New(ssmreload.WithTarget(ssmreload.TargetFunc(
+//	    func(context.Context) error { runtime.ReloadCallback()(); return nil },
+//	)))
+//	poller, _ := ssmreload.NewPoller(queueURL, notifier)
+//	go poller.Run(ctx)
+type Poller struct {
+	QueueURL     string
+	notifier     *Notifier
+	client       SQSClient
+	errorBackoff time.Duration
+}
+
+// PollerOption is a functional option for configuring Poller.
+type PollerOption func(*Poller)
+
+// WithSQSClient sets a custom SQS client.
+func WithSQSClient(client SQSClient) PollerOption {
+	return func(p *Poller) {
+		p.client = client
+	}
+}
+
+// WithErrorBackoff sets how long Run waits after a failed receive or
+// reload before polling again. Defaults to DefaultErrorBackoff.
+func WithErrorBackoff(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.errorBackoff = d
+	}
+}
+
+// NewPoller creates a poller for the queue at queueURL that reloads the
+// targets of n.
+func NewPoller(queueURL string, n *Notifier, opts ...PollerOption) (*Poller, error) {
+	if queueURL == "" {
+		return nil, errors.New("ssmreload: queue URL cannot be empty")
+	}
+	if n == nil {
+		return nil, errors.New("ssmreload: notifier is required")
+	}
+	p := &Poller{
+		QueueURL:     queueURL,
+		notifier:     n,
+		errorBackoff: DefaultErrorBackoff,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.client == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		p.client = sqs.NewFromConfig(cfg)
+	}
+	return p, nil
+}
+
+// Run polls the queue until ctx is canceled, then returns nil. Each batch
+// of messages triggers at most one reload. Messages are deleted once
+// handled; if the reload fails, the matching messages are left on the
+// queue to be received again after its visibility timeout.
+func (p *Poller) Run(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+	for {
+		if err := p.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Errorf("[ssmreload] %v", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(p.errorBackoff):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// poll receives and handles one batch of messages.
+func (p *Poller) poll(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+
+	out, err := p.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(p.QueueURL),
+		MaxNumberOfMessages: 10,
+		WaitTimeSeconds:     pollWaitSeconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to receive messages: %w", err)
+	}
+
+	var matched, other []sqstypes.Message
+	for _, msg := range out.Messages {
+		change, err := ParseMessage(aws.ToString(msg.Body))
+		switch {
+		case err != nil:
+			log.Warnf("[ssmreload] dropping message %s: %v", aws.ToString(msg.MessageId), err)
+			other = append(other, msg)
+		case !p.notifier.Matches(change):
+			other = append(other, msg)
+		default:
+			log.Infof("[ssmreload] parameter %s changed (%s)", change.Name, change.Operation)
+			matched = append(matched, msg)
+		}
+	}
+
+	var reloadErr error
+	if len(matched) > 0 {
+		if reloadErr = p.notifier.Reload(ctx); reloadErr == nil {
+			other = append(other, matched...)
+		}
+	}
+	p.delete(ctx, other)
+	if reloadErr != nil {
+		return fmt.Errorf("reload failed, %d messages will be retried: %w", len(matched), reloadErr)
+	}
+	return nil
+}
+
+// delete removes handled messages from the queue. Failures are logged;
+// the messages are received and handled again.
+func (p *Poller) delete(ctx context.Context, msgs []sqstypes.Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	log := clog.FromContext(ctx)
+
+	entries := make([]sqstypes.DeleteMessageBatchRequestEntry, len(msgs))
+	for i, msg := range msgs {
+		entries[i] = sqstypes.DeleteMessageBatchRequestEntry{
+			Id:            aws.String(fmt.Sprint(i)),
+			ReceiptHandle: msg.ReceiptHandle,
+		}
+	}
+	out, err := p.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+		QueueUrl: aws.String(p.QueueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		log.Warnf("[ssmreload] failed to delete %d messages: %v", len(msgs), err)
+		return
+	}
+	for _, failed := range out.Failed {
+		log.Warnf("[ssmreload] failed to delete message: %s: %s", aws.ToString(failed.Code), aws.ToString(failed.Message))
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeQueue serves queued batches to ReceiveMessage and records deletes.
+// Once the batches run out, ReceiveMessage blocks until ctx is canceled.
+type fakeQueue struct {
+	mu         sync.Mutex
+	batches    [][]sqstypes.Message
+	receiveErr error
+	deleted    []string
+	drained    chan struct{}
+}
+
+func newFakeQueue(batches ...[]sqstypes.Message) *fakeQueue {
+	return &fakeQueue{batches: batches, drained: make(chan struct{})}
+}
+
+func (q *fakeQueue) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	q.mu.Lock()
+	if q.receiveErr != nil {
+		err := q.receiveErr
+		q.receiveErr = nil
+		q.mu.Unlock()
+		return nil, err
+	}
+	if len(q.batches) > 0 {
+		batch := q.batches[0]
+		q.batches = q.batches[1:]
+		q.mu.Unlock()
+		return &sqs.ReceiveMessageOutput{Messages: batch}, nil
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.drained:
+	default:
+		close(q.drained)
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (q *fakeQueue) DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, e := range params.Entries {
+		q.deleted = append(q.deleted, aws.ToString(e.ReceiptHandle))
+	}
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func queueMessage(handle, body string) sqstypes.Message {
+	return sqstypes.Message{MessageId: aws.String(handle), ReceiptHandle: aws.String(handle), Body: aws.String(body)}
+}
+
+// runPoller runs p until the queue is drained and returns the deleted
+// receipt handles.
+func runPoller(t *testing.T, p *Poller, q *fakeQueue) []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	select {
+	case <-q.drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("poller did not drain the queue")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.deleted
+}
+
+func TestNewPoller(t *testing.T) {
+	n := newTestNotifier(t, &countingTarget{})
+	if _, err := NewPoller("", n, WithSQSClient(newFakeQueue())); err == nil {
+		t.Error("NewPoller() with empty queue URL should return error")
+	}
+	if _, err := NewPoller("https://sqs.example.com/q", nil, WithSQSClient(newFakeQueue())); err == nil {
+		t.Error("NewPoller() without notifier should return error")
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	q := newFakeQueue(
+		[]sqstypes.Message{
+			queueMessage("a", changeMessage("/my-app/GITHUB_APP_ID")),
+			queueMessage("b", changeMessage("/my-app/GITHUB_APP_PRIVATE_KEY")),
+			queueMessage("c", "not json"),
+		},
+		[]sqstypes.Message{queueMessage("d", changeMessage("/other/GITHUB_APP_ID"))},
+	)
+	q.receiveErr = errors.New("throttled")
+
+	target := &countingTarget{}
+	p, err := NewPoller("https://sqs.example.com/q", newTestNotifier(t, target),
+		WithSQSClient(q), WithErrorBackoff(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewPoller() error = %v", err)
+	}
+
+	deleted := runPoller(t, p, q)
+	if target.reloads != 1 {
+		t.Errorf("reloads = %d, want 1", target.reloads)
+	}
+	if len(deleted) != 4 {
+		t.Errorf("deleted = %v, want every message", deleted)
+	}
+}
+
+func TestPoller_ReloadFailureKeepsMessages(t *testing.T) {
+	q := newFakeQueue([]sqstypes.Message{
+		queueMessage("a", changeMessage("/my-app/GITHUB_APP_ID")),
+		queueMessage("b", changeMessage("/other/GITHUB_APP_ID")),
+	})
+
+	target := &countingTarget{err: errors.New("unavailable")}
+	p, err := NewPoller("https://sqs.example.com/q", newTestNotifier(t, target),
+		WithSQSClient(q), WithErrorBackoff(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewPoller() error = %v", err)
+	}
+
+	deleted := runPoller(t, p, q)
+	if len(deleted) != 1 || deleted[0] != "b" {
+		t.Errorf("deleted = %v, want only the unmatched message", deleted)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package ssmreload reloads running services when their SSM Parameter
+// Store parameters change. EventBridge emits a "Parameter Store Change"
+// event for every parameter edit; a Notifier consumes these events, from a
+// Lambda function (HandleEventBridge, HandleSQS) or from a queue polled by
+// the service itself (Poller), and triggers a reload of each target: the
+// authenticated reload endpoint of a running service (see
+// ghappsetup.Runtime.ReloadHandler), or an in-process reload trigger.
+package ssmreload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+const (
+	// EventSource and EventDetailType identify Parameter Store change
+	// events on EventBridge.
+	EventSource     = "aws.ssm"
+	EventDetailType = "Parameter Store Change"
+
+	// EnvReloadURLs lists the reload endpoints New calls when no target is
+	// set with an option, separated by commas.
+	EnvReloadURLs = "GHAPPSETUP_RELOAD_URLS"
+
+	// EnvReloadToken is the bearer token sent to the reload endpoints. It
+	// is the variable ghappsetup reads for Config.ReloadToken, so the
+	// consumer and the services can share one value.
+	EnvReloadToken = "GHAPPSETUP_RELOAD_TOKEN"
+)
+
+// ErrNotParameterChange is returned by ParseEvent and ParseMessage for
+// events other than Parameter Store changes.
+var ErrNotParameterChange = errors.New("ssmreload: not a parameter store change event")
+
+// ChangeEvent is the detail of a Parameter Store change event.
+type ChangeEvent struct {
+	// Name is the parameter name, e.g. "/my-app/GITHUB_APP_ID".
+	Name string `json:"name"`
+	// Type is the parameter type: String, StringList, or SecureString.
+	Type string `json:"type"`
+	// Operation is Create, Update, Delete, or LabelParameterVersion.
+	Operation string `json:"operation"`
+}
+
+// ParseEvent returns the change described by an EventBridge event, or
+// ErrNotParameterChange for events of other sources or types.
+func ParseEvent(e events.CloudWatchEvent) (*ChangeEvent, error) {
+	if e.Source != EventSource || e.DetailType != EventDetailType {
+		return nil, fmt.Errorf("%w: %s %q", ErrNotParameterChange, e.Source, e.DetailType)
+	}
+	var change ChangeEvent
+	if err := json.Unmarshal(e.Detail, &change); err != nil {
+		return nil, fmt.Errorf("ssmreload: failed to parse event detail: %w", err)
+	}
+	if change.Name == "" {
+		return nil, errors.New("ssmreload: event detail has no parameter name")
+	}
+	return &change, nil
+}
+
+// ParseMessage returns the change described by a queue message body
+// holding an EventBridge event, as delivered to an SQS target of an
+// EventBridge rule.
+func ParseMessage(body string) (*ChangeEvent, error) {
+	var e events.CloudWatchEvent
+	if err := json.Unmarshal([]byte(body), &e); err != nil {
+		return nil, fmt.Errorf("ssmreload: failed to parse message: %w", err)
+	}
+	return ParseEvent(e)
+}
+
+// Notifier triggers reloads of its targets for changes to parameters
+// under its prefix.
+type Notifier struct {
+	Prefix     string
+	targets    []Target
+	httpClient *http.Client
+}
+
+// Option is a functional option for configuring Notifier.
+type Option func(*Notifier)
+
+// WithParameterPrefix sets the parameter name prefix whose changes trigger
+// reloads. Defaults to AWS_SSM_PARAMETER_PREFIX, the prefix the services'
+// configstore.AWSSSMStore reads. As with the store, a trailing "/" is
+// added; an empty prefix matches every parameter.
+func WithParameterPrefix(prefix string) Option {
+	return func(n *Notifier) {
+		n.Prefix = prefix
+	}
+}
+
+// WithTarget adds a reload target, such as a TargetFunc wrapping
+// ghappsetup.Runtime.ReloadCallback for services that poll the queue
+// themselves.
+func WithTarget(t Target) Option {
+	return func(n *Notifier) {
+		n.targets = append(n.targets, t)
+	}
+}
+
+// WithReloadURL adds the reload endpoint of a running service as a target,
+// called with token as a bearer token if it is not empty.
+func WithReloadURL(url, token string) Option {
+	return func(n *Notifier) {
+		n.targets = append(n.targets, &URLTarget{URL: url, Token: token})
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call reload endpoints added
+// with WithReloadURL or GHAPPSETUP_RELOAD_URLS. Defaults to a client with
+// DefaultTimeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.httpClient = client
+	}
+}
+
+// New creates a Notifier. Without targets set by WithTarget or
+// WithReloadURL, the endpoints listed in GHAPPSETUP_RELOAD_URLS are
+// called with the token in GHAPPSETUP_RELOAD_TOKEN. It returns an error if
+// there are no targets.
+func New(opts ...Option) (*Notifier, error) {
+	n := &Notifier{
+		Prefix: os.Getenv(configstore.EnvAWSSSMParameterPfx),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.Prefix != "" && !strings.HasSuffix(n.Prefix, "/") {
+		n.Prefix += "/"
+	}
+
+	if len(n.targets) == 0 {
+		token := strings.TrimSpace(os.Getenv(EnvReloadToken))
+		for url := range strings.SplitSeq(os.Getenv(EnvReloadURLs), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				WithReloadURL(url, token)(n)
+			}
+		}
+	}
+	if len(n.targets) == 0 {
+		return nil, fmt.Errorf("ssmreload: no reload targets (set %s)", EnvReloadURLs)
+	}
+	for _, t := range n.targets {
+		if u, ok := t.(*URLTarget); ok && u.Client == nil {
+			u.Client = n.httpClient
+		}
+	}
+	return n, nil
+}
+
+// Matches reports whether a change to the parameter triggers reloads.
+func (n *Notifier) Matches(change *ChangeEvent) bool {
+	return strings.HasPrefix(change.Name, n.Prefix)
+}
+
+// Reload triggers a reload of every target, returning the errors of the
+// targets that failed joined together.
+func (n *Notifier) Reload(ctx context.Context) error {
+	var errs []error
+	for _, t := range n.targets {
+		if err := t.Reload(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// changeEvent returns a Parameter Store change event for the parameter.
+func changeEvent(name string) events.CloudWatchEvent {
+	detail, _ := json.Marshal(ChangeEvent{Name: name, Type: "SecureString", Operation: "Update"})
+	return events.CloudWatchEvent{
+		ID:         "event-" + name,
+		Source:     EventSource,
+		DetailType: EventDetailType,
+		Detail:     detail,
+	}
+}
+
+// changeMessage returns the SQS message body EventBridge delivers for a
+// change to the parameter.
+func changeMessage(name string) string {
+	body, _ := json.Marshal(changeEvent(name))
+	return string(body)
+}
+
+// countingTarget records reloads and fails with err, if set.
+type countingTarget struct {
+	reloads int
+	err     error
+}
+
+func (t *countingTarget) Reload(ctx context.Context) error {
+	t.reloads++
+	return t.err
+}
+
+func TestParseEvent(t *testing.T) {
+	change, err := ParseEvent(changeEvent("/my-app/GITHUB_APP_ID"))
+	if err != nil {
+		t.Fatalf("ParseEvent() error = %v", err)
+	}
+	if change.Name != "/my-app/GITHUB_APP_ID" || change.Operation != "Update" || change.Type != "SecureString" {
+		t.Errorf("ParseEvent() = %+v", change)
+	}
+
+	other := changeEvent("/my-app/GITHUB_APP_ID")
+	other.Source = "aws.secretsmanager"
+	if _, err := ParseEvent(other); !errors.Is(err, ErrNotParameterChange) {
+		t.Errorf("ParseEvent() of other source error = %v, want ErrNotParameterChange", err)
+	}
+
+	unnamed := changeEvent("")
+	if _, err := ParseEvent(unnamed); err == nil {
+		t.Error("ParseEvent() without parameter name should return error")
+	}
+
+	malformed := changeEvent("/x")
+	malformed.Detail = json.RawMessage(`"not an object"`)
+	if _, err := ParseEvent(malformed); err == nil {
+		t.Error("ParseEvent() with malformed detail should return error")
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	change, err := ParseMessage(changeMessage("/my-app/GITHUB_WEBHOOK_SECRET"))
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if change.Name != "/my-app/GITHUB_WEBHOOK_SECRET" {
+		t.Errorf("Name = %q", change.Name)
+	}
+
+	if _, err := ParseMessage("not json"); err == nil {
+		t.Error("ParseMessage() of invalid body should return error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("no targets", func(t *testing.T) {
+		t.Setenv(EnvReloadURLs, "")
+		if _, err := New(); err == nil {
+			t.Error("New() without targets should return error")
+		}
+	})
+
+	t.Run("targets from environment", func(t *testing.T) {
+		t.Setenv(EnvReloadURLs, "https://a.example.com/reload, https://b.example.com/reload,")
+		t.Setenv(EnvReloadToken, "secret")
+		t.Setenv(configstore.EnvAWSSSMParameterPfx, "/my-app")
+
+		n, err := New()
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if n.Prefix != "/my-app/" {
+			t.Errorf("Prefix = %q, want %q", n.Prefix, "/my-app/")
+		}
+		if len(n.targets) != 2 {
+			t.Fatalf("targets = %d, want 2", len(n.targets))
+		}
+		if u := n.targets[1].(*URLTarget); u.URL != "https://b.example.com/reload" || u.Token != "secret" {
+			t.Errorf("target = %+v, want second URL with token", u)
+		}
+	})
+
+	t.Run("options replace environment", func(t *testing.T) {
+		t.Setenv(EnvReloadURLs, "https://a.example.com/reload")
+		t.Setenv(configstore.EnvAWSSSMParameterPfx, "/my-app/")
+
+		n, err := New(WithParameterPrefix(""), WithTarget(&countingTarget{}))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if n.Prefix != "" || len(n.targets) != 1 {
+			t.Errorf("Notifier = %+v, want empty prefix and one target", n)
+		}
+	})
+}
+
+func TestNotifier_Matches(t *testing.T) {
+	n, err := New(WithParameterPrefix("/my-app"), WithTarget(&countingTarget{}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := map[string]bool{
+		"/my-app/GITHUB_APP_ID":         true,
+		"/my-app-staging/GITHUB_APP_ID": false,
+		"/other/GITHUB_APP_ID":          false,
+	}
+	for name, want := range tests {
+		if got := n.Matches(&ChangeEvent{Name: name}); got != want {
+			t.Errorf("Matches(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNotifier_Reload(t *testing.T) {
+	ok := &countingTarget{}
+	failing := &countingTarget{err: errors.New("unavailable")}
+	n, err := New(WithTarget(failing), WithTarget(ok))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	err = n.Reload(context.Background())
+	if err == nil || err.Error() != "unavailable" {
+		t.Errorf("Reload() error = %v, want the failing target's error", err)
+	}
+	if ok.reloads != 1 || failing.reloads != 1 {
+		t.Errorf("reloads = %d and %d, want every target reloaded once", failing.reloads, ok.reloads)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each call to a reload endpoint unless a custom
+// client is set with WithHTTPClient. Endpoints respond once the reload has
+// finished, so it allows for a slow store.
+const DefaultTimeout = 30 * time.Second
+
+// Target is something a Notifier can reload.
+type Target interface {
+	Reload(ctx context.Context) error
+}
+
+// TargetFunc adapts a function to a Target. To trigger the reload of a
+// Runtime in the same process, honoring its Config.ReloadCooldown:
+//
+//	ssmreload.TargetFunc(func(context.Context) error {
+//	    runtime.ReloadCallback()()
+//	    return nil
+//	})
+type TargetFunc func(ctx context.Context) error
+
+// Reload calls f.
+func (f TargetFunc) Reload(ctx context.Context) error {
+	return f(ctx)
+}
+
+// URLTarget is the reload endpoint of a running service, such as
+// ghappsetup.Runtime.ReloadHandler. Reload POSTs to URL and fails unless
+// the endpoint responds with a 2xx status.
+type URLTarget struct {
+	URL string
+	// Token is sent as a bearer token if it is not empty.
+	Token string
+	// Client is the HTTP client. If nil, a client with DefaultTimeout is
+	// used.
+	Client *http.Client
+}
+
+// Reload calls the reload endpoint.
+func (t *URLTarget) Reload(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, nil)
+	if err != nil {
+		return fmt.Errorf("ssmreload: invalid reload URL %s: %w", t.URL, err)
+	}
+	if t.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ssmreload: reload request to %s failed: %w", t.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ssmreload: reload endpoint %s returned %d: %s", t.URL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmreload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestURLTarget_Reload(t *testing.T) {
+	var gotMethod, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotAuth = r.Method, r.Header.Get("Authorization")
+		if gotAuth != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("reloaded"))
+	}))
+	defer srv.Close()
+
+	target := &URLTarget{URL: srv.URL + "/admin/reload", Token: "secret"}
+	if err := target.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %s, want POST", gotMethod)
+	}
+
+	target.Token = "wrong"
+	err := target.Reload(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "unauthorized") {
+		t.Errorf("Reload() error = %v, want 401 with response body", err)
+	}
+
+	target.Token = ""
+	_ = target.Reload(context.Background())
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none without token", gotAuth)
+	}
+}
+
+func TestURLTarget_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	target := &URLTarget{URL: url, Client: srv.Client()}
+	if err := target.Reload(context.Background()); err == nil {
+		t.Error("Reload() of closed server should return error")
+	}
+}
+
+func TestTargetFunc(t *testing.T) {
+	called := false
+	var target Target = TargetFunc(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if err := target.Reload(context.Background()); err != nil || !called {
+		t.Errorf("Reload() error = %v, called = %v", err, called)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+// DefaultAssumeRoleSessionName is the session name used when
+// AssumeRoleConfig.SessionName is empty.
+const DefaultAssumeRoleSessionName = "ssmresolver"
+
+// AssumeRoleConfig configures an IAM role assumed before fetching
+// parameters.
+type AssumeRoleConfig struct {
+	// RoleARN is the ARN of the role to assume. Required.
+	RoleARN string
+
+	// ExternalID is passed to sts:AssumeRole when the role's trust policy
+	// requires one.
+	ExternalID string
+
+	// SessionName identifies the role session in CloudTrail. Defaults to
+	// DefaultAssumeRoleSessionName.
+	SessionName string
+
+	// Duration is the requested session duration. If zero, the STS
+	// default applies.
+	Duration time.Duration
+}
+
+// WithAssumeRole makes New fetch parameters using credentials for the
+// given role, assumed via STS with the default credential chain. This lets
+// a central "config-reader" role be used across accounts without changing
+// the function's execution role. Credentials are cached and refreshed
+// before they expire. The option has no effect on resolvers created with
+// NewWithClient, whose client already carries its credentials.
+func WithAssumeRole(cfg AssumeRoleConfig) Option {
+	return func(r *Resolver) {
+		r.assumeRole = &cfg
+	}
+}
+
+// assumeRoleCredentials returns a cached credentials provider for the role
+// described by ar, using client to call STS.
+func assumeRoleCredentials(client stscreds.AssumeRoleAPIClient, ar AssumeRoleConfig) aws.CredentialsProvider {
+	provider := stscreds.NewAssumeRoleProvider(client, ar.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = ar.SessionName
+		if o.RoleSessionName == "" {
+			o.RoleSessionName = DefaultAssumeRoleSessionName
+		}
+		if ar.ExternalID != "" {
+			o.ExternalID = aws.String(ar.ExternalID)
+		}
+		if ar.Duration > 0 {
+			o.Duration = ar.Duration
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
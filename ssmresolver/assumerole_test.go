@@ -0,0 +1,70 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// mockSTSClient implements stscreds.AssumeRoleAPIClient for testing
+type mockSTSClient struct {
+	input *sts.AssumeRoleInput
+}
+
+func (m *mockSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	m.input = params
+	return &sts.AssumeRoleOutput{
+		Credentials: &types.Credentials{
+			AccessKeyId:     aws.String("AKIDEXAMPLE"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("token"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+func TestAssumeRoleCredentials(t *testing.T) {
+	client := &mockSTSClient{}
+	provider := assumeRoleCredentials(client, AssumeRoleConfig{
+		RoleARN:    "arn:aws:iam::111122223333:role/config-reader",
+		ExternalID: "ext-123",
+		Duration:   30 * time.Minute,
+	})
+
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if creds.AccessKeyID != "AKIDEXAMPLE" {
+		t.Errorf("AccessKeyID = %q, want %q", creds.AccessKeyID, "AKIDEXAMPLE")
+	}
+
+	in := client.input
+	if aws.ToString(in.RoleArn) != "arn:aws:iam::111122223333:role/config-reader" {
+		t.Errorf("RoleArn = %q", aws.ToString(in.RoleArn))
+	}
+	if aws.ToString(in.ExternalId) != "ext-123" {
+		t.Errorf("ExternalId = %q, want %q", aws.ToString(in.ExternalId), "ext-123")
+	}
+	if aws.ToString(in.RoleSessionName) != DefaultAssumeRoleSessionName {
+		t.Errorf("RoleSessionName = %q, want %q", aws.ToString(in.RoleSessionName), DefaultAssumeRoleSessionName)
+	}
+	if aws.ToInt32(in.DurationSeconds) != 1800 {
+		t.Errorf("DurationSeconds = %d, want 1800", aws.ToInt32(in.DurationSeconds))
+	}
+}
+
+func TestNew_AssumeRoleRequiresRoleARN(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	if _, err := New(context.Background(), WithAssumeRole(AssumeRoleConfig{SessionName: "test"})); err == nil {
+		t.Error("New() should return error when RoleARN is empty")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestResolver_CacheTTL(t *testing.T) {
+	var calls int
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			calls++
+			value := "secret"
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	}, WithCacheTTL(time.Minute))
+
+	now := time.Now()
+	resolver.cache.now = func() time.Time { return now }
+
+	arn := "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/secret"
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.ResolveValue(context.Background(), arn); err != nil {
+			t.Fatalf("ResolveValue() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("GetParameter called %d times, want 1 while cached", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := resolver.ResolveValue(context.Background(), arn); err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("GetParameter called %d times, want 2 after TTL expiry", calls)
+	}
+
+	resolver.Flush()
+	if _, err := resolver.ResolveValue(context.Background(), arn); err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("GetParameter called %d times, want 3 after Flush()", calls)
+	}
+}
+
+func TestResolver_NoCacheByDefault(t *testing.T) {
+	var calls int
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			calls++
+			value := "secret"
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	arn := "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/secret"
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.ResolveValue(context.Background(), arn); err != nil {
+			t.Fatalf("ResolveValue() error = %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("GetParameter called %d times, want 2 without cache", calls)
+	}
+
+	// Flush is a no-op without a cache
+	resolver.Flush()
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/chainguard-dev/clog"
+)
+
+// defaultSeparator introduces a fallback value used when the referenced
+// parameter does not exist (e.g. "ssm:///app/optional|default=info"). An
+// empty value ("|default=") resolves a missing parameter to "".
+const defaultSeparator = "|default="
+
+// errJSONKeyNotFound is returned when an extracted JSON key is missing.
+var errJSONKeyNotFound = errors.New("key not found in JSON value")
+
+// PlaceholderValue is the value "ghappsetup bootstrap aws-ssm" writes to
+// parameters created before the app is registered (it matches
+// configstore.PlaceholderValue). A parameter holding it is treated as not
+// found, so defaults apply and callers see a missing parameter rather than
+// the placeholder.
+const PlaceholderValue = "ghappsetup:placeholder"
+
+// WithDefaults sets fallback values, keyed by environment variable name,
+// used by ResolveEnvironment, ResolveEnvironTo, and ResolveMap when the
+// parameter referenced by that variable does not exist. This keeps
+// optional variables from failing the whole resolution. A "|default="
+// suffix on the reference itself takes precedence.
+func WithDefaults(defaults map[string]string) Option {
+	return func(r *Resolver) {
+		if r.defaults == nil {
+			r.defaults = make(map[string]string, len(defaults))
+		}
+		for k, v := range defaults {
+			r.defaults[k] = v
+		}
+	}
+}
+
+// IsNotFound reports whether err indicates that a referenced parameter,
+// parameter version, file, S3 object, or extracted JSON key does not exist.
+func IsNotFound(err error) bool {
+	var notFound *types.ParameterNotFound
+	var versionNotFound *types.ParameterVersionNotFound
+	var noSuchKey *s3types.NoSuchKey
+	return errors.As(err, &notFound) || errors.As(err, &versionNotFound) ||
+		errors.As(err, &noSuchKey) || errors.Is(err, fs.ErrNotExist) ||
+		errors.Is(err, errJSONKeyNotFound)
+}
+
+// fallback returns def in place of err if err is a not-found error, logging
+// the miss.
+func fallback(ctx context.Context, source string, def *string, err error) (string, error) {
+	if def == nil || !IsNotFound(err) {
+		return "", err
+	}
+	clog.FromContext(ctx).Warnf("[ssmresolver] %s not found, using default: %v", source, err)
+	return *def, nil
+}
+
+// defaultPtr returns a pointer to def if ok is true, or nil.
+func defaultPtr(def string, ok bool) *string {
+	if !ok {
+		return nil
+	}
+	return &def
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// notFoundClient returns ParameterNotFound for names in missing.
+func notFoundClient(missing ...string) *mockSSMClient {
+	return &mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			for _, name := range missing {
+				if *params.Name == name {
+					return nil, &types.ParameterNotFound{Message: aws.String("not found")}
+				}
+			}
+			if *params.Name == "/denied" {
+				return nil, errors.New("access denied")
+			}
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(`{"present":"yes"}`)}}, nil
+		},
+	}
+}
+
+func TestResolveValue_DefaultSuffix(t *testing.T) {
+	resolver := NewWithClient(notFoundClient("/app/optional"))
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "ssm:///app/optional|default=info", want: "info"},
+		{value: "ssm:///app/optional|default=", want: ""},
+		{value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/optional|default=a#b:c", want: "a#b:c"},
+		{value: "ssm:///app/present#missing|default=fallback", want: "fallback"},
+		{value: "ssm:///app/present#present|default=fallback", want: "yes"},
+		{value: "ssm:///app/optional", wantErr: true},
+		{value: "ssm:///denied|default=x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := resolver.ResolveValue(context.Background(), tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveMap_WithDefaults(t *testing.T) {
+	resolver := NewWithClient(notFoundClient("/app/log-level", "/app/required"),
+		WithDefaults(map[string]string{"LOG_LEVEL": "info"}),
+	)
+
+	got, err := resolver.ResolveMap(context.Background(), map[string]string{
+		"LOG_LEVEL": "ssm:///app/log-level",
+	})
+	if err != nil {
+		t.Fatalf("ResolveMap() error = %v", err)
+	}
+	if got["LOG_LEVEL"] != "info" {
+		t.Errorf("LOG_LEVEL = %q, want %q", got["LOG_LEVEL"], "info")
+	}
+
+	_, err = resolver.ResolveMap(context.Background(), map[string]string{
+		"REQUIRED": "ssm:///app/required",
+	})
+	if !IsNotFound(err) {
+		t.Errorf("ResolveMap() error = %v, want not found error", err)
+	}
+}
+
+func TestResolveValue_Placeholder(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(PlaceholderValue)}}, nil
+		},
+	})
+	ctx := context.Background()
+
+	if _, err := resolver.ResolveValue(ctx, "ssm:///app/GITHUB_APP_ID"); !IsNotFound(err) {
+		t.Errorf("ResolveValue() error = %v, want not found", err)
+	}
+	if got, err := resolver.ResolveValue(ctx, "ssm:///app/LOG_LEVEL|default=info"); err != nil || got != "info" {
+		t.Errorf("ResolveValue() = %q, %v, want default", got, err)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// DryRunEntry describes how a single variable would be resolved.
+type DryRunEntry struct {
+	// Variable is the environment variable (or map key) holding the
+	// reference.
+	Variable string `json:"variable"`
+
+	// Parameter is the parameter name, including any version or label.
+	Parameter string `json:"parameter"`
+
+	// Region is the region the parameter would be read from. It is empty
+	// when the region is not known (e.g. a shorthand reference with
+	// NewWithClient and no WithDefaultRegion).
+	Region string `json:"region,omitempty"`
+
+	// JSONKey is the key that would be extracted from the value, if any.
+	JSONKey string `json:"json_key,omitempty"`
+
+	// Checked is true if access was verified with DescribeParameters.
+	Checked bool `json:"checked"`
+
+	// Found is true if DescribeParameters returned the parameter.
+	Found bool `json:"found"`
+
+	// Error describes why the check failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunReport lists the references found during a dry run.
+type DryRunReport struct {
+	Entries []DryRunEntry `json:"entries"`
+}
+
+// OK reports whether every entry was checked and found.
+func (rep *DryRunReport) OK() bool {
+	for _, e := range rep.Entries {
+		if !e.Checked || !e.Found {
+			return false
+		}
+	}
+	return true
+}
+
+// DryRun scans the process environment and reports which variables would
+// be resolved, to which parameter names and regions, and whether each
+// parameter is visible to the caller via DescribeParameters. Nothing is
+// fetched or modified, making it suitable for debugging IAM before a
+// deploy. Parameters are not checked if the client does not implement
+// ssm.DescribeParametersAPIClient. File, S3, and KMS references are not
+// reported.
+func (r *Resolver) DryRun(ctx context.Context) (*DryRunReport, error) {
+	return r.DryRunMap(ctx, environMap())
+}
+
+// DryRunMap is like DryRun but scans values instead of the environment.
+func (r *Resolver) DryRunMap(ctx context.Context, values map[string]string) (*DryRunReport, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	report := &DryRunReport{}
+	for _, key := range keys {
+		value := values[key]
+		ref, ok := parseReference(value)
+		if !ok {
+			if IsSSMARN(value) {
+				return nil, fmt.Errorf("invalid SSM ARN format for %s: %s", key, value)
+			}
+			continue
+		}
+		if ref.kind != kindSSM {
+			continue
+		}
+
+		entry := DryRunEntry{
+			Variable:  key,
+			Parameter: ref.qualifiedName(),
+			Region:    ref.region,
+			JSONKey:   ref.jsonKey,
+		}
+		if entry.Region == "" {
+			entry.Region = r.regional.defaultRegion
+		}
+		r.checkAccess(ctx, ref, &entry)
+		report.Entries = append(report.Entries, entry)
+	}
+	return report, nil
+}
+
+// checkAccess looks up the parameter with DescribeParameters and records
+// the outcome in entry.
+func (r *Resolver) checkAccess(ctx context.Context, ref reference, entry *DryRunEntry) {
+	client, ok := r.clientFor(ref.region).(ssm.DescribeParametersAPIClient)
+	if !ok {
+		entry.Error = "SSM client does not support DescribeParameters"
+		return
+	}
+
+	resp, err := client.DescribeParameters(ctx, &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{{
+			Key:    ptr("Name"),
+			Option: ptr("Equals"),
+			Values: []string{ref.name},
+		}},
+	})
+	entry.Checked = true
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	entry.Found = len(resp.Parameters) > 0
+	if !entry.Found {
+		entry.Error = "parameter not found"
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// mockDescribeClient implements Client and ssm.DescribeParametersAPIClient
+type mockDescribeClient struct {
+	mockSSMClient
+	existing map[string]bool
+}
+
+func (m *mockDescribeClient) DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	name := params.ParameterFilters[0].Values[0]
+	if name == "/denied" {
+		return nil, errors.New("AccessDeniedException")
+	}
+	out := &ssm.DescribeParametersOutput{}
+	if m.existing[name] {
+		out.Parameters = []types.ParameterMetadata{{Name: aws.String(name)}}
+	}
+	return out, nil
+}
+
+func TestDryRunMap(t *testing.T) {
+	client := &mockDescribeClient{
+		mockSSMClient: mockSSMClient{
+			getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+				t.Fatal("GetParameter should not be called during a dry run")
+				return nil, nil
+			},
+		},
+		existing: map[string]bool{"/app/id": true},
+	}
+	resolver := NewWithClient(client, WithDefaultRegion("us-east-1"))
+
+	report, err := resolver.DryRunMap(context.Background(), map[string]string{
+		"APP_ID":  "arn:aws:ssm:us-east-1:123456789012:parameter/app/id:3",
+		"SECRET":  "ssm:///app/creds#secret",
+		"DENIED":  "ssm:///denied",
+		"LITERAL": "plain-value",
+	})
+	if err != nil {
+		t.Fatalf("DryRunMap() error = %v", err)
+	}
+
+	if len(report.Entries) != 3 {
+		t.Fatalf("len(Entries) = %d, want 3", len(report.Entries))
+	}
+	byVar := map[string]DryRunEntry{}
+	for _, e := range report.Entries {
+		byVar[e.Variable] = e
+	}
+
+	if e := byVar["APP_ID"]; e.Parameter != "/app/id:3" || e.Region != "us-east-1" || !e.Checked || !e.Found {
+		t.Errorf("APP_ID entry = %+v", e)
+	}
+	if e := byVar["SECRET"]; e.JSONKey != "secret" || e.Found || e.Error == "" {
+		t.Errorf("SECRET entry = %+v, want not found", e)
+	}
+	if e := byVar["DENIED"]; !e.Checked || e.Found || e.Error != "AccessDeniedException" {
+		t.Errorf("DENIED entry = %+v, want access error", e)
+	}
+	if report.OK() {
+		t.Error("OK() should be false when a parameter is missing")
+	}
+}
+
+func TestDryRunMap_Unchecked(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{})
+
+	report, err := resolver.DryRunMap(context.Background(), map[string]string{
+		"APP_ID": "ssm:///app/id",
+	})
+	if err != nil {
+		t.Fatalf("DryRunMap() error = %v", err)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Checked {
+		t.Errorf("Entries = %+v, want one unchecked entry", report.Entries)
+	}
+}
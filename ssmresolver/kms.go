@@ -0,0 +1,59 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// KMSClient defines the interface for KMS operations used to resolve
+// kms:// references.
+type KMSClient interface {
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// WithKMSClient sets the client used to resolve kms:// references. New
+// creates one from the default AWS configuration if none is set.
+func WithKMSClient(client KMSClient) Option {
+	return func(r *Resolver) {
+		r.kms = client
+	}
+}
+
+// decrypt decrypts the base64-encoded ciphertext of a kms:// reference,
+// consulting the cache when enabled. The key is identified by the
+// ciphertext itself, so no key ID is needed.
+func (r *Resolver) decrypt(ctx context.Context, ref reference) (string, error) {
+	if r.kms == nil {
+		return "", errors.New("no KMS client configured for " + ref.String())
+	}
+
+	cacheKey := SchemeKMS + ref.name
+	if r.cache != nil {
+		if v, ok := r.cache.get(cacheKey); ok {
+			return v, nil
+		}
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(ref.name)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 in %s: %w", ref, err)
+	}
+
+	resp, err := r.kms.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", ref, err)
+	}
+
+	value := string(resp.Plaintext)
+	if r.cache != nil {
+		r.cache.set(cacheKey, value)
+	}
+	return value, nil
+}
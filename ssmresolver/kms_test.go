@@ -0,0 +1,76 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// mockKMSClient "decrypts" by reversing the ciphertext bytes.
+type mockKMSClient struct {
+	calls int
+}
+
+func (m *mockKMSClient) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	m.calls++
+	if string(params.CiphertextBlob) == "denied" {
+		return nil, errors.New("access denied")
+	}
+	plain := make([]byte, len(params.CiphertextBlob))
+	for i, b := range params.CiphertextBlob {
+		plain[len(plain)-1-i] = b
+	}
+	return &kms.DecryptOutput{Plaintext: plain}, nil
+}
+
+func encrypt(plain string) string {
+	b := []byte(plain)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return SchemeKMS + base64.StdEncoding.EncodeToString(b)
+}
+
+func TestResolveValue_KMS(t *testing.T) {
+	client := &mockKMSClient{}
+	resolver := NewWithClient(&mockSSMClient{}, WithKMSClient(client))
+	ctx := context.Background()
+
+	got, err := resolver.ResolveValue(ctx, encrypt("s3cr3t"))
+	if err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Errorf("ResolveValue() = %q, want %q", got, "s3cr3t")
+	}
+
+	got, err = resolver.ResolveValue(ctx, encrypt(`{"client_id":"abc"}`)+"#client_id")
+	if err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if got != "abc" {
+		t.Errorf("ResolveValue() = %q, want %q", got, "abc")
+	}
+}
+
+func TestResolveValue_KMSErrors(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{}, WithKMSClient(&mockKMSClient{}))
+	ctx := context.Background()
+
+	if _, err := resolver.ResolveValue(ctx, "kms://not*base64"); err == nil {
+		t.Error("ResolveValue() expected error for invalid base64, got nil")
+	}
+	denied := SchemeKMS + base64.StdEncoding.EncodeToString([]byte("denied"))
+	if _, err := resolver.ResolveValue(ctx, denied); err == nil {
+		t.Error("ResolveValue() expected error when Decrypt fails, got nil")
+	}
+	if _, err := NewWithClient(&mockSSMClient{}).ResolveValue(ctx, encrypt("x")); err == nil {
+		t.Error("ResolveValue() expected error without a KMS client, got nil")
+	}
+}
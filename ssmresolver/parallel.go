@@ -0,0 +1,60 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of references resolved in parallel by
+// ResolveEnvironment, ResolveEnvironTo, and ResolveMap unless overridden
+// with WithConcurrency.
+const DefaultConcurrency = 8
+
+// WithConcurrency bounds how many distinct references are fetched in
+// parallel. A limit of 1 resolves references sequentially; a non-positive
+// limit uses DefaultConcurrency.
+func WithConcurrency(n int) Option {
+	return func(r *Resolver) {
+		r.concurrency = n
+	}
+}
+
+// resolveResult is the outcome of resolving a single reference.
+type resolveResult struct {
+	value string
+	err   error
+}
+
+// resolveAll resolves each of refs with ResolveValue, running up to the
+// configured concurrency at once, and returns the results keyed by
+// reference.
+func (r *Resolver) resolveAll(ctx context.Context, refs []string) map[string]resolveResult {
+	limit := r.concurrency
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+
+	results := make(map[string]resolveResult, len(refs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for _, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			v, err := r.ResolveValue(ctx, ref)
+			mu.Lock()
+			results[ref] = resolveResult{value: v, err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// slowClient counts calls and the peak number of concurrent calls.
+func slowClient(calls, peak *atomic.Int32) *mockSSMClient {
+	var running atomic.Int32
+	return &mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			calls.Add(1)
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: params.Name}}, nil
+		},
+	}
+}
+
+func TestResolveMap_Concurrency(t *testing.T) {
+	values := make(map[string]string)
+	for i := 0; i < 6; i++ {
+		values[fmt.Sprintf("VAR_%d", i)] = fmt.Sprintf("ssm:///app/param-%d", i)
+	}
+
+	for _, tt := range []struct {
+		limit    int
+		wantPeak int32
+	}{
+		{limit: 1, wantPeak: 1},
+		{limit: 3, wantPeak: 3},
+	} {
+		t.Run(fmt.Sprintf("limit=%d", tt.limit), func(t *testing.T) {
+			var calls, peak atomic.Int32
+			resolver := NewWithClient(slowClient(&calls, &peak), WithConcurrency(tt.limit))
+
+			got, err := resolver.ResolveMap(context.Background(), values)
+			if err != nil {
+				t.Fatalf("ResolveMap() error = %v", err)
+			}
+			if got["VAR_4"] != "/app/param-4" {
+				t.Errorf("VAR_4 = %q, want %q", got["VAR_4"], "/app/param-4")
+			}
+			if p := peak.Load(); p > tt.wantPeak {
+				t.Errorf("peak concurrency = %d, want at most %d", p, tt.wantPeak)
+			}
+			if calls.Load() != 6 {
+				t.Errorf("GetParameter called %d times, want 6", calls.Load())
+			}
+		})
+	}
+}
+
+func TestResolveMap_DeduplicatesSharedReferences(t *testing.T) {
+	var calls, peak atomic.Int32
+	resolver := NewWithClient(slowClient(&calls, &peak))
+
+	got, err := resolver.ResolveMap(context.Background(), map[string]string{
+		"PRIMARY": "ssm:///app/shared",
+		"ALIAS":   "ssm:///app/shared",
+	})
+	if err != nil {
+		t.Fatalf("ResolveMap() error = %v", err)
+	}
+	if got["PRIMARY"] != "/app/shared" || got["ALIAS"] != "/app/shared" {
+		t.Errorf("ResolveMap() = %v", got)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("GetParameter called %d times, want 1", calls.Load())
+	}
+}
+
+// BenchmarkResolveMap compares serial and parallel resolution of ten
+// references against a client with 1ms of simulated SSM latency.
+func BenchmarkResolveMap(b *testing.B) {
+	values := make(map[string]string)
+	for i := 0; i < 10; i++ {
+		values[fmt.Sprintf("VAR_%d", i)] = fmt.Sprintf("ssm:///app/param-%d", i)
+	}
+	client := &mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			time.Sleep(time.Millisecond)
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: params.Name}}, nil
+		},
+	}
+
+	for _, limit := range []int{1, DefaultConcurrency} {
+		b.Run(fmt.Sprintf("concurrency=%d", limit), func(b *testing.B) {
+			resolver := NewWithClient(client, WithConcurrency(limit))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := resolver.ResolveMap(context.Background(), values); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
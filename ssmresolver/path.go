@@ -0,0 +1,122 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// PathOptions configures ResolvePath.
+type PathOptions struct {
+	// Recursive includes parameters in nested paths below the path.
+	Recursive bool
+
+	// StripPrefix removes the requested path from each parameter name, so
+	// "/myapp/prod/GITHUB_APP_ID" under "/myapp/prod/" becomes
+	// "GITHUB_APP_ID". Otherwise the full name minus its leading slash is
+	// used.
+	StripPrefix bool
+
+	// Normalize converts names to environment variable style by
+	// uppercasing them and replacing every character other than letters,
+	// digits, and underscores with an underscore (e.g. "db/password"
+	// becomes "DB_PASSWORD").
+	Normalize bool
+
+	// EnvPrefix is prepended to each resulting variable name.
+	EnvPrefix string
+}
+
+// ErrPathUnsupported is returned by ResolvePath when the resolver's client
+// does not implement GetParametersByPath.
+var ErrPathUnsupported = errors.New("SSM client does not support GetParametersByPath")
+
+// ResolvePath fetches every parameter under path, decrypted and across all
+// result pages, and sets each as an environment variable named according
+// to opts. This lets applications point at a prefix such as "/myapp/prod/"
+// instead of enumerating every variable. Parameters holding
+// PlaceholderValue are skipped.
+//
+// The resolver's client must implement ssm.GetParametersByPathAPIClient;
+// the client created by New does.
+func (r *Resolver) ResolvePath(ctx context.Context, path string, opts PathOptions) error {
+	values, err := r.fetchPath(ctx, path, opts)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// fetchPath returns the parameters under path keyed by variable name.
+func (r *Resolver) fetchPath(ctx context.Context, path string, opts PathOptions) (map[string]string, error) {
+	client, ok := r.client.(ssm.GetParametersByPathAPIClient)
+	if !ok {
+		return nil, ErrPathUnsupported
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	values := make(map[string]string)
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:           &path,
+		Recursive:      &opts.Recursive,
+		WithDecryption: ptr(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SSM parameters by path %s: %w", path, err)
+		}
+		for _, p := range page.Parameters {
+			if p.Name == nil || p.Value == nil || *p.Value == PlaceholderValue {
+				continue
+			}
+			key := pathEnvName(*p.Name, path, opts)
+			if key == "" {
+				continue
+			}
+			values[key] = *p.Value
+		}
+	}
+	return values, nil
+}
+
+// pathEnvName derives an environment variable name from a parameter name.
+func pathEnvName(name, path string, opts PathOptions) string {
+	if opts.StripPrefix {
+		name = strings.TrimPrefix(name, strings.TrimSuffix(path, "/"))
+	}
+	name = strings.TrimLeft(name, "/")
+
+	if opts.Normalize {
+		name = strings.Map(func(c rune) rune {
+			switch {
+			case c >= 'a' && c <= 'z':
+				return c - 'a' + 'A'
+			case c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+				return c
+			default:
+				return '_'
+			}
+		}, name)
+	}
+
+	if name == "" {
+		return ""
+	}
+	return opts.EnvPrefix + name
+}
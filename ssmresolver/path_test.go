@@ -0,0 +1,110 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// mockPathClient implements Client and ssm.GetParametersByPathAPIClient,
+// serving one page per entry in pages
+type mockPathClient struct {
+	mockSSMClient
+	pages [][]types.Parameter
+	input *ssm.GetParametersByPathInput
+}
+
+func (m *mockPathClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	m.input = params
+	page := 0
+	if params.NextToken != nil {
+		page = int((*params.NextToken)[0] - '0')
+	}
+	out := &ssm.GetParametersByPathOutput{Parameters: m.pages[page]}
+	if page+1 < len(m.pages) {
+		out.NextToken = aws.String(string(rune('0' + page + 1)))
+	}
+	return out, nil
+}
+
+func TestResolvePath(t *testing.T) {
+	client := &mockPathClient{
+		pages: [][]types.Parameter{
+			{
+				{Name: aws.String("/myapp/prod/GITHUB_APP_ID"), Value: aws.String("12345")},
+				{Name: aws.String("/myapp/prod/db/password"), Value: aws.String("hunter2")},
+			},
+			{
+				{Name: aws.String("/myapp/prod/webhook-secret"), Value: aws.String("s3cret")},
+			},
+		},
+	}
+	resolver := NewWithClient(client)
+
+	for _, key := range []string{"APP_GITHUB_APP_ID", "APP_DB_PASSWORD", "APP_WEBHOOK_SECRET"} {
+		t.Cleanup(func() { os.Unsetenv(key) })
+	}
+
+	err := resolver.ResolvePath(context.Background(), "/myapp/prod/", PathOptions{
+		Recursive:   true,
+		StripPrefix: true,
+		Normalize:   true,
+		EnvPrefix:   "APP_",
+	})
+	if err != nil {
+		t.Fatalf("ResolvePath() error = %v", err)
+	}
+
+	want := map[string]string{
+		"APP_GITHUB_APP_ID":  "12345",
+		"APP_DB_PASSWORD":    "hunter2",
+		"APP_WEBHOOK_SECRET": "s3cret",
+	}
+	for key, value := range want {
+		if got := os.Getenv(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+
+	if !aws.ToBool(client.input.Recursive) || !aws.ToBool(client.input.WithDecryption) {
+		t.Error("GetParametersByPath should be called recursively with decryption")
+	}
+}
+
+func TestPathEnvName(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PathOptions
+		want string
+	}{
+		{"/myapp/prod/KEY", PathOptions{}, "myapp/prod/KEY"},
+		{"/myapp/prod/KEY", PathOptions{StripPrefix: true}, "KEY"},
+		{"/myapp/prod/db/password", PathOptions{StripPrefix: true, Normalize: true}, "DB_PASSWORD"},
+		{"/myapp/prod/my-key.v2", PathOptions{StripPrefix: true, Normalize: true}, "MY_KEY_V2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathEnvName(tt.name, "/myapp/prod", tt.opts); got != tt.want {
+				t.Errorf("pathEnvName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePath_Unsupported(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{})
+
+	err := resolver.ResolvePath(context.Background(), "/myapp/prod/", PathOptions{})
+	if !errors.Is(err, ErrPathUnsupported) {
+		t.Errorf("ResolvePath() error = %v, want %v", err, ErrPathUnsupported)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonKeySeparator separates a parameter reference from the JSON key to
+// extract from its value (e.g. "arn:...:parameter/creds#client_secret").
+// SSM parameter names cannot contain "#", so the suffix is unambiguous.
+// File paths and S3 keys can, so a literal "#" in them is written as
+// escapedJSONKeySeparator.
+const jsonKeySeparator = "#"
+
+// escapedJSONKeySeparator is the percent-encoding of a "#" that is part of
+// a file path or S3 key rather than a JSON key separator (e.g.
+// "file:///run/secrets/app%231#client_id" reads "/run/secrets/app#1").
+const escapedJSONKeySeparator = "%23"
+
+// selectorSeparator separates a parameter name from a version number or
+// label (e.g. "parameter/app/key:3" or "parameter/app/key:prod"). SSM
+// parameter names cannot contain ":".
+const selectorSeparator = ":"
+
+// Shorthand URL schemes accepted in place of a full ARN. Both resolve
+// against the resolver's default region and request decryption.
+const (
+	SchemeSSM       = "ssm://"
+	SchemeSSMSecure = "ssm-secure://"
+)
+
+// URL schemes for references resolved outside of Parameter Store: a local
+// file (e.g. a mounted secret, "file:///run/secrets/app-key") or an S3
+// object (e.g. a bootstrap bundle, "s3://my-bucket/app/config.json").
+// Unrelated variables often hold such URLs, so a Resolver only resolves
+// them when enabled with WithFileReferences or WithS3References.
+const (
+	SchemeFile = "file://"
+	SchemeS3   = "s3://"
+)
+
+// SchemeKMS prefixes a base64-encoded KMS ciphertext that is decrypted with
+// KMS Decrypt (e.g. "kms://AQICAHh..."), for encrypted blobs baked into
+// task definitions instead of stored in Parameter Store.
+const SchemeKMS = "kms://"
+
+// referenceKind identifies where a reference is resolved from.
+type referenceKind int
+
+const (
+	kindSSM referenceKind = iota
+	kindFile
+	kindS3
+	kindKMS
+)
+
+// reference is a parsed parameter reference.
+type reference struct {
+	// kind is where the reference is resolved from.
+	kind referenceKind
+
+	// name is the parameter name, always with a leading slash. For file
+	// references it is the file path, for S3 references the object key,
+	// and for KMS references the base64-encoded ciphertext.
+	name string
+
+	// bucket is the S3 bucket of an S3 reference.
+	bucket string
+
+	// region is the region named in an ARN. It is empty for shorthand
+	// references, which resolve against the default region.
+	region string
+
+	// selector, if set, is a version number or label that pins the
+	// parameter (e.g. "3" or "prod"). It is only parsed for SSM references.
+	selector string
+
+	// jsonKey, if set, is the top-level key extracted from the parameter
+	// value after parsing it as a JSON object.
+	jsonKey string
+
+	// fallback, if set, is returned when the parameter, file, object, or
+	// JSON key does not exist.
+	fallback *string
+}
+
+// IsReference checks if the given value is an indirect reference resolved
+// by a Resolver: a full SSM ARN (see IsSSMARN), an ssm:// or ssm-secure://
+// shorthand such as "ssm:///my-app/prod/GITHUB_APP_ID", a file:// path, an
+// s3://bucket/key object, or a kms:// ciphertext. File and S3 references
+// are only resolved by a Resolver that enables them.
+func IsReference(value string) bool {
+	_, ok := parseReference(value)
+	return ok
+}
+
+// parse parses value like parseReference, but rejects file and S3
+// references unless the Resolver enables them.
+func (r *Resolver) parse(value string) (reference, bool) {
+	ref, ok := parseReference(value)
+	switch {
+	case !ok:
+		return reference{}, false
+	case ref.kind == kindFile && !r.files, ref.kind == kindS3 && !r.s3Objects:
+		return reference{}, false
+	}
+	return ref, true
+}
+
+// parseReference parses an SSM ARN, shorthand URL, file:// path, s3://
+// object, or kms:// ciphertext, with optional "#key" and "|default=" suffixes, into a reference.
+func parseReference(value string) (reference, bool) {
+	var fallback *string
+	if i := strings.Index(value, defaultSeparator); i >= 0 {
+		def := value[i+len(defaultSeparator):]
+		value, fallback = value[:i], &def
+	}
+
+	ref := reference{kind: kindSSM, fallback: fallback}
+	var path string
+	switch {
+	case strings.HasPrefix(value, SchemeSSM):
+		path = strings.TrimPrefix(value, SchemeSSM)
+	case strings.HasPrefix(value, SchemeSSMSecure):
+		path = strings.TrimPrefix(value, SchemeSSMSecure)
+	case strings.HasPrefix(value, SchemeFile):
+		ref.kind = kindFile
+		ref.name, ref.jsonKey, _ = strings.Cut(strings.TrimPrefix(value, SchemeFile), jsonKeySeparator)
+		ref.name = unescapeJSONKeySeparator(ref.name)
+		return ref, ref.name != ""
+	case strings.HasPrefix(value, SchemeS3):
+		ref.kind = kindS3
+		path, ref.jsonKey, _ = strings.Cut(strings.TrimPrefix(value, SchemeS3), jsonKeySeparator)
+		ref.bucket, ref.name, _ = strings.Cut(path, "/")
+		ref.name = unescapeJSONKeySeparator(ref.name)
+		return ref, ref.bucket != "" && ref.name != ""
+	case strings.HasPrefix(value, SchemeKMS):
+		ref.kind = kindKMS
+		ref.name, ref.jsonKey, _ = strings.Cut(strings.TrimPrefix(value, SchemeKMS), jsonKeySeparator)
+		return ref, ref.name != ""
+	default:
+		matches := ssmARNPattern.FindStringSubmatch(value)
+		if len(matches) != 3 {
+			return reference{}, false
+		}
+		ref.region, path = matches[1], matches[2]
+	}
+
+	ref.name, ref.jsonKey, _ = strings.Cut(path, jsonKeySeparator)
+	ref.name, ref.selector, _ = strings.Cut(ref.name, selectorSeparator)
+	ref.name = strings.TrimLeft(ref.name, "/")
+	if ref.name == "" {
+		return reference{}, false
+	}
+	ref.name = "/" + ref.name
+	return ref, true
+}
+
+// unescapeJSONKeySeparator restores the literal "#" characters of a file
+// path or S3 key.
+func unescapeJSONKeySeparator(name string) string {
+	return strings.ReplaceAll(name, escapedJSONKeySeparator, jsonKeySeparator)
+}
+
+// String describes the referenced source for logs and errors (e.g.
+// "SSM parameter /app/key:3" or "S3 object s3://bucket/key").
+func (ref reference) String() string {
+	switch ref.kind {
+	case kindFile:
+		return "file " + ref.name
+	case kindS3:
+		return "S3 object " + SchemeS3 + ref.bucket + "/" + ref.name
+	case kindKMS:
+		return "KMS ciphertext"
+	default:
+		return "SSM parameter " + ref.qualifiedName()
+	}
+}
+
+// qualifiedName returns the parameter name including any version or label
+// selector, in the form accepted by GetParameter (e.g. "/app/key:3").
+func (ref reference) qualifiedName() string {
+	if ref.selector == "" {
+		return ref.name
+	}
+	return ref.name + selectorSeparator + ref.selector
+}
+
+// extractJSONKey parses value as a JSON object and returns the given
+// top-level key. String values are returned as-is; other values are
+// returned in their JSON encoding.
+func extractJSONKey(value, key string) (string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &obj); err != nil {
+		return "", fmt.Errorf("value is not a JSON object: %w", err)
+	}
+
+	raw, ok := obj[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", errJSONKeyNotFound, key)
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParseReference_JSONKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantName    string
+		wantJSONKey string
+		wantOK      bool
+	}{
+		{
+			name:     "no key",
+			value:    "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/creds",
+			wantName: "/my-app/creds",
+			wantOK:   true,
+		},
+		{
+			name:        "with key",
+			value:       "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/creds#client_secret",
+			wantName:    "/my-app/creds",
+			wantJSONKey: "client_secret",
+			wantOK:      true,
+		},
+		{
+			name:   "key without name",
+			value:  "arn:aws:ssm:us-east-1:123456789012:parameter/#client_secret",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, ok := parseReference(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("parseReference(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ref.name != tt.wantName {
+				t.Errorf("name = %q, want %q", ref.name, tt.wantName)
+			}
+			if ref.jsonKey != tt.wantJSONKey {
+				t.Errorf("jsonKey = %q, want %q", ref.jsonKey, tt.wantJSONKey)
+			}
+		})
+	}
+}
+
+func TestResolveValue_JSONKey(t *testing.T) {
+	var calls int
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			calls++
+			if *params.Name != "/my-app/creds" {
+				t.Errorf("GetParameter called with name = %q, want %q", *params.Name, "/my-app/creds")
+			}
+			value := `{"client_id":"Iv1.abc","client_secret":"s3cret","app_id":12345}`
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	arn := "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/creds"
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "client_secret", want: "s3cret"},
+		{key: "app_id", want: "12345"},
+		{key: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := resolver.ResolveValue(context.Background(), arn+"#"+tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValue_JSONKeyNotObject(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			value := "plain-secret"
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	arn := "arn:aws:ssm:us-east-1:123456789012:parameter/my-app/secret#key"
+	if _, err := resolver.ResolveValue(context.Background(), arn); err == nil {
+		t.Error("ResolveValue() expected error for non-JSON value, got nil")
+	}
+}
+
+func TestIsReference(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"arn:aws:ssm:us-east-1:123456789012:parameter/my-app/secret", true},
+		{"ssm:///my-app/secret", true},
+		{"ssm://my-app/secret", true},
+		{"ssm-secure:///my-app/secret#key", true},
+		{"ssm://", false},
+		{"ssm:///", false},
+		{"s3://bucket/key", true},
+		{"s3://bucket", false},
+		{"file:///run/secrets/key", true},
+		{"file://", false},
+		{"kms://AQICAHh=", true},
+		{"kms://", false},
+		{"my-secret-value", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := IsReference(tt.value); got != tt.want {
+				t.Errorf("IsReference(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValue_Shorthand(t *testing.T) {
+	var capturedName string
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			capturedName = *params.Name
+			value := `{"client_secret":"s3cret"}`
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	tests := []struct {
+		value    string
+		wantName string
+		want     string
+	}{
+		{"ssm:///my-app/creds", "/my-app/creds", `{"client_secret":"s3cret"}`},
+		{"ssm://my-app/creds", "/my-app/creds", `{"client_secret":"s3cret"}`},
+		{"ssm-secure:///my-app/creds#client_secret", "/my-app/creds", "s3cret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := resolver.ResolveValue(context.Background(), tt.value)
+			if err != nil {
+				t.Fatalf("ResolveValue() error = %v", err)
+			}
+			if capturedName != tt.wantName {
+				t.Errorf("GetParameter called with name = %q, want %q", capturedName, tt.wantName)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValue_VersionAndLabel(t *testing.T) {
+	var capturedName string
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			capturedName = *params.Name
+			value := `{"key":"v"}`
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	}, WithCacheTTL(time.Minute))
+
+	tests := []struct {
+		value    string
+		wantName string
+	}{
+		{"arn:aws:ssm:us-east-1:123456789012:parameter/app/key:3", "/app/key:3"},
+		{"arn:aws:ssm:us-east-1:123456789012:parameter/app/key:prod#key", "/app/key:prod"},
+		{"ssm:///app/key:prod", "/app/key:prod"},
+		{"ssm:///app/key", "/app/key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			capturedName = ""
+			if _, err := resolver.ResolveValue(context.Background(), tt.value); err != nil {
+				t.Fatalf("ResolveValue() error = %v", err)
+			}
+			if capturedName != tt.wantName {
+				t.Errorf("GetParameter called with name = %q, want %q", capturedName, tt.wantName)
+			}
+		})
+	}
+
+	name, ok := ExtractParameterName("arn:aws:ssm:us-east-1:123456789012:parameter/app/key:3")
+	if !ok || name != "/app/key" {
+		t.Errorf("ExtractParameterName() = %q, %v, want %q without selector", name, ok, "/app/key")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ClientFactory creates an SSM client for the given region.
+type ClientFactory func(region string) Client
+
+// WithClientFactory sets the factory used to create clients for regions
+// other than the default region. Resolvers created with New use a factory
+// derived from the loaded AWS configuration; resolvers created with
+// NewWithClient use the custom client for every region unless a factory is
+// provided.
+func WithClientFactory(factory ClientFactory) Option {
+	return func(r *Resolver) {
+		r.regional.factory = factory
+	}
+}
+
+// WithDefaultRegion sets the region served by the resolver's default
+// client. ARNs in other regions are resolved with a regional client (see
+// WithClientFactory). Resolvers created with New default to the region of
+// the loaded AWS configuration.
+func WithDefaultRegion(region string) Option {
+	return func(r *Resolver) {
+		r.regional.defaultRegion = region
+	}
+}
+
+// regionalClients creates and reuses per-region SSM clients.
+type regionalClients struct {
+	defaultRegion string
+	factory       ClientFactory
+
+	mu      sync.Mutex
+	clients map[string]Client
+}
+
+// configClientFactory returns a factory creating SSM clients from cfg with
+// the region overridden and opts applied.
+func configClientFactory(cfg aws.Config, opts ...func(*ssm.Options)) ClientFactory {
+	return func(region string) Client {
+		return ssm.NewFromConfig(cfg, append(opts, func(o *ssm.Options) {
+			o.Region = region
+		})...)
+	}
+}
+
+// WithEndpoint sets a custom SSM endpoint URL, e.g. a LocalStack instance
+// in integration tests or local development, without changing the global
+// AWS configuration. It takes precedence over AWS_ENDPOINT_URL_SSM and
+// only affects resolvers created with New.
+func WithEndpoint(url string) Option {
+	return func(r *Resolver) {
+		r.endpoint = url
+	}
+}
+
+// endpointOptions returns the SSM client options applying endpoint, or
+// AWS_ENDPOINT_URL_SSM if endpoint is empty.
+func endpointOptions(endpoint string) []func(*ssm.Options) {
+	if endpoint == "" {
+		endpoint = os.Getenv(EnvEndpointURLSSM)
+	}
+	if endpoint == "" {
+		return nil
+	}
+	return []func(*ssm.Options){func(o *ssm.Options) {
+		o.BaseEndpoint = &endpoint
+	}}
+}
+
+// clientFor returns the client to use for region. The default client is
+// used when region is empty, matches the default region, or no factory is
+// configured.
+func (r *Resolver) clientFor(region string) Client {
+	rc := &r.regional
+	if region == "" || region == rc.defaultRegion || rc.factory == nil {
+		return r.client
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if c, ok := rc.clients[region]; ok {
+		return c
+	}
+	if rc.clients == nil {
+		rc.clients = make(map[string]Client)
+	}
+	c := rc.factory(region)
+	rc.clients[region] = c
+	return c
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// regionClient returns a mock client whose values name the region it serves.
+func regionClient(region string) *mockSSMClient {
+	return &mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			value := region + ":" + *params.Name
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	}
+}
+
+func TestResolveValue_CrossRegion(t *testing.T) {
+	created := map[string]int{}
+	resolver := NewWithClient(regionClient("us-east-1"),
+		WithDefaultRegion("us-east-1"),
+		WithClientFactory(func(region string) Client {
+			created[region]++
+			return regionClient(region)
+		}),
+	)
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"arn:aws:ssm:us-east-1:123456789012:parameter/app/secret", "us-east-1:/app/secret"},
+		{"arn:aws:ssm:us-west-2:123456789012:parameter/app/secret", "us-west-2:/app/secret"},
+		{"arn:aws:ssm:us-west-2:123456789012:parameter/app/other", "us-west-2:/app/other"},
+		{"ssm:///app/secret", "us-east-1:/app/secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := resolver.ResolveValue(context.Background(), tt.value)
+			if err != nil {
+				t.Fatalf("ResolveValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if created["us-west-2"] != 1 {
+		t.Errorf("us-west-2 client created %d times, want 1", created["us-west-2"])
+	}
+	if created["us-east-1"] != 0 {
+		t.Errorf("default region client created %d times via factory, want 0", created["us-east-1"])
+	}
+}
+
+func TestResolveValue_CrossRegionWithoutFactory(t *testing.T) {
+	resolver := NewWithClient(regionClient("default"))
+
+	got, err := resolver.ResolveValue(context.Background(), "arn:aws:ssm:eu-west-1:123456789012:parameter/app/secret")
+	if err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if got != "default:/app/secret" {
+		t.Errorf("ResolveValue() = %q, want the custom client to serve every region", got)
+	}
+}
+
+func TestNew_Endpoint(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv(EnvEndpointURLSSM, "http://env:4566")
+
+	for endpoint, want := range map[string]string{"": "http://env:4566", "http://localstack:4566": "http://localstack:4566"} {
+		r, err := New(context.Background(), WithEndpoint(endpoint))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		for _, c := range []Client{r.client, r.clientFor("eu-west-1")} {
+			got := c.(*ssm.Client).Options().BaseEndpoint
+			if got == nil || *got != want {
+				t.Errorf("BaseEndpoint = %v, want %q", got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/chainguard-dev/clog"
+)
+
+// origins maps environment variables to the references they were resolved
+// from.
+type origins struct {
+	mu   sync.Mutex
+	refs map[string]string
+}
+
+func (o *origins) record(key, ref string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.refs == nil {
+		o.refs = make(map[string]string)
+	}
+	o.refs[key] = ref
+}
+
+func (o *origins) snapshot() map[string]string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make(map[string]string, len(o.refs))
+	for k, v := range o.refs {
+		out[k] = v
+	}
+	return out
+}
+
+// ReResolveChanged re-fetches the references behind every variable set by
+// an earlier ResolveEnvironment call on this Resolver and updates the
+// variables whose values changed, e.g. after a secret rotation. Once
+// ResolveEnvironment has run, the environment holds resolved values rather
+// than references, so calling it again would not pick up rotated values.
+//
+// The cache is flushed first so every value is fetched fresh. As with
+// ResolveEnvironment, every reference is resolved before any variable is
+// updated. It returns the sorted names of the variables that changed.
+func (r *Resolver) ReResolveChanged(ctx context.Context) ([]string, error) {
+	refs := r.origins.snapshot()
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	r.Flush()
+	resolved, err := r.resolveReferences(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, value := range resolved {
+		if os.Getenv(key) == value {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		changed = append(changed, key)
+	}
+	sort.Strings(changed)
+
+	if len(changed) > 0 {
+		clog.FromContext(ctx).Infof("[ssmresolver] re-resolved %d changed variables: %v", len(changed), changed)
+	}
+	return changed, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestReResolveChanged(t *testing.T) {
+	var version atomic.Int32
+	var fail atomic.Bool
+	client := &mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			if fail.Load() {
+				return nil, errors.New("throttled")
+			}
+			value := "static"
+			if *params.Name == "/app/secret" {
+				value = "secret-v" + strconv.Itoa(int(version.Load()))
+			}
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	}
+	t.Setenv("TEST_RERESOLVE_SECRET", "ssm:///app/secret")
+	t.Setenv("TEST_RERESOLVE_STATIC", "ssm:///app/static")
+
+	resolver := NewWithClient(client, WithCacheTTL(time.Hour))
+	ctx := context.Background()
+
+	if changed, err := resolver.ReResolveChanged(ctx); err != nil || changed != nil {
+		t.Errorf("ReResolveChanged() before ResolveEnvironment = %v, %v, want nil", changed, err)
+	}
+
+	if err := resolver.ResolveEnvironment(ctx); err != nil {
+		t.Fatalf("ResolveEnvironment() error = %v", err)
+	}
+	if got := os.Getenv("TEST_RERESOLVE_SECRET"); got != "secret-v0" {
+		t.Fatalf("TEST_RERESOLVE_SECRET = %q, want %q", got, "secret-v0")
+	}
+
+	version.Store(1)
+	changed, err := resolver.ReResolveChanged(ctx)
+	if err != nil {
+		t.Fatalf("ReResolveChanged() error = %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "TEST_RERESOLVE_SECRET" {
+		t.Errorf("ReResolveChanged() = %v, want [TEST_RERESOLVE_SECRET]", changed)
+	}
+	if got := os.Getenv("TEST_RERESOLVE_SECRET"); got != "secret-v1" {
+		t.Errorf("TEST_RERESOLVE_SECRET = %q, want %q despite caching", got, "secret-v1")
+	}
+
+	version.Store(2)
+	fail.Store(true)
+	if _, err := resolver.ReResolveChanged(ctx); err == nil {
+		t.Error("ReResolveChanged() expected error, got nil")
+	}
+	if got := os.Getenv("TEST_RERESOLVE_SECRET"); got != "secret-v1" {
+		t.Errorf("TEST_RERESOLVE_SECRET = %q, want unchanged after failure", got)
+	}
+}
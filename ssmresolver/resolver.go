@@ -2,25 +2,36 @@
 // SPDX-License-Identifier: MIT
 
 // Package ssmresolver provides utilities for resolving AWS SSM Parameter Store
-// ARNs in environment variables.
+// ARNs, file paths, S3 objects, and KMS ciphertexts referenced by environment
+// variables.
 package ssmresolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/chainguard-dev/clog"
 )
 
 const (
 	EnvMaxRetries    = "CONFIG_WAIT_MAX_RETRIES"
 	EnvRetryInterval = "CONFIG_WAIT_RETRY_INTERVAL"
+
+	// EnvEndpointURLSSM overrides the SSM endpoint (e.g. LocalStack) when
+	// WithEndpoint is not set.
+	EnvEndpointURLSSM = "AWS_ENDPOINT_URL_SSM"
 )
 
 const (
@@ -28,7 +39,7 @@ const (
 	DefaultRetryInterval = 1 * time.Second
 )
 
-var ssmARNPattern = regexp.MustCompile(`^arn:aws:ssm:[^:]+:[^:]+:parameter/(.+)$`)
+var ssmARNPattern = regexp.MustCompile(`^arn:aws:ssm:([^:]+):[^:]+:parameter/(.+)$`)
 
 // Client defines the interface for SSM operations.
 type Client interface {
@@ -37,23 +48,102 @@ type Client interface {
 
 // Resolver handles SSM parameter resolution.
 type Resolver struct {
-	client Client
+	client     Client
+	s3         S3Client
+	kms        KMSClient
+	regional   regionalClients
+	cache      *cache
+	assumeRole *AssumeRoleConfig
+	defaults   map[string]string
+	endpoint   string
+
+	// files and s3Objects enable file:// and s3:// references.
+	files     bool
+	s3Objects bool
+
+	// concurrency bounds parallel fetches in resolveAll.
+	concurrency int
+
+	// origins remembers the references behind variables set by
+	// ResolveEnvironment, for ReResolveChanged.
+	origins origins
+}
+
+// Option is a functional option for configuring a Resolver.
+type Option func(*Resolver)
+
+// WithCacheTTL caches resolved parameter values in process for ttl, so
+// repeated ResolveValue and ResolveEnvironment calls (e.g. Lambda retries
+// or runtime reloads) do not re-fetch unchanged values from SSM. Use Flush
+// to force a refresh. A non-positive ttl disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(r *Resolver) {
+		if ttl > 0 {
+			r.cache = newCache(ttl)
+		} else {
+			r.cache = nil
+		}
+	}
 }
 
-// New creates a Resolver with the default AWS configuration.
-func New(ctx context.Context) (*Resolver, error) {
+// New creates a Resolver with the default AWS configuration. ARNs naming a
+// region other than the configured one are resolved with a client for that
+// region. The SSM endpoint can be overridden with WithEndpoint or
+// AWS_ENDPOINT_URL_SSM.
+func New(ctx context.Context, opts ...Option) (*Resolver, error) {
+	r := newResolver(opts)
+
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
-	return &Resolver{
-		client: ssm.NewFromConfig(cfg),
-	}, nil
+	if r.assumeRole != nil {
+		if r.assumeRole.RoleARN == "" {
+			return nil, errors.New("assume role: RoleARN is required")
+		}
+		cfg.Credentials = assumeRoleCredentials(sts.NewFromConfig(cfg), *r.assumeRole)
+	}
+
+	ssmOpts := endpointOptions(r.endpoint)
+	r.client = ssm.NewFromConfig(cfg, ssmOpts...)
+	if r.s3 == nil {
+		r.s3 = s3.NewFromConfig(cfg)
+	}
+	if r.kms == nil {
+		r.kms = kms.NewFromConfig(cfg)
+	}
+	if r.regional.defaultRegion == "" {
+		r.regional.defaultRegion = cfg.Region
+	}
+	if r.regional.factory == nil {
+		r.regional.factory = configClientFactory(cfg, ssmOpts...)
+	}
+	return r, nil
 }
 
-// NewWithClient creates a Resolver with a custom SSM client.
-func NewWithClient(client Client) *Resolver {
-	return &Resolver{client: client}
+// NewWithClient creates a Resolver with a custom SSM client. Use
+// WithS3Client and WithKMSClient to also resolve s3:// and kms://
+// references; s3:// references also need WithS3References.
+func NewWithClient(client Client, opts ...Option) *Resolver {
+	r := newResolver(opts)
+	r.client = client
+	return r
+}
+
+func newResolver(opts []Option) *Resolver {
+	r := &Resolver{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Flush discards all cached parameter values so the next resolution
+// fetches fresh values from SSM. It is a no-op when caching is disabled.
+func (r *Resolver) Flush() {
+	if r.cache != nil {
+		r.cache.flush()
+	}
 }
 
 // IsSSMARN checks if the given value is an SSM Parameter Store ARN.
@@ -61,31 +151,86 @@ func IsSSMARN(value string) bool {
 	return ssmARNPattern.MatchString(value)
 }
 
-// ExtractParameterName extracts the parameter name from an SSM ARN.
+// ExtractParameterName extracts the parameter name from an SSM ARN. Any
+// "#key" JSON extraction suffix is not part of the returned name.
 func ExtractParameterName(arn string) (string, bool) {
-	matches := ssmARNPattern.FindStringSubmatch(arn)
-	if len(matches) != 2 {
+	ref, ok := parseReference(arn)
+	if !ok || ref.kind != kindSSM {
 		return "", false
 	}
-	paramName := matches[1]
-	if !strings.HasPrefix(paramName, "/") {
-		paramName = "/" + paramName
-	}
-	return paramName, true
+	return ref.name, true
 }
 
-// ResolveValue resolves an SSM ARN to its value, or returns it unchanged.
+// ResolveValue resolves a reference (an SSM ARN, ssm:// shorthand, file://
+// path, s3:// object, or kms:// ciphertext, see IsReference) to its value, or returns the
+// value unchanged. File and S3 references are returned unchanged unless
+// enabled with WithFileReferences or WithS3References.
+//
+// An ARN may end in "#key" (e.g. "arn:aws:ssm:...:parameter/creds#client_secret")
+// to parse the parameter value as a JSON object and return only that key,
+// so a single SecureString JSON blob can feed multiple environment variables.
+// A "|default=value" suffix supplies a fallback returned when the parameter
+// or JSON key does not exist.
+// A ":version" or ":label" suffix on the name (e.g. "parameter/app/key:3" or
+// "parameter/app/key:prod") pins the parameter instead of reading the
+// latest version.
 func (r *Resolver) ResolveValue(ctx context.Context, value string) (string, error) {
-	if !IsSSMARN(value) {
+	ref, ok := r.parse(value)
+	if !ok {
+		if IsSSMARN(value) {
+			return "", fmt.Errorf("invalid SSM ARN format: %s", value)
+		}
 		return value, nil
 	}
 
-	paramName, ok := ExtractParameterName(value)
-	if !ok {
-		return "", fmt.Errorf("invalid SSM ARN format: %s", value)
+	resolved, err := r.resolveReference(ctx, ref)
+	if err != nil {
+		return fallback(ctx, ref.String(), ref.fallback, err)
+	}
+	return resolved, nil
+}
+
+// resolveReference fetches the referenced value and applies any JSON key
+// extraction.
+func (r *Resolver) resolveReference(ctx context.Context, ref reference) (string, error) {
+	var resolved string
+	var err error
+	switch ref.kind {
+	case kindFile:
+		resolved, err = readFile(ref)
+	case kindS3:
+		resolved, err = r.getObject(ctx, ref)
+	case kindKMS:
+		resolved, err = r.decrypt(ctx, ref)
+	default:
+		resolved, err = r.getParameter(ctx, ref)
+	}
+	if err != nil {
+		return "", err
+	}
+	if ref.jsonKey == "" {
+		return resolved, nil
 	}
 
-	resp, err := r.client.GetParameter(ctx, &ssm.GetParameterInput{
+	extracted, err := extractJSONKey(resolved, ref.jsonKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract from %s: %w", ref, err)
+	}
+	return extracted, nil
+}
+
+// getParameter fetches a decrypted parameter value from the reference's
+// region, consulting the cache when enabled.
+func (r *Resolver) getParameter(ctx context.Context, ref reference) (string, error) {
+	paramName := ref.qualifiedName()
+	cacheKey := ref.region + paramName
+	if r.cache != nil {
+		if v, ok := r.cache.get(cacheKey); ok {
+			return v, nil
+		}
+	}
+
+	resp, err := r.clientFor(ref.region).GetParameter(ctx, &ssm.GetParameterInput{
 		Name:           &paramName,
 		WithDecryption: ptr(true),
 	})
@@ -96,30 +241,109 @@ func (r *Resolver) ResolveValue(ctx context.Context, value string) (string, erro
 	if resp.Parameter == nil || resp.Parameter.Value == nil {
 		return "", fmt.Errorf("SSM parameter %s has no value", paramName)
 	}
+	if *resp.Parameter.Value == PlaceholderValue {
+		return "", fmt.Errorf("failed to get SSM parameter %s: %w", paramName, &types.ParameterNotFound{
+			Message: ptr("parameter holds a placeholder; the GitHub App is not registered yet"),
+		})
+	}
 
+	if r.cache != nil {
+		r.cache.set(cacheKey, *resp.Parameter.Value)
+	}
 	return *resp.Parameter.Value, nil
 }
 
-// ResolveEnvironment resolves any SSM ARN values in environment variables.
+// ResolveEnvironment resolves any reference values in environment
+// variables. Every reference is resolved before any variable is updated,
+// so a failed resolution leaves the environment unchanged. The references
+// are remembered so ReResolveChanged can refresh the variables later.
 func (r *Resolver) ResolveEnvironment(ctx context.Context) error {
-	for _, env := range os.Environ() {
-		parts := strings.SplitN(env, "=", 2)
-		if len(parts) != 2 {
+	env := environMap()
+	resolved, err := r.resolveReferences(ctx, env)
+	if err != nil {
+		return err
+	}
+	for key, value := range resolved {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		r.origins.record(key, env[key])
+	}
+	return nil
+}
+
+// ResolveMap returns a copy of values with every reference resolved.
+// Unlike ResolveEnvironment it does not modify the process environment,
+// which suits tests and multi-tenant processes.
+func (r *Resolver) ResolveMap(ctx context.Context, values map[string]string) (map[string]string, error) {
+	resolved, err := r.resolveReferences(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(values))
+	for key, value := range values {
+		out[key] = value
+	}
+	for key, value := range resolved {
+		out[key] = value
+	}
+	return out, nil
+}
+
+// ResolveEnvironTo returns the process environment as a map with every
+// reference resolved, without calling os.Setenv.
+func (r *Resolver) ResolveEnvironTo(ctx context.Context) (map[string]string, error) {
+	return r.ResolveMap(ctx, environMap())
+}
+
+// resolveReferences resolves the entries of values that are references and
+// returns only those entries. Distinct references are fetched concurrently
+// (see WithConcurrency), and a reference shared by several keys is fetched
+// once.
+func (r *Resolver) resolveReferences(ctx context.Context, values map[string]string) (map[string]string, error) {
+	keys := make([]string, 0, len(values))
+	var refs []string
+	seen := make(map[string]bool)
+	for key, value := range values {
+		if _, ok := r.parse(value); !ok && !IsSSMARN(value) {
 			continue
 		}
-		key, value := parts[0], parts[1]
+		keys = append(keys, key)
+		if !seen[value] {
+			seen[value] = true
+			refs = append(refs, value)
+		}
+	}
+	sort.Strings(keys)
 
-		if IsSSMARN(value) {
-			resolved, err := r.ResolveValue(ctx, value)
-			if err != nil {
-				return fmt.Errorf("failed to resolve %s: %w", key, err)
-			}
-			if err := os.Setenv(key, resolved); err != nil {
-				return fmt.Errorf("failed to set %s: %w", key, err)
+	results := r.resolveAll(ctx, refs)
+	resolved := make(map[string]string, len(keys))
+	for _, key := range keys {
+		res := results[values[key]]
+		v, err := res.value, res.err
+		if err != nil {
+			def, ok := r.defaults[key]
+			if v, err = fallback(ctx, key, defaultPtr(def, ok), err); err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", key, err)
 			}
 		}
+		resolved[key] = v
 	}
-	return nil
+	return resolved, nil
+}
+
+// environMap returns the process environment as a map.
+func environMap() map[string]string {
+	env := os.Environ()
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		m[key] = value
+	}
+	return m
 }
 
 // ResolveEnvironmentWithDefaults creates a resolver and resolves all env vars.
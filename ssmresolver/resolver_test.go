@@ -6,6 +6,7 @@ package ssmresolver
 import (
 	"context"
 	"errors"
+	"os"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
@@ -298,3 +299,75 @@ func TestNewRetryConfigFromEnv_Defaults(t *testing.T) {
 		t.Errorf("RetryInterval = %v, want %v", cfg.RetryInterval, DefaultRetryInterval)
 	}
 }
+
+func TestResolveMap(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			value := "resolved:" + *params.Name
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	in := map[string]string{
+		"GITHUB_APP_ID": "ssm:///my-app/app-id",
+		"PLAIN":         "unchanged",
+	}
+	got, err := resolver.ResolveMap(context.Background(), in)
+	if err != nil {
+		t.Fatalf("ResolveMap() error = %v", err)
+	}
+
+	if got["GITHUB_APP_ID"] != "resolved:/my-app/app-id" {
+		t.Errorf("GITHUB_APP_ID = %q, want resolved value", got["GITHUB_APP_ID"])
+	}
+	if got["PLAIN"] != "unchanged" {
+		t.Errorf("PLAIN = %q, want %q", got["PLAIN"], "unchanged")
+	}
+	if in["GITHUB_APP_ID"] != "ssm:///my-app/app-id" {
+		t.Error("ResolveMap() should not modify its input")
+	}
+}
+
+func TestResolveEnvironTo_DoesNotMutateEnvironment(t *testing.T) {
+	t.Setenv("SSMRESOLVER_TEST_SECRET", "ssm:///my-app/secret")
+
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			value := "s3cret"
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	got, err := resolver.ResolveEnvironTo(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveEnvironTo() error = %v", err)
+	}
+	if got["SSMRESOLVER_TEST_SECRET"] != "s3cret" {
+		t.Errorf("SSMRESOLVER_TEST_SECRET = %q, want %q", got["SSMRESOLVER_TEST_SECRET"], "s3cret")
+	}
+	if v := os.Getenv("SSMRESOLVER_TEST_SECRET"); v != "ssm:///my-app/secret" {
+		t.Errorf("environment modified: SSMRESOLVER_TEST_SECRET = %q", v)
+	}
+}
+
+func TestResolveEnvironment_FailureLeavesEnvironmentUnchanged(t *testing.T) {
+	t.Setenv("SSMRESOLVER_TEST_A", "ssm:///my-app/a")
+	t.Setenv("SSMRESOLVER_TEST_B", "ssm:///my-app/b")
+
+	resolver := NewWithClient(&mockSSMClient{
+		getParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			if *params.Name == "/my-app/b" {
+				return nil, errors.New("access denied")
+			}
+			value := "resolved"
+			return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}, nil
+		},
+	})
+
+	if err := resolver.ResolveEnvironment(context.Background()); err == nil {
+		t.Fatal("ResolveEnvironment() expected error, got nil")
+	}
+	if v := os.Getenv("SSMRESOLVER_TEST_A"); v != "ssm:///my-app/a" {
+		t.Errorf("SSMRESOLVER_TEST_A = %q, want unchanged reference", v)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Client defines the interface for S3 operations used to resolve s3://
+// references.
+type S3Client interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// WithFileReferences resolves file:// references. They are left unchanged
+// by default, since unrelated variables may hold file URLs.
+func WithFileReferences() Option {
+	return func(r *Resolver) {
+		r.files = true
+	}
+}
+
+// WithS3References resolves s3:// references. They are left unchanged by
+// default, since unrelated variables often hold S3 URLs (e.g.
+// ARTIFACT_BUCKET=s3://my-bucket/artifacts) that would otherwise fail the
+// whole resolution.
+func WithS3References() Option {
+	return func(r *Resolver) {
+		r.s3Objects = true
+	}
+}
+
+// WithS3Client sets the client used to resolve s3:// references (see
+// WithS3References). New creates one from the default AWS configuration if
+// none is set.
+func WithS3Client(client S3Client) Option {
+	return func(r *Resolver) {
+		r.s3 = client
+	}
+}
+
+// readFile reads the file named by a file:// reference. Files are read on
+// every resolution and are not cached, so rotated mounted secrets are
+// picked up by the next reload.
+func readFile(ref reference) (string, error) {
+	data, err := os.ReadFile(ref.name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", ref.name, err)
+	}
+	return trimValue(data), nil
+}
+
+// getObject fetches the object named by an s3:// reference, consulting the
+// cache when enabled.
+func (r *Resolver) getObject(ctx context.Context, ref reference) (string, error) {
+	if r.s3 == nil {
+		return "", errors.New("no S3 client configured for " + ref.String())
+	}
+
+	cacheKey := SchemeS3 + ref.bucket + "/" + ref.name
+	if r.cache != nil {
+		if v, ok := r.cache.get(cacheKey); ok {
+			return v, nil
+		}
+	}
+
+	resp, err := r.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &ref.bucket,
+		Key:    &ref.name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", ref, err)
+	}
+
+	value := trimValue(data)
+	if r.cache != nil {
+		r.cache.set(cacheKey, value)
+	}
+	return value, nil
+}
+
+// trimValue converts file or object contents to a value, dropping trailing
+// newlines that editors and "echo" commonly append.
+func trimValue(data []byte) string {
+	return strings.TrimRight(string(data), "\r\n")
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package ssmresolver
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type mockS3Client struct {
+	objects map[string]string
+	calls   int
+}
+
+func (m *mockS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	m.calls++
+	body, ok := m.objects[*params.Bucket+"/"+*params.Key]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestResolveValue_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte(`{"client_id":"abc"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	resolver := NewWithClient(&mockSSMClient{}, WithFileReferences())
+	ctx := context.Background()
+
+	got, err := resolver.ResolveValue(ctx, "file://"+path)
+	if err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if want := `{"client_id":"abc"}`; got != want {
+		t.Errorf("ResolveValue() = %q, want %q", got, want)
+	}
+
+	got, err = resolver.ResolveValue(ctx, "file://"+path+"#client_id")
+	if err != nil {
+		t.Fatalf("ResolveValue() error = %v", err)
+	}
+	if got != "abc" {
+		t.Errorf("ResolveValue() = %q, want %q", got, "abc")
+	}
+
+	missing := "file://" + filepath.Join(t.TempDir(), "missing")
+	if _, err := resolver.ResolveValue(ctx, missing); !IsNotFound(err) {
+		t.Errorf("ResolveValue() error = %v, want not found", err)
+	}
+	got, err = resolver.ResolveValue(ctx, missing+"|default=none")
+	if err != nil || got != "none" {
+		t.Errorf("ResolveValue() = %q, %v, want default", got, err)
+	}
+}
+
+func TestResolveValue_S3(t *testing.T) {
+	client := &mockS3Client{objects: map[string]string{
+		"bootstrap/app/config.json": `{"webhook_secret":"s3cr3t"}`,
+	}}
+	resolver := NewWithClient(&mockSSMClient{}, WithS3Client(client), WithS3References(), WithCacheTTL(time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		got, err := resolver.ResolveValue(ctx, "s3://bootstrap/app/config.json#webhook_secret")
+		if err != nil {
+			t.Fatalf("ResolveValue() error = %v", err)
+		}
+		if got != "s3cr3t" {
+			t.Errorf("ResolveValue() = %q, want %q", got, "s3cr3t")
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("GetObject called %d times, want 1 with caching", client.calls)
+	}
+
+	if _, err := resolver.ResolveValue(ctx, "s3://bootstrap/missing"); !IsNotFound(err) {
+		t.Errorf("ResolveValue() error = %v, want not found", err)
+	}
+}
+
+func TestResolveValue_S3WithoutClient(t *testing.T) {
+	resolver := NewWithClient(&mockSSMClient{}, WithS3References())
+	if _, err := resolver.ResolveValue(context.Background(), "s3://bucket/key"); err == nil {
+		t.Error("ResolveValue() expected error without an S3 client, got nil")
+	}
+}
+
+func TestResolveMap_MixedSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, []byte("PEM"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	resolver := NewWithClient(
+		notFoundClient(),
+		WithS3Client(&mockS3Client{objects: map[string]string{"b/id": "123"}}),
+		WithFileReferences(),
+		WithS3References(),
+	)
+
+	got, err := resolver.ResolveMap(context.Background(), map[string]string{
+		"GITHUB_APP_ID":          "s3://b/id",
+		"GITHUB_APP_PRIVATE_KEY": "file://" + path,
+		"LOG_LEVEL":              "info",
+	})
+	if err != nil {
+		t.Fatalf("ResolveMap() error = %v", err)
+	}
+	if got["GITHUB_APP_ID"] != "123" || got["GITHUB_APP_PRIVATE_KEY"] != "PEM" || got["LOG_LEVEL"] != "info" {
+		t.Errorf("ResolveMap() = %v", got)
+	}
+}
+
+func TestResolveMap_FileAndS3OptIn(t *testing.T) {
+	client := &mockS3Client{}
+	resolver := NewWithClient(notFoundClient(), WithS3Client(client))
+
+	values := map[string]string{
+		"ARTIFACT_BUCKET": "s3://my-bucket/artifacts",
+		"CONFIG_PATH":     "file:///etc/app/config.json",
+	}
+	got, err := resolver.ResolveMap(context.Background(), values)
+	if err != nil {
+		t.Fatalf("ResolveMap() error = %v", err)
+	}
+	for key, want := range values {
+		if got[key] != want {
+			t.Errorf("%s = %q, want it unchanged", key, got[key])
+		}
+	}
+	if client.calls != 0 {
+		t.Errorf("GetObject called %d times, want 0 without WithS3References", client.calls)
+	}
+}
+
+func TestResolveValue_EscapedHash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app#1.json"), []byte(`{"id":"42"}`), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	client := &mockS3Client{objects: map[string]string{"b/keys/app#1": "PEM"}}
+	resolver := NewWithClient(&mockSSMClient{}, WithS3Client(client), WithFileReferences(), WithS3References())
+	ctx := context.Background()
+
+	got, err := resolver.ResolveValue(ctx, "file://"+dir+"/app%231.json#id")
+	if err != nil || got != "42" {
+		t.Errorf("ResolveValue(file) = %q, %v, want %q", got, err, "42")
+	}
+	got, err = resolver.ResolveValue(ctx, "s3://b/keys/app%231")
+	if err != nil || got != "PEM" {
+		t.Errorf("ResolveValue(s3) = %q, %v, want %q", got, err, "PEM")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultDedupSize is how many delivery IDs a MemoryDedup remembers.
+const DefaultDedupSize = 10000
+
+// DedupStore remembers which deliveries have been processed, keyed by
+// delivery ID (the X-GitHub-Delivery GUID, which GitHub keeps across
+// redeliveries). Implementations backed by shared storage let several
+// replicas, and Recoverer runs after a restart, agree on what was seen.
+type DedupStore interface {
+	// Seen reports whether the delivery has been processed.
+	Seen(ctx context.Context, id string) (bool, error)
+	// Mark records the delivery as processed.
+	Mark(ctx context.Context, id string) error
+}
+
+// MemoryDedup is an in-process DedupStore that remembers the most recent
+// delivery IDs, forgetting the oldest once full. It does not survive
+// restarts.
+type MemoryDedup struct {
+	mu    sync.Mutex
+	size  int
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+// NewMemoryDedup creates a MemoryDedup holding up to size IDs, or
+// DefaultDedupSize if size is not positive.
+func NewMemoryDedup(size int) *MemoryDedup {
+	if size <= 0 {
+		size = DefaultDedupSize
+	}
+	return &MemoryDedup{
+		size: size,
+		seen: make(map[string]struct{}, size),
+	}
+}
+
+// Seen implements DedupStore.
+func (m *MemoryDedup) Seen(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.seen[id]
+	return ok, nil
+}
+
+// Mark implements DedupStore.
+func (m *MemoryDedup) Mark(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.seen[id]; ok {
+		return nil
+	}
+	if len(m.order) < m.size {
+		m.order = append(m.order, id)
+	} else {
+		delete(m.seen, m.order[m.next])
+		m.order[m.next] = id
+		m.next = (m.next + 1) % m.size
+	}
+	m.seen[id] = struct{}{}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMemoryDedup_EvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryDedup(2)
+	for _, id := range []string{"a", "b", "a", "c"} {
+		if err := m.Mark(ctx, id); err != nil {
+			t.Fatalf("Mark(%q) error = %v", id, err)
+		}
+	}
+
+	for id, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		if got, _ := m.Seen(ctx, id); got != want {
+			t.Errorf("Seen(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestHandler_SkipsDuplicates(t *testing.T) {
+	dedup := NewMemoryDedup(0)
+	calls := 0
+	h := NewHandler(func(ctx context.Context, d *Delivery) error {
+		calls++
+		return nil
+	}, WithSecret(testSecret), WithDedup(dedup))
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newDeliveryRequest(`{}`, testSecret))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("DeliveryFunc calls = %d, want 1", calls)
+	}
+	if seen, _ := dedup.Seen(context.Background(), "d-1"); !seen {
+		t.Error("processed delivery should be recorded")
+	}
+}
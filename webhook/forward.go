@@ -0,0 +1,303 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v82/github"
+)
+
+// EnvWebhookProxyURL names a smee.io channel to forward webhooks from in
+// development, matching the variable other GitHub App frameworks use.
+const EnvWebhookProxyURL = "WEBHOOK_PROXY_URL"
+
+// DefaultPollInterval is how often the deliveries API is polled for new
+// deliveries, and how long to wait before reconnecting to a smee channel.
+const DefaultPollInterval = 5 * time.Second
+
+// Forwarder replays webhook deliveries into a local handler during
+// development, so webhooks can be tested without exposing localhost. It
+// reads deliveries either from the app's delivery log, where GitHub
+// records them even when the configured webhook URL is unreachable, or
+// from a smee.io channel. Each delivery is POSTed to the target URL with
+// the original headers and a signature computed with the local webhook
+// secret, so the target verifies it like a real delivery.
+//
+// A Forwarder is meant for development only; deliveries seen before Run
+// starts are not forwarded.
+type Forwarder struct {
+	target   string
+	secret   SecretFunc
+	client   *http.Client
+	api      *github.Client
+	smeeURL  string
+	interval time.Duration
+}
+
+// ForwarderOption is a functional option for configuring Forwarder.
+type ForwarderOption func(*Forwarder)
+
+// WithDeliveriesAPI reads deliveries from the app's delivery log. The
+// client must authenticate as the app (e.g. ghauth.NewClient with
+// installation ID zero).
+func WithDeliveriesAPI(client *github.Client) ForwarderOption {
+	return func(f *Forwarder) {
+		f.api = client
+	}
+}
+
+// WithSmeeChannel reads deliveries from a smee.io channel URL.
+func WithSmeeChannel(url string) ForwarderOption {
+	return func(f *Forwarder) {
+		f.smeeURL = url
+	}
+}
+
+// WithForwardSecret sets where the secret used to sign forwarded
+// deliveries comes from. The default is EnvSecret.
+func WithForwardSecret(fn SecretFunc) ForwarderOption {
+	return func(f *Forwarder) {
+		f.secret = fn
+	}
+}
+
+// WithForwardHTTPClient sets the client used to reach the target and the
+// smee channel.
+func WithForwardHTTPClient(c *http.Client) ForwarderOption {
+	return func(f *Forwarder) {
+		f.client = c
+	}
+}
+
+// WithPollInterval sets how often the deliveries API is polled. The
+// default is DefaultPollInterval.
+func WithPollInterval(d time.Duration) ForwarderOption {
+	return func(f *Forwarder) {
+		f.interval = d
+	}
+}
+
+// NewForwarder creates a Forwarder that delivers to target, e.g.
+// "http://localhost:8080/webhook". Exactly one source must be set with
+// WithDeliveriesAPI or WithSmeeChannel.
+func NewForwarder(target string, opts ...ForwarderOption) (*Forwarder, error) {
+	f := &Forwarder{
+		target:   target,
+		secret:   EnvSecret(),
+		client:   http.DefaultClient,
+		interval: DefaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	switch {
+	case target == "":
+		return nil, errors.New("webhook: forward target cannot be empty")
+	case f.api == nil && f.smeeURL == "":
+		return nil, errors.New("webhook: forwarder needs a deliveries API client or smee channel")
+	case f.api != nil && f.smeeURL != "":
+		return nil, errors.New("webhook: forwarder cannot use both the deliveries API and a smee channel")
+	}
+	return f, nil
+}
+
+// Run forwards deliveries until ctx is canceled.
+func (f *Forwarder) Run(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+	if f.api != nil {
+		log.Infof("[webhook] forwarding deliveries from the deliveries API to %s", f.target)
+		return f.poll(ctx)
+	}
+	log.Infof("[webhook] forwarding deliveries from %s to %s", f.smeeURL, f.target)
+	return f.streamSmee(ctx)
+}
+
+// poll forwards deliveries that appear in the delivery log after the
+// first poll, oldest first.
+func (f *Forwarder) poll(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+	seen := make(map[int64]bool)
+	first := true
+
+	for {
+		page, _, err := f.api.Apps.ListHookDeliveries(ctx, &github.ListCursorOptions{PerPage: recoverPageSize})
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			log.Warnf("[webhook] failed to list deliveries: %v", err)
+		case first:
+			for _, hd := range page {
+				seen[hd.GetID()] = true
+			}
+			first = false
+		default:
+			// The log is newest first; forward in arrival order.
+			for _, hd := range slices.Backward(page) {
+				if seen[hd.GetID()] {
+					continue
+				}
+				if err := f.forwardLogged(ctx, hd); err != nil {
+					log.Warnf("[webhook] failed to forward delivery %s: %v", hd.GetGUID(), err)
+					continue
+				}
+				seen[hd.GetID()] = true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// forwardLogged fetches a logged delivery's payload and forwards it.
+// Redeliveries are recorded but not forwarded again.
+func (f *Forwarder) forwardLogged(ctx context.Context, hd *github.HookDelivery) error {
+	if hd.GetRedelivery() {
+		return nil
+	}
+	full, _, err := f.api.Apps.GetHookDelivery(ctx, hd.GetID())
+	if err != nil {
+		return fmt.Errorf("fetch delivery: %w", err)
+	}
+	d := &Delivery{ID: hd.GetGUID(), Event: hd.GetEvent()}
+	if req := full.Request; req != nil {
+		d.HookID = req.GetHeader(HeaderHookID)
+		if req.RawPayload != nil {
+			d.Payload = []byte(*req.RawPayload)
+		}
+	}
+	return f.forward(ctx, d)
+}
+
+// streamSmee forwards events from the smee channel, reconnecting whenever
+// the stream ends.
+func (f *Forwarder) streamSmee(ctx context.Context) error {
+	log := clog.FromContext(ctx)
+	for {
+		if err := f.readSmee(ctx); err != nil && ctx.Err() == nil {
+			log.Warnf("[webhook] smee channel disconnected: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// readSmee reads one server-sent event stream from the smee channel. Each
+// message event carries the delivery's lowercased headers and its parsed
+// body as top-level JSON fields.
+func (f *Forwarder) readSmee(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.smeeURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("smee returned %d", resp.StatusCode)
+	}
+
+	log := clog.FromContext(ctx)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, MaxPayloadSize)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:") && (event == "" || event == "message"):
+			d, err := parseSmeeEvent([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))))
+			if err != nil {
+				log.Warnf("[webhook] ignoring smee event: %v", err)
+				continue
+			}
+			if err := f.forward(ctx, d); err != nil {
+				log.Warnf("[webhook] failed to forward delivery %s: %v", d.ID, err)
+			}
+		case line == "":
+			event = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// parseSmeeEvent extracts a delivery from a smee message event.
+func parseSmeeEvent(data []byte) (*Delivery, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	header := func(name string) string {
+		var v string
+		_ = json.Unmarshal(fields[strings.ToLower(name)], &v)
+		return v
+	}
+
+	d := &Delivery{
+		ID:      header(HeaderDelivery),
+		Event:   header(HeaderEvent),
+		HookID:  header(HeaderHookID),
+		Payload: fields["body"],
+	}
+	if d.Event == "" {
+		return nil, fmt.Errorf("missing %s", HeaderEvent)
+	}
+	return d, nil
+}
+
+// forward POSTs d to the target, signed with the local secret.
+func (f *Forwarder) forward(ctx context.Context, d *Delivery) error {
+	secret := f.secret()
+	if secret == "" {
+		return ErrMissingSecret
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.target, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	if d.HookID != "" {
+		req.Header.Set(HeaderHookID, d.HookID)
+	}
+	req.Header.Set(HeaderSignature, Sign(d.Payload, secret))
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	clog.FromContext(ctx).Infof("[webhook] forwarded %s delivery %s: %d", d.Event, d.ID, resp.StatusCode)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// forwardTarget returns a server running a verifying Handler that reports
+// each received delivery on the returned channel.
+func forwardTarget(t *testing.T) (*httptest.Server, <-chan *Delivery) {
+	t.Helper()
+	got := make(chan *Delivery, 10)
+	srv := httptest.NewServer(NewHandler(func(ctx context.Context, d *Delivery) error {
+		got <- d
+		return nil
+	}, WithSecret(testSecret)))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func receive(t *testing.T, got <-chan *Delivery) *Delivery {
+	t.Helper()
+	select {
+	case d := <-got:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a forwarded delivery")
+		return nil
+	}
+}
+
+func TestNewForwarder_RequiresOneSource(t *testing.T) {
+	if _, err := NewForwarder("http://localhost/webhook"); err == nil {
+		t.Error("NewForwarder() should fail without a source")
+	}
+	if _, err := NewForwarder("", WithSmeeChannel("https://smee.io/x")); err == nil {
+		t.Error("NewForwarder() should fail without a target")
+	}
+}
+
+func TestForwarder_DeliveriesAPI(t *testing.T) {
+	target, got := forwardTarget(t)
+	now := time.Now().UTC()
+	l := &deliveryLog{pages: [][]map[string]any{
+		{hookDelivery(1, "old", 502, now)},
+	}}
+	client := newRecoverClient(t, l)
+
+	f, err := NewForwarder(target.URL,
+		WithDeliveriesAPI(client),
+		WithForwardSecret(func() string { return testSecret }),
+		WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewForwarder() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.Run(ctx)
+
+	// Let the first poll record the existing delivery, then add a new one.
+	time.Sleep(50 * time.Millisecond)
+	l.mu.Lock()
+	l.pages[0] = append([]map[string]any{hookDelivery(2, "new", 502, now)}, l.pages[0]...)
+	l.mu.Unlock()
+
+	d := receive(t, got)
+	if d.ID != "new" || d.Event != "push" || string(d.Payload) != `{"ref":"main"}` {
+		t.Errorf("forwarded delivery = %+v", d)
+	}
+	select {
+	case extra := <-got:
+		t.Errorf("unexpected forwarded delivery %+v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestForwarder_Smee(t *testing.T) {
+	target, got := forwardTarget(t)
+	smee := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: ready\ndata: {}\n\n")
+		fmt.Fprint(w, "event: message\n")
+		fmt.Fprint(w, `data: {"x-github-event":"issues","x-github-delivery":"d-9","x-hub-signature-256":"sha256=stale","body":{"action":"opened"},"timestamp":1}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer smee.Close()
+
+	f, err := NewForwarder(target.URL,
+		WithSmeeChannel(smee.URL),
+		WithForwardSecret(func() string { return testSecret }))
+	if err != nil {
+		t.Fatalf("NewForwarder() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.Run(ctx)
+
+	d := receive(t, got)
+	if d.ID != "d-9" || d.Event != "issues" || string(d.Payload) != `{"action":"opened"}` {
+		t.Errorf("forwarded delivery = %+v", d)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// installationPayload is the part of installation and
+// installation_repositories payloads needed to record an installation.
+type installationPayload struct {
+	Action       string `json:"action"`
+	Installation struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"account"`
+		RepositorySelection string  `json:"repository_selection"`
+		SuspendedAt         *string `json:"suspended_at"`
+	} `json:"installation"`
+}
+
+// TrackInstallations registers routes that keep store's record of the
+// app's installations current: "installation" events add, update, or
+// remove installations, and "installation_repositories" events refresh
+// the repository selection of installations made before tracking began.
+// All built-in configstore backends implement InstallationStore.
+func TrackInstallations(r *Router, store configstore.InstallationStore) {
+	r.On(EventInstallation, func(ctx context.Context, d *Delivery) error {
+		return recordInstallation(ctx, store, d)
+	})
+	r.On(EventInstallationRepositories, func(ctx context.Context, d *Delivery) error {
+		return recordInstallation(ctx, store, d)
+	})
+}
+
+// recordInstallation applies an installation event to store.
+func recordInstallation(ctx context.Context, store configstore.InstallationStore, d *Delivery) error {
+	var p installationPayload
+	if err := json.Unmarshal(d.Payload, &p); err != nil {
+		return fmt.Errorf("webhook: failed to parse %s payload: %w", d.Event, err)
+	}
+	if p.Installation.ID == 0 {
+		return fmt.Errorf("webhook: %s payload has no installation", d.Event)
+	}
+	log := clog.FromContext(ctx)
+
+	if d.Event == EventInstallation && p.Action == "deleted" {
+		if err := store.DeleteInstallation(ctx, p.Installation.ID); err != nil {
+			return fmt.Errorf("webhook: failed to remove installation %d: %w", p.Installation.ID, err)
+		}
+		log.Infof("[webhook] removed installation %d (%s)", p.Installation.ID, p.Installation.Account.Login)
+		return nil
+	}
+
+	inst := configstore.Installation{
+		ID:                  p.Installation.ID,
+		Account:             p.Installation.Account.Login,
+		AccountType:         p.Installation.Account.Type,
+		RepositorySelection: p.Installation.RepositorySelection,
+		Suspended:           p.Installation.SuspendedAt != nil,
+	}
+	// Suspension events may arrive before the payload reflects them.
+	switch p.Action {
+	case "suspend":
+		inst.Suspended = true
+	case "unsuspend":
+		inst.Suspended = false
+	}
+
+	if err := store.SaveInstallation(ctx, inst); err != nil {
+		return fmt.Errorf("webhook: failed to record installation %d: %w", inst.ID, err)
+	}
+	log.Infof("[webhook] recorded installation %d (%s) from %s.%s", inst.ID, inst.Account, d.Event, p.Action)
+	return nil
+}
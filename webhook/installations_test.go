@@ -0,0 +1,82 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+func installationEvent(event, action string, id int64) *Delivery {
+	payload := fmt.Sprintf(`{"action":%q,"installation":{"id":%d,`+
+		`"account":{"login":"acme","type":"Organization"},"repository_selection":"selected"}}`, action, id)
+	return &Delivery{ID: "d", Event: event, Payload: []byte(payload)}
+}
+
+func TestTrackInstallations(t *testing.T) {
+	store := configstore.NewLocalFileStore(filepath.Join(t.TempDir(), "creds"))
+	r := NewRouter()
+	TrackInstallations(r, store)
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		d    *Delivery
+		want []configstore.Installation
+	}{
+		{
+			name: "created",
+			d:    installationEvent("installation", "created", 7),
+			want: []configstore.Installation{{ID: 7, Account: "acme", AccountType: "Organization", RepositorySelection: "selected"}},
+		},
+		{
+			name: "suspended",
+			d:    installationEvent("installation", "suspend", 7),
+			want: []configstore.Installation{{ID: 7, Account: "acme", AccountType: "Organization", RepositorySelection: "selected", Suspended: true}},
+		},
+		{
+			name: "repositories changed on untracked installation",
+			d:    installationEvent("installation_repositories", "added", 8),
+			want: []configstore.Installation{
+				{ID: 7, Account: "acme", AccountType: "Organization", RepositorySelection: "selected", Suspended: true},
+				{ID: 8, Account: "acme", AccountType: "Organization", RepositorySelection: "selected"},
+			},
+		},
+		{
+			name: "deleted",
+			d:    installationEvent("installation", "deleted", 7),
+			want: []configstore.Installation{{ID: 8, Account: "acme", AccountType: "Organization", RepositorySelection: "selected"}},
+		},
+	}
+	for _, step := range steps {
+		if err := r.Dispatch(ctx, step.d); err != nil {
+			t.Fatalf("%s: Dispatch() error = %v", step.name, err)
+		}
+		got, err := store.Installations(ctx)
+		if err != nil {
+			t.Fatalf("%s: Installations() error = %v", step.name, err)
+		}
+		if len(got) != len(step.want) {
+			t.Fatalf("%s: Installations() = %+v, want %+v", step.name, got, step.want)
+		}
+		for i := range got {
+			if got[i] != step.want[i] {
+				t.Errorf("%s: Installations()[%d] = %+v, want %+v", step.name, i, got[i], step.want[i])
+			}
+		}
+	}
+}
+
+func TestTrackInstallations_RejectsMissingInstallation(t *testing.T) {
+	r := NewRouter()
+	TrackInstallations(r, configstore.NewLocalFileStore(t.TempDir()))
+	d := &Delivery{Event: "installation", Payload: []byte(`{"action":"created"}`)}
+	if err := r.Dispatch(context.Background(), d); err == nil {
+		t.Error("Dispatch() should fail for a payload without an installation")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-github/v82/github"
+)
+
+// Events with their go-github payload types, for use with Decode and Typed.
+const (
+	EventPush                     = "push"                      // *github.PushEvent
+	EventPullRequest              = "pull_request"              // *github.PullRequestEvent
+	EventIssues                   = "issues"                    // *github.IssuesEvent
+	EventCheckRun                 = "check_run"                 // *github.CheckRunEvent
+	EventInstallation             = "installation"              // *github.InstallationEvent
+	EventWorkflowRun              = "workflow_run"              // *github.WorkflowRunEvent
+	EventInstallationRepositories = "installation_repositories" // *github.InstallationRepositoriesEvent
+)
+
+// Common holds the fields most event payloads share, enough for logging
+// and routing without decoding the full event.
+type Common struct {
+	Action       string `json:"action,omitempty"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+	Repository struct {
+		ID       int64  `json:"id"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Organization struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+	Sender struct {
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"sender"`
+}
+
+// Common decodes the fields shared by most event payloads. Fields absent
+// from the event are left empty.
+func (d *Delivery) Common() (*Common, error) {
+	var c Common
+	if err := json.Unmarshal(d.Payload, &c); err != nil {
+		return nil, fmt.Errorf("webhook: failed to parse %s payload: %w", d.Event, err)
+	}
+	return &c, nil
+}
+
+// Parse decodes the payload into the go-github event type for d.Event
+// (e.g. *github.PushEvent for "push"), for use in a type switch.
+func (d *Delivery) Parse() (any, error) {
+	event, err := github.ParseWebHook(d.Event, d.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to parse %s payload: %w", d.Event, err)
+	}
+	return event, nil
+}
+
+// githubPkgPath is the import path of the go-github package.
+var githubPkgPath = reflect.TypeFor[github.PushEvent]().PkgPath()
+
+// Decode decodes the payload into T, typically a go-github event type such
+// as github.PullRequestEvent or an application-defined struct. If T is a
+// go-github event type, it must match d.Event, so a handler registered for
+// the wrong event fails instead of seeing an empty payload.
+func Decode[T any](d *Delivery) (*T, error) {
+	if reflect.TypeFor[T]().PkgPath() == githubPkgPath {
+		if want := github.EventForType(d.Event); want != nil {
+			if _, ok := want.(*T); !ok {
+				return nil, fmt.Errorf("webhook: %s delivery carries %T, not *%s",
+					d.Event, want, reflect.TypeFor[T]())
+			}
+		}
+	}
+
+	var v T
+	if err := json.Unmarshal(d.Payload, &v); err != nil {
+		return nil, fmt.Errorf("webhook: failed to parse %s payload: %w", d.Event, err)
+	}
+	return &v, nil
+}
+
+// Typed adapts a function taking a decoded payload into a DeliveryFunc
+// for Router.On (see Decode):
+//
+//	router.On(webhook.EventPullRequest+".opened", webhook.Typed(
+//	    func(ctx context.Context, d *webhook.Delivery, e *github.PullRequestEvent) error {
+//	        return greet(ctx, e.GetRepo(), e.GetNumber())
+//	    }))
+func Typed[T any](fn func(ctx context.Context, d *Delivery, payload *T) error) DeliveryFunc {
+	return func(ctx context.Context, d *Delivery) error {
+		payload, err := Decode[T](d)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, d, payload)
+	}
+}
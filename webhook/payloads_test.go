@@ -0,0 +1,84 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v82/github"
+)
+
+const pullRequestPayload = `{"action":"opened","number":5,
+	"pull_request":{"title":"Fix it"},
+	"installation":{"id":9},
+	"repository":{"id":3,"full_name":"acme/widgets"},
+	"sender":{"login":"octocat","type":"User"}}`
+
+func TestDelivery_Common(t *testing.T) {
+	d := &Delivery{Event: EventPullRequest, Payload: []byte(pullRequestPayload)}
+	c, err := d.Common()
+	if err != nil {
+		t.Fatalf("Common() error = %v", err)
+	}
+	if c.Action != "opened" || c.Installation.ID != 9 || c.Repository.FullName != "acme/widgets" || c.Sender.Login != "octocat" {
+		t.Errorf("Common() = %+v", c)
+	}
+}
+
+func TestDelivery_Parse(t *testing.T) {
+	d := &Delivery{Event: EventPullRequest, Payload: []byte(pullRequestPayload)}
+	event, err := d.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pr, ok := event.(*github.PullRequestEvent)
+	if !ok || pr.GetNumber() != 5 {
+		t.Errorf("Parse() = %T %+v, want *github.PullRequestEvent #5", event, event)
+	}
+
+	if _, err := (&Delivery{Event: "not_an_event", Payload: []byte(`{}`)}).Parse(); err == nil {
+		t.Error("Parse() should fail for unknown events")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	d := &Delivery{Event: EventPullRequest, Payload: []byte(pullRequestPayload)}
+
+	pr, err := Decode[github.PullRequestEvent](d)
+	if err != nil || pr.GetPullRequest().GetTitle() != "Fix it" {
+		t.Fatalf("Decode() = %+v, %v", pr, err)
+	}
+
+	if _, err := Decode[github.PushEvent](d); err == nil {
+		t.Error("Decode() should reject a go-github type for a different event")
+	}
+
+	type custom struct {
+		Number int `json:"number"`
+	}
+	if c, err := Decode[custom](d); err != nil || c.Number != 5 {
+		t.Errorf("Decode() custom = %+v, %v", c, err)
+	}
+}
+
+func TestTyped(t *testing.T) {
+	r := NewRouter()
+	var got *github.PullRequestEvent
+	r.On(EventPullRequest+".opened", Typed(func(ctx context.Context, d *Delivery, e *github.PullRequestEvent) error {
+		got = e
+		return nil
+	}))
+	r.On(EventPullRequest, Typed(func(ctx context.Context, d *Delivery, e *github.IssuesEvent) error {
+		return nil
+	}))
+
+	err := r.Dispatch(context.Background(), &Delivery{Event: EventPullRequest, Payload: []byte(pullRequestPayload)})
+	if err == nil {
+		t.Error("Dispatch() should report the handler registered with the wrong payload type")
+	}
+	if got.GetRepo().GetFullName() != "acme/widgets" {
+		t.Errorf("typed handler got %+v", got)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// DefaultMessageGroup is the message group ID used for FIFO queues and
+// topics unless WithMessageGroup is set.
+const DefaultMessageGroup = "github-webhooks"
+
+// MaxMessageSize is the largest message SQS and SNS accept (256 KiB),
+// counting the body and the message attributes. GitHub payloads can be up
+// to MaxPayloadSize, so large deliveries such as pushes with many commits
+// may not fit.
+const MaxMessageSize = 256 << 10
+
+// ErrMessageTooLarge is returned by SQSPublisher.Publish and
+// SNSPublisher.Publish when a delivery does not fit in MaxMessageSize.
+// The delivery is not sent.
+var ErrMessageTooLarge = errors.New("webhook: delivery exceeds the SQS/SNS message size limit")
+
+// SQSClient defines the interface for AWS SQS operations.
+type SQSClient interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput,
+		optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SNSClient defines the interface for AWS SNS operations.
+type SNSClient interface {
+	Publish(ctx context.Context, params *sns.PublishInput,
+		optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// publisher holds the configuration shared by the queue publishers.
+type publisher struct {
+	sqsClient    SQSClient
+	snsClient    SNSClient
+	messageGroup func(*Delivery) string
+}
+
+// PublisherOption is a functional option for configuring SQSPublisher and
+// SNSPublisher.
+type PublisherOption func(*publisher)
+
+// WithSQSClient sets a custom SQS client.
+func WithSQSClient(client SQSClient) PublisherOption {
+	return func(p *publisher) {
+		p.sqsClient = client
+	}
+}
+
+// WithSNSClient sets a custom SNS client.
+func WithSNSClient(client SNSClient) PublisherOption {
+	return func(p *publisher) {
+		p.snsClient = client
+	}
+}
+
+// WithMessageGroup sets how deliveries are assigned to message groups on
+// FIFO queues and topics, e.g. by repository to keep each repository's
+// events in order. The default puts every delivery in
+// DefaultMessageGroup.
+func WithMessageGroup(fn func(*Delivery) string) PublisherOption {
+	return func(p *publisher) {
+		p.messageGroup = fn
+	}
+}
+
+func newPublisher(opts []PublisherOption) *publisher {
+	p := &publisher{
+		messageGroup: func(*Delivery) string { return DefaultMessageGroup },
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// SQSPublisher publishes verified deliveries to an SQS queue. Each message
+// body is the raw payload, with the event name, delivery ID, and hook ID
+// as the X-GitHub-Event, X-GitHub-Delivery, and X-GitHub-Hook-ID string
+// attributes. On FIFO queues the delivery ID is the deduplication ID, so
+// GitHub redeliveries are dropped.
+//
+// Use Publish as a DeliveryFunc to buffer webhooks in a queue:
+//
+//	http.Handle("/webhook", webhook.NewHandler(pub.Publish))
+type SQSPublisher struct {
+	QueueURL string
+	*publisher
+}
+
+// NewSQSPublisher creates a publisher for the queue at queueURL.
+func NewSQSPublisher(queueURL string, opts ...PublisherOption) (*SQSPublisher, error) {
+	if queueURL == "" {
+		return nil, errors.New("webhook: queue URL cannot be empty")
+	}
+	p := &SQSPublisher{QueueURL: queueURL, publisher: newPublisher(opts)}
+	if p.sqsClient == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		p.sqsClient = sqs.NewFromConfig(cfg)
+	}
+	return p, nil
+}
+
+// Publish sends d to the queue. It returns ErrMessageTooLarge without
+// sending when the message would exceed MaxMessageSize.
+func (p *SQSPublisher) Publish(ctx context.Context, d *Delivery) error {
+	values := deliveryAttributes(d)
+	if err := checkMessageSize(d, values); err != nil {
+		return err
+	}
+	attrs := make(map[string]sqstypes.MessageAttributeValue)
+	for name, value := range values {
+		attrs[name] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(p.QueueURL),
+		MessageBody:       aws.String(string(d.Payload)),
+		MessageAttributes: attrs,
+	}
+	if strings.HasSuffix(p.QueueURL, ".fifo") {
+		input.MessageGroupId = aws.String(p.messageGroup(d))
+		input.MessageDeduplicationId = aws.String(d.ID)
+	}
+
+	if _, err := p.sqsClient.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("webhook: failed to send delivery %s to SQS: %w", d.ID, err)
+	}
+	return nil
+}
+
+// SNSPublisher publishes verified deliveries to an SNS topic, with the
+// same message body and attributes as SQSPublisher.
+type SNSPublisher struct {
+	TopicARN string
+	*publisher
+}
+
+// NewSNSPublisher creates a publisher for the topic topicARN.
+func NewSNSPublisher(topicARN string, opts ...PublisherOption) (*SNSPublisher, error) {
+	if topicARN == "" {
+		return nil, errors.New("webhook: topic ARN cannot be empty")
+	}
+	p := &SNSPublisher{TopicARN: topicARN, publisher: newPublisher(opts)}
+	if p.snsClient == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		p.snsClient = sns.NewFromConfig(cfg)
+	}
+	return p, nil
+}
+
+// Publish sends d to the topic. It returns ErrMessageTooLarge without
+// sending when the message would exceed MaxMessageSize.
+func (p *SNSPublisher) Publish(ctx context.Context, d *Delivery) error {
+	values := deliveryAttributes(d)
+	if err := checkMessageSize(d, values); err != nil {
+		return err
+	}
+	attrs := make(map[string]snstypes.MessageAttributeValue)
+	for name, value := range values {
+		attrs[name] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(p.TopicARN),
+		Message:           aws.String(string(d.Payload)),
+		MessageAttributes: attrs,
+	}
+	if strings.HasSuffix(p.TopicARN, ".fifo") {
+		input.MessageGroupId = aws.String(p.messageGroup(d))
+		input.MessageDeduplicationId = aws.String(d.ID)
+	}
+
+	if _, err := p.snsClient.Publish(ctx, input); err != nil {
+		return fmt.Errorf("webhook: failed to publish delivery %s to SNS: %w", d.ID, err)
+	}
+	return nil
+}
+
+// deliveryAttributes returns the message attributes describing d. Empty
+// values are omitted, as queues reject them.
+func deliveryAttributes(d *Delivery) map[string]string {
+	attrs := make(map[string]string, 3)
+	for name, value := range map[string]string{
+		HeaderEvent:    d.Event,
+		HeaderDelivery: d.ID,
+		HeaderHookID:   d.HookID,
+	} {
+		if value != "" {
+			attrs[name] = value
+		}
+	}
+	return attrs
+}
+
+// checkMessageSize returns ErrMessageTooLarge if the payload of d and
+// attrs, sent as String attributes, exceed MaxMessageSize. SQS and SNS
+// count each attribute's name, data type, and value.
+func checkMessageSize(d *Delivery, attrs map[string]string) error {
+	size := len(d.Payload)
+	for name, value := range attrs {
+		size += len(name) + len("String") + len(value)
+	}
+	if size > MaxMessageSize {
+		return fmt.Errorf("%w: delivery %s is %d bytes, limit %d", ErrMessageTooLarge, d.ID, size, MaxMessageSize)
+	}
+	return nil
+}
+
+// snsEnvelope is the JSON body SNS delivers to subscribed queues unless raw
+// message delivery is enabled.
+type snsEnvelope struct {
+	Type              string `json:"Type"`
+	Message           string `json:"Message"`
+	MessageAttributes map[string]struct {
+		Type  string `json:"Type"`
+		Value string `json:"Value"`
+	} `json:"MessageAttributes"`
+}
+
+// ParseMessage rebuilds a delivery from a queue message body and its
+// string attributes, as published by SQSPublisher or SNSPublisher. SNS
+// notifications wrapped in the SNS envelope (raw message delivery off) are
+// unwrapped. Use it for messages from sources other than the SQS API, such
+// as Lambda SQS events; for ReceiveMessage results use ParseSQSMessage.
+func ParseMessage(body string, attributes map[string]string) (*Delivery, error) {
+	if attributes[HeaderEvent] == "" {
+		var env snsEnvelope
+		if err := json.Unmarshal([]byte(body), &env); err == nil && env.Type == "Notification" {
+			body = env.Message
+			attributes = make(map[string]string, len(env.MessageAttributes))
+			for name, attr := range env.MessageAttributes {
+				attributes[name] = attr.Value
+			}
+		}
+	}
+
+	d := &Delivery{
+		ID:      attributes[HeaderDelivery],
+		Event:   attributes[HeaderEvent],
+		HookID:  attributes[HeaderHookID],
+		Payload: []byte(body),
+	}
+	if d.Event == "" {
+		return nil, fmt.Errorf("webhook: message has no %s attribute", HeaderEvent)
+	}
+	return d, nil
+}
+
+// ParseSQSMessage rebuilds a delivery from an SQS message. The message
+// attributes must have been requested, e.g. with MessageAttributeNames
+// set to "All" on ReceiveMessage.
+func ParseSQSMessage(msg sqstypes.Message) (*Delivery, error) {
+	attrs := make(map[string]string, len(msg.MessageAttributes))
+	for name, attr := range msg.MessageAttributes {
+		attrs[name] = aws.ToString(attr.StringValue)
+	}
+	return ParseMessage(aws.ToString(msg.Body), attrs)
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// mockSQSClient implements SQSClient for testing
+type mockSQSClient struct {
+	inputs []*sqs.SendMessageInput
+	err    error
+}
+
+func (m *mockSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	m.inputs = append(m.inputs, params)
+	return &sqs.SendMessageOutput{}, m.err
+}
+
+// mockSNSClient implements SNSClient for testing
+type mockSNSClient struct {
+	inputs []*sns.PublishInput
+}
+
+func (m *mockSNSClient) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	m.inputs = append(m.inputs, params)
+	return &sns.PublishOutput{}, nil
+}
+
+func testDelivery() *Delivery {
+	return &Delivery{ID: "d-1", Event: "push", Payload: []byte(`{"ref":"main"}`)}
+}
+
+func TestSQSPublisher_RoundTrip(t *testing.T) {
+	client := &mockSQSClient{}
+	pub, err := NewSQSPublisher("https://sqs.us-east-1.amazonaws.com/123/hooks", WithSQSClient(client))
+	if err != nil {
+		t.Fatalf("NewSQSPublisher() error = %v", err)
+	}
+	if err := pub.Publish(context.Background(), testDelivery()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(client.inputs) != 1 {
+		t.Fatalf("SendMessage calls = %d, want 1", len(client.inputs))
+	}
+
+	in := client.inputs[0]
+	if in.MessageGroupId != nil || in.MessageDeduplicationId != nil {
+		t.Error("standard queues should not set FIFO fields")
+	}
+	if _, ok := in.MessageAttributes[HeaderHookID]; ok {
+		t.Error("empty hook ID should not be sent as an attribute")
+	}
+
+	// Feed the sent message back through the consumer helper.
+	msg := sqstypes.Message{Body: in.MessageBody, MessageAttributes: in.MessageAttributes}
+	got, err := ParseSQSMessage(msg)
+	if err != nil {
+		t.Fatalf("ParseSQSMessage() error = %v", err)
+	}
+	want := testDelivery()
+	if got.ID != want.ID || got.Event != want.Event || string(got.Payload) != string(want.Payload) {
+		t.Errorf("ParseSQSMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSQSPublisher_FIFO(t *testing.T) {
+	client := &mockSQSClient{}
+	pub, _ := NewSQSPublisher("https://sqs.us-east-1.amazonaws.com/123/hooks.fifo",
+		WithSQSClient(client),
+		WithMessageGroup(func(d *Delivery) string { return "group-" + d.Event }))
+	if err := pub.Publish(context.Background(), testDelivery()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	in := client.inputs[0]
+	if aws.ToString(in.MessageGroupId) != "group-push" {
+		t.Errorf("MessageGroupId = %q, want group-push", aws.ToString(in.MessageGroupId))
+	}
+	if aws.ToString(in.MessageDeduplicationId) != "d-1" {
+		t.Errorf("MessageDeduplicationId = %q, want delivery ID", aws.ToString(in.MessageDeduplicationId))
+	}
+}
+
+func TestSQSPublisher_Error(t *testing.T) {
+	client := &mockSQSClient{err: errors.New("throttled")}
+	pub, _ := NewSQSPublisher("https://sqs.example/q", WithSQSClient(client))
+	if err := pub.Publish(context.Background(), testDelivery()); err == nil {
+		t.Error("Publish() should return the SQS error")
+	}
+}
+
+func TestPublishers_MessageTooLarge(t *testing.T) {
+	fits := testDelivery()
+	fits.Payload = make([]byte, MaxMessageSize-len(HeaderEvent+"String"+fits.Event)-len(HeaderDelivery+"String"+fits.ID))
+	tooLarge := testDelivery()
+	tooLarge.Payload = make([]byte, len(fits.Payload)+1)
+
+	sqsClient := &mockSQSClient{}
+	sqsPub, _ := NewSQSPublisher("https://sqs.example/q", WithSQSClient(sqsClient))
+	snsClient := &mockSNSClient{}
+	snsPub, _ := NewSNSPublisher("arn:aws:sns:us-east-1:123:hooks", WithSNSClient(snsClient))
+
+	for name, publish := range map[string]DeliveryFunc{"SQS": sqsPub.Publish, "SNS": snsPub.Publish} {
+		if err := publish(context.Background(), fits); err != nil {
+			t.Errorf("%s Publish() at the limit error = %v", name, err)
+		}
+		if err := publish(context.Background(), tooLarge); !errors.Is(err, ErrMessageTooLarge) {
+			t.Errorf("%s Publish() over the limit error = %v, want ErrMessageTooLarge", name, err)
+		}
+	}
+	if len(sqsClient.inputs) != 1 || len(snsClient.inputs) != 1 {
+		t.Errorf("sent %d SQS and %d SNS messages, want only the one that fits", len(sqsClient.inputs), len(snsClient.inputs))
+	}
+}
+
+func TestNewPublishers_RequireTarget(t *testing.T) {
+	if _, err := NewSQSPublisher("", WithSQSClient(&mockSQSClient{})); err == nil {
+		t.Error("NewSQSPublisher() should fail without a queue URL")
+	}
+	if _, err := NewSNSPublisher("", WithSNSClient(&mockSNSClient{})); err == nil {
+		t.Error("NewSNSPublisher() should fail without a topic ARN")
+	}
+}
+
+func TestSNSPublisher_EnvelopeRoundTrip(t *testing.T) {
+	client := &mockSNSClient{}
+	pub, err := NewSNSPublisher("arn:aws:sns:us-east-1:123:hooks", WithSNSClient(client))
+	if err != nil {
+		t.Fatalf("NewSNSPublisher() error = %v", err)
+	}
+	if err := pub.Publish(context.Background(), testDelivery()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	in := client.inputs[0]
+
+	// Build the envelope SNS wraps notifications in for SQS subscribers.
+	env := map[string]any{
+		"Type":              "Notification",
+		"Message":           aws.ToString(in.Message),
+		"MessageAttributes": map[string]any{},
+	}
+	for name, attr := range in.MessageAttributes {
+		env["MessageAttributes"].(map[string]any)[name] = map[string]string{"Type": "String", "Value": aws.ToString(attr.StringValue)}
+	}
+	body, _ := json.Marshal(env)
+
+	got, err := ParseMessage(string(body), nil)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if got.ID != "d-1" || got.Event != "push" || string(got.Payload) != `{"ref":"main"}` {
+		t.Errorf("ParseMessage() = %+v", got)
+	}
+}
+
+func TestParseMessage_RequiresEvent(t *testing.T) {
+	if _, err := ParseMessage(`{"ref":"main"}`, map[string]string{HeaderDelivery: "d-1"}); err == nil {
+		t.Error("ParseMessage() should fail without an event attribute")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v82/github"
+)
+
+// recoverPageSize is how many deliveries are listed per API request, the
+// maximum GitHub allows.
+const recoverPageSize = 100
+
+// RecoverResult summarizes a Recover run.
+type RecoverResult struct {
+	// Checked is the number of distinct deliveries examined.
+	Checked int
+	// Recovered lists the IDs of deliveries that were redelivered or
+	// processed.
+	Recovered []string
+	// Errors holds failures for individual deliveries; they do not stop
+	// the run.
+	Errors []error
+}
+
+// Recoverer finds webhook deliveries the app missed, e.g. while it was
+// down between deploys, using the app's delivery log. A delivery counts as
+// missed when it is not in the DedupStore and none of GitHub's attempts to
+// deliver it succeeded, so deliveries that were acknowledged before a
+// restart are not repeated even with a MemoryDedup.
+//
+// By default missed deliveries are redelivered by GitHub, arriving at the
+// webhook endpoint again like any other delivery. With WithRecoverFunc
+// their payloads are fetched from the API and processed directly instead.
+type Recoverer struct {
+	client *github.Client
+	dedup  DedupStore
+	fn     DeliveryFunc
+}
+
+// RecovererOption is a functional option for configuring Recoverer.
+type RecovererOption func(*Recoverer)
+
+// WithRecoverFunc processes missed deliveries with fn, marking them in
+// the DedupStore once it succeeds, instead of asking GitHub to redeliver
+// them.
+func WithRecoverFunc(fn DeliveryFunc) RecovererOption {
+	return func(r *Recoverer) {
+		r.fn = fn
+	}
+}
+
+// NewRecoverer creates a Recoverer. The client must authenticate as the
+// app (e.g. ghauth.NewClient with installation ID zero), and dedup should
+// be the store the webhook Handler records deliveries in (see WithDedup).
+// A nil dedup treats every failed delivery as missed.
+func NewRecoverer(client *github.Client, dedup DedupStore, opts ...RecovererOption) *Recoverer {
+	r := &Recoverer{
+		client: client,
+		dedup:  dedup,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// missedDelivery is the latest attempt of a delivery, and whether any
+// attempt succeeded.
+type missedDelivery struct {
+	latest    *github.HookDelivery
+	delivered bool
+}
+
+// Recover recovers deliveries missed since the given time. GitHub keeps
+// delivery logs for three days, so older deliveries cannot be recovered.
+func (r *Recoverer) Recover(ctx context.Context, since time.Time) (*RecoverResult, error) {
+	if r.client == nil {
+		return nil, errors.New("webhook: recoverer has no GitHub client")
+	}
+	log := clog.FromContext(ctx)
+
+	deliveries, order, err := r.listSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecoverResult{Checked: len(order)}
+	for _, guid := range order {
+		d := deliveries[guid]
+		if d.delivered {
+			continue
+		}
+		if r.dedup != nil {
+			seen, err := r.dedup.Seen(ctx, guid)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("webhook: dedup lookup for delivery %s: %w", guid, err))
+				continue
+			}
+			if seen {
+				continue
+			}
+		}
+
+		if err := r.recoverDelivery(ctx, guid, d.latest); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Recovered = append(result.Recovered, guid)
+	}
+
+	if len(result.Recovered) > 0 || len(result.Errors) > 0 {
+		log.Infof("[webhook] recovered %d of %d deliveries since %s (%d errors)",
+			len(result.Recovered), result.Checked, since.Format(time.RFC3339), len(result.Errors))
+	}
+	return result, nil
+}
+
+// listSince pages through the delivery log, newest first, until it reaches
+// deliveries older than since. It returns the attempts grouped by GUID and
+// the GUIDs in the order first seen.
+func (r *Recoverer) listSince(ctx context.Context, since time.Time) (map[string]*missedDelivery, []string, error) {
+	deliveries := make(map[string]*missedDelivery)
+	var order []string
+
+	opts := &github.ListCursorOptions{PerPage: recoverPageSize}
+	for {
+		page, resp, err := r.client.Apps.ListHookDeliveries(ctx, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("webhook: failed to list deliveries: %w", err)
+		}
+
+		for _, hd := range page {
+			if hd.DeliveredAt != nil && hd.DeliveredAt.Before(since) {
+				return deliveries, order, nil
+			}
+			guid := hd.GetGUID()
+			d, ok := deliveries[guid]
+			if !ok {
+				d = &missedDelivery{latest: hd}
+				deliveries[guid] = d
+				order = append(order, guid)
+			}
+			if code := hd.GetStatusCode(); code >= 200 && code < 300 {
+				d.delivered = true
+			}
+		}
+
+		if resp == nil || resp.Cursor == "" || len(page) == 0 {
+			return deliveries, order, nil
+		}
+		opts.Cursor = resp.Cursor
+	}
+}
+
+// recoverDelivery redelivers a missed delivery or fetches and processes it.
+func (r *Recoverer) recoverDelivery(ctx context.Context, guid string, hd *github.HookDelivery) error {
+	if r.fn == nil {
+		// GitHub answers 202, which go-github reports as an AcceptedError.
+		_, _, err := r.client.Apps.RedeliverHookDelivery(ctx, hd.GetID())
+		var accepted *github.AcceptedError
+		if err != nil && !errors.As(err, &accepted) {
+			return fmt.Errorf("webhook: failed to redeliver delivery %s: %w", guid, err)
+		}
+		return nil
+	}
+
+	full, _, err := r.client.Apps.GetHookDelivery(ctx, hd.GetID())
+	if err != nil {
+		return fmt.Errorf("webhook: failed to fetch delivery %s: %w", guid, err)
+	}
+	d := &Delivery{ID: guid, Event: hd.GetEvent()}
+	if req := full.Request; req != nil {
+		d.HookID = req.GetHeader(HeaderHookID)
+		if req.RawPayload != nil {
+			d.Payload = []byte(*req.RawPayload)
+		}
+	}
+
+	if err := r.fn(ctx, d); err != nil {
+		return fmt.Errorf("webhook: failed to process delivery %s: %w", guid, err)
+	}
+	if r.dedup != nil {
+		if err := r.dedup.Mark(ctx, guid); err != nil {
+			return fmt.Errorf("webhook: failed to record delivery %s: %w", guid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v82/github"
+)
+
+// deliveryLog emulates the app hook deliveries API.
+type deliveryLog struct {
+	mu          sync.Mutex
+	pages       [][]map[string]any
+	redelivered []int64
+}
+
+func (l *deliveryLog) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/hook/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		page := 0
+		if c := r.URL.Query().Get("cursor"); c != "" {
+			fmt.Sscanf(c, "%d", &page)
+		}
+		if page+1 < len(l.pages) {
+			next := fmt.Sprintf("<%s?cursor=%d&per_page=100>; rel=\"next\"", "http://"+r.Host+r.URL.Path, page+1)
+			w.Header().Set("Link", next)
+		}
+		_ = json.NewEncoder(w).Encode(l.pages[page])
+	})
+	mux.HandleFunc("GET /app/hook/deliveries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		fmt.Sscanf(r.PathValue("id"), "%d", &id)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":    id,
+			"event": "push",
+			"request": map[string]any{
+				"headers": map[string]string{HeaderHookID: "99"},
+				"payload": map[string]string{"ref": "main"},
+			},
+		})
+	})
+	mux.HandleFunc("POST /app/hook/deliveries/{id}/attempts", func(w http.ResponseWriter, r *http.Request) {
+		var id int64
+		fmt.Sscanf(r.PathValue("id"), "%d", &id)
+		l.mu.Lock()
+		l.redelivered = append(l.redelivered, id)
+		l.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	return mux
+}
+
+func hookDelivery(id int64, guid string, status int, at time.Time) map[string]any {
+	return map[string]any{
+		"id":           id,
+		"guid":         guid,
+		"status_code":  status,
+		"event":        "push",
+		"delivered_at": at.Format(time.RFC3339),
+	}
+}
+
+func newRecoverClient(t *testing.T, l *deliveryLog) *github.Client {
+	t.Helper()
+	srv := httptest.NewServer(l.handler())
+	t.Cleanup(srv.Close)
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+func TestRecoverer_RedeliversMissed(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	l := &deliveryLog{pages: [][]map[string]any{
+		{
+			hookDelivery(6, "failed-then-ok", 200, now.Add(-time.Minute)),
+			hookDelivery(5, "failed", 502, now.Add(-2*time.Minute)),
+			hookDelivery(4, "failed-then-ok", 0, now.Add(-3*time.Minute)),
+		},
+		{
+			hookDelivery(3, "already-seen", 503, now.Add(-4*time.Minute)),
+			hookDelivery(2, "too-old", 502, now.Add(-2*time.Hour)),
+		},
+	}}
+	dedup := NewMemoryDedup(0)
+	_ = dedup.Mark(context.Background(), "already-seen")
+
+	rec := NewRecoverer(newRecoverClient(t, l), dedup)
+	result, err := rec.Recover(context.Background(), now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	if result.Checked != 3 {
+		t.Errorf("Checked = %d, want 3", result.Checked)
+	}
+	if !slices.Equal(result.Recovered, []string{"failed"}) {
+		t.Errorf("Recovered = %v, want [failed]", result.Recovered)
+	}
+	if !slices.Equal(l.redelivered, []int64{5}) {
+		t.Errorf("redelivered IDs = %v, want [5]", l.redelivered)
+	}
+}
+
+func TestRecoverer_ProcessesWithRecoverFunc(t *testing.T) {
+	now := time.Now().UTC()
+	l := &deliveryLog{pages: [][]map[string]any{
+		{hookDelivery(7, "missed", 500, now)},
+	}}
+	dedup := NewMemoryDedup(0)
+
+	var got []*Delivery
+	rec := NewRecoverer(newRecoverClient(t, l), dedup, WithRecoverFunc(func(ctx context.Context, d *Delivery) error {
+		got = append(got, d)
+		return nil
+	}))
+	if _, err := rec.Recover(context.Background(), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("processed %d deliveries, want 1", len(got))
+	}
+	if got[0].ID != "missed" || got[0].Event != "push" || got[0].HookID != "99" || string(got[0].Payload) != `{"ref":"main"}` {
+		t.Errorf("delivery = %+v", got[0])
+	}
+	if len(l.redelivered) != 0 {
+		t.Errorf("redelivered = %v, want none", l.redelivered)
+	}
+	if seen, _ := dedup.Seen(context.Background(), "missed"); !seen {
+		t.Error("processed delivery should be recorded in the dedup store")
+	}
+}
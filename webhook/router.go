@@ -0,0 +1,80 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Router dispatches deliveries to functions registered by event name or by
+// "event.action" (e.g. "issues" or "issues.opened"). Every matching
+// function runs, event-wide ones first, in registration order; their
+// errors are joined. Use Dispatch as a Handler's DeliveryFunc:
+//
+//	router := webhook.NewRouter()
+//	router.On("pull_request.opened", onPullRequestOpened)
+//	mux.Handle("/webhook", webhook.NewHandler(router.Dispatch))
+type Router struct {
+	mu       sync.RWMutex
+	routes   map[string][]DeliveryFunc
+	fallback DeliveryFunc
+}
+
+// NewRouter creates an empty Router.
+func NewRouter() *Router {
+	return &Router{routes: make(map[string][]DeliveryFunc)}
+}
+
+// On registers fn for an event name or "event.action" pair.
+func (r *Router) On(event string, fn DeliveryFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[event] = append(r.routes[event], fn)
+}
+
+// Fallback sets the function called for deliveries no route matches.
+// Unmatched deliveries are otherwise acknowledged and dropped.
+func (r *Router) Fallback(fn DeliveryFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = fn
+}
+
+// Dispatch runs the functions registered for d.
+func (r *Router) Dispatch(ctx context.Context, d *Delivery) error {
+	r.mu.RLock()
+	fns := append([]DeliveryFunc(nil), r.routes[d.Event]...)
+	if action := d.Action(); action != "" {
+		fns = append(fns, r.routes[d.Event+"."+action]...)
+	}
+	fallback := r.fallback
+	r.mu.RUnlock()
+
+	if len(fns) == 0 {
+		if fallback != nil {
+			return fallback(ctx, d)
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ctx, d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Action returns the payload's "action" field, or "" if it has none.
+func (d *Delivery) Action() string {
+	var payload struct {
+		Action string `json:"action"`
+	}
+	_ = json.Unmarshal(d.Payload, &payload)
+	return payload.Action
+}
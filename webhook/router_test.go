@@ -0,0 +1,51 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRouter_Dispatch(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) DeliveryFunc {
+		return func(ctx context.Context, d *Delivery) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	r := NewRouter()
+	r.On("issues.opened", record("opened", nil))
+	r.On("issues", record("issues", errors.New("boom")))
+	r.On("push", record("push", nil))
+	r.Fallback(record("fallback", nil))
+
+	tests := []struct {
+		name    string
+		d       *Delivery
+		want    []string
+		wantErr bool
+	}{
+		{"event and action", &Delivery{Event: "issues", Payload: []byte(`{"action":"opened"}`)}, []string{"issues", "opened"}, true},
+		{"event only", &Delivery{Event: "issues", Payload: []byte(`{"action":"closed"}`)}, []string{"issues"}, true},
+		{"no action", &Delivery{Event: "push", Payload: []byte(`{"ref":"main"}`)}, []string{"push"}, false},
+		{"unmatched", &Delivery{Event: "star", Payload: []byte(`{}`)}, []string{"fallback"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			err := r.Dispatch(context.Background(), tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Dispatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(calls, tt.want) {
+				t.Errorf("calls = %v, want %v", calls, tt.want)
+			}
+		})
+	}
+}
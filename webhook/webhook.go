@@ -0,0 +1,237 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+// Package webhook receives GitHub webhook deliveries. It verifies their
+// signatures against the app's webhook secret and hands verified
+// deliveries to application code, or to an adapter such as a queue
+// publisher.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+// GitHub webhook request headers.
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderDelivery  = "X-GitHub-Delivery"
+	HeaderHookID    = "X-GitHub-Hook-ID"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+// MaxPayloadSize is the largest payload GitHub sends (25 MB).
+const MaxPayloadSize = 25 << 20
+
+var (
+	// ErrInvalidSignature is returned when a delivery's signature is
+	// missing or does not match the webhook secret.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+	// ErrMissingSecret is returned when no webhook secret is configured.
+	ErrMissingSecret = errors.New("webhook: webhook secret is not set")
+)
+
+// Delivery is a single webhook delivery from GitHub.
+type Delivery struct {
+	// ID is the delivery GUID from X-GitHub-Delivery.
+	ID string `json:"id"`
+	// Event is the event name from X-GitHub-Event, e.g. "push".
+	Event string `json:"event"`
+	// HookID is the webhook's ID from X-GitHub-Hook-ID, if present.
+	HookID string `json:"hook_id,omitempty"`
+	// Payload is the raw JSON body.
+	Payload []byte `json:"payload"`
+}
+
+// DeliveryFunc processes a verified delivery.
+type DeliveryFunc func(ctx context.Context, d *Delivery) error
+
+// SecretFunc returns the current webhook secret.
+type SecretFunc func() string
+
+// EnvSecret reads the webhook secret from GITHUB_WEBHOOK_SECRET on every
+// call, so secrets loaded or rotated by a ghappsetup Runtime take effect
+// without restarting.
+func EnvSecret() SecretFunc {
+	return func() string {
+		return os.Getenv(configstore.EnvGitHubWebhookSecret)
+	}
+}
+
+// VerifySignature reports whether signature, an X-Hub-Signature-256 value
+// ("sha256=<hex>"), is the HMAC-SHA256 of payload under secret.
+func VerifySignature(payload []byte, signature, secret string) bool {
+	if signature == "" || secret == "" {
+		return false
+	}
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, sign(payload, secret))
+}
+
+// Sign returns the X-Hub-Signature-256 value for payload under secret, as
+// GitHub computes it.
+func Sign(payload []byte, secret string) string {
+	return "sha256=" + hex.EncodeToString(sign(payload, secret))
+}
+
+func sign(payload []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// ParseRequest reads and verifies a webhook request. It returns
+// ErrInvalidSignature if the signature does not match secret.
+func ParseRequest(r *http.Request, secret string) (*Delivery, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to read body: %w", err)
+	}
+	if len(body) > MaxPayloadSize {
+		return nil, fmt.Errorf("webhook: payload exceeds %d bytes", MaxPayloadSize)
+	}
+	if !VerifySignature(body, r.Header.Get(HeaderSignature), secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	d := &Delivery{
+		ID:      r.Header.Get(HeaderDelivery),
+		Event:   r.Header.Get(HeaderEvent),
+		HookID:  r.Header.Get(HeaderHookID),
+		Payload: body,
+	}
+	if d.Event == "" {
+		return nil, fmt.Errorf("webhook: missing %s header", HeaderEvent)
+	}
+	return d, nil
+}
+
+// Handler is an http.Handler that verifies webhook deliveries and passes
+// them to a DeliveryFunc. It responds 200 once the function succeeds, 401
+// for bad signatures, and 500 if the function fails so GitHub records the
+// delivery as failed.
+type Handler struct {
+	fn     DeliveryFunc
+	secret SecretFunc
+	dedup  DedupStore
+}
+
+// HandlerOption is a functional option for configuring Handler.
+type HandlerOption func(*Handler)
+
+// WithSecret sets a fixed webhook secret.
+func WithSecret(secret string) HandlerOption {
+	return func(h *Handler) {
+		h.secret = func() string { return secret }
+	}
+}
+
+// WithSecretFunc sets where the webhook secret comes from. The default is
+// EnvSecret.
+func WithSecretFunc(fn SecretFunc) HandlerOption {
+	return func(h *Handler) {
+		h.secret = fn
+	}
+}
+
+// WithDedup skips deliveries already recorded in store, acknowledging
+// them without calling the DeliveryFunc, and records each delivery once it
+// has been processed. Concurrent duplicates may still both be processed.
+func WithDedup(store DedupStore) HandlerOption {
+	return func(h *Handler) {
+		h.dedup = store
+	}
+}
+
+// NewHandler creates a Handler that calls fn for each verified delivery.
+func NewHandler(fn DeliveryFunc, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		fn:     fn,
+		secret: EnvSecret(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := clog.FromContext(ctx)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	d, err := ParseRequest(r, h.secret())
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		log.Warnf("[webhook] signature validation failed for delivery %s", r.Header.Get(HeaderDelivery))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrMissingSecret):
+		log.Errorf("[webhook] rejecting delivery: %v", err)
+		http.Error(w, "webhook secret not configured", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.dedup != nil && d.ID != "" {
+		seen, err := h.dedup.Seen(ctx, d.ID)
+		if err != nil {
+			log.Warnf("[webhook] dedup lookup failed for delivery %s: %v", d.ID, err)
+		}
+		if seen {
+			log.Infof("[webhook] skipping duplicate %s delivery %s", d.Event, d.ID)
+			writeOK(w)
+			return
+		}
+	}
+
+	if err := h.fn(ctx, d); err != nil {
+		log.Errorf("[webhook] failed to process %s delivery %s: %v", d.Event, d.ID, err)
+		http.Error(w, "failed to process delivery", http.StatusInternalServerError)
+		return
+	}
+
+	if h.dedup != nil && d.ID != "" {
+		if err := h.dedup.Mark(ctx, d.ID); err != nil {
+			log.Warnf("[webhook] failed to record delivery %s: %v", d.ID, err)
+		}
+	}
+	writeOK(w)
+}
+
+// writeOK acknowledges a delivery.
+func writeOK(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`))
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 CruxStack
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cruxstack/github-app-setup-go/configstore"
+)
+
+const testSecret = "s3cret"
+
+// newDeliveryRequest returns a signed webhook request for payload.
+func newDeliveryRequest(payload, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set(HeaderEvent, "push")
+	req.Header.Set(HeaderDelivery, "d-1")
+	req.Header.Set(HeaderHookID, "99")
+	req.Header.Set(HeaderSignature, Sign([]byte(payload), secret))
+	return req
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"zen":"Keep it logically awesome."}`)
+	sig := Sign(payload, testSecret)
+
+	tests := []struct {
+		name      string
+		signature string
+		secret    string
+		want      bool
+	}{
+		{"valid", sig, testSecret, true},
+		{"wrong secret", sig, "other", false},
+		{"missing prefix", strings.TrimPrefix(sig, "sha256="), testSecret, false},
+		{"not hex", "sha256=zz", testSecret, false},
+		{"empty signature", "", testSecret, false},
+		{"empty secret", sig, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(payload, tt.signature, tt.secret); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	d, err := ParseRequest(newDeliveryRequest(`{"ref":"main"}`, testSecret), testSecret)
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if d.ID != "d-1" || d.Event != "push" || d.HookID != "99" || string(d.Payload) != `{"ref":"main"}` {
+		t.Errorf("ParseRequest() = %+v", d)
+	}
+
+	if _, err := ParseRequest(newDeliveryRequest(`{}`, "other"), testSecret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseRequest() error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := ParseRequest(newDeliveryRequest(`{}`, testSecret), ""); !errors.Is(err, ErrMissingSecret) {
+		t.Errorf("ParseRequest() error = %v, want ErrMissingSecret", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Setenv(configstore.EnvGitHubWebhookSecret, testSecret)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		fnErr  error
+		want   int
+		called bool
+	}{
+		{"verified", newDeliveryRequest(`{}`, testSecret), nil, http.StatusOK, true},
+		{"bad signature", newDeliveryRequest(`{}`, "other"), nil, http.StatusUnauthorized, false},
+		{"wrong method", httptest.NewRequest(http.MethodGet, "/webhook", nil), nil, http.StatusMethodNotAllowed, false},
+		{"handler fails", newDeliveryRequest(`{}`, testSecret), errors.New("boom"), http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := NewHandler(func(ctx context.Context, d *Delivery) error {
+				called = true
+				return tt.fnErr
+			})
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if called != tt.called {
+				t.Errorf("DeliveryFunc called = %v, want %v", called, tt.called)
+			}
+		})
+	}
+}
+
+func TestHandler_MissingSecret(t *testing.T) {
+	h := NewHandler(func(ctx context.Context, d *Delivery) error { return nil }, WithSecret(""))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDeliveryRequest(`{}`, testSecret))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func BenchmarkVerifySignature(b *testing.B) {
+	for _, size := range []int{1 << 10, 64 << 10, 1 << 20} {
+		payload := []byte(strings.Repeat("x", size))
+		signature := Sign(payload, testSecret)
+		b.Run(fmt.Sprintf("%dKiB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for b.Loop() {
+				if !VerifySignature(payload, signature, testSecret) {
+					b.Fatal("signature rejected")
+				}
+			}
+		})
+	}
+}